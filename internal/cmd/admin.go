@@ -0,0 +1,400 @@
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	admin "google.golang.org/api/admin/directory/v1"
+
+	"github.com/steipete/gogcli/internal/errfmt"
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+var newAdminDirectoryService = googleapi.NewAdminDirectory
+
+const (
+	adminCustomerMe         = "my_customer"
+	adminGeneratedPassChars = "abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789!@#%^*-_=+"
+	adminGeneratedPassLen   = 20
+)
+
+type AdminCmd struct {
+	Users    AdminUsersCmd    `cmd:"" name:"users" help:"Directory users"`
+	OrgUnits AdminOrgUnitsCmd `cmd:"" name:"orgunits" aliases:"ou" help:"Organizational units"`
+}
+
+type AdminUsersCmd struct {
+	List    AdminUsersListCmd    `cmd:"" name:"list" help:"List users"`
+	Get     AdminUsersGetCmd     `cmd:"" name:"get" help:"Get a user"`
+	Create  AdminUsersCreateCmd  `cmd:"" name:"create" help:"Create a user"`
+	Suspend AdminUsersSuspendCmd `cmd:"" name:"suspend" help:"Suspend a user"`
+	Restore AdminUsersRestoreCmd `cmd:"" name:"restore" help:"Restore (unsuspend) a user"`
+}
+
+type AdminUsersListCmd struct {
+	Domain  string `name:"domain" help:"Only list users in this domain (default: all domains of the customer)"`
+	Query   string `name:"query" short:"q" help:"Directory search query (e.g. 'orgUnitPath=/Sales isSuspended=false')"`
+	OrgUnit string `name:"org-unit" help:"Only list users in this org unit path (e.g. /Sales)"`
+	Max     int64  `name:"max" aliases:"limit" help:"Max results" default:"100"`
+	Page    string `name:"page" help:"Page token"`
+}
+
+func (c *AdminUsersListCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+
+	svc, err := newAdminDirectoryService(ctx, account)
+	if err != nil {
+		return wrapAdminError(err)
+	}
+
+	query := strings.TrimSpace(c.Query)
+	if orgUnit := strings.TrimSpace(c.OrgUnit); orgUnit != "" {
+		query = strings.TrimSpace(query + " orgUnitPath='" + normalizeOrgUnitPath(orgUnit) + "'")
+	}
+
+	call := svc.Users.List().MaxResults(c.Max).PageToken(c.Page).OrderBy("email").Context(ctx)
+	if domain := strings.TrimSpace(c.Domain); domain != "" {
+		call = call.Domain(domain)
+	} else {
+		call = call.Customer(adminCustomerMe)
+	}
+	if query != "" {
+		call = call.Query(query)
+	}
+
+	resp, err := call.Do()
+	if err != nil {
+		return wrapAdminError(err)
+	}
+
+	if outfmt.IsJSON(ctx) {
+		items := make([]adminUserItem, 0, len(resp.Users))
+		for _, usr := range resp.Users {
+			if usr == nil {
+				continue
+			}
+			items = append(items, newAdminUserItem(usr))
+		}
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"users":         items,
+			"nextPageToken": resp.NextPageToken,
+		})
+	}
+
+	if len(resp.Users) == 0 {
+		u.Err().Println("No users")
+		return nil
+	}
+
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "EMAIL\tNAME\tORG_UNIT\tSTATUS\tLAST_LOGIN")
+	for _, usr := range resp.Users {
+		if usr == nil {
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			sanitizeTab(usr.PrimaryEmail),
+			sanitizeTab(adminUserName(usr)),
+			sanitizeTab(usr.OrgUnitPath),
+			adminUserStatus(usr),
+			adminLastLogin(usr.LastLoginTime),
+		)
+	}
+	printNextPageHint(u, resp.NextPageToken)
+	return nil
+}
+
+type AdminUsersGetCmd struct {
+	UserKey string `arg:"" name:"user" help:"User email, alias, or ID"`
+}
+
+func (c *AdminUsersGetCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	userKey := strings.TrimSpace(c.UserKey)
+	if userKey == "" {
+		return usage("empty user")
+	}
+
+	svc, err := newAdminDirectoryService(ctx, account)
+	if err != nil {
+		return wrapAdminError(err)
+	}
+
+	usr, err := svc.Users.Get(userKey).Context(ctx).Do()
+	if err != nil {
+		return wrapAdminError(err)
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{"user": usr})
+	}
+	printAdminUser(u, usr)
+	return nil
+}
+
+type AdminUsersCreateCmd struct {
+	Email                  string `name:"email" help:"Primary email (required)"`
+	GivenName              string `name:"given-name" aliases:"first-name" help:"Given name (required)"`
+	FamilyName             string `name:"family-name" aliases:"last-name" help:"Family name (required)"`
+	Password               string `name:"password" help:"Initial password (default: generate a random one)"`
+	OrgUnit                string `name:"org-unit" help:"Org unit path (default: /)"`
+	RecoveryEmail          string `name:"recovery-email" help:"Recovery email address"`
+	NoChangePasswordAtNext bool   `name:"no-change-password" help:"Do not require a password change at next login"`
+}
+
+func (c *AdminUsersCreateCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+
+	email := strings.TrimSpace(c.Email)
+	givenName := strings.TrimSpace(c.GivenName)
+	familyName := strings.TrimSpace(c.FamilyName)
+	if email == "" {
+		return usage("required: --email")
+	}
+	if givenName == "" || familyName == "" {
+		return usage("required: --given-name and --family-name")
+	}
+
+	password := c.Password
+	generated := false
+	if password == "" {
+		password, err = generateAdminPassword()
+		if err != nil {
+			return err
+		}
+		generated = true
+	}
+
+	svc, err := newAdminDirectoryService(ctx, account)
+	if err != nil {
+		return wrapAdminError(err)
+	}
+
+	usr := &admin.User{
+		PrimaryEmail:              email,
+		Name:                      &admin.UserName{GivenName: givenName, FamilyName: familyName},
+		Password:                  password,
+		ChangePasswordAtNextLogin: !c.NoChangePasswordAtNext,
+		RecoveryEmail:             strings.TrimSpace(c.RecoveryEmail),
+	}
+	if orgUnit := strings.TrimSpace(c.OrgUnit); orgUnit != "" {
+		usr.OrgUnitPath = normalizeOrgUnitPath(orgUnit)
+	}
+
+	created, err := svc.Users.Insert(usr).Context(ctx).Do()
+	if err != nil {
+		return wrapAdminError(err)
+	}
+
+	if outfmt.IsJSON(ctx) {
+		out := map[string]any{"user": newAdminUserItem(created)}
+		if generated {
+			out["password"] = password
+		}
+		return outfmt.WriteJSON(os.Stdout, out)
+	}
+
+	u.Out().Printf("id\t%s", created.Id)
+	u.Out().Printf("email\t%s", created.PrimaryEmail)
+	u.Out().Printf("org_unit\t%s", created.OrgUnitPath)
+	if generated {
+		u.Out().Printf("password\t%s", password)
+		u.Err().Println("Generated password shown once; share it securely.")
+	}
+	return nil
+}
+
+type AdminUsersSuspendCmd struct {
+	UserKey string `arg:"" name:"user" help:"User email, alias, or ID"`
+}
+
+func (c *AdminUsersSuspendCmd) Run(ctx context.Context, flags *RootFlags) error {
+	return setAdminUserSuspended(ctx, flags, c.UserKey, true)
+}
+
+type AdminUsersRestoreCmd struct {
+	UserKey string `arg:"" name:"user" help:"User email, alias, or ID"`
+}
+
+func (c *AdminUsersRestoreCmd) Run(ctx context.Context, flags *RootFlags) error {
+	return setAdminUserSuspended(ctx, flags, c.UserKey, false)
+}
+
+func setAdminUserSuspended(ctx context.Context, flags *RootFlags, rawUserKey string, suspended bool) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	userKey := strings.TrimSpace(rawUserKey)
+	if userKey == "" {
+		return usage("empty user")
+	}
+
+	if suspended {
+		if confirmErr := confirmDestructive(ctx, flags, fmt.Sprintf("suspend user %s", userKey)); confirmErr != nil {
+			return confirmErr
+		}
+	}
+
+	svc, err := newAdminDirectoryService(ctx, account)
+	if err != nil {
+		return wrapAdminError(err)
+	}
+
+	// Suspended is a bool; ForceSendFields is required to send false.
+	patch := &admin.User{Suspended: suspended, ForceSendFields: []string{"Suspended"}}
+	updated, err := svc.Users.Update(userKey, patch).Context(ctx).Do()
+	if err != nil {
+		return wrapAdminError(err)
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{"user": newAdminUserItem(updated)})
+	}
+	u.Out().Printf("email\t%s", updated.PrimaryEmail)
+	u.Out().Printf("suspended\t%t", updated.Suspended)
+	return nil
+}
+
+type adminUserItem struct {
+	ID          string `json:"id"`
+	Email       string `json:"email"`
+	Name        string `json:"name,omitempty"`
+	OrgUnitPath string `json:"orgUnitPath,omitempty"`
+	Suspended   bool   `json:"suspended"`
+	Archived    bool   `json:"archived,omitempty"`
+	IsAdmin     bool   `json:"isAdmin,omitempty"`
+	LastLogin   string `json:"lastLoginTime,omitempty"`
+	Created     string `json:"creationTime,omitempty"`
+}
+
+func newAdminUserItem(usr *admin.User) adminUserItem {
+	if usr == nil {
+		return adminUserItem{}
+	}
+	return adminUserItem{
+		ID:          usr.Id,
+		Email:       usr.PrimaryEmail,
+		Name:        adminUserName(usr),
+		OrgUnitPath: usr.OrgUnitPath,
+		Suspended:   usr.Suspended,
+		Archived:    usr.Archived,
+		IsAdmin:     usr.IsAdmin,
+		LastLogin:   usr.LastLoginTime,
+		Created:     usr.CreationTime,
+	}
+}
+
+func printAdminUser(u *ui.UI, usr *admin.User) {
+	u.Out().Printf("id\t%s", usr.Id)
+	u.Out().Printf("email\t%s", usr.PrimaryEmail)
+	u.Out().Printf("name\t%s", adminUserName(usr))
+	u.Out().Printf("org_unit\t%s", usr.OrgUnitPath)
+	u.Out().Printf("status\t%s", adminUserStatus(usr))
+	u.Out().Printf("admin\t%t", usr.IsAdmin)
+	if len(usr.Aliases) > 0 {
+		u.Out().Printf("aliases\t%s", strings.Join(usr.Aliases, ","))
+	}
+	u.Out().Printf("last_login\t%s", adminLastLogin(usr.LastLoginTime))
+	if usr.CreationTime != "" {
+		u.Out().Printf("created\t%s", usr.CreationTime)
+	}
+}
+
+func adminUserName(usr *admin.User) string {
+	if usr == nil || usr.Name == nil {
+		return ""
+	}
+	if usr.Name.FullName != "" {
+		return usr.Name.FullName
+	}
+	return strings.TrimSpace(usr.Name.GivenName + " " + usr.Name.FamilyName)
+}
+
+func adminUserStatus(usr *admin.User) string {
+	switch {
+	case usr.Archived:
+		return "archived"
+	case usr.Suspended:
+		return "suspended"
+	default:
+		return "active"
+	}
+}
+
+// adminLastLogin hides the epoch placeholder Google returns for users that never logged in.
+func adminLastLogin(v string) string {
+	if v == "" || strings.HasPrefix(v, "1970-01-01") {
+		return "never"
+	}
+	return v
+}
+
+func normalizeOrgUnitPath(path string) string {
+	path = strings.TrimSpace(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	if len(path) > 1 {
+		path = strings.TrimRight(path, "/")
+	}
+	return path
+}
+
+func generateAdminPassword() (string, error) {
+	var b strings.Builder
+	max := big.NewInt(int64(len(adminGeneratedPassChars)))
+	for i := 0; i < adminGeneratedPassLen; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("generate password: %w", err)
+		}
+		b.WriteByte(adminGeneratedPassChars[n.Int64()])
+	}
+	return b.String(), nil
+}
+
+// wrapAdminError provides helpful error messages for common Admin SDK issues.
+func wrapAdminError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var authErr *googleapi.AuthRequiredError
+	if errors.As(err, &authErr) {
+		return err
+	}
+	errStr := err.Error()
+	if strings.Contains(errStr, "accessNotConfigured") ||
+		strings.Contains(errStr, "Admin SDK API has not been used") {
+		return errfmt.NewUserFacingError("Admin SDK API is not enabled; enable it at: https://console.developers.google.com/apis/api/admin.googleapis.com/overview", err)
+	}
+	if strings.Contains(errStr, "insufficientPermissions") ||
+		strings.Contains(errStr, "insufficient authentication scopes") {
+		return errfmt.NewUserFacingError("Insufficient permissions for the Admin SDK; re-authenticate with: gog auth add <account> --services admin", err)
+	}
+	if strings.Contains(errStr, "Not Authorized to access this resource") {
+		return errfmt.NewUserFacingError("Account is not a Workspace administrator (or lacks the required admin role)", err)
+	}
+	return err
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	admin "google.golang.org/api/admin/directory/v1"
+
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+type AdminOrgUnitsCmd struct {
+	List AdminOrgUnitsListCmd `cmd:"" name:"list" help:"List org units"`
+	Move AdminOrgUnitsMoveCmd `cmd:"" name:"move" help:"Move users into an org unit"`
+}
+
+type AdminOrgUnitsListCmd struct {
+	Parent string `name:"parent" help:"Parent org unit path" default:"/"`
+	Type   string `name:"type" help:"all|children|all_including_parent" enum:"all,children,all_including_parent" default:"all"`
+}
+
+func (c *AdminOrgUnitsListCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+
+	svc, err := newAdminDirectoryService(ctx, account)
+	if err != nil {
+		return wrapAdminError(err)
+	}
+
+	call := svc.Orgunits.List(adminCustomerMe).Type(c.Type).Context(ctx)
+	if parent := normalizeOrgUnitPath(c.Parent); parent != "/" {
+		call = call.OrgUnitPath(parent)
+	}
+	resp, err := call.Do()
+	if err != nil {
+		return wrapAdminError(err)
+	}
+
+	units := resp.OrganizationUnits
+	sort.SliceStable(units, func(i, j int) bool {
+		return units[i].OrgUnitPath < units[j].OrgUnitPath
+	})
+
+	if outfmt.IsJSON(ctx) {
+		type item struct {
+			ID          string `json:"id"`
+			Path        string `json:"path"`
+			Name        string `json:"name"`
+			ParentPath  string `json:"parentPath,omitempty"`
+			Description string `json:"description,omitempty"`
+		}
+		items := make([]item, 0, len(units))
+		for _, ou := range units {
+			if ou == nil {
+				continue
+			}
+			items = append(items, item{
+				ID:          ou.OrgUnitId,
+				Path:        ou.OrgUnitPath,
+				Name:        ou.Name,
+				ParentPath:  ou.ParentOrgUnitPath,
+				Description: ou.Description,
+			})
+		}
+		return outfmt.WriteJSON(os.Stdout, map[string]any{"orgUnits": items})
+	}
+
+	if len(units) == 0 {
+		u.Err().Println("No org units")
+		return nil
+	}
+
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "PATH\tNAME\tID\tDESCRIPTION")
+	for _, ou := range units {
+		if ou == nil {
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+			sanitizeTab(ou.OrgUnitPath),
+			sanitizeTab(ou.Name),
+			ou.OrgUnitId,
+			sanitizeTab(ou.Description),
+		)
+	}
+	return nil
+}
+
+type AdminOrgUnitsMoveCmd struct {
+	Users []string `arg:"" name:"user" help:"User emails or IDs to move"`
+	To    string   `name:"to" help:"Destination org unit path (required)"`
+}
+
+func (c *AdminOrgUnitsMoveCmd) Run(ctx context.Context, flags *RootFlags) error {
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(c.To) == "" {
+		return usage("required: --to")
+	}
+	dest := normalizeOrgUnitPath(c.To)
+
+	users := make([]string, 0, len(c.Users))
+	for _, usr := range c.Users {
+		if usr = strings.TrimSpace(usr); usr != "" {
+			users = append(users, usr)
+		}
+	}
+	if len(users) == 0 {
+		return usage("no users specified")
+	}
+
+	if confirmErr := confirmDestructive(ctx, flags, fmt.Sprintf("move %d user(s) to org unit %s", len(users), dest)); confirmErr != nil {
+		return confirmErr
+	}
+
+	svc, err := newAdminDirectoryService(ctx, account)
+	if err != nil {
+		return wrapAdminError(err)
+	}
+
+	type result struct {
+		User        string `json:"user"`
+		OrgUnitPath string `json:"orgUnitPath"`
+	}
+	results := make([]result, 0, len(users))
+	for _, userKey := range users {
+		updated, updateErr := svc.Users.Update(userKey, &admin.User{OrgUnitPath: dest}).Context(ctx).Do()
+		if updateErr != nil {
+			return fmt.Errorf("move %s: %w", userKey, wrapAdminError(updateErr))
+		}
+		results = append(results, result{User: updated.PrimaryEmail, OrgUnitPath: updated.OrgUnitPath})
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{"moved": results})
+	}
+
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "USER\tORG_UNIT")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%s\n", r.User, r.OrgUnitPath)
+	}
+	return nil
+}
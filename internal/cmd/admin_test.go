@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	admin "google.golang.org/api/admin/directory/v1"
+	"google.golang.org/api/option"
+)
+
+func stubAdminDirectory(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+
+	orig := newAdminDirectoryService
+	t.Cleanup(func() { newAdminDirectoryService = orig })
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	svc, err := admin.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newAdminDirectoryService = func(context.Context, string) (*admin.Service, error) { return svc, nil }
+}
+
+func TestExecute_AdminUsersList_JSON(t *testing.T) {
+	stubAdminDirectory(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/admin/directory/v1/users") {
+			http.NotFound(w, r)
+			return
+		}
+		q := r.URL.Query()
+		if q.Get("customer") != "my_customer" {
+			t.Fatalf("customer=%q", q.Get("customer"))
+		}
+		if q.Get("query") != "orgUnitPath='/Sales'" {
+			t.Fatalf("query=%q", q.Get("query"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"users": []map[string]any{
+				{
+					"id":            "u1",
+					"primaryEmail":  "ada@example.com",
+					"name":          map[string]any{"fullName": "Ada Lovelace"},
+					"orgUnitPath":   "/Sales",
+					"suspended":     true,
+					"lastLoginTime": "1970-01-01T00:00:00.000Z",
+				},
+			},
+			"nextPageToken": "npt",
+		})
+	})
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "admin@example.com", "admin", "users", "list", "--org-unit", "Sales"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+
+	var parsed struct {
+		Users []struct {
+			Email     string `json:"email"`
+			Name      string `json:"name"`
+			Suspended bool   `json:"suspended"`
+		} `json:"users"`
+		NextPageToken string `json:"nextPageToken"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json parse: %v\nout=%q", err, out)
+	}
+	if len(parsed.Users) != 1 || parsed.Users[0].Email != "ada@example.com" || !parsed.Users[0].Suspended || parsed.Users[0].Name != "Ada Lovelace" {
+		t.Fatalf("unexpected users: %#v", parsed.Users)
+	}
+	if parsed.NextPageToken != "npt" {
+		t.Fatalf("nextPageToken=%q", parsed.NextPageToken)
+	}
+}
+
+func TestExecute_AdminUsersRestore_SendsSuspendedFalse(t *testing.T) {
+	stubAdminDirectory(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || !strings.HasSuffix(r.URL.Path, "/users/ada@example.com") {
+			http.NotFound(w, r)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), `"suspended":false`) {
+			t.Fatalf("expected suspended=false in body: %s", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"primaryEmail": "ada@example.com", "suspended": false})
+	})
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--account", "admin@example.com", "admin", "users", "restore", "ada@example.com"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if !strings.Contains(out, "suspended\tfalse") {
+		t.Fatalf("unexpected output: %q", out)
+	}
+}
+
+func TestExecute_AdminUsersSuspend_RequiresForce(t *testing.T) {
+	stubAdminDirectory(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+	})
+
+	_ = captureStderr(t, func() {
+		err := Execute([]string{"--no-input", "--account", "admin@example.com", "admin", "users", "suspend", "ada@example.com"})
+		if err == nil || ExitCode(err) != 2 {
+			t.Fatalf("expected usage error, got %v", err)
+		}
+	})
+}
+
+func TestExecute_AdminOrgUnitsMove(t *testing.T) {
+	var moved []string
+	stubAdminDirectory(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || !strings.Contains(r.URL.Path, "/users/") {
+			http.NotFound(w, r)
+			return
+		}
+		var usr admin.User
+		if err := json.NewDecoder(r.Body).Decode(&usr); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if usr.OrgUnitPath != "/Engineering/Platform" {
+			t.Fatalf("orgUnitPath=%q", usr.OrgUnitPath)
+		}
+		email := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		moved = append(moved, email)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"primaryEmail": email, "orgUnitPath": usr.OrgUnitPath})
+	})
+
+	_ = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--force", "--account", "admin@example.com", "admin", "orgunits", "move", "a@example.com", "b@example.com", "--to", "Engineering/Platform/"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if strings.Join(moved, ",") != "a@example.com,b@example.com" {
+		t.Fatalf("moved=%v", moved)
+	}
+}
+
+func TestNormalizeOrgUnitPath(t *testing.T) {
+	cases := map[string]string{
+		"":         "/",
+		"/":        "/",
+		"Sales":    "/Sales",
+		"/Sales/":  "/Sales",
+		" /A/B/  ": "/A/B",
+	}
+	for in, want := range cases {
+		if got := normalizeOrgUnitPath(in); got != want {
+			t.Fatalf("normalizeOrgUnitPath(%q)=%q want %q", in, got, want)
+		}
+	}
+}
+
+func TestGenerateAdminPassword(t *testing.T) {
+	p, err := generateAdminPassword()
+	if err != nil {
+		t.Fatalf("generateAdminPassword: %v", err)
+	}
+	if len(p) != adminGeneratedPassLen {
+		t.Fatalf("len=%d", len(p))
+	}
+}
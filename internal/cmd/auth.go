@@ -26,6 +26,7 @@ var (
 	checkRefreshToken    = googleauth.CheckRefreshToken
 	ensureKeychainAccess = secrets.EnsureKeychainAccess
 	fetchAuthorizedEmail = googleauth.EmailForRefreshToken
+	revokeGoogleToken    = googleauth.RevokeToken
 )
 
 func ensureKeychainAccessIfNeeded() error {
@@ -60,6 +61,7 @@ type AuthCmd struct {
 	Remove      AuthRemoveCmd         `cmd:"" name:"remove" help:"Remove a stored refresh token"`
 	Tokens      AuthTokensCmd         `cmd:"" name:"tokens" help:"Manage stored refresh tokens"`
 	Manage      AuthManageCmd         `cmd:"" name:"manage" help:"Open accounts manager in browser" aliases:"login"`
+	Minimize    AuthMinimizeCmd       `cmd:"" name:"minimize" help:"Re-consent with only the scopes recently used by the account"`
 	ServiceAcct AuthServiceAccountCmd `cmd:"" name:"service-account" help:"Configure service account (Workspace only; domain-wide delegation)"`
 	Keep        AuthKeepCmd           `cmd:"" name:"keep" help:"Configure service account for Google Keep (Workspace only)"`
 }
@@ -480,7 +482,8 @@ func (c *AuthTokensImportCmd) Run(ctx context.Context) error {
 
 type AuthAddCmd struct {
 	Email        string `arg:"" name:"email" help:"Email"`
-	Manual       bool   `name:"manual" help:"Browserless auth flow (paste redirect URL)"`
+	Manual       bool   `name:"manual" aliases:"no-browser" help:"Browserless auth flow (print auth URL, paste redirect URL)"`
+	Device       bool   `name:"device" help:"OAuth device-code flow for headless machines (requires a 'TVs and Limited Input devices' client)"`
 	ForceConsent bool   `name:"force-consent" help:"Force consent screen to obtain a refresh token"`
 	ServicesCSV  string `name:"services" help:"Services to authorize: user|all or comma-separated ${auth_services} (Keep uses service account: gog auth service-account set)" default:"user"`
 	Readonly     bool   `name:"readonly" help:"Use read-only scopes where available (still includes OIDC identity scopes)"`
@@ -507,6 +510,9 @@ func (c *AuthAddCmd) Run(ctx context.Context) error {
 	if c.Readonly && c.DriveScope == strFile {
 		return usage("cannot combine --readonly with --drive-scope=file (file is write-capable)")
 	}
+	if c.Device && c.Manual {
+		return usage("use only one of --device or --manual")
+	}
 	scopes, err := googleauth.ScopesForManageWithOptions(services, googleauth.ScopeOptions{
 		Readonly:   c.Readonly,
 		DriveScope: googleauth.DriveScopeMode(c.DriveScope),
@@ -524,6 +530,7 @@ func (c *AuthAddCmd) Run(ctx context.Context) error {
 		Services:     services,
 		Scopes:       scopes,
 		Manual:       c.Manual,
+		Device:       c.Device,
 		ForceConsent: c.ForceConsent,
 		Client:       client,
 	})
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/googleauth"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/secrets"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+const (
+	minimizeKeepFull     = "full"
+	minimizeKeepReadonly = "readonly"
+	minimizeDrop         = "drop"
+)
+
+type AuthMinimizeCmd struct {
+	Days   int  `name:"days" help:"Keep services used within this many days" default:"30"`
+	DryRun bool `name:"dry-run" help:"Show the scope plan without re-consenting"`
+	Manual bool `name:"manual" aliases:"no-browser" help:"Browserless auth flow (print auth URL, paste redirect URL)"`
+	Device bool `name:"device" help:"OAuth device-code flow for headless machines"`
+}
+
+type minimizePlanEntry struct {
+	Service   string `json:"service"`
+	Action    string `json:"action"`
+	LastRead  string `json:"lastRead,omitempty"`
+	LastWrite string `json:"lastWrite,omitempty"`
+}
+
+func (c *AuthMinimizeCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	if c.Days <= 0 {
+		return usage("--days must be > 0")
+	}
+	if c.Device && c.Manual {
+		return usage("use only one of --device or --manual")
+	}
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	client, err := resolveClientForEmail(account, flags, "")
+	if err != nil {
+		return err
+	}
+
+	store, err := openSecretsStore()
+	if err != nil {
+		return err
+	}
+	tok, err := store.GetToken(client, account)
+	if err != nil {
+		return err
+	}
+	if len(tok.Services) == 0 {
+		return fmt.Errorf("stored token for %s has no service list; re-authorize with: gog auth add %s", account, account)
+	}
+
+	recorded, err := config.ReadScopeUsage()
+	if err != nil {
+		return err
+	}
+	cutoff := time.Now().UTC().AddDate(0, 0, -c.Days).Format("2006-01-02")
+	plan := planScopeMinimize(tok.Services, recorded.Accounts[normalizeEmail(account)], cutoff)
+
+	var full, readonly []googleauth.Service
+	changed, used := false, false
+	for _, entry := range plan {
+		if entry.Action != minimizeDrop && googleauth.Service(entry.Service) != googleauth.ServicePeople {
+			used = true
+		}
+		switch entry.Action {
+		case minimizeKeepFull:
+			full = append(full, googleauth.Service(entry.Service))
+		case minimizeKeepReadonly:
+			readonly = append(readonly, googleauth.Service(entry.Service))
+			changed = true
+		default:
+			changed = true
+		}
+	}
+	if !used {
+		return usagef("no API usage recorded for %s in the last %d days; nothing to keep", account, c.Days)
+	}
+
+	scopes, err := googleauth.ScopesForManageModes(full, readonly)
+	if err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) && (c.DryRun || !changed) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"email":   account,
+			"days":    c.Days,
+			"plan":    plan,
+			"scopes":  scopes,
+			"changed": changed,
+			"applied": false,
+		})
+	}
+	if !outfmt.IsJSON(ctx) {
+		w, done := tableWriter(ctx)
+		fmt.Fprintln(w, "SERVICE\tACTION\tLAST_READ\tLAST_WRITE")
+		for _, entry := range plan {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", entry.Service, entry.Action, orEmpty(entry.LastRead, "-"), orEmpty(entry.LastWrite, "-"))
+		}
+		done()
+	}
+	if !changed {
+		u.Err().Println("Scopes are already minimal")
+		return nil
+	}
+	if c.DryRun {
+		return nil
+	}
+
+	if err := confirmDestructive(ctx, flags, fmt.Sprintf("revoke the current grant for %s and re-consent with fewer scopes", account)); err != nil {
+		return err
+	}
+	if keychainErr := ensureKeychainAccessIfNeeded(); keychainErr != nil {
+		return fmt.Errorf("keychain access: %w", keychainErr)
+	}
+
+	services := append(append([]googleauth.Service{}, full...), readonly...)
+	serviceNames := make([]string, 0, len(services))
+	for _, svc := range services {
+		serviceNames = append(serviceNames, string(svc))
+	}
+	sort.Strings(serviceNames)
+	reauthHint := fmt.Sprintf("gog auth add %s --services %s --force-consent", account, strings.Join(serviceNames, ","))
+
+	// Google merges new consent into the existing grant, so the old grant has
+	// to go first or the dropped scopes would come back.
+	if err := revokeGoogleToken(ctx, tok.RefreshToken); err != nil {
+		return fmt.Errorf("revoke current grant: %w", err)
+	}
+
+	refreshToken, err := authorizeGoogle(ctx, googleauth.AuthorizeOptions{
+		Services:     services,
+		Scopes:       scopes,
+		Manual:       c.Manual,
+		Device:       c.Device,
+		ForceConsent: true,
+		Client:       client,
+	})
+	if err != nil {
+		u.Err().Printf("The previous grant was revoked; re-authorize with: %s", reauthHint)
+		return err
+	}
+	authorizedEmail, err := fetchAuthorizedEmail(ctx, client, refreshToken, scopes, 15*time.Second)
+	if err != nil {
+		return fmt.Errorf("fetch authorized email: %w", err)
+	}
+	if normalizeEmail(authorizedEmail) != normalizeEmail(account) {
+		u.Err().Printf("The previous grant was revoked; re-authorize with: %s", reauthHint)
+		return fmt.Errorf("authorized as %s, expected %s", authorizedEmail, account)
+	}
+
+	if err := store.SetToken(client, authorizedEmail, secrets.Token{
+		Client:       client,
+		Email:        authorizedEmail,
+		Services:     serviceNames,
+		Scopes:       scopes,
+		RefreshToken: refreshToken,
+	}); err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"email":   authorizedEmail,
+			"days":    c.Days,
+			"plan":    plan,
+			"scopes":  scopes,
+			"changed": true,
+			"applied": true,
+		})
+	}
+	u.Out().Printf("email\t%s", authorizedEmail)
+	u.Out().Printf("services\t%s", strings.Join(serviceNames, ","))
+	u.Out().Printf("scopes\t%d", len(scopes))
+	return nil
+}
+
+// planScopeMinimize decides per granted service whether recent writes need
+// the full scope, reads only need the read-only one, or it can be dropped.
+// Dates compare as strings since they are YYYY-MM-DD.
+func planScopeMinimize(granted []string, recorded map[string]config.APIUsage, cutoff string) []minimizePlanEntry {
+	names := append([]string(nil), granted...)
+	sort.Strings(names)
+	plan := make([]minimizePlanEntry, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		rec := recorded[name]
+		entry := minimizePlanEntry{Service: name, LastRead: rec.LastRead, LastWrite: rec.LastWrite}
+		switch {
+		case googleauth.Service(name) == googleauth.ServicePeople:
+			// Profile scope backs identity lookups; never worth dropping.
+			entry.Action = minimizeKeepFull
+		case rec.LastWrite >= cutoff:
+			entry.Action = minimizeKeepFull
+		case rec.LastRead >= cutoff:
+			entry.Action = minimizeKeepReadonly
+		default:
+			entry.Action = minimizeDrop
+		}
+		plan = append(plan, entry)
+	}
+	return plan
+}
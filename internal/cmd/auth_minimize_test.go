@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/googleauth"
+	"github.com/steipete/gogcli/internal/secrets"
+)
+
+func seedScopeUsage(t *testing.T, usage config.ScopeUsage) {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	path, err := config.ScopeUsagePath()
+	if err != nil {
+		t.Fatalf("ScopeUsagePath: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	data, _ := json.Marshal(usage)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write usage: %v", err)
+	}
+}
+
+func TestPlanScopeMinimize(t *testing.T) {
+	plan := planScopeMinimize(
+		[]string{"gmail", "calendar", "drive", "people"},
+		map[string]config.APIUsage{
+			"gmail":    {LastRead: "2026-03-10"},
+			"calendar": {LastRead: "2026-03-10", LastWrite: "2026-03-09"},
+			"drive":    {LastWrite: "2026-01-01"},
+		},
+		"2026-03-01",
+	)
+	got := map[string]string{}
+	for _, e := range plan {
+		got[e.Service] = e.Action
+	}
+	want := map[string]string{"gmail": "readonly", "calendar": "full", "drive": "drop", "people": "full"}
+	for svc, action := range want {
+		if got[svc] != action {
+			t.Fatalf("%s: got %q want %q (plan %#v)", svc, got[svc], action, plan)
+		}
+	}
+}
+
+func TestAuthMinimizeCmd_ReconsentsWithUsedScopes(t *testing.T) {
+	origAuth := authorizeGoogle
+	origOpen := openSecretsStore
+	origKeychain := ensureKeychainAccess
+	origFetch := fetchAuthorizedEmail
+	origRevoke := revokeGoogleToken
+	t.Cleanup(func() {
+		authorizeGoogle = origAuth
+		openSecretsStore = origOpen
+		ensureKeychainAccess = origKeychain
+		fetchAuthorizedEmail = origFetch
+		revokeGoogleToken = origRevoke
+	})
+
+	today := time.Now().UTC().Format("2006-01-02")
+	seedScopeUsage(t, config.ScopeUsage{Accounts: map[string]map[string]config.APIUsage{
+		"a@b.com": {
+			"gmail":    {LastRead: today},
+			"calendar": {LastWrite: today},
+		},
+	}})
+
+	ensureKeychainAccess = func() error { return nil }
+	store := newMemSecretsStore()
+	_ = store.SetToken(config.DefaultClientName, "a@b.com", secrets.Token{
+		Email:        "a@b.com",
+		Services:     []string{"calendar", "drive", "gmail"},
+		RefreshToken: "old-rt",
+	})
+	openSecretsStore = func() (secrets.Store, error) { return store, nil }
+
+	var revoked string
+	revokeGoogleToken = func(_ context.Context, token string) error {
+		revoked = token
+		return nil
+	}
+	var gotOpts googleauth.AuthorizeOptions
+	authorizeGoogle = func(_ context.Context, opts googleauth.AuthorizeOptions) (string, error) {
+		if revoked == "" {
+			t.Fatalf("expected revoke before re-consent")
+		}
+		gotOpts = opts
+		return "new-rt", nil
+	}
+	fetchAuthorizedEmail = func(context.Context, string, string, []string, time.Duration) (string, error) {
+		return "a@b.com", nil
+	}
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--force", "--account", "a@b.com", "auth", "minimize"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+
+	if revoked != "old-rt" || !gotOpts.ForceConsent {
+		t.Fatalf("revoked=%q opts=%+v", revoked, gotOpts)
+	}
+	scopes := strings.Join(gotOpts.Scopes, " ")
+	if !strings.Contains(scopes, "gmail.readonly") || strings.Contains(scopes, "gmail.modify") || strings.Contains(scopes, "auth/drive") {
+		t.Fatalf("unexpected scopes: %v", gotOpts.Scopes)
+	}
+
+	var parsed struct {
+		Applied bool `json:"applied"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil || !parsed.Applied {
+		t.Fatalf("unexpected output %q: %v", out, err)
+	}
+	tok, err := store.GetToken(config.DefaultClientName, "a@b.com")
+	if err != nil {
+		t.Fatalf("GetToken: %v", err)
+	}
+	if tok.RefreshToken != "new-rt" || strings.Join(tok.Services, ",") != "calendar,gmail" {
+		t.Fatalf("unexpected token: %#v", tok)
+	}
+}
+
+func TestAuthMinimizeCmd_DryRunNoUsage(t *testing.T) {
+	origOpen := openSecretsStore
+	t.Cleanup(func() { openSecretsStore = origOpen })
+
+	seedScopeUsage(t, config.ScopeUsage{})
+	store := newMemSecretsStore()
+	_ = store.SetToken(config.DefaultClientName, "a@b.com", secrets.Token{
+		Email:        "a@b.com",
+		Services:     []string{"gmail"},
+		RefreshToken: "rt",
+	})
+	openSecretsStore = func() (secrets.Store, error) { return store, nil }
+
+	_ = captureStderr(t, func() {
+		err := Execute([]string{"--account", "a@b.com", "auth", "minimize", "--dry-run"})
+		if err == nil || ExitCode(err) != 2 {
+			t.Fatalf("expected usage error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,444 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/steipete/gogcli/internal/errfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// BatchCmd runs many gog invocations in one process so they share OAuth
+// token sources and HTTP connections. Commands write to os.Stdout directly,
+// so a process can only capture one command's output at a time; --parallel
+// therefore fans out to long-lived worker processes, each of which runs its
+// share of the commands in-process.
+type BatchCmd struct {
+	File        string `name:"file" short:"f" required:"" help:"File with one gog command per line ('-' for stdin; blank lines and # comments are skipped)"`
+	Parallel    int    `name:"parallel" short:"p" help:"Run commands concurrently in this many worker subprocesses (each one re-runs gog with the batch's global flags)" default:"1"`
+	StopOnError bool   `name:"stop-on-error" help:"Stop starting new commands after the first failure"`
+	Worker      bool   `name:"worker" hidden:"" help:"Internal: run commands sent as NDJSON on stdin"`
+}
+
+type batchCommand struct {
+	Index int      `json:"index"`
+	Line  int      `json:"line"`
+	Args  []string `json:"args"`
+}
+
+type batchResult struct {
+	batchCommand
+	OK         bool                   `json:"ok"`
+	ExitCode   int                    `json:"exitCode"`
+	DurationMs int64                  `json:"durationMs"`
+	Output     json.RawMessage        `json:"output,omitempty"`
+	Stdout     string                 `json:"stdout,omitempty"`
+	Stderr     string                 `json:"stderr,omitempty"`
+	Error      *errfmt.Classification `json:"error,omitempty"`
+}
+
+// batchWorker runs one command at a time and returns its result.
+type batchWorker interface {
+	Run(cmd batchCommand) (batchResult, error)
+	Close() error
+}
+
+var (
+	// executeBatchArgs runs one parsed command line; tests stub it.
+	executeBatchArgs = Execute
+	// startBatchWorker starts a worker process for --parallel.
+	startBatchWorker = startBatchWorkerProcess
+	// batchCaptureMu serializes os.Stdout/os.Stderr swapping; batchNested is
+	// set while a command runs under it.
+	batchCaptureMu sync.Mutex
+	batchNested    bool
+)
+
+func (c *BatchCmd) Run(ctx context.Context, kctx *kong.Context) error {
+	if batchNested {
+		return usage("batch cannot run batch")
+	}
+	if c.Worker {
+		return serveBatchWorker(os.Stdin, os.Stdout)
+	}
+	u := ui.FromContext(ctx)
+	if c.Parallel < 1 {
+		return usage("--parallel must be >= 1")
+	}
+
+	commands, err := readBatchFile(c.File, batchGlobalArgs(kctx))
+	if err != nil {
+		return err
+	}
+	if len(commands) == 0 {
+		u.Err().Println("No commands")
+		return nil
+	}
+
+	// Results are NDJSON regardless of --json; each line carries its index.
+	out := json.NewEncoder(os.Stdout)
+	var outMu sync.Mutex
+	var ran, failed int
+	emit := func(res batchResult) {
+		outMu.Lock()
+		defer outMu.Unlock()
+		ran++
+		if !res.OK {
+			failed++
+		}
+		_ = out.Encode(res)
+	}
+
+	workers := min(c.Parallel, len(commands))
+	if workers == 1 {
+		for _, bc := range commands {
+			if interrupted(ctx) {
+				break
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			res := runBatchCommand(bc)
+			emit(res)
+			if !res.OK && c.StopOnError {
+				break
+			}
+		}
+	} else if err := runBatchParallel(ctx, commands, workers, c.StopOnError, emit); err != nil && !interrupted(ctx) {
+		return err
+	}
+
+	skipped := len(commands) - ran
+	u.Err().Printf("batch: %d ok, %d failed, %d not run", ran-failed, failed, skipped)
+	if failed > 0 {
+		return &ExitError{Code: 1, Err: fmt.Errorf("%d of %d commands failed", failed, len(commands))}
+	}
+	return nil
+}
+
+func runBatchParallel(ctx context.Context, commands []batchCommand, n int, stopOnError bool, emit func(batchResult)) error {
+	jobs := make(chan batchCommand)
+	var stopped sync.Once
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	var errMu sync.Mutex
+	var firstErr error
+
+	for range n {
+		w, err := startBatchWorker(ctx)
+		if err != nil {
+			close(jobs)
+			wg.Wait()
+			return fmt.Errorf("start batch worker: %w", err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { _ = w.Close() }()
+			for bc := range jobs {
+				res, err := w.Run(bc)
+				if err != nil {
+					errMu.Lock()
+					if firstErr == nil {
+						firstErr = fmt.Errorf("batch worker: %w", err)
+					}
+					errMu.Unlock()
+					stopped.Do(func() { close(stop) })
+					// Drain so the dispatcher never blocks on a dead worker.
+					for range jobs { //nolint:revive // intentionally empty
+					}
+					return
+				}
+				emit(res)
+				if !res.OK && stopOnError {
+					stopped.Do(func() { close(stop) })
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for _, bc := range commands {
+		select {
+		case <-stop:
+			break dispatch
+		case <-ctx.Done():
+			break dispatch
+		case jobs <- bc:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// runBatchCommand executes one command in-process, capturing what it writes
+// to stdout and stderr.
+func runBatchCommand(bc batchCommand) batchResult {
+	batchCaptureMu.Lock()
+	defer batchCaptureMu.Unlock()
+
+	batchNested = true
+	defer func() { batchNested = false }()
+
+	start := time.Now()
+	var stdout, stderr bytes.Buffer
+	err := captureOutput(&stdout, &stderr, func() error {
+		return executeBatchArgs(bc.Args)
+	})
+
+	res := batchResult{batchCommand: bc, OK: err == nil, DurationMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		info := classifyError(err)
+		res.ExitCode = info.ExitCode
+		res.Error = &info
+	}
+	if out := bytes.TrimSpace(stdout.Bytes()); len(out) > 0 {
+		if json.Valid(out) {
+			res.Output = json.RawMessage(out)
+		} else {
+			res.Stdout = stdout.String()
+		}
+	}
+	res.Stderr = stderr.String()
+	return res
+}
+
+// captureOutput points os.Stdout and os.Stderr at pipes while fn runs.
+func captureOutput(stdout, stderr io.Writer, fn func() error) (err error) {
+	origOut, origErr := os.Stdout, os.Stderr
+	outR, outW, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	errR, errW, err := os.Pipe()
+	if err != nil {
+		_ = outR.Close()
+		_ = outW.Close()
+		return err
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { defer wg.Done(); _, _ = io.Copy(stdout, outR) }()
+	go func() { defer wg.Done(); _, _ = io.Copy(stderr, errR) }()
+
+	os.Stdout, os.Stderr = outW, errW
+	defer func() {
+		os.Stdout, os.Stderr = origOut, origErr
+		_ = outW.Close()
+		_ = errW.Close()
+		wg.Wait()
+		_ = outR.Close()
+		_ = errR.Close()
+		if r := recover(); r != nil {
+			err = fmt.Errorf("command panicked: %v", r)
+		}
+	}()
+	return fn()
+}
+
+// serveBatchWorker reads batchCommand lines from r and answers each with a
+// batchResult line on w.
+func serveBatchWorker(r io.Reader, w io.Writer) error {
+	dec := json.NewDecoder(r)
+	enc := json.NewEncoder(w)
+	for {
+		var bc batchCommand
+		if err := dec.Decode(&bc); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("read batch command: %w", err)
+		}
+		if err := enc.Encode(runBatchCommand(bc)); err != nil {
+			return err
+		}
+	}
+}
+
+type batchWorkerProcess struct {
+	cmd *exec.Cmd
+	in  io.WriteCloser
+	out *json.Decoder
+}
+
+func startBatchWorkerProcess(ctx context.Context) (batchWorker, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.CommandContext(ctx, exe, "batch", "--worker", "--file", "-") //nolint:gosec // re-executing ourselves
+	cmd.Stderr = os.Stderr
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return &batchWorkerProcess{cmd: cmd, in: in, out: json.NewDecoder(out)}, nil
+}
+
+func (p *batchWorkerProcess) Run(bc batchCommand) (batchResult, error) {
+	var res batchResult
+	b, err := json.Marshal(bc)
+	if err != nil {
+		return res, err
+	}
+	if _, err := p.in.Write(append(b, '\n')); err != nil {
+		return res, err
+	}
+	if err := p.out.Decode(&res); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+func (p *batchWorkerProcess) Close() error {
+	_ = p.in.Close()
+	return p.cmd.Wait()
+}
+
+// batchGlobalArgs carries the root flags given on the batch command line
+// (--account, --color, --http-header, ...) into every command, so worker
+// processes see them too. They come first so flags on the command line itself
+// win; --no-input is forced because stdin may be the command list. Flags set
+// through the environment or config are resolved again by each command.
+func batchGlobalArgs(kctx *kong.Context) []string {
+	args := []string{"--no-input"}
+	if kctx == nil {
+		return args
+	}
+	seen := map[string]bool{"no-input": true}
+	for _, p := range kctx.Path {
+		if p.Flag == nil || seen[p.Flag.Name] || !slices.Contains(kctx.Model.Flags, p.Flag) {
+			continue
+		}
+		seen[p.Flag.Name] = true
+		if values, ok := kctx.FlagValue(p.Flag).([]string); ok {
+			for _, v := range values {
+				args = append(args, "--"+p.Flag.Name+"="+v)
+			}
+			continue
+		}
+		args = append(args, fmt.Sprintf("--%s=%v", p.Flag.Name, kctx.FlagValue(p.Flag)))
+	}
+	return args
+}
+
+func readBatchFile(path string, prefix []string) ([]batchCommand, error) {
+	var r io.Reader
+	if strings.TrimSpace(path) == "-" {
+		r = os.Stdin
+	} else {
+		f, err := os.Open(path) //nolint:gosec // user-provided path
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var commands []batchCommand
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		args, err := splitBatchLine(text)
+		if err != nil {
+			return nil, usagef("%s:%d: %v", path, line, err)
+		}
+		if len(args) > 0 && args[0] == "gog" {
+			args = args[1:]
+		}
+		if len(args) == 0 {
+			continue
+		}
+		commands = append(commands, batchCommand{
+			Index: len(commands),
+			Line:  line,
+			Args:  append(append([]string{}, prefix...), args...),
+		})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return commands, nil
+}
+
+// splitBatchLine splits a command line into words with POSIX-shell quoting:
+// single quotes are literal, double quotes allow \" and \\, and a backslash
+// outside quotes escapes the next character. Variables and globs are not
+// expanded.
+func splitBatchLine(line string) ([]string, error) {
+	var words []string
+	var cur strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			if quote == '"' && r != '"' && r != '\\' && r != '$' && r != '`' {
+				cur.WriteRune('\\')
+			}
+			cur.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			cur.WriteRune(r)
+			inWord = true
+		}
+	}
+	if escaped || quote != 0 {
+		return nil, errors.New("unterminated quote or escape")
+	}
+	if inWord {
+		words = append(words, cur.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/mock"
+)
+
+func TestSplitBatchLine(t *testing.T) {
+	cases := map[string][]string{
+		`gmail search "from:alice subject:hi" --max 5`: {"gmail", "search", "from:alice subject:hi", "--max", "5"},
+		`drive ls --query 'name contains "x"'`:         {"drive", "ls", "--query", `name contains "x"`},
+		`a\ b "c\"d" "e\f" ''`:                         {"a b", `c"d`, `e\f`, ""},
+		"  tabs\tand  spaces ":                         {"tabs", "and", "spaces"},
+	}
+	for line, want := range cases {
+		got, err := splitBatchLine(line)
+		if err != nil {
+			t.Fatalf("%q: %v", line, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("%q: got %q, want %q", line, got, want)
+		}
+	}
+	if _, err := splitBatchLine(`gmail search "open`); err == nil {
+		t.Fatalf("expected unterminated quote error")
+	}
+}
+
+func TestBatchCmd_Sequential(t *testing.T) {
+	s, err := mock.New(nil, mock.ServiceGmail)
+	if err != nil {
+		t.Fatalf("mock.New: %v", err)
+	}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	t.Setenv(googleapi.EnvAPIEndpoint, srv.URL)
+
+	file := filepath.Join(t.TempDir(), "commands.txt")
+	body := strings.Join([]string{
+		"# labels first",
+		"gog gmail labels list",
+		"",
+		"gmail get doesnotexist",
+		"gmail labels list",
+	}, "\n")
+	if err := os.WriteFile(file, []byte(body), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	var runErr error
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			runErr = Execute([]string{"--json", "--account", "a@b.com", "batch", "--file", file, "--stop-on-error"})
+		})
+	})
+	if ExitCode(runErr) != 1 {
+		t.Fatalf("expected exit 1, got %v", runErr)
+	}
+
+	var results []batchResult
+	dec := json.NewDecoder(strings.NewReader(out))
+	for dec.More() {
+		var res batchResult
+		if err := dec.Decode(&res); err != nil {
+			t.Fatalf("ndjson: %v\n%s", err, out)
+		}
+		results = append(results, res)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results (stop on error), got %d:\n%s", len(results), out)
+	}
+	first := results[0]
+	if !first.OK || first.Line != 2 || first.Args[len(first.Args)-1] != "list" || !strings.Contains(string(first.Output), "INBOX") {
+		t.Fatalf("unexpected first result: %#v", first)
+	}
+	for _, want := range []string{"--no-input", "--account", "a@b.com", "--json"} {
+		if !strings.Contains(strings.Join(first.Args, " "), want) {
+			t.Fatalf("global flag %s not propagated: %v", want, first.Args)
+		}
+	}
+	second := results[1]
+	if second.OK || second.ExitCode != 4 || second.Error == nil || second.Line != 4 {
+		t.Fatalf("unexpected second result: %#v", second)
+	}
+}
+
+func TestBatchGlobalArgs(t *testing.T) {
+	parser, _, err := newParser("")
+	if err != nil {
+		t.Fatalf("newParser: %v", err)
+	}
+	kctx, err := parser.Parse([]string{
+		"--color", "never", "--user-agent-suffix", "ci", "--http-header", "X-A: 1", "--http-header", "X-B: 2", "--no-input",
+		"batch", "--file", "cmds.txt", "--parallel", "2", "--json",
+	})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := []string{"--no-input", "--color=never", "--user-agent-suffix=ci", "--http-header=X-A: 1", "--http-header=X-B: 2", "--json=true"}
+	if got := batchGlobalArgs(kctx); !reflect.DeepEqual(got, want) {
+		t.Fatalf("batchGlobalArgs = %q, want %q", got, want)
+	}
+}
+
+func TestBatchCmd_RejectsNesting(t *testing.T) {
+	res := runBatchCommand(batchCommand{Args: []string{"batch", "--file", "-"}})
+	if res.OK || res.ExitCode != 2 {
+		t.Fatalf("expected usage error, got %#v", res)
+	}
+}
+
+func TestServeBatchWorker(t *testing.T) {
+	orig := executeBatchArgs
+	t.Cleanup(func() { executeBatchArgs = orig })
+	executeBatchArgs = func(args []string) error {
+		if args[0] == "fail" {
+			return usage("bad")
+		}
+		_, _ = os.Stdout.WriteString("plain " + args[0] + "\n")
+		return nil
+	}
+
+	in := strings.NewReader(`{"index":0,"line":1,"args":["one"]}` + "\n" + `{"index":1,"line":2,"args":["fail"]}` + "\n")
+	var out bytes.Buffer
+	if err := serveBatchWorker(in, &out); err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("unexpected output: %s", out.String())
+	}
+	var a, b batchResult
+	_ = json.Unmarshal([]byte(lines[0]), &a)
+	_ = json.Unmarshal([]byte(lines[1]), &b)
+	if !a.OK || a.Stdout != "plain one\n" || b.OK || b.ExitCode != 2 || b.Index != 1 {
+		t.Fatalf("unexpected results: %#v %#v", a, b)
+	}
+}
+
+type fakeBatchWorker struct {
+	mu     *sync.Mutex
+	ran    *[]int
+	closed *int
+}
+
+func (f fakeBatchWorker) Run(bc batchCommand) (batchResult, error) {
+	f.mu.Lock()
+	*f.ran = append(*f.ran, bc.Index)
+	f.mu.Unlock()
+	if bc.Args[0] == "broken" {
+		return batchResult{}, errors.New("worker died")
+	}
+	return batchResult{batchCommand: bc, OK: bc.Args[0] != "fail"}, nil
+}
+
+func (f fakeBatchWorker) Close() error {
+	f.mu.Lock()
+	*f.closed++
+	f.mu.Unlock()
+	return nil
+}
+
+func TestRunBatchParallel(t *testing.T) {
+	orig := startBatchWorker
+	t.Cleanup(func() { startBatchWorker = orig })
+
+	var mu sync.Mutex
+	var ran []int
+	closed := 0
+	startBatchWorker = func(context.Context) (batchWorker, error) {
+		return fakeBatchWorker{mu: &mu, ran: &ran, closed: &closed}, nil
+	}
+
+	var commands []batchCommand
+	for i := range 20 {
+		commands = append(commands, batchCommand{Index: i, Args: []string{"ok"}})
+	}
+	var emitted []batchResult
+	emit := func(res batchResult) {
+		mu.Lock()
+		emitted = append(emitted, res)
+		mu.Unlock()
+	}
+	if err := runBatchParallel(context.Background(), commands, 4, false, emit); err != nil {
+		t.Fatalf("parallel: %v", err)
+	}
+	if len(emitted) != 20 || closed != 4 {
+		t.Fatalf("emitted=%d closed=%d", len(emitted), closed)
+	}
+
+	ran, emitted, closed = nil, nil, 0
+	commands[3].Args = []string{"broken"}
+	if err := runBatchParallel(context.Background(), commands, 2, false, emit); err == nil || !strings.Contains(err.Error(), "worker died") {
+		t.Fatalf("expected worker error, got %v", err)
+	}
+	if closed != 2 || len(ran) == 20 {
+		t.Fatalf("expected early stop: ran=%d closed=%d", len(ran), closed)
+	}
+}
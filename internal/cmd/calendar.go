@@ -20,11 +20,16 @@ type CalendarCmd struct {
 	Delete          CalendarDeleteCmd          `cmd:"" name:"delete" help:"Delete an event"`
 	FreeBusy        CalendarFreeBusyCmd        `cmd:"" name:"freebusy" help:"Get free/busy"`
 	Respond         CalendarRespondCmd         `cmd:"" name:"respond" help:"Respond to an event invitation"`
+	Attendees       CalendarAttendeesCmd       `cmd:"" name:"attendees" help:"List, invite, or remove event attendees"`
 	ProposeTime     CalendarProposeTimeCmd     `cmd:"" name:"propose-time" help:"Generate URL to propose a new meeting time (browser-only feature)"`
 	Colors          CalendarColorsCmd          `cmd:"" name:"colors" help:"Show calendar colors"`
+	Settings        CalendarSettingsCmd        `cmd:"" name:"settings" help:"Show calendar settings (timezone, week start, ...)"`
 	Conflicts       CalendarConflictsCmd       `cmd:"" name:"conflicts" help:"Find conflicts"`
+	Rules           CalendarRulesCmd           `cmd:"" name:"rules" help:"Auto-decline rules from config (list, process)"`
+	Report          CalendarReportCmd          `cmd:"" name:"report" help:"Reports over a time range (meeting load)"`
 	Search          CalendarSearchCmd          `cmd:"" name:"search" help:"Search events"`
 	Time            CalendarTimeCmd            `cmd:"" name:"time" help:"Show server time"`
+	Watch           CalendarWatchCmd           `cmd:"" name:"watch" help:"Announce events shortly before they start (calendar.event.starting in the event log)"`
 	Users           CalendarUsersCmd           `cmd:"" name:"users" help:"List workspace users (use their email as calendar ID)"`
 	Team            CalendarTeamCmd            `cmd:"" name:"team" help:"Show events for all members of a Google Group"`
 	FocusTime       CalendarFocusTimeCmd       `cmd:"" name:"focus-time" help:"Create a Focus Time block"`
@@ -53,6 +58,13 @@ func (c *CalendarCalendarsCmd) Run(ctx context.Context, flags *RootFlags) error
 	if err != nil {
 		return err
 	}
+	if c.Page == "" && resp.NextPageToken == "" {
+		ids := []string{"primary"}
+		for _, cal := range resp.Items {
+			ids = append(ids, cal.Id)
+		}
+		rememberCompletionValues(account, completeCalendars, ids)
+	}
 	if outfmt.IsJSON(ctx) {
 		return outfmt.WriteJSON(os.Stdout, map[string]any{
 			"calendars":     resp.Items,
@@ -75,7 +87,7 @@ func (c *CalendarCalendarsCmd) Run(ctx context.Context, flags *RootFlags) error
 }
 
 type CalendarAclCmd struct {
-	CalendarID string `arg:"" name:"calendarId" help:"Calendar ID"`
+	CalendarID string `arg:"" name:"calendarId" complete:"calendars" help:"Calendar ID"`
 	Max        int64  `name:"max" aliases:"limit" help:"Max results" default:"100"`
 	Page       string `name:"page" help:"Page token"`
 }
@@ -128,9 +140,9 @@ func (c *CalendarAclCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type CalendarEventsCmd struct {
-	CalendarID        string `arg:"" name:"calendarId" optional:"" help:"Calendar ID (default: primary)"`
-	From              string `name:"from" help:"Start time (RFC3339, date, or relative: today, tomorrow, monday)"`
-	To                string `name:"to" help:"End time (RFC3339, date, or relative)"`
+	CalendarID        string `arg:"" name:"calendarId" complete:"calendars" optional:"" help:"Calendar ID (default: primary)"`
+	From              string `name:"from" help:"Start time (RFC3339, date, or relative: today, tomorrow, monday, -7d)"`
+	To                string `name:"to" help:"End time (RFC3339, date, or relative: +3d)"`
 	Today             bool   `name:"today" help:"Today only (timezone-aware)"`
 	Tomorrow          bool   `name:"tomorrow" help:"Tomorrow only (timezone-aware)"`
 	Week              bool   `name:"week" help:"This week (uses --week-start, default Mon)"`
@@ -188,7 +200,7 @@ func (c *CalendarEventsCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type CalendarEventCmd struct {
-	CalendarID string `arg:"" name:"calendarId" help:"Calendar ID"`
+	CalendarID string `arg:"" name:"calendarId" complete:"calendars" help:"Calendar ID"`
 	EventID    string `arg:"" name:"eventId" help:"Event ID"`
 }
 
@@ -0,0 +1,291 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	reports "google.golang.org/api/admin/reports/v1"
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+var newAdminReportsService = googleapi.NewAdminReports
+
+type CalendarAttendeesExportCmd struct {
+	EventID    string    `arg:"" name:"eventId" optional:"" help:"Event ID (or use --query)"`
+	CalendarID string    `name:"calendar" help:"Calendar ID" default:"primary"`
+	Query      string    `name:"query" help:"Export every event matching this text instead of one event"`
+	Since      SinceTime `name:"since" help:"With --query: look back this far (e.g. 30d) or since a date (YYYY-MM-DD)" default:"30d"`
+	Max        int64     `name:"max" aliases:"limit" help:"With --query: max events" default:"50"`
+	NoMeet     bool      `name:"no-meet" help:"Skip Meet join/leave times from the Admin Reports API"`
+}
+
+// attendanceRow is one person's invitation and Meet attendance for an event.
+// Joined, Left, and Minutes are empty when the Reports API has no record.
+type attendanceRow struct {
+	EventID    string `json:"eventId"`
+	EventTitle string `json:"eventTitle"`
+	EventStart string `json:"eventStart"`
+	Email      string `json:"email"`
+	Name       string `json:"name,omitempty"`
+	Response   string `json:"response"`
+	Optional   bool   `json:"optional"`
+	Organizer  bool   `json:"organizer"`
+	Joined     string `json:"joined,omitempty"`
+	Left       string `json:"left,omitempty"`
+	Minutes    int64  `json:"minutes"`
+	Sessions   int    `json:"sessions"`
+}
+
+// meetAttendance sums one participant's Meet sessions in a call.
+type meetAttendance struct {
+	name     string
+	join     time.Time
+	leave    time.Time
+	seconds  int64
+	sessions int
+}
+
+func (c *CalendarAttendeesExportCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	eventID := strings.TrimSpace(c.EventID)
+	query := strings.TrimSpace(c.Query)
+	if (eventID == "") == (query == "") {
+		return usage("give an eventId or --query (not both)")
+	}
+	if c.Max <= 0 {
+		return usage("--max must be > 0")
+	}
+
+	svc, err := newCalendarService(ctx, account)
+	if err != nil {
+		return err
+	}
+	var events []*calendar.Event
+	if eventID != "" {
+		event, getErr := svc.Events.Get(c.CalendarID, eventID).Context(ctx).Do()
+		if getErr != nil {
+			return getErr
+		}
+		events = append(events, event)
+	} else {
+		resp, listErr := svc.Events.List(c.CalendarID).
+			Q(query).
+			TimeMin(c.Since.Time(time.Now()).Format(time.RFC3339)).
+			TimeMax(time.Now().Format(time.RFC3339)).
+			SingleEvents(true).
+			OrderBy("startTime").
+			MaxResults(c.Max).
+			Context(ctx).
+			Do()
+		if listErr != nil {
+			return listErr
+		}
+		events = resp.Items
+	}
+
+	var rsvc *reports.Service
+	if !c.NoMeet {
+		if rsvc, err = newAdminReportsService(ctx, account); err != nil {
+			u.Err().Printf("Meet attendance unavailable: %v", err)
+		}
+	}
+
+	var rows []attendanceRow
+	for _, e := range events {
+		if e == nil || e.Status == "cancelled" {
+			continue
+		}
+		if interrupted(ctx) {
+			break
+		}
+		var meet map[string]*meetAttendance
+		if rsvc != nil {
+			m, meetErr := fetchMeetAttendance(ctx, rsvc, e.Id)
+			if meetErr != nil {
+				// Reports needs a Workspace admin; the invitation data is still useful.
+				u.Err().Printf("Meet attendance unavailable: %v", meetErr)
+				rsvc = nil
+			}
+			meet = m
+		}
+		rows = append(rows, attendanceRows(e, meet)...)
+	}
+
+	if outfmt.IsJSON(ctx) {
+		if rows == nil {
+			rows = []attendanceRow{}
+		}
+		return outfmt.WriteJSON(os.Stdout, map[string]any{"attendance": rows})
+	}
+	if len(rows) == 0 && query != "" {
+		u.Err().Println("No matching events")
+	}
+	return writeAttendanceCSV(rows)
+}
+
+// fetchMeetAttendance reads Meet "call ended" audit events for a calendar
+// event, one per participant session, keyed by lowercased email.
+func fetchMeetAttendance(ctx context.Context, svc *reports.Service, eventID string) (map[string]*meetAttendance, error) {
+	out := map[string]*meetAttendance{}
+	pageToken := ""
+	for {
+		call := svc.Activities.List("all", "meet").
+			EventName("call_ended").
+			Filters("calendar_event_id==" + eventID).
+			MaxResults(1000).
+			Context(ctx)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range resp.Items {
+			if a == nil {
+				continue
+			}
+			for _, ev := range a.Events {
+				if ev == nil || ev.Name != "call_ended" {
+					continue
+				}
+				recordMeetSession(out, a, ev)
+			}
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+	return out, nil
+}
+
+func recordMeetSession(out map[string]*meetAttendance, a *reports.Activity, ev *reports.ActivityEvents) {
+	var email, name string
+	var start, seconds int64
+	for _, p := range ev.Parameters {
+		if p == nil {
+			continue
+		}
+		switch p.Name {
+		case "identifier":
+			email = p.Value
+		case "display_name":
+			name = p.Value
+		case "start_timestamp_seconds":
+			start = p.IntValue
+		case "duration_seconds":
+			seconds = p.IntValue
+		}
+	}
+	if email == "" && a.Actor != nil {
+		email = a.Actor.Email
+	}
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return // phone dial-ins and anonymous guests carry no address
+	}
+	join := time.Unix(start, 0)
+	if start == 0 && a.Id != nil {
+		if ended, err := time.Parse(time.RFC3339, a.Id.Time); err == nil {
+			join = ended.Add(-time.Duration(seconds) * time.Second)
+		}
+	}
+	leave := join.Add(time.Duration(seconds) * time.Second)
+
+	m, ok := out[email]
+	if !ok {
+		m = &meetAttendance{name: name, join: join, leave: leave}
+		out[email] = m
+	}
+	if join.Before(m.join) {
+		m.join = join
+	}
+	if leave.After(m.leave) {
+		m.leave = leave
+	}
+	m.seconds += seconds
+	m.sessions++
+}
+
+// attendanceRows lists invited attendees first, then Meet participants who
+// joined without an invitation.
+func attendanceRows(e *calendar.Event, meet map[string]*meetAttendance) []attendanceRow {
+	start := ""
+	if e.Start != nil {
+		start = orEmpty(e.Start.DateTime, e.Start.Date)
+	}
+	base := attendanceRow{EventID: e.Id, EventTitle: e.Summary, EventStart: start}
+	withMeet := func(r attendanceRow, m *meetAttendance) attendanceRow {
+		if m == nil {
+			return r
+		}
+		r.Joined = m.join.UTC().Format(time.RFC3339)
+		r.Left = m.leave.UTC().Format(time.RFC3339)
+		r.Minutes = (m.seconds + 30) / 60
+		r.Sessions = m.sessions
+		if r.Name == "" {
+			r.Name = m.name
+		}
+		return r
+	}
+
+	var rows []attendanceRow
+	invited := map[string]bool{}
+	for _, a := range e.Attendees {
+		if a == nil || a.Resource {
+			continue
+		}
+		key := strings.ToLower(a.Email)
+		invited[key] = true
+		r := base
+		r.Email, r.Name, r.Response, r.Optional, r.Organizer = a.Email, a.DisplayName, a.ResponseStatus, a.Optional, a.Organizer
+		rows = append(rows, withMeet(r, meet[key]))
+	}
+	var uninvited []string
+	for email := range meet {
+		if !invited[email] {
+			uninvited = append(uninvited, email)
+		}
+	}
+	sort.Strings(uninvited)
+	for _, email := range uninvited {
+		r := base
+		r.Email, r.Response = email, "notInvited"
+		rows = append(rows, withMeet(r, meet[email]))
+	}
+	return rows
+}
+
+func writeAttendanceCSV(rows []attendanceRow) error {
+	cw := csv.NewWriter(os.Stdout)
+	if err := cw.Write([]string{"event_id", "event_title", "event_start", "email", "name", "response", "optional", "organizer", "joined", "left", "minutes", "sessions"}); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		record := []string{
+			r.EventID, r.EventTitle, r.EventStart,
+			r.Email, r.Name, r.Response,
+			fmt.Sprint(r.Optional), fmt.Sprint(r.Organizer),
+			r.Joined, r.Left, fmt.Sprint(r.Minutes), fmt.Sprint(r.Sessions),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	reports "google.golang.org/api/admin/reports/v1"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/option"
+)
+
+func TestCalendarAttendeesExport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/calendars/primary/events/ev1"):
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id": "ev1", "summary": "Safety training", "start": map[string]string{"dateTime": "2026-10-01T10:00:00Z"},
+				"attendees": []map[string]any{
+					{"email": "Ann@example.com", "responseStatus": "accepted", "organizer": true},
+					{"email": "bob@example.com", "responseStatus": "declined", "optional": true},
+					{"email": "room@resource.calendar.google.com", "resource": true},
+				},
+			})
+		case strings.HasSuffix(r.URL.Path, "/activity/users/all/applications/meet"):
+			if got := r.URL.Query().Get("filters"); got != "calendar_event_id==ev1" {
+				t.Errorf("filters = %q", got)
+			}
+			session := func(email string, start, seconds string) map[string]any {
+				return map[string]any{"id": map[string]string{"time": "2026-10-01T11:00:00Z"}, "events": []map[string]any{{
+					"name": "call_ended",
+					"parameters": []map[string]string{
+						{"name": "identifier", "value": email},
+						{"name": "start_timestamp_seconds", "intValue": start},
+						{"name": "duration_seconds", "intValue": seconds},
+					},
+				}}}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"items": []map[string]any{
+				session("ann@example.com", "1790848860", "1200"), // 10:01 for 20m
+				session("ann@example.com", "1790850600", "1800"), // 10:30 for 30m
+				session("guest@other.example", "1790849100", "600"),
+			}})
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	origCal, origReports := newCalendarService, newAdminReportsService
+	t.Cleanup(func() { newCalendarService, newAdminReportsService = origCal, origReports })
+	newCalendarService = func(ctx context.Context, _ string) (*calendar.Service, error) {
+		return calendar.NewService(ctx, option.WithoutAuthentication(), option.WithHTTPClient(srv.Client()), option.WithEndpoint(srv.URL+"/"))
+	}
+	newAdminReportsService = func(ctx context.Context, _ string) (*reports.Service, error) {
+		return reports.NewService(ctx, option.WithoutAuthentication(), option.WithHTTPClient(srv.Client()), option.WithEndpoint(srv.URL+"/"))
+	}
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--account", "admin@example.com", "calendar", "attendees", "export", "ev1"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil {
+		t.Fatalf("csv: %v\n%s", err, out)
+	}
+	if len(records) != 4 {
+		t.Fatalf("want header + ann + bob + guest, got:\n%s", out)
+	}
+	ann, bob, guest := records[1], records[2], records[3]
+	if ann[3] != "Ann@example.com" || ann[5] != "accepted" || ann[7] != "true" || ann[8] != "2026-10-01T10:01:00Z" || ann[9] != "2026-10-01T11:00:00Z" || ann[10] != "50" || ann[11] != "2" {
+		t.Fatalf("ann = %v", ann)
+	}
+	if bob[5] != "declined" || bob[6] != "true" || bob[8] != "" || bob[10] != "0" {
+		t.Fatalf("bob = %v", bob)
+	}
+	if guest[3] != "guest@other.example" || guest[5] != "notInvited" || guest[10] != "10" {
+		t.Fatalf("guest = %v", guest)
+	}
+
+	if err := Execute([]string{"--account", "admin@example.com", "calendar", "attendees", "export"}); ExitCode(err) != 2 {
+		t.Fatalf("want usage error without eventId or --query, got %v", err)
+	}
+}
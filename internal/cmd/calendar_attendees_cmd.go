@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+type CalendarAttendeesCmd struct {
+	List   CalendarAttendeesListCmd   `cmd:"" name:"list" aliases:"ls" help:"List attendees and their responses"`
+	Add    CalendarAttendeesAddCmd    `cmd:"" name:"add" help:"Invite attendees (keeps existing attendees and responses)"`
+	Remove CalendarAttendeesRemoveCmd `cmd:"" name:"remove" aliases:"rm" help:"Remove attendees"`
+	Export CalendarAttendeesExportCmd `cmd:"" name:"export" help:"Export attendees, responses, and Meet join/leave times as CSV (one event or --query)"`
+}
+
+type CalendarAttendeesListCmd struct {
+	CalendarID string `arg:"" name:"calendarId" complete:"calendars" help:"Calendar ID"`
+	EventID    string `arg:"" name:"eventId" help:"Event ID"`
+}
+
+func (c *CalendarAttendeesListCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	svc, calendarID, eventID, err := calendarAttendeesTarget(ctx, flags, c.CalendarID, c.EventID)
+	if err != nil {
+		return err
+	}
+	event, err := svc.Events.Get(calendarID, eventID).Fields("id,attendees").Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+	if outfmt.IsJSON(ctx) {
+		attendees := event.Attendees
+		if attendees == nil {
+			attendees = []*calendar.EventAttendee{}
+		}
+		return outfmt.WriteJSON(os.Stdout, map[string]any{"eventId": event.Id, "attendees": attendees})
+	}
+	if len(event.Attendees) == 0 {
+		u.Err().Println("No attendees")
+		return nil
+	}
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "EMAIL\tRESPONSE\tOPTIONAL\tCOMMENT")
+	for _, a := range event.Attendees {
+		if a == nil {
+			continue
+		}
+		email := a.Email
+		if a.Organizer {
+			email += " (organizer)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%t\t%s\n", email, a.ResponseStatus, a.Optional, a.Comment)
+	}
+	return nil
+}
+
+type CalendarAttendeesAddCmd struct {
+	CalendarID  string   `arg:"" name:"calendarId" complete:"calendars" help:"Calendar ID"`
+	EventID     string   `arg:"" name:"eventId" help:"Event ID"`
+	Emails      []string `arg:"" name:"email" help:"Attendee emails (email or email;optional)"`
+	Optional    bool     `name:"optional" help:"Mark the new attendees as optional"`
+	SendUpdates string   `name:"send-updates" aliases:"notify" help:"Notification mode: all, externalOnly, none (default: all)" default:"all"`
+}
+
+func (c *CalendarAttendeesAddCmd) Run(ctx context.Context, flags *RootFlags) error {
+	var added []*calendar.EventAttendee
+	for _, raw := range c.Emails {
+		for _, a := range buildAttendees(raw) {
+			if c.Optional {
+				a.Optional = true
+			}
+			added = append(added, a)
+		}
+	}
+	if len(added) == 0 {
+		return usage("missing attendee email")
+	}
+	return patchCalendarAttendees(ctx, flags, c.CalendarID, c.EventID, c.SendUpdates, func(existing []*calendar.EventAttendee) ([]*calendar.EventAttendee, []string) {
+		seen := make(map[string]bool, len(existing))
+		for _, a := range existing {
+			if a != nil {
+				seen[strings.ToLower(a.Email)] = true
+			}
+		}
+		out := existing
+		var changed []string
+		for _, a := range added {
+			key := strings.ToLower(a.Email)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			a.ResponseStatus = "needsAction"
+			out = append(out, a)
+			changed = append(changed, a.Email)
+		}
+		return out, changed
+	})
+}
+
+type CalendarAttendeesRemoveCmd struct {
+	CalendarID  string   `arg:"" name:"calendarId" complete:"calendars" help:"Calendar ID"`
+	EventID     string   `arg:"" name:"eventId" help:"Event ID"`
+	Emails      []string `arg:"" name:"email" help:"Attendee emails to remove"`
+	SendUpdates string   `name:"send-updates" aliases:"notify" help:"Notification mode: all, externalOnly, none (default: all)" default:"all"`
+}
+
+func (c *CalendarAttendeesRemoveCmd) Run(ctx context.Context, flags *RootFlags) error {
+	remove := map[string]bool{}
+	for _, raw := range c.Emails {
+		for _, email := range splitCSV(raw) {
+			remove[strings.ToLower(email)] = true
+		}
+	}
+	if len(remove) == 0 {
+		return usage("missing attendee email")
+	}
+	return patchCalendarAttendees(ctx, flags, c.CalendarID, c.EventID, c.SendUpdates, func(existing []*calendar.EventAttendee) ([]*calendar.EventAttendee, []string) {
+		out := make([]*calendar.EventAttendee, 0, len(existing))
+		var changed []string
+		for _, a := range existing {
+			if a != nil && remove[strings.ToLower(a.Email)] {
+				changed = append(changed, a.Email)
+				continue
+			}
+			out = append(out, a)
+		}
+		return out, changed
+	})
+}
+
+func calendarAttendeesTarget(ctx context.Context, flags *RootFlags, rawCalendarID, rawEventID string) (*calendar.Service, string, string, error) {
+	account, err := requireAccount(flags)
+	if err != nil {
+		return nil, "", "", err
+	}
+	calendarID := strings.TrimSpace(rawCalendarID)
+	eventID := strings.TrimSpace(rawEventID)
+	if calendarID == "" {
+		return nil, "", "", usage("empty calendarId")
+	}
+	if eventID == "" {
+		return nil, "", "", usage("empty eventId")
+	}
+	svc, err := newCalendarService(ctx, account)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return svc, calendarID, eventID, nil
+}
+
+// patchCalendarAttendees reads the current attendee list, lets edit change
+// it, and patches only the attendees so responses and other fields stay
+// untouched.
+func patchCalendarAttendees(ctx context.Context, flags *RootFlags, rawCalendarID, rawEventID, rawSendUpdates string, edit func([]*calendar.EventAttendee) ([]*calendar.EventAttendee, []string)) error {
+	u := ui.FromContext(ctx)
+	sendUpdates, err := validateSendUpdates(rawSendUpdates)
+	if err != nil {
+		return err
+	}
+	svc, calendarID, eventID, err := calendarAttendeesTarget(ctx, flags, rawCalendarID, rawEventID)
+	if err != nil {
+		return err
+	}
+	event, err := svc.Events.Get(calendarID, eventID).Fields("id,attendees").Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+	attendees, changed := edit(event.Attendees)
+
+	updated := event
+	if len(changed) > 0 {
+		patch := &calendar.Event{Attendees: attendees}
+		if len(attendees) == 0 {
+			patch.ForceSendFields = []string{"Attendees"}
+		}
+		call := svc.Events.Patch(calendarID, eventID, patch).Context(ctx)
+		if sendUpdates != "" {
+			call = call.SendUpdates(sendUpdates)
+		}
+		if updated, err = call.Do(); err != nil {
+			return err
+		}
+	}
+
+	if outfmt.IsJSON(ctx) {
+		if changed == nil {
+			changed = []string{}
+		}
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"eventId":   eventID,
+			"changed":   changed,
+			"attendees": updated.Attendees,
+		})
+	}
+	if len(changed) == 0 {
+		u.Err().Println("No attendee changes")
+	}
+	u.Out().Printf("id\t%s", eventID)
+	u.Out().Printf("changed\t%d", len(changed))
+	printEventAttendees(u, updated.Attendees)
+	return nil
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/option"
+)
+
+// stubCalendarEventServer serves GET/PATCH for one event and records patches.
+func stubCalendarEventServer(t *testing.T, event map[string]any) (patches *[]map[string]any, queries *[]string) {
+	t.Helper()
+	origNew := newCalendarService
+	t.Cleanup(func() { newCalendarService = origNew })
+
+	var gotPatches []map[string]any
+	var gotQueries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/calendars/c1/events/e1") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode(event)
+		case http.MethodPatch:
+			var payload map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			gotPatches = append(gotPatches, payload)
+			gotQueries = append(gotQueries, r.URL.RawQuery)
+			payload["id"] = "e1"
+			_ = json.NewEncoder(w).Encode(payload)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	svc, err := calendar.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newCalendarService = func(context.Context, string) (*calendar.Service, error) { return svc, nil }
+	return &gotPatches, &gotQueries
+}
+
+func TestCalendarAttendeesAdd_PreservesResponses(t *testing.T) {
+	patches, queries := stubCalendarEventServer(t, map[string]any{
+		"id": "e1",
+		"attendees": []map[string]any{
+			{"email": "me@example.com", "organizer": true, "responseStatus": "accepted"},
+			{"email": "bob@example.com", "responseStatus": "declined"},
+		},
+	})
+
+	_ = captureStdout(t, func() {
+		if err := Execute([]string{"--json", "--account", "me@example.com", "calendar", "attendees", "add", "c1", "e1", "Bob@example.com", "carol@example.com;optional", "--notify", "none"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	if len(*patches) != 1 {
+		t.Fatalf("expected one patch, got %d", len(*patches))
+	}
+	if !strings.Contains((*queries)[0], "sendUpdates=none") {
+		t.Fatalf("expected sendUpdates=none, got %q", (*queries)[0])
+	}
+	attendees, _ := (*patches)[0]["attendees"].([]any)
+	if len(attendees) != 3 {
+		t.Fatalf("unexpected attendees: %#v", attendees)
+	}
+	bob, _ := attendees[1].(map[string]any)
+	carol, _ := attendees[2].(map[string]any)
+	if bob["responseStatus"] != "declined" || carol["email"] != "carol@example.com" || carol["optional"] != true {
+		t.Fatalf("unexpected attendees: %#v", attendees)
+	}
+	if _, ok := (*patches)[0]["summary"]; ok {
+		t.Fatalf("patch should only touch attendees: %#v", (*patches)[0])
+	}
+}
+
+func TestCalendarAttendeesRemove_LastAttendee(t *testing.T) {
+	patches, queries := stubCalendarEventServer(t, map[string]any{
+		"id":        "e1",
+		"attendees": []map[string]any{{"email": "bob@example.com", "responseStatus": "accepted"}},
+	})
+
+	_ = captureStdout(t, func() {
+		if err := Execute([]string{"--account", "me@example.com", "calendar", "attendees", "remove", "c1", "e1", "bob@example.com"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	if len(*patches) != 1 || !strings.Contains((*queries)[0], "sendUpdates=all") {
+		t.Fatalf("unexpected patches: %#v %v", *patches, *queries)
+	}
+	attendees, ok := (*patches)[0]["attendees"].([]any)
+	if !ok || len(attendees) != 0 {
+		t.Fatalf("expected an explicit empty attendee list, got %#v", (*patches)[0])
+	}
+}
+
+func TestCalendarUpdate_Meet(t *testing.T) {
+	patches, queries := stubCalendarEventServer(t, map[string]any{"id": "e1"})
+
+	_ = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "me@example.com", "calendar", "update", "c1", "e1", "--meet", "--notify", "all"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if len(*patches) != 1 {
+		t.Fatalf("expected one patch, got %d", len(*patches))
+	}
+	if q := (*queries)[0]; !strings.Contains(q, "conferenceDataVersion=1") || !strings.Contains(q, "sendUpdates=all") {
+		t.Fatalf("unexpected query %q", q)
+	}
+	conf, _ := (*patches)[0]["conferenceData"].(map[string]any)
+	req, _ := conf["createRequest"].(map[string]any)
+	key, _ := req["conferenceSolutionKey"].(map[string]any)
+	if req["requestId"] == "" || key["type"] != "hangoutsMeet" {
+		t.Fatalf("unexpected conferenceData: %#v", conf)
+	}
+}
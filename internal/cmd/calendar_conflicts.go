@@ -22,13 +22,13 @@ type conflict struct {
 }
 
 type CalendarConflictsCmd struct {
-	From      string `name:"from" help:"Start time (RFC3339, date, or relative: today, tomorrow, monday)"`
-	To        string `name:"to" help:"End time (RFC3339, date, or relative)"`
+	From      string `name:"from" help:"Start time (RFC3339, date, or relative: today, tomorrow, monday, -7d)"`
+	To        string `name:"to" help:"End time (RFC3339, date, or relative: +3d)"`
 	Today     bool   `name:"today" help:"Today only (timezone-aware)"`
 	Week      bool   `name:"week" help:"This week (uses --week-start, default Mon)"`
 	Days      int    `name:"days" help:"Next N days (timezone-aware)" default:"0"`
 	WeekStart string `name:"week-start" help:"Week start day for --week (sun, mon, ...)" default:""`
-	Calendars string `name:"calendars" help:"Comma-separated calendar IDs" default:"primary"`
+	Calendars string `name:"calendars" complete:"calendars" help:"Comma-separated calendar IDs" default:"primary"`
 }
 
 func (c *CalendarConflictsCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -14,44 +14,45 @@ import (
 )
 
 type CalendarCreateCmd struct {
-	CalendarID            string   `arg:"" name:"calendarId" help:"Calendar ID"`
-	Summary               string   `name:"summary" help:"Event summary/title"`
-	From                  string   `name:"from" help:"Start time (RFC3339)"`
-	To                    string   `name:"to" help:"End time (RFC3339)"`
-	Description           string   `name:"description" help:"Description"`
-	Location              string   `name:"location" help:"Location"`
-	Attendees             string   `name:"attendees" help:"Comma-separated attendee emails"`
-	AllDay                bool     `name:"all-day" help:"All-day event (use date-only in --from/--to)"`
-	Recurrence            []string `name:"rrule" help:"Recurrence rules (e.g., 'RRULE:FREQ=MONTHLY;BYMONTHDAY=11'). Can be repeated."`
-	Reminders             []string `name:"reminder" help:"Custom reminders as method:duration (e.g., popup:30m, email:1d). Can be repeated (max 5)."`
-	ColorId               string   `name:"event-color" help:"Event color ID (1-11). Use 'gog calendar colors' to see available colors."`
-	Visibility            string   `name:"visibility" help:"Event visibility: default, public, private, confidential"`
-	Transparency          string   `name:"transparency" help:"Show as busy (opaque) or free (transparent). Aliases: busy, free"`
-	SendUpdates           string   `name:"send-updates" help:"Notification mode: all, externalOnly, none (default: all)"`
-	GuestsCanInviteOthers *bool    `name:"guests-can-invite" help:"Allow guests to invite others"`
-	GuestsCanModify       *bool    `name:"guests-can-modify" help:"Allow guests to modify event"`
-	GuestsCanSeeOthers    *bool    `name:"guests-can-see-others" help:"Allow guests to see other guests"`
-	WithMeet              bool     `name:"with-meet" help:"Create a Google Meet video conference for this event"`
-	SourceUrl             string   `name:"source-url" help:"URL where event was created/imported from"`
-	SourceTitle           string   `name:"source-title" help:"Title of the source"`
-	Attachments           []string `name:"attachment" help:"File attachment URL (can be repeated)"`
-	PrivateProps          []string `name:"private-prop" help:"Private extended property (key=value, can be repeated)"`
-	SharedProps           []string `name:"shared-prop" help:"Shared extended property (key=value, can be repeated)"`
-	EventType             string   `name:"event-type" help:"Event type: default, focus-time, out-of-office, working-location"`
-	FocusAutoDecline      string   `name:"focus-auto-decline" help:"Focus Time auto-decline mode: none, all, new"`
-	FocusDeclineMessage   string   `name:"focus-decline-message" help:"Focus Time decline message"`
-	FocusChatStatus       string   `name:"focus-chat-status" help:"Focus Time chat status: available, doNotDisturb"`
-	OOOAutoDecline        string   `name:"ooo-auto-decline" help:"Out of Office auto-decline mode: none, all, new"`
-	OOODeclineMessage     string   `name:"ooo-decline-message" help:"Out of Office decline message"`
-	WorkingLocationType   string   `name:"working-location-type" help:"Working location type: home, office, custom"`
-	WorkingOfficeLabel    string   `name:"working-office-label" help:"Working location office name/label"`
-	WorkingBuildingId     string   `name:"working-building-id" help:"Working location building ID"`
-	WorkingFloorId        string   `name:"working-floor-id" help:"Working location floor ID"`
-	WorkingDeskId         string   `name:"working-desk-id" help:"Working location desk ID"`
-	WorkingCustomLabel    string   `name:"working-custom-label" help:"Working location custom label"`
+	CalendarID            string          `arg:"" name:"calendarId" complete:"calendars" help:"Calendar ID"`
+	Summary               string          `name:"summary" help:"Event summary/title"`
+	From                  string          `name:"from" help:"Start time (RFC3339)"`
+	To                    string          `name:"to" help:"End time (RFC3339)"`
+	Description           string          `name:"description" help:"Description"`
+	Location              string          `name:"location" help:"Location"`
+	Attendees             string          `name:"attendees" help:"Comma-separated attendee emails"`
+	AllDay                bool            `name:"all-day" help:"All-day event (use date-only in --from/--to)"`
+	Recurrence            []string        `name:"rrule" help:"Recurrence rules (e.g., 'RRULE:FREQ=MONTHLY;BYMONTHDAY=11'). Can be repeated."`
+	Reminders             []string        `name:"reminder" help:"Custom reminders as method:duration (e.g., popup:30m, email:1d). Can be repeated (max 5)."`
+	ColorId               string          `name:"event-color" help:"Event color ID (1-11). Use 'gog calendar colors' to see available colors."`
+	Visibility            string          `name:"visibility" help:"Event visibility: default, public, private, confidential"`
+	Transparency          string          `name:"transparency" help:"Show as busy (opaque) or free (transparent). Aliases: busy, free"`
+	SendUpdates           string          `name:"send-updates" aliases:"notify" help:"Notification mode: all, externalOnly, none (default: all)"`
+	GuestsCanInviteOthers *bool           `name:"guests-can-invite" help:"Allow guests to invite others"`
+	GuestsCanModify       *bool           `name:"guests-can-modify" help:"Allow guests to modify event"`
+	GuestsCanSeeOthers    *bool           `name:"guests-can-see-others" help:"Allow guests to see other guests"`
+	WithMeet              bool            `name:"with-meet" aliases:"meet" help:"Create a Google Meet video conference for this event"`
+	SourceUrl             string          `name:"source-url" help:"URL where event was created/imported from"`
+	SourceTitle           string          `name:"source-title" help:"Title of the source"`
+	Attachments           []string        `name:"attachment" help:"File attachment URL (can be repeated)"`
+	PrivateProps          []string        `name:"private-prop" help:"Private extended property (key=value, can be repeated)"`
+	SharedProps           []string        `name:"shared-prop" help:"Shared extended property (key=value, can be repeated)"`
+	EventType             string          `name:"event-type" help:"Event type: default, focus-time, out-of-office, working-location"`
+	FocusAutoDecline      string          `name:"focus-auto-decline" help:"Focus Time auto-decline mode: none, all, new"`
+	FocusDeclineMessage   string          `name:"focus-decline-message" help:"Focus Time decline message"`
+	FocusChatStatus       string          `name:"focus-chat-status" help:"Focus Time chat status: available, doNotDisturb"`
+	OOOAutoDecline        string          `name:"ooo-auto-decline" help:"Out of Office auto-decline mode: none, all, new"`
+	OOODeclineMessage     string          `name:"ooo-decline-message" help:"Out of Office decline message"`
+	WorkingLocationType   string          `name:"working-location-type" help:"Working location type: home, office, custom"`
+	WorkingOfficeLabel    string          `name:"working-office-label" help:"Working location office name/label"`
+	WorkingBuildingId     string          `name:"working-building-id" help:"Working location building ID"`
+	WorkingFloorId        string          `name:"working-floor-id" help:"Working location floor ID"`
+	WorkingDeskId         string          `name:"working-desk-id" help:"Working location desk ID"`
+	WorkingCustomLabel    string          `name:"working-custom-label" help:"Working location custom label"`
+	Idempotency           IdempotencyFlag `embed:""`
 }
 
-func (c *CalendarCreateCmd) Run(ctx context.Context, flags *RootFlags) error {
+func (c *CalendarCreateCmd) Run(ctx context.Context, flags *RootFlags) (err error) {
 	u := ui.FromContext(ctx)
 	account, err := requireAccount(flags)
 	if err != nil {
@@ -102,6 +103,12 @@ func (c *CalendarCreateCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 	transparency = applyEventTypeTransparencyDefault(transparency, eventType)
 
+	idem, done, err := beginIdempotent(ctx, c.Idempotency.Key, account, "calendar.create")
+	if done || err != nil {
+		return err
+	}
+	defer idem.finish(&err)
+
 	svc, err := newCalendarService(ctx, account)
 	if err != nil {
 		return err
@@ -157,6 +164,7 @@ func (c *CalendarCreateCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if err != nil {
 		return err
 	}
+	idem.resource(created.Id)
 	tz, loc, _ := getCalendarLocation(ctx, svc, calendarID)
 	if outfmt.IsJSON(ctx) {
 		return outfmt.WriteJSON(os.Stdout, map[string]any{"event": wrapEventWithDaysWithTimezone(created, tz, loc)})
@@ -295,7 +303,7 @@ func (c *CalendarCreateCmd) buildOutOfOfficeProperties() (*calendar.EventOutOfOf
 }
 
 type CalendarUpdateCmd struct {
-	CalendarID            string   `arg:"" name:"calendarId" help:"Calendar ID"`
+	CalendarID            string   `arg:"" name:"calendarId" complete:"calendars" help:"Calendar ID"`
 	EventID               string   `arg:"" name:"eventId" help:"Event ID"`
 	Summary               string   `name:"summary" help:"New summary/title (set empty to clear)"`
 	From                  string   `name:"from" help:"New start time (RFC3339; set empty to clear)"`
@@ -313,6 +321,8 @@ type CalendarUpdateCmd struct {
 	GuestsCanInviteOthers *bool    `name:"guests-can-invite" help:"Allow guests to invite others"`
 	GuestsCanModify       *bool    `name:"guests-can-modify" help:"Allow guests to modify event"`
 	GuestsCanSeeOthers    *bool    `name:"guests-can-see-others" help:"Allow guests to see other guests"`
+	WithMeet              bool     `name:"with-meet" aliases:"meet" help:"Add a Google Meet video conference to this event"`
+	SendUpdates           string   `name:"send-updates" aliases:"notify" help:"Notification mode: all, externalOnly, none (default: none)"`
 	Scope                 string   `name:"scope" help:"For recurring events: single, future, all" default:"all"`
 	OriginalStartTime     string   `name:"original-start" help:"Original start time of instance (required for scope=single,future)"`
 	PrivateProps          []string `name:"private-prop" help:"Private extended property (key=value, can be repeated)"`
@@ -376,6 +386,11 @@ func (c *CalendarUpdateCmd) Run(ctx context.Context, kctx *kong.Context, flags *
 		return usage("cannot use both --attendees and --add-attendee; use --attendees to replace all, or --add-attendee to add")
 	}
 
+	sendUpdates, err := validateSendUpdates(c.SendUpdates)
+	if err != nil {
+		return err
+	}
+
 	patch, changed, err := c.buildUpdatePatch(kctx)
 	if err != nil {
 		return err
@@ -414,7 +429,14 @@ func (c *CalendarUpdateCmd) Run(ctx context.Context, kctx *kong.Context, flags *
 		return err
 	}
 
-	updated, err := svc.Events.Patch(calendarID, targetEventID, patch).Do()
+	call := svc.Events.Patch(calendarID, targetEventID, patch)
+	if sendUpdates != "" {
+		call = call.SendUpdates(sendUpdates)
+	}
+	if c.WithMeet {
+		call = call.ConferenceDataVersion(1)
+	}
+	updated, err := call.Do()
 	if err != nil {
 		return err
 	}
@@ -484,6 +506,11 @@ func (c *CalendarUpdateCmd) buildUpdatePatch(kctx *kong.Context) (*calendar.Even
 		changed = true
 	}
 
+	if c.WithMeet {
+		patch.ConferenceData = buildConferenceData(true)
+		changed = true
+	}
+
 	eventTypeChanged, err := c.applyEventTypeProperties(kctx, patch, eventType, eventTypeRequested, focusFlags, oooFlags, workingFlags)
 	if err != nil {
 		return nil, false, err
@@ -801,7 +828,7 @@ func truncateParentRecurrence(ctx context.Context, svc *calendar.Service, calend
 }
 
 type CalendarDeleteCmd struct {
-	CalendarID        string `arg:"" name:"calendarId" help:"Calendar ID"`
+	CalendarID        string `arg:"" name:"calendarId" complete:"calendars" help:"Calendar ID"`
 	EventID           string `arg:"" name:"eventId" help:"Event ID"`
 	Scope             string `name:"scope" help:"For recurring events: single, future, all" default:"all"`
 	OriginalStartTime string `name:"original-start" help:"Original start time of instance (required for scope=single,future)"`
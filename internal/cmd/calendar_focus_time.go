@@ -13,7 +13,7 @@ import (
 )
 
 type CalendarFocusTimeCmd struct {
-	CalendarID     string   `arg:"" name:"calendarId" help:"Calendar ID (default: primary)" default:"primary"`
+	CalendarID     string   `arg:"" name:"calendarId" complete:"calendars" help:"Calendar ID (default: primary)" default:"primary"`
 	Summary        string   `name:"summary" help:"Focus time title" default:"Focus Time"`
 	From           string   `name:"from" required:"" help:"Start time (RFC3339)"`
 	To             string   `name:"to" required:"" help:"End time (RFC3339)"`
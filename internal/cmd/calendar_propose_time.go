@@ -24,7 +24,7 @@ const (
 // CalendarProposeTimeCmd generates a browser URL for proposing a new meeting time.
 // This is a workaround for a Google Calendar API limitation (since 2018).
 type CalendarProposeTimeCmd struct {
-	CalendarID string `arg:"" name:"calendarId" help:"Calendar ID"`
+	CalendarID string `arg:"" name:"calendarId" complete:"calendars" help:"Calendar ID"`
 	EventID    string `arg:"" name:"eventId" help:"Event ID"`
 	Open       bool   `name:"open" help:"Open the URL in browser automatically"`
 	Decline    bool   `name:"decline" help:"Also decline the event (notifies organizer)"`
@@ -0,0 +1,340 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+type CalendarReportCmd struct {
+	Load CalendarReportLoadCmd `cmd:"" name:"load" help:"Meeting load: hours per week and organizer, back-to-back and after-hours meetings"`
+}
+
+type CalendarReportLoadCmd struct {
+	CalendarID  string `name:"calendar" complete:"calendars" help:"Calendar ID" default:"primary"`
+	WorkHours   string `name:"work-hours" help:"Working hours as HH:MM-HH:MM; meetings outside them (or on weekends) count as after-hours" default:"09:00-18:00"`
+	B2BGap      string `name:"b2b-gap" help:"Max gap between meetings that still counts as back-to-back (Go duration)" default:"5m"`
+	IncludeSolo bool   `name:"include-solo" help:"Also count events without other attendees"`
+	CSV         bool   `name:"csv" help:"Write the report as CSV to stdout"`
+	TimeRangeFlags
+}
+
+// meetingLoad aggregates one slice of the report (a week, an organizer, or
+// the whole range).
+type meetingLoad struct {
+	Key         string  `json:"key"`
+	Meetings    int     `json:"meetings"`
+	Hours       float64 `json:"hours"`
+	BackToBack  int     `json:"backToBack"`
+	AfterHours  int     `json:"afterHours"`
+	minutesSeen float64
+}
+
+type loadMeeting struct {
+	start, end time.Time
+	organizer  string
+	backToBack bool
+	afterHours bool
+}
+
+func (c *CalendarReportLoadCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	if c.CSV && outfmt.IsJSON(ctx) {
+		return usage("use only one of --csv or --json")
+	}
+	workStart, workEnd, err := parseWorkHours(c.WorkHours)
+	if err != nil {
+		return usagef("--work-hours: %v", err)
+	}
+	gap, err := time.ParseDuration(strings.TrimSpace(c.B2BGap))
+	if err != nil || gap < 0 {
+		return usagef("invalid --b2b-gap %q", c.B2BGap)
+	}
+	weekStart, err := resolveWeekStart(c.WeekStart)
+	if err != nil {
+		return err
+	}
+
+	svc, err := newCalendarService(ctx, account)
+	if err != nil {
+		return err
+	}
+	tr, err := ResolveTimeRangeWithDefaults(ctx, svc, c.TimeRangeFlags, TimeRangeDefaults{
+		FromOffset:   -28 * 24 * time.Hour,
+		ToFromOffset: 28 * 24 * time.Hour,
+	})
+	if err != nil {
+		return err
+	}
+
+	meetings, err := c.fetchMeetings(ctx, svc, tr)
+	if err != nil {
+		return err
+	}
+	markMeetingLoad(meetings, gap, workStart, workEnd, tr.Location)
+
+	total := &meetingLoad{Key: "total"}
+	weeks := map[string]*meetingLoad{}
+	organizers := map[string]*meetingLoad{}
+	for _, m := range meetings {
+		week := startOfWeek(m.start.In(tr.Location), weekStart).Format("2006-01-02")
+		for _, agg := range []*meetingLoad{total, loadBucket(weeks, week), loadBucket(organizers, m.organizer)} {
+			agg.add(m)
+		}
+	}
+	weekRows := sortedLoads(weeks, func(a, b *meetingLoad) bool { return a.Key < b.Key })
+	organizerRows := sortedLoads(organizers, func(a, b *meetingLoad) bool {
+		if a.minutesSeen != b.minutesSeen {
+			return a.minutesSeen > b.minutesSeen
+		}
+		return a.Key < b.Key
+	})
+	total.round()
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"from":        tr.From.Format(time.RFC3339),
+			"to":          tr.To.Format(time.RFC3339),
+			"total":       total,
+			"weeks":       weekRows,
+			"organizers":  organizerRows,
+			"workHours":   c.WorkHours,
+			"backToBack":  gap.String(),
+			"includeSolo": c.IncludeSolo,
+		})
+	}
+	if c.CSV {
+		return writeMeetingLoadCSV(total, weekRows, organizerRows)
+	}
+	if total.Meetings == 0 {
+		u.Err().Println("No meetings")
+		return nil
+	}
+
+	u.Out().Printf("meetings\t%d", total.Meetings)
+	u.Out().Printf("hours\t%.1f", total.Hours)
+	u.Out().Printf("back_to_back\t%d", total.BackToBack)
+	u.Out().Printf("after_hours\t%d", total.AfterHours)
+	u.Out().Println("")
+
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "WEEK\tMEETINGS\tHOURS\tBACK_TO_BACK\tAFTER_HOURS")
+	for _, r := range weekRows {
+		fmt.Fprintf(w, "%s\t%d\t%.1f\t%d\t%d\n", r.Key, r.Meetings, r.Hours, r.BackToBack, r.AfterHours)
+	}
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "ORGANIZER\tMEETINGS\tHOURS\tBACK_TO_BACK\tAFTER_HOURS")
+	for _, r := range organizerRows {
+		fmt.Fprintf(w, "%s\t%d\t%.1f\t%d\t%d\n", sanitizeTab(r.Key), r.Meetings, r.Hours, r.BackToBack, r.AfterHours)
+	}
+	return nil
+}
+
+func (c *CalendarReportLoadCmd) fetchMeetings(ctx context.Context, svc *calendar.Service, tr *TimeRange) ([]*loadMeeting, error) {
+	from, to := tr.FormatRFC3339()
+	var out []*loadMeeting
+	pageToken := ""
+	for {
+		call := svc.Events.List(c.CalendarID).
+			TimeMin(from).
+			TimeMax(to).
+			SingleEvents(true).
+			OrderBy("startTime").
+			MaxResults(2500).
+			Context(ctx)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range resp.Items {
+			if m := c.meetingFrom(e); m != nil {
+				out = append(out, m)
+			}
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].start.Before(out[j].start) })
+	return out, nil
+}
+
+// meetingFrom keeps timed, non-cancelled meetings the calendar owner has not
+// declined. Focus time, out of office, and working location blocks are not
+// meetings.
+func (c *CalendarReportLoadCmd) meetingFrom(e *calendar.Event) *loadMeeting {
+	if e == nil || e.Status == "cancelled" || isAllDayEvent(e) || e.Start == nil || e.End == nil {
+		return nil
+	}
+	if e.EventType != "" && e.EventType != "default" && e.EventType != "fromGmail" {
+		return nil
+	}
+	others := 0
+	for _, a := range e.Attendees {
+		if a == nil || a.Resource {
+			continue
+		}
+		if a.Self {
+			if a.ResponseStatus == "declined" {
+				return nil
+			}
+			continue
+		}
+		others++
+	}
+	if others == 0 && !c.IncludeSolo {
+		return nil
+	}
+	start, err := time.Parse(time.RFC3339, e.Start.DateTime)
+	if err != nil {
+		return nil
+	}
+	end, err := time.Parse(time.RFC3339, e.End.DateTime)
+	if err != nil || !end.After(start) {
+		return nil
+	}
+	organizer := "(unknown)"
+	if e.Organizer != nil {
+		organizer = strings.TrimSpace(e.Organizer.Email)
+		if organizer == "" {
+			organizer = strings.TrimSpace(e.Organizer.DisplayName)
+		}
+	}
+	return &loadMeeting{start: start, end: end, organizer: organizer}
+}
+
+// markMeetingLoad flags meetings that follow another within gap and meetings
+// that fall outside working hours. meetings must be sorted by start.
+func markMeetingLoad(meetings []*loadMeeting, gap time.Duration, workStart, workEnd time.Duration, loc *time.Location) {
+	var latestEnd time.Time
+	for i, m := range meetings {
+		// Overlapping meetings count too: there is no break between them.
+		if i > 0 && !m.start.After(latestEnd.Add(gap)) && sameDay(m.start.In(loc), latestEnd.In(loc)) {
+			m.backToBack = true
+		}
+		if m.end.After(latestEnd) {
+			latestEnd = m.end
+		}
+
+		start, end := m.start.In(loc), m.end.In(loc)
+		day := startOfDay(start)
+		switch {
+		case start.Weekday() == time.Saturday || start.Weekday() == time.Sunday:
+			m.afterHours = true
+		case start.Before(day.Add(workStart)) || end.After(day.Add(workEnd)):
+			m.afterHours = true
+		}
+	}
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// parseWorkHours parses "HH:MM-HH:MM" into offsets from midnight.
+func parseWorkHours(value string) (time.Duration, time.Duration, error) {
+	startRaw, endRaw, ok := strings.Cut(strings.TrimSpace(value), "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid working hours %q (expected HH:MM-HH:MM)", value)
+	}
+	parse := func(s string) (time.Duration, error) {
+		t, err := time.Parse("15:04", strings.TrimSpace(s))
+		if err != nil {
+			return 0, fmt.Errorf("invalid working hours %q (expected HH:MM-HH:MM)", value)
+		}
+		return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+	}
+	start, err := parse(startRaw)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := parse(endRaw)
+	if err != nil {
+		return 0, 0, err
+	}
+	if end <= start {
+		return 0, 0, fmt.Errorf("invalid working hours %q: end must be after start", value)
+	}
+	return start, end, nil
+}
+
+func loadBucket(m map[string]*meetingLoad, key string) *meetingLoad {
+	agg, ok := m[key]
+	if !ok {
+		agg = &meetingLoad{Key: key}
+		m[key] = agg
+	}
+	return agg
+}
+
+func (l *meetingLoad) add(m *loadMeeting) {
+	l.Meetings++
+	l.minutesSeen += m.end.Sub(m.start).Minutes()
+	if m.backToBack {
+		l.BackToBack++
+	}
+	if m.afterHours {
+		l.AfterHours++
+	}
+}
+
+func (l *meetingLoad) round() {
+	l.Hours = float64(int(l.minutesSeen/60*100+0.5)) / 100
+}
+
+func sortedLoads(m map[string]*meetingLoad, less func(a, b *meetingLoad) bool) []*meetingLoad {
+	out := make([]*meetingLoad, 0, len(m))
+	for _, agg := range m {
+		agg.round()
+		out = append(out, agg)
+	}
+	sort.Slice(out, func(i, j int) bool { return less(out[i], out[j]) })
+	return out
+}
+
+func writeMeetingLoadCSV(total *meetingLoad, weeks, organizers []*meetingLoad) error {
+	cw := csv.NewWriter(os.Stdout)
+	if err := cw.Write([]string{"section", "key", "meetings", "hours", "back_to_back", "after_hours"}); err != nil {
+		return err
+	}
+	write := func(section string, rows ...*meetingLoad) error {
+		for _, r := range rows {
+			record := []string{section, r.Key, fmt.Sprint(r.Meetings), fmt.Sprintf("%.2f", r.Hours), fmt.Sprint(r.BackToBack), fmt.Sprint(r.AfterHours)}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := write("total", total); err != nil {
+		return err
+	}
+	if err := write("week", weeks...); err != nil {
+		return err
+	}
+	if err := write("organizer", organizers...); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
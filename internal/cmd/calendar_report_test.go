@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/mock"
+)
+
+func TestCalendarReportLoad(t *testing.T) {
+	self := &calendar.EventAttendee{Email: "test@example.com", Self: true, ResponseStatus: "accepted"}
+	guest := &calendar.EventAttendee{Email: "alice@example.com", ResponseStatus: "accepted"}
+	meeting := func(id, start, end, organizer string, attendees ...*calendar.EventAttendee) *calendar.Event {
+		return &calendar.Event{
+			Id:        id,
+			Summary:   id,
+			Start:     &calendar.EventDateTime{DateTime: start},
+			End:       &calendar.EventDateTime{DateTime: end},
+			Organizer: &calendar.EventOrganizer{Email: organizer},
+			Attendees: attendees,
+		}
+	}
+	declined := &calendar.EventAttendee{Email: "test@example.com", Self: true, ResponseStatus: "declined"}
+	f := &mock.Fixtures{Calendar: mock.CalendarFixtures{
+		Calendars: []*calendar.CalendarListEntry{{Id: "test@example.com", Summary: "me", TimeZone: "UTC", AccessRole: "owner", Primary: true}},
+		Events: map[string][]*calendar.Event{"test@example.com": {
+			// Week of Mon 2026-03-02.
+			meeting("a", "2026-03-02T10:00:00Z", "2026-03-02T11:00:00Z", "boss@example.com", self, guest),
+			meeting("b", "2026-03-02T11:05:00Z", "2026-03-02T11:35:00Z", "boss@example.com", self, guest),  // back-to-back
+			meeting("c", "2026-03-02T19:00:00Z", "2026-03-02T20:00:00Z", "alice@example.com", self, guest), // after hours
+			meeting("solo", "2026-03-03T10:00:00Z", "2026-03-03T11:00:00Z", "test@example.com", self),
+			meeting("declined", "2026-03-03T12:00:00Z", "2026-03-03T13:00:00Z", "alice@example.com", declined, guest),
+			// Week of Mon 2026-03-09; Saturday counts as after hours.
+			meeting("d", "2026-03-14T10:00:00Z", "2026-03-14T12:00:00Z", "alice@example.com", self, guest),
+		}},
+	}}
+	s, err := mock.New(f, mock.ServiceCalendar)
+	if err != nil {
+		t.Fatalf("mock.New: %v", err)
+	}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	t.Setenv(googleapi.EnvAPIEndpoint, srv.URL)
+
+	run := func(args ...string) string {
+		t.Helper()
+		return captureStdout(t, func() {
+			if err := Execute(append([]string{"--account", "test@example.com"}, args...)); err != nil {
+				t.Fatalf("Execute %v: %v", args, err)
+			}
+		})
+	}
+
+	out := run("--json", "calendar", "report", "load", "--from", "2026-03-01", "--to", "2026-03-15")
+	var parsed struct {
+		Total      meetingLoad   `json:"total"`
+		Weeks      []meetingLoad `json:"weeks"`
+		Organizers []meetingLoad `json:"organizers"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json parse: %v\n%s", err, out)
+	}
+	if parsed.Total.Meetings != 4 || parsed.Total.Hours != 4.5 || parsed.Total.BackToBack != 1 || parsed.Total.AfterHours != 2 {
+		t.Fatalf("unexpected total: %#v", parsed.Total)
+	}
+	if len(parsed.Weeks) != 2 || parsed.Weeks[0].Key != "2026-03-02" || parsed.Weeks[0].Meetings != 3 {
+		t.Fatalf("unexpected weeks: %#v", parsed.Weeks)
+	}
+	if len(parsed.Organizers) != 2 || parsed.Organizers[0].Key != "alice@example.com" || parsed.Organizers[0].Hours != 3 {
+		t.Fatalf("unexpected organizers: %#v", parsed.Organizers)
+	}
+
+	out = run("calendar", "report", "load", "--from", "2026-03-01", "--to", "2026-03-15", "--include-solo", "--csv")
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if lines[0] != "section,key,meetings,hours,back_to_back,after_hours" || lines[1] != "total,total,5,5.50,1,2" {
+		t.Fatalf("unexpected csv: %q", out)
+	}
+}
@@ -12,10 +12,11 @@ import (
 )
 
 type CalendarRespondCmd struct {
-	CalendarID string `arg:"" name:"calendarId" help:"Calendar ID"`
-	EventID    string `arg:"" name:"eventId" help:"Event ID"`
-	Status     string `name:"status" help:"Response status (accepted, declined, tentative, needsAction)"`
-	Comment    string `name:"comment" help:"Optional comment/note to include with response"`
+	CalendarID  string `arg:"" name:"calendarId" complete:"calendars" help:"Calendar ID"`
+	EventID     string `arg:"" name:"eventId" help:"Event ID"`
+	Status      string `name:"status" help:"Response status (accepted, declined, tentative, needsAction)"`
+	Comment     string `name:"comment" help:"Optional comment/note to include with response"`
+	SendUpdates string `name:"send-updates" aliases:"notify" help:"Notify the organizer and guests: all, externalOnly, none (default: none)"`
 }
 
 func (c *CalendarRespondCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -49,6 +50,11 @@ func (c *CalendarRespondCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return fmt.Errorf("invalid status %q; must be one of: %s", status, strings.Join(validStatuses, ", "))
 	}
 
+	sendUpdates, err := validateSendUpdates(c.SendUpdates)
+	if err != nil {
+		return err
+	}
+
 	svc, err := newCalendarService(ctx, account)
 	if err != nil {
 		return err
@@ -84,7 +90,11 @@ func (c *CalendarRespondCmd) Run(ctx context.Context, flags *RootFlags) error {
 		event.Attendees[*selfAttendee].Comment = strings.TrimSpace(c.Comment)
 	}
 
-	updated, err := svc.Events.Patch(calendarID, eventID, event).Do()
+	call := svc.Events.Patch(calendarID, eventID, event)
+	if sendUpdates != "" {
+		call = call.SendUpdates(sendUpdates)
+	}
+	updated, err := call.Do()
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,418 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/events"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+const defaultCalendarRuleComment = "Declined automatically: {{.Reason}}."
+
+// CalendarRulesCmd evaluates the calendar_rules in config.json.
+type CalendarRulesCmd struct {
+	List    CalendarRulesListCmd    `cmd:"" name:"list" help:"Show the auto-decline rules that apply to the account"`
+	Process CalendarRulesProcessCmd `cmd:"" name:"process" help:"Decline unanswered invitations matching a rule (run from cron)"`
+}
+
+// calendarRule is a validated config.CalendarRule.
+type calendarRule struct {
+	config.CalendarRule
+	workStart, workEnd time.Duration
+	comment            *template.Template
+	sendUpdates        string
+}
+
+type calendarRuleDecline struct {
+	EventID    string   `json:"eventId"`
+	CalendarID string   `json:"calendarId"`
+	Summary    string   `json:"summary"`
+	Start      string   `json:"start"`
+	Organizer  string   `json:"organizer,omitempty"`
+	Rule       string   `json:"rule"`
+	Reasons    []string `json:"reasons"`
+	Comment    string   `json:"comment"`
+}
+
+// calendarRuleCommentData is what comment templates see.
+type calendarRuleCommentData struct {
+	Summary   string
+	Organizer string
+	Start     string
+	Rule      string
+	Reason    string
+}
+
+func loadCalendarRules(account string) ([]calendarRule, error) {
+	raw, err := config.CalendarRulesFor(account)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]calendarRule, 0, len(raw))
+	for i, r := range raw {
+		name := strings.TrimSpace(r.Name)
+		if name == "" {
+			name = fmt.Sprintf("rule %d", i+1)
+		}
+		r.Name = name
+		if strings.TrimSpace(r.Calendar) == "" {
+			r.Calendar = "primary"
+		}
+		rule := calendarRule{CalendarRule: r}
+		if !r.NoAgenda && r.OutsideWorkingHours == "" && !r.ConflictsWithFocusTime {
+			return nil, fmt.Errorf("calendar rule %q: set at least one of no_agenda, outside_working_hours, conflicts_with_focus_time", name)
+		}
+		if r.OutsideWorkingHours != "" {
+			if rule.workStart, rule.workEnd, err = parseWorkHours(r.OutsideWorkingHours); err != nil {
+				return nil, fmt.Errorf("calendar rule %q: %w", name, err)
+			}
+		}
+		text := r.Comment
+		if strings.TrimSpace(text) == "" {
+			text = defaultCalendarRuleComment
+		}
+		if rule.comment, err = template.New(name).Option("missingkey=error").Parse(text); err != nil {
+			return nil, fmt.Errorf("calendar rule %q: comment: %w", name, err)
+		}
+		if rule.sendUpdates, err = validateSendUpdates(r.SendUpdates); err != nil {
+			return nil, fmt.Errorf("calendar rule %q: %w", name, err)
+		}
+		out = append(out, rule)
+	}
+	return out, nil
+}
+
+type CalendarRulesListCmd struct{}
+
+func (c *CalendarRulesListCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	rules, err := loadCalendarRules(account)
+	if err != nil {
+		return err
+	}
+	if outfmt.IsJSON(ctx) {
+		raw := make([]config.CalendarRule, 0, len(rules))
+		for _, r := range rules {
+			raw = append(raw, r.CalendarRule)
+		}
+		return outfmt.WriteJSON(os.Stdout, map[string]any{"rules": raw})
+	}
+	if len(rules) == 0 {
+		u.Err().Println("No calendar rules (add calendar_rules to config.json)")
+		return nil
+	}
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "NAME\tCALENDAR\tCONDITIONS")
+	for _, r := range rules {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", r.Name, r.Calendar, strings.Join(r.conditions(), ", "))
+	}
+	return nil
+}
+
+func (r calendarRule) conditions() []string {
+	var out []string
+	if r.NoAgenda {
+		out = append(out, "no agenda")
+	}
+	if r.OutsideWorkingHours != "" {
+		out = append(out, "outside "+r.OutsideWorkingHours)
+	}
+	if r.ConflictsWithFocusTime {
+		out = append(out, "conflicts with focus time")
+	}
+	return out
+}
+
+type CalendarRulesProcessCmd struct {
+	Days   int  `name:"days" help:"Look at invitations starting within this many days" default:"14"`
+	DryRun bool `name:"dry-run" help:"Report what would be declined without responding"`
+}
+
+func (c *CalendarRulesProcessCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	if c.Days <= 0 {
+		return usage("--days must be positive")
+	}
+	rules, err := loadCalendarRules(account)
+	if err != nil {
+		return err
+	}
+
+	declines := []calendarRuleDecline{}
+	if len(rules) > 0 {
+		svc, svcErr := newCalendarService(ctx, account)
+		if svcErr != nil {
+			return svcErr
+		}
+		now := time.Now()
+		byCalendar := map[string][]calendarRule{}
+		var calendarIDs []string
+		for _, r := range rules {
+			if _, ok := byCalendar[r.Calendar]; !ok {
+				calendarIDs = append(calendarIDs, r.Calendar)
+			}
+			byCalendar[r.Calendar] = append(byCalendar[r.Calendar], r)
+		}
+		for _, calendarID := range calendarIDs {
+			got, procErr := c.processCalendar(ctx, u, svc, account, calendarID, byCalendar[calendarID], now)
+			declines = append(declines, got...)
+			if procErr != nil && interrupted(ctx) {
+				// Keep the declines already sent so the output matches what changed.
+				break
+			}
+			if procErr != nil {
+				return procErr
+			}
+		}
+	}
+
+	if !c.DryRun {
+		if emitErr := events.Emit(events.TypeJobFinished, account, map[string]any{
+			"job":      "calendar.rules.process",
+			"declined": len(declines),
+		}); emitErr != nil {
+			u.Err().Printf("event log: %v", emitErr)
+		}
+	}
+
+	if outfmt.IsJSON(ctx) {
+		out := map[string]any{
+			"declined": declines,
+			"dryRun":   c.DryRun,
+		}
+		if interrupted(ctx) {
+			out["interrupted"] = true
+		}
+		return outfmt.WriteJSON(os.Stdout, out)
+	}
+	if len(declines) == 0 {
+		u.Err().Println("No invitations matched")
+		return nil
+	}
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "EVENT\tSTART\tSUMMARY\tRULE\tREASONS")
+	for _, d := range declines {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", d.EventID, d.Start, sanitizeTab(d.Summary), d.Rule, strings.Join(d.Reasons, ", "))
+	}
+	if c.DryRun {
+		u.Err().Printf("Dry run: %d invitation(s) would be declined", len(declines))
+	}
+	return nil
+}
+
+func (c *CalendarRulesProcessCmd) processCalendar(ctx context.Context, u *ui.UI, svc *calendar.Service, account, calendarID string, rules []calendarRule, now time.Time) ([]calendarRuleDecline, error) {
+	_, loc, err := getCalendarLocation(ctx, svc, calendarID)
+	if err != nil {
+		return nil, err
+	}
+	if loc == nil {
+		loc = time.Local
+	}
+
+	var items []*calendar.Event
+	pageToken := ""
+	for {
+		call := svc.Events.List(calendarID).
+			TimeMin(now.Format(time.RFC3339)).
+			TimeMax(now.AddDate(0, 0, c.Days).Format(time.RFC3339)).
+			SingleEvents(true).
+			OrderBy("startTime").
+			MaxResults(2500).
+			Context(ctx)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, listErr := call.Do()
+		if listErr != nil {
+			return nil, listErr
+		}
+		items = append(items, resp.Items...)
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+
+	var focus []timeSpan
+	for _, e := range items {
+		if e != nil && e.EventType == eventTypeFocusTime && e.Status != "cancelled" {
+			if span, ok := eventSpan(e); ok {
+				focus = append(focus, span)
+			}
+		}
+	}
+
+	var out []calendarRuleDecline
+	for _, e := range items {
+		self := pendingInvitation(e)
+		if self == nil {
+			continue
+		}
+		for _, r := range rules {
+			reasons := r.match(e, focus, loc)
+			if len(reasons) == 0 {
+				continue
+			}
+			d, declineErr := c.decline(ctx, svc, account, calendarID, e, self, r, reasons, loc)
+			if declineErr != nil {
+				return out, fmt.Errorf("decline %s: %w", e.Id, declineErr)
+			}
+			out = append(out, d)
+			break
+		}
+	}
+	return out, nil
+}
+
+// pendingInvitation returns the calendar owner's attendee entry for
+// invitations from someone else that have not been answered yet.
+func pendingInvitation(e *calendar.Event) *calendar.EventAttendee {
+	if e == nil || e.Status == "cancelled" || isAllDayEvent(e) {
+		return nil
+	}
+	if e.EventType != "" && e.EventType != "default" {
+		return nil
+	}
+	for _, a := range e.Attendees {
+		if a != nil && a.Self {
+			if a.Organizer || a.ResponseStatus != "needsAction" {
+				return nil
+			}
+			return a
+		}
+	}
+	return nil
+}
+
+// match returns why the event matches r, or nil unless every condition the
+// rule sets holds.
+func (r calendarRule) match(e *calendar.Event, focus []timeSpan, loc *time.Location) []string {
+	span, ok := eventSpan(e)
+	if !ok {
+		return nil
+	}
+	var reasons []string
+	if r.NoAgenda {
+		minChars := r.MinAgendaChars
+		if minChars <= 0 {
+			minChars = 1
+		}
+		if len([]rune(strings.TrimSpace(e.Description))) >= minChars {
+			return nil
+		}
+		reasons = append(reasons, "no agenda")
+	}
+	if r.OutsideWorkingHours != "" {
+		start, end := span.start.In(loc), span.end.In(loc)
+		day := startOfDay(start)
+		weekend := start.Weekday() == time.Saturday || start.Weekday() == time.Sunday
+		if !weekend && !start.Before(day.Add(r.workStart)) && !end.After(day.Add(r.workEnd)) {
+			return nil
+		}
+		reasons = append(reasons, "outside working hours")
+	}
+	if r.ConflictsWithFocusTime {
+		conflict := false
+		for _, f := range focus {
+			if span.start.Before(f.end) && span.end.After(f.start) {
+				conflict = true
+				break
+			}
+		}
+		if !conflict {
+			return nil
+		}
+		reasons = append(reasons, "conflicts with focus time")
+	}
+	return reasons
+}
+
+func (c *CalendarRulesProcessCmd) decline(ctx context.Context, svc *calendar.Service, account, calendarID string, e *calendar.Event, self *calendar.EventAttendee, r calendarRule, reasons []string, loc *time.Location) (calendarRuleDecline, error) {
+	span, _ := eventSpan(e)
+	d := calendarRuleDecline{
+		EventID:    e.Id,
+		CalendarID: calendarID,
+		Summary:    orEmpty(e.Summary, "(no title)"),
+		Start:      span.start.In(loc).Format("2006-01-02 15:04"),
+		Rule:       r.Name,
+		Reasons:    reasons,
+	}
+	if e.Organizer != nil {
+		d.Organizer = e.Organizer.Email
+	}
+	var comment strings.Builder
+	if err := r.comment.Execute(&comment, calendarRuleCommentData{
+		Summary:   d.Summary,
+		Organizer: d.Organizer,
+		Start:     d.Start,
+		Rule:      r.Name,
+		Reason:    strings.Join(reasons, ", "),
+	}); err != nil {
+		return d, fmt.Errorf("calendar rule %q: comment: %w", r.Name, err)
+	}
+	d.Comment = strings.TrimSpace(comment.String())
+	if c.DryRun {
+		return d, nil
+	}
+
+	self.ResponseStatus = "declined"
+	self.Comment = d.Comment
+	call := svc.Events.Patch(calendarID, e.Id, &calendar.Event{Attendees: e.Attendees}).Context(ctx)
+	if r.sendUpdates != "" {
+		call = call.SendUpdates(r.sendUpdates)
+	}
+	if _, err := call.Do(); err != nil {
+		return d, err
+	}
+	if emitErr := events.Emit(events.TypeCalendarDeclined, account, map[string]any{
+		"calendarId": calendarID,
+		"eventId":    e.Id,
+		"summary":    d.Summary,
+		"organizer":  d.Organizer,
+		"start":      span.start.Format(time.RFC3339),
+		"rule":       r.Name,
+		"reasons":    reasons,
+		"comment":    d.Comment,
+	}); emitErr != nil {
+		ui.FromContext(ctx).Err().Printf("event log: %v", emitErr)
+	}
+	return d, nil
+}
+
+type timeSpan struct {
+	start, end time.Time
+}
+
+func eventSpan(e *calendar.Event) (timeSpan, bool) {
+	if e == nil || e.Start == nil || e.End == nil {
+		return timeSpan{}, false
+	}
+	start, err := time.Parse(time.RFC3339, e.Start.DateTime)
+	if err != nil {
+		return timeSpan{}, false
+	}
+	end, err := time.Parse(time.RFC3339, e.End.DateTime)
+	if err != nil {
+		return timeSpan{}, false
+	}
+	return timeSpan{start: start, end: end}, true
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/events"
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/mock"
+)
+
+func TestCalendarRulesProcess(t *testing.T) {
+	orig, err := config.ReadConfig()
+	if err != nil {
+		t.Fatalf("ReadConfig: %v", err)
+	}
+	t.Cleanup(func() { _ = config.WriteConfig(orig) })
+	cfg := orig
+	cfg.CalendarRules = []config.CalendarRule{
+		{Name: "agenda", NoAgenda: true, Comment: "Hi {{.Organizer}}, please add an agenda to {{.Summary}}."},
+		{Name: "focus", ConflictsWithFocusTime: true},
+		{Name: "late", OutsideWorkingHours: "09:00-18:00", Account: "other@example.com"},
+	}
+	if err := config.WriteConfig(cfg); err != nil {
+		t.Fatalf("WriteConfig: %v", err)
+	}
+	logPath := filepath.Join(t.TempDir(), "events.ndjson")
+	t.Setenv(events.EnvFile, logPath)
+
+	day := time.Now().UTC().AddDate(0, 0, 2).Truncate(24 * time.Hour)
+	at := func(h int) string { return day.Add(time.Duration(h) * time.Hour).Format(time.RFC3339) }
+	invite := func(id string, from, to int, description string) *calendar.Event {
+		return &calendar.Event{
+			Id:          id,
+			Summary:     id,
+			Description: description,
+			Start:       &calendar.EventDateTime{DateTime: at(from)},
+			End:         &calendar.EventDateTime{DateTime: at(to)},
+			Organizer:   &calendar.EventOrganizer{Email: "alice@example.com"},
+			Attendees: []*calendar.EventAttendee{
+				{Email: "alice@example.com", Organizer: true, ResponseStatus: "accepted"},
+				{Email: "test@example.com", Self: true, ResponseStatus: "needsAction"},
+			},
+		}
+	}
+	answered := invite("answered", 10, 11, "")
+	answered.Attendees[1].ResponseStatus = "accepted"
+	f := &mock.Fixtures{Calendar: mock.CalendarFixtures{
+		Calendars: []*calendar.CalendarListEntry{{Id: "test@example.com", Summary: "me", TimeZone: "UTC", AccessRole: "owner", Primary: true}},
+		Events: map[string][]*calendar.Event{"test@example.com": {
+			invite("sync", 10, 11, ""),
+			invite("review", 14, 15, "Agenda: go through the doc"),
+			invite("late", 20, 21, "Agenda: postmortem"),
+			answered,
+			{
+				Id: "focus", Summary: "Focus", EventType: eventTypeFocusTime,
+				Start: &calendar.EventDateTime{DateTime: at(13)}, End: &calendar.EventDateTime{DateTime: at(16)},
+			},
+		}},
+	}}
+	s, err := mock.New(f, mock.ServiceCalendar)
+	if err != nil {
+		t.Fatalf("mock.New: %v", err)
+	}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	t.Setenv(googleapi.EnvAPIEndpoint, srv.URL)
+
+	run := func(args ...string) string {
+		t.Helper()
+		return captureStdout(t, func() {
+			_ = captureStderr(t, func() {
+				if err := Execute(append([]string{"--json", "--account", "test@example.com"}, args...)); err != nil {
+					t.Fatalf("Execute %v: %v", args, err)
+				}
+			})
+		})
+	}
+	var parsed struct {
+		Declined []calendarRuleDecline `json:"declined"`
+	}
+
+	out := run("calendar", "rules", "process", "--dry-run")
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json parse: %v\n%s", err, out)
+	}
+	if len(parsed.Declined) != 2 || parsed.Declined[0].EventID != "sync" || parsed.Declined[1].Rule != "focus" {
+		t.Fatalf("unexpected dry run: %#v", parsed.Declined)
+	}
+	if got := parsed.Declined[0].Comment; got != "Hi alice@example.com, please add an agenda to sync." {
+		t.Fatalf("unexpected comment: %q", got)
+	}
+
+	run("calendar", "rules", "process")
+	out = run("calendar", "event", "primary", "sync")
+	if !strings.Contains(out, `"responseStatus": "declined"`) {
+		t.Fatalf("sync not declined: %s", out)
+	}
+	out = run("calendar", "rules", "process")
+	parsed.Declined = nil
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil || len(parsed.Declined) != 0 {
+		t.Fatalf("expected nothing left to decline: %s", out)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read event log: %v", err)
+	}
+	if n := strings.Count(string(data), events.TypeCalendarDeclined); n != 2 {
+		t.Fatalf("expected 2 decline events, got %d:\n%s", n, data)
+	}
+}
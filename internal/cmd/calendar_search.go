@@ -15,7 +15,7 @@ import (
 type CalendarSearchCmd struct {
 	Query string `arg:"" name:"query" help:"Search query"`
 	TimeRangeFlags
-	CalendarID string `name:"calendar" help:"Calendar ID" default:"primary"`
+	CalendarID string `name:"calendar" complete:"calendars" help:"Calendar ID" default:"primary"`
 	Max        int64  `name:"max" aliases:"limit" help:"Max results" default:"25"`
 }
 
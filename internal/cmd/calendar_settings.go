@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+type CalendarSettingsCmd struct {
+	SettingsDiffFlags `embed:""`
+}
+
+func (c *CalendarSettingsCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	if c.Apply {
+		// The Calendar API exposes user settings read-only.
+		return usage("calendar settings cannot be changed through the API; use --diff-with without --apply")
+	}
+
+	svc, err := newCalendarService(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	if c.active() {
+		return c.run(ctx, flags, "calendar settings", "settings", &calendarSettingsState{svc: svc})
+	}
+
+	settings, err := listCalendarSettings(ctx, svc)
+	if err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{"settings": settings})
+	}
+
+	if len(settings) == 0 {
+		u.Err().Println("No settings")
+		return nil
+	}
+	ids := make([]string, 0, len(settings))
+	for id := range settings {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	w, flush := tableWriter(ctx)
+	fmt.Fprintln(w, "SETTING\tVALUE")
+	for _, id := range ids {
+		fmt.Fprintf(w, "%s\t%s\n", id, sanitizeTab(settings[id]))
+	}
+	flush()
+	return nil
+}
+
+// listCalendarSettings returns the user's settings by ID.
+func listCalendarSettings(ctx context.Context, svc *calendar.Service) (map[string]string, error) {
+	settings := map[string]string{}
+	err := svc.Settings.List().Pages(ctx, func(resp *calendar.Settings) error {
+		for _, s := range resp.Items {
+			settings[s.Id] = s.Value
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// calendarSettingsState diffs calendar settings; they are read-only, so it
+// only reports drift.
+type calendarSettingsState struct {
+	svc *calendar.Service
+}
+
+func (s *calendarSettingsState) plan(ctx context.Context, desired json.RawMessage) ([]settingChange, error) {
+	var want map[string]any
+	if err := json.Unmarshal(desired, &want); err != nil {
+		return nil, usagef("calendar settings must be a JSON object of setting IDs to values: %v", err)
+	}
+	settings, err := listCalendarSettings(ctx, s.svc)
+	if err != nil {
+		return nil, err
+	}
+	live := make(map[string]any, len(settings))
+	for id, v := range settings {
+		live[id] = v
+	}
+	return diffSettingValues("", live, want, nil, nil), nil
+}
+
+func (s *calendarSettingsState) apply(context.Context) error {
+	return usage("calendar settings are read-only")
+}
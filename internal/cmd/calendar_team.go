@@ -16,7 +16,7 @@ import (
 )
 
 type CalendarTeamCmd struct {
-	GroupEmail string `arg:"" help:"Google Group email (e.g., engineering@company.com)"`
+	GroupEmail string `arg:"" help:"Google Group email (e.g., engineering@company.com), or a contact group name for consumer accounts"`
 	FreeBusy   bool   `name:"freebusy" help:"Show only busy/free blocks (faster, single API call)"`
 	Query      string `name:"query" short:"q" help:"Filter events by title (case-insensitive)"`
 	Max        int64  `name:"max" help:"Max events per calendar" default:"100"`
@@ -62,15 +62,9 @@ func (c *CalendarTeamCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
-	// Get group members via Cloud Identity API
-	cloudSvc, err := newCloudIdentityService(ctx, account)
-	if err != nil {
-		return wrapCloudIdentityError(err, account)
-	}
-
-	memberEmails, err := collectGroupMemberEmails(ctx, cloudSvc, groupEmail)
+	memberEmails, err := teamMemberEmails(ctx, u, account, groupEmail)
 	if err != nil {
-		return fmt.Errorf("failed to list group members: %w", err)
+		return err
 	}
 
 	if len(memberEmails) == 0 {
@@ -84,6 +78,24 @@ func (c *CalendarTeamCmd) Run(ctx context.Context, flags *RootFlags) error {
 	return c.runEvents(ctx, calSvc, u, memberEmails, tr)
 }
 
+// teamMemberEmails expands a Workspace group via Cloud Identity, or a contact
+// group of that name for consumer accounts.
+func teamMemberEmails(ctx context.Context, u *ui.UI, account, group string) ([]string, error) {
+	if groupsViaContacts(account) {
+		u.Err().Printf("Consumer account: using contact group %q instead of a Workspace group", group)
+		return expandContactGroups(ctx, account, []string{group})
+	}
+	cloudSvc, err := newCloudIdentityService(ctx, account)
+	if err != nil {
+		return nil, wrapCloudIdentityError(err, account)
+	}
+	memberEmails, err := collectGroupMemberEmails(ctx, cloudSvc, group)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list group members: %w", err)
+	}
+	return memberEmails, nil
+}
+
 func (c *CalendarTeamCmd) runFreeBusy(ctx context.Context, svc *calendar.Service, emails []string, tr *TimeRange) error {
 	// Build FreeBusy request
 	items := make([]*calendar.FreeBusyRequestItem, len(emails))
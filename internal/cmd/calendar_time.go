@@ -10,7 +10,7 @@ import (
 )
 
 type CalendarTimeCmd struct {
-	CalendarID string `name:"calendar" help:"Calendar ID to get timezone from" default:"primary"`
+	CalendarID string `name:"calendar" complete:"calendars" help:"Calendar ID to get timezone from" default:"primary"`
 	Timezone   string `name:"timezone" help:"Override timezone (e.g., America/New_York, UTC)"`
 }
 
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/steipete/gogcli/internal/events"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+type CalendarWatchCmd struct {
+	Calendar string        `name:"calendar" complete:"calendars" help:"Calendar ID" default:"primary"`
+	Lead     time.Duration `name:"lead" help:"Announce events this long before they start" default:"5m"`
+	Interval time.Duration `name:"interval" help:"Time between checks" default:"1m"`
+	Once     bool          `name:"once" help:"Check once and exit (for cron)"`
+}
+
+// calendarStartingEvent is one calendar.event.starting announcement.
+type calendarStartingEvent struct {
+	CalendarID string `json:"calendarId"`
+	EventID    string `json:"eventId"`
+	Summary    string `json:"summary,omitempty"`
+	Start      string `json:"start"`
+	MeetURL    string `json:"meetUrl,omitempty"`
+}
+
+func (c *CalendarWatchCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	calendarID := strings.TrimSpace(c.Calendar)
+	if calendarID == "" {
+		return usage("empty --calendar")
+	}
+	if c.Lead <= 0 {
+		return usage("--lead must be > 0")
+	}
+	if !c.Once && c.Interval < 10*time.Second {
+		return usage("--interval must be at least 10s")
+	}
+
+	svc, err := newCalendarService(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	// announced remembers event instances already sent, so each one is
+	// announced once even though it stays inside the lead window.
+	announced := map[string]time.Time{}
+	for {
+		checkErr := c.check(ctx, u, svc, account, calendarID, announced, time.Now())
+		if interrupted(ctx) {
+			return nil
+		}
+		if checkErr != nil {
+			if c.Once {
+				return checkErr
+			}
+			// A daemon keeps going through transient API errors.
+			u.Err().Printf("calendar watch failed: %v", checkErr)
+		}
+		if c.Once {
+			return nil
+		}
+		if err := sendPacerSleep(ctx, c.Interval); err != nil {
+			return nil //nolint:nilerr // stopped by the user
+		}
+	}
+}
+
+// check announces timed events starting within the lead window. All-day
+// events have no meaningful start and are skipped.
+func (c *CalendarWatchCmd) check(ctx context.Context, u *ui.UI, svc *calendar.Service, account, calendarID string, announced map[string]time.Time, now time.Time) error {
+	for key, start := range announced {
+		if start.Before(now) {
+			delete(announced, key)
+		}
+	}
+
+	resp, err := svc.Events.List(calendarID).
+		TimeMin(now.Format(time.RFC3339)).
+		TimeMax(now.Add(c.Lead).Format(time.RFC3339)).
+		SingleEvents(true).
+		OrderBy("startTime").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return err
+	}
+	for _, e := range resp.Items {
+		if e == nil || e.Status == "cancelled" || e.Start == nil || e.Start.DateTime == "" {
+			continue
+		}
+		start := parseEventStart(e, time.Local)
+		if start.IsZero() || start.Before(now) || start.After(now.Add(c.Lead)) {
+			continue
+		}
+		key := e.Id + "|" + e.Start.DateTime
+		if _, ok := announced[key]; ok {
+			continue
+		}
+		announced[key] = start
+
+		ev := calendarStartingEvent{
+			CalendarID: calendarID,
+			EventID:    e.Id,
+			Summary:    e.Summary,
+			Start:      e.Start.DateTime,
+			MeetURL:    e.HangoutLink,
+		}
+		if emitErr := events.Emit(events.TypeCalendarEventStarting, account, map[string]any{
+			"calendarId": ev.CalendarID,
+			"eventId":    ev.EventID,
+			"summary":    ev.Summary,
+			"start":      ev.Start,
+			"meetUrl":    ev.MeetURL,
+		}); emitErr != nil {
+			u.Err().Printf("event log: %v", emitErr)
+		}
+		if outfmt.IsJSON(ctx) {
+			if err := json.NewEncoder(os.Stdout).Encode(ev); err != nil {
+				return err
+			}
+			continue
+		}
+		u.Out().Printf("%s\t%s\t%s", ev.Start, ev.EventID, ev.Summary)
+	}
+	return nil
+}
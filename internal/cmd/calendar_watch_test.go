@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/option"
+
+	"github.com/steipete/gogcli/internal/events"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+func TestCalendarWatch_AnnouncesStartingEventsOnce(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "events.ndjson")
+	t.Setenv(events.EnvFile, logPath)
+
+	now := time.Date(2026, 3, 2, 9, 58, 0, 0, time.UTC)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"items": []map[string]any{
+			{"id": "e1", "summary": "Standup", "start": map[string]any{"dateTime": "2026-03-02T10:00:00Z"}},
+			{"id": "e2", "summary": "Offsite", "start": map[string]any{"date": "2026-03-02"}},
+			{"id": "e3", "summary": "Later", "start": map[string]any{"dateTime": "2026-03-02T12:00:00Z"}},
+		}})
+	}))
+	defer srv.Close()
+	svc, err := calendar.NewService(context.Background(), option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()), option.WithEndpoint(srv.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+
+	cmd := &CalendarWatchCmd{Lead: 5 * time.Minute}
+	announced := map[string]time.Time{}
+	out := captureStdout(t, func() {
+		u, uiErr := ui.New(ui.Options{Stdout: os.Stdout, Stderr: os.Stderr, Color: "never"})
+		if uiErr != nil {
+			t.Fatalf("ui.New: %v", uiErr)
+		}
+		for i := 0; i < 2; i++ {
+			if err := cmd.check(context.Background(), u, svc, "a@b.com", "primary", announced, now.Add(time.Duration(i)*time.Minute)); err != nil {
+				t.Fatalf("check: %v", err)
+			}
+		}
+	})
+	if strings.Count(out, "Standup") != 1 || strings.Contains(out, "Offsite") || strings.Contains(out, "Later") {
+		t.Fatalf("unexpected output: %q", out)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read event log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 || !strings.Contains(lines[0], `"type":"calendar.event.starting"`) || !strings.Contains(lines[0], `"eventId":"e1"`) {
+		t.Fatalf("unexpected events: %q", data)
+	}
+}
@@ -13,7 +13,7 @@ import (
 )
 
 type CalendarWorkingLocationCmd struct {
-	CalendarID  string `arg:"" name:"calendarId" help:"Calendar ID (default: primary)" default:"primary"`
+	CalendarID  string `arg:"" name:"calendarId" complete:"calendars" help:"Calendar ID (default: primary)" default:"primary"`
 	From        string `name:"from" required:"" help:"Start date (YYYY-MM-DD)"`
 	To          string `name:"to" required:"" help:"End date (YYYY-MM-DD)"`
 	Type        string `name:"type" required:"" help:"Location type: home, office, custom"`
@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/googleauth"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+const (
+	accountKindConsumer  = "consumer"
+	accountKindWorkspace = "workspace"
+	accountKindUnknown   = "unknown"
+
+	capabilityAvailable     = "available"
+	capabilityNotAuthorized = "not-authorized"
+	capabilityUnsupported   = "unsupported"
+)
+
+// accountKindConsumerTTL is how long a consumer result is trusted. Cloud
+// Identity answers a consumer account with a plain bad request, which a
+// transient error can look like, so it is probed again later; a Workspace
+// result comes from a successful call and is kept.
+const accountKindConsumerTTL = 7 * 24 * time.Hour
+
+// workspaceOnlyServices need a Google Workspace (or Cloud Identity) account.
+var workspaceOnlyServices = []googleauth.Service{
+	googleauth.ServiceChat,
+	googleauth.ServiceGroups,
+	googleauth.ServiceKeep,
+	googleauth.ServiceAdmin,
+}
+
+type CapabilitiesCmd struct {
+	NoProbe bool `name:"no-probe" help:"Do not call Cloud Identity; use the domain and earlier results to tell consumer from Workspace accounts"`
+}
+
+type accountCapabilities struct {
+	Account  string              `json:"account"`
+	Kind     string              `json:"kind"`
+	Services []serviceCapability `json:"services"`
+}
+
+type serviceCapability struct {
+	Service string `json:"service"`
+	Status  string `json:"status"`
+	Note    string `json:"note,omitempty"`
+}
+
+func (c *CapabilitiesCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	store, err := openSecretsStore()
+	if err != nil {
+		return err
+	}
+	tokens, err := store.ListTokens()
+	if err != nil {
+		return err
+	}
+	// One entry per account; tokens from several OAuth clients add up.
+	authorized := map[string][]string{}
+	for _, t := range tokens {
+		if email := normalizeEmail(t.Email); email != "" {
+			authorized[email] = append(authorized[email], t.Services...)
+		}
+	}
+	var accounts []string
+	if strings.TrimSpace(flags.Account) != "" {
+		account, accountErr := requireAccount(flags)
+		if accountErr != nil {
+			return accountErr
+		}
+		accounts = []string{normalizeEmail(account)}
+	} else {
+		for email := range authorized {
+			accounts = append(accounts, email)
+		}
+		sort.Strings(accounts)
+	}
+	if len(accounts) == 0 {
+		return usage("no authenticated accounts (run gog auth add <email>)")
+	}
+
+	out := make([]accountCapabilities, 0, len(accounts))
+	for _, account := range accounts {
+		services := authorized[account]
+		kind := accountKind(account)
+		if kind == accountKindUnknown && !c.NoProbe && slices.Contains(services, string(googleauth.ServiceGroups)) {
+			probed, probeErr := probeAccountKind(ctx, account)
+			if probeErr != nil {
+				u.Err().Printf("%s: Cloud Identity probe failed: %v", account, probeErr)
+			}
+			kind = probed
+		}
+		out = append(out, accountCapabilities{
+			Account:  account,
+			Kind:     kind,
+			Services: serviceCapabilities(account, kind, services),
+		})
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{"accounts": out})
+	}
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "ACCOUNT\tKIND\tSERVICE\tSTATUS\tNOTE")
+	for _, a := range out {
+		for _, s := range a.Services {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", a.Account, a.Kind, s.Service, s.Status, s.Note)
+		}
+	}
+	return nil
+}
+
+func serviceCapabilities(account, kind string, authorized []string) []serviceCapability {
+	out := make([]serviceCapability, 0, len(googleauth.AllServices()))
+	for _, svc := range googleauth.AllServices() {
+		capability := serviceCapability{Service: string(svc), Status: capabilityAvailable}
+		switch {
+		case kind == accountKindConsumer && slices.Contains(workspaceOnlyServices, svc):
+			capability.Status = capabilityUnsupported
+			capability.Note = "needs Google Workspace"
+			if svc == googleauth.ServiceGroups {
+				capability.Note += "; contact groups are used instead"
+			}
+		case !slices.Contains(authorized, string(svc)):
+			capability.Status = capabilityNotAuthorized
+			capability.Note = fmt.Sprintf("gog auth add %s --services %s", account, svc)
+		}
+		out = append(out, capability)
+	}
+	return out
+}
+
+// accountKind tells consumer from Workspace accounts without an API call:
+// gmail.com addresses are consumer accounts, other domains are known once
+// probed (consumer results until accountKindConsumerTTL runs out).
+func accountKind(account string) string {
+	if isConsumerAccount(account) {
+		return accountKindConsumer
+	}
+	state, err := loadAccountKinds()
+	if err != nil {
+		return accountKindUnknown
+	}
+	entry, ok := state.Accounts[statusKey(account)]
+	if !ok || entry.Kind == "" {
+		return accountKindUnknown
+	}
+	if entry.Kind == accountKindConsumer && time.Since(entry.CheckedAt) > accountKindConsumerTTL {
+		return accountKindUnknown
+	}
+	return entry.Kind
+}
+
+// probeAccountKind asks Cloud Identity for one of the account's groups and
+// remembers the answer: consumer accounts get a bad request back.
+func probeAccountKind(ctx context.Context, account string) (string, error) {
+	svc, err := newCloudIdentityService(ctx, account)
+	if err != nil {
+		return accountKindUnknown, err
+	}
+	_, err = svc.Groups.Memberships.SearchTransitiveGroups("groups/-").
+		Query(cloudIdentityMemberQuery(account)).
+		PageSize(1).
+		Context(ctx).
+		Do()
+	switch {
+	case err == nil:
+		return accountKindWorkspace, rememberAccountKind(account, accountKindWorkspace)
+	case cloudIdentityUnsupported(err):
+		return accountKindConsumer, rememberAccountKind(account, accountKindConsumer)
+	default:
+		return accountKindUnknown, err
+	}
+}
+
+// accountKindState caches detected account kinds across runs.
+type accountKindState struct {
+	Accounts map[string]accountKindEntry `json:"accounts"`
+}
+
+type accountKindEntry struct {
+	Kind      string    `json:"kind"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+func rememberAccountKind(account, kind string) error {
+	state, err := loadAccountKinds()
+	if err != nil {
+		return err
+	}
+	state.Accounts[statusKey(account)] = accountKindEntry{Kind: kind, CheckedAt: time.Now().UTC()}
+	return saveAccountKinds(state)
+}
+
+func saveAccountKinds(state *accountKindState) error {
+	path, err := config.CapabilitiesPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	payload, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(payload, '\n'), 0o600)
+}
+
+func loadAccountKinds() (*accountKindState, error) {
+	path, err := config.CapabilitiesPath()
+	if err != nil {
+		return nil, err
+	}
+	state := &accountKindState{Accounts: map[string]accountKindEntry{}}
+	data, err := os.ReadFile(path) //nolint:gosec // path under config dir
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return state, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	if state.Accounts == nil {
+		state.Accounts = map[string]accountKindEntry{}
+	}
+	return state, nil
+}
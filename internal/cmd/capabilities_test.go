@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/cloudidentity/v1"
+	"google.golang.org/api/option"
+	"google.golang.org/api/people/v1"
+
+	"github.com/steipete/gogcli/internal/secrets"
+)
+
+func stubCloudIdentity(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	svc, err := cloudidentity.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	orig := newCloudIdentityService
+	t.Cleanup(func() { newCloudIdentityService = orig })
+	newCloudIdentityService = func(context.Context, string) (*cloudidentity.Service, error) { return svc, nil }
+}
+
+func TestCapabilities(t *testing.T) {
+	origStore := openSecretsStore
+	t.Cleanup(func() { openSecretsStore = origStore })
+	openSecretsStore = func() (secrets.Store, error) {
+		return &fakeSecretsStore{tokens: []secrets.Token{
+			{Email: "me@gmail.com", Services: []string{"gmail", "calendar", "groups"}},
+			{Email: "me@work.example", Services: []string{"gmail", "groups"}},
+			{Email: "me@family.example", Services: []string{"gmail", "groups"}},
+		}}, nil
+	}
+	probes := 0
+	stubCloudIdentity(t, func(w http.ResponseWriter, r *http.Request) {
+		probes++
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Query().Get("query"), "family.example") {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"code":400,"message":"Request contains an invalid argument.","status":"INVALID_ARGUMENT","errors":[{"reason":"badRequest"}]}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"memberships":[]}`))
+	})
+
+	run := func() map[string]accountCapabilities {
+		var resp struct {
+			Accounts []accountCapabilities `json:"accounts"`
+		}
+		out := captureStdout(t, func() {
+			if err := Execute([]string{"--json", "capabilities"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+		if err := json.Unmarshal([]byte(out), &resp); err != nil {
+			t.Fatalf("json: %v\n%s", err, out)
+		}
+		byAccount := map[string]accountCapabilities{}
+		for _, a := range resp.Accounts {
+			byAccount[a.Account] = a
+		}
+		return byAccount
+	}
+	status := func(a accountCapabilities, service string) string {
+		for _, s := range a.Services {
+			if s.Service == service {
+				return s.Status
+			}
+		}
+		return ""
+	}
+
+	got := run()
+	if got["me@gmail.com"].Kind != accountKindConsumer || got["me@work.example"].Kind != accountKindWorkspace || got["me@family.example"].Kind != accountKindConsumer {
+		t.Fatalf("kinds: %+v", got)
+	}
+	if probes != 2 {
+		t.Fatalf("expected probes only for custom domains, got %d", probes)
+	}
+	if s := status(got["me@gmail.com"], "groups"); s != capabilityUnsupported {
+		t.Fatalf("gmail.com groups = %q", s)
+	}
+	if s := status(got["me@gmail.com"], "calendar"); s != capabilityAvailable {
+		t.Fatalf("gmail.com calendar = %q", s)
+	}
+	if s := status(got["me@work.example"], "calendar"); s != capabilityNotAuthorized {
+		t.Fatalf("work calendar = %q", s)
+	}
+	if s := status(got["me@work.example"], "groups"); s != capabilityAvailable {
+		t.Fatalf("work groups = %q", s)
+	}
+
+	// Probe results are remembered.
+	if run()["me@family.example"].Kind != accountKindConsumer || probes != 2 {
+		t.Fatalf("expected cached kinds, probes=%d", probes)
+	}
+	if !groupsViaContacts("me@family.example") || groupsViaContacts("me@work.example") {
+		t.Fatalf("groupsViaContacts does not follow the cache")
+	}
+
+	// A consumer result can come from a transient bad request, so it expires;
+	// the Workspace result stays.
+	state, err := loadAccountKinds()
+	if err != nil {
+		t.Fatalf("loadAccountKinds: %v", err)
+	}
+	for key, entry := range state.Accounts {
+		entry.CheckedAt = entry.CheckedAt.Add(-accountKindConsumerTTL - time.Hour)
+		state.Accounts[key] = entry
+	}
+	if err := saveAccountKinds(state); err != nil {
+		t.Fatalf("saveAccountKinds: %v", err)
+	}
+	if run()["me@family.example"].Kind != accountKindConsumer || probes != 3 {
+		t.Fatalf("expected only the expired consumer result to be probed again, probes=%d", probes)
+	}
+}
+
+func TestCloudIdentityMemberQuery(t *testing.T) {
+	if got, want := cloudIdentityMemberQuery(`o'neil\x@example.com`), `member_key_id == 'o\'neil\\x@example.com'`; got != want {
+		t.Fatalf("query = %s, want %s", got, want)
+	}
+}
+
+func TestGroupsFallBackToContactGroups(t *testing.T) {
+	origPeople := newPeopleContactsService
+	t.Cleanup(func() { newPeopleContactsService = origPeople })
+	peopleSvc := newContactGroupsTestService(t)
+	newPeopleContactsService = func(context.Context, string) (*people.Service, error) { return peopleSvc, nil }
+	stubCloudIdentity(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected Cloud Identity call %s", r.URL.Path)
+		http.NotFound(w, r)
+	})
+
+	var list map[string]any
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "me@gmail.com", "groups", "list"}); err != nil {
+				t.Fatalf("groups list: %v", err)
+			}
+		})
+	})
+	if err := json.Unmarshal([]byte(out), &list); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	groups, _ := list["groups"].([]any)
+	if list["source"] != "contacts" || len(groups) != 1 || groups[0].(map[string]any)["displayName"] != "Family" {
+		t.Fatalf("groups list = %v", list)
+	}
+
+	out = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "me@gmail.com", "groups", "members", "Family"}); err != nil {
+				t.Fatalf("groups members: %v", err)
+			}
+		})
+	})
+	if !strings.Contains(out, "mom@example.com") || !strings.Contains(out, "dad@example.com") {
+		t.Fatalf("groups members = %s", out)
+	}
+}
@@ -11,11 +11,25 @@ import (
 
 type completionFlag struct {
 	takesValue bool
+	values     completionValues
+}
+
+// completionValues describes how to complete a flag or positional value:
+// static enum values, or a dynamic kind from the `complete:"..."` tag.
+type completionValues struct {
+	enum []string
+	kind string
 }
 
 type completionNode struct {
-	children map[string]*completionNode
-	flags    map[string]completionFlag
+	children    map[string]*completionNode
+	flags       map[string]completionFlag
+	positionals []completionPositional
+}
+
+type completionPositional struct {
+	values     completionValues
+	cumulative bool
 }
 
 var (
@@ -41,9 +55,14 @@ func completeWords(cword int, words []string) ([]string, error) {
 
 	start := completionStartIndex(words)
 
-	node, terminatorIndex, needsValue := advanceCompletionNode(root, words, start, cword)
-	if needsValue {
-		return nil, nil
+	current := ""
+	if cword < len(words) {
+		current = words[cword]
+	}
+
+	node, terminatorIndex, valueFlag, positional := advanceCompletionNode(root, words, start, cword)
+	if valueFlag != "" {
+		return matchingValues(node.flags[valueFlag].values, "", current, words), nil
 	}
 
 	if shouldStopAfterTerminator(terminatorIndex, cword, words) {
@@ -54,22 +73,63 @@ func completeWords(cword int, words []string) ([]string, error) {
 		return nil, nil
 	}
 
-	current := ""
-	if cword < len(words) {
-		current = words[cword]
-	}
-
 	suggestions := make([]string, 0)
 	if strings.HasPrefix(current, "-") {
+		if flagToken, value, hasValue := strings.Cut(current, "="); hasValue {
+			if spec, ok := node.flags[flagToken]; ok && spec.takesValue {
+				return matchingValues(spec.values, flagToken+"=", value, words), nil
+			}
+			return nil, nil
+		}
 		suggestions = append(suggestions, matchingFlags(node, current)...)
 	} else {
 		suggestions = append(suggestions, matchingCommands(node, current)...)
 		suggestions = append(suggestions, matchingFlags(node, current)...)
+		if values, ok := node.positionalValues(positional); ok {
+			suggestions = append(suggestions, matchingValues(values, "", current, words)...)
+		}
 	}
 	sort.Strings(suggestions)
 	return suggestions, nil
 }
 
+// positionalValues returns the completion spec for the n-th positional word
+// after the command; a trailing slice argument absorbs any extra words.
+func (n *completionNode) positionalValues(index int) (completionValues, bool) {
+	if len(n.positionals) == 0 {
+		return completionValues{}, false
+	}
+	if index >= len(n.positionals) {
+		last := n.positionals[len(n.positionals)-1]
+		if !last.cumulative {
+			return completionValues{}, false
+		}
+		return last.values, true
+	}
+	return n.positionals[index].values, true
+}
+
+// matchingValues completes a value, keeping everything up to the last comma so
+// comma-separated lists (e.g. --add INBOX,Work) complete item by item.
+func matchingValues(values completionValues, prefix string, current string, words []string) []string {
+	if idx := strings.LastIndex(current, ","); idx != -1 {
+		prefix += current[:idx+1]
+		current = current[idx+1:]
+	}
+	candidates := values.enum
+	if len(candidates) == 0 && values.kind != "" {
+		candidates = dynamicCompletionValues(values.kind, words)
+	}
+	results := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		if strings.HasPrefix(strings.ToLower(c), strings.ToLower(current)) {
+			results = append(results, prefix+c)
+		}
+	}
+	sort.Strings(results)
+	return results
+}
+
 func completionRootNode() (*completionNode, error) {
 	completionRootOnce.Do(func() {
 		parser, _, err := newParser(baseDescription())
@@ -105,9 +165,13 @@ func completionStartIndex(words []string) int {
 	return 0
 }
 
-func advanceCompletionNode(root *completionNode, words []string, start int, cword int) (*completionNode, int, bool) {
+// advanceCompletionNode walks the words before cword. It returns the deepest
+// command node, the index of a "--" terminator (or -1), the flag whose value is
+// being completed (if any), and how many positionals precede cword.
+func advanceCompletionNode(root *completionNode, words []string, start int, cword int) (*completionNode, int, string, int) {
 	node := root
 	terminatorIndex := -1
+	positional := 0
 	for i := start; i < cword && i < len(words); {
 		word := words[i]
 		if word == "--" {
@@ -122,7 +186,7 @@ func advanceCompletionNode(root *completionNode, words []string, start int, cwor
 			}
 			if spec, ok := node.flags[flagToken]; ok && spec.takesValue {
 				if i+1 == cword {
-					return node, terminatorIndex, true
+					return node, terminatorIndex, flagToken, positional
 				}
 				i += 2
 				continue
@@ -132,13 +196,15 @@ func advanceCompletionNode(root *completionNode, words []string, start int, cwor
 		}
 		if child, ok := node.children[word]; ok {
 			node = child
+			positional = 0
 			i++
 			continue
 		}
+		positional++
 		i++
 	}
 
-	return node, terminatorIndex, false
+	return node, terminatorIndex, "", positional
 }
 
 func shouldStopAfterTerminator(terminatorIndex int, cword int, words []string) bool {
@@ -185,6 +251,13 @@ func buildCompletionNode(node *kong.Node) *completionNode {
 		}
 	}
 
+	for _, arg := range node.Positional {
+		current.positionals = append(current.positionals, completionPositional{
+			values:     valueCompletion(arg),
+			cumulative: arg.IsCumulative(),
+		})
+	}
+
 	for _, child := range node.Children {
 		if child.Hidden {
 			continue
@@ -204,17 +277,35 @@ func buildCompletionNode(node *kong.Node) *completionNode {
 }
 
 func addFlagTokens(flags map[string]completionFlag, flag *kong.Flag) {
-	takesValue := !(flag.IsBool() || flag.IsCounter())
-	addFlag(flags, "--"+flag.Name, takesValue)
+	spec := completionFlag{takesValue: !(flag.IsBool() || flag.IsCounter())}
+	if spec.takesValue {
+		spec.values = valueCompletion(flag.Value)
+	}
+	addFlag(flags, "--"+flag.Name, spec)
 	for _, alias := range flag.Aliases {
-		addFlag(flags, "--"+alias, takesValue)
+		addFlag(flags, "--"+alias, spec)
 	}
 	if flag.Short != 0 {
-		addFlag(flags, "-"+string(flag.Short), takesValue)
+		addFlag(flags, "-"+string(flag.Short), spec)
 	}
 	if negated := negatedFlagName(flag); negated != "" {
-		addFlag(flags, negated, false)
+		addFlag(flags, negated, completionFlag{})
+	}
+}
+
+func valueCompletion(v *kong.Value) completionValues {
+	out := completionValues{}
+	if v.Tag != nil {
+		out.kind = v.Tag.Get("complete")
+	}
+	if v.Enum != "" {
+		for _, e := range v.EnumSlice() {
+			if e != "" {
+				out.enum = append(out.enum, e)
+			}
+		}
 	}
+	return out
 }
 
 func negatedFlagName(flag *kong.Flag) string {
@@ -228,14 +319,14 @@ func negatedFlagName(flag *kong.Flag) string {
 	}
 }
 
-func addFlag(flags map[string]completionFlag, token string, takesValue bool) {
+func addFlag(flags map[string]completionFlag, token string, spec completionFlag) {
 	if token == "" {
 		return
 	}
 	if _, exists := flags[token]; exists {
 		return
 	}
-	flags[token] = completionFlag{takesValue: takesValue}
+	flags[token] = spec
 }
 
 func splitFlagToken(word string) (string, bool) {
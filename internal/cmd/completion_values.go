@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/secrets"
+)
+
+// Value kinds for the `complete:"..."` struct tag on flags and positionals.
+const (
+	completeAccounts  = "accounts"
+	completeLabels    = "labels"
+	completeCalendars = "calendars"
+)
+
+const (
+	// Cached label/calendar lists older than this are re-fetched on completion.
+	completionCacheTTL = 24 * time.Hour
+	// Completion must stay snappy; give up on the network after this long.
+	completionFetchTimeout = 3 * time.Second
+)
+
+type completionCacheEntry struct {
+	Labels             []string `json:"labels,omitempty"`
+	LabelsUpdatedAt    string   `json:"labelsUpdatedAt,omitempty"`
+	Calendars          []string `json:"calendars,omitempty"`
+	CalendarsUpdatedAt string   `json:"calendarsUpdatedAt,omitempty"`
+}
+
+// completionCache keeps per-account label and calendar lists so tab completion
+// does not need an API round-trip. It is refreshed by `gmail labels list`,
+// `calendar calendars`, and lazily when completing a stale entry.
+type completionCache struct {
+	path     string
+	Accounts map[string]*completionCacheEntry `json:"accounts"`
+}
+
+func loadCompletionCache() (*completionCache, error) {
+	path, err := config.CompletionCachePath()
+	if err != nil {
+		return nil, err
+	}
+	cache := &completionCache{path: path, Accounts: map[string]*completionCacheEntry{}}
+	data, err := os.ReadFile(path) //nolint:gosec // path under config dir
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cache, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, cache); err != nil {
+		// A corrupt cache is not worth failing completion over; start fresh.
+		return &completionCache{path: path, Accounts: map[string]*completionCacheEntry{}}, nil
+	}
+	if cache.Accounts == nil {
+		cache.Accounts = map[string]*completionCacheEntry{}
+	}
+	return cache, nil
+}
+
+func (c *completionCache) save() error {
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o700); err != nil {
+		return err
+	}
+	payload, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, append(payload, '\n'), 0o600)
+}
+
+func (c *completionCache) entry(account string) *completionCacheEntry {
+	key := strings.ToLower(strings.TrimSpace(account))
+	e, ok := c.Accounts[key]
+	if !ok {
+		e = &completionCacheEntry{}
+		c.Accounts[key] = e
+	}
+	return e
+}
+
+// rememberCompletionValues records values for later completion. Failures are
+// ignored: the cache is an optimization and must never break a command.
+func rememberCompletionValues(account, kind string, values []string) {
+	if strings.TrimSpace(account) == "" {
+		return
+	}
+	cache, err := loadCompletionCache()
+	if err != nil {
+		return
+	}
+	setCompletionValues(cache.entry(account), kind, values, time.Now())
+	_ = cache.save()
+}
+
+func setCompletionValues(e *completionCacheEntry, kind string, values []string, now time.Time) {
+	values = uniqueSorted(values)
+	stamp := now.UTC().Format(time.RFC3339)
+	switch kind {
+	case completeLabels:
+		e.Labels, e.LabelsUpdatedAt = values, stamp
+	case completeCalendars:
+		e.Calendars, e.CalendarsUpdatedAt = values, stamp
+	}
+}
+
+func cachedCompletionValues(e *completionCacheEntry, kind string, now time.Time) ([]string, bool) {
+	var values []string
+	var stamp string
+	switch kind {
+	case completeLabels:
+		values, stamp = e.Labels, e.LabelsUpdatedAt
+	case completeCalendars:
+		values, stamp = e.Calendars, e.CalendarsUpdatedAt
+	default:
+		return nil, false
+	}
+	updated, err := time.Parse(time.RFC3339, stamp)
+	fresh := err == nil && now.Sub(updated) < completionCacheTTL
+	return values, fresh
+}
+
+var completionFetchers = map[string]func(ctx context.Context, account string) ([]string, error){
+	completeLabels: func(ctx context.Context, account string) ([]string, error) {
+		svc, err := newGmailService(ctx, account)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := svc.Users.Labels.List("me").Context(ctx).Do()
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(resp.Labels))
+		for _, l := range resp.Labels {
+			names = append(names, l.Name)
+		}
+		return names, nil
+	},
+	completeCalendars: func(ctx context.Context, account string) ([]string, error) {
+		svc, err := newCalendarService(ctx, account)
+		if err != nil {
+			return nil, err
+		}
+		ids := []string{"primary"}
+		err = svc.CalendarList.List().Pages(ctx, func(resp *calendar.CalendarList) error {
+			for _, cal := range resp.Items {
+				ids = append(ids, cal.Id)
+			}
+			return nil
+		})
+		return ids, err
+	},
+}
+
+// dynamicCompletionValues returns candidates for a value kind. words is the
+// full command line, used to find the account being completed for.
+func dynamicCompletionValues(kind string, words []string) []string {
+	switch kind {
+	case completeAccounts:
+		return completionAccounts()
+	case completeLabels, completeCalendars:
+	default:
+		return nil
+	}
+
+	account := completionAccount(words)
+	if account == "" {
+		return nil
+	}
+	cache, err := loadCompletionCache()
+	if err != nil {
+		return nil
+	}
+	entry := cache.entry(account)
+	now := time.Now()
+	values, fresh := cachedCompletionValues(entry, kind, now)
+	if fresh {
+		return values
+	}
+
+	fetch := completionFetchers[kind]
+	ctx, cancel := context.WithTimeout(context.Background(), completionFetchTimeout)
+	defer cancel()
+	fetched, err := fetch(ctx, account)
+	if err != nil {
+		// Offline or unauthorized: stale values beat none.
+		return values
+	}
+	setCompletionValues(entry, kind, fetched, now)
+	_ = cache.save()
+	values, _ = cachedCompletionValues(entry, kind, now)
+	return values
+}
+
+// completionAccounts lists stored accounts and aliases. Only keyring key
+// names are read, never token payloads.
+func completionAccounts() []string {
+	var out []string
+	if store, err := openSecretsStoreForAccount(); err == nil {
+		if keys, keysErr := store.Keys(); keysErr == nil {
+			for _, k := range keys {
+				if _, email, ok := secrets.ParseTokenKey(k); ok {
+					out = append(out, email)
+				}
+			}
+		}
+	}
+	if aliases, err := config.ListAccountAliases(); err == nil {
+		for alias := range aliases {
+			out = append(out, alias)
+		}
+	}
+	return uniqueSorted(out)
+}
+
+// completionAccount resolves the account the command line will run as.
+func completionAccount(words []string) string {
+	flags := &RootFlags{}
+	for i, w := range words {
+		if w == "--" {
+			break
+		}
+		if v, ok := strings.CutPrefix(w, "--account="); ok {
+			flags.Account = v
+			continue
+		}
+		if w == "--account" && i+1 < len(words) {
+			flags.Account = words[i+1]
+		}
+		if v, ok := strings.CutPrefix(w, "--client="); ok {
+			flags.Client = v
+			continue
+		}
+		if w == "--client" && i+1 < len(words) {
+			flags.Client = words[i+1]
+		}
+	}
+	account, err := requireAccount(flags)
+	if err != nil {
+		return ""
+	}
+	return account
+}
+
+func uniqueSorted(values []string) []string {
+	seen := make(map[string]struct{}, len(values))
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		out = append(out, v)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/steipete/gogcli/internal/secrets"
+)
+
+func TestCompleteWords_EnumValues(t *testing.T) {
+	got, err := completeWords(2, []string{"gog", "completion", "f"})
+	if err != nil {
+		t.Fatalf("completeWords: %v", err)
+	}
+	if !reflect.DeepEqual(got, []string{"fish"}) {
+		t.Fatalf("got %v", got)
+	}
+
+	got, err = completeWords(6, []string{"gog", "gmail", "settings", "forwarding", "set", "--disposition", "ar"})
+	if err != nil {
+		t.Fatalf("completeWords: %v", err)
+	}
+	if !reflect.DeepEqual(got, []string{"archive"}) {
+		t.Fatalf("got %v", got)
+	}
+}
+
+func TestCompleteWords_Accounts(t *testing.T) {
+	store := newMemSecretsStore()
+	_ = store.SetToken("", "me@example.com", secrets.Token{RefreshToken: "r"})
+	_ = store.SetToken("work", "boss@corp.example", secrets.Token{RefreshToken: "r"})
+	prev := openSecretsStoreForAccount
+	t.Cleanup(func() { openSecretsStoreForAccount = prev })
+	openSecretsStoreForAccount = func() (secrets.Store, error) { return store, nil }
+
+	got, err := completeWords(2, []string{"gog", "--account", ""})
+	if err != nil {
+		t.Fatalf("completeWords: %v", err)
+	}
+	if !reflect.DeepEqual(got, []string{"boss@corp.example", "me@example.com"}) {
+		t.Fatalf("got %v", got)
+	}
+
+	got, _ = completeWords(1, []string{"gog", "--account=me"})
+	if !reflect.DeepEqual(got, []string{"--account=me@example.com"}) {
+		t.Fatalf("got %v", got)
+	}
+}
+
+func TestCompleteWords_CachedLabelsAndCalendars(t *testing.T) {
+	origFetchers := completionFetchers
+	t.Cleanup(func() { completionFetchers = origFetchers })
+	completionFetchers = map[string]func(context.Context, string) ([]string, error){
+		completeLabels: func(context.Context, string) ([]string, error) {
+			t.Fatalf("fresh cache must not be re-fetched")
+			return nil, nil
+		},
+		completeCalendars: func(context.Context, string) ([]string, error) {
+			return []string{"primary", "team@group.calendar.google.com"}, nil
+		},
+	}
+
+	rememberCompletionValues("complete@b.com", completeLabels, []string{"INBOX", "Work", "Work/Receipts", "IMPORTANT"})
+
+	got, _ := completeWords(7, []string{"gog", "--account", "complete@b.com", "gmail", "labels", "modify", "--add", "wo"})
+	if !reflect.DeepEqual(got, []string{"Work", "Work/Receipts"}) {
+		t.Fatalf("flag value: got %v", got)
+	}
+	got, _ = completeWords(6, []string{"gog", "--account", "complete@b.com", "gmail", "thread", "modify", "--remove=INBOX,IM"})
+	if !reflect.DeepEqual(got, []string{"--remove=INBOX,IMPORTANT"}) {
+		t.Fatalf("comma list: got %v", got)
+	}
+	got, _ = completeWords(6, []string{"gog", "--account", "complete@b.com", "gmail", "labels", "get", "In"})
+	if !reflect.DeepEqual(got, []string{"INBOX"}) {
+		t.Fatalf("positional: got %v", got)
+	}
+
+	// Missing calendars are fetched lazily and cached.
+	got, _ = completeWords(5, []string{"gog", "--account", "complete@b.com", "calendar", "events", "te"})
+	if !reflect.DeepEqual(got, []string{"team@group.calendar.google.com"}) {
+		t.Fatalf("calendar positional: got %v", got)
+	}
+	cache, err := loadCompletionCache()
+	if err != nil {
+		t.Fatalf("load cache: %v", err)
+	}
+	if cals, fresh := cachedCompletionValues(cache.entry("complete@b.com"), completeCalendars, time.Now()); !fresh || len(cals) != 2 {
+		t.Fatalf("expected cached calendars, got %v fresh=%v", cals, fresh)
+	}
+}
+
+func TestDynamicCompletionValues_StaleFallback(t *testing.T) {
+	origFetchers := completionFetchers
+	t.Cleanup(func() { completionFetchers = origFetchers })
+	completionFetchers = map[string]func(context.Context, string) ([]string, error){
+		completeLabels: func(context.Context, string) ([]string, error) { return nil, errors.New("offline") },
+	}
+
+	cache, err := loadCompletionCache()
+	if err != nil {
+		t.Fatalf("load cache: %v", err)
+	}
+	setCompletionValues(cache.entry("stale@b.com"), completeLabels, []string{"Old"}, time.Now().Add(-48*time.Hour))
+	if err := cache.save(); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	got := dynamicCompletionValues(completeLabels, []string{"gog", "--account", "stale@b.com"})
+	if !reflect.DeepEqual(got, []string{"Old"}) {
+		t.Fatalf("expected stale values when offline, got %v", got)
+	}
+}
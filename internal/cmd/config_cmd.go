@@ -3,7 +3,9 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 
 	"github.com/steipete/gogcli/internal/config"
 	"github.com/steipete/gogcli/internal/outfmt"
@@ -16,10 +18,12 @@ type ConfigCmd struct {
 	Unset ConfigUnsetCmd `cmd:"" help:"Unset a config value"`
 	List  ConfigListCmd  `cmd:"" help:"List all config values"`
 	Path  ConfigPathCmd  `cmd:"" help:"Print config file path"`
+	Sync  ConfigSyncCmd  `cmd:"" help:"Sync aliases, defaults, and links across machines (secrets stay local)"`
 }
 
 type ConfigGetCmd struct {
-	Key string `arg:"" help:"Config key to get (timezone)"`
+	Key        string `arg:"" help:"Config key to get (timezone, or defaults.<command>.<flag>)"`
+	ForAccount string `name:"for-account" help:"Read a per-account flag default (defaults.* keys only)"`
 }
 
 func (c *ConfigGetCmd) Run(ctx context.Context) error {
@@ -28,6 +32,18 @@ func (c *ConfigGetCmd) Run(ctx context.Context) error {
 		return err
 	}
 
+	if name, ok := config.ParseDefaultsKey(c.Key); ok {
+		value := cfg.GetDefault(c.ForAccount, name)
+		if outfmt.IsJSON(ctx) {
+			return outfmt.WriteJSON(os.Stdout, outfmt.KeyValuePayload(config.DefaultsKeyPrefix+name, value))
+		}
+		fmt.Fprintln(os.Stdout, formatConfigValue(value, nil))
+		return nil
+	}
+	if c.ForAccount != "" {
+		return usage("--for-account only applies to defaults.* keys")
+	}
+
 	key, err := config.ParseKey(c.Key)
 	if err != nil {
 		return err
@@ -59,8 +75,9 @@ func (c *ConfigKeysCmd) Run(ctx context.Context) error {
 }
 
 type ConfigSetCmd struct {
-	Key   string `arg:"" help:"Config key to set (timezone)"`
-	Value string `arg:"" help:"Value to set"`
+	Key        string `arg:"" help:"Config key to set (timezone, or defaults.<command>.<flag>)"`
+	Value      string `arg:"" help:"Value to set"`
+	ForAccount string `name:"for-account" help:"Set the flag default only for this account (defaults.* keys only)"`
 }
 
 func (c *ConfigSetCmd) Run(ctx context.Context) error {
@@ -69,6 +86,19 @@ func (c *ConfigSetCmd) Run(ctx context.Context) error {
 		return err
 	}
 
+	if name, ok := config.ParseDefaultsKey(c.Key); ok {
+		if err := validateDefaultName(name); err != nil {
+			return err
+		}
+		if err := cfg.SetDefault(c.ForAccount, name, c.Value); err != nil {
+			return usage(err.Error())
+		}
+		return writeConfigDefaultResult(ctx, cfg, config.DefaultsKeyPrefix+name, c.Value, c.ForAccount, "saved")
+	}
+	if c.ForAccount != "" {
+		return usage("--for-account only applies to defaults.* keys")
+	}
+
 	key, err := config.ParseKey(c.Key)
 	if err != nil {
 		return err
@@ -92,7 +122,8 @@ func (c *ConfigSetCmd) Run(ctx context.Context) error {
 }
 
 type ConfigUnsetCmd struct {
-	Key string `arg:"" help:"Config key to unset (timezone)"`
+	Key        string `arg:"" help:"Config key to unset (timezone, or defaults.<command>.<flag>)"`
+	ForAccount string `name:"for-account" help:"Unset a per-account flag default (defaults.* keys only)"`
 }
 
 func (c *ConfigUnsetCmd) Run(ctx context.Context) error {
@@ -101,6 +132,14 @@ func (c *ConfigUnsetCmd) Run(ctx context.Context) error {
 		return err
 	}
 
+	if name, ok := config.ParseDefaultsKey(c.Key); ok {
+		cfg.UnsetDefault(c.ForAccount, name)
+		return writeConfigDefaultResult(ctx, cfg, config.DefaultsKeyPrefix+name, "", c.ForAccount, "removed")
+	}
+	if c.ForAccount != "" {
+		return usage("--for-account only applies to defaults.* keys")
+	}
+
 	key, err := config.ParseKey(c.Key)
 	if err != nil {
 		return err
@@ -139,6 +178,12 @@ func (c *ConfigListCmd) Run(ctx context.Context) error {
 		for _, key := range keys {
 			payload[key.String()] = config.GetValue(cfg, key)
 		}
+		if len(cfg.Defaults) > 0 {
+			payload["defaults"] = cfg.Defaults
+		}
+		if len(cfg.AccountDefaults) > 0 {
+			payload["account_defaults"] = cfg.AccountDefaults
+		}
 		return outfmt.WriteJSON(os.Stdout, payload)
 	}
 
@@ -147,6 +192,38 @@ func (c *ConfigListCmd) Run(ctx context.Context) error {
 		value := config.GetValue(cfg, key)
 		fmt.Fprintf(os.Stdout, "%s: %s\n", key, formatConfigValue(value, func() string { return "(not set)" }))
 	}
+	for _, name := range config.DefaultNames(cfg.Defaults) {
+		fmt.Fprintf(os.Stdout, "%s%s: %s\n", config.DefaultsKeyPrefix, name, cfg.Defaults[name])
+	}
+	for _, account := range slices.Sorted(maps.Keys(cfg.AccountDefaults)) {
+		for _, name := range config.DefaultNames(cfg.AccountDefaults[account]) {
+			fmt.Fprintf(os.Stdout, "%s%s [%s]: %s\n", config.DefaultsKeyPrefix, name, account, cfg.AccountDefaults[account][name])
+		}
+	}
+	return nil
+}
+
+func writeConfigDefaultResult(ctx context.Context, cfg config.File, key, value, account, verb string) error {
+	if err := config.WriteConfig(cfg); err != nil {
+		return err
+	}
+	if outfmt.IsJSON(ctx) {
+		payload := outfmt.KeyValuePayload(key, value)
+		payload[verb] = true
+		if account != "" {
+			payload["account"] = account
+		}
+		return outfmt.WriteJSON(os.Stdout, payload)
+	}
+	scope := ""
+	if account != "" {
+		scope = " for " + account
+	}
+	if verb == "removed" {
+		fmt.Fprintf(os.Stdout, "Unset %s%s\n", key, scope)
+		return nil
+	}
+	fmt.Fprintf(os.Stdout, "Set %s = %s%s\n", key, value, scope)
 	return nil
 }
 
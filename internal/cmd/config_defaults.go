@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/steipete/gogcli/internal/config"
+)
+
+// Root flags whose defaults come from environment variables. A set variable
+// beats the config file, matching the flag > env > config > built-in order.
+var flagDefaultEnv = map[string]string{
+	"account":           "GOG_ACCOUNT",
+	"client":            "GOG_CLIENT",
+	"color":             "GOG_COLOR",
+	"enable-commands":   "GOG_ENABLE_COMMANDS",
+	"json":              "GOG_JSON",
+	"plain":             "GOG_PLAIN",
+	"preflight":         "GOG_PREFLIGHT",
+	"user-agent-suffix": "GOG_USER_AGENT_SUFFIX",
+	"weekday":           "GOG_CALENDAR_WEEKDAY",
+}
+
+// noConfigDefaultsCommands never take flag defaults from config.json, so
+// running them does not read it.
+var noConfigDefaultsCommands = map[string]bool{
+	"version":    true,
+	"completion": true,
+	"__complete": true,
+}
+
+// readConfigForDefaults is swapped out in tests to count config reads.
+var readConfigForDefaults = config.ReadConfig
+
+// configDefaultsResolver fills unset flags from the `defaults` and
+// `account_defaults` sections of config.json. The file is read on the first
+// lookup that could use it, not when the parser is built; `--help` exits
+// before flags are resolved, and version/completion never read it.
+func configDefaultsResolver() kong.Resolver {
+	var (
+		loaded  bool
+		cfg     config.File
+		account string
+	)
+	return kong.ResolverFunc(func(kctx *kong.Context, _ *kong.Path, flag *kong.Flag) (any, error) {
+		if env, ok := flagDefaultEnv[flag.Name]; ok && os.Getenv(env) != "" {
+			return nil, nil
+		}
+		command := commandPath(kctx)
+		if len(command) > 0 && noConfigDefaultsCommands[command[0]] {
+			return nil, nil
+		}
+		if !loaded {
+			loaded = true
+			// A broken config surfaces from the commands that need it; flag
+			// defaults are best-effort.
+			cfg, _ = readConfigForDefaults()
+			if len(cfg.AccountDefaults) > 0 {
+				account = defaultsAccount(kctx, cfg)
+			}
+		}
+		if len(cfg.Defaults) == 0 && len(cfg.AccountDefaults) == 0 {
+			return nil, nil
+		}
+
+		if account != "" {
+			if v, ok := config.LookupDefault(cfg.DefaultsFor(account), command, flag.Name); ok {
+				return v, nil
+			}
+		}
+		if v, ok := config.LookupDefault(cfg.Defaults, command, flag.Name); ok {
+			return v, nil
+		}
+		return nil, nil
+	})
+}
+
+// defaultsAccount picks the account for per-account defaults from --account,
+// GOG_ACCOUNT, or the global `account` default. The keyring default account
+// is not consulted so resolving flags never triggers a keychain prompt.
+func defaultsAccount(kctx *kong.Context, cfg config.File) string {
+	account := ""
+	for _, p := range kctx.Path {
+		if p.Flag != nil && p.Flag.Name == "account" {
+			account, _ = kctx.FlagValue(p.Flag).(string)
+		}
+	}
+	if account == "" {
+		account = os.Getenv("GOG_ACCOUNT")
+	}
+	if account == "" {
+		account, _ = config.LookupDefault(cfg.Defaults, nil, "account")
+	}
+	account = strings.TrimSpace(account)
+	if email, ok := cfg.AccountAliases[config.NormalizeAccountAlias(account)]; ok {
+		account = email
+	}
+	return strings.ToLower(account)
+}
+
+func commandPath(kctx *kong.Context) []string {
+	var out []string
+	for _, p := range kctx.Path {
+		if p.Command != nil {
+			out = append(out, p.Command.Name)
+		}
+	}
+	return out
+}
+
+// validateDefaultName checks that "gmail.search.max" names a real command
+// path and that the flag exists on it or one of its subcommands.
+func validateDefaultName(name string) error {
+	parser, _, err := newParser("")
+	if err != nil {
+		return err
+	}
+	path, flag := config.SplitDefaultName(name)
+	node := parser.Model.Node
+	for _, seg := range path {
+		var next *kong.Node
+		for _, child := range node.Children {
+			if child.Type == kong.CommandNode && (child.Name == seg || slices.Contains(child.Aliases, seg)) {
+				next = child
+				break
+			}
+		}
+		if next == nil {
+			return usagef("unknown command %q in defaults key %q", seg, name)
+		}
+		if next.Name != seg {
+			return usagef("use the command name %q instead of alias %q in defaults key", next.Name, seg)
+		}
+		node = next
+	}
+	if !nodeHasFlag(node, flag) {
+		return usagef("unknown flag --%s for %q", flag, strings.Join(append([]string{"gog"}, path...), " "))
+	}
+	return nil
+}
+
+func nodeHasFlag(node *kong.Node, flag string) bool {
+	// Flags on ancestors (root flags) apply to every command below them.
+	for n := node; n != nil; n = n.Parent {
+		for _, f := range n.Flags {
+			if f.Name == flag {
+				return true
+			}
+		}
+	}
+	return subtreeHasFlag(node, flag)
+}
+
+func subtreeHasFlag(node *kong.Node, flag string) bool {
+	for _, child := range node.Children {
+		for _, f := range child.Flags {
+			if f.Name == flag {
+				return true
+			}
+		}
+		if subtreeHasFlag(child, flag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/tasks/v1"
+
+	"github.com/steipete/gogcli/internal/config"
+)
+
+func TestConfigDefaults_AppliedWithPrecedence(t *testing.T) {
+	orig, err := config.ReadConfig()
+	if err != nil {
+		t.Fatalf("ReadConfig: %v", err)
+	}
+	t.Cleanup(func() { _ = config.WriteConfig(orig) })
+
+	origNew := newTasksService
+	t.Cleanup(func() { newTasksService = origNew })
+
+	var gotMax string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMax = r.URL.Query().Get("maxResults")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"items": []any{}})
+	}))
+	defer srv.Close()
+	svc, err := tasks.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newTasksService = func(context.Context, string) (*tasks.Service, error) { return svc, nil }
+
+	run := func(args ...string) string {
+		t.Helper()
+		return captureStdout(t, func() {
+			_ = captureStderr(t, func() {
+				if err := Execute(args); err != nil {
+					t.Fatalf("Execute %v: %v", args, err)
+				}
+			})
+		})
+	}
+
+	run("config", "set", "defaults.max", "11")
+	run("config", "set", "defaults.tasks.list.max", "7")
+	run("config", "set", "defaults.tasks.list.max", "3", "--for-account", "Work@B.com")
+	run("config", "set", "defaults.json", "true")
+
+	out := run("--account", "a@b.com", "tasks", "list", "l1")
+	if gotMax != "7" {
+		t.Fatalf("maxResults = %q, want 7 from defaults.tasks.list.max", gotMax)
+	}
+	if !strings.HasPrefix(strings.TrimSpace(out), "{") {
+		t.Fatalf("defaults.json should switch to JSON output, got %q", out)
+	}
+
+	run("--account", "work@b.com", "tasks", "list", "l1")
+	if gotMax != "3" {
+		t.Fatalf("maxResults = %q, want per-account 3", gotMax)
+	}
+
+	run("--account", "work@b.com", "tasks", "list", "l1", "--max", "5")
+	if gotMax != "5" {
+		t.Fatalf("maxResults = %q, want explicit flag 5", gotMax)
+	}
+
+	t.Setenv("GOG_JSON", "0")
+	out = run("--account", "a@b.com", "tasks", "list", "l1")
+	if strings.HasPrefix(strings.TrimSpace(out), "{") {
+		t.Fatalf("GOG_JSON must override defaults.json, got %q", out)
+	}
+}
+
+func TestConfigDefaults_SkipsConfigReadWhenUnused(t *testing.T) {
+	orig := readConfigForDefaults
+	t.Cleanup(func() { readConfigForDefaults = orig })
+	reads := 0
+	readConfigForDefaults = func() (config.File, error) {
+		reads++
+		return config.File{}, nil
+	}
+
+	_ = captureStdout(t, func() {
+		if err := Execute([]string{"version"}); err != nil {
+			t.Fatalf("version: %v", err)
+		}
+		_ = Execute([]string{"gmail", "search", "--help"})
+	})
+	if reads != 0 {
+		t.Fatalf("version/--help read config.json %d times", reads)
+	}
+}
+
+// parseWithConfigDefaults parses args against a config file holding defaults,
+// without running the command.
+func parseWithConfigDefaults(t *testing.T, defaults map[string]string, args ...string) *CLI {
+	t.Helper()
+	orig := readConfigForDefaults
+	t.Cleanup(func() { readConfigForDefaults = orig })
+	readConfigForDefaults = func() (config.File, error) {
+		return config.File{Defaults: defaults}, nil
+	}
+	parser, cli, err := newParser("")
+	if err != nil {
+		t.Fatalf("newParser: %v", err)
+	}
+	if _, err := parser.Parse(args); err != nil {
+		t.Fatalf("Parse %v: %v", args, err)
+	}
+	return cli
+}
+
+func TestConfigDefaults_PreflightEnvBeatsConfig(t *testing.T) {
+	if !parseWithConfigDefaults(t, map[string]string{"preflight": "true"}, "gmail", "labels", "list").Preflight {
+		t.Fatalf("defaults.preflight should apply without GOG_PREFLIGHT")
+	}
+	t.Setenv("GOG_PREFLIGHT", "true")
+	if !parseWithConfigDefaults(t, map[string]string{"preflight": "false"}, "gmail", "labels", "list").Preflight {
+		t.Fatalf("GOG_PREFLIGHT must override defaults.preflight")
+	}
+}
+
+func TestConfigDefaults_UserAgentSuffixEnvBeatsConfig(t *testing.T) {
+	defaults := map[string]string{"user-agent-suffix": "from-config"}
+	if got := parseWithConfigDefaults(t, defaults, "gmail", "labels", "list").UserAgentSuffix; got != "from-config" {
+		t.Fatalf("suffix = %q, want the config default", got)
+	}
+	t.Setenv("GOG_USER_AGENT_SUFFIX", "from-env")
+	if got := parseWithConfigDefaults(t, defaults, "gmail", "labels", "list").UserAgentSuffix; got != "from-env" {
+		t.Fatalf("suffix = %q, GOG_USER_AGENT_SUFFIX must override the config default", got)
+	}
+}
+
+func TestConfigSet_ValidatesDefaultsKey(t *testing.T) {
+	orig, err := config.ReadConfig()
+	if err != nil {
+		t.Fatalf("ReadConfig: %v", err)
+	}
+	t.Cleanup(func() { _ = config.WriteConfig(orig) })
+
+	for _, key := range []string{"defaults.gmail.nope.max", "defaults.tasks.list.nope", "defaults.mail.search.max"} {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"config", "set", key, "1"}); ExitCode(err) != 2 {
+				t.Fatalf("%s: expected usage error, got %v", key, err)
+			}
+		})
+	}
+	_ = captureStdout(t, func() {
+		if err := Execute([]string{"config", "set", "defaults.gmail.max", "25"}); err != nil {
+			t.Fatalf("gmail.max should be valid (flag on subcommands): %v", err)
+		}
+	})
+}
+
+func TestAppendSignature(t *testing.T) {
+	if got := appendTextSignature("Hi\n\n", "Jane\nACME"); got != "Hi\n\n-- \nJane\nACME\n" {
+		t.Fatalf("text signature: %q", got)
+	}
+	if got := appendHTMLSignature("<p>Hi</p>", "A & B\nx"); got != "<p>Hi</p><br><br>-- <br>A &amp; B<br>x" {
+		t.Fatalf("html signature: %q", got)
+	}
+	if got := appendTextSignature("Hi", " "); got != "Hi" {
+		t.Fatalf("empty signature changed body: %q", got)
+	}
+}
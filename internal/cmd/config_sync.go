@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"google.golang.org/api/drive/v3"
+	gapi "google.golang.org/api/googleapi"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+const (
+	configSyncFileName = "gog-config-sync.json"
+	driveAppDataFolder = "appDataFolder"
+)
+
+// ConfigSyncCmd shares non-secret config (aliases, defaults, timezone, links)
+// between machines through a hidden file in the account's Drive
+// appDataFolder. Keyring settings, OAuth client mappings, and tokens never
+// leave the machine.
+type ConfigSyncCmd struct {
+	Via    string `name:"via" help:"Sync backend" enum:"drive-appdata" default:"drive-appdata"`
+	Prefer string `name:"prefer" help:"Which side wins when a key changed on both: local|remote" enum:"local,remote" default:"local"`
+	DryRun bool   `name:"dry-run" help:"Show what would change without writing anything"`
+}
+
+func (c *ConfigSyncCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	state, err := config.ReadSyncState()
+	if err != nil {
+		return err
+	}
+
+	svc, err := newDriveService(ctx, account)
+	if err != nil {
+		return err
+	}
+	fileID, doc, err := readConfigSyncDoc(ctx, svc)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	merged, entries, changes := config.MergeSync(state.Base, config.SyncableValues(cfg), doc.Entries, c.Prefer == "remote", now)
+
+	pulled, pushed := 0, 0
+	for _, ch := range changes {
+		switch {
+		case ch.Action == "pull" || ch.Resolved == "remote":
+			pulled++
+		default:
+			pushed++
+		}
+	}
+
+	if !c.DryRun {
+		if pulled > 0 {
+			if err := config.ApplySyncableValues(&cfg, merged); err != nil {
+				return err
+			}
+			if err := config.WriteConfig(cfg); err != nil {
+				return err
+			}
+		}
+		if pushed > 0 || fileID == "" {
+			host, _ := os.Hostname()
+			doc = config.SyncDoc{Version: config.SyncVersion, UpdatedAt: now, UpdatedBy: host, Entries: entries}
+			if fileID, err = writeConfigSyncDoc(ctx, svc, fileID, doc); err != nil {
+				return err
+			}
+		}
+		state = config.SyncState{LastSync: now, FileID: fileID, Base: merged}
+		if err := config.WriteSyncState(state); err != nil {
+			return err
+		}
+	}
+
+	if changes == nil {
+		changes = []config.SyncChange{}
+	}
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"via":     c.Via,
+			"fileId":  fileID,
+			"pulled":  pulled,
+			"pushed":  pushed,
+			"changes": changes,
+			"dryRun":  c.DryRun,
+		})
+	}
+	if len(changes) == 0 {
+		u.Err().Println("Config already in sync")
+		return nil
+	}
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "ACTION\tKEY")
+	for _, ch := range changes {
+		action := ch.Action
+		if ch.Resolved != "" {
+			action += " (" + ch.Resolved + " wins)"
+		}
+		fmt.Fprintf(w, "%s\t%s\n", action, ch.Key)
+	}
+	if c.DryRun {
+		u.Err().Println("Dry run: nothing written")
+	}
+	return nil
+}
+
+// readConfigSyncDoc returns the sync file's ID and contents; a missing file
+// yields an empty ID and document.
+func readConfigSyncDoc(ctx context.Context, svc *drive.Service) (string, config.SyncDoc, error) {
+	doc := config.SyncDoc{Entries: map[string]config.SyncEntry{}}
+	list, err := svc.Files.List().
+		Spaces(driveAppDataFolder).
+		Q(fmt.Sprintf("name = '%s' and trashed = false", configSyncFileName)).
+		Fields("files(id, name, modifiedTime)").
+		OrderBy("modifiedTime desc").
+		PageSize(1).
+		Context(ctx).
+		Do()
+	if err != nil {
+		return "", doc, fmt.Errorf("find %s in Drive appDataFolder: %w", configSyncFileName, err)
+	}
+	if len(list.Files) == 0 {
+		return "", doc, nil
+	}
+	fileID := list.Files[0].Id
+
+	resp, err := svc.Files.Get(fileID).Context(ctx).Download()
+	if err != nil {
+		return "", doc, fmt.Errorf("download %s: %w", configSyncFileName, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", doc, err
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return "", doc, fmt.Errorf("parse %s: %w", configSyncFileName, err)
+	}
+	if doc.Version > config.SyncVersion {
+		return "", doc, fmt.Errorf("%s was written by a newer gog (format %d); upgrade to sync", configSyncFileName, doc.Version)
+	}
+	if doc.Entries == nil {
+		doc.Entries = map[string]config.SyncEntry{}
+	}
+	return fileID, doc, nil
+}
+
+func writeConfigSyncDoc(ctx context.Context, svc *drive.Service, fileID string, doc config.SyncDoc) (string, error) {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	media := bytes.NewReader(data)
+	if fileID == "" {
+		created, createErr := svc.Files.Create(&drive.File{Name: configSyncFileName, Parents: []string{driveAppDataFolder}}).
+			Media(media, gapi.ContentType("application/json")).
+			Fields("id").
+			Context(ctx).
+			Do()
+		if createErr != nil {
+			return "", fmt.Errorf("create %s: %w", configSyncFileName, createErr)
+		}
+		return created.Id, nil
+	}
+	if _, err := svc.Files.Update(fileID, &drive.File{}).
+		Media(media, gapi.ContentType("application/json")).
+		Fields("id").
+		Context(ctx).
+		Do(); err != nil {
+		return "", fmt.Errorf("update %s: %w", configSyncFileName, err)
+	}
+	return fileID, nil
+}
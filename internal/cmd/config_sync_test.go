@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+
+	"github.com/steipete/gogcli/internal/config"
+)
+
+func TestConfigSyncCmd(t *testing.T) {
+	origNew := newDriveService
+	t.Cleanup(func() { newDriveService = origNew })
+
+	var stored []byte
+	var creates, updates int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/upload"), "/drive/v3")
+		upload := strings.HasPrefix(r.URL.Path, "/upload/")
+		switch {
+		case r.Method == http.MethodGet && !upload && path == "/files":
+			if r.URL.Query().Get("spaces") != "appDataFolder" || !strings.Contains(r.URL.Query().Get("q"), configSyncFileName) {
+				t.Fatalf("unexpected list query: %s", r.URL.RawQuery)
+			}
+			files := []map[string]any{}
+			if stored != nil {
+				files = append(files, map[string]any{"id": "cfg1", "name": configSyncFileName})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"files": files})
+		case r.Method == http.MethodGet && path == "/files/cfg1" && r.URL.Query().Get("alt") == "media":
+			_, _ = w.Write(stored)
+		case r.Method == http.MethodPost && upload && path == "/files":
+			meta, media := readMultipartUpload(t, r)
+			if !strings.Contains(meta, `"appDataFolder"`) {
+				t.Fatalf("sync file not created in appDataFolder: %s", meta)
+			}
+			stored = media
+			creates++
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "cfg1"})
+		case r.Method == http.MethodPatch && upload && path == "/files/cfg1":
+			_, stored = readMultipartUpload(t, r)
+			updates++
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "cfg1"})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.String())
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	svc, err := drive.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newDriveService = func(context.Context, string) (*drive.Service, error) { return svc, nil }
+
+	if err := config.WriteConfig(config.File{
+		KeyringBackend: "file",
+		AccountAliases: map[string]string{"work": "me@work.example"},
+		AccountClients: map[string]string{"me@work.example": "work"},
+	}); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	sync := func(args ...string) map[string]any {
+		t.Helper()
+		out := captureStdout(t, func() {
+			if err := Execute(append([]string{"--json", "--account", "a@b.com", "config", "sync"}, args...)); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+		var parsed map[string]any
+		if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+			t.Fatalf("json: %v\n%s", err, out)
+		}
+		return parsed
+	}
+
+	// First sync creates the shared file with only non-secret keys.
+	if got := sync(); got["pushed"] != float64(1) || creates != 1 {
+		t.Fatalf("first sync: %v creates=%d", got, creates)
+	}
+	var doc config.SyncDoc
+	if err := json.Unmarshal(stored, &doc); err != nil {
+		t.Fatalf("stored doc: %v", err)
+	}
+	if len(doc.Entries) != 1 || string(doc.Entries["account_aliases/work"].Value) != `"me@work.example"` {
+		t.Fatalf("unexpected stored entries: %#v", doc.Entries)
+	}
+	if strings.Contains(string(stored), "keyring") || strings.Contains(string(stored), "account_clients") {
+		t.Fatalf("machine-local settings leaked: %s", stored)
+	}
+
+	// Another machine adds an alias; we pull it without re-uploading.
+	doc.Entries["account_aliases/home"] = config.SyncEntry{Value: json.RawMessage(`"me@home.example"`)}
+	stored, _ = json.Marshal(doc)
+	if got := sync("--dry-run"); got["pulled"] != float64(1) {
+		t.Fatalf("dry run: %v", got)
+	}
+	if cfg, _ := config.ReadConfig(); cfg.AccountAliases["home"] != "" {
+		t.Fatalf("dry run wrote config: %#v", cfg.AccountAliases)
+	}
+	if got := sync(); got["pulled"] != float64(1) || got["pushed"] != float64(0) || updates != 0 {
+		t.Fatalf("pull sync: %v updates=%d", got, updates)
+	}
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	if cfg.AccountAliases["home"] != "me@home.example" || cfg.KeyringBackend != "file" || cfg.AccountClients["me@work.example"] != "work" {
+		t.Fatalf("unexpected merged config: %#v", cfg)
+	}
+
+	// A local delete is pushed as a tombstone.
+	delete(cfg.AccountAliases, "work")
+	if err := config.WriteConfig(cfg); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if got := sync(); got["pushed"] != float64(1) || updates != 1 {
+		t.Fatalf("delete sync: %v updates=%d", got, updates)
+	}
+	doc = config.SyncDoc{}
+	if err := json.Unmarshal(stored, &doc); err != nil {
+		t.Fatalf("stored doc: %v", err)
+	}
+	if !doc.Entries["account_aliases/work"].Deleted {
+		t.Fatalf("expected tombstone: %#v", doc.Entries)
+	}
+
+	if got := sync(); len(got["changes"].([]any)) != 0 {
+		t.Fatalf("expected no changes: %v", got)
+	}
+}
+
+func readMultipartUpload(t *testing.T, r *http.Request) (string, []byte) {
+	t.Helper()
+	_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("content type: %v", err)
+	}
+	mr := multipart.NewReader(r.Body, params["boundary"])
+	var parts [][]byte
+	for {
+		p, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("multipart: %v", err)
+		}
+		b, _ := io.ReadAll(p)
+		parts = append(parts, b)
+	}
+	if len(parts) != 2 {
+		t.Fatalf("expected metadata and media parts, got %d", len(parts))
+	}
+	return string(parts[0]), parts[1]
+}
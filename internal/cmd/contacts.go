@@ -19,6 +19,8 @@ type ContactsCmd struct {
 	Create    ContactsCreateCmd    `cmd:"" name:"create" help:"Create a contact"`
 	Update    ContactsUpdateCmd    `cmd:"" name:"update" help:"Update a contact"`
 	Delete    ContactsDeleteCmd    `cmd:"" name:"delete" help:"Delete a contact"`
+	Enrich    ContactsEnrichCmd    `cmd:"" name:"enrich" help:"Propose phone/title/company updates from a sender's email signatures"`
+	Report    ContactsReportCmd    `cmd:"" name:"report" help:"Rank contacts by mail and meeting frequency and recency; flag relationships going cold"`
 	Directory ContactsDirectoryCmd `cmd:"" name:"directory" help:"Directory contacts"`
 	Other     ContactsOtherCmd     `cmd:"" name:"other" help:"Other contacts"`
 }
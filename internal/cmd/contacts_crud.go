@@ -166,13 +166,14 @@ func (c *ContactsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type ContactsCreateCmd struct {
-	Given  string `name:"given" help:"Given name (required)"`
-	Family string `name:"family" help:"Family name"`
-	Email  string `name:"email" help:"Email address"`
-	Phone  string `name:"phone" help:"Phone number"`
+	Given       string          `name:"given" help:"Given name (required)"`
+	Family      string          `name:"family" help:"Family name"`
+	Email       string          `name:"email" help:"Email address"`
+	Phone       string          `name:"phone" help:"Phone number"`
+	Idempotency IdempotencyFlag `embed:""`
 }
 
-func (c *ContactsCreateCmd) Run(ctx context.Context, flags *RootFlags) error {
+func (c *ContactsCreateCmd) Run(ctx context.Context, flags *RootFlags) (err error) {
 	u := ui.FromContext(ctx)
 	account, err := requireAccount(flags)
 	if err != nil {
@@ -182,6 +183,12 @@ func (c *ContactsCreateCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return usage("required: --given")
 	}
 
+	idem, done, err := beginIdempotent(ctx, c.Idempotency.Key, account, "contacts.create")
+	if done || err != nil {
+		return err
+	}
+	defer idem.finish(&err)
+
 	svc, err := newPeopleContactsService(ctx, account)
 	if err != nil {
 		return err
@@ -204,6 +211,7 @@ func (c *ContactsCreateCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if err != nil {
 		return err
 	}
+	idem.resource(created.ResourceName)
 	if outfmt.IsJSON(ctx) {
 		return outfmt.WriteJSON(os.Stdout, map[string]any{"contact": created})
 	}
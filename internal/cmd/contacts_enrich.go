@@ -0,0 +1,421 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"google.golang.org/api/people/v1"
+
+	"github.com/steipete/gogcli/internal/errfmt"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+const contactsEnrichReadMask = contactsReadMask + ",organizations"
+
+type ContactsEnrichCmd struct {
+	Query   string `name:"query" short:"q" help:"Gmail query selecting messages from the person (e.g. from:person@x.com)"`
+	Contact string `name:"contact" help:"Contact resource name (people/...; default: look up the sender's email)"`
+	Max     int64  `name:"max" aliases:"limit" help:"Max messages to scan" default:"20"`
+	DryRun  bool   `name:"dry-run" help:"Only show proposed updates"`
+}
+
+// enrichProposal is one contact field change backed by signature evidence.
+type enrichProposal struct {
+	Field    string `json:"field"`
+	Current  string `json:"current,omitempty"`
+	Proposed string `json:"proposed"`
+	Seen     int    `json:"seen"`
+}
+
+func (c *ContactsEnrichCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	query := strings.TrimSpace(c.Query)
+	if query == "" {
+		return usage("required: --query")
+	}
+	if c.Max <= 0 {
+		return usage("--max must be > 0")
+	}
+
+	gsvc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+	list, err := gsvc.Users.Messages.List("me").Q(query).MaxResults(c.Max).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+	if len(list.Messages) == 0 {
+		return fmt.Errorf("no messages match %q: %w", query, errfmt.ErrNotFound)
+	}
+
+	senders := map[string]int{}
+	names := map[string]string{}
+	var sigs []signatureInfo
+	for _, ref := range list.Messages {
+		msg, getErr := gsvc.Users.Messages.Get("me", ref.Id).Format("full").Context(ctx).Do()
+		if getErr != nil {
+			return getErr
+		}
+		from, parseErr := mail.ParseAddress(headerValue(msg.Payload, "From"))
+		if parseErr != nil {
+			continue
+		}
+		email := strings.ToLower(from.Address)
+		senders[email]++
+		if from.Name != "" {
+			names[email] = from.Name
+		}
+		sigs = append(sigs, parseSignature(bestBodyText(msg.Payload), from.Name))
+	}
+	sender := topVote(senders)
+	if sender == "" {
+		return usage("could not determine the sender of the matched messages")
+	}
+
+	psvc, err := newPeopleContactsService(ctx, account)
+	if err != nil {
+		return err
+	}
+	person, err := c.findContact(ctx, psvc, sender)
+	if err != nil {
+		return err
+	}
+
+	proposals := proposeEnrichment(person, sigs)
+	applied := false
+	if len(proposals) > 0 && !c.DryRun {
+		if err := confirmDestructive(ctx, flags, fmt.Sprintf("update %d field(s) on contact %s", len(proposals), person.ResourceName)); err != nil {
+			return err
+		}
+		if person, err = applyEnrichment(ctx, psvc, person, proposals); err != nil {
+			return err
+		}
+		applied = true
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"resource":  person.ResourceName,
+			"sender":    sender,
+			"name":      names[sender],
+			"scanned":   len(sigs),
+			"proposals": proposals,
+			"applied":   applied,
+		})
+	}
+
+	if len(proposals) == 0 {
+		u.Err().Printf("No new details found in %d message(s) from %s", len(sigs), sender)
+		return nil
+	}
+	w, flush := tableWriter(ctx)
+	fmt.Fprintln(w, "FIELD\tCURRENT\tPROPOSED\tSEEN")
+	for _, p := range proposals {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d/%d\n", p.Field, p.Current, p.Proposed, p.Seen, len(sigs))
+	}
+	flush()
+	if applied {
+		u.Err().Printf("Updated %s", person.ResourceName)
+	} else {
+		u.Err().Println("Dry run: contact not updated")
+	}
+	return nil
+}
+
+func (c *ContactsEnrichCmd) findContact(ctx context.Context, svc *people.Service, email string) (*people.Person, error) {
+	if resource := strings.TrimSpace(c.Contact); resource != "" {
+		if !strings.HasPrefix(resource, "people/") {
+			return nil, usage("--contact must start with people/")
+		}
+		return svc.People.Get(resource).PersonFields(contactsEnrichReadMask).Context(ctx).Do()
+	}
+	resp, err := svc.People.SearchContacts().Query(email).ReadMask(contactsEnrichReadMask).PageSize(10).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range resp.Results {
+		if r == nil || r.Person == nil {
+			continue
+		}
+		for _, e := range r.Person.EmailAddresses {
+			if e != nil && strings.EqualFold(strings.TrimSpace(e.Value), email) {
+				return r.Person, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no contact with email %s (create one with `gog contacts create --email %s` or pass --contact): %w", email, email, errfmt.ErrNotFound)
+}
+
+// proposeEnrichment picks the most common signature values and keeps the ones
+// the contact does not already have.
+func proposeEnrichment(p *people.Person, sigs []signatureInfo) []enrichProposal {
+	phones := map[string]int{} // keyed by digits so formatting variants agree
+	phoneDisplay := map[string]string{}
+	titles := map[string]int{}
+	companies := map[string]int{}
+	for _, s := range sigs {
+		for _, ph := range s.Phones {
+			key := phoneDigits(ph)
+			phones[key]++
+			if _, ok := phoneDisplay[key]; !ok {
+				phoneDisplay[key] = ph
+			}
+		}
+		if s.Title != "" {
+			titles[s.Title]++
+		}
+		if s.Company != "" {
+			companies[s.Company]++
+		}
+	}
+
+	var out []enrichProposal
+	existingPhones := map[string]bool{}
+	for _, ph := range p.PhoneNumbers {
+		if ph != nil {
+			existingPhones[phoneDigits(ph.Value)] = true
+		}
+	}
+	for _, key := range sortedVotes(phones) {
+		if existingPhones[key] {
+			continue
+		}
+		out = append(out, enrichProposal{Field: "phone", Proposed: phoneDisplay[key], Seen: phones[key]})
+	}
+
+	var curTitle, curCompany string
+	if len(p.Organizations) > 0 && p.Organizations[0] != nil {
+		curTitle = p.Organizations[0].Title
+		curCompany = p.Organizations[0].Name
+	}
+	if t := topVote(titles); t != "" && !strings.EqualFold(t, curTitle) {
+		out = append(out, enrichProposal{Field: "title", Current: curTitle, Proposed: t, Seen: titles[t]})
+	}
+	if co := topVote(companies); co != "" && !strings.EqualFold(co, curCompany) {
+		out = append(out, enrichProposal{Field: "company", Current: curCompany, Proposed: co, Seen: companies[co]})
+	}
+	return out
+}
+
+func applyEnrichment(ctx context.Context, svc *people.Service, p *people.Person, proposals []enrichProposal) (*people.Person, error) {
+	fields := map[string]bool{}
+	for _, pr := range proposals {
+		switch pr.Field {
+		case "phone":
+			p.PhoneNumbers = append(p.PhoneNumbers, &people.PhoneNumber{Value: pr.Proposed, Type: "work"})
+			fields["phoneNumbers"] = true
+		case "title", "company":
+			if len(p.Organizations) == 0 || p.Organizations[0] == nil {
+				p.Organizations = []*people.Organization{{}}
+			}
+			if pr.Field == "title" {
+				p.Organizations[0].Title = pr.Proposed
+			} else {
+				p.Organizations[0].Name = pr.Proposed
+			}
+			fields["organizations"] = true
+		}
+	}
+	return svc.People.UpdateContact(p.ResourceName, p).
+		UpdatePersonFields(strings.Join(sortedSetKeys(fields), ",")).
+		Context(ctx).
+		Do()
+}
+
+type signatureInfo struct {
+	Phones  []string
+	Title   string
+	Company string
+}
+
+var (
+	sigPhoneRe      = regexp.MustCompile(`\+?\(?\d[\d\s().\-/]{6,}\d`)
+	sigPhoneLabelRe = regexp.MustCompile(`(?i)^(tel|phone|mobile|mob|cell|direct|office|work|[tmpo])\.?\s*[:.]\s*`)
+	sigURLRe        = regexp.MustCompile(`(?i)(https?://|www\.|\S+@\S+\.\S+)`)
+	sigTitleWordRe  = regexp.MustCompile(`(?i)\b(engineer|manager|director|founder|co-founder|ceo|cto|cfo|coo|cmo|vp|vice president|head|lead|president|consultant|designer|developer|officer|partner|analyst|specialist|coordinator|architect|scientist|associate|recruiter|counsel|attorney|editor|producer|owner|principal|advisor|administrator|representative|executive|professor|researcher)\b`)
+	sigSplitRe      = regexp.MustCompile(`\s+(?:\||·|•|–|—|-|@|at)\s+|,\s+`)
+	sigQuoteStartRe = regexp.MustCompile(`(?i)^(on .+ wrote:|-+\s*original message\s*-+|from:\s.+|sent from my )`)
+	sigDateRe       = regexp.MustCompile(`^\d{4}[-/]\d{1,2}[-/]\d{1,2}$`)
+)
+
+// parseSignature extracts phone numbers, a job title and a company from the
+// signature block of a plain-text body. Only the trailing lines of the
+// sender's own text are considered, after quoted replies are cut off.
+func parseSignature(body, senderName string) signatureInfo {
+	lines := signatureLines(body)
+	var info signatureInfo
+	seen := map[string]bool{}
+	for _, line := range lines {
+		for _, ph := range extractPhones(line) {
+			if !seen[phoneDigits(ph)] {
+				seen[phoneDigits(ph)] = true
+				info.Phones = append(info.Phones, ph)
+			}
+		}
+	}
+
+	// Title/company usually follow the name line; otherwise look for a line
+	// with a job-title keyword.
+	start := -1
+	if first := firstWord(senderName); first != "" {
+		for i, line := range lines {
+			if strings.HasPrefix(strings.ToLower(line), strings.ToLower(first)) && len(line) <= len(senderName)+10 {
+				start = i + 1
+				break
+			}
+		}
+	}
+	var candidates []string
+	if start >= 0 {
+		for _, line := range lines[start:] {
+			if isContactDetailLine(line) {
+				break
+			}
+			candidates = append(candidates, line)
+			if len(candidates) == 2 {
+				break
+			}
+		}
+	}
+	if len(candidates) == 0 || !sigTitleWordRe.MatchString(candidates[0]) {
+		candidates = nil
+		for i, line := range lines {
+			if sigTitleWordRe.MatchString(line) && !isContactDetailLine(line) {
+				candidates = append(candidates, line)
+				if i+1 < len(lines) && !isContactDetailLine(lines[i+1]) && !sigTitleWordRe.MatchString(lines[i+1]) {
+					candidates = append(candidates, lines[i+1])
+				}
+				break
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return info
+	}
+
+	if parts := sigSplitRe.Split(candidates[0], 2); len(parts) == 2 {
+		info.Title, info.Company = cleanSigField(parts[0]), cleanSigField(parts[1])
+	} else {
+		info.Title = cleanSigField(candidates[0])
+		if len(candidates) > 1 {
+			info.Company = cleanSigField(candidates[1])
+		}
+	}
+	if !sigTitleWordRe.MatchString(info.Title) {
+		// "Acme Corp | Staff Engineer" ordering.
+		if sigTitleWordRe.MatchString(info.Company) {
+			info.Title, info.Company = info.Company, info.Title
+		} else {
+			info.Title, info.Company = "", ""
+		}
+	}
+	return info
+}
+
+// signatureLines returns the non-empty trailing lines of the sender's text:
+// everything after a "-- " delimiter, or else the last lines before any
+// quoted reply.
+func signatureLines(body string) []string {
+	raw := strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n")
+	var own []string
+	for _, line := range raw {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, ">") || sigQuoteStartRe.MatchString(trimmed) {
+			break
+		}
+		own = append(own, line)
+	}
+	for i := len(own) - 1; i >= 0; i-- {
+		if own[i] == "-- " || strings.TrimSpace(own[i]) == "--" {
+			own = own[i+1:]
+			break
+		}
+	}
+
+	var out []string
+	for _, line := range own {
+		if t := strings.TrimSpace(line); t != "" {
+			out = append(out, t)
+		}
+	}
+	const maxSignatureLines = 8
+	if len(out) > maxSignatureLines {
+		out = out[len(out)-maxSignatureLines:]
+	}
+	return out
+}
+
+func extractPhones(line string) []string {
+	line = sigPhoneLabelRe.ReplaceAllString(line, "")
+	var out []string
+	for _, m := range sigPhoneRe.FindAllString(line, -1) {
+		m = strings.TrimSpace(m)
+		if n := len(phoneDigits(m)); n >= 7 && n <= 15 && !looksLikeDate(m) {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+func looksLikeDate(s string) bool {
+	return sigDateRe.MatchString(strings.TrimSpace(s))
+}
+
+func isContactDetailLine(line string) bool {
+	return len(extractPhones(line)) > 0 || sigURLRe.MatchString(line) || sigPhoneLabelRe.MatchString(line)
+}
+
+func cleanSigField(s string) string {
+	return strings.Trim(strings.TrimSpace(s), " ,|·•-–—")
+}
+
+func phoneDigits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func firstWord(s string) string {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// sortedVotes orders values by count, then alphabetically.
+func sortedVotes(votes map[string]int) []string {
+	keys := make([]string, 0, len(votes))
+	for k := range votes {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if votes[keys[i]] != votes[keys[j]] {
+			return votes[keys[i]] > votes[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+func topVote(votes map[string]int) string {
+	if keys := sortedVotes(votes); len(keys) > 0 {
+		return keys[0]
+	}
+	return ""
+}
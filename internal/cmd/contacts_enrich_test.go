@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+func TestParseSignature(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		sender string
+		want   signatureInfo
+	}{
+		{
+			name:   "delimiter with pipe",
+			body:   "Hi,\n\nSounds good.\n\n-- \nJane Doe\nSenior Engineer | Acme Corp\nM: +1 (415) 555-0134\nhttps://acme.example\n",
+			sender: "Jane Doe",
+			want:   signatureInfo{Phones: []string{"+1 (415) 555-0134"}, Title: "Senior Engineer", Company: "Acme Corp"},
+		},
+		{
+			name:   "separate lines before quote",
+			body:   "Thanks!\n\nBest,\nJane\nHead of Sales\nAcme Corp\nTel: 020 7946 0018\n\nOn Mon, Jan 5, 2026 at 9:00 AM Bob wrote:\n> Phone: 555 000 1111\n",
+			sender: "Jane Doe",
+			want:   signatureInfo{Phones: []string{"020 7946 0018"}, Title: "Head of Sales", Company: "Acme Corp"},
+		},
+		{
+			name:   "company first",
+			body:   "ok\n--\nAcme Corp, Staff Engineer\n",
+			sender: "",
+			want:   signatureInfo{Title: "Staff Engineer", Company: "Acme Corp"},
+		},
+		{
+			name:   "no signature",
+			body:   "See you on 2026-01-05.\n",
+			sender: "Jane Doe",
+			want:   signatureInfo{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseSignature(tt.body, tt.sender)
+			if strings.Join(got.Phones, ";") != strings.Join(tt.want.Phones, ";") || got.Title != tt.want.Title || got.Company != tt.want.Company {
+				t.Fatalf("parseSignature() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContactsEnrich_ProposesAndApplies(t *testing.T) {
+	gsvc := newMockGmail(t)
+	for _, body := range []string{
+		"Hi\r\n\r\n-- \r\nJane Doe\r\nSenior Engineer | Acme Corp\r\nM: +1 (415) 555-0134\r\n",
+		"Ok\r\n\r\n-- \r\nJane Doe\r\nSenior Engineer | Acme Corp\r\nM: +1 415 555 0134\r\n",
+	} {
+		raw := "From: Jane Doe <jane@acme.example>\r\nTo: test@example.com\r\nSubject: Hello\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n" + body
+		if _, err := gsvc.Users.Messages.Insert("me", &gmail.Message{Raw: base64.URLEncoding.EncodeToString([]byte(raw)), LabelIds: []string{"INBOX"}}).Do(); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+	}
+
+	var updateBody map[string]any
+	var updateFields string
+	psvc, closeSrv := newPeopleService(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(r.URL.Path, "people:searchContacts"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"results": []map[string]any{{
+				"person": map[string]any{
+					"resourceName":   "people/c1",
+					"etag":           "e1",
+					"emailAddresses": []map[string]any{{"value": "Jane@acme.example"}},
+					"phoneNumbers":   []map[string]any{{"value": "+44 20 7946 0018"}},
+				},
+			}}})
+		case strings.Contains(r.URL.Path, "people/c1:updateContact") && r.Method == http.MethodPatch:
+			updateFields = r.URL.Query().Get("updatePersonFields")
+			_ = json.NewDecoder(r.Body).Decode(&updateBody)
+			updateBody["resourceName"] = "people/c1"
+			_ = json.NewEncoder(w).Encode(updateBody)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer closeSrv()
+	stubPeopleServices(t, psvc)
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "test@example.com", "contacts", "enrich", "--query", "from:jane@acme.example", "--dry-run"}); err != nil {
+				t.Fatalf("dry run: %v", err)
+			}
+		})
+	})
+	var parsed struct {
+		Resource  string           `json:"resource"`
+		Scanned   int              `json:"scanned"`
+		Applied   bool             `json:"applied"`
+		Proposals []enrichProposal `json:"proposals"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if parsed.Resource != "people/c1" || parsed.Scanned != 2 || parsed.Applied || len(parsed.Proposals) != 3 {
+		t.Fatalf("unexpected dry run: %#v", parsed)
+	}
+	if p := parsed.Proposals[0]; p.Field != "phone" || p.Seen != 2 {
+		t.Fatalf("unexpected phone proposal: %#v", p)
+	}
+	if updateBody != nil {
+		t.Fatalf("dry run must not update")
+	}
+
+	_ = captureStderr(t, func() {
+		if err := Execute([]string{"--no-input", "--account", "test@example.com", "contacts", "enrich", "--query", "from:jane@acme.example"}); ExitCode(err) != 2 {
+			t.Fatalf("expected refusal without --force, got %v", err)
+		}
+	})
+
+	_ = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--force", "--account", "test@example.com", "contacts", "enrich", "--query", "from:jane@acme.example"}); err != nil {
+				t.Fatalf("apply: %v", err)
+			}
+		})
+	})
+	if updateFields != "organizations,phoneNumbers" {
+		t.Fatalf("unexpected updatePersonFields %q", updateFields)
+	}
+	orgs, _ := updateBody["organizations"].([]any)
+	phones, _ := updateBody["phoneNumbers"].([]any)
+	if len(orgs) != 1 || len(phones) != 2 {
+		t.Fatalf("unexpected update body: %#v", updateBody)
+	}
+	org, _ := orgs[0].(map[string]any)
+	if org["title"] != "Senior Engineer" || org["name"] != "Acme Corp" {
+		t.Fatalf("unexpected organization: %#v", org)
+	}
+}
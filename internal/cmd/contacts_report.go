@@ -0,0 +1,354 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"net/mail"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// contactScoreHalfLife is how long it takes an interaction to count half as
+// much toward a contact's score.
+const contactScoreHalfLife = 90 * 24 * time.Hour
+
+// automatedLocalParts mark senders that are not people.
+var automatedLocalParts = []string{"noreply", "no-reply", "donotreply", "do-not-reply", "mailer-daemon", "postmaster", "notifications", "notification", "bounce"}
+
+type ContactsReportCmd struct {
+	Since     SinceTime   `name:"since" help:"Look back this far (e.g. 1y, 26w) or since a date (YYYY-MM-DD)" default:"1y"`
+	Max       int64       `name:"max" aliases:"limit" help:"Max messages to scan" default:"5000"`
+	Top       int         `name:"top" help:"Show only the top N contacts (0 = all)" default:"50"`
+	ColdAfter DayDuration `name:"cold-after" help:"Minimum silence before a regular contact is flagged as going cold" default:"30d"`
+	NoMail    bool        `name:"no-mail" help:"Skip Gmail"`
+	NoMeet    bool        `name:"no-meetings" help:"Skip calendar meetings"`
+	CSV       bool        `name:"csv" help:"Write the report as CSV to stdout"`
+}
+
+// contactActivity is one person's interactions in the report window.
+type contactActivity struct {
+	Email       string    `json:"email"`
+	Name        string    `json:"name,omitempty"`
+	Sent        int       `json:"sent"`
+	Received    int       `json:"received"`
+	Meetings    int       `json:"meetings"`
+	First       time.Time `json:"first"`
+	Last        time.Time `json:"last"`
+	DaysSince   int       `json:"daysSince"`
+	Score       float64   `json:"score"`
+	GoingCold   bool      `json:"goingCold"`
+	interaction []time.Time
+}
+
+func (a *contactActivity) total() int {
+	return a.Sent + a.Received + a.Meetings
+}
+
+// contactLedger collects interactions keyed by lowercased email.
+type contactLedger struct {
+	mu   sync.Mutex
+	self map[string]bool
+	byID map[string]*contactActivity
+}
+
+func (l *contactLedger) record(addr *mail.Address, at time.Time, kind string) {
+	if addr == nil || at.IsZero() {
+		return
+	}
+	email := strings.ToLower(strings.TrimSpace(addr.Address))
+	if email == "" || l.self[email] || isAutomatedAddress(email) {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	a, ok := l.byID[email]
+	if !ok {
+		a = &contactActivity{Email: email}
+		l.byID[email] = a
+	}
+	if a.Name == "" && addr.Name != "" && !strings.EqualFold(addr.Name, email) {
+		a.Name = addr.Name
+	}
+	switch kind {
+	case "sent":
+		a.Sent++
+	case "received":
+		a.Received++
+	default:
+		a.Meetings++
+	}
+	a.interaction = append(a.interaction, at)
+}
+
+func (c *ContactsReportCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	if c.CSV && outfmt.IsJSON(ctx) {
+		return usage("use only one of --csv or --json")
+	}
+	if c.Max <= 0 {
+		return usage("--max must be > 0")
+	}
+	if c.Top < 0 {
+		return usage("--top must be >= 0")
+	}
+	if c.NoMail && c.NoMeet {
+		return usage("nothing to analyze: --no-mail and --no-meetings both set")
+	}
+
+	now := time.Now()
+	since := c.Since.Time(now)
+	ledger := &contactLedger{self: map[string]bool{strings.ToLower(account): true}, byID: map[string]*contactActivity{}}
+
+	scanned := 0
+	if !c.NoMail {
+		if scanned, err = c.scanMail(ctx, account, ledger, since); err != nil {
+			return err
+		}
+	}
+	meetings := 0
+	if !c.NoMeet {
+		if meetings, err = c.scanMeetings(ctx, account, ledger, since, now); err != nil {
+			return err
+		}
+	}
+
+	rows := rankContacts(ledger.byID, now, c.ColdAfter.Duration())
+	if c.Top > 0 && len(rows) > c.Top {
+		rows = rows[:c.Top]
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"since":    since.UTC().Format(time.RFC3339),
+			"messages": scanned,
+			"meetings": meetings,
+			"contacts": rows,
+		})
+	}
+	if c.CSV {
+		return writeContactReportCSV(rows)
+	}
+	if len(rows) == 0 {
+		u.Err().Println("No interactions")
+		return nil
+	}
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "EMAIL\tNAME\tSENT\tRECEIVED\tMEETINGS\tLAST\tDAYS\tSCORE\tSTATUS")
+	for _, r := range rows {
+		status := "-"
+		if r.GoingCold {
+			status = "going cold"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%s\t%d\t%.1f\t%s\n", r.Email, orEmpty(sanitizeTab(r.Name), "-"), r.Sent, r.Received, r.Meetings, r.Last.Local().Format("2006-01-02"), r.DaysSince, r.Score, status)
+	}
+	return nil
+}
+
+// scanMail counts mail you sent to each person and mail they sent you.
+// Bulk mail (List-Unsubscribe) does not count as an interaction.
+func (c *ContactsReportCmd) scanMail(ctx context.Context, account string, ledger *contactLedger, since time.Time) (int, error) {
+	svc, err := newGmailService(ctx, account)
+	if err != nil {
+		return 0, err
+	}
+	ids, err := listMessageIDs(ctx, svc, fmt.Sprintf("after:%d -in:chats", since.Unix()), c.Max)
+	if err != nil {
+		return 0, err
+	}
+	err = fetchGmailConcurrently(ctx, len(ids), gmailQuotaMessageGet, func(ctx context.Context, idx int) error {
+		msg, getErr := svc.Users.Messages.Get("me", ids[idx]).
+			Format("metadata").
+			MetadataHeaders("From", "To", "Cc", "List-Unsubscribe").
+			Context(ctx).
+			Do()
+		if getErr != nil {
+			return fmt.Errorf("message %s: %w", ids[idx], getErr)
+		}
+		recordMessageContacts(ledger, msg)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+func recordMessageContacts(ledger *contactLedger, msg *gmail.Message) {
+	if msg == nil || msg.Payload == nil {
+		return
+	}
+	at := time.UnixMilli(msg.InternalDate)
+	sent := false
+	for _, l := range msg.LabelIds {
+		if l == "SENT" {
+			sent = true
+			break
+		}
+	}
+	if sent {
+		for _, h := range []string{"To", "Cc"} {
+			for _, addr := range parseContactAddresses(headerValue(msg.Payload, h)) {
+				ledger.record(addr, at, "sent")
+			}
+		}
+		return
+	}
+	if headerValue(msg.Payload, "List-Unsubscribe") != "" {
+		return
+	}
+	for _, addr := range parseContactAddresses(headerValue(msg.Payload, "From")) {
+		ledger.record(addr, at, "received")
+	}
+}
+
+// scanMeetings counts meetings you attended with each person. Declined
+// meetings and large events (more than 25 guests) are skipped.
+func (c *ContactsReportCmd) scanMeetings(ctx context.Context, account string, ledger *contactLedger, since, now time.Time) (int, error) {
+	svc, err := newCalendarService(ctx, account)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	pageToken := ""
+	for {
+		call := svc.Events.List("primary").
+			TimeMin(since.Format(time.RFC3339)).
+			TimeMax(now.Format(time.RFC3339)).
+			SingleEvents(true).
+			MaxResults(2500).
+			Context(ctx)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return 0, err
+		}
+		for _, e := range resp.Items {
+			if recordMeetingContacts(ledger, e) {
+				count++
+			}
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+	return count, nil
+}
+
+func recordMeetingContacts(ledger *contactLedger, e *calendar.Event) bool {
+	if e == nil || e.Status == "cancelled" || e.Start == nil || len(e.Attendees) < 2 || len(e.Attendees) > 25 {
+		return false
+	}
+	at, ok := parseEventTime(e.Start.DateTime, e.Start.TimeZone)
+	if !ok {
+		return false // all-day events are rarely meetings
+	}
+	for _, a := range e.Attendees {
+		if a != nil && a.Self && a.ResponseStatus == "declined" {
+			return false
+		}
+	}
+	for _, a := range e.Attendees {
+		if a == nil || a.Self || a.Resource || a.ResponseStatus == "declined" {
+			continue
+		}
+		ledger.record(&mail.Address{Name: a.DisplayName, Address: a.Email}, at, "meeting")
+	}
+	return true
+}
+
+func parseContactAddresses(header string) []*mail.Address {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return nil
+	}
+	if list, err := mail.ParseAddressList(header); err == nil {
+		return list
+	}
+	var out []*mail.Address
+	for _, email := range parseEmailAddresses(header) {
+		out = append(out, &mail.Address{Address: email})
+	}
+	return out
+}
+
+func isAutomatedAddress(email string) bool {
+	local, _, _ := strings.Cut(email, "@")
+	for _, p := range automatedLocalParts {
+		if local == p || strings.HasPrefix(local, p+"+") || strings.HasPrefix(local, p+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// rankContacts scores each contact by interactions weighted for recency and
+// flags regular contacts (3+ interactions) whose silence is longer than
+// coldAfter and twice their usual gap between interactions.
+func rankContacts(byID map[string]*contactActivity, now time.Time, coldAfter time.Duration) []*contactActivity {
+	rows := make([]*contactActivity, 0, len(byID))
+	for _, a := range byID {
+		sort.Slice(a.interaction, func(i, j int) bool { return a.interaction[i].Before(a.interaction[j]) })
+		a.First = a.interaction[0]
+		a.Last = a.interaction[len(a.interaction)-1]
+		a.DaysSince = int(now.Sub(a.Last).Hours() / 24)
+		for _, at := range a.interaction {
+			a.Score += math.Pow(0.5, now.Sub(at).Hours()/contactScoreHalfLife.Hours())
+		}
+		a.Score = math.Round(a.Score*10) / 10
+		if n := len(a.interaction); n >= 3 {
+			usualGap := a.Last.Sub(a.First) / time.Duration(n-1)
+			silence := now.Sub(a.Last)
+			a.GoingCold = silence > coldAfter && silence > 2*usualGap
+		}
+		rows = append(rows, a)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Score != rows[j].Score {
+			return rows[i].Score > rows[j].Score
+		}
+		if rows[i].total() != rows[j].total() {
+			return rows[i].total() > rows[j].total()
+		}
+		return rows[i].Email < rows[j].Email
+	})
+	return rows
+}
+
+func writeContactReportCSV(rows []*contactActivity) error {
+	cw := csv.NewWriter(os.Stdout)
+	if err := cw.Write([]string{"email", "name", "sent", "received", "meetings", "first", "last", "days_since", "score", "going_cold"}); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		record := []string{
+			r.Email, r.Name,
+			fmt.Sprint(r.Sent), fmt.Sprint(r.Received), fmt.Sprint(r.Meetings),
+			r.First.UTC().Format(time.RFC3339), r.Last.UTC().Format(time.RFC3339),
+			fmt.Sprint(r.DaysSince), fmt.Sprintf("%.1f", r.Score), fmt.Sprint(r.GoingCold),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/option"
+)
+
+func TestContactsReport(t *testing.T) {
+	now := time.Now()
+	daysAgo := func(d int) string { return fmt.Sprint(now.AddDate(0, 0, -d).UnixMilli()) }
+	msgs := map[string]map[string]any{
+		"s1": {"labelIds": []string{"SENT"}, "internalDate": daysAgo(2), "headers": [][2]string{{"To", "Ann <ann@example.com>"}, {"Cc", "me@example.com"}}},
+		"r1": {"labelIds": []string{"INBOX"}, "internalDate": daysAgo(3), "headers": [][2]string{{"From", "Ann <ann@example.com>"}}},
+		"r2": {"labelIds": []string{"INBOX"}, "internalDate": daysAgo(1), "headers": [][2]string{{"From", "News <news@example.com>"}, {"List-Unsubscribe", "<mailto:u@example.com>"}}},
+		"r3": {"labelIds": []string{"INBOX"}, "internalDate": daysAgo(1), "headers": [][2]string{{"From", "no-reply@example.com"}}},
+		"b1": {"labelIds": []string{"INBOX"}, "internalDate": daysAgo(200), "headers": [][2]string{{"From", "Bob <bob@example.com>"}}},
+		"b2": {"labelIds": []string{"INBOX"}, "internalDate": daysAgo(190), "headers": [][2]string{{"From", "Bob <bob@example.com>"}}},
+		"b3": {"labelIds": []string{"SENT"}, "internalDate": daysAgo(180), "headers": [][2]string{{"To", "bob@example.com"}}},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/users/me/messages"):
+			var refs []map[string]string
+			for id := range msgs {
+				refs = append(refs, map[string]string{"id": id})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"messages": refs})
+		case strings.Contains(r.URL.Path, "/users/me/messages/"):
+			id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+			m := msgs[id]
+			var headers []map[string]string
+			for _, h := range m["headers"].([][2]string) {
+				headers = append(headers, map[string]string{"name": h[0], "value": h[1]})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": id, "labelIds": m["labelIds"], "internalDate": m["internalDate"], "payload": map[string]any{"headers": headers}})
+		case strings.HasSuffix(r.URL.Path, "/calendars/primary/events"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"items": []map[string]any{{
+				"id": "e1", "start": map[string]string{"dateTime": now.AddDate(0, 0, -5).Format(time.RFC3339)},
+				"attendees": []map[string]any{{"email": "me@example.com", "self": true}, {"email": "ann@example.com"}, {"email": "room@resource.calendar.google.com", "resource": true}},
+			}}})
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	stubGmailService(t, srv)
+	origCal := newCalendarService
+	t.Cleanup(func() { newCalendarService = origCal })
+	newCalendarService = func(ctx context.Context, _ string) (*calendar.Service, error) {
+		return calendar.NewService(ctx, option.WithoutAuthentication(), option.WithHTTPClient(srv.Client()), option.WithEndpoint(srv.URL+"/"))
+	}
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--account", "me@example.com", "contacts", "report", "--since", "1y", "--csv"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil {
+		t.Fatalf("csv: %v\n%s", err, out)
+	}
+	if len(records) != 3 {
+		t.Fatalf("want header + ann + bob, got:\n%s", out)
+	}
+	ann, bob := records[1], records[2]
+	if ann[0] != "ann@example.com" || ann[1] != "Ann" || ann[2] != "1" || ann[3] != "1" || ann[4] != "1" || ann[9] != "false" {
+		t.Fatalf("ann = %v", ann)
+	}
+	if bob[0] != "bob@example.com" || bob[2] != "1" || bob[3] != "2" || bob[9] != "true" {
+		t.Fatalf("bob = %v", bob)
+	}
+}
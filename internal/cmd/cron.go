@@ -0,0 +1,410 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/cron"
+	"github.com/steipete/gogcli/internal/events"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+type CronCmd struct {
+	Add    CronAddCmd    `cmd:"" name:"add" help:"Schedule a gog command (five-field cron syntax)"`
+	List   CronListCmd   `cmd:"" name:"list" aliases:"ls" help:"List scheduled commands with their next and last run"`
+	Remove CronRemoveCmd `cmd:"" name:"remove" aliases:"rm,delete" help:"Remove a scheduled command"`
+	Run    CronRunCmd    `cmd:"" name:"run" help:"Run scheduled commands as they fall due (the cron daemon)"`
+}
+
+// cronJob is one scheduled gog command. Args are stored already split, so
+// the daemon runs exactly what add validated.
+type cronJob struct {
+	ID        string    `json:"id"`
+	Schedule  string    `json:"schedule"`
+	Command   string    `json:"command"`
+	Args      []string  `json:"args"`
+	Account   string    `json:"account,omitempty"`
+	Created   time.Time `json:"created"`
+	LastRun   time.Time `json:"lastRun,omitzero"`
+	LastExit  int       `json:"lastExit"`
+	LastError string    `json:"lastError,omitempty"`
+}
+
+// cronDue reports whether the job has a run time between its last run (or
+// creation) and now. A daemon that slept through several runs finds them all
+// in the past and runs the job once to catch up.
+func cronDue(job cronJob, sched cron.Schedule, now time.Time) bool {
+	since := job.LastRun
+	if since.IsZero() {
+		since = job.Created
+	}
+	next := sched.Next(since)
+	return !next.IsZero() && !next.After(now)
+}
+
+// cronStore holds the scheduled commands across accounts in the local state
+// dir. The daemon reloads it every tick, so add and remove apply without a
+// restart.
+type cronStore struct {
+	path string
+	Jobs []cronJob `json:"jobs"`
+}
+
+func loadCronStore() (*cronStore, error) {
+	path, err := config.CronPath()
+	if err != nil {
+		return nil, err
+	}
+	store := &cronStore{path: path}
+	data, err := os.ReadFile(path) //nolint:gosec // path under config dir
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return store, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("parse cron store: %w", err)
+	}
+	return store, nil
+}
+
+func (s *cronStore) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("ensure state dir: %w", err)
+	}
+	sort.SliceStable(s.Jobs, func(i, j int) bool {
+		a, _ := strconv.Atoi(s.Jobs[i].ID)
+		b, _ := strconv.Atoi(s.Jobs[j].ID)
+		return a < b
+	})
+	payload, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, append(payload, '\n'), 0o600)
+}
+
+func (s *cronStore) nextID() string {
+	highest := 0
+	for _, j := range s.Jobs {
+		if n, err := strconv.Atoi(j.ID); err == nil && n > highest {
+			highest = n
+		}
+	}
+	return strconv.Itoa(highest + 1)
+}
+
+func (s *cronStore) find(id string) *cronJob {
+	for i := range s.Jobs {
+		if s.Jobs[i].ID == id {
+			return &s.Jobs[i]
+		}
+	}
+	return nil
+}
+
+type CronAddCmd struct {
+	Schedule string `arg:"" name:"schedule" help:"When to run: \"min hour day month weekday\" (e.g. \"0 8 * * 1-5\") or @hourly, @daily, @weekly"`
+	Command  string `arg:"" name:"command" help:"gog command line, quoted (e.g. \"gmail later --json\"); a leading gog is optional"`
+}
+
+func (c *CronAddCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	sched, err := cron.Parse(c.Schedule)
+	if err != nil {
+		return usagef("schedule: %v", err)
+	}
+	args, err := parseCronCommand(c.Command)
+	if err != nil {
+		return err
+	}
+
+	store, err := loadCronStore()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	job := cronJob{
+		ID:       store.nextID(),
+		Schedule: strings.Join(strings.Fields(c.Schedule), " "),
+		Command:  strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(c.Command), "gog ")),
+		Args:     args,
+		Account:  strings.TrimSpace(flags.Account),
+		Created:  now,
+	}
+	store.Jobs = append(store.Jobs, job)
+	if err := store.save(); err != nil {
+		return err
+	}
+
+	next := sched.Next(now)
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{"job": job, "next": cronTimeJSON(next)})
+	}
+	u.Out().Printf("id\t%s", job.ID)
+	u.Out().Printf("next\t%s", cronTimeText(next))
+	u.Err().Println("Runs while `gog cron run` is running")
+	return nil
+}
+
+// parseCronCommand splits a scheduled command line and checks that it parses
+// as a gog command, so typos fail now rather than at 8am.
+func parseCronCommand(line string) ([]string, error) {
+	args, err := splitBatchLine(line)
+	if err != nil {
+		return nil, usagef("command: %v", err)
+	}
+	if len(args) > 0 && args[0] == "gog" {
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		return nil, usage("command: empty")
+	}
+	if topLevelCommand(args) == "cron" {
+		return nil, usage("command: cron jobs cannot manage cron")
+	}
+	for _, a := range args {
+		if a == "--" {
+			break
+		}
+		if slices.Contains([]string{"-h", "--help", "--version"}, a) {
+			return nil, usagef("command: %s does not run anything", a)
+		}
+	}
+	parser, _, err := newParser("", onlyCommand(args)...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := parser.Parse(args); err != nil {
+		return nil, usagef("command: %v", err)
+	}
+	return args, nil
+}
+
+type CronListCmd struct{}
+
+func (c *CronListCmd) Run(ctx context.Context) error {
+	u := ui.FromContext(ctx)
+	store, err := loadCronStore()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	type jobView struct {
+		cronJob
+		Next any `json:"next"`
+	}
+	if outfmt.IsJSON(ctx) {
+		jobs := make([]jobView, 0, len(store.Jobs))
+		for _, j := range store.Jobs {
+			jobs = append(jobs, jobView{cronJob: j, Next: cronTimeJSON(cronNext(j, now))})
+		}
+		return outfmt.WriteJSON(os.Stdout, map[string]any{"jobs": jobs})
+	}
+	if len(store.Jobs) == 0 {
+		u.Err().Println("No cron jobs (add one with gog cron add)")
+		return nil
+	}
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "ID\tSCHEDULE\tNEXT\tLAST\tSTATUS\tACCOUNT\tCOMMAND")
+	for _, j := range store.Jobs {
+		last, status := "-", "-"
+		if !j.LastRun.IsZero() {
+			last = j.LastRun.Local().Format("2006-01-02 15:04")
+			status = "ok"
+			if j.LastExit != 0 || j.LastError != "" {
+				status = fmt.Sprintf("exit %d", j.LastExit)
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", j.ID, j.Schedule, cronTimeText(cronNext(j, now)), last, status, orEmpty(j.Account, "-"), sanitizeTab(j.Command))
+	}
+	return nil
+}
+
+// cronNext is when the daemon will next run the job: now when a run is
+// overdue, otherwise the next scheduled time.
+func cronNext(j cronJob, now time.Time) time.Time {
+	sched, err := cron.Parse(j.Schedule)
+	if err != nil {
+		return time.Time{}
+	}
+	if cronDue(j, sched, now) {
+		return now
+	}
+	return sched.Next(now)
+}
+
+func cronTimeText(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+func cronTimeJSON(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t.Format(time.RFC3339)
+}
+
+type CronRemoveCmd struct {
+	ID string `arg:"" name:"id" help:"Job ID (see gog cron list)"`
+}
+
+func (c *CronRemoveCmd) Run(ctx context.Context) error {
+	u := ui.FromContext(ctx)
+	store, err := loadCronStore()
+	if err != nil {
+		return err
+	}
+	id := strings.TrimSpace(c.ID)
+	before := len(store.Jobs)
+	store.Jobs = slices.DeleteFunc(store.Jobs, func(j cronJob) bool { return j.ID == id })
+	if len(store.Jobs) == before {
+		return usagef("no cron job %q (see gog cron list)", id)
+	}
+	if err := store.save(); err != nil {
+		return err
+	}
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{"removed": true, "id": id})
+	}
+	u.Out().Printf("removed\t%s", id)
+	return nil
+}
+
+type CronRunCmd struct {
+	Once     bool          `name:"once" help:"Run the jobs that are due now, then exit (for launchd or system cron)"`
+	Interval time.Duration `name:"interval" help:"How often to check for due jobs" default:"30s"`
+}
+
+// runCronJob runs one job's command; tests replace it.
+var runCronJob = execCronJob
+
+func (c *CronRunCmd) Run(ctx context.Context) error {
+	u := ui.FromContext(ctx)
+	if c.Interval < time.Second {
+		return usage("--interval must be at least 1s")
+	}
+	if !c.Once {
+		u.Err().Printf("cron: checking every %s (Ctrl-C to stop)", c.Interval)
+	}
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+	for {
+		if err := runDueCronJobs(ctx, time.Now()); err != nil {
+			return err
+		}
+		if c.Once {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// runDueCronJobs runs every due job, one at a time, and records the result.
+// The store is reloaded before each write so jobs added or removed meanwhile
+// survive.
+func runDueCronJobs(ctx context.Context, now time.Time) error {
+	u := ui.FromContext(ctx)
+	store, err := loadCronStore()
+	if err != nil {
+		return err
+	}
+	for _, job := range store.Jobs {
+		if interrupted(ctx) {
+			return nil
+		}
+		sched, parseErr := cron.Parse(job.Schedule)
+		if parseErr != nil {
+			u.Err().Printf("cron: job %s: %v", job.ID, parseErr)
+			continue
+		}
+		if !cronDue(job, sched, now) {
+			continue
+		}
+
+		u.Err().Printf("cron: job %s: gog %s", job.ID, job.Command)
+		start := time.Now()
+		exitCode, runErr := runCronJob(ctx, job)
+		if interrupted(ctx) {
+			return nil // don't record a run the user cut short
+		}
+
+		latest, loadErr := loadCronStore()
+		if loadErr != nil {
+			return loadErr
+		}
+		if j := latest.find(job.ID); j != nil {
+			j.LastRun = now
+			j.LastExit = exitCode
+			j.LastError = ""
+			if runErr != nil {
+				j.LastError = runErr.Error()
+			}
+			if err := latest.save(); err != nil {
+				return err
+			}
+		}
+
+		data := map[string]any{
+			"job":        "cron",
+			"id":         job.ID,
+			"command":    job.Command,
+			"exitCode":   exitCode,
+			"durationMs": time.Since(start).Milliseconds(),
+		}
+		if runErr != nil {
+			data["error"] = runErr.Error()
+			u.Err().Printf("cron: job %s: %v", job.ID, runErr)
+		}
+		if emitErr := events.Emit(events.TypeJobFinished, job.Account, data); emitErr != nil {
+			u.Err().Printf("event log: %v", emitErr)
+		}
+	}
+	return nil
+}
+
+// execCronJob runs the job as a child gog process with the daemon's stdout
+// and stderr. A non-zero exit is reported as the exit code, not an error.
+func execCronJob(ctx context.Context, job cronJob) (int, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return -1, err
+	}
+	args := []string{"--no-input"}
+	if job.Account != "" {
+		args = append(args, "--account", job.Account)
+	}
+	args = append(args, job.Args...)
+	cmd := exec.CommandContext(ctx, exe, args...) //nolint:gosec // re-executing ourselves
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode(), nil
+		}
+		return -1, err
+	}
+	return 0, nil
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steipete/gogcli/internal/cron"
+	"github.com/steipete/gogcli/internal/events"
+)
+
+func setupCronTest(t *testing.T) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg-config"))
+	t.Setenv(events.EnvFile, filepath.Join(home, "events.ndjson"))
+}
+
+func TestCronAddListRemove(t *testing.T) {
+	setupCronTest(t)
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--json", "--account", "me@example.com", "cron", "add", "0 8 * * 1-5", `gog gmail search "is:unread newer_than:1d" --max 5`}); err != nil {
+			t.Fatalf("add: %v", err)
+		}
+	})
+	var added struct {
+		Job cronJob `json:"job"`
+	}
+	if err := json.Unmarshal([]byte(out), &added); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if added.Job.ID != "1" || added.Job.Account != "me@example.com" || strings.Join(added.Job.Args, "|") != "gmail|search|is:unread newer_than:1d|--max|5" {
+		t.Fatalf("job = %+v", added.Job)
+	}
+
+	for _, bad := range [][]string{
+		{"cron", "add", "0 8 * *", "status"},
+		{"cron", "add", "@daily", "gmail serch foo"},
+		{"cron", "add", "@daily", "cron list"},
+		{"cron", "add", "@daily", "status --help"},
+	} {
+		if err := Execute(bad); ExitCode(err) != 2 {
+			t.Fatalf("%v: err = %v, want usage error", bad, err)
+		}
+	}
+
+	out = captureStdout(t, func() {
+		if err := Execute([]string{"--json", "cron", "list"}); err != nil {
+			t.Fatalf("list: %v", err)
+		}
+	})
+	var listed struct {
+		Jobs []struct {
+			ID   string  `json:"id"`
+			Next *string `json:"next"`
+		} `json:"jobs"`
+	}
+	if err := json.Unmarshal([]byte(out), &listed); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if len(listed.Jobs) != 1 || listed.Jobs[0].Next == nil {
+		t.Fatalf("jobs = %s", out)
+	}
+
+	_ = captureStdout(t, func() {
+		if err := Execute([]string{"cron", "remove", "1"}); err != nil {
+			t.Fatalf("remove: %v", err)
+		}
+	})
+	if err := Execute([]string{"cron", "remove", "1"}); ExitCode(err) != 2 {
+		t.Fatalf("second remove err = %v", err)
+	}
+}
+
+func TestCronDueCatchesUpOnce(t *testing.T) {
+	sched, err := cron.Parse("0 8 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC)
+	job := cronJob{Created: created}
+	if cronDue(job, sched, created.Add(22*time.Hour)) {
+		t.Fatal("due before the first 08:00")
+	}
+	// Asleep through three mornings: one catch-up run, then wait for tomorrow.
+	woke := time.Date(2026, 10, 15, 10, 30, 0, 0, time.UTC)
+	if !cronDue(job, sched, woke) {
+		t.Fatal("missed runs not due")
+	}
+	job.LastRun = woke
+	if cronDue(job, sched, woke.Add(time.Hour)) {
+		t.Fatal("due again after catching up")
+	}
+	if !cronDue(job, sched, time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)) {
+		t.Fatal("next morning not due")
+	}
+}
+
+func TestCronRunOnce(t *testing.T) {
+	setupCronTest(t)
+	store, err := loadCronStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-48 * time.Hour)
+	store.Jobs = []cronJob{
+		{ID: "1", Schedule: "@hourly", Command: "status", Args: []string{"status"}, Account: "a@example.com", Created: past},
+		{ID: "2", Schedule: "0 0 30 2 *", Command: "status", Args: []string{"status"}, Created: past},
+	}
+	if err := store.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	var ran []string
+	orig := runCronJob
+	t.Cleanup(func() { runCronJob = orig })
+	runCronJob = func(_ context.Context, job cronJob) (int, error) {
+		ran = append(ran, job.ID)
+		return 3, nil
+	}
+	for range 2 {
+		if err := Execute([]string{"cron", "run", "--once"}); err != nil {
+			t.Fatalf("run: %v", err)
+		}
+	}
+	if strings.Join(ran, ",") != "1" {
+		t.Fatalf("ran = %v, want job 1 once", ran)
+	}
+
+	store, err = loadCronStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j := store.find("1"); j == nil || j.LastRun.IsZero() || j.LastExit != 3 {
+		t.Fatalf("job 1 = %+v", j)
+	}
+	log, err := os.ReadFile(os.Getenv(events.EnvFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(log), `"job":"cron"`) || !strings.Contains(string(log), `"exitCode":3`) {
+		t.Fatalf("event log = %s", log)
+	}
+}
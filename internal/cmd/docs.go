@@ -30,9 +30,9 @@ type DocsCmd struct {
 }
 
 type DocsExportCmd struct {
-	DocID  string         `arg:"" name:"docId" help:"Doc ID"`
-	Output OutputPathFlag `embed:""`
-	Format string         `name:"format" help:"Export format: pdf|docx|txt" default:"pdf"`
+	DocID  string           `arg:"" name:"docId" help:"Doc ID"`
+	Output ExportOutputFlag `embed:""`
+	Format string           `name:"format" help:"Export format: pdf|docx|txt" default:"pdf"`
 }
 
 func (c *DocsExportCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -56,7 +56,10 @@ type DriveCmd struct {
 	Share       DriveShareCmd       `cmd:"" name:"share" help:"Share a file or folder"`
 	Unshare     DriveUnshareCmd     `cmd:"" name:"unshare" help:"Remove a permission from a file"`
 	Permissions DrivePermissionsCmd `cmd:"" name:"permissions" help:"List permissions on a file"`
+	Du          DriveDuCmd          `cmd:"" name:"du" help:"Summarize space used per subfolder, recursively"`
+	Links       DriveLinksCmd       `cmd:"" name:"links" help:"Report link sharing type and expiry for every file in a folder"`
 	URL         DriveURLCmd         `cmd:"" name:"url" help:"Print web URLs for files"`
+	Email       DriveEmailCmd       `cmd:"" name:"email" help:"Email a file as an attachment or share link"`
 	Comments    DriveCommentsCmd    `cmd:"" name:"comments" help:"Manage comments on files"`
 	Drives      DriveDrivesCmd      `cmd:"" name:"drives" help:"List shared drives (Team Drives)"`
 }
@@ -247,9 +250,9 @@ func (c *DriveGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type DriveDownloadCmd struct {
-	FileID string         `arg:"" name:"fileId" help:"File ID"`
-	Output OutputPathFlag `embed:""`
-	Format string         `name:"format" help:"Export format for Google Docs files: pdf|csv|xlsx|pptx|txt|png|docx (default: auto)"`
+	FileID string           `arg:"" name:"fileId" help:"File ID"`
+	Output ExportOutputFlag `embed:""`
+	Format string           `name:"format" help:"Export format for Google Docs files: pdf|csv|xlsx|pptx|txt|png|docx (default: auto)"`
 }
 
 func (c *DriveDownloadCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -281,12 +284,13 @@ func (c *DriveDownloadCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return errors.New("file has no name")
 	}
 
-	destPath, err := resolveDriveDownloadDestPath(meta, c.Output.Path)
-	if err != nil {
-		return err
-	}
-
-	downloadedPath, size, err := downloadDriveFile(ctx, svc, meta, destPath, c.Format)
+	downloadedPath, size, err := writeToOutput(ctx, c.Output.Path, func(out string) (string, int64, error) {
+		destPath, destErr := resolveDriveDownloadDestPath(meta, out)
+		if destErr != nil {
+			return "", 0, destErr
+		}
+		return downloadDriveFile(ctx, svc, meta, destPath, c.Format)
+	})
 	if err != nil {
 		return err
 	}
@@ -316,12 +320,14 @@ func (c *DriveCopyCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type DriveUploadCmd struct {
-	LocalPath string `arg:"" name:"localPath" help:"Path to local file"`
-	Name      string `name:"name" help:"Override filename"`
-	Parent    string `name:"parent" help:"Destination folder ID"`
+	LocalPath   string          `arg:"" name:"localPath" help:"Path to local file ('-' streams stdin; requires --name)"`
+	Name        string          `name:"name" help:"Override filename"`
+	Parent      string          `name:"parent" help:"Destination folder ID"`
+	ChunkSize   int             `name:"chunk-size" help:"Resumable upload chunk size in MiB (buffered in memory per chunk)" default:"16"`
+	Idempotency IdempotencyFlag `embed:""`
 }
 
-func (c *DriveUploadCmd) Run(ctx context.Context, flags *RootFlags) error {
+func (c *DriveUploadCmd) Run(ctx context.Context, flags *RootFlags) (err error) {
 	u := ui.FromContext(ctx)
 	account, err := requireAccount(flags)
 	if err != nil {
@@ -332,21 +338,38 @@ func (c *DriveUploadCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if localPath == "" {
 		return usage("empty localPath")
 	}
-	localPath, err = config.ExpandPath(localPath)
-	if err != nil {
-		return err
+	if c.ChunkSize <= 0 {
+		return usage("--chunk-size must be positive")
 	}
 
-	f, err := os.Open(localPath) //nolint:gosec // user-provided path
-	if err != nil {
-		return err
+	fileName := strings.TrimSpace(c.Name)
+	var media io.Reader
+	if localPath == "-" {
+		if fileName == "" {
+			return usage("--name is required when uploading from stdin")
+		}
+		media = os.Stdin
+	} else {
+		localPath, err = config.ExpandPath(localPath)
+		if err != nil {
+			return err
+		}
+		f, openErr := os.Open(localPath) //nolint:gosec // user-provided path
+		if openErr != nil {
+			return openErr
+		}
+		defer f.Close()
+		media = f
+		if fileName == "" {
+			fileName = filepath.Base(localPath)
+		}
 	}
-	defer f.Close()
 
-	fileName := strings.TrimSpace(c.Name)
-	if fileName == "" {
-		fileName = filepath.Base(localPath)
+	idem, done, err := beginIdempotent(ctx, c.Idempotency.Key, account, "drive.upload")
+	if done || err != nil {
+		return err
 	}
+	defer idem.finish(&err)
 
 	svc, err := newDriveService(ctx, account)
 	if err != nil {
@@ -359,16 +382,22 @@ func (c *DriveUploadCmd) Run(ctx context.Context, flags *RootFlags) error {
 		meta.Parents = []string{parent}
 	}
 
+	// Readers of unknown length (pipes) go through a resumable session one
+	// chunk at a time, so only a single chunk is ever held in memory.
 	mimeType := guessMimeType(localPath)
+	if localPath == "-" {
+		mimeType = guessMimeType(fileName)
+	}
 	created, err := svc.Files.Create(meta).
 		SupportsAllDrives(true).
-		Media(f, gapi.ContentType(mimeType)).
+		Media(media, gapi.ContentType(mimeType), gapi.ChunkSize(c.ChunkSize<<20)).
 		Fields("id, name, mimeType, size, webViewLink").
 		Context(ctx).
 		Do()
 	if err != nil {
 		return err
 	}
+	idem.resource(created.Id)
 
 	if outfmt.IsJSON(ctx) {
 		return outfmt.WriteJSON(os.Stdout, map[string]any{strFile: created})
@@ -383,11 +412,12 @@ func (c *DriveUploadCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type DriveMkdirCmd struct {
-	Name   string `arg:"" name:"name" help:"Folder name"`
-	Parent string `name:"parent" help:"Parent folder ID"`
+	Name        string          `arg:"" name:"name" help:"Folder name"`
+	Parent      string          `name:"parent" help:"Parent folder ID"`
+	Idempotency IdempotencyFlag `embed:""`
 }
 
-func (c *DriveMkdirCmd) Run(ctx context.Context, flags *RootFlags) error {
+func (c *DriveMkdirCmd) Run(ctx context.Context, flags *RootFlags) (err error) {
 	u := ui.FromContext(ctx)
 	account, err := requireAccount(flags)
 	if err != nil {
@@ -399,6 +429,12 @@ func (c *DriveMkdirCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return usage("empty name")
 	}
 
+	idem, done, err := beginIdempotent(ctx, c.Idempotency.Key, account, "drive.mkdir")
+	if done || err != nil {
+		return err
+	}
+	defer idem.finish(&err)
+
 	svc, err := newDriveService(ctx, account)
 	if err != nil {
 		return err
@@ -420,6 +456,7 @@ func (c *DriveMkdirCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if err != nil {
 		return err
 	}
+	idem.resource(created.Id)
 
 	if outfmt.IsJSON(ctx) {
 		return outfmt.WriteJSON(os.Stdout, map[string]any{"folder": created})
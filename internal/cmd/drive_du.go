@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/api/drive/v3"
+
+	"github.com/steipete/gogcli/internal/errfmt"
+	"github.com/steipete/gogcli/internal/outfmt"
+)
+
+const (
+	driveDuNativeQuota = "quota"
+	driveMimeShortcut  = "application/vnd.google-apps.shortcut"
+)
+
+type DriveDuCmd struct {
+	Folder string `arg:"" name:"folder" optional:"" help:"Folder ID, or a path below My Drive starting with / (default: root)"`
+	Depth  int    `name:"depth" help:"Print subfolders down to this depth (totals always include everything below)" default:"1"`
+	Native string `name:"google-native" help:"Size of Google Docs/Sheets/Slides: zero (no byte size) or quota (storage they use)" enum:"zero,quota" default:"zero"`
+	Bytes  bool   `name:"bytes" short:"b" help:"Print sizes in bytes instead of human-readable units"`
+}
+
+// driveDuRow is one folder's recursive total.
+type driveDuRow struct {
+	ID          string `json:"id"`
+	Path        string `json:"path"`
+	Depth       int    `json:"depth"`
+	Bytes       int64  `json:"bytes"`
+	Files       int    `json:"files"`
+	NativeFiles int    `json:"nativeFiles"`
+}
+
+func (c *DriveDuCmd) Run(ctx context.Context, flags *RootFlags) error {
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	if c.Depth < 0 {
+		return usage("--depth must be >= 0")
+	}
+
+	svc, err := newDriveService(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	target := strings.TrimSpace(c.Folder)
+	folderID, label := target, target
+	switch {
+	case target == "" || target == "/":
+		folderID, label = "root", "/"
+	case strings.HasPrefix(target, "/"):
+		if folderID, err = resolveDriveFolderPath(ctx, svc, target); err != nil {
+			return err
+		}
+	default:
+		meta, getErr := svc.Files.Get(folderID).SupportsAllDrives(true).Fields("name, mimeType").Context(ctx).Do()
+		if getErr != nil {
+			return getErr
+		}
+		if meta.MimeType != driveFolderMimeType {
+			return usagef("%s is not a folder", folderID)
+		}
+		label = meta.Name
+	}
+
+	w := &driveDuWalker{svc: svc, native: c.Native, maxDepth: c.Depth, seen: map[string]bool{}}
+	if _, err := w.walk(ctx, folderID, label, 0); err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"folderId": folderID,
+			"total":    w.rows[len(w.rows)-1],
+			"folders":  w.rows,
+		})
+	}
+
+	tw, flush := tableWriter(ctx)
+	defer flush()
+	for _, r := range w.rows {
+		size := formatBytes(r.Bytes)
+		if c.Bytes {
+			size = fmt.Sprint(r.Bytes)
+		}
+		fmt.Fprintf(tw, "%s\t%d files\t%s\n", size, r.Files, sanitizeTab(r.Path))
+	}
+	return nil
+}
+
+type driveDuWalker struct {
+	svc      *drive.Service
+	native   string
+	maxDepth int
+	seen     map[string]bool
+	rows     []driveDuRow
+}
+
+// walk sums everything below folderID and records a row for folders within
+// maxDepth. Rows come out children-first, like du.
+func (w *driveDuWalker) walk(ctx context.Context, folderID, path string, depth int) (driveDuRow, error) {
+	row := driveDuRow{ID: folderID, Path: path, Depth: depth}
+	// A folder can have several parents; count it once.
+	if w.seen[folderID] {
+		return row, nil
+	}
+	w.seen[folderID] = true
+
+	prefix := strings.TrimSuffix(path, "/") + "/"
+	pageToken := ""
+	for {
+		call := w.svc.Files.List().
+			Q(buildDriveListQuery(folderID, "")).
+			PageSize(1000).
+			OrderBy("name").
+			SupportsAllDrives(true).
+			IncludeItemsFromAllDrives(true).
+			Fields("nextPageToken, files(id, name, mimeType, size, quotaBytesUsed)").
+			Context(ctx)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return row, err
+		}
+		for _, f := range resp.Files {
+			if f == nil {
+				continue
+			}
+			switch {
+			case f.MimeType == driveFolderMimeType:
+				child, err := w.walk(ctx, f.Id, prefix+f.Name, depth+1)
+				if err != nil {
+					return row, err
+				}
+				row.Bytes += child.Bytes
+				row.Files += child.Files
+				row.NativeFiles += child.NativeFiles
+			case f.MimeType == driveMimeShortcut:
+				// Shortcuts point elsewhere; following them would double count.
+			case strings.HasPrefix(f.MimeType, "application/vnd.google-apps."):
+				row.Files++
+				row.NativeFiles++
+				if w.native == driveDuNativeQuota {
+					row.Bytes += f.QuotaBytesUsed
+				}
+			default:
+				row.Files++
+				row.Bytes += f.Size
+			}
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+
+	if depth <= w.maxDepth {
+		w.rows = append(w.rows, row)
+	}
+	return row, nil
+}
+
+// resolveDriveFolderPath looks up a /-separated folder path below My Drive.
+func resolveDriveFolderPath(ctx context.Context, svc *drive.Service, folderPath string) (string, error) {
+	parent := "root"
+	for _, name := range strings.Split(folderPath, "/") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		id, err := lookupDriveFolder(ctx, svc, parent, name)
+		if err != nil {
+			return "", err
+		}
+		if id == "" {
+			return "", fmt.Errorf("no folder %q in %s: %w", name, folderPath, errfmt.ErrNotFound)
+		}
+		parent = id
+	}
+	return parent, nil
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+)
+
+func TestDriveDu(t *testing.T) {
+	origNew := newDriveService
+	t.Cleanup(func() { newDriveService = origNew })
+
+	children := map[string][]map[string]any{
+		"root": {
+			{"id": "reports", "name": "Reports", "mimeType": driveFolderMimeType},
+			{"id": "a", "name": "a.bin", "mimeType": "application/octet-stream", "size": "1000"},
+			{"id": "doc", "name": "Notes", "mimeType": driveMimeGoogleDoc, "quotaBytesUsed": "500"},
+		},
+		"reports": {
+			{"id": "y2024", "name": "2024", "mimeType": driveFolderMimeType},
+			{"id": "s", "name": "link", "mimeType": driveMimeShortcut},
+		},
+		"y2024": {
+			{"id": "b", "name": "b.pdf", "mimeType": mimePDF, "size": "2048"},
+		},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		q := r.URL.Query().Get("q")
+		if strings.Contains(q, "name = 'Reports'") {
+			_ = json.NewEncoder(w).Encode(map[string]any{"files": []map[string]any{{"id": "reports"}}})
+			return
+		}
+		for id, files := range children {
+			if strings.HasPrefix(q, "'"+id+"' in parents") {
+				_ = json.NewEncoder(w).Encode(map[string]any{"files": files})
+				return
+			}
+		}
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	svc, err := drive.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newDriveService = func(context.Context, string) (*drive.Service, error) { return svc, nil }
+
+	run := func(args ...string) string {
+		t.Helper()
+		return captureStdout(t, func() {
+			if err := Execute(append([]string{"--account", "a@b.com"}, args...)); err != nil {
+				t.Fatalf("Execute %v: %v", args, err)
+			}
+		})
+	}
+
+	var parsed struct {
+		Total   driveDuRow   `json:"total"`
+		Folders []driveDuRow `json:"folders"`
+	}
+	out := run("--json", "drive", "du", "--depth", "2")
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json parse: %v\n%s", err, out)
+	}
+	if parsed.Total.Bytes != 3048 || parsed.Total.Files != 3 || parsed.Total.NativeFiles != 1 {
+		t.Fatalf("unexpected total: %#v", parsed.Total)
+	}
+	if len(parsed.Folders) != 3 || parsed.Folders[0].Path != "/Reports/2024" || parsed.Folders[1].Bytes != 2048 {
+		t.Fatalf("unexpected folders: %#v", parsed.Folders)
+	}
+
+	out = run("drive", "du", "--google-native", "quota", "--depth", "0", "-b")
+	if strings.TrimSpace(out) != "3548  3 files  /" {
+		t.Fatalf("unexpected quota output: %q", out)
+	}
+
+	out = run("drive", "du", "/Reports", "--depth", "0")
+	if !strings.Contains(out, "2.0 KB") || !strings.Contains(out, "/Reports") {
+		t.Fatalf("unexpected path output: %q", out)
+	}
+}
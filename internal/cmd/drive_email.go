@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"os"
+	"strings"
+
+	"google.golang.org/api/drive/v3"
+
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+const (
+	// Gmail rejects messages over 25 MB, measured after base64 encoding.
+	gmailMaxMessageBytes = 25 << 20
+	// Headroom for headers, body text and MIME boundaries.
+	gmailMessageOverheadBytes = 64 << 10
+
+	driveEmailModeAttachment = "attachment"
+	driveEmailModeLink       = "link"
+)
+
+// gmailMaxAttachmentBytes is the largest raw file that still fits in one message.
+const gmailMaxAttachmentBytes = (gmailMaxMessageBytes - gmailMessageOverheadBytes) / 4 * 3
+
+type DriveEmailCmd struct {
+	FileID       string        `arg:"" name:"fileId" help:"File ID"`
+	To           string        `name:"to" help:"Recipients (comma-separated) (required)"`
+	Cc           string        `name:"cc" help:"CC recipients (comma-separated)"`
+	Bcc          string        `name:"bcc" help:"BCC recipients (comma-separated)"`
+	Subject      string        `name:"subject" help:"Subject (default: file name)"`
+	Body         string        `name:"body" help:"Message body (plain text)"`
+	BodyFile     string        `name:"body-file" help:"Body file path (plain text; '-' for stdin)"`
+	AsAttachment bool          `name:"as-attachment" help:"Attach the file (fails if it exceeds the 25 MB Gmail limit)"`
+	AsLink       bool          `name:"as-link" help:"Send a share link and grant recipients access"`
+	Role         string        `name:"role" help:"Link mode permission: reader|commenter|writer" default:"reader"`
+	Anyone       bool          `name:"anyone" help:"Link mode: share with anyone who has the link instead of per recipient"`
+	Format       string        `name:"format" help:"Export format for Google Docs files (pdf|docx|txt|csv|xlsx|pptx|png)"`
+	Guardrail    GuardrailFlag `embed:""`
+}
+
+func (c *DriveEmailCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+
+	fileID := strings.TrimSpace(c.FileID)
+	if fileID == "" {
+		return usage("empty fileId")
+	}
+	toRecipients := splitCSV(c.To)
+	if len(toRecipients) == 0 {
+		return usage("required: --to")
+	}
+	if c.AsAttachment && c.AsLink {
+		return usage("use only one of --as-attachment or --as-link")
+	}
+	role := strings.TrimSpace(c.Role)
+	if role != "reader" && role != "commenter" && role != "writer" {
+		return usage("invalid --role (expected reader|commenter|writer)")
+	}
+	body, err := resolveBodyInput(c.Body, c.BodyFile)
+	if err != nil {
+		return err
+	}
+	ccRecipients := splitCSV(c.Cc)
+	bccRecipients := splitCSV(c.Bcc)
+	// Headers keep display names; Drive permissions need the bare address.
+	shareWith, err := bareAddresses(append(append(append([]string{}, toRecipients...), ccRecipients...), bccRecipients...))
+	if err != nil {
+		return err
+	}
+
+	dsvc, err := newDriveService(ctx, account)
+	if err != nil {
+		return err
+	}
+	meta, err := dsvc.Files.Get(fileID).
+		SupportsAllDrives(true).
+		Fields("id, name, mimeType, size, webViewLink").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return err
+	}
+
+	mode := driveEmailModeAttachment
+	if c.AsLink {
+		mode = driveEmailModeLink
+	}
+
+	var attachment *mailAttachment
+	if mode == driveEmailModeAttachment {
+		attachment, err = downloadDriveAttachment(ctx, dsvc, meta, c.Format)
+		if err != nil {
+			return err
+		}
+		if attachment == nil {
+			if c.AsAttachment {
+				return usagef("%s is larger than the Gmail attachment limit (%s); use --as-link", meta.Name, formatBytes(gmailMaxAttachmentBytes))
+			}
+			u.Err().Printf("%s exceeds the Gmail attachment limit; sending a share link instead", meta.Name)
+			mode = driveEmailModeLink
+		}
+	}
+
+	// Checked before any access is granted; a share link is not an attachment.
+	if err = enforceSendGuardrails(ctx, flags, account, c.Guardrail, sendGuardrailCheck{
+		Recipients:     append(append(append([]string{}, toRecipients...), ccRecipients...), bccRecipients...),
+		HasAttachments: mode == driveEmailModeAttachment,
+	}); err != nil {
+		return err
+	}
+
+	var (
+		link        string
+		permissions []string
+	)
+	if mode == driveEmailModeLink {
+		permissions, err = grantDriveEmailAccess(ctx, dsvc, fileID, role, c.Anyone, shareWith)
+		if err != nil {
+			return err
+		}
+		link = meta.WebViewLink
+		if link == "" {
+			link = fmt.Sprintf("https://drive.google.com/file/d/%s/view", fileID)
+		}
+	}
+
+	subject := strings.TrimSpace(c.Subject)
+	if subject == "" {
+		subject = meta.Name
+	}
+	body = driveEmailBody(body, meta.Name, link)
+
+	var atts []mailAttachment
+	if attachment != nil {
+		atts = append(atts, *attachment)
+	}
+
+	gsvc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+	results, err := sendGmailBatches(ctx, gsvc, sendMessageOptions{
+		FromAddr:    account,
+		Subject:     subject,
+		Body:        body,
+		Attachments: atts,
+	}, []sendBatch{{To: toRecipients, Cc: ccRecipients, Bcc: bccRecipients}})
+	if err != nil {
+		return err
+	}
+	sent := results[0]
+
+	if outfmt.IsJSON(ctx) {
+		resp := map[string]any{
+			"messageId": sent.MessageID,
+			"threadId":  sent.ThreadID,
+			"fileId":    fileID,
+			"name":      meta.Name,
+			"mode":      mode,
+		}
+		if link != "" {
+			resp["link"] = link
+			resp["permissionIds"] = permissions
+		}
+		return outfmt.WriteJSON(os.Stdout, resp)
+	}
+
+	u.Out().Printf("message_id\t%s", sent.MessageID)
+	if sent.ThreadID != "" {
+		u.Out().Printf("thread_id\t%s", sent.ThreadID)
+	}
+	u.Out().Printf("mode\t%s", mode)
+	if link != "" {
+		u.Out().Printf("link\t%s", link)
+	}
+	return nil
+}
+
+// downloadDriveAttachment reads the file (exporting Google Docs formats) into
+// memory. It returns nil without error when the file is too large to attach.
+func downloadDriveAttachment(ctx context.Context, svc *drive.Service, meta *drive.File, format string) (*mailAttachment, error) {
+	filename := meta.Name
+	mimeType := meta.MimeType
+
+	var (
+		resp *http.Response
+		err  error
+	)
+	if strings.HasPrefix(meta.MimeType, "application/vnd.google-apps.") {
+		exportMimeType, mimeErr := driveExportMimeTypeForFormat(meta.MimeType, format)
+		if mimeErr != nil {
+			return nil, usage(mimeErr.Error())
+		}
+		filename = replaceExt(meta.Name, driveExportExtension(exportMimeType))
+		mimeType = exportMimeType
+		resp, err = driveExportDownload(ctx, svc, meta.Id, exportMimeType)
+	} else {
+		if strings.TrimSpace(format) != "" {
+			return nil, usage("--format is only supported for Google Docs files")
+		}
+		if meta.Size > gmailMaxAttachmentBytes {
+			return nil, nil
+		}
+		resp, err = driveDownload(ctx, svc, meta.Id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("download failed: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	// Exports have no size up front, so read one byte past the limit to detect overflow.
+	data, err := io.ReadAll(io.LimitReader(resp.Body, gmailMaxAttachmentBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > gmailMaxAttachmentBytes {
+		return nil, nil
+	}
+	return &mailAttachment{Filename: filename, MIMEType: mimeType, Data: data}, nil
+}
+
+// bareAddresses reduces recipients such as "Ada <ada@example.com>" to their
+// addresses, deduplicated.
+func bareAddresses(recipients []string) ([]string, error) {
+	out := make([]string, 0, len(recipients))
+	for _, r := range recipients {
+		addr, err := mail.ParseAddress(r)
+		if err != nil {
+			return nil, usagef("invalid recipient %q: %v", r, err)
+		}
+		out = append(out, addr.Address)
+	}
+	return deduplicateAddresses(out), nil
+}
+
+// grantDriveEmailAccess shares the file with each recipient (or anyone with
+// the link) without Drive's own notification email, since we send our own.
+func grantDriveEmailAccess(ctx context.Context, svc *drive.Service, fileID, role string, anyone bool, recipients []string) ([]string, error) {
+	perms := []*drive.Permission{}
+	if anyone {
+		perms = append(perms, &drive.Permission{Type: "anyone", Role: role})
+	} else {
+		for _, r := range recipients {
+			perms = append(perms, &drive.Permission{Type: "user", Role: role, EmailAddress: r})
+		}
+	}
+
+	ids := make([]string, 0, len(perms))
+	for _, perm := range perms {
+		created, err := svc.Permissions.Create(fileID, perm).
+			SupportsAllDrives(true).
+			SendNotificationEmail(false).
+			Fields("id").
+			Context(ctx).
+			Do()
+		if err != nil {
+			if perm.EmailAddress != "" {
+				return nil, fmt.Errorf("share with %s: %w", perm.EmailAddress, err)
+			}
+			return nil, err
+		}
+		ids = append(ids, created.Id)
+	}
+	return ids, nil
+}
+
+func driveEmailBody(body, name, link string) string {
+	body = strings.TrimRight(body, "\r\n ")
+	if link != "" {
+		line := fmt.Sprintf("%s: %s", name, link)
+		if body == "" {
+			return line + "\n"
+		}
+		return body + "\n\n" + line + "\n"
+	}
+	if body == "" {
+		return fmt.Sprintf("Attached: %s\n", name)
+	}
+	return body + "\n"
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+type driveEmailFixture struct {
+	size        int64
+	sentRaw     string
+	permissions []drive.Permission
+}
+
+func newDriveEmailFixture(t *testing.T, size int64) *driveEmailFixture {
+	t.Helper()
+	fx := &driveEmailFixture{size: size}
+
+	origDrive := newDriveService
+	origDownload := driveDownload
+	t.Cleanup(func() {
+		newDriveService = origDrive
+		driveDownload = origDownload
+	})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/files/f1"):
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id":          "f1",
+				"name":        "report.pdf",
+				"mimeType":    "application/pdf",
+				"size":        strconv.FormatInt(fx.size, 10),
+				"webViewLink": "https://drive.example/f1",
+			})
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/files/f1/permissions"):
+			var p drive.Permission
+			_ = json.NewDecoder(r.Body).Decode(&p)
+			if r.URL.Query().Get("sendNotificationEmail") != "false" {
+				t.Errorf("expected sendNotificationEmail=false")
+			}
+			fx.permissions = append(fx.permissions, p)
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "p" + p.EmailAddress})
+		case r.Method == http.MethodPost && strings.Contains(r.URL.Path, "/gmail/v1/users/me/messages/send"):
+			var msg gmail.Message
+			_ = json.NewDecoder(r.Body).Decode(&msg)
+			raw, err := base64.RawURLEncoding.DecodeString(msg.Raw)
+			if err != nil {
+				t.Fatalf("decode raw: %v", err)
+			}
+			fx.sentRaw = string(raw)
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "s1", "threadId": "t1"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	stubGmailService(t, srv)
+
+	dsvc, err := drive.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newDriveService = func(context.Context, string) (*drive.Service, error) { return dsvc, nil }
+	driveDownload = func(context.Context, *drive.Service, string) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Status: "200 OK", Body: io.NopCloser(strings.NewReader("%PDF-1.4 data"))}, nil
+	}
+	return fx
+}
+
+func TestDriveEmail_Attachment(t *testing.T) {
+	fx := newDriveEmailFixture(t, 13)
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--json", "--account", "a@b.com", "drive", "email", "f1", "--to", "x@example.com"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	if !strings.Contains(out, `"mode": "attachment"`) {
+		t.Fatalf("unexpected output: %s", out)
+	}
+	for _, want := range []string{"Subject: report.pdf", "Content-Type: application/pdf", base64.StdEncoding.EncodeToString([]byte("%PDF-1.4 data"))} {
+		if !strings.Contains(fx.sentRaw, want) {
+			t.Fatalf("raw missing %q:\n%s", want, fx.sentRaw)
+		}
+	}
+	if len(fx.permissions) != 0 {
+		t.Fatalf("attachment mode must not share: %#v", fx.permissions)
+	}
+}
+
+func TestDriveEmail_TooLargeFallsBackToLink(t *testing.T) {
+	fx := newDriveEmailFixture(t, gmailMaxAttachmentBytes+1)
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "a@b.com", "drive", "email", "f1", "--to", "X Person <x@example.com>", "--cc", "y@example.com,Y Again <Y@example.com>", "--body", "Here you go"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if !strings.Contains(out, `"mode": "link"`) {
+		t.Fatalf("unexpected output: %s", out)
+	}
+	// Display names are dropped and duplicates merged before sharing.
+	if len(fx.permissions) != 2 || fx.permissions[0].EmailAddress != "x@example.com" || fx.permissions[1].EmailAddress != "y@example.com" || fx.permissions[1].Role != "reader" {
+		t.Fatalf("unexpected permissions: %#v", fx.permissions)
+	}
+	if !strings.Contains(fx.sentRaw, "Here you go") || !strings.Contains(fx.sentRaw, "report.pdf: https://drive.example/f1") {
+		t.Fatalf("raw missing link:\n%s", fx.sentRaw)
+	}
+}
+
+func TestDriveEmail_AsAttachmentTooLarge(t *testing.T) {
+	fx := newDriveEmailFixture(t, gmailMaxAttachmentBytes+1)
+
+	_ = captureStderr(t, func() {
+		err := Execute([]string{"--account", "a@b.com", "drive", "email", "f1", "--to", "x@example.com", "--as-attachment"})
+		if err == nil || ExitCode(err) != 2 {
+			t.Fatalf("expected usage error, got %v", err)
+		}
+	})
+	if fx.sentRaw != "" {
+		t.Fatalf("nothing should be sent")
+	}
+}
@@ -0,0 +1,293 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+
+	"google.golang.org/api/drive/v3"
+
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// Link audiences, from most to least exposed.
+const (
+	driveLinkPublic         = "public"
+	driveLinkAnyoneWithLink = "anyone-with-link"
+	driveLinkDomain         = "domain"
+	driveLinkDomainWithLink = "domain-with-link"
+	driveLinkRestricted     = "restricted"
+)
+
+const driveLinksPermissionFields = "permissions(id, type, role, domain, emailAddress, allowFileDiscovery, expirationTime)"
+
+type DriveLinksCmd struct {
+	Folder    string `name:"folder" help:"Folder ID (default: root)"`
+	Recursive bool   `name:"recursive" short:"r" help:"Include files in subfolders"`
+	Folders   bool   `name:"include-folders" help:"Also list the folders themselves"`
+	CSV       bool   `name:"csv" help:"Write the report as CSV to stdout"`
+	Shorten   string `name:"shorten" help:"Shell command that shortens a link: it gets the URL on stdin and in $GOG_LINK_URL and prints the short link"`
+}
+
+// driveLinkRow is one file's sharing state.
+type driveLinkRow struct {
+	ID         string `json:"id"`
+	Path       string `json:"path"`
+	MimeType   string `json:"mimeType"`
+	Link       string `json:"link"`
+	ShortLink  string `json:"shortLink,omitempty"`
+	LinkType   string `json:"linkType"`
+	Role       string `json:"role,omitempty"`
+	Domain     string `json:"domain,omitempty"`
+	SharedWith int    `json:"sharedWith"`
+	Expires    string `json:"expires,omitempty"`
+}
+
+// runLinkShortener is swapped out in tests.
+var runLinkShortener = func(ctx context.Context, command, url string, row driveLinkRow) (string, error) {
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+	cmd := exec.CommandContext(ctx, shell, flag, command) //nolint:gosec // user-supplied hook
+	cmd.Stdin = strings.NewReader(url + "\n")
+	cmd.Env = append(os.Environ(),
+		"GOG_LINK_URL="+url,
+		"GOG_FILE_ID="+row.ID,
+		"GOG_FILE_NAME="+row.Path,
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("shorten %s: %w: %s", row.Path, err, strings.TrimSpace(stderr.String()))
+	}
+	short, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	if short == "" {
+		return "", fmt.Errorf("shorten %s: command printed nothing", row.Path)
+	}
+	return strings.TrimSpace(short), nil
+}
+
+func (c *DriveLinksCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	folderID := strings.TrimSpace(c.Folder)
+	if folderID == "" {
+		folderID = "root"
+	}
+	if c.CSV && outfmt.IsJSON(ctx) {
+		return usage("use only one of --csv or --json")
+	}
+
+	svc, err := newDriveService(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	var rows []driveLinkRow
+	if err := c.collect(ctx, svc, folderID, "", &rows); err != nil {
+		return err
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].Path < rows[j].Path })
+
+	if command := strings.TrimSpace(c.Shorten); command != "" {
+		for i := range rows {
+			if rows[i].Link == "" {
+				continue
+			}
+			short, shortErr := runLinkShortener(ctx, command, rows[i].Link, rows[i])
+			if shortErr != nil {
+				return shortErr
+			}
+			rows[i].ShortLink = short
+		}
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"folderId": folderID,
+			"files":    rows,
+			"count":    len(rows),
+		})
+	}
+	if c.CSV {
+		return writeDriveLinksCSV(rows, c.Shorten != "")
+	}
+	if len(rows) == 0 {
+		u.Err().Println("No files")
+		return nil
+	}
+
+	w, flush := tableWriter(ctx)
+	defer flush()
+	header := "PATH\tLINK_TYPE\tROLE\tSHARED_WITH\tEXPIRES\tLINK"
+	if c.Shorten != "" {
+		header += "\tSHORT"
+	}
+	fmt.Fprintln(w, header)
+	for _, r := range rows {
+		role := r.Role
+		if role == "" {
+			role = "-"
+		}
+		line := fmt.Sprintf("%s\t%s\t%s\t%d\t%s\t%s",
+			sanitizeTab(r.Path), r.LinkType, role, r.SharedWith, formatDateTime(r.Expires), r.Link)
+		if c.Shorten != "" {
+			line += "\t" + r.ShortLink
+		}
+		fmt.Fprintln(w, line)
+	}
+	return nil
+}
+
+func (c *DriveLinksCmd) collect(ctx context.Context, svc *drive.Service, folderID, prefix string, rows *[]driveLinkRow) error {
+	pageToken := ""
+	for {
+		call := svc.Files.List().
+			Q(buildDriveListQuery(folderID, "")).
+			PageSize(1000).
+			OrderBy("name").
+			SupportsAllDrives(true).
+			IncludeItemsFromAllDrives(true).
+			Fields("nextPageToken, files(id, name, mimeType, webViewLink, " + driveLinksPermissionFields + ")").
+			Context(ctx)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return err
+		}
+		for _, f := range resp.Files {
+			if f == nil {
+				continue
+			}
+			path := prefix + f.Name
+			isFolder := f.MimeType == driveFolderMimeType
+			if !isFolder || c.Folders {
+				perms := f.Permissions
+				if perms == nil {
+					// Shared drive listings omit permissions; ask per file.
+					if perms, err = listDrivePermissions(ctx, svc, f.Id); err != nil {
+						return fmt.Errorf("permissions for %s: %w", path, err)
+					}
+				}
+				row := driveLinkRowFor(perms)
+				row.ID = f.Id
+				row.Path = path
+				row.MimeType = f.MimeType
+				row.Link = f.WebViewLink
+				*rows = append(*rows, row)
+			}
+			if isFolder && c.Recursive {
+				if err := c.collect(ctx, svc, f.Id, path+"/", rows); err != nil {
+					return err
+				}
+			}
+		}
+		if resp.NextPageToken == "" {
+			return nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+func listDrivePermissions(ctx context.Context, svc *drive.Service, fileID string) ([]*drive.Permission, error) {
+	var out []*drive.Permission
+	pageToken := ""
+	for {
+		call := svc.Permissions.List(fileID).
+			SupportsAllDrives(true).
+			Fields("nextPageToken, " + driveLinksPermissionFields).
+			Context(ctx)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, resp.Permissions...)
+		if resp.NextPageToken == "" {
+			return out, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// driveLinkRowFor classifies who can open the file by link and when the
+// earliest individual grant expires.
+func driveLinkRowFor(perms []*drive.Permission) driveLinkRow {
+	row := driveLinkRow{LinkType: driveLinkRestricted}
+	rank := map[string]int{
+		driveLinkPublic:         4,
+		driveLinkAnyoneWithLink: 3,
+		driveLinkDomain:         2,
+		driveLinkDomainWithLink: 1,
+		driveLinkRestricted:     0,
+	}
+	for _, p := range perms {
+		if p == nil {
+			continue
+		}
+		var linkType string
+		switch p.Type {
+		case "anyone":
+			linkType = driveLinkAnyoneWithLink
+			if p.AllowFileDiscovery {
+				linkType = driveLinkPublic
+			}
+		case "domain":
+			linkType = driveLinkDomainWithLink
+			if p.AllowFileDiscovery {
+				linkType = driveLinkDomain
+			}
+		default:
+			if p.Role != "owner" {
+				row.SharedWith++
+			}
+		}
+		if linkType != "" && rank[linkType] > rank[row.LinkType] {
+			row.LinkType = linkType
+			row.Role = p.Role
+			row.Domain = p.Domain
+		}
+		if p.ExpirationTime != "" && (row.Expires == "" || p.ExpirationTime < row.Expires) {
+			row.Expires = p.ExpirationTime
+		}
+	}
+	return row
+}
+
+func writeDriveLinksCSV(rows []driveLinkRow, withShort bool) error {
+	cw := csv.NewWriter(os.Stdout)
+	header := []string{"id", "path", "link_type", "role", "domain", "shared_with", "expires", "link"}
+	if withShort {
+		header = append(header, "short_link")
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		record := []string{r.ID, r.Path, r.LinkType, r.Role, r.Domain, fmt.Sprint(r.SharedWith), r.Expires, r.Link}
+		if withShort {
+			record = append(record, r.ShortLink)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+)
+
+func TestDriveLinksCmd(t *testing.T) {
+	origNew, origShorten := newDriveService, runLinkShortener
+	t.Cleanup(func() { newDriveService, runLinkShortener = origNew, origShorten })
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		q := r.URL.Query().Get("q")
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/files") && strings.Contains(q, "'root' in parents"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"files": []map[string]any{
+				{
+					"id": "f1", "name": "Brief.pdf", "mimeType": "application/pdf", "webViewLink": "https://drive.example/f1",
+					"permissions": []map[string]any{
+						{"id": "o", "type": "user", "role": "owner", "emailAddress": "me@example.com"},
+						{"id": "a", "type": "anyone", "role": "reader"},
+						{"id": "u", "type": "user", "role": "commenter", "emailAddress": "x@example.com", "expirationTime": "2026-12-01T00:00:00Z"},
+					},
+				},
+				{"id": "sub", "name": "Handouts", "mimeType": driveFolderMimeType, "permissions": []map[string]any{}},
+			}})
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/files") && strings.Contains(q, "'sub' in parents"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"files": []map[string]any{
+				{"id": "f2", "name": "Notes", "mimeType": driveMimeGoogleDoc, "webViewLink": "https://drive.example/f2"},
+			}})
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/files/f2/permissions"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"permissions": []map[string]any{
+				{"id": "d", "type": "domain", "role": "writer", "domain": "example.com"},
+			}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	svc, err := drive.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newDriveService = func(context.Context, string) (*drive.Service, error) { return svc, nil }
+	runLinkShortener = func(_ context.Context, command, url string, row driveLinkRow) (string, error) {
+		if command != "shorten" {
+			t.Fatalf("unexpected command %q", command)
+		}
+		return "https://s.example/" + row.ID, nil
+	}
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--json", "--account", "a@b.com", "drive", "links", "-r"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	var parsed struct {
+		Files []driveLinkRow `json:"files"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if len(parsed.Files) != 2 {
+		t.Fatalf("expected 2 files, got %#v", parsed.Files)
+	}
+	if got := parsed.Files[0]; got.Path != "Brief.pdf" || got.LinkType != driveLinkAnyoneWithLink || got.Role != "reader" ||
+		got.SharedWith != 1 || got.Expires != "2026-12-01T00:00:00Z" {
+		t.Fatalf("unexpected first row: %#v", got)
+	}
+	if got := parsed.Files[1]; got.Path != "Handouts/Notes" || got.LinkType != driveLinkDomainWithLink || got.Domain != "example.com" {
+		t.Fatalf("unexpected second row: %#v", got)
+	}
+
+	out = captureStdout(t, func() {
+		if err := Execute([]string{"--account", "a@b.com", "drive", "links", "--csv", "--shorten", "shorten"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 || !strings.HasSuffix(lines[0], ",short_link") ||
+		lines[1] != "f1,Brief.pdf,anyone-with-link,reader,,1,2026-12-01T00:00:00Z,https://drive.example/f1,https://s.example/f1" {
+		t.Fatalf("unexpected csv:\n%s", out)
+	}
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+)
+
+func TestDriveUploadStdinResumable(t *testing.T) {
+	origNew := newDriveService
+	t.Cleanup(func() { newDriveService = origNew })
+
+	var (
+		received   bytes.Buffer
+		chunks     int
+		uploadType string
+		gotName    string
+	)
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.Path, "/upload/drive/v3/files"):
+			uploadType = r.URL.Query().Get("uploadType")
+			var meta drive.File
+			_ = json.NewDecoder(r.Body).Decode(&meta)
+			gotName = meta.Name
+			w.Header().Set("Location", srv.URL+"/session/1")
+			w.WriteHeader(http.StatusOK)
+		case r.URL.Path == "/session/1":
+			chunks++
+			_, _ = io.Copy(&received, r.Body)
+			// Unknown total length shows up as "bytes a-b/*" until the last chunk.
+			if strings.HasSuffix(r.Header.Get("Content-Range"), "/*") {
+				// The client opts out of 308 via X-GUploader-No-308.
+				w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", received.Len()-1))
+				w.Header().Set("X-Http-Status-Code-Override", "308")
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "big1", "name": gotName})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	svc, err := drive.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newDriveService = func(context.Context, string) (*drive.Service, error) { return svc, nil }
+
+	payload := bytes.Repeat([]byte("0123456789abcdef"), (5<<20)/16+1000) // ~5 MiB
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe: %v", err)
+	}
+	origStdin := os.Stdin
+	os.Stdin = r
+	t.Cleanup(func() { os.Stdin = origStdin })
+	go func() {
+		_, _ = w.Write(payload)
+		_ = w.Close()
+	}()
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--account", "a@b.com", "drive", "upload", "-", "--name", "backup.sql.gz", "--chunk-size", "2"}); err != nil {
+			t.Fatalf("upload: %v", err)
+		}
+	})
+	if !strings.Contains(out, "big1") {
+		t.Fatalf("unexpected output: %q", out)
+	}
+	if uploadType != "resumable" || gotName != "backup.sql.gz" {
+		t.Fatalf("uploadType=%q name=%q", uploadType, gotName)
+	}
+	if chunks != 3 || !bytes.Equal(received.Bytes(), payload) {
+		t.Fatalf("chunks=%d received=%d bytes, want %d", chunks, received.Len(), len(payload))
+	}
+
+	if err := Execute([]string{"--account", "a@b.com", "drive", "upload", "-"}); err == nil || !strings.Contains(err.Error(), "--name") {
+		t.Fatalf("expected --name usage error, got %v", err)
+	}
+}
@@ -156,7 +156,7 @@ func TestDriveDownload_TextOutput(t *testing.T) {
 	flags := &RootFlags{Account: "a@b.com"}
 
 	dest := filepath.Join(t.TempDir(), "out.txt")
-	cmd := &DriveDownloadCmd{FileID: "file1", Output: OutputPathFlag{Path: dest}}
+	cmd := &DriveDownloadCmd{FileID: "file1", Output: ExportOutputFlag{Path: dest}}
 	if err := cmd.Run(ctx, flags); err != nil {
 		t.Fatalf("Run: %v", err)
 	}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/steipete/gogcli/internal/events"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+type EventsCmd struct {
+	Tail EventsTailCmd `cmd:"" name:"tail" help:"Print (and follow) the NDJSON event log written by gog daemons"`
+}
+
+type EventsTailCmd struct {
+	Follow bool     `name:"follow" short:"f" help:"Keep reading as new events are appended"`
+	Lines  int      `name:"lines" short:"n" help:"Number of past events to print first" default:"10"`
+	Type   []string `name:"type" sep:"," help:"Only these event types; prefixes match (gmail, job.finished)"`
+	Path   string   `name:"path" help:"Event log to read (default: $GOG_EVENTS_FILE or the state dir)"`
+}
+
+func (c *EventsTailCmd) Run(ctx context.Context) error {
+	u := ui.FromContext(ctx)
+	if c.Lines < 0 {
+		return usage("--lines must be >= 0")
+	}
+	path := strings.TrimSpace(c.Path)
+	if path == "" {
+		var err error
+		if path, err = events.Path(); err != nil {
+			return err
+		}
+		if path == "" {
+			return usagef("event log disabled (%s=off)", events.EnvFile)
+		}
+	}
+
+	var filters []string
+	for _, t := range c.Type {
+		if t = strings.TrimSpace(t); t != "" {
+			filters = append(filters, t)
+		}
+	}
+	if c.Follow {
+		u.Err().Printf("events: following %s", path)
+	}
+	return events.Tail(ctx, path, c.Lines, c.Follow, filters, func(line []byte) error {
+		_, err := os.Stdout.Write(append(line, '\n'))
+		return err
+	})
+}
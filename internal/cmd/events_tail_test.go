@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/events"
+)
+
+func TestEventsTail_TypeFilter(t *testing.T) {
+	t.Setenv(events.EnvFile, filepath.Join(t.TempDir(), "events.ndjson"))
+	_ = events.Emit(events.TypeGmailMessageReceived, "a@b.com", map[string]any{"id": "m1"})
+	_ = events.Emit(events.TypeJobFinished, "a@b.com", map[string]any{"job": "gmail.snooze.wake"})
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"events", "tail", "--type", "job"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 1 || !strings.Contains(lines[0], `"type":"job.finished"`) {
+		t.Fatalf("unexpected output: %q", out)
+	}
+}
+
+func TestEventsTail_Disabled(t *testing.T) {
+	t.Setenv(events.EnvFile, "off")
+	if err := Execute([]string{"events", "tail"}); err == nil || ExitCode(err) != 2 {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+}
@@ -1,6 +1,10 @@
 package cmd
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/steipete/gogcli/internal/errfmt"
+)
 
 type ExitError struct {
 	Code int
@@ -21,6 +25,8 @@ func (e *ExitError) Unwrap() error {
 	return e.Err
 }
 
+// ExitCode maps err to the process exit code. Explicit ExitError codes win;
+// everything else follows the errfmt taxonomy (auth=3, not-found=4, quota=5).
 func ExitCode(err error) int {
 	if err == nil {
 		return 0
@@ -32,5 +38,18 @@ func ExitCode(err error) int {
 		}
 		return ee.Code
 	}
-	return 1
+	return errfmt.Classify(err).ExitCode
+}
+
+// classifyError is errfmt.Classify adjusted for explicit ExitError codes.
+func classifyError(err error) errfmt.Classification {
+	info := errfmt.Classify(err)
+	var ee *ExitError
+	if errors.As(err, &ee) && ee != nil {
+		info.ExitCode = ExitCode(err)
+		if info.ExitCode == errfmt.ExitUsage {
+			info.Code = errfmt.CodeUsage
+		}
+	}
+	return info
 }
@@ -1,8 +1,15 @@
 package cmd
 
 import (
+	"encoding/json"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+
+	"google.golang.org/api/googleapi"
+
+	"github.com/steipete/gogcli/internal/errfmt"
 )
 
 func TestExitError(t *testing.T) {
@@ -29,3 +36,64 @@ func TestExitCode(t *testing.T) {
 		t.Fatalf("expected 5")
 	}
 }
+
+func TestExitCode_Taxonomy(t *testing.T) {
+	if got := ExitCode(&googleapi.Error{Code: http.StatusNotFound}); got != errfmt.ExitNotFound {
+		t.Fatalf("404 exit=%d", got)
+	}
+	if got := ExitCode(&googleapi.Error{Code: http.StatusTooManyRequests}); got != errfmt.ExitQuota {
+		t.Fatalf("429 exit=%d", got)
+	}
+	// Explicit exit codes still win over the classified error.
+	if got := ExitCode(&ExitError{Code: 2, Err: &googleapi.Error{Code: http.StatusNotFound}}); got != 2 {
+		t.Fatalf("explicit exit=%d", got)
+	}
+}
+
+func TestExecute_JSONErrorOutput(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":{"code":404,"message":"Requested entity was not found.","errors":[{"reason":"notFound"}]}}`))
+	}))
+	t.Cleanup(srv.Close)
+	stubGmailService(t, srv)
+
+	var execErr error
+	stderr := captureStderr(t, func() {
+		_ = captureStdout(t, func() {
+			execErr = Execute([]string{"--json", "--account", "a@b.com", "gmail", "get", "missing"})
+		})
+	})
+	if ExitCode(execErr) != errfmt.ExitNotFound {
+		t.Fatalf("exit=%d err=%v", ExitCode(execErr), execErr)
+	}
+	var parsed struct {
+		Error errfmt.Classification `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(stderr), &parsed); err != nil {
+		t.Fatalf("json parse: %v\nstderr=%q", err, stderr)
+	}
+	if parsed.Error.Code != errfmt.CodeNotFound || parsed.Error.HTTPStatus != 404 || parsed.Error.Reason != "notFound" || parsed.Error.Retryable || parsed.Error.ExitCode != 4 {
+		t.Fatalf("unexpected error object: %+v", parsed.Error)
+	}
+}
+
+func TestExecute_JSONErrorOutput_Usage(t *testing.T) {
+	var execErr error
+	stderr := captureStderr(t, func() {
+		execErr = Execute([]string{"--json", "gmail", "--no-such-flag"})
+	})
+	if ExitCode(execErr) != errfmt.ExitUsage {
+		t.Fatalf("exit=%d", ExitCode(execErr))
+	}
+	var parsed struct {
+		Error errfmt.Classification `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(stderr), &parsed); err != nil {
+		t.Fatalf("json parse: %v\nstderr=%q", err, stderr)
+	}
+	if parsed.Error.Code != errfmt.CodeUsage || parsed.Error.Message == "" {
+		t.Fatalf("unexpected error object: %+v", parsed.Error)
+	}
+}
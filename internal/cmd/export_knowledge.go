@@ -0,0 +1,508 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/googleapi"
+
+	"github.com/steipete/gogcli/internal/knowledge"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+const mimeGoogleDoc = "application/vnd.google-apps.document"
+
+var googleDocLinkRe = regexp.MustCompile(`docs\.google\.com/document/d/([A-Za-z0-9_-]{20,})`)
+
+// Calendar notification subjects that wrap the event title.
+var calendarSubjectPrefixes = []string{
+	"invitation:", "updated invitation:", "updated invitation with note:", "invitation from google calendar:",
+	"accepted:", "declined:", "tentatively accepted:", "canceled event:", "cancelled event:",
+}
+
+type ExportCmd struct {
+	Knowledge ExportKnowledgeCmd `cmd:"" name:"knowledge" help:"Export threads, events, and meeting notes as a Markdown corpus for search/RAG"`
+}
+
+type ExportKnowledgeCmd struct {
+	Output     string    `name:"output" short:"o" help:"Corpus directory (created if missing), or gs://bucket/path or s3://bucket/path to upload (required)"`
+	Since      SinceTime `name:"since" help:"Look back this far (e.g. 90d, 2w) or since a date (YYYY-MM-DD)" default:"90d"`
+	Query      string    `name:"query" short:"q" help:"Extra Gmail search query for threads (e.g. 'label:projects')"`
+	CalendarID string    `name:"calendar" help:"Calendar ID" default:"primary"`
+	Max        int64     `name:"max" aliases:"limit" help:"Max threads to export" default:"1000"`
+	NoMail     bool      `name:"no-mail" help:"Skip Gmail threads"`
+	NoCalendar bool      `name:"no-calendar" help:"Skip calendar events and their meeting notes"`
+}
+
+// knowledgeCounts tracks written and unchanged documents per kind.
+type knowledgeCounts struct {
+	Written   map[string]int `json:"written"`
+	Unchanged map[string]int `json:"unchanged"`
+}
+
+func (k *knowledgeCounts) add(kind string, written bool) {
+	if written {
+		k.Written[kind]++
+	} else {
+		k.Unchanged[kind]++
+	}
+}
+
+func (c *ExportKnowledgeCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(c.Output) == "" {
+		return usage("required: --output")
+	}
+	if c.Max <= 0 {
+		return usage("--max must be > 0")
+	}
+	if c.NoMail && c.NoCalendar {
+		return usage("nothing to export: --no-mail and --no-calendar both set")
+	}
+	out, err := openOutputDir(strings.TrimSpace(c.Output))
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	corpus, err := knowledge.Open(out.Local)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	since := c.Since.Time(now)
+	counts := &knowledgeCounts{Written: map[string]int{}, Unchanged: map[string]int{}}
+
+	var threadTitles map[string]string
+	if !c.NoMail {
+		threadTitles, err = c.exportThreads(ctx, account, corpus, counts, since, now)
+		if err != nil {
+			return err
+		}
+	}
+	if !c.NoCalendar {
+		if err := c.exportEvents(ctx, account, corpus, counts, threadTitles, since, now); err != nil {
+			return err
+		}
+	}
+	if err := corpus.Save(now); err != nil {
+		return err
+	}
+	root, err := out.Finish(ctx)
+	if err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"dir":       root,
+			"since":     since.UTC().Format(time.RFC3339),
+			"written":   counts.Written,
+			"unchanged": counts.Unchanged,
+		})
+	}
+	u.Out().Printf("dir\t%s", root)
+	for _, kind := range []string{knowledge.KindThread, knowledge.KindEvent, knowledge.KindDoc} {
+		u.Out().Printf("%ss\t%d written, %d unchanged", kind, counts.Written[kind], counts.Unchanged[kind])
+	}
+	return nil
+}
+
+// exportThreads writes threads active since the cutoff. Threads whose
+// historyId is unchanged are not fetched again. It returns the normalized
+// subject of every thread in the corpus, for linking events.
+func (c *ExportKnowledgeCmd) exportThreads(ctx context.Context, account string, corpus *knowledge.Corpus, counts *knowledgeCounts, since, now time.Time) (map[string]string, error) {
+	svc, err := newGmailService(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+	labelNames, err := fetchLabelIDToName(svc)
+	if err != nil {
+		return nil, err
+	}
+	query := strings.TrimSpace(fmt.Sprintf("after:%d %s", since.Unix(), strings.TrimSpace(c.Query)))
+	listed, err := listThreadVersions(ctx, svc, query, c.Max)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []*gmail.Thread
+	for _, t := range listed {
+		if corpus.Current(knowledge.Key(knowledge.KindThread, t.Id), threadVersion(t)) {
+			counts.add(knowledge.KindThread, false)
+			continue
+		}
+		pending = append(pending, t)
+	}
+
+	var mu sync.Mutex
+	err = fetchGmailConcurrently(ctx, len(pending), gmailQuotaThreadGet, func(ctx context.Context, idx int) error {
+		thread, getErr := svc.Users.Threads.Get("me", pending[idx].Id).Format(gmailFormatFull).Context(ctx).Do()
+		if getErr != nil {
+			return fmt.Errorf("thread %s: %w", pending[idx].Id, getErr)
+		}
+		doc := threadKnowledgeDoc(account, thread, labelNames)
+		doc.Version = threadVersion(pending[idx])
+		mu.Lock()
+		defer mu.Unlock()
+		written, putErr := corpus.Put(doc, now)
+		if putErr != nil {
+			return fmt.Errorf("thread %s: %w", thread.Id, putErr)
+		}
+		counts.add(knowledge.KindThread, written)
+		return nil
+	})
+	if err != nil {
+		// Keep what was written so the next run resumes.
+		_ = corpus.Save(now)
+		return nil, err
+	}
+
+	titles := map[string]string{}
+	for key, item := range corpus.Items {
+		if strings.HasPrefix(key, knowledge.KindThread+":") {
+			if s := normalizeKnowledgeSubject(item.Title); s != "" {
+				titles[key] = s
+			}
+		}
+	}
+	return titles, nil
+}
+
+func threadVersion(t *gmail.Thread) string {
+	return fmt.Sprintf("%d", t.HistoryId)
+}
+
+func listThreadVersions(ctx context.Context, svc *gmail.Service, query string, limit int64) ([]*gmail.Thread, error) {
+	var out []*gmail.Thread
+	pageToken := ""
+	for int64(len(out)) < limit {
+		call := svc.Users.Threads.List("me").Q(query).MaxResults(min(limit-int64(len(out)), 500)).Context(ctx)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range resp.Threads {
+			if t != nil && t.Id != "" {
+				out = append(out, t)
+			}
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+	return out, nil
+}
+
+func threadKnowledgeDoc(account string, thread *gmail.Thread, labelNames map[string]string) knowledge.Doc {
+	doc := knowledge.Doc{
+		Kind:    knowledge.KindThread,
+		ID:      thread.Id,
+		Account: account,
+		Source:  fmt.Sprintf("https://mail.google.com/mail/?authuser=%s#all/%s", url.QueryEscape(account), thread.Id),
+	}
+	people := map[string]bool{}
+	labels := map[string]bool{}
+	var body strings.Builder
+	for _, msg := range thread.Messages {
+		if msg == nil {
+			continue
+		}
+		if doc.Title == "" {
+			doc.Title = strings.TrimSpace(headerValue(msg.Payload, "Subject"))
+		}
+		if doc.Date.IsZero() && msg.InternalDate > 0 {
+			doc.Date = time.UnixMilli(msg.InternalDate)
+		}
+		for _, h := range []string{"From", "To", "Cc"} {
+			for _, addr := range parseEmailAddresses(headerValue(msg.Payload, h)) {
+				people[strings.ToLower(addr)] = true
+			}
+		}
+		for _, id := range msg.LabelIds {
+			if name := labelNames[id]; name != "" {
+				labels[name] = true
+			}
+		}
+		text, isHTML := bestBodyForDisplay(msg.Payload)
+		if isHTML {
+			text = stripHTMLTags(text)
+		}
+		fmt.Fprintf(&body, "## %s — %s\n\n%s\n\n", headerValue(msg.Payload, "From"), headerValue(msg.Payload, "Date"), strings.TrimSpace(text))
+	}
+	doc.People = sortedKeys(people)
+	doc.Labels = sortedKeys(labels)
+	doc.Body = body.String()
+	return doc
+}
+
+// exportEvents writes events since the cutoff and the Google Docs attached to
+// or linked from them (meeting notes, transcripts). Events link to their
+// notes and to threads about them; notes link back to the event.
+func (c *ExportKnowledgeCmd) exportEvents(ctx context.Context, account string, corpus *knowledge.Corpus, counts *knowledgeCounts, threadTitles map[string]string, since, now time.Time) error {
+	svc, err := newCalendarService(ctx, account)
+	if err != nil {
+		return err
+	}
+	events, err := listKnowledgeEvents(ctx, svc, c.CalendarID, since, now)
+	if err != nil {
+		return err
+	}
+
+	var drv *drive.Service
+	for _, e := range events {
+		links := eventThreadLinks(e.Summary, threadTitles)
+		for _, docID := range eventNoteDocIDs(e) {
+			if drv == nil {
+				if drv, err = newDriveService(ctx, account); err != nil {
+					return err
+				}
+			}
+			written, docErr := exportNotesDoc(ctx, drv, corpus, account, docID, knowledge.Key(knowledge.KindEvent, e.Id), now)
+			if docErr != nil {
+				if driveFileUnavailable(docErr) {
+					continue // not shared with this account
+				}
+				return fmt.Errorf("notes %s for event %s: %w", docID, e.Id, docErr)
+			}
+			counts.add(knowledge.KindDoc, written)
+			links = append(links, knowledge.Key(knowledge.KindDoc, docID))
+		}
+		doc := eventKnowledgeDoc(account, e)
+		doc.Links = links
+		doc.Version = e.Updated + "|" + strings.Join(links, ",")
+		written, putErr := corpus.Put(doc, now)
+		if putErr != nil {
+			return fmt.Errorf("event %s: %w", e.Id, putErr)
+		}
+		counts.add(knowledge.KindEvent, written)
+	}
+	return nil
+}
+
+func listKnowledgeEvents(ctx context.Context, svc *calendar.Service, calendarID string, since, now time.Time) ([]*calendar.Event, error) {
+	var out []*calendar.Event
+	pageToken := ""
+	for {
+		call := svc.Events.List(calendarID).
+			TimeMin(since.Format(time.RFC3339)).
+			TimeMax(now.Format(time.RFC3339)).
+			SingleEvents(true).
+			OrderBy("startTime").
+			MaxResults(2500).
+			Context(ctx)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range resp.Items {
+			if e != nil && e.Status != "cancelled" {
+				out = append(out, e)
+			}
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+	return out, nil
+}
+
+func eventKnowledgeDoc(account string, e *calendar.Event) knowledge.Doc {
+	doc := knowledge.Doc{
+		Kind:    knowledge.KindEvent,
+		ID:      e.Id,
+		Title:   strings.TrimSpace(e.Summary),
+		Account: account,
+		Source:  e.HtmlLink,
+	}
+	if e.Start != nil {
+		if t, ok := parseEventTime(e.Start.DateTime, e.Start.TimeZone); ok {
+			doc.Date = t
+		} else if t, ok := parseEventDate(e.Start.Date, e.Start.TimeZone); ok {
+			doc.Date = t
+		}
+	}
+
+	people := map[string]bool{}
+	var body strings.Builder
+	if e.Start != nil && e.End != nil {
+		fmt.Fprintf(&body, "- When: %s – %s\n", formatEventLocal(e.Start, nil), formatEventLocal(e.End, nil))
+	}
+	if loc := strings.TrimSpace(e.Location); loc != "" {
+		fmt.Fprintf(&body, "- Where: %s\n", loc)
+	}
+	if e.Organizer != nil && e.Organizer.Email != "" {
+		fmt.Fprintf(&body, "- Organizer: %s\n", e.Organizer.Email)
+		people[strings.ToLower(e.Organizer.Email)] = true
+	}
+	for _, a := range e.Attendees {
+		if a == nil || a.Resource || a.Email == "" {
+			continue
+		}
+		people[strings.ToLower(a.Email)] = true
+	}
+	if len(people) > 0 {
+		fmt.Fprintf(&body, "- Attendees: %s\n", strings.Join(sortedKeys(people), ", "))
+	}
+	if desc := strings.TrimSpace(e.Description); desc != "" {
+		fmt.Fprintf(&body, "\n## Description\n\n%s\n", strings.TrimSpace(stripHTMLTags(desc)))
+	}
+	doc.People = sortedKeys(people)
+	doc.Body = body.String()
+	return doc
+}
+
+// eventNoteDocIDs returns Google Docs attached to or linked from an event,
+// which is where Meet puts notes and transcripts.
+func eventNoteDocIDs(e *calendar.Event) []string {
+	seen := map[string]bool{}
+	var ids []string
+	add := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, a := range e.Attachments {
+		if a == nil || a.MimeType != mimeGoogleDoc {
+			continue
+		}
+		if a.FileId != "" {
+			add(a.FileId)
+		} else if m := googleDocLinkRe.FindStringSubmatch(a.FileUrl); m != nil {
+			add(m[1])
+		}
+	}
+	for _, m := range googleDocLinkRe.FindAllStringSubmatch(e.Description, -1) {
+		add(m[1])
+	}
+	return ids
+}
+
+// exportNotesDoc writes one Google Doc as Markdown unless its modifiedTime is
+// unchanged.
+func exportNotesDoc(ctx context.Context, svc *drive.Service, corpus *knowledge.Corpus, account, id, eventKey string, now time.Time) (bool, error) {
+	meta, err := svc.Files.Get(id).SupportsAllDrives(true).Fields("id, name, mimeType, modifiedTime, webViewLink").Context(ctx).Do()
+	if err != nil {
+		return false, err
+	}
+	key := knowledge.Key(knowledge.KindDoc, id)
+	version := meta.ModifiedTime + "|" + eventKey
+	if corpus.Current(key, version) {
+		return false, nil
+	}
+	resp, err := driveExportDownload(ctx, svc, id, "text/markdown")
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return false, fmt.Errorf("export failed: %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	doc := knowledge.Doc{
+		Kind:    knowledge.KindDoc,
+		ID:      id,
+		Title:   strings.TrimSpace(meta.Name),
+		Account: account,
+		Source:  meta.WebViewLink,
+		Links:   []string{eventKey},
+		Body:    string(data),
+		Version: version,
+	}
+	if t, parseErr := time.Parse(time.RFC3339, meta.ModifiedTime); parseErr == nil {
+		doc.Date = t
+	}
+	return corpus.Put(doc, now)
+}
+
+// driveFileUnavailable reports errors for notes the account cannot read.
+func driveFileUnavailable(err error) bool {
+	var gerr *googleapi.Error
+	if errors.As(err, &gerr) {
+		return gerr.Code == http.StatusNotFound || gerr.Code == http.StatusForbidden
+	}
+	return false
+}
+
+// eventThreadLinks returns the threads whose subject is about the event:
+// the event title itself, or a calendar notification for it.
+func eventThreadLinks(summary string, threadTitles map[string]string) []string {
+	title := strings.ToLower(strings.TrimSpace(summary))
+	if len([]rune(title)) < 3 {
+		return nil
+	}
+	var links []string
+	for key, subject := range threadTitles {
+		if subject == title {
+			links = append(links, key)
+		}
+	}
+	sort.Strings(links)
+	return links
+}
+
+// normalizeKnowledgeSubject lowercases a subject and strips reply, forward,
+// and calendar notification wrapping ("Invitation: X @ Fri ...").
+func normalizeKnowledgeSubject(subject string) string {
+	s := strings.ToLower(strings.TrimSpace(subject))
+	notification := false
+	for changed := true; changed; {
+		changed = false
+		for _, prefix := range []string{"re:", "fwd:", "fw:"} {
+			if strings.HasPrefix(s, prefix) {
+				s = strings.TrimSpace(s[len(prefix):])
+				changed = true
+			}
+		}
+		for _, prefix := range calendarSubjectPrefixes {
+			if strings.HasPrefix(s, prefix) {
+				s = strings.TrimSpace(s[len(prefix):])
+				changed, notification = true, true
+			}
+		}
+	}
+	// Notifications append " @ <when>" to the title.
+	if i := strings.LastIndex(s, " @ "); notification && i > 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	return s
+}
+
+func sortedKeys(m map[string]bool) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+)
+
+func TestExportKnowledge(t *testing.T) {
+	const notesID = "1AbCdEfGhIjKlMnOpQrStUvWxYz"
+	var threadGets, exports atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/users/me/labels"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"labels": []map[string]any{{"id": "Label_1", "name": "Projects"}}})
+		case strings.HasSuffix(r.URL.Path, "/users/me/threads"):
+			if !strings.Contains(r.URL.Query().Get("q"), "after:") {
+				t.Errorf("thread query = %q", r.URL.Query().Get("q"))
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"threads": []map[string]any{{"id": "t1", "historyId": "42"}}})
+		case strings.HasSuffix(r.URL.Path, "/users/me/threads/t1"):
+			threadGets.Add(1)
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "t1", "messages": []map[string]any{{
+				"id": "m1", "threadId": "t1", "internalDate": "1788000000000", "labelIds": []string{"Label_1"},
+				"payload": map[string]any{
+					"mimeType": "text/plain",
+					"headers": []map[string]string{
+						{"name": "Subject", "value": "Invitation: Q3 planning @ Tue Sep 1, 2026"},
+						{"name": "From", "value": "Ann <ann@example.com>"},
+					},
+					"body": map[string]any{"data": base64.RawURLEncoding.EncodeToString([]byte("See you there."))},
+				},
+			}}})
+		case strings.HasSuffix(r.URL.Path, "/calendars/primary/events"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"items": []map[string]any{{
+				"id": "e1", "summary": "Q3 planning", "updated": "2026-09-01T10:00:00Z",
+				"start":       map[string]string{"dateTime": "2026-09-01T15:00:00Z"},
+				"end":         map[string]string{"dateTime": "2026-09-01T16:00:00Z"},
+				"attendees":   []map[string]any{{"email": "ann@example.com"}},
+				"attachments": []map[string]string{{"fileId": notesID, "mimeType": mimeGoogleDoc}},
+			}}})
+		case strings.HasSuffix(r.URL.Path, "/files/"+notesID+"/export"):
+			exports.Add(1)
+			w.Header().Set("Content-Type", "text/markdown")
+			_, _ = w.Write([]byte("# Notes\n\n- Ship it"))
+		case strings.HasSuffix(r.URL.Path, "/files/"+notesID):
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": notesID, "name": "Q3 planning - Notes", "mimeType": mimeGoogleDoc, "modifiedTime": "2026-09-01T17:00:00Z"})
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	stubGmailService(t, srv)
+	opts := []option.ClientOption{option.WithoutAuthentication(), option.WithHTTPClient(srv.Client()), option.WithEndpoint(srv.URL + "/")}
+	origCal, origDrive := newCalendarService, newDriveService
+	t.Cleanup(func() { newCalendarService, newDriveService = origCal, origDrive })
+	newCalendarService = func(ctx context.Context, _ string) (*calendar.Service, error) {
+		return calendar.NewService(ctx, opts...)
+	}
+	newDriveService = func(ctx context.Context, _ string) (*drive.Service, error) {
+		return drive.NewService(ctx, opts...)
+	}
+
+	dir := t.TempDir()
+	run := func() map[string]any {
+		out := captureStdout(t, func() {
+			if err := Execute([]string{"--json", "--account", "me@example.com", "export", "knowledge", "--output", dir, "--since", "90d"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+		var res map[string]any
+		if err := json.Unmarshal([]byte(out), &res); err != nil {
+			t.Fatalf("json: %v\n%s", err, out)
+		}
+		return res
+	}
+
+	first := run()
+	if w := first["written"].(map[string]any); w["thread"] != float64(1) || w["event"] != float64(1) || w["doc"] != float64(1) {
+		t.Fatalf("written = %v", w)
+	}
+	event, err := os.ReadFile(filepath.Join(dir, "events", "2026-09-01-q3-planning-e1.md"))
+	if err != nil {
+		t.Fatalf("event file: %v", err)
+	}
+	for _, want := range []string{"- thread:t1", "- doc:" + notesID, "ann@example.com"} {
+		if !strings.Contains(string(event), want) {
+			t.Fatalf("event missing %q:\n%s", want, event)
+		}
+	}
+	thread, err := os.ReadFile(filepath.Join(dir, "threads", "2026-08-29-invitation-q3-planning-tue-sep-1-2026-t1.md"))
+	if err != nil {
+		t.Fatalf("thread file: %v", err)
+	}
+	if !strings.Contains(string(thread), "- Projects") || !strings.Contains(string(thread), "See you there.") {
+		t.Fatalf("thread:\n%s", thread)
+	}
+
+	second := run()
+	if w := second["written"].(map[string]any); len(w) != 0 {
+		t.Fatalf("second run wrote %v", w)
+	}
+	if threadGets.Load() != 1 || exports.Load() != 1 {
+		t.Fatalf("refetched unchanged items: threads=%d exports=%d", threadGets.Load(), exports.Load())
+	}
+}
+
+func TestNormalizeKnowledgeSubject(t *testing.T) {
+	for in, want := range map[string]string{
+		"Re: Fwd: Q3 planning":                              "q3 planning",
+		"Updated invitation: Q3 planning @ Tue Sep 1, 2026": "q3 planning",
+		"Accepted: Q3 planning @ Tue Sep 1":                 "q3 planning",
+		"Invitation: Lunch @ noon? @ Fri":                   "lunch @ noon?",
+		"Lunch @ noon":                                      "lunch @ noon",
+	} {
+		if got := normalizeKnowledgeSubject(in); got != want {
+			t.Errorf("normalizeKnowledgeSubject(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
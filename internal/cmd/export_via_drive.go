@@ -61,11 +61,6 @@ func exportViaDrive(ctx context.Context, flags *RootFlags, opts exportViaDriveOp
 		return fmt.Errorf("file is not a %s (mimeType=%q)", label, meta.MimeType)
 	}
 
-	destPath, err := resolveDriveDownloadDestPath(meta, outPathFlag)
-	if err != nil {
-		return err
-	}
-
 	format = strings.TrimSpace(format)
 	if format == "" {
 		format = strings.TrimSpace(opts.DefaultFormat)
@@ -74,7 +69,13 @@ func exportViaDrive(ctx context.Context, flags *RootFlags, opts exportViaDriveOp
 		format = defaultExportFormat
 	}
 
-	downloadedPath, size, err := downloadDriveFile(ctx, svc, meta, destPath, format)
+	downloadedPath, size, err := writeToOutput(ctx, outPathFlag, func(out string) (string, int64, error) {
+		destPath, destErr := resolveDriveDownloadDestPath(meta, out)
+		if destErr != nil {
+			return "", 0, destErr
+		}
+		return downloadDriveFile(ctx, svc, meta, destPath, format)
+	})
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Shared kong flag types. Each implements encoding.TextUnmarshaler, so kong
+// parses flags and defaults the same way everywhere and reports bad values as
+// "--flag: invalid ..." usage errors before Run is called.
+
+var (
+	byteSizeRe    = regexp.MustCompile(`^(\d+(?:\.\d+)?|\.\d+)\s*([kmgt]?)(i?b)?$`)
+	dayDurationRe = regexp.MustCompile(`^(\d+)([dwy])$`)
+)
+
+// ByteSize is a size in bytes given as 300, 500K, 5M, 1.2GB, or 10MiB.
+// Units are binary (1K = 1024), matching how sizes are printed.
+type ByteSize int64
+
+func (b *ByteSize) UnmarshalText(text []byte) error {
+	v, err := parseByteSize(string(text))
+	if err != nil {
+		return err
+	}
+	*b = v
+	return nil
+}
+
+func (b ByteSize) String() string {
+	return formatBytes(int64(b))
+}
+
+func parseByteSize(raw string) (ByteSize, error) {
+	m := byteSizeRe.FindStringSubmatch(strings.ToLower(strings.TrimSpace(raw)))
+	if m == nil || (m[2] == "" && m[3] == "ib") {
+		return 0, fmt.Errorf("invalid size %q (use e.g. 500K, 5M, 1.2GB)", raw)
+	}
+	n, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q (use e.g. 500K, 5M, 1.2GB)", raw)
+	}
+	if m[2] != "" {
+		n *= math.Pow(1024, float64(strings.Index("kmgt", m[2])+1))
+	}
+	if n > math.MaxInt64 {
+		return 0, fmt.Errorf("size %q is too large", raw)
+	}
+	return ByteSize(n), nil
+}
+
+// DayDuration is a non-negative duration given as a Go duration (72h, 90m)
+// or in days, weeks, and years of 365 days (30d, 2w, 1y).
+type DayDuration time.Duration
+
+func (d *DayDuration) UnmarshalText(text []byte) error {
+	days, rest, err := parseDayDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = DayDuration(time.Duration(days)*24*time.Hour + rest)
+	return nil
+}
+
+func (d DayDuration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+// parseDayDuration splits 30d/2w/1y into whole days, so callers can step by
+// calendar days across DST changes; Go durations come back as rest.
+func parseDayDuration(raw string) (days int, rest time.Duration, err error) {
+	s := strings.ToLower(strings.TrimSpace(raw))
+	if m := dayDurationRe.FindStringSubmatch(s); m != nil {
+		days, err = strconv.Atoi(m[1])
+		if err == nil {
+			switch m[2] {
+			case "w":
+				days *= 7
+			case "y":
+				days *= 365
+			}
+			return days, 0, nil
+		}
+	}
+	if d, parseErr := time.ParseDuration(s); parseErr == nil && d >= 0 {
+		return 0, d, nil
+	}
+	return 0, 0, fmt.Errorf("invalid duration %q (use e.g. 30d, 2w, 1y, or 72h)", raw)
+}
+
+// Percent is a share of 100 given as 90% or 90.
+type Percent float64
+
+func (p *Percent) UnmarshalText(text []byte) error {
+	raw := strings.TrimSpace(string(text))
+	v, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(raw, "%")), 64)
+	if err != nil || v <= 0 || v > 100 {
+		return fmt.Errorf("invalid percentage %q (use e.g. 90%%)", raw)
+	}
+	*p = Percent(v)
+	return nil
+}
+
+func (p Percent) String() string {
+	return strconv.FormatFloat(float64(p), 'f', -1, 64) + "%"
+}
+
+// SinceTime is a point in the past given either relative to now (7d, 2w,
+// 1y, 48h) or absolutely (YYYY-MM-DD in local time, or RFC3339). Relative
+// values resolve when Time is called, so defaults like "7d" track the clock.
+type SinceTime struct {
+	raw  string
+	days int
+	ago  time.Duration
+	at   time.Time
+	set  bool
+}
+
+func (s *SinceTime) UnmarshalText(text []byte) error {
+	raw := strings.TrimSpace(string(text))
+	if raw == "" {
+		*s = SinceTime{}
+		return nil
+	}
+	if days, ago, err := parseDayDuration(raw); err == nil {
+		*s = SinceTime{raw: raw, days: days, ago: ago, set: true}
+		return nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", raw, time.Local); err == nil {
+		*s = SinceTime{raw: raw, at: t, set: true}
+		return nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		*s = SinceTime{raw: raw, at: t, set: true}
+		return nil
+	}
+	return fmt.Errorf("invalid time %q (use e.g. 7d, 48h, 2w, 1y, YYYY-MM-DD, or RFC3339)", raw)
+}
+
+// String returns the value as given on the command line.
+func (s SinceTime) String() string {
+	return s.raw
+}
+
+// IsZero reports whether the flag was left empty.
+func (s SinceTime) IsZero() bool {
+	return !s.set
+}
+
+// Time resolves s against now.
+func (s SinceTime) Time(now time.Time) time.Time {
+	if !s.at.IsZero() {
+		return s.at
+	}
+	return now.AddDate(0, 0, -s.days).Add(-s.ago)
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestByteSize(t *testing.T) {
+	cases := map[string]ByteSize{
+		"300":    300,
+		"300B":   300,
+		"500k":   500 * 1024,
+		"5M":     5 * 1024 * 1024,
+		"10MiB":  10 * 1024 * 1024,
+		"1.5 GB": 3 * 512 * 1024 * 1024,
+	}
+	for in, want := range cases {
+		var got ByteSize
+		if err := got.UnmarshalText([]byte(in)); err != nil || got != want {
+			t.Fatalf("%q: got %d err=%v want %d", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"", "5X", "-1M", "1.2.3G", "5ib"} {
+		var got ByteSize
+		if err := got.UnmarshalText([]byte(bad)); err == nil || !strings.Contains(err.Error(), "invalid size") {
+			t.Fatalf("%q: expected error, got %v", bad, err)
+		}
+	}
+}
+
+func TestDayDuration(t *testing.T) {
+	cases := map[string]time.Duration{
+		"30d": 30 * 24 * time.Hour,
+		"2W":  14 * 24 * time.Hour,
+		"72h": 72 * time.Hour,
+		"1y":  365 * 24 * time.Hour,
+		"0":   0,
+	}
+	for in, want := range cases {
+		var got DayDuration
+		if err := got.UnmarshalText([]byte(in)); err != nil || got.Duration() != want {
+			t.Fatalf("%q: got %v err=%v want %v", in, got.Duration(), err, want)
+		}
+	}
+	for _, bad := range []string{"soon", "-3h", "3 days"} {
+		var got DayDuration
+		if err := got.UnmarshalText([]byte(bad)); err == nil || !strings.Contains(err.Error(), "invalid duration") {
+			t.Fatalf("%q: expected error, got %v", bad, err)
+		}
+	}
+}
+
+func TestSinceTime(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	cases := map[string]time.Time{
+		"7d":                   now.AddDate(0, 0, -7),
+		"2w":                   now.AddDate(0, 0, -14),
+		"1y":                   now.AddDate(0, 0, -365),
+		"48h":                  now.Add(-48 * time.Hour),
+		"2026-10-01T08:00:00Z": time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC),
+	}
+	for in, want := range cases {
+		var s SinceTime
+		if err := s.UnmarshalText([]byte(in)); err != nil {
+			t.Fatalf("%q: %v", in, err)
+		}
+		if got := s.Time(now); !got.Equal(want) {
+			t.Fatalf("%q: got %v want %v", in, got, want)
+		}
+	}
+	var s SinceTime
+	if err := s.UnmarshalText([]byte("2026-10-01")); err != nil || s.Time(now).Day() != 1 {
+		t.Fatalf("date: %v %v", s.Time(now), err)
+	}
+	if err := s.UnmarshalText([]byte("not-a-date")); err == nil {
+		t.Fatalf("expected error")
+	}
+	if err := s.UnmarshalText(nil); err != nil || !s.IsZero() {
+		t.Fatalf("empty should reset: %v", err)
+	}
+}
+
+func TestFlagTypes_UsageErrors(t *testing.T) {
+	cases := map[string][]string{
+		`--since: invalid time "soon"`:   {"gmail", "bounces", "scan", "--since", "soon"},
+		`--min-size: invalid size "big"`: {"gmail", "attachments", "save-to-drive", "-q", "x", "--parent", "p", "--min-size", "big"},
+		"is larger than --max-size":      {"gmail", "attachments", "save-to-drive", "-q", "x", "--parent", "p", "--min-size", "5M", "--max-size", "1M"},
+	}
+	for want, args := range cases {
+		var err error
+		errOut := captureStderr(t, func() {
+			err = Execute(append([]string{"--account", "a@b.com"}, args...))
+		})
+		if ExitCode(err) != 2 || !strings.Contains(errOut, want) {
+			t.Fatalf("%v: expected usage error %q, got %v (%q)", args, want, err, errOut)
+		}
+	}
+}
+
+func TestParseTimeExpr_Offsets(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	if got, err := parseTimeExpr("+3d", now, time.UTC); err != nil || !got.Equal(now.AddDate(0, 0, 3)) {
+		t.Fatalf("+3d: %v %v", got, err)
+	}
+	if got, err := parseTimeExpr("-36h", now, time.UTC); err != nil || !got.Equal(now.Add(-36*time.Hour)) {
+		t.Fatalf("-36h: %v %v", got, err)
+	}
+	if _, err := parseTimeExpr("+soon", now, time.UTC); err == nil {
+		t.Fatalf("expected error")
+	}
+}
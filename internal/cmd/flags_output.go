@@ -4,6 +4,12 @@ type OutputPathFlag struct {
 	Path string `name:"out" aliases:"output" help:"Output file path (default: gogcli config dir)"`
 }
 
+// ExportOutputFlag is OutputPathFlag for exports, which can also upload to
+// object storage (see writeToOutput).
+type ExportOutputFlag struct {
+	Path string `name:"out" aliases:"output" help:"Output file path, or gs://bucket/path or s3://bucket/path to upload (default: gogcli config dir)"`
+}
+
 type OutputPathRequiredFlag struct {
 	Path string `name:"out" aliases:"output" help:"Output file path (required)"`
 }
@@ -6,7 +6,6 @@ import (
 	"os"
 	"regexp"
 	"strings"
-	"sync"
 	"time"
 
 	"google.golang.org/api/gmail/v1"
@@ -19,21 +18,31 @@ import (
 var newGmailService = googleapi.NewGmail
 
 type GmailCmd struct {
-	Search     GmailSearchCmd     `cmd:"" name:"search" group:"Read" help:"Search threads using Gmail query syntax"`
-	Messages   GmailMessagesCmd   `cmd:"" name:"messages" group:"Read" help:"Message operations"`
-	Thread     GmailThreadCmd     `cmd:"" name:"thread" aliases:"read" group:"Organize" help:"Thread operations (get, modify)"`
-	Get        GmailGetCmd        `cmd:"" name:"get" group:"Read" help:"Get a message (full|metadata|raw)"`
-	Attachment GmailAttachmentCmd `cmd:"" name:"attachment" group:"Read" help:"Download a single attachment"`
-	URL        GmailURLCmd        `cmd:"" name:"url" group:"Read" help:"Print Gmail web URLs for threads"`
-	History    GmailHistoryCmd    `cmd:"" name:"history" group:"Read" help:"Gmail history"`
+	Mailbox string `name:"mailbox" help:"Act on a mailbox delegated to you (e.g. shared@example.com); every call is recorded in the event log"`
+
+	Search      GmailSearchCmd      `cmd:"" name:"search" group:"Read" help:"Search threads using Gmail query syntax"`
+	Messages    GmailMessagesCmd    `cmd:"" name:"messages" group:"Read" help:"Message operations"`
+	Thread      GmailThreadCmd      `cmd:"" name:"thread" aliases:"read,threads" group:"Organize" help:"Thread operations (get, modify, split, join)"`
+	Get         GmailGetCmd         `cmd:"" name:"get" group:"Read" help:"Get a message (full|metadata|raw)"`
+	Attachment  GmailAttachmentCmd  `cmd:"" name:"attachment" group:"Read" help:"Download a single attachment"`
+	Attachments GmailAttachmentsCmd `cmd:"" name:"attachments" group:"Read" help:"Bulk attachment operations"`
+	URL         GmailURLCmd         `cmd:"" name:"url" group:"Read" help:"Print Gmail web URLs for threads"`
+	History     GmailHistoryCmd     `cmd:"" name:"history" group:"Read" help:"Gmail history"`
+	Export      GmailExportCmd      `cmd:"" name:"export" group:"Read" help:"Export messages to local mail stores"`
 
 	Labels GmailLabelsCmd `cmd:"" name:"labels" group:"Organize" help:"Label operations"`
 	Batch  GmailBatchCmd  `cmd:"" name:"batch" group:"Organize" help:"Batch operations"`
+	Alias  GmailAliasCmd  `cmd:"" name:"alias" group:"Organize" help:"Plus-address aliases and tag registry"`
+	Snooze GmailSnoozeCmd `cmd:"" name:"snooze" group:"Organize" help:"Snoozed messages (list, process due, cancel)"`
+	Later  GmailLaterCmd  `cmd:"" name:"later" group:"Organize" help:"Reply-later queue with deadlines (add, list --overdue, done, process)"`
 
 	Send   GmailSendCmd   `cmd:"" name:"send" group:"Write" help:"Send an email"`
+	Reply  GmailReplyCmd  `cmd:"" name:"reply" group:"Write" help:"Draft a reply with the summarizer hook (never sends)"`
 	Track  GmailTrackCmd  `cmd:"" name:"track" group:"Write" help:"Email open tracking"`
 	Drafts GmailDraftsCmd `cmd:"" name:"drafts" group:"Write" help:"Draft operations"`
 
+	Bounces GmailBouncesCmd `cmd:"" name:"bounces" group:"Write" help:"Bounce (delivery failure) processing"`
+
 	Settings GmailSettingsCmd `cmd:"" name:"settings" group:"Admin" help:"Settings and admin"`
 
 	// Kept for backwards-compatibility; hidden from default help.
@@ -329,124 +338,63 @@ type threadItem struct {
 	MessageCount int      `json:"messageCount,omitempty"` // Number of messages in the thread
 }
 
-// fetchThreadDetails fetches thread metadata concurrently with bounded parallelism.
-// This eliminates N+1 queries by fetching all threads in parallel.
+// fetchThreadDetails fetches thread metadata concurrently on the quota-aware
+// fetch pool. This eliminates N+1 queries by fetching all threads in parallel.
 // When oldest is false (default), the date shown is from the last message in the thread.
 // When oldest is true, the date shown is from the first message in the thread.
 func fetchThreadDetails(ctx context.Context, svc *gmail.Service, threads []*gmail.Thread, idToName map[string]string, oldest bool, loc *time.Location) ([]threadItem, error) {
-	if len(threads) == 0 {
-		return nil, nil
+	ids := make([]string, 0, len(threads))
+	for _, t := range threads {
+		if t != nil && t.Id != "" {
+			ids = append(ids, t.Id)
+		}
 	}
-
-	const maxConcurrency = 10 // Limit parallel requests to avoid rate limiting
-	sem := make(chan struct{}, maxConcurrency)
-
-	type result struct {
-		index int
-		item  threadItem
-		err   error
+	if len(ids) == 0 {
+		return nil, nil
 	}
 
-	results := make(chan result, len(threads))
-	var wg sync.WaitGroup
-
-	for i, t := range threads {
-		if t.Id == "" {
-			continue
+	items := make([]threadItem, len(ids))
+	err := fetchGmailConcurrently(ctx, len(ids), gmailQuotaThreadGet, func(ctx context.Context, idx int) error {
+		threadID := ids[idx]
+		thread, err := svc.Users.Threads.Get("me", threadID).
+			Format("metadata").
+			MetadataHeaders("From", "Subject", "Date").
+			Context(ctx).
+			Do()
+		if err != nil {
+			return err
 		}
 
-		wg.Add(1)
-		go func(idx int, threadID string) {
-			defer wg.Done()
-
-			// Acquire semaphore
-			select {
-			case sem <- struct{}{}:
-				defer func() { <-sem }()
-			case <-ctx.Done():
-				results <- result{index: idx, err: ctx.Err()}
-				return
-			}
-
-			thread, err := svc.Users.Threads.Get("me", threadID).
-				Format("metadata").
-				MetadataHeaders("From", "Subject", "Date").
-				Context(ctx).
-				Do()
-			if err != nil {
-				results <- result{index: idx, err: err}
-				return
-			}
-
-			item := threadItem{ID: threadID, MessageCount: len(thread.Messages)}
-			if first := firstMessage(thread); first != nil {
-				item.From = sanitizeTab(headerValue(first.Payload, "From"))
-				item.Subject = sanitizeTab(headerValue(first.Payload, "Subject"))
-				if len(first.LabelIds) > 0 {
-					names := make([]string, 0, len(first.LabelIds))
-					for _, lid := range first.LabelIds {
-						if n, ok := idToName[lid]; ok {
-							names = append(names, n)
-						} else {
-							names = append(names, lid)
-						}
+		item := threadItem{ID: threadID, MessageCount: len(thread.Messages)}
+		if first := firstMessage(thread); first != nil {
+			item.From = sanitizeTab(headerValue(first.Payload, "From"))
+			item.Subject = sanitizeTab(headerValue(first.Payload, "Subject"))
+			if len(first.LabelIds) > 0 {
+				names := make([]string, 0, len(first.LabelIds))
+				for _, lid := range first.LabelIds {
+					if n, ok := idToName[lid]; ok {
+						names = append(names, n)
+					} else {
+						names = append(names, lid)
 					}
-					item.Labels = names
 				}
+				item.Labels = names
 			}
-			// Date from newest message by default, oldest if --oldest
-			dateMsg := newestMessageByDate(thread)
-			if oldest {
-				dateMsg = oldestMessageByDate(thread)
-			}
-			if dateMsg != nil {
-				item.Date = formatGmailDateInLocation(headerValue(dateMsg.Payload, "Date"), loc)
-			}
-
-			results <- result{index: idx, item: item}
-		}(i, t.Id)
-	}
-
-	// Close results channel when all goroutines complete
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
-
-	// Collect results in order
-	ordered := make([]threadItem, len(threads))
-	hasErr := false
-	for r := range results {
-		if r.err != nil {
-			hasErr = true
-			ordered[r.index] = threadItem{ID: "", Date: "", From: "", Subject: "", Labels: nil, MessageCount: 0}
-			continue
 		}
-		ordered[r.index] = r.item
-	}
-
-	if hasErr {
-		// Re-run sequentially to find and return the first actual error
-		for _, t := range threads {
-			if t.Id == "" {
-				continue
-			}
-			_, err := svc.Users.Threads.Get("me", t.Id).
-				Format("metadata").
-				MetadataHeaders("From", "Subject", "Date").
-				Context(ctx).
-				Do()
-			if err != nil {
-				return nil, err
-			}
+		// Date from newest message by default, oldest if --oldest
+		dateMsg := newestMessageByDate(thread)
+		if oldest {
+			dateMsg = oldestMessageByDate(thread)
 		}
-	}
-
-	items := make([]threadItem, 0, len(ordered))
-	for _, item := range ordered {
-		if item.ID != "" {
-			items = append(items, item)
+		if dateMsg != nil {
+			item.Date = formatGmailDateInLocation(headerValue(dateMsg.Payload, "Date"), loc)
 		}
+
+		items[idx] = item
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	return items, nil
 }
@@ -0,0 +1,288 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+type GmailAliasCmd struct {
+	Suggest GmailAliasSuggestCmd `cmd:"" name:"suggest" help:"Generate a plus-address for a service and record it"`
+	List    GmailAliasListCmd    `cmd:"" name:"list" help:"List recorded plus-address tags"`
+	Remove  GmailAliasRemoveCmd  `cmd:"" name:"remove" aliases:"rm" help:"Forget a recorded tag"`
+}
+
+var aliasTagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,63}$`)
+
+type aliasTagEntry struct {
+	Tag       string `json:"tag"`
+	Address   string `json:"address"`
+	Base      string `json:"base"`
+	Note      string `json:"note,omitempty"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// aliasTagRegistry records which plus-address was handed to which service so
+// leaks can be traced back and filtered. It lives in the local state dir.
+type aliasTagRegistry struct {
+	path    string
+	Entries []aliasTagEntry `json:"entries"`
+}
+
+func loadAliasTagRegistry() (*aliasTagRegistry, error) {
+	path, err := config.GmailAliasTagsPath()
+	if err != nil {
+		return nil, err
+	}
+	reg := &aliasTagRegistry{path: path}
+	data, err := os.ReadFile(path) //nolint:gosec // path under config dir
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return reg, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, reg); err != nil {
+		return nil, fmt.Errorf("parse alias tag registry: %w", err)
+	}
+	return reg, nil
+}
+
+func (r *aliasTagRegistry) save() error {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o700); err != nil {
+		return fmt.Errorf("ensure state dir: %w", err)
+	}
+	sort.SliceStable(r.Entries, func(i, j int) bool {
+		if r.Entries[i].Tag != r.Entries[j].Tag {
+			return r.Entries[i].Tag < r.Entries[j].Tag
+		}
+		return r.Entries[i].Address < r.Entries[j].Address
+	})
+	payload, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(payload, '\n'), 0o600)
+}
+
+func (r *aliasTagRegistry) byTag(tag string) []aliasTagEntry {
+	var out []aliasTagEntry
+	for _, e := range r.Entries {
+		if e.Tag == tag {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func (r *aliasTagRegistry) find(address string) (aliasTagEntry, bool) {
+	for _, e := range r.Entries {
+		if strings.EqualFold(e.Address, address) {
+			return e, true
+		}
+	}
+	return aliasTagEntry{}, false
+}
+
+func normalizeAliasTag(raw string) (string, error) {
+	tag := strings.ToLower(strings.TrimSpace(raw))
+	if !aliasTagPattern.MatchString(tag) {
+		return "", usagef("invalid tag %q (use letters, digits, '.', '_' or '-')", raw)
+	}
+	return tag, nil
+}
+
+// plusAddress builds local+tag@domain, replacing any existing +suffix on base.
+func plusAddress(base, tag string) (string, error) {
+	base = strings.ToLower(strings.TrimSpace(base))
+	local, domain, ok := strings.Cut(base, "@")
+	if !ok || local == "" || domain == "" || strings.Contains(domain, "@") {
+		return "", usagef("invalid base address %q", base)
+	}
+	if i := strings.IndexByte(local, '+'); i >= 0 {
+		local = local[:i]
+	}
+	return local + "+" + tag + "@" + domain, nil
+}
+
+// aliasTagQuery returns a Gmail query matching mail delivered to any address
+// recorded for tag, falling back to account+tag when the tag is unknown.
+func aliasTagQuery(rawTag string, account string) (string, error) {
+	tag, err := normalizeAliasTag(rawTag)
+	if err != nil {
+		return "", err
+	}
+	reg, err := loadAliasTagRegistry()
+	if err != nil {
+		return "", err
+	}
+	var addrs []string
+	for _, e := range reg.byTag(tag) {
+		addrs = append(addrs, e.Address)
+	}
+	if len(addrs) == 0 {
+		addr, addrErr := plusAddress(account, tag)
+		if addrErr != nil {
+			return "", addrErr
+		}
+		addrs = append(addrs, addr)
+	}
+	terms := make([]string, 0, len(addrs)*2)
+	for _, a := range addrs {
+		terms = append(terms, "deliveredto:"+a, "to:"+a)
+	}
+	return "(" + strings.Join(terms, " OR ") + ")", nil
+}
+
+type GmailAliasSuggestCmd struct {
+	Base   string `name:"base" help:"Base address (default: the selected account)"`
+	Tag    string `name:"tag" help:"Tag for the service receiving the address (e.g. netflix) (required)"`
+	Note   string `name:"note" help:"Free-form note stored with the tag"`
+	DryRun bool   `name:"dry-run" help:"Print the address without recording it"`
+}
+
+func (c *GmailAliasSuggestCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+
+	base := strings.TrimSpace(c.Base)
+	if base == "" {
+		account, err := requireAccount(flags)
+		if err != nil {
+			return err
+		}
+		base = account
+	}
+	if strings.TrimSpace(c.Tag) == "" {
+		return usage("required: --tag")
+	}
+	tag, err := normalizeAliasTag(c.Tag)
+	if err != nil {
+		return err
+	}
+	address, err := plusAddress(base, tag)
+	if err != nil {
+		return err
+	}
+
+	reg, err := loadAliasTagRegistry()
+	if err != nil {
+		return err
+	}
+	entry, existing := reg.find(address)
+	if !existing {
+		entry = aliasTagEntry{
+			Tag:       tag,
+			Address:   address,
+			Base:      strings.ToLower(strings.TrimSpace(base)),
+			Note:      strings.TrimSpace(c.Note),
+			CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		}
+		if !c.DryRun {
+			reg.Entries = append(reg.Entries, entry)
+			if err := reg.save(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"alias":    entry,
+			"existing": existing,
+			"recorded": existing || !c.DryRun,
+		})
+	}
+	u.Out().Println(address)
+	if existing {
+		u.Err().Printf("Tag %q already recorded on %s", tag, entry.CreatedAt)
+	}
+	return nil
+}
+
+type GmailAliasListCmd struct {
+	Tag string `name:"tag" help:"Only show this tag"`
+}
+
+func (c *GmailAliasListCmd) Run(ctx context.Context, _ *RootFlags) error {
+	u := ui.FromContext(ctx)
+	reg, err := loadAliasTagRegistry()
+	if err != nil {
+		return err
+	}
+	entries := reg.Entries
+	if strings.TrimSpace(c.Tag) != "" {
+		tag, tagErr := normalizeAliasTag(c.Tag)
+		if tagErr != nil {
+			return tagErr
+		}
+		entries = reg.byTag(tag)
+	}
+
+	if outfmt.IsJSON(ctx) {
+		if entries == nil {
+			entries = []aliasTagEntry{}
+		}
+		return outfmt.WriteJSON(os.Stdout, map[string]any{"aliases": entries})
+	}
+	if len(entries) == 0 {
+		u.Err().Println("No alias tags")
+		return nil
+	}
+
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "TAG\tADDRESS\tCREATED\tNOTE")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.Tag, e.Address, e.CreatedAt, sanitizeTab(e.Note))
+	}
+	return nil
+}
+
+type GmailAliasRemoveCmd struct {
+	Tag string `arg:"" name:"tag" help:"Tag to forget"`
+}
+
+func (c *GmailAliasRemoveCmd) Run(ctx context.Context, _ *RootFlags) error {
+	u := ui.FromContext(ctx)
+	tag, err := normalizeAliasTag(c.Tag)
+	if err != nil {
+		return err
+	}
+	reg, err := loadAliasTagRegistry()
+	if err != nil {
+		return err
+	}
+	kept := reg.Entries[:0]
+	removed := 0
+	for _, e := range reg.Entries {
+		if e.Tag == tag {
+			removed++
+			continue
+		}
+		kept = append(kept, e)
+	}
+	if removed == 0 {
+		return usagef("unknown tag %q", tag)
+	}
+	reg.Entries = kept
+	if err := reg.save(); err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{"removed": removed, "tag": tag})
+	}
+	u.Out().Printf("Removed %d address(es) for tag %q", removed, tag)
+	return nil
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPlusAddress(t *testing.T) {
+	got, err := plusAddress("Me+old@Gmail.com", "netflix")
+	if err != nil {
+		t.Fatalf("plusAddress: %v", err)
+	}
+	if got != "me+netflix@gmail.com" {
+		t.Fatalf("got %q", got)
+	}
+	if _, err := plusAddress("nope", "x"); err == nil {
+		t.Fatalf("expected error for invalid base")
+	}
+}
+
+func TestNormalizeAliasTag(t *testing.T) {
+	if got, err := normalizeAliasTag(" Netflix "); err != nil || got != "netflix" {
+		t.Fatalf("got %q, %v", got, err)
+	}
+	for _, bad := range []string{"", "a b", "+x", "x@y"} {
+		if _, err := normalizeAliasTag(bad); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestGmailAlias_SuggestListRemove(t *testing.T) {
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "gmail", "alias", "suggest", "--base", "me@gmail.com", "--tag", "Netflix", "--note", "streaming"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	var suggested struct {
+		Alias struct {
+			Address string `json:"address"`
+			Tag     string `json:"tag"`
+		} `json:"alias"`
+		Existing bool `json:"existing"`
+		Recorded bool `json:"recorded"`
+	}
+	if err := json.Unmarshal([]byte(out), &suggested); err != nil {
+		t.Fatalf("json parse: %v\nout=%q", err, out)
+	}
+	if suggested.Alias.Address != "me+netflix@gmail.com" || suggested.Existing || !suggested.Recorded {
+		t.Fatalf("unexpected suggest: %#v", suggested)
+	}
+
+	// Suggesting again reports the existing entry instead of duplicating it.
+	out = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "gmail", "alias", "suggest", "--base", "me@gmail.com", "--tag", "netflix"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if !strings.Contains(out, `"existing": true`) {
+		t.Fatalf("expected existing, got %q", out)
+	}
+
+	out = captureStdout(t, func() {
+		if err := Execute([]string{"--json", "gmail", "alias", "list"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	var listed struct {
+		Aliases []aliasTagEntry `json:"aliases"`
+	}
+	if err := json.Unmarshal([]byte(out), &listed); err != nil {
+		t.Fatalf("json parse: %v\nout=%q", err, out)
+	}
+	if len(listed.Aliases) != 1 || listed.Aliases[0].Note != "streaming" {
+		t.Fatalf("unexpected list: %#v", listed.Aliases)
+	}
+
+	_ = captureStdout(t, func() {
+		if err := Execute([]string{"gmail", "alias", "remove", "netflix"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	_ = captureStderr(t, func() {
+		if err := Execute([]string{"gmail", "alias", "remove", "netflix"}); err == nil || ExitCode(err) != 2 {
+			t.Fatalf("expected usage error for unknown tag, got %v", err)
+		}
+	})
+}
+
+func TestGmailMessagesSearch_AliasTag(t *testing.T) {
+	_ = captureStdout(t, func() {
+		if err := Execute([]string{"gmail", "alias", "suggest", "--base", "me@gmail.com", "--tag", "shop"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	t.Cleanup(func() {
+		_ = captureStdout(t, func() { _ = Execute([]string{"gmail", "alias", "remove", "shop"}) })
+	})
+
+	var gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/gmail/v1")
+		w.Header().Set("Content-Type", "application/json")
+		switch path {
+		case "/users/me/messages":
+			gotQuery = r.URL.Query().Get("q")
+			_ = json.NewEncoder(w).Encode(map[string]any{"messages": []any{}})
+		case "/users/me/labels":
+			_ = json.NewEncoder(w).Encode(map[string]any{"labels": []any{}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	stubGmailService(t, srv)
+
+	_ = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "me@gmail.com", "gmail", "messages", "search", "--alias-tag", "shop", "is:unread"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	want := "(deliveredto:me+shop@gmail.com OR to:me+shop@gmail.com) is:unread"
+	if gotQuery != want {
+		t.Fatalf("query=%q want %q", gotQuery, want)
+	}
+}
@@ -0,0 +1,321 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/gmail/v1"
+	gapi "google.golang.org/api/googleapi"
+
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+const (
+	driveFolderMimeType = "application/vnd.google-apps.folder"
+
+	// appProperties written on uploaded attachments so re-runs skip them.
+	driveAppPropGmailMessageID    = "gogGmailMessageId"
+	driveAppPropGmailAttachmentID = "gogGmailAttachment"
+)
+
+type GmailAttachmentsCmd struct {
+	SaveToDrive GmailAttachmentsSaveToDriveCmd `cmd:"" name:"save-to-drive" help:"Copy attachments of matching messages into a Drive folder"`
+}
+
+type GmailAttachmentsSaveToDriveCmd struct {
+	Query       string   `name:"query" short:"q" help:"Gmail query selecting messages (has:attachment is added) (required)"`
+	DriveFolder string   `name:"drive-folder" help:"Destination folder path from My Drive root (created if missing), e.g. Receipts/2026"`
+	Parent      string   `name:"parent" help:"Destination folder ID (alternative to --drive-folder)"`
+	Max         int64    `name:"max" aliases:"limit" help:"Max messages to process" default:"50"`
+	Match       string   `name:"match" help:"Only attachments whose filename matches this glob (e.g. '*.pdf')"`
+	MinSize     ByteSize `name:"min-size" help:"Only attachments at least this large (e.g. 500K, 5M)"`
+	MaxSize     ByteSize `name:"max-size" help:"Only attachments at most this large (e.g. 25M, 1.2GB)"`
+	DryRun      bool     `name:"dry-run" help:"List what would be uploaded without uploading"`
+}
+
+type savedAttachment struct {
+	MessageID   string `json:"messageId"`
+	Filename    string `json:"filename"`
+	MimeType    string `json:"mimeType,omitempty"`
+	Size        int64  `json:"size"`
+	DriveFileID string `json:"driveFileId,omitempty"`
+	Link        string `json:"link,omitempty"`
+	Status      string `json:"status"`
+}
+
+func (c *GmailAttachmentsSaveToDriveCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+
+	query := strings.TrimSpace(c.Query)
+	if query == "" {
+		return usage("required: --query")
+	}
+	folderPath := strings.Trim(strings.TrimSpace(c.DriveFolder), "/")
+	parentID := strings.TrimSpace(c.Parent)
+	if (folderPath == "") == (parentID == "") {
+		return usage("specify exactly one of --drive-folder or --parent")
+	}
+	if c.Max <= 0 {
+		return usage("--max must be > 0")
+	}
+	if c.Match != "" {
+		if _, matchErr := path.Match(c.Match, ""); matchErr != nil {
+			return usagef("invalid --match pattern: %v", matchErr)
+		}
+	}
+	if c.MaxSize > 0 && c.MinSize > c.MaxSize {
+		return usagef("--min-size %s is larger than --max-size %s", c.MinSize, c.MaxSize)
+	}
+	if !strings.Contains(strings.ToLower(query), "has:attachment") {
+		query += " has:attachment"
+	}
+	if c.MinSize > 0 {
+		// A message is at least as large as its attachments.
+		query += fmt.Sprintf(" larger:%d", int64(c.MinSize))
+	}
+
+	gsvc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+	dsvc, err := newDriveService(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	ids, err := listMessageIDs(ctx, gsvc, query, c.Max)
+	if err != nil {
+		return err
+	}
+	messages := make([]*gmail.Message, len(ids))
+	err = fetchGmailConcurrently(ctx, len(ids), gmailQuotaMessageGet, func(ctx context.Context, idx int) error {
+		msg, getErr := gsvc.Users.Messages.Get("me", ids[idx]).Format("full").Context(ctx).Do()
+		if getErr != nil {
+			return fmt.Errorf("message %s: %w", ids[idx], getErr)
+		}
+		messages[idx] = msg
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if folderPath != "" && !c.DryRun {
+		parentID, err = ensureDriveFolderPath(ctx, dsvc, folderPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	results := make([]savedAttachment, 0)
+	for _, msg := range messages {
+		if msg == nil {
+			continue
+		}
+		for _, a := range collectAttachments(msg.Payload) {
+			if c.Match != "" {
+				if ok, _ := path.Match(strings.ToLower(c.Match), strings.ToLower(a.Filename)); !ok {
+					continue
+				}
+			}
+			if a.Size < int64(c.MinSize) || (c.MaxSize > 0 && a.Size > int64(c.MaxSize)) {
+				continue
+			}
+			item := savedAttachment{MessageID: msg.Id, Filename: a.Filename, MimeType: a.MimeType, Size: a.Size}
+			if c.DryRun {
+				item.Status = "planned"
+				results = append(results, item)
+				continue
+			}
+
+			existing, findErr := findSavedAttachment(ctx, dsvc, parentID, msg.Id, a)
+			if findErr != nil {
+				return findErr
+			}
+			if existing != nil {
+				item.DriveFileID = existing.Id
+				item.Link = existing.WebViewLink
+				item.Status = "exists"
+				results = append(results, item)
+				continue
+			}
+
+			created, upErr := copyAttachmentToDrive(ctx, gsvc, dsvc, account, msg, a, parentID)
+			if upErr != nil {
+				return fmt.Errorf("save %s from message %s: %w", a.Filename, msg.Id, upErr)
+			}
+			item.DriveFileID = created.Id
+			item.Link = created.WebViewLink
+			item.Status = "uploaded"
+			results = append(results, item)
+		}
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"folderId":    parentID,
+			"attachments": results,
+			"count":       len(results),
+			"dryRun":      c.DryRun,
+		})
+	}
+
+	if len(results) == 0 {
+		u.Err().Println("No attachments")
+		return nil
+	}
+
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "STATUS\tMESSAGE\tFILENAME\tSIZE\tDRIVE_ID")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.Status, r.MessageID, sanitizeTab(r.Filename), formatBytes(r.Size), r.DriveFileID)
+	}
+	return nil
+}
+
+// copyAttachmentToDrive decodes the attachment payload while uploading it, so
+// nothing is written to local disk.
+func copyAttachmentToDrive(ctx context.Context, gsvc *gmail.Service, dsvc *drive.Service, account string, msg *gmail.Message, a attachmentInfo, parentID string) (*drive.File, error) {
+	body, err := gsvc.Users.Messages.Attachments.Get("me", msg.Id, a.AttachmentID).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	if body == nil || body.Data == "" {
+		return nil, errors.New("empty attachment data")
+	}
+	// Gmail can return padded base64url; strip padding and decode raw.
+	data := base64.NewDecoder(base64.RawURLEncoding, strings.NewReader(strings.TrimRight(body.Data, "=")))
+
+	mimeType := a.MimeType
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	meta := &drive.File{
+		Name:        a.Filename,
+		Parents:     []string{parentID},
+		Description: attachmentDriveDescription(account, msg),
+		AppProperties: map[string]string{
+			driveAppPropGmailMessageID:    msg.Id,
+			driveAppPropGmailAttachmentID: attachmentKey(a),
+		},
+	}
+	return dsvc.Files.Create(meta).
+		SupportsAllDrives(true).
+		Media(data, gapi.ContentType(mimeType)).
+		Fields("id, name, webViewLink").
+		Context(ctx).
+		Do()
+}
+
+func attachmentDriveDescription(account string, msg *gmail.Message) string {
+	lines := []string{
+		fmt.Sprintf("Saved from Gmail: https://mail.google.com/mail/?authuser=%s#all/%s", url.QueryEscape(account), msg.Id),
+	}
+	if subject := headerValue(msg.Payload, "Subject"); subject != "" {
+		lines = append(lines, "Subject: "+subject)
+	}
+	if from := headerValue(msg.Payload, "From"); from != "" {
+		lines = append(lines, "From: "+from)
+	}
+	if date := headerValue(msg.Payload, "Date"); date != "" {
+		lines = append(lines, "Date: "+date)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// attachmentKey identifies an attachment within its message by MIME part ID,
+// since one message can carry several files with the same name (Gmail's
+// attachment IDs change between fetches). The filename keeps it readable;
+// the key stays within Drive's 124-byte key+value limit for appProperties.
+func attachmentKey(a attachmentInfo) string {
+	key, _ := truncateUTF8Bytes(a.PartID+":"+a.Filename, 100)
+	return key
+}
+
+func findSavedAttachment(ctx context.Context, dsvc *drive.Service, parentID, messageID string, a attachmentInfo) (*drive.File, error) {
+	q := fmt.Sprintf("'%s' in parents and trashed = false and appProperties has { key='%s' and value='%s' } and appProperties has { key='%s' and value='%s' }",
+		escapeDriveQueryString(parentID),
+		driveAppPropGmailMessageID, escapeDriveQueryString(messageID),
+		driveAppPropGmailAttachmentID, escapeDriveQueryString(attachmentKey(a)),
+	)
+	resp, err := dsvc.Files.List().
+		Q(q).
+		PageSize(1).
+		SupportsAllDrives(true).
+		IncludeItemsFromAllDrives(true).
+		Fields("files(id, webViewLink)").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Files) == 0 {
+		return nil, nil
+	}
+	return resp.Files[0], nil
+}
+
+// ensureDriveFolderPath resolves a slash-separated folder path below My Drive,
+// creating any missing folders, and returns the ID of the last one.
+func ensureDriveFolderPath(ctx context.Context, dsvc *drive.Service, folderPath string) (string, error) {
+	parent := "root"
+	for _, name := range strings.Split(folderPath, "/") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		id, err := lookupDriveFolder(ctx, dsvc, parent, name)
+		if err != nil {
+			return "", err
+		}
+		if id != "" {
+			parent = id
+			continue
+		}
+		created, err := dsvc.Files.Create(&drive.File{
+			Name:     name,
+			MimeType: driveFolderMimeType,
+			Parents:  []string{parent},
+		}).SupportsAllDrives(true).Fields("id").Context(ctx).Do()
+		if err != nil {
+			return "", fmt.Errorf("create folder %q: %w", name, err)
+		}
+		parent = created.Id
+	}
+	return parent, nil
+}
+
+// lookupDriveFolder returns the ID of the folder called name directly below
+// parent, or "" when there is none.
+func lookupDriveFolder(ctx context.Context, dsvc *drive.Service, parent, name string) (string, error) {
+	q := fmt.Sprintf("name = '%s' and '%s' in parents and mimeType = '%s' and trashed = false",
+		escapeDriveQueryString(name), escapeDriveQueryString(parent), driveFolderMimeType)
+	resp, err := dsvc.Files.List().
+		Q(q).
+		PageSize(1).
+		SupportsAllDrives(true).
+		IncludeItemsFromAllDrives(true).
+		Fields("files(id)").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return "", fmt.Errorf("resolve folder %q: %w", name, err)
+	}
+	if len(resp.Files) == 0 {
+		return "", nil
+	}
+	return resp.Files[0].Id, nil
+}
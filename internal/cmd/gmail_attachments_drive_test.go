@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+)
+
+func TestGmailAttachmentsSaveToDrive(t *testing.T) {
+	origDrive := newDriveService
+	t.Cleanup(func() { newDriveService = origDrive })
+
+	var createdFolders []string
+	var uploads []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		// Gmail
+		case r.Method == http.MethodGet && r.URL.Path == "/gmail/v1/users/me/messages":
+			if q := r.URL.Query().Get("q"); q != "from:billing has:attachment" {
+				t.Fatalf("unexpected gmail query %q", q)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"messages": []map[string]any{{"id": "m1"}}})
+		case r.Method == http.MethodGet && r.URL.Path == "/gmail/v1/users/me/messages/m1":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id": "m1",
+				"payload": map[string]any{
+					"mimeType": "multipart/mixed",
+					"headers":  []map[string]any{{"name": "Subject", "value": "Invoice 42"}},
+					"parts": []map[string]any{
+						{"partId": "0", "mimeType": "text/plain", "body": map[string]any{"data": base64.RawURLEncoding.EncodeToString([]byte("see attached"))}},
+						{"partId": "1", "mimeType": "application/pdf", "filename": "invoice.pdf", "body": map[string]any{"attachmentId": "att1", "size": 9}},
+						{"partId": "2", "mimeType": "image/png", "filename": "logo.png", "body": map[string]any{"attachmentId": "att2", "size": 3}},
+						// A second, different file with the same name.
+						{"partId": "3", "mimeType": "application/pdf", "filename": "invoice.pdf", "body": map[string]any{"attachmentId": "att3", "size": 11}},
+					},
+				},
+			})
+		case r.Method == http.MethodGet && r.URL.Path == "/gmail/v1/users/me/messages/m1/attachments/att1":
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": base64.URLEncoding.EncodeToString([]byte("hello pdf"))})
+		case r.Method == http.MethodGet && r.URL.Path == "/gmail/v1/users/me/messages/m1/attachments/att3":
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": base64.URLEncoding.EncodeToString([]byte("credit note"))})
+
+		// Drive
+		case r.Method == http.MethodGet && (r.URL.Path == "/files" || r.URL.Path == "/drive/v3/files"):
+			q := r.URL.Query().Get("q")
+			switch {
+			case strings.Contains(q, "name = 'Receipts'"):
+				_ = json.NewEncoder(w).Encode(map[string]any{"files": []map[string]any{{"id": "receipts"}}})
+			case strings.Contains(q, "value='1:invoice.pdf'"):
+				// Saved by an earlier run.
+				_ = json.NewEncoder(w).Encode(map[string]any{"files": []map[string]any{{"id": "saved1"}}})
+			default:
+				_ = json.NewEncoder(w).Encode(map[string]any{"files": []map[string]any{}})
+			}
+		case r.Method == http.MethodPost && (r.URL.Path == "/files" || r.URL.Path == "/drive/v3/files"):
+			var f drive.File
+			_ = json.NewDecoder(r.Body).Decode(&f)
+			if f.MimeType != driveFolderMimeType || len(f.Parents) != 1 || f.Parents[0] != "receipts" {
+				t.Fatalf("unexpected folder create: %#v", f)
+			}
+			createdFolders = append(createdFolders, f.Name)
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "folder2026"})
+		case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/upload/"):
+			body, _ := io.ReadAll(r.Body)
+			uploads = append(uploads, string(body))
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "up1", "webViewLink": "https://drive.example/up1"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	stubGmailService(t, srv)
+
+	dsvc, err := drive.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newDriveService = func(context.Context, string) (*drive.Service, error) { return dsvc, nil }
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "a@b.com", "gmail", "attachments", "save-to-drive",
+				"--query", "from:billing", "--drive-folder", "/Receipts/2026/", "--match", "*.PDF"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+
+	if strings.Join(createdFolders, ",") != "2026" {
+		t.Fatalf("createdFolders=%v", createdFolders)
+	}
+	if len(uploads) != 1 {
+		t.Fatalf("expected 1 upload, got %d", len(uploads))
+	}
+	for _, want := range []string{"credit note", "Invoice 42", "#all/m1", driveAppPropGmailMessageID, `"3:invoice.pdf"`, `"parents":["folder2026"]`} {
+		if !strings.Contains(uploads[0], want) {
+			t.Fatalf("upload missing %q:\n%s", want, uploads[0])
+		}
+	}
+
+	var parsed struct {
+		FolderID    string            `json:"folderId"`
+		Attachments []savedAttachment `json:"attachments"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json parse: %v\nout=%q", err, out)
+	}
+	if parsed.FolderID != "folder2026" || len(parsed.Attachments) != 2 || parsed.Attachments[0].Status != "exists" || parsed.Attachments[1].Status != "uploaded" || parsed.Attachments[1].Filename != "invoice.pdf" {
+		t.Fatalf("unexpected result: %#v", parsed)
+	}
+}
+
+func TestGmailAttachmentsSaveToDrive_RequiresOneDestination(t *testing.T) {
+	_ = captureStderr(t, func() {
+		err := Execute([]string{"--account", "a@b.com", "gmail", "attachments", "save-to-drive", "--query", "x"})
+		if err == nil || ExitCode(err) != 2 {
+			t.Fatalf("expected usage error, got %v", err)
+		}
+	})
+}
@@ -58,7 +58,6 @@ type GmailAutoForwardUpdateCmd struct {
 }
 
 func (c *GmailAutoForwardUpdateCmd) Run(ctx context.Context, kctx *kong.Context, flags *RootFlags) error {
-	u := ui.FromContext(ctx)
 	account, err := requireAccount(flags)
 	if err != nil {
 		return err
@@ -73,8 +72,47 @@ func (c *GmailAutoForwardUpdateCmd) Run(ctx context.Context, kctx *kong.Context,
 		return err
 	}
 
+	return updateAutoForwarding(ctx, svc, func(autoForward *gmail.AutoForwarding) error {
+		if c.Enable {
+			autoForward.Enabled = true
+		}
+		if c.Disable {
+			autoForward.Enabled = false
+		}
+		if flagProvided(kctx, "email") {
+			autoForward.EmailAddress = c.Email
+		}
+		if flagProvided(kctx, "disposition") {
+			if err := validateAutoForwardDisposition(c.Disposition); err != nil {
+				return err
+			}
+			autoForward.Disposition = c.Disposition
+		}
+		return nil
+	})
+}
+
+func validateAutoForwardDisposition(disposition string) error {
+	validDispositions := map[string]bool{
+		"leaveInInbox": true,
+		"archive":      true,
+		"trash":        true,
+		"markRead":     true,
+	}
+	if !validDispositions[disposition] {
+		return errors.New("invalid disposition value; must be one of: leaveInInbox, archive, trash, markRead")
+	}
+	return nil
+}
+
+// updateAutoForwarding applies change to the current auto-forwarding
+// settings, so fields the caller leaves alone keep their values, and prints
+// the result. Shared by `autoforward update` and `forwarding set`.
+func updateAutoForwarding(ctx context.Context, svc *gmail.Service, change func(*gmail.AutoForwarding) error) error {
+	u := ui.FromContext(ctx)
+
 	// Get current settings first
-	current, err := svc.Users.Settings.GetAutoForwarding("me").Do()
+	current, err := svc.Users.Settings.GetAutoForwarding("me").Context(ctx).Do()
 	if err != nil {
 		return err
 	}
@@ -85,32 +123,11 @@ func (c *GmailAutoForwardUpdateCmd) Run(ctx context.Context, kctx *kong.Context,
 		EmailAddress: current.EmailAddress,
 		Disposition:  current.Disposition,
 	}
-
-	// Apply flags
-	if c.Enable {
-		autoForward.Enabled = true
-	}
-	if c.Disable {
-		autoForward.Enabled = false
-	}
-	if flagProvided(kctx, "email") {
-		autoForward.EmailAddress = c.Email
-	}
-	if flagProvided(kctx, "disposition") {
-		// Validate disposition value
-		validDispositions := map[string]bool{
-			"leaveInInbox": true,
-			"archive":      true,
-			"trash":        true,
-			"markRead":     true,
-		}
-		if !validDispositions[c.Disposition] {
-			return errors.New("invalid disposition value; must be one of: leaveInInbox, archive, trash, markRead")
-		}
-		autoForward.Disposition = c.Disposition
+	if err := change(autoForward); err != nil {
+		return err
 	}
 
-	updated, err := svc.Users.Settings.UpdateAutoForwarding("me", autoForward).Do()
+	updated, err := svc.Users.Settings.UpdateAutoForwarding("me", autoForward).Context(ctx).Do()
 	if err != nil {
 		return err
 	}
@@ -52,8 +52,8 @@ func (c *GmailBatchDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
 
 type GmailBatchModifyCmd struct {
 	MessageIDs []string `arg:"" name:"messageId" help:"Message IDs"`
-	Add        string   `name:"add" help:"Labels to add (comma-separated, name or ID)"`
-	Remove     string   `name:"remove" help:"Labels to remove (comma-separated, name or ID)"`
+	Add        string   `name:"add" complete:"labels" help:"Labels to add (comma-separated, name or ID)"`
+	Remove     string   `name:"remove" complete:"labels" help:"Labels to remove (comma-separated, name or ID)"`
 }
 
 func (c *GmailBatchModifyCmd) Run(ctx context.Context, flags *RootFlags) error {
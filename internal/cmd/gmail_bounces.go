@@ -0,0 +1,396 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// bounceSearchQuery matches the senders and subjects MTAs commonly use for
+// delivery status notifications (RFC 3464) and non-standard bounce messages.
+const bounceSearchQuery = `(from:mailer-daemon OR from:postmaster OR subject:"Delivery Status Notification" OR subject:"Undeliverable" OR subject:"Undelivered Mail" OR subject:"Mail delivery failed" OR subject:"Returned mail")`
+
+type GmailBouncesCmd struct {
+	Scan GmailBouncesScanCmd `cmd:"" name:"scan" help:"Scan for bounce (delivery status) notifications"`
+}
+
+type GmailBouncesScanCmd struct {
+	Since SinceTime `name:"since" help:"Look back this far (e.g. 7d, 48h, 2w) or since a date (YYYY-MM-DD)" default:"7d"`
+	Query string    `name:"query" short:"q" help:"Additional Gmail query to narrow the scan"`
+	Max   int64     `name:"max" aliases:"limit" help:"Max bounce messages to inspect" default:"500"`
+	Label string    `name:"label" complete:"labels" help:"Apply this label to detected bounces (created if missing)"`
+}
+
+type bounceRecipient struct {
+	Recipient  string `json:"recipient"`
+	Action     string `json:"action,omitempty"`
+	Status     string `json:"status,omitempty"`
+	Class      string `json:"class,omitempty"`
+	Diagnostic string `json:"diagnostic,omitempty"`
+}
+
+type bounceItem struct {
+	MessageID       string            `json:"messageId"`
+	ThreadID        string            `json:"threadId,omitempty"`
+	Date            string            `json:"date,omitempty"`
+	From            string            `json:"from,omitempty"`
+	Subject         string            `json:"subject,omitempty"`
+	OriginalSubject string            `json:"originalSubject,omitempty"`
+	OriginalMsgID   string            `json:"originalMessageId,omitempty"`
+	Recipients      []bounceRecipient `json:"recipients"`
+}
+
+func (c *GmailBouncesScanCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+
+	since := c.Since.Time(time.Now())
+	if c.Max <= 0 {
+		return usage("--max must be > 0")
+	}
+
+	query := fmt.Sprintf("%s after:%d", bounceSearchQuery, since.Unix())
+	if extra := strings.TrimSpace(c.Query); extra != "" {
+		query += " " + extra
+	}
+
+	svc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	ids, err := listMessageIDs(ctx, svc, query, c.Max)
+	if err != nil {
+		return err
+	}
+
+	items, err := fetchBounces(ctx, svc, ids)
+	if err != nil {
+		return err
+	}
+
+	labeled := 0
+	if label := strings.TrimSpace(c.Label); label != "" && len(items) > 0 {
+		labelID, labelErr := ensureLabelID(ctx, svc, label)
+		if labelErr != nil {
+			return labelErr
+		}
+		msgIDs := make([]string, 0, len(items))
+		for _, it := range items {
+			msgIDs = append(msgIDs, it.MessageID)
+		}
+		// batchModify accepts up to 1000 IDs per call.
+		for start := 0; start < len(msgIDs); start += 1000 {
+			end := min(start+1000, len(msgIDs))
+			if modErr := svc.Users.Messages.BatchModify("me", &gmail.BatchModifyMessagesRequest{
+				Ids:         msgIDs[start:end],
+				AddLabelIds: []string{labelID},
+			}).Context(ctx).Do(); modErr != nil {
+				return fmt.Errorf("apply label %q: %w", label, modErr)
+			}
+		}
+		labeled = len(msgIDs)
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"since":   since.UTC().Format(time.RFC3339),
+			"bounces": items,
+			"count":   len(items),
+			"summary": summarizeBounces(items),
+			"labeled": labeled,
+		})
+	}
+
+	if len(items) == 0 {
+		u.Err().Println("No bounces")
+		return nil
+	}
+
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "RECIPIENT\tSTATUS\tCLASS\tDATE\tMESSAGE\tDIAGNOSTIC")
+	for _, it := range items {
+		for _, r := range it.Recipients {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				sanitizeTab(r.Recipient),
+				r.Status,
+				r.Class,
+				it.Date,
+				it.MessageID,
+				sanitizeTab(truncateRunes(r.Diagnostic, 80)),
+			)
+		}
+	}
+	if labeled > 0 {
+		u.Err().Printf("Labeled %d bounce message(s) %q", labeled, c.Label)
+	}
+	return nil
+}
+
+func listMessageIDs(ctx context.Context, svc *gmail.Service, query string, limit int64) ([]string, error) {
+	var ids []string
+	pageToken := ""
+	for int64(len(ids)) < limit {
+		resp, err := svc.Users.Messages.List("me").
+			Q(query).
+			MaxResults(min(limit-int64(len(ids)), 500)).
+			PageToken(pageToken).
+			Fields("messages(id),nextPageToken").
+			Context(ctx).
+			Do()
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range resp.Messages {
+			if m != nil && m.Id != "" {
+				ids = append(ids, m.Id)
+			}
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+	return ids, nil
+}
+
+func fetchBounces(ctx context.Context, svc *gmail.Service, ids []string) ([]bounceItem, error) {
+	results := make([]*bounceItem, len(ids))
+	err := fetchGmailConcurrently(ctx, len(ids), gmailQuotaMessageGet, func(ctx context.Context, idx int) error {
+		msg, err := svc.Users.Messages.Get("me", ids[idx]).Format("full").Context(ctx).Do()
+		if err != nil {
+			return fmt.Errorf("message %s: %w", ids[idx], err)
+		}
+		results[idx] = parseBounceMessage(msg)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]bounceItem, 0, len(ids))
+	for _, it := range results {
+		if it != nil {
+			items = append(items, *it)
+		}
+	}
+	return items, nil
+}
+
+// parseBounceMessage extracts failed recipients from a bounce. It prefers the
+// machine-readable message/delivery-status part and falls back to the
+// X-Failed-Recipients header. Messages without any recipient are ignored.
+func parseBounceMessage(msg *gmail.Message) *bounceItem {
+	if msg == nil || msg.Payload == nil {
+		return nil
+	}
+	item := &bounceItem{
+		MessageID: msg.Id,
+		ThreadID:  msg.ThreadId,
+		Date:      formatGmailDateInLocation(headerValue(msg.Payload, "Date"), nil),
+		From:      headerValue(msg.Payload, "From"),
+		Subject:   headerValue(msg.Payload, "Subject"),
+	}
+
+	if part := findPartByMimeType(msg.Payload, "message/delivery-status"); part != nil {
+		if body, err := decodePartBody(part); err == nil {
+			item.Recipients = parseDeliveryStatus(body)
+		}
+	}
+
+	if len(item.Recipients) == 0 {
+		for _, addr := range splitCSV(headerValue(msg.Payload, "X-Failed-Recipients")) {
+			item.Recipients = append(item.Recipients, bounceRecipient{Recipient: addr, Action: "failed"})
+		}
+	}
+	if len(item.Recipients) == 0 {
+		return nil
+	}
+
+	if part := findPartByMimeType(msg.Payload, "text/rfc822-headers"); part != nil {
+		if body, err := decodePartBody(part); err == nil {
+			hdrs := parseHeaderBlock(body)
+			item.OriginalSubject = hdrs["subject"]
+			item.OriginalMsgID = hdrs["message-id"]
+		}
+	} else if part := findPartByMimeType(msg.Payload, "message/rfc822"); part != nil {
+		if len(part.Parts) > 0 && part.Parts[0] != nil {
+			item.OriginalSubject = headerValue(part.Parts[0], "Subject")
+			item.OriginalMsgID = headerValue(part.Parts[0], "Message-ID")
+		}
+	}
+	return item
+}
+
+func findPartByMimeType(p *gmail.MessagePart, mimeType string) *gmail.MessagePart {
+	if p == nil {
+		return nil
+	}
+	if mimeTypeMatches(p.MimeType, mimeType) {
+		return p
+	}
+	for _, child := range p.Parts {
+		if found := findPartByMimeType(child, mimeType); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// parseDeliveryStatus parses an RFC 3464 delivery-status body: one per-message
+// field group followed by one group per recipient, separated by blank lines.
+func parseDeliveryStatus(body string) []bounceRecipient {
+	var out []bounceRecipient
+	for _, group := range strings.Split(normalizeNewlines(body), "\n\n") {
+		fields := parseHeaderBlock(group)
+		recipient := dsnAddress(fields["final-recipient"])
+		if recipient == "" {
+			recipient = dsnAddress(fields["original-recipient"])
+		}
+		if recipient == "" {
+			continue
+		}
+		action := strings.ToLower(strings.TrimSpace(fields["action"]))
+		switch action {
+		case "delivered", "relayed", "expanded":
+			// Success notifications, not bounces.
+			continue
+		}
+		status := strings.TrimSpace(fields["status"])
+		if m := dsnStatusRegex.FindString(status); m != "" {
+			status = m
+		}
+		out = append(out, bounceRecipient{
+			Recipient:  recipient,
+			Action:     action,
+			Status:     status,
+			Class:      bounceClass(status),
+			Diagnostic: dsnAddress(fields["diagnostic-code"]),
+		})
+	}
+	return out
+}
+
+var dsnStatusRegex = regexp.MustCompile(`[245]\.\d{1,3}\.\d{1,3}`)
+
+// parseHeaderBlock parses RFC 822 style "Name: value" lines (with folded
+// continuation lines) into a lower-cased map. The first occurrence wins.
+func parseHeaderBlock(block string) map[string]string {
+	fields := map[string]string{}
+	lastKey := ""
+	scanner := bufio.NewScanner(strings.NewReader(normalizeNewlines(block)))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && lastKey != "" {
+			fields[lastKey] += " " + strings.TrimSpace(line)
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			lastKey = ""
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(name))
+		if _, exists := fields[key]; exists {
+			lastKey = ""
+			continue
+		}
+		fields[key] = strings.TrimSpace(value)
+		lastKey = key
+	}
+	return fields
+}
+
+// dsnAddress strips the address-type prefix ("rfc822;", "smtp;") from DSN fields.
+func dsnAddress(v string) string {
+	v = strings.TrimSpace(v)
+	if _, rest, ok := strings.Cut(v, ";"); ok {
+		v = strings.TrimSpace(rest)
+	}
+	return strings.Trim(v, "<>")
+}
+
+func bounceClass(status string) string {
+	switch {
+	case strings.HasPrefix(status, "5."):
+		return "hard"
+	case strings.HasPrefix(status, "4."):
+		return "soft"
+	case strings.HasPrefix(status, "2."):
+		return "delivered"
+	default:
+		return ""
+	}
+}
+
+func normalizeNewlines(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.ReplaceAll(s, "\r", "\n")
+}
+
+type bounceSummary struct {
+	Recipient string `json:"recipient"`
+	Count     int    `json:"count"`
+	Status    string `json:"status,omitempty"`
+	Class     string `json:"class,omitempty"`
+}
+
+// summarizeBounces groups bounces by recipient, keeping the most recent status.
+func summarizeBounces(items []bounceItem) []bounceSummary {
+	byRecipient := map[string]*bounceSummary{}
+	for _, it := range items {
+		for _, r := range it.Recipients {
+			key := strings.ToLower(r.Recipient)
+			s, ok := byRecipient[key]
+			if !ok {
+				s = &bounceSummary{Recipient: r.Recipient, Status: r.Status, Class: r.Class}
+				byRecipient[key] = s
+			}
+			s.Count++
+		}
+	}
+	out := make([]bounceSummary, 0, len(byRecipient))
+	for _, s := range byRecipient {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Recipient < out[j].Recipient
+	})
+	return out
+}
+
+func ensureLabelID(ctx context.Context, svc *gmail.Service, name string) (string, error) {
+	idMap, err := fetchLabelNameToID(svc)
+	if err != nil {
+		return "", err
+	}
+	if id, ok := idMap[strings.ToLower(name)]; ok {
+		return id, nil
+	}
+	created, err := createLabel(ctx, svc, name)
+	if err != nil {
+		return "", mapLabelCreateError(err, name)
+	}
+	return created.Id, nil
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testDeliveryStatus = "Reporting-MTA: dns; googlemail.com\r\n" +
+	"Arrival-Date: Mon, 12 Oct 2026 10:00:00 -0700\r\n" +
+	"\r\n" +
+	"Final-Recipient: rfc822; gone@example.com\r\n" +
+	"Action: failed\r\n" +
+	"Status: 5.1.1\r\n" +
+	"Diagnostic-Code: smtp; 550-5.1.1 The email account that you tried to reach\r\n" +
+	" does not exist.\r\n" +
+	"\r\n" +
+	"Final-Recipient: rfc822; slow@example.com\r\n" +
+	"Action: delayed\r\n" +
+	"Status: 4.4.7 (delivery time expired)\r\n" +
+	"\r\n" +
+	"Final-Recipient: rfc822; ok@example.com\r\n" +
+	"Action: delivered\r\n" +
+	"Status: 2.0.0\r\n"
+
+func TestParseDeliveryStatus(t *testing.T) {
+	got := parseDeliveryStatus(testDeliveryStatus)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 recipients, got %#v", got)
+	}
+	if got[0].Recipient != "gone@example.com" || got[0].Status != "5.1.1" || got[0].Class != "hard" || got[0].Action != "failed" {
+		t.Fatalf("unexpected first recipient: %#v", got[0])
+	}
+	if !strings.Contains(got[0].Diagnostic, "does not exist") || strings.HasPrefix(got[0].Diagnostic, "smtp") {
+		t.Fatalf("unexpected diagnostic: %q", got[0].Diagnostic)
+	}
+	if got[1].Recipient != "slow@example.com" || got[1].Status != "4.4.7" || got[1].Class != "soft" {
+		t.Fatalf("unexpected second recipient: %#v", got[1])
+	}
+}
+
+func TestExecute_GmailBouncesScan_JSONWithLabel(t *testing.T) {
+	dsn := base64.URLEncoding.EncodeToString([]byte(testDeliveryStatus))
+	headers := base64.URLEncoding.EncodeToString([]byte("Message-ID: <orig@example.com>\r\nSubject: Launch update\r\n"))
+
+	var modified struct {
+		Ids         []string `json:"ids"`
+		AddLabelIds []string `json:"addLabelIds"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/gmail/v1")
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && path == "/users/me/messages":
+			if q := r.URL.Query().Get("q"); !strings.Contains(q, "from:mailer-daemon") || !strings.Contains(q, "after:") {
+				t.Fatalf("unexpected query %q", q)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"messages": []map[string]any{{"id": "b1"}, {"id": "n1"}},
+			})
+		case r.Method == http.MethodGet && path == "/users/me/messages/b1":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id":       "b1",
+				"threadId": "t1",
+				"payload": map[string]any{
+					"mimeType": "multipart/report",
+					"headers": []map[string]any{
+						{"name": "From", "value": "Mail Delivery Subsystem <mailer-daemon@googlemail.com>"},
+						{"name": "Subject", "value": "Delivery Status Notification (Failure)"},
+					},
+					"parts": []map[string]any{
+						{"mimeType": "text/plain", "body": map[string]any{"data": base64.URLEncoding.EncodeToString([]byte("bounce"))}},
+						{"mimeType": "message/delivery-status", "body": map[string]any{"data": dsn}},
+						{"mimeType": "text/rfc822-headers", "body": map[string]any{"data": headers}},
+					},
+				},
+			})
+		case r.Method == http.MethodGet && path == "/users/me/messages/n1":
+			// Matches the search but is not a bounce (no DSN, no X-Failed-Recipients).
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id":      "n1",
+				"payload": map[string]any{"mimeType": "text/plain", "headers": []map[string]any{{"name": "Subject", "value": "Returned mail policy"}}},
+			})
+		case r.Method == http.MethodGet && path == "/users/me/labels":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"labels": []map[string]any{{"id": "Label_9", "name": "Bounces"}},
+			})
+		case r.Method == http.MethodPost && path == "/users/me/messages/batchModify":
+			if err := json.NewDecoder(r.Body).Decode(&modified); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	stubGmailService(t, srv)
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "a@b.com", "gmail", "bounces", "scan", "--since", "7d", "--label", "bounces"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+
+	var parsed struct {
+		Count   int `json:"count"`
+		Labeled int `json:"labeled"`
+		Bounces []struct {
+			MessageID       string `json:"messageId"`
+			OriginalSubject string `json:"originalSubject"`
+			OriginalMsgID   string `json:"originalMessageId"`
+			Recipients      []struct {
+				Recipient string `json:"recipient"`
+				Status    string `json:"status"`
+			} `json:"recipients"`
+		} `json:"bounces"`
+		Summary []struct {
+			Recipient string `json:"recipient"`
+			Count     int    `json:"count"`
+		} `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json parse: %v\nout=%q", err, out)
+	}
+	if parsed.Count != 1 || len(parsed.Bounces) != 1 || parsed.Bounces[0].MessageID != "b1" {
+		t.Fatalf("unexpected bounces: %#v", parsed)
+	}
+	if parsed.Bounces[0].OriginalSubject != "Launch update" || parsed.Bounces[0].OriginalMsgID != "<orig@example.com>" {
+		t.Fatalf("unexpected original: %#v", parsed.Bounces[0])
+	}
+	if len(parsed.Summary) != 2 {
+		t.Fatalf("unexpected summary: %#v", parsed.Summary)
+	}
+	if parsed.Labeled != 1 || len(modified.Ids) != 1 || modified.Ids[0] != "b1" || modified.AddLabelIds[0] != "Label_9" {
+		t.Fatalf("unexpected label modify: labeled=%d %#v", parsed.Labeled, modified)
+	}
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/people/v1"
+)
+
+const (
+	contactGroupsPageSize  = 1000
+	contactGroupMaxMembers = 10000
+	peopleBatchGetMax      = 200
+)
+
+// expandContactGroups resolves contact group names (or contactGroups/... resource
+// names) into the primary email addresses of their members.
+func expandContactGroups(ctx context.Context, account string, groups []string) ([]string, error) {
+	names := make([]string, 0, len(groups))
+	for _, g := range groups {
+		if g = strings.TrimSpace(g); g != "" {
+			names = append(names, g)
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	svc, err := newPeopleContactsService(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	available, err := listContactGroups(ctx, svc)
+	if err != nil {
+		return nil, wrapPeopleAPIError(err)
+	}
+
+	recipients := make([]string, 0)
+	for _, name := range names {
+		group, err := matchContactGroup(available, name)
+		if err != nil {
+			return nil, err
+		}
+		emails, err := contactGroupMemberEmails(ctx, svc, group.ResourceName)
+		if err != nil {
+			return nil, wrapPeopleAPIError(err)
+		}
+		if len(emails) == 0 {
+			return nil, fmt.Errorf("contact group %q has no members with an email address", name)
+		}
+		recipients = append(recipients, emails...)
+	}
+	return deduplicateAddresses(recipients), nil
+}
+
+func listContactGroups(ctx context.Context, svc *people.Service) ([]*people.ContactGroup, error) {
+	var out []*people.ContactGroup
+	pageToken := ""
+	for {
+		call := svc.ContactGroups.List().PageSize(contactGroupsPageSize).Context(ctx)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, resp.ContactGroups...)
+		if resp.NextPageToken == "" {
+			return out, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+func matchContactGroup(groups []*people.ContactGroup, name string) (*people.ContactGroup, error) {
+	for _, g := range groups {
+		if g != nil && g.ResourceName == name {
+			return g, nil
+		}
+	}
+	var match *people.ContactGroup
+	for _, g := range groups {
+		if g == nil {
+			continue
+		}
+		if strings.EqualFold(g.Name, name) || strings.EqualFold(g.FormattedName, name) {
+			if match != nil && match.ResourceName != g.ResourceName {
+				return nil, fmt.Errorf("contact group name %q is ambiguous; use the resource name (%s or %s)", name, match.ResourceName, g.ResourceName)
+			}
+			match = g
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("contact group %q not found", name)
+	}
+	return match, nil
+}
+
+func contactGroupMemberEmails(ctx context.Context, svc *people.Service, resourceName string) ([]string, error) {
+	group, err := svc.ContactGroups.Get(resourceName).MaxMembers(contactGroupMaxMembers).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	members := group.MemberResourceNames
+	emails := make([]string, 0, len(members))
+	for start := 0; start < len(members); start += peopleBatchGetMax {
+		end := start + peopleBatchGetMax
+		if end > len(members) {
+			end = len(members)
+		}
+		resp, err := svc.People.GetBatchGet().
+			ResourceNames(members[start:end]...).
+			PersonFields("emailAddresses").
+			Context(ctx).
+			Do()
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range resp.Responses {
+			if r == nil {
+				continue
+			}
+			if email := strings.TrimSpace(primaryEmail(r.Person)); email != "" {
+				emails = append(emails, email)
+			}
+		}
+	}
+	return emails, nil
+}
+
+// mergeContactGroupRecipients appends expanded contact group members to a
+// comma-separated recipient list.
+func mergeContactGroupRecipients(ctx context.Context, account string, to string, groups []string) (string, error) {
+	if len(groups) == 0 {
+		return to, nil
+	}
+	expanded, err := expandContactGroups(ctx, account, groups)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(deduplicateAddresses(append(splitCSV(to), expanded...)), ", "), nil
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+	"google.golang.org/api/people/v1"
+)
+
+func newContactGroupsTestService(t *testing.T) *people.Service {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1/contactGroups":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"contactGroups": []map[string]any{
+					{"resourceName": "contactGroups/family", "name": "Family", "formattedName": "Family", "groupType": "USER_CONTACT_GROUP", "memberCount": 3},
+					{"resourceName": "contactGroups/myContacts", "name": "myContacts", "formattedName": "My Contacts", "groupType": "SYSTEM_CONTACT_GROUP"},
+				},
+			})
+		case r.URL.Path == "/v1/contactGroups/family":
+			if got := r.URL.Query().Get("maxMembers"); got == "" {
+				t.Fatalf("expected maxMembers to be set")
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"resourceName":        "contactGroups/family",
+				"memberResourceNames": []string{"people/c1", "people/c2", "people/c3"},
+			})
+		case r.URL.Path == "/v1/people:batchGet":
+			if got := r.URL.Query()["resourceNames"]; len(got) != 3 {
+				t.Fatalf("resourceNames=%v", got)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"responses": []map[string]any{
+					{"person": map[string]any{"resourceName": "people/c1", "emailAddresses": []map[string]any{{"value": "mom@example.com"}}}},
+					{"person": map[string]any{"resourceName": "people/c2", "emailAddresses": []map[string]any{{"value": "dad@example.com"}}}},
+					{"person": map[string]any{"resourceName": "people/c3"}},
+				},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	svc, err := people.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	return svc
+}
+
+func TestExpandContactGroups(t *testing.T) {
+	origPeople := newPeopleContactsService
+	t.Cleanup(func() { newPeopleContactsService = origPeople })
+
+	svc := newContactGroupsTestService(t)
+	newPeopleContactsService = func(context.Context, string) (*people.Service, error) { return svc, nil }
+
+	got, err := expandContactGroups(context.Background(), "a@b.com", []string{"family", "contactGroups/family"})
+	if err != nil {
+		t.Fatalf("expandContactGroups: %v", err)
+	}
+	if strings.Join(got, ",") != "mom@example.com,dad@example.com" {
+		t.Fatalf("unexpected recipients: %v", got)
+	}
+
+	if _, err := expandContactGroups(context.Background(), "a@b.com", []string{"Work"}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestMatchContactGroup_Ambiguous(t *testing.T) {
+	groups := []*people.ContactGroup{
+		{ResourceName: "contactGroups/a", Name: "Team"},
+		{ResourceName: "contactGroups/b", Name: "team"},
+	}
+	if _, err := matchContactGroup(groups, "Team"); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Fatalf("expected ambiguous error, got %v", err)
+	}
+	if g, err := matchContactGroup(groups, "contactGroups/b"); err != nil || g.ResourceName != "contactGroups/b" {
+		t.Fatalf("resource match: %v %#v", err, g)
+	}
+}
+
+func TestExecute_GmailSend_ToGroup(t *testing.T) {
+	origGmail := newGmailService
+	origPeople := newPeopleContactsService
+	t.Cleanup(func() {
+		newGmailService = origGmail
+		newPeopleContactsService = origPeople
+	})
+
+	peopleSvc := newContactGroupsTestService(t)
+	newPeopleContactsService = func(context.Context, string) (*people.Service, error) { return peopleSvc, nil }
+
+	var rawTo string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && strings.Contains(r.URL.Path, "/users/me/messages/send"):
+			body, _ := io.ReadAll(r.Body)
+			var msg gmail.Message
+			if err := json.Unmarshal(body, &msg); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			raw, err := base64.RawURLEncoding.DecodeString(msg.Raw)
+			if err != nil {
+				t.Fatalf("decode raw: %v", err)
+			}
+			for _, line := range strings.Split(string(raw), "\r\n") {
+				if strings.HasPrefix(line, "To: ") {
+					rawTo = strings.TrimPrefix(line, "To: ")
+				}
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "s1", "threadId": "t1"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	gmailSvc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return gmailSvc, nil }
+
+	_ = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{
+				"--json",
+				"--account", "a@b.com",
+				"gmail", "send",
+				"--to", "x@y.com",
+				"--to-group", "Family",
+				"--subject", "S",
+				"--body", "B",
+			}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+
+	if rawTo != "x@y.com, mom@example.com, dad@example.com" {
+		t.Fatalf("unexpected To header: %q", rawTo)
+	}
+}
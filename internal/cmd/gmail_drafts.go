@@ -209,7 +209,8 @@ func (c *GmailDraftsDeleteCmd) Run(ctx context.Context, flags *RootFlags) error
 }
 
 type GmailDraftsSendCmd struct {
-	DraftID string `arg:"" name:"draftId" help:"Draft ID"`
+	DraftID   string        `arg:"" name:"draftId" help:"Draft ID"`
+	Guardrail GuardrailFlag `embed:""`
 }
 
 func (c *GmailDraftsSendCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -228,6 +229,10 @@ func (c *GmailDraftsSendCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
+	if err = c.checkGuardrails(ctx, flags, svc, account, draftID); err != nil {
+		return err
+	}
+
 	msg, err := svc.Users.Drafts.Send("me", &gmail.Draft{Id: draftID}).Do()
 	if err != nil {
 		return err
@@ -245,18 +250,45 @@ func (c *GmailDraftsSendCmd) Run(ctx context.Context, flags *RootFlags) error {
 	return nil
 }
 
+// checkGuardrails applies the send guardrails to the draft's recipients and
+// attachments; the draft is only fetched when guardrails are configured.
+func (c *GmailDraftsSendCmd) checkGuardrails(ctx context.Context, flags *RootFlags, svc *gmail.Service, account, draftID string) error {
+	guardrails, err := config.SendGuardrailsFor(account)
+	if err != nil || len(guardrails) == 0 {
+		return err
+	}
+	draft, err := svc.Users.Drafts.Get("me", draftID).Format("full").Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+	if draft.Message == nil {
+		return nil
+	}
+	payload := draft.Message.Payload
+	var recipients []string
+	for _, h := range []string{"To", "Cc", "Bcc"} {
+		recipients = append(recipients, parseEmailAddresses(headerValue(payload, h))...)
+	}
+	return enforceSendGuardrails(ctx, flags, account, c.Guardrail, sendGuardrailCheck{
+		Recipients:     recipients,
+		HasAttachments: len(collectAttachments(payload)) > 0,
+	})
+}
+
 type GmailDraftsCreateCmd struct {
-	To               string   `name:"to" help:"Recipients (comma-separated)"`
-	Cc               string   `name:"cc" help:"CC recipients (comma-separated)"`
-	Bcc              string   `name:"bcc" help:"BCC recipients (comma-separated)"`
-	Subject          string   `name:"subject" help:"Subject (required)"`
-	Body             string   `name:"body" help:"Body (plain text; required unless --body-html is set)"`
-	BodyFile         string   `name:"body-file" help:"Body file path (plain text; '-' for stdin)"`
-	BodyHTML         string   `name:"body-html" help:"Body (HTML; optional)"`
-	ReplyToMessageID string   `name:"reply-to-message-id" help:"Reply to Gmail message ID (sets In-Reply-To/References and thread)"`
-	ReplyTo          string   `name:"reply-to" help:"Reply-To header address"`
-	Attach           []string `name:"attach" help:"Attachment file path (repeatable)"`
-	From             string   `name:"from" help:"Send from this email address (must be a verified send-as alias)"`
+	To               string          `name:"to" help:"Recipients (comma-separated)"`
+	ToGroup          []string        `name:"to-group" sep:"none" help:"Contact group to expand into individual To recipients (name or contactGroups/ID; repeatable)"`
+	Cc               string          `name:"cc" help:"CC recipients (comma-separated)"`
+	Bcc              string          `name:"bcc" help:"BCC recipients (comma-separated)"`
+	Subject          string          `name:"subject" help:"Subject (required)"`
+	Body             string          `name:"body" help:"Body (plain text; required unless --body-html is set)"`
+	BodyFile         string          `name:"body-file" help:"Body file path (plain text; '-' for stdin)"`
+	BodyHTML         string          `name:"body-html" help:"Body (HTML; optional)"`
+	ReplyToMessageID string          `name:"reply-to-message-id" help:"Reply to Gmail message ID (sets In-Reply-To/References and thread)"`
+	ReplyTo          string          `name:"reply-to" help:"Reply-To header address"`
+	Attach           []string        `name:"attach" help:"Attachment file path (repeatable)"`
+	From             string          `name:"from" help:"Send from this email address (must be a verified send-as alias)"`
+	Idempotency      IdempotencyFlag `embed:""`
 }
 
 type draftComposeInput struct {
@@ -364,7 +396,7 @@ func writeDraftResult(ctx context.Context, u *ui.UI, draft *gmail.Draft, threadI
 	return nil
 }
 
-func (c *GmailDraftsCreateCmd) Run(ctx context.Context, flags *RootFlags) error {
+func (c *GmailDraftsCreateCmd) Run(ctx context.Context, flags *RootFlags) (err error) {
 	u := ui.FromContext(ctx)
 	account, err := requireAccount(flags)
 	if err != nil {
@@ -376,8 +408,13 @@ func (c *GmailDraftsCreateCmd) Run(ctx context.Context, flags *RootFlags) error
 		return err
 	}
 
+	to, err := mergeContactGroupRecipients(ctx, account, c.To, c.ToGroup)
+	if err != nil {
+		return err
+	}
+
 	input := draftComposeInput{
-		To:               c.To,
+		To:               to,
 		Cc:               c.Cc,
 		Bcc:              c.Bcc,
 		Subject:          c.Subject,
@@ -393,6 +430,12 @@ func (c *GmailDraftsCreateCmd) Run(ctx context.Context, flags *RootFlags) error
 		return validateErr
 	}
 
+	idem, done, err := beginIdempotent(ctx, c.Idempotency.Key, account, "gmail.drafts.create")
+	if done || err != nil {
+		return err
+	}
+	defer idem.finish(&err)
+
 	svc, err := newGmailService(ctx, account)
 	if err != nil {
 		return err
@@ -407,12 +450,14 @@ func (c *GmailDraftsCreateCmd) Run(ctx context.Context, flags *RootFlags) error
 	if err != nil {
 		return err
 	}
+	idem.resource(draft.Id)
 	return writeDraftResult(ctx, u, draft, threadID)
 }
 
 type GmailDraftsUpdateCmd struct {
 	DraftID          string   `arg:"" name:"draftId" help:"Draft ID"`
 	To               *string  `name:"to" help:"Recipients (comma-separated; omit to keep existing)"`
+	ToGroup          []string `name:"to-group" sep:"none" help:"Contact group to expand into individual To recipients (name or contactGroups/ID; repeatable)"`
 	Cc               string   `name:"cc" help:"CC recipients (comma-separated)"`
 	Bcc              string   `name:"bcc" help:"BCC recipients (comma-separated)"`
 	Subject          string   `name:"subject" help:"Subject (required)"`
@@ -465,6 +510,10 @@ func (c *GmailDraftsUpdateCmd) Run(ctx context.Context, flags *RootFlags) error
 	if !toWasSet {
 		to = existingTo
 	}
+	to, err = mergeContactGroupRecipients(ctx, account, to, c.ToGroup)
+	if err != nil {
+		return err
+	}
 
 	body, err := resolveBodyInput(c.Body, c.BodyFile)
 	if err != nil {
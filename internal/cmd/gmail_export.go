@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/steipete/gogcli/internal/maildir"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+const (
+	maildirLayoutKeywords = "keywords"
+	maildirLayoutFolders  = "folders"
+)
+
+type GmailExportCmd struct {
+	Maildir GmailExportMaildirCmd `cmd:"" name:"maildir" help:"Export messages to a local Maildir (for notmuch, mu, mutt)"`
+}
+
+type GmailExportMaildirCmd struct {
+	Query  string `name:"query" short:"q" help:"Gmail search query (default: all mail except spam/trash)"`
+	Dir    string `name:"dir" help:"Maildir root (created if missing), or gs://bucket/path or s3://bucket/path to upload (required)"`
+	Layout string `name:"layout" help:"Label mapping: keywords (one maildir, labels in X-Keywords) or folders (one Maildir++ folder per label)" enum:"keywords,folders" default:"keywords"`
+	Max    int64  `name:"max" aliases:"limit" help:"Max messages to export" default:"1000"`
+}
+
+// maildirMessage is where one Gmail message lands and with which flags.
+type maildirMessage struct {
+	folders  []string
+	keywords []string
+	flags    string
+}
+
+func (c *GmailExportMaildirCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(c.Dir) == "" {
+		return usage("required: --dir")
+	}
+	if c.Max <= 0 {
+		return usage("--max must be > 0")
+	}
+	out, err := openOutputDir(strings.TrimSpace(c.Dir))
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	root := out.Local
+	if err := maildir.Ensure(root); err != nil {
+		return fmt.Errorf("create maildir: %w", err)
+	}
+
+	svc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+	labelNames, err := fetchLabelIDToName(svc)
+	if err != nil {
+		return err
+	}
+	ids, err := listMessageIDs(ctx, svc, strings.TrimSpace(c.Query), c.Max)
+	if err != nil {
+		return err
+	}
+
+	// Messages are keyed by Gmail ID in the filename, so re-runs only fetch
+	// what is new.
+	existing, err := maildir.UniqueIDs(root)
+	if err != nil {
+		return err
+	}
+	var pending []string
+	for _, id := range ids {
+		if !existing[id] {
+			pending = append(pending, id)
+		}
+	}
+
+	host, _ := os.Hostname()
+	var (
+		mu       sync.Mutex
+		folders  = map[string]int{}
+		exported int
+	)
+	err = fetchGmailConcurrently(ctx, len(pending), gmailQuotaMessageGet, func(ctx context.Context, idx int) error {
+		msg, getErr := svc.Users.Messages.Get("me", pending[idx]).Format(gmailFormatRaw).Context(ctx).Do()
+		if getErr != nil {
+			return fmt.Errorf("message %s: %w", pending[idx], getErr)
+		}
+		raw, decodeErr := decodeBase64URLBytes(msg.Raw)
+		if decodeErr != nil {
+			return fmt.Errorf("message %s: decode raw: %w", msg.Id, decodeErr)
+		}
+		placement := maildirPlacement(msg.LabelIds, labelNames, c.Layout)
+		data := maildirMessageBytes(raw, placement.keywords)
+		name := maildir.Filename(msg.InternalDate/1000, msg.Id, host, placement.flags)
+		for _, folder := range placement.folders {
+			dir := maildir.Folder(root, folder)
+			if ensureErr := maildir.Ensure(dir); ensureErr != nil {
+				return ensureErr
+			}
+			if writeErr := maildir.Deliver(dir, name, data); writeErr != nil {
+				return fmt.Errorf("message %s: %w", msg.Id, writeErr)
+			}
+			mu.Lock()
+			folders[folder]++
+			mu.Unlock()
+		}
+		mu.Lock()
+		exported++
+		mu.Unlock()
+		return nil
+	})
+	// On Ctrl-C the delivered files are the checkpoint: report them and let a
+	// re-run pick up the rest.
+	stopped := err != nil && interrupted(ctx)
+	if err != nil && !stopped {
+		return err
+	}
+
+	dest, err := out.Finish(ctx)
+	if err != nil {
+		return err
+	}
+
+	skipped := len(ids) - len(pending)
+	if outfmt.IsJSON(ctx) {
+		payload := map[string]any{
+			"dir":      dest,
+			"layout":   c.Layout,
+			"matched":  len(ids),
+			"exported": exported,
+			"skipped":  skipped,
+			"folders":  folders,
+		}
+		if stopped {
+			payload["interrupted"] = true
+		}
+		return outfmt.WriteJSON(os.Stdout, payload)
+	}
+	u.Out().Printf("dir\t%s", dest)
+	u.Out().Printf("exported\t%d", exported)
+	u.Out().Printf("skipped\t%d", skipped)
+	if c.Layout == maildirLayoutFolders && len(folders) > 0 {
+		names := make([]string, 0, len(folders))
+		for name := range folders {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			label := name
+			if label == "" {
+				label = "INBOX"
+			}
+			u.Out().Printf("folder\t%s\t%d", label, folders[name])
+		}
+	}
+	if stopped {
+		u.Err().Printf("Interrupted after %d of %d messages; re-run to export the rest", exported, len(pending))
+	} else if int64(len(ids)) >= c.Max {
+		u.Err().Printf("Stopped at --max %d; re-run with a higher --max to continue", c.Max)
+	}
+	return nil
+}
+
+// Folder names for system labels in folders layout; INBOX is the root maildir.
+var maildirSystemFolders = map[string]string{
+	"INBOX": "",
+	"SENT":  "Sent",
+	"DRAFT": "Drafts",
+	"SPAM":  "Spam",
+	"TRASH": "Trash",
+}
+
+// maildirPlacement maps Gmail labels onto maildir flags, X-Keywords, and
+// (in folders layout) the folders a copy is filed into.
+func maildirPlacement(labelIDs []string, labelNames map[string]string, layout string) maildirMessage {
+	var (
+		out                    maildirMessage
+		unread, starred, draft bool
+		trash                  bool
+	)
+	for _, id := range labelIDs {
+		switch {
+		case id == "UNREAD":
+			unread = true
+			continue
+		case id == "STARRED":
+			starred = true
+		case id == "DRAFT":
+			draft = true
+		case id == "TRASH":
+			trash = true
+		case id == "CHAT" || strings.HasPrefix(id, "CATEGORY_"):
+			continue
+		}
+
+		name := labelNames[id]
+		if name == "" {
+			name = id
+		}
+		if folder, system := maildirSystemFolders[id]; system {
+			out.keywords = append(out.keywords, strings.ToLower(id))
+			if layout == maildirLayoutFolders {
+				out.folders = append(out.folders, folder)
+			}
+			continue
+		}
+		if id == "STARRED" || id == "IMPORTANT" {
+			out.keywords = append(out.keywords, strings.ToLower(id))
+			continue
+		}
+		out.keywords = append(out.keywords, name)
+		if layout == maildirLayoutFolders {
+			out.folders = append(out.folders, name)
+		}
+	}
+
+	switch {
+	case layout != maildirLayoutFolders:
+		out.folders = []string{""}
+	case len(out.folders) == 0:
+		out.folders = []string{"Archive"}
+	}
+
+	if draft {
+		out.flags += "D"
+	}
+	if starred {
+		out.flags += "F"
+	}
+	if !unread {
+		out.flags += "S"
+	}
+	if trash {
+		out.flags += "T"
+	}
+	return out
+}
+
+// maildirMessageBytes converts to LF line endings, as Maildir tools expect,
+// and prepends an X-Keywords header listing the Gmail labels.
+func maildirMessageBytes(raw []byte, keywords []string) []byte {
+	raw = bytes.ReplaceAll(raw, []byte("\r\n"), []byte("\n"))
+	if len(keywords) == 0 {
+		return raw
+	}
+	clean := make([]string, 0, len(keywords))
+	for _, k := range keywords {
+		clean = append(clean, strings.ReplaceAll(k, ",", " "))
+	}
+	header := "X-Keywords: " + strings.Join(clean, ",") + "\n"
+	return append([]byte(header), raw...)
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMaildirPlacement(t *testing.T) {
+	names := map[string]string{"Label_1": "Work/Clients"}
+	labels := []string{"INBOX", "UNREAD", "STARRED", "CATEGORY_UPDATES", "Label_1"}
+
+	got := maildirPlacement(labels, names, maildirLayoutKeywords)
+	if got.flags != "F" {
+		t.Fatalf("flags=%q", got.flags)
+	}
+	if strings.Join(got.keywords, ",") != "inbox,starred,Work/Clients" {
+		t.Fatalf("keywords=%v", got.keywords)
+	}
+	if len(got.folders) != 1 || got.folders[0] != "" {
+		t.Fatalf("folders=%v", got.folders)
+	}
+
+	got = maildirPlacement(labels, names, maildirLayoutFolders)
+	if strings.Join(got.folders, "|") != "|Work/Clients" {
+		t.Fatalf("folders=%v", got.folders)
+	}
+
+	got = maildirPlacement([]string{"IMPORTANT"}, nil, maildirLayoutFolders)
+	if got.flags != "S" || len(got.folders) != 1 || got.folders[0] != "Archive" {
+		t.Fatalf("archived: %#v", got)
+	}
+}
+
+func TestMaildirMessageBytes(t *testing.T) {
+	got := string(maildirMessageBytes([]byte("Subject: hi\r\n\r\nbody\r\n"), []string{"inbox", "a,b"}))
+	want := "X-Keywords: inbox,a b\nSubject: hi\n\nbody\n"
+	if got != want {
+		t.Fatalf("got %q want %q", got, want)
+	}
+}
+
+func TestExecute_GmailExportMaildir_FoldersAndResume(t *testing.T) {
+	raw := base64.RawURLEncoding.EncodeToString([]byte("From: a@example.com\r\nSubject: Hello\r\n\r\nHi\r\n"))
+	gets := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/gmail/v1")
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && path == "/users/me/messages":
+			if q := r.URL.Query().Get("q"); q != "newer_than:1y" {
+				t.Fatalf("unexpected query %q", q)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"messages": []map[string]any{{"id": "m1"}, {"id": "m2"}},
+			})
+		case r.Method == http.MethodGet && strings.HasPrefix(path, "/users/me/messages/"):
+			gets++
+			id := strings.TrimPrefix(path, "/users/me/messages/")
+			if r.URL.Query().Get("format") != "raw" {
+				t.Fatalf("expected raw format, got %q", r.URL.RawQuery)
+			}
+			labels := []string{"INBOX", "Label_1"}
+			if id == "m2" {
+				labels = []string{"SENT", "UNREAD"}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id":           id,
+				"labelIds":     labels,
+				"internalDate": "1700000000000",
+				"raw":          raw,
+			})
+		case r.Method == http.MethodGet && path == "/users/me/labels":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"labels": []map[string]any{{"id": "Label_1", "name": "Work"}},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	stubGmailService(t, srv)
+
+	dir := t.TempDir()
+	run := func() map[string]any {
+		out := captureStdout(t, func() {
+			if err := Execute([]string{"--json", "--account", "a@b.com", "gmail", "export", "maildir", "--query", "newer_than:1y", "--dir", dir, "--layout", "folders"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+		var parsed map[string]any
+		if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+			t.Fatalf("json parse: %v\nout=%q", err, out)
+		}
+		return parsed
+	}
+
+	first := run()
+	if first["exported"] != float64(2) || first["skipped"] != float64(0) {
+		t.Fatalf("unexpected first run: %#v", first)
+	}
+	inbox, _ := filepath.Glob(filepath.Join(dir, "cur", "*"))
+	work, _ := filepath.Glob(filepath.Join(dir, ".Work", "cur", "*"))
+	sent, _ := filepath.Glob(filepath.Join(dir, ".Sent", "cur", "*"))
+	if len(inbox) != 1 || len(work) != 1 || len(sent) != 1 {
+		t.Fatalf("unexpected layout: inbox=%v work=%v sent=%v", inbox, work, sent)
+	}
+	if !strings.HasSuffix(inbox[0], ".m1."+maildirTestHost()+":2,S") {
+		t.Fatalf("unexpected filename %q", inbox[0])
+	}
+	if !strings.HasSuffix(sent[0], ":2,") {
+		t.Fatalf("unread message should have no flags: %q", sent[0])
+	}
+	data, err := os.ReadFile(inbox[0])
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "X-Keywords: inbox,Work\nFrom: a@example.com\n") || strings.Contains(string(data), "\r") {
+		t.Fatalf("unexpected message: %q", data)
+	}
+
+	second := run()
+	if second["exported"] != float64(0) || second["skipped"] != float64(2) || gets != 2 {
+		t.Fatalf("expected resume to skip: %#v gets=%d", second, gets)
+	}
+}
+
+func maildirTestHost() string {
+	host, _ := os.Hostname()
+	if host == "" {
+		return "localhost"
+	}
+	return strings.NewReplacer("/", `\057`, ":", `\072`).Replace(host)
+}
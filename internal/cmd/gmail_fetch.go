@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	gapi "google.golang.org/api/googleapi"
+
+	"github.com/steipete/gogcli/internal/googleapi"
+)
+
+// Gmail enforces a per-user quota of 250 units per second. messages.get costs
+// 5 units and threads.get 10, so a naive fan-out trips rate limiting quickly.
+// See https://developers.google.com/gmail/api/reference/quota.
+const (
+	gmailUserQuotaPerSecond = 250
+	gmailQuotaMessageGet    = 5
+	gmailQuotaThreadGet     = 10
+
+	// Concurrent calls are coalesced into Gmail batch requests of up to
+	// googleapi.GmailBatchMaxCalls, so the pool may hold a full batch in flight.
+	gmailFetchStartWorkers = 50
+	gmailFetchMaxWorkers   = googleapi.GmailBatchMaxCalls
+	gmailFetchMaxRetries   = 4
+)
+
+// gmailQuota is shared by every fetch in the process. gog acts for a single
+// account per invocation, so this tracks that user's quota.
+var gmailQuota = newQuotaBucket(gmailUserQuotaPerSecond)
+
+var gmailFetchBackoff = func(attempt int) time.Duration {
+	return time.Duration(1<<attempt) * 500 * time.Millisecond
+}
+
+// quotaBucket is a token bucket refilled at rate units per second, holding at
+// most one second worth of units.
+type quotaBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+func newQuotaBucket(perSecond int) *quotaBucket {
+	return &quotaBucket{
+		rate:   float64(perSecond),
+		tokens: float64(perSecond),
+		now:    time.Now,
+	}
+}
+
+// take blocks until cost units are available or ctx is done.
+func (b *quotaBucket) take(ctx context.Context, cost int) error {
+	for {
+		b.mu.Lock()
+		now := b.now()
+		if !b.last.IsZero() {
+			b.tokens = min(b.rate, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		}
+		b.last = now
+		need := float64(cost)
+		if b.tokens >= need {
+			b.tokens -= need
+			b.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((need - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		if err := sendPacerSleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// adaptiveLimiter bounds in-flight requests. The limit grows by one after a
+// full window of successes and halves on rate limiting (AIMD).
+type adaptiveLimiter struct {
+	mu        sync.Mutex
+	cond      *sync.Cond
+	limit     int
+	max       int
+	inflight  int
+	successes int
+}
+
+func newAdaptiveLimiter(start, maxLimit int) *adaptiveLimiter {
+	l := &adaptiveLimiter{limit: max(1, min(start, maxLimit)), max: maxLimit}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+func (l *adaptiveLimiter) acquire(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for l.inflight >= l.limit {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		l.cond.Wait()
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	l.inflight++
+	return nil
+}
+
+func (l *adaptiveLimiter) release(rateLimited bool) {
+	l.mu.Lock()
+	l.inflight--
+	if rateLimited {
+		l.limit = max(1, l.limit/2)
+		l.successes = 0
+	} else {
+		l.successes++
+		if l.successes >= l.limit && l.limit < l.max {
+			l.limit++
+			l.successes = 0
+		}
+	}
+	l.mu.Unlock()
+	l.cond.Broadcast()
+}
+
+func (l *adaptiveLimiter) current() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// fetchGmailConcurrently runs fetch for every index in [0, n) on a bounded,
+// quota-aware worker pool. Gmail GET calls made with the passed context are
+// sent as multipart batch requests (see googleapi.WithGmailBatch), so a pool
+// of concurrent fetches costs one round trip per batch instead of per message.
+// Rate-limited calls are retried with backoff; other errors are not retried.
+// All calls run to completion and the error for the lowest failing index is
+// returned so output stays deterministic.
+func fetchGmailConcurrently(ctx context.Context, n int, cost int, fetch func(ctx context.Context, i int) error) error {
+	if n <= 0 {
+		return nil
+	}
+	ctx = googleapi.WithGmailBatch(ctx, n)
+
+	limiter := newAdaptiveLimiter(gmailFetchStartWorkers, gmailFetchMaxWorkers)
+	stop := context.AfterFunc(ctx, func() {
+		limiter.mu.Lock()
+		limiter.cond.Broadcast()
+		limiter.mu.Unlock()
+	})
+	defer stop()
+
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			for attempt := 0; ; attempt++ {
+				if err := limiter.acquire(ctx); err != nil {
+					errs[idx] = err
+					return
+				}
+				if err := gmailQuota.take(ctx, cost); err != nil {
+					limiter.release(false)
+					errs[idx] = err
+					return
+				}
+				err := fetch(ctx, idx)
+				limited := isGmailRateLimitError(err)
+				limiter.release(limited)
+				if !limited || attempt >= gmailFetchMaxRetries {
+					errs[idx] = err
+					return
+				}
+				if sleepErr := sendPacerSleep(ctx, gmailFetchBackoff(attempt)); sleepErr != nil {
+					errs[idx] = sleepErr
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isGmailRateLimitError(err error) bool {
+	var gerr *gapi.Error
+	if !errors.As(err, &gerr) {
+		return false
+	}
+	if gerr.Code == http.StatusTooManyRequests {
+		return true
+	}
+	if gerr.Code != http.StatusForbidden {
+		return false
+	}
+	for _, item := range gerr.Errors {
+		switch item.Reason {
+		case "rateLimitExceeded", "userRateLimitExceeded":
+			return true
+		}
+	}
+	return false
+}
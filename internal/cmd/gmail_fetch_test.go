@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+func stubFetchSleep(t *testing.T) *[]time.Duration {
+	t.Helper()
+	orig := sendPacerSleep
+	t.Cleanup(func() { sendPacerSleep = orig })
+	var slept []time.Duration
+	sendPacerSleep = func(_ context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return nil
+	}
+	return &slept
+}
+
+func TestQuotaBucket_WaitsForRefill(t *testing.T) {
+	slept := stubFetchSleep(t)
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := newQuotaBucket(10)
+	b.now = func() time.Time { return now }
+	sendPacerSleep = func(_ context.Context, d time.Duration) error {
+		*slept = append(*slept, d)
+		now = now.Add(d)
+		return nil
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := b.take(context.Background(), 5); err != nil {
+			t.Fatalf("take: %v", err)
+		}
+	}
+	if len(*slept) != 0 {
+		t.Fatalf("expected burst without waiting, slept %v", *slept)
+	}
+	if err := b.take(context.Background(), 5); err != nil {
+		t.Fatalf("take: %v", err)
+	}
+	if len(*slept) != 1 || (*slept)[0] != 500*time.Millisecond {
+		t.Fatalf("expected one 500ms wait, got %v", *slept)
+	}
+}
+
+func TestAdaptiveLimiter_AIMD(t *testing.T) {
+	l := newAdaptiveLimiter(4, 5)
+	for i := 0; i < 4; i++ {
+		if err := l.acquire(context.Background()); err != nil {
+			t.Fatalf("acquire: %v", err)
+		}
+		l.release(false)
+	}
+	if got := l.current(); got != 5 {
+		t.Fatalf("expected additive increase to 5, got %d", got)
+	}
+	for i := 0; i < 10; i++ {
+		_ = l.acquire(context.Background())
+		l.release(false)
+	}
+	if got := l.current(); got != 5 {
+		t.Fatalf("expected cap at 5, got %d", got)
+	}
+	_ = l.acquire(context.Background())
+	l.release(true)
+	if got := l.current(); got != 2 {
+		t.Fatalf("expected halving to 2, got %d", got)
+	}
+}
+
+func TestFetchGmailConcurrently_RetriesRateLimits(t *testing.T) {
+	slept := stubFetchSleep(t)
+
+	var calls atomic.Int32
+	err := fetchGmailConcurrently(context.Background(), 3, 1, func(_ context.Context, i int) error {
+		n := calls.Add(1)
+		if i == 1 && n <= 3 {
+			return &googleapi.Error{Code: http.StatusTooManyRequests}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls.Load() < 4 {
+		t.Fatalf("expected a retry, got %d calls", calls.Load())
+	}
+	if len(*slept) == 0 {
+		t.Fatalf("expected backoff sleep")
+	}
+}
+
+func TestFetchGmailConcurrently_ReturnsLowestIndexError(t *testing.T) {
+	stubFetchSleep(t)
+
+	errA := errors.New("a")
+	errB := errors.New("b")
+	var calls atomic.Int32
+	err := fetchGmailConcurrently(context.Background(), 4, 1, func(_ context.Context, i int) error {
+		calls.Add(1)
+		switch i {
+		case 1:
+			return errA
+		case 3:
+			return errB
+		}
+		return nil
+	})
+	if !errors.Is(err, errA) {
+		t.Fatalf("expected errA, got %v", err)
+	}
+	if calls.Load() != 4 {
+		t.Fatalf("expected non-rate-limit errors not to be retried, got %d calls", calls.Load())
+	}
+}
+
+func TestIsGmailRateLimitError(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{&googleapi.Error{Code: http.StatusTooManyRequests}, true},
+		{&googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "userRateLimitExceeded"}}}, true},
+		{&googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "insufficientPermissions"}}}, false},
+		{&googleapi.Error{Code: http.StatusInternalServerError}, false},
+		{errors.New("boom"), false},
+		{nil, false},
+	}
+	for _, tc := range cases {
+		if got := isGmailRateLimitError(tc.err); got != tc.want {
+			t.Fatalf("isGmailRateLimitError(%v)=%v want %v", tc.err, got, tc.want)
+		}
+	}
+}
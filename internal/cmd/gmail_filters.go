@@ -21,7 +21,9 @@ type GmailFiltersCmd struct {
 	Delete GmailFiltersDeleteCmd `cmd:"" name:"delete" help:"Delete a filter"`
 }
 
-type GmailFiltersListCmd struct{}
+type GmailFiltersListCmd struct {
+	SettingsDiffFlags `embed:""`
+}
 
 func (c *GmailFiltersListCmd) Run(ctx context.Context, flags *RootFlags) error {
 	u := ui.FromContext(ctx)
@@ -35,6 +37,10 @@ func (c *GmailFiltersListCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
+	if c.active() {
+		return c.run(ctx, flags, "filters", "filters", &filtersState{svc: svc})
+	}
+
 	resp, err := svc.Users.Settings.Filters.List("me").Do()
 	if err != nil {
 		return err
@@ -155,8 +161,8 @@ type GmailFiltersCreateCmd struct {
 	Subject       string `name:"subject" help:"Match messages with this subject"`
 	Query         string `name:"query" help:"Advanced Gmail search query for matching"`
 	HasAttachment bool   `name:"has-attachment" help:"Match messages with attachments"`
-	AddLabel      string `name:"add-label" help:"Label(s) to add to matching messages (comma-separated, name or ID)"`
-	RemoveLabel   string `name:"remove-label" help:"Label(s) to remove from matching messages (comma-separated, name or ID)"`
+	AddLabel      string `name:"add-label" complete:"labels" help:"Label(s) to add to matching messages (comma-separated, name or ID)"`
+	RemoveLabel   string `name:"remove-label" complete:"labels" help:"Label(s) to remove from matching messages (comma-separated, name or ID)"`
 	Archive       bool   `name:"archive" help:"Archive matching messages (skip inbox)"`
 	MarkRead      bool   `name:"mark-read" help:"Mark matching messages as read"`
 	Star          bool   `name:"star" help:"Star matching messages"`
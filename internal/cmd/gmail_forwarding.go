@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"google.golang.org/api/gmail/v1"
 
@@ -16,7 +17,9 @@ import (
 type GmailForwardingCmd struct {
 	List   GmailForwardingListCmd   `cmd:"" name:"list" help:"List all forwarding addresses"`
 	Get    GmailForwardingGetCmd    `cmd:"" name:"get" help:"Get a specific forwarding address"`
-	Create GmailForwardingCreateCmd `cmd:"" name:"create" help:"Create/add a forwarding address"`
+	Create GmailForwardingCreateCmd `cmd:"" name:"create" aliases:"add" help:"Create/add a forwarding address"`
+	Verify GmailForwardingVerifyCmd `cmd:"" name:"verify" help:"Check (or wait for) the verification handshake of a forwarding address"`
+	Set    GmailForwardingSetCmd    `cmd:"" name:"set" help:"Enable auto-forwarding to a verified address"`
 	Delete GmailForwardingDeleteCmd `cmd:"" name:"delete" help:"Delete a forwarding address"`
 }
 
@@ -56,6 +59,12 @@ func (c *GmailForwardingListCmd) Run(ctx context.Context, flags *RootFlags) erro
 			f.VerificationStatus)
 	}
 	_ = tw.Flush()
+	for _, f := range resp.ForwardingAddresses {
+		if f.VerificationStatus == forwardingStatusPending {
+			u.Err().Println("Pending addresses forward nothing until the recipient clicks the verification link; check with: gog gmail forwarding verify <email>")
+			break
+		}
+	}
 	return nil
 }
 
@@ -169,3 +178,129 @@ func (c *GmailForwardingDeleteCmd) Run(ctx context.Context, flags *RootFlags) er
 	u.Out().Printf("Forwarding address %s deleted successfully", forwardingEmail)
 	return nil
 }
+
+const (
+	forwardingStatusAccepted = "accepted"
+	forwardingStatusPending  = "pending"
+)
+
+var forwardingPollInterval = 10 * time.Second
+
+type GmailForwardingVerifyCmd struct {
+	ForwardingEmail string        `arg:"" name:"forwardingEmail" help:"Forwarding email"`
+	Resend          bool          `name:"resend" help:"Re-send the verification email if the address is still pending"`
+	Wait            time.Duration `name:"wait" help:"Poll until the address is accepted or this long has passed (e.g. 10m)"`
+}
+
+func (c *GmailForwardingVerifyCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+
+	forwardingEmail := strings.TrimSpace(c.ForwardingEmail)
+	if forwardingEmail == "" {
+		return usage("empty forwardingEmail")
+	}
+	if c.Wait < 0 {
+		return usage("--wait must be >= 0")
+	}
+
+	svc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	address, err := svc.Users.Settings.ForwardingAddresses.Get("me", forwardingEmail).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	resent := false
+	if c.Resend && address.VerificationStatus == forwardingStatusPending {
+		// The API has no resend call; re-creating the address triggers a new verification email.
+		if err := confirmDestructive(ctx, flags, fmt.Sprintf("delete and re-create forwarding address %s to resend its verification email", forwardingEmail)); err != nil {
+			return err
+		}
+		if err := svc.Users.Settings.ForwardingAddresses.Delete("me", forwardingEmail).Context(ctx).Do(); err != nil {
+			return fmt.Errorf("resend verification: %w", err)
+		}
+		address, err = svc.Users.Settings.ForwardingAddresses.Create("me", &gmail.ForwardingAddress{ForwardingEmail: forwardingEmail}).Context(ctx).Do()
+		if err != nil {
+			return fmt.Errorf("resend verification: %w", err)
+		}
+		resent = true
+		u.Err().Printf("Verification email re-sent to %s", forwardingEmail)
+	}
+
+	if c.Wait > 0 && address.VerificationStatus != forwardingStatusAccepted {
+		deadline := time.Now().Add(c.Wait)
+		u.Err().Printf("Waiting up to %s for %s to confirm…", c.Wait, forwardingEmail)
+		for address.VerificationStatus != forwardingStatusAccepted {
+			if !time.Now().Before(deadline) {
+				return fmt.Errorf("forwarding address %s still %s after %s", forwardingEmail, address.VerificationStatus, c.Wait)
+			}
+			if err := sendPacerSleep(ctx, min(forwardingPollInterval, time.Until(deadline))); err != nil {
+				return err
+			}
+			address, err = svc.Users.Settings.ForwardingAddresses.Get("me", forwardingEmail).Context(ctx).Do()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"forwardingAddress": address,
+			"verified":          address.VerificationStatus == forwardingStatusAccepted,
+			"resent":            resent,
+		})
+	}
+
+	u.Out().Printf("forwarding_email\t%s", address.ForwardingEmail)
+	u.Out().Printf("verification_status\t%s", address.VerificationStatus)
+	if address.VerificationStatus == forwardingStatusPending {
+		u.Err().Println("The recipient must click the link in the verification email; use --resend to send it again or --wait to poll.")
+	}
+	return nil
+}
+
+type GmailForwardingSetCmd struct {
+	ForwardingEmail string `arg:"" name:"forwardingEmail" help:"Verified forwarding email"`
+	Disposition     string `name:"disposition" help:"What to do with forwarded messages: leaveInInbox|archive|trash|markRead (default: keep the current setting)" enum:",leaveInInbox,archive,trash,markRead" default:""`
+}
+
+func (c *GmailForwardingSetCmd) Run(ctx context.Context, flags *RootFlags) error {
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+
+	forwardingEmail := strings.TrimSpace(c.ForwardingEmail)
+	if forwardingEmail == "" {
+		return usage("empty forwardingEmail")
+	}
+	svc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	address, err := svc.Users.Settings.ForwardingAddresses.Get("me", forwardingEmail).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+	if address.VerificationStatus != forwardingStatusAccepted {
+		return usagef("forwarding address %s is %s; it must be verified first (gog gmail forwarding verify %s)", forwardingEmail, address.VerificationStatus, forwardingEmail)
+	}
+
+	return updateAutoForwarding(ctx, svc, func(autoForward *gmail.AutoForwarding) error {
+		autoForward.Enabled = true
+		autoForward.EmailAddress = forwardingEmail
+		if c.Disposition != "" {
+			autoForward.Disposition = c.Disposition
+		}
+		return nil
+	})
+}
@@ -1,6 +1,14 @@
 package cmd
 
-import "testing"
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestForwardingCommandsExist(t *testing.T) {
 	// Unit tests for the actual API calls live in integration; here we just ensure
@@ -9,5 +17,146 @@ func TestForwardingCommandsExist(t *testing.T) {
 	_ = GmailForwardingListCmd{}
 	_ = GmailForwardingGetCmd{}
 	_ = GmailForwardingCreateCmd{}
+	_ = GmailForwardingVerifyCmd{}
+	_ = GmailForwardingSetCmd{}
 	_ = GmailForwardingDeleteCmd{}
 }
+
+func TestGmailForwardingVerify_ResendAndWait(t *testing.T) {
+	origInterval := forwardingPollInterval
+	origSleep := sendPacerSleep
+	t.Cleanup(func() {
+		forwardingPollInterval = origInterval
+		sendPacerSleep = origSleep
+	})
+	forwardingPollInterval = time.Millisecond
+	sendPacerSleep = func(_ context.Context, _ time.Duration) error { return nil }
+
+	gets := 0
+	var deleted, created bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/gmail/v1")
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && path == "/users/me/settings/forwardingAddresses/f@b.com":
+			gets++
+			status := "pending"
+			if gets >= 3 {
+				status = "accepted"
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"forwardingEmail": "f@b.com", "verificationStatus": status})
+		case r.Method == http.MethodDelete && path == "/users/me/settings/forwardingAddresses/f@b.com":
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodPost && path == "/users/me/settings/forwardingAddresses":
+			created = true
+			_ = json.NewEncoder(w).Encode(map[string]any{"forwardingEmail": "f@b.com", "verificationStatus": "pending"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	stubGmailService(t, srv)
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "a@b.com", "gmail", "forwarding", "verify", "f@b.com", "--resend", "--wait", "1m", "--force"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if !deleted || !created {
+		t.Fatalf("expected resend via delete+create, deleted=%v created=%v", deleted, created)
+	}
+	var parsed struct {
+		Verified bool `json:"verified"`
+		Resent   bool `json:"resent"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json parse: %v\nout=%q", err, out)
+	}
+	if !parsed.Verified || !parsed.Resent {
+		t.Fatalf("unexpected result: %#v", parsed)
+	}
+}
+
+func TestGmailForwardingSet(t *testing.T) {
+	status := "pending"
+	var update map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/gmail/v1")
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && path == "/users/me/settings/forwardingAddresses/f@b.com":
+			_ = json.NewEncoder(w).Encode(map[string]any{"forwardingEmail": "f@b.com", "verificationStatus": status})
+		case r.Method == http.MethodGet && path == "/users/me/settings/autoForwarding":
+			_ = json.NewEncoder(w).Encode(map[string]any{"enabled": false, "disposition": "markRead"})
+		case r.Method == http.MethodPut && path == "/users/me/settings/autoForwarding":
+			_ = json.NewDecoder(r.Body).Decode(&update)
+			_ = json.NewEncoder(w).Encode(update)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	stubGmailService(t, srv)
+
+	_ = captureStderr(t, func() {
+		err := Execute([]string{"--account", "a@b.com", "gmail", "forwarding", "set", "f@b.com"})
+		if err == nil || ExitCode(err) != 2 {
+			t.Fatalf("expected usage error for unverified address, got %v", err)
+		}
+	})
+
+	status = "accepted"
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--account", "a@b.com", "gmail", "forwarding", "set", "f@b.com", "--disposition", "archive"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	if update["enabled"] != true || update["emailAddress"] != "f@b.com" || update["disposition"] != "archive" {
+		t.Fatalf("unexpected update: %#v", update)
+	}
+	if !strings.Contains(out, "disposition\tarchive") {
+		t.Fatalf("unexpected output: %q", out)
+	}
+
+	// Without --disposition the current one is kept, as with autoforward update.
+	_ = captureStdout(t, func() {
+		if err := Execute([]string{"--account", "a@b.com", "gmail", "forwarding", "set", "f@b.com"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	if update["disposition"] != "markRead" {
+		t.Fatalf("expected current disposition kept, got %#v", update)
+	}
+}
+
+func TestGmailForwardingVerify_ResendNeedsForce(t *testing.T) {
+	deleted := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/gmail/v1")
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && path == "/users/me/settings/forwardingAddresses/f@b.com":
+			_ = json.NewEncoder(w).Encode(map[string]any{"forwardingEmail": "f@b.com", "verificationStatus": "pending"})
+		case r.Method == http.MethodDelete:
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	stubGmailService(t, srv)
+
+	_ = captureStderr(t, func() {
+		err := Execute([]string{"--no-input", "--account", "a@b.com", "gmail", "forwarding", "verify", "f@b.com", "--resend"})
+		if err == nil || ExitCode(err) != 2 {
+			t.Fatalf("expected usage error without --force, got %v", err)
+		}
+	})
+	if deleted {
+		t.Fatalf("--resend deleted the address without confirmation")
+	}
+}
@@ -7,14 +7,22 @@ import (
 	"os"
 	"strings"
 
+	"google.golang.org/api/gmail/v1"
+
 	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/translate"
 	"github.com/steipete/gogcli/internal/ui"
 )
 
 type GmailGetCmd struct {
-	MessageID string `arg:"" name:"messageId" help:"Message ID"`
-	Format    string `name:"format" help:"Message format: full|metadata|raw" default:"full"`
-	Headers   string `name:"headers" help:"Metadata headers (comma-separated; only for --format=metadata)"`
+	MessageID        string   `arg:"" name:"messageId" help:"Message ID"`
+	Format           string   `name:"format" help:"Message format: full|metadata|raw" default:"full"`
+	Headers          string   `name:"headers" help:"Metadata headers (comma-separated; only for --format=metadata)"`
+	Header           []string `name:"header" help:"Print only this header, every occurrence in order (repeatable; e.g. --header Received)"`
+	HeadersOnly      bool     `name:"headers-only" help:"Print all headers and nothing else"`
+	HeadersJSON      bool     `name:"headers-json" help:"Dump the header array (name/value pairs, in order) as JSON"`
+	Translate        string   `name:"translate" help:"Also show the body translated into this language (e.g. en)"`
+	TranslateCommand string   `name:"translate-command" help:"Shell command that translates: text on stdin, target language in $GOG_TRANSLATE_TARGET (default: Google Cloud Translation)"`
 }
 
 const (
@@ -44,16 +52,25 @@ func (c *GmailGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return fmt.Errorf("invalid --format: %q (expected full|metadata|raw)", format)
 	}
 
+	target := strings.TrimSpace(c.Translate)
+	if target != "" && format != gmailFormatFull {
+		return usage("--translate needs --format full")
+	}
+
 	svc, err := newGmailService(ctx, account)
 	if err != nil {
 		return err
 	}
 
+	if len(c.Header) > 0 || c.HeadersOnly || c.HeadersJSON {
+		return c.runHeaders(ctx, u, svc, messageID)
+	}
+
 	call := svc.Users.Messages.Get("me", messageID).Format(format).Context(ctx)
 	if format == gmailFormatMetadata {
 		headerList := splitCSV(c.Headers)
 		if len(headerList) == 0 {
-			headerList = []string{"From", "To", "Subject", "Date"}
+			headerList = []string{"From", "To", "Subject", "Date", "Message-ID"}
 		}
 		if !hasHeaderName(headerList, "List-Unsubscribe") {
 			headerList = append(headerList, "List-Unsubscribe")
@@ -66,7 +83,21 @@ func (c *GmailGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
+	var translation *translate.Result
+	if target != "" {
+		if body := bestBodyText(msg.Payload); strings.TrimSpace(body) == "" {
+			u.Err().Println("No text body to translate")
+		} else {
+			res, err := translateMessageBody(ctx, c.TranslateCommand, target, body)
+			if err != nil {
+				return err
+			}
+			translation = &res
+		}
+	}
+
 	unsubscribe := bestUnsubscribeLink(msg.Payload)
+	rfc822ID := headerValue(msg.Payload, "Message-ID")
 	if outfmt.IsJSON(ctx) {
 		// Include a flattened headers map for easier querying
 		// (e.g., jq '.headers.to' instead of complex nested queries)
@@ -82,6 +113,9 @@ func (c *GmailGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 			"message": msg,
 			"headers": headers,
 		}
+		if rfc822ID != "" {
+			payload["rfc822MessageId"] = rfc822ID
+		}
 		if unsubscribe != "" {
 			payload["unsubscribe"] = unsubscribe
 		}
@@ -89,6 +123,9 @@ func (c *GmailGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 			if body := bestBodyText(msg.Payload); body != "" {
 				payload["body"] = body
 			}
+			if translation != nil {
+				payload["translation"] = translation
+			}
 		}
 		if format == gmailFormatFull || format == gmailFormatMetadata {
 			attachments := collectAttachments(msg.Payload)
@@ -121,6 +158,9 @@ func (c *GmailGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 		u.Out().Printf("to\t%s", headerValue(msg.Payload, "To"))
 		u.Out().Printf("subject\t%s", headerValue(msg.Payload, "Subject"))
 		u.Out().Printf("date\t%s", headerValue(msg.Payload, "Date"))
+		if rfc822ID != "" {
+			u.Out().Printf("message_id\t%s", rfc822ID)
+		}
 		if unsubscribe != "" {
 			u.Out().Printf("unsubscribe\t%s", unsubscribe)
 		}
@@ -135,9 +175,65 @@ func (c *GmailGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 				u.Out().Println("")
 				u.Out().Println(body)
 			}
+			if translation != nil {
+				u.Out().Println("")
+				u.Out().Println(translationHeading(translation))
+				u.Out().Println(translation.Text)
+			}
 		}
 		return nil
 	default:
 		return nil
 	}
 }
+
+// runHeaders prints raw headers (all, or only the --header names) without
+// fetching the body. Repeated headers such as Received keep their order.
+func (c *GmailGetCmd) runHeaders(ctx context.Context, u *ui.UI, svc *gmail.Service, messageID string) error {
+	var names []string
+	for _, h := range c.Header {
+		if h = strings.TrimSpace(h); h != "" {
+			names = append(names, h)
+		}
+	}
+
+	call := svc.Users.Messages.Get("me", messageID).Format(gmailFormatMetadata).Context(ctx)
+	if len(names) > 0 {
+		call = call.MetadataHeaders(names...)
+	}
+	msg, err := call.Do()
+	if err != nil {
+		return err
+	}
+
+	headers := selectHeaders(msg.Payload, names)
+	if c.HeadersJSON {
+		return outfmt.WriteJSON(os.Stdout, headers)
+	}
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"id":       msg.Id,
+			"threadId": msg.ThreadId,
+			"headers":  headers,
+		})
+	}
+	for _, h := range headers {
+		u.Out().Printf("%s: %s", h.Name, h.Value)
+	}
+	return nil
+}
+
+// selectHeaders returns the top-level headers matching names (all when names
+// is empty), preserving message order.
+func selectHeaders(p *gmail.MessagePart, names []string) []*gmail.MessagePartHeader {
+	out := []*gmail.MessagePartHeader{}
+	if p == nil {
+		return out
+	}
+	for _, h := range p.Headers {
+		if len(names) == 0 || hasHeaderName(names, h.Name) {
+			out = append(out, h)
+		}
+	}
+	return out
+}
@@ -474,3 +474,69 @@ func TestGmailGetCmd_RawEmpty(t *testing.T) {
 		t.Fatalf("unexpected stderr: %q", errOut)
 	}
 }
+
+func TestGmailGetCmd_HeaderExtraction(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+
+	var gotFormat string
+	var gotHeaders []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotFormat = r.URL.Query().Get("format")
+		gotHeaders = r.URL.Query()["metadataHeaders"]
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id":       "m1",
+			"threadId": "t1",
+			"payload": map[string]any{
+				"headers": []map[string]any{
+					{"name": "Received", "value": "from mx2 by mx3"},
+					{"name": "X-Mailer", "value": "Mutt"},
+					{"name": "Received", "value": "from mx1 by mx2"},
+					{"name": "Subject", "value": "S"},
+				},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	run := func(args ...string) string {
+		t.Helper()
+		return captureStdout(t, func() {
+			if err := Execute(append([]string{"--account", "a@b.com"}, args...)); err != nil {
+				t.Fatalf("Execute %v: %v", args, err)
+			}
+		})
+	}
+
+	out := run("gmail", "messages", "get", "m1", "--header", "received", "--header", "X-Mailer")
+	if gotFormat != "metadata" || len(gotHeaders) != 2 {
+		t.Fatalf("format=%q metadataHeaders=%v", gotFormat, gotHeaders)
+	}
+	want := "Received: from mx2 by mx3\nX-Mailer: Mutt\nReceived: from mx1 by mx2\n"
+	if out != want {
+		t.Fatalf("unexpected output: %q", out)
+	}
+
+	out = run("gmail", "get", "m1", "--headers-json")
+	if len(gotHeaders) != 0 {
+		t.Fatalf("expected all headers requested, got %v", gotHeaders)
+	}
+	var headers []gmail.MessagePartHeader
+	if err := json.Unmarshal([]byte(out), &headers); err != nil {
+		t.Fatalf("json parse: %v\n%s", err, out)
+	}
+	if len(headers) != 4 || headers[2].Value != "from mx1 by mx2" || headers[3].Name != "Subject" {
+		t.Fatalf("unexpected headers: %#v", headers)
+	}
+}
@@ -20,7 +20,7 @@ type GmailLabelsCmd struct {
 }
 
 type GmailLabelsGetCmd struct {
-	Label string `arg:"" name:"labelIdOrName" help:"Label ID or name"`
+	Label string `arg:"" name:"labelIdOrName" complete:"labels" help:"Label ID or name"`
 }
 
 func (c *GmailLabelsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -129,6 +129,12 @@ func (c *GmailLabelsListCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if err != nil {
 		return err
 	}
+	names := make([]string, 0, len(resp.Labels))
+	for _, l := range resp.Labels {
+		names = append(names, l.Name)
+	}
+	rememberCompletionValues(account, completeLabels, names)
+
 	if outfmt.IsJSON(ctx) {
 		return outfmt.WriteJSON(os.Stdout, map[string]any{"labels": resp.Labels})
 	}
@@ -148,8 +154,8 @@ func (c *GmailLabelsListCmd) Run(ctx context.Context, flags *RootFlags) error {
 
 type GmailLabelsModifyCmd struct {
 	ThreadIDs []string `arg:"" name:"threadId" help:"Thread IDs"`
-	Add       string   `name:"add" help:"Labels to add (comma-separated, name or ID)"`
-	Remove    string   `name:"remove" help:"Labels to remove (comma-separated, name or ID)"`
+	Add       string   `name:"add" complete:"labels" help:"Labels to add (comma-separated, name or ID)"`
+	Remove    string   `name:"remove" complete:"labels" help:"Labels to remove (comma-separated, name or ID)"`
 }
 
 func (c *GmailLabelsModifyCmd) Run(ctx context.Context, flags *RootFlags) error {
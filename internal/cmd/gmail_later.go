@@ -0,0 +1,479 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/events"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// laterDueHour is when date-only --by values (friday, 2026-11-02) fall due:
+// the end of a working day.
+const laterDueHour = 17
+
+type GmailLaterCmd struct {
+	Add     GmailLaterAddCmd     `cmd:"" name:"add" help:"Promise a reply to messages by a deadline"`
+	List    GmailLaterListCmd    `cmd:"" name:"list" aliases:"ls" help:"List the reply-later queue"`
+	Done    GmailLaterDoneCmd    `cmd:"" name:"done" aliases:"remove,rm" help:"Drop messages from the queue"`
+	Process GmailLaterProcessCmd `cmd:"" name:"process" help:"Drop answered items and draft replies for overdue ones (run from cron)"`
+}
+
+type laterEntry struct {
+	Account   string `json:"account"`
+	MessageID string `json:"messageId"`
+	ThreadID  string `json:"threadId,omitempty"`
+	From      string `json:"from,omitempty"`
+	Subject   string `json:"subject,omitempty"`
+	By        string `json:"by"`
+	CreatedAt string `json:"createdAt"`
+	AutoDraft bool   `json:"autoDraft,omitempty"`
+	DraftID   string `json:"draftId,omitempty"`
+}
+
+func (e laterEntry) by() time.Time {
+	t, _ := time.Parse(time.RFC3339, e.By)
+	return t
+}
+
+func (e laterEntry) createdAt() time.Time {
+	t, _ := time.Parse(time.RFC3339, e.CreatedAt)
+	return t
+}
+
+func (e laterEntry) overdue(now time.Time) bool {
+	return !e.by().After(now)
+}
+
+// laterStore is the local reply-SLA queue across accounts. It lives in the
+// local state dir next to the snooze store.
+type laterStore struct {
+	path    string
+	Entries []laterEntry `json:"entries"`
+}
+
+func loadLaterStore() (*laterStore, error) {
+	path, err := config.GmailLaterPath()
+	if err != nil {
+		return nil, err
+	}
+	store := &laterStore{path: path}
+	data, err := os.ReadFile(path) //nolint:gosec // path under config dir
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return store, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("parse reply-later store: %w", err)
+	}
+	return store, nil
+}
+
+func (s *laterStore) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("ensure state dir: %w", err)
+	}
+	sort.SliceStable(s.Entries, func(i, j int) bool {
+		return s.Entries[i].By < s.Entries[j].By
+	})
+	payload, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, append(payload, '\n'), 0o600)
+}
+
+func (s *laterStore) put(entry laterEntry) {
+	s.remove(entry.Account, entry.MessageID)
+	s.Entries = append(s.Entries, entry)
+}
+
+func (s *laterStore) remove(account, messageID string) bool {
+	kept := s.Entries[:0]
+	removed := false
+	for _, e := range s.Entries {
+		if strings.EqualFold(e.Account, account) && e.MessageID == messageID {
+			removed = true
+			continue
+		}
+		kept = append(kept, e)
+	}
+	s.Entries = kept
+	return removed
+}
+
+func (s *laterStore) forAccount(account string) []laterEntry {
+	var out []laterEntry
+	for _, e := range s.Entries {
+		if strings.EqualFold(e.Account, account) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// laterCounts returns how many of the account's promised replies are overdue
+// and how many fall due within the next day.
+func laterCounts(account string, now time.Time) (overdue, dueSoon int) {
+	store, err := loadLaterStore()
+	if err != nil {
+		return 0, 0
+	}
+	for _, e := range store.forAccount(account) {
+		switch {
+		case e.overdue(now):
+			overdue++
+		case e.by().Before(now.Add(24 * time.Hour)):
+			dueSoon++
+		}
+	}
+	return overdue, dueSoon
+}
+
+type GmailLaterAddCmd struct {
+	MessageIDs []string `arg:"" name:"messageId" help:"Message IDs"`
+	By         string   `name:"by" help:"Reply deadline: duration (4h, 2d, 1w), RFC3339, YYYY-MM-DD[ HH:MM], today, tomorrow, friday (required)"`
+	AutoDraft  bool     `name:"auto-draft" help:"Create a reply draft when the item becomes overdue (on gmail later process)"`
+}
+
+func (c *GmailLaterAddCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	ids := make([]string, 0, len(c.MessageIDs))
+	for _, id := range c.MessageIDs {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return usage("missing messageId")
+	}
+	now := time.Now()
+	by, err := parseFutureTime(c.By, now, "--by", laterDueHour)
+	if err != nil {
+		return err
+	}
+
+	svc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+	store, err := loadLaterStore()
+	if err != nil {
+		return err
+	}
+
+	entries := make([]laterEntry, 0, len(ids))
+	for _, id := range ids {
+		msg, getErr := svc.Users.Messages.Get("me", id).
+			Format("metadata").
+			MetadataHeaders("From", "Subject").
+			Context(ctx).
+			Do()
+		if getErr != nil {
+			return fmt.Errorf("message %s: %w", id, getErr)
+		}
+		entry := laterEntry{
+			Account:   strings.ToLower(account),
+			MessageID: id,
+			ThreadID:  msg.ThreadId,
+			From:      headerValue(msg.Payload, "From"),
+			Subject:   headerValue(msg.Payload, "Subject"),
+			By:        by.UTC().Format(time.RFC3339),
+			CreatedAt: now.UTC().Format(time.RFC3339),
+			AutoDraft: c.AutoDraft,
+		}
+		store.put(entry)
+		entries = append(entries, entry)
+	}
+	if err := store.save(); err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"queued": entries,
+			"count":  len(entries),
+			"by":     by.Format(time.RFC3339),
+		})
+	}
+	u.Out().Printf("queued\t%d", len(entries))
+	u.Out().Printf("by\t%s", by.Local().Format("2006-01-02 15:04 MST"))
+	u.Err().Println("Run `gog gmail later process` periodically (e.g. from cron) to drop answered items and draft overdue replies")
+	return nil
+}
+
+type GmailLaterListCmd struct {
+	Overdue bool `name:"overdue" help:"Only show items past their deadline"`
+	All     bool `name:"all" help:"List items for every account, not just the current one"`
+}
+
+func (c *GmailLaterListCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	store, err := loadLaterStore()
+	if err != nil {
+		return err
+	}
+	entries := store.Entries
+	if !c.All {
+		account, accountErr := requireAccount(flags)
+		if accountErr != nil {
+			return accountErr
+		}
+		entries = store.forAccount(account)
+	}
+	now := time.Now()
+	if c.Overdue {
+		kept := make([]laterEntry, 0, len(entries))
+		for _, e := range entries {
+			if e.overdue(now) {
+				kept = append(kept, e)
+			}
+		}
+		entries = kept
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].By < entries[j].By })
+
+	if outfmt.IsJSON(ctx) {
+		type item struct {
+			laterEntry
+			Overdue bool `json:"overdue"`
+		}
+		items := make([]item, 0, len(entries))
+		for _, e := range entries {
+			items = append(items, item{laterEntry: e, Overdue: e.overdue(now)})
+		}
+		return outfmt.WriteJSON(os.Stdout, map[string]any{"items": items})
+	}
+	if len(entries) == 0 {
+		if c.Overdue {
+			u.Err().Println("No overdue replies")
+		} else {
+			u.Err().Println("Reply-later queue is empty")
+		}
+		return nil
+	}
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "BY\tSTATUS\tFROM\tSUBJECT\tMESSAGE")
+	for _, e := range entries {
+		status := "due " + formatUntil(e.by().Sub(now))
+		if e.overdue(now) {
+			status = "overdue"
+			if e.DraftID != "" {
+				status = "overdue (drafted)"
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			e.by().Local().Format("2006-01-02 15:04"),
+			status,
+			sanitizeTab(e.From),
+			sanitizeTab(e.Subject),
+			e.MessageID)
+	}
+	return nil
+}
+
+type GmailLaterDoneCmd struct {
+	MessageIDs []string `arg:"" name:"messageId" help:"Message IDs"`
+}
+
+func (c *GmailLaterDoneCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	store, err := loadLaterStore()
+	if err != nil {
+		return err
+	}
+	var done []string
+	for _, id := range c.MessageIDs {
+		id = strings.TrimSpace(id)
+		if !store.remove(account, id) {
+			return usagef("message %q is not in the reply-later queue", id)
+		}
+		done = append(done, id)
+	}
+	if err := store.save(); err != nil {
+		return err
+	}
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{"done": done, "count": len(done)})
+	}
+	u.Out().Printf("done\t%d", len(done))
+	return nil
+}
+
+type GmailLaterProcessCmd struct {
+	AutoDraft bool   `name:"auto-draft" help:"Draft replies for every overdue item, not just those added with --auto-draft"`
+	DraftBody string `name:"draft-body" help:"Body for auto-created reply drafts"`
+	DryRun    bool   `name:"dry-run" help:"Report what would change without touching Gmail or the queue"`
+}
+
+func (c *GmailLaterProcessCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	store, err := loadLaterStore()
+	if err != nil {
+		return err
+	}
+	entries := store.forAccount(account)
+	now := time.Now()
+
+	var answered, drafted, missing []string
+	var svc *gmail.Service
+	if len(entries) > 0 {
+		if svc, err = newGmailService(ctx, account); err != nil {
+			return err
+		}
+	}
+	for _, e := range entries {
+		replied, checkErr := repliedSince(ctx, svc, e)
+		switch {
+		case isNotFoundAPIError(checkErr):
+			missing = append(missing, e.MessageID)
+			if !c.DryRun {
+				store.remove(account, e.MessageID)
+			}
+			continue
+		case checkErr != nil:
+			_ = store.save()
+			return fmt.Errorf("check %s: %w", e.MessageID, checkErr)
+		case replied:
+			answered = append(answered, e.MessageID)
+			if !c.DryRun {
+				store.remove(account, e.MessageID)
+			}
+			continue
+		}
+
+		if !e.overdue(now) || e.DraftID != "" || !(e.AutoDraft || c.AutoDraft) {
+			continue
+		}
+		drafted = append(drafted, e.MessageID)
+		if c.DryRun {
+			continue
+		}
+		draftID, draftErr := createLaterDraft(ctx, svc, account, e, c.DraftBody)
+		if draftErr != nil {
+			_ = store.save()
+			return fmt.Errorf("draft reply to %s: %w", e.MessageID, draftErr)
+		}
+		e.DraftID = draftID
+		store.put(e)
+	}
+	if !c.DryRun {
+		if err := store.save(); err != nil {
+			return err
+		}
+		if emitErr := events.Emit(events.TypeJobFinished, account, map[string]any{
+			"job":      "gmail.later.process",
+			"answered": answered,
+			"drafted":  drafted,
+			"missing":  missing,
+		}); emitErr != nil {
+			u.Err().Printf("event log: %v", emitErr)
+		}
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"answered": nonNilStrings(answered),
+			"drafted":  nonNilStrings(drafted),
+			"missing":  nonNilStrings(missing),
+			"dryRun":   c.DryRun,
+		})
+	}
+	u.Out().Printf("answered\t%d", len(answered))
+	u.Out().Printf("drafted\t%d", len(drafted))
+	if len(missing) > 0 {
+		u.Err().Printf("Forgot %d deleted message(s): %s", len(missing), strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// repliedSince reports whether the thread has a message sent by the user
+// after the item was queued.
+func repliedSince(ctx context.Context, svc *gmail.Service, e laterEntry) (bool, error) {
+	threadID := e.ThreadID
+	if threadID == "" {
+		msg, err := svc.Users.Messages.Get("me", e.MessageID).Format("minimal").Context(ctx).Do()
+		if err != nil {
+			return false, err
+		}
+		threadID = msg.ThreadId
+	}
+	thread, err := svc.Users.Threads.Get("me", threadID).Format("minimal").Context(ctx).Do()
+	if err != nil {
+		return false, err
+	}
+	since := e.createdAt().UnixMilli()
+	for _, m := range thread.Messages {
+		if m == nil || m.InternalDate < since {
+			continue
+		}
+		for _, l := range m.LabelIds {
+			if l == "SENT" {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func createLaterDraft(ctx context.Context, svc *gmail.Service, account string, e laterEntry, body string) (string, error) {
+	info, err := fetchReplyInfo(ctx, svc, e.MessageID, "")
+	if err != nil {
+		return "", err
+	}
+	to := info.ReplyToAddr
+	if to == "" {
+		to = info.FromAddr
+	}
+	subject := strings.TrimSpace(e.Subject)
+	if !strings.HasPrefix(strings.ToLower(subject), "re:") {
+		subject = strings.TrimSpace("Re: " + subject)
+	}
+	msg, _, err := buildDraftMessage(ctx, svc, account, draftComposeInput{
+		To:               to,
+		Subject:          subject,
+		Body:             body,
+		ReplyToMessageID: e.MessageID,
+	})
+	if err != nil {
+		return "", err
+	}
+	draft, err := svc.Users.Drafts.Create("me", &gmail.Draft{Message: msg}).Context(ctx).Do()
+	if err != nil {
+		return "", err
+	}
+	return draft.Id, nil
+}
+
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
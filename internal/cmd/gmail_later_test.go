@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGmailLater_AddListProcess(t *testing.T) {
+	const account = "later@example.com"
+	t.Cleanup(func() {
+		store, err := loadLaterStore()
+		if err == nil {
+			store.remove(account, "m1")
+			store.remove(account, "m2")
+			_ = store.save()
+		}
+	})
+
+	var draftRaw string
+	repliedAt := time.Now().Add(time.Hour).UnixMilli()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/gmail/v1")
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && strings.HasPrefix(path, "/users/me/messages/"):
+			id := strings.TrimPrefix(path, "/users/me/messages/")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id":       id,
+				"threadId": "t-" + id,
+				"payload": map[string]any{"headers": []map[string]any{
+					{"name": "From", "value": "Alice <alice@example.com>"},
+					{"name": "Subject", "value": "Budget " + id},
+					{"name": "Message-ID", "value": "<" + id + "@mail.example>"},
+				}},
+			})
+		case r.Method == http.MethodGet && path == "/users/me/threads/t-m1":
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "t-m1", "messages": []map[string]any{
+				{"id": "m1", "labelIds": []string{"INBOX"}, "internalDate": "1"},
+			}})
+		case r.Method == http.MethodGet && path == "/users/me/threads/t-m2":
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "t-m2", "messages": []map[string]any{
+				{"id": "m2", "labelIds": []string{"INBOX"}, "internalDate": "1"},
+				{"id": "r2", "labelIds": []string{"SENT"}, "internalDate": strconv.FormatInt(repliedAt, 10)},
+			}})
+		case r.Method == http.MethodPost && path == "/users/me/drafts":
+			var req struct {
+				Message struct {
+					Raw string `json:"raw"`
+				} `json:"message"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			draftRaw = req.Message.Raw
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "d1", "message": map[string]any{"id": "dm1"}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	stubGmailService(t, srv)
+
+	run := func(args ...string) string {
+		t.Helper()
+		return captureStdout(t, func() {
+			_ = captureStderr(t, func() {
+				if err := Execute(append([]string{"--account", account}, args...)); err != nil {
+					t.Fatalf("Execute %v: %v", args, err)
+				}
+			})
+		})
+	}
+
+	run("gmail", "later", "add", "m1", "--by", "2h", "--auto-draft")
+	run("gmail", "later", "add", "m2", "--by", "friday")
+
+	if out := run("gmail", "later", "list"); !strings.Contains(out, "Budget m1") || !strings.Contains(out, "Budget m2") {
+		t.Fatalf("unexpected list: %q", out)
+	}
+	if out := run("gmail", "later", "list", "--overdue"); strings.Contains(out, "Budget") {
+		t.Fatalf("nothing should be overdue yet: %q", out)
+	}
+
+	// Push m1 past its deadline.
+	store, err := loadLaterStore()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	for _, e := range store.forAccount(account) {
+		if e.MessageID == "m1" {
+			e.By = time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+			store.put(e)
+		}
+	}
+	if err := store.save(); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	var listed struct {
+		Items []struct {
+			MessageID string `json:"messageId"`
+			Overdue   bool   `json:"overdue"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(run("--json", "gmail", "later", "list", "--overdue")), &listed); err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	if len(listed.Items) != 1 || listed.Items[0].MessageID != "m1" || !listed.Items[0].Overdue {
+		t.Fatalf("unexpected overdue list: %#v", listed)
+	}
+
+	var processed struct {
+		Answered []string `json:"answered"`
+		Drafted  []string `json:"drafted"`
+	}
+	if err := json.Unmarshal([]byte(run("--json", "gmail", "later", "process", "--draft-body", "Sorry for the delay.")), &processed); err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	if len(processed.Answered) != 1 || processed.Answered[0] != "m2" || len(processed.Drafted) != 1 || processed.Drafted[0] != "m1" {
+		t.Fatalf("unexpected process result: %#v", processed)
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(draftRaw)
+	if err != nil {
+		t.Fatalf("decode draft: %v", err)
+	}
+	for _, want := range []string{"To: Alice <alice@example.com>", "Subject: Re: Budget m1", "In-Reply-To: <m1@mail.example>", "Sorry for the delay."} {
+		if !strings.Contains(string(raw), want) {
+			t.Fatalf("draft missing %q:\n%s", want, raw)
+		}
+	}
+
+	// A second run must not draft again.
+	draftRaw = ""
+	run("gmail", "later", "process")
+	if draftRaw != "" {
+		t.Fatalf("draft created twice")
+	}
+	entries := mustLoadLater(t).forAccount(account)
+	if len(entries) != 1 || entries[0].MessageID != "m1" || entries[0].DraftID != "d1" {
+		t.Fatalf("unexpected queue: %#v", entries)
+	}
+	if overdue, _ := laterCounts(account, time.Now()); overdue != 1 {
+		t.Fatalf("laterCounts overdue=%d", overdue)
+	}
+
+	run("gmail", "later", "done", "m1")
+	if entries := mustLoadLater(t).forAccount(account); len(entries) != 0 {
+		t.Fatalf("expected empty queue: %#v", entries)
+	}
+	err = Execute([]string{"--account", account, "gmail", "later", "done", "m1"})
+	if err == nil || ExitCode(err) != 2 {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+}
+
+func mustLoadLater(t *testing.T) *laterStore {
+	t.Helper()
+	store, err := loadLaterStore()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	return store
+}
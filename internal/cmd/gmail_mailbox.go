@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"net/mail"
+	"strings"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/steipete/gogcli/internal/googleapi"
+)
+
+// gmailSettingsCommands manage the mailbox itself. Delegation grants access
+// to mail, not settings, so they refuse --mailbox.
+var gmailSettingsCommands = map[string]bool{
+	"settings":    true,
+	"watch":       true,
+	"autoforward": true,
+	"delegates":   true,
+	"filters":     true,
+	"forwarding":  true,
+	"sendas":      true,
+	"vacation":    true,
+}
+
+// gmailLocalStateCommands keep local queues (snooze, reply-later, tracking,
+// alias registry) keyed by the signed-in account; under --mailbox they would
+// mix the delegated mailbox's message IDs into the user's own queues.
+var gmailLocalStateCommands = map[string]bool{
+	"snooze":          true,
+	"later":           true,
+	"track":           true,
+	"alias":           true,
+	"messages snooze": true,
+}
+
+// withGmailMailbox routes the selected gmail command to a delegated mailbox.
+func withGmailMailbox(ctx context.Context, kctx *kong.Context, mailbox string) (context.Context, error) {
+	mailbox = strings.TrimSpace(mailbox)
+	if mailbox == "" {
+		return ctx, nil
+	}
+	if addr, err := mail.ParseAddress(mailbox); err != nil || addr.Address != mailbox || strings.ContainsAny(mailbox, "/?#") {
+		return ctx, usagef("--mailbox: invalid address %q", mailbox)
+	}
+	cmd := strings.Fields(kctx.Command())
+	if len(cmd) > 1 && gmailSettingsCommands[cmd[1]] {
+		return ctx, usagef("gmail %s does not support --mailbox: delegated access covers mail, not settings", cmd[1])
+	}
+	for n := min(len(cmd), 3); n > 1; n-- {
+		if sub := strings.Join(cmd[1:n], " "); gmailLocalStateCommands[sub] {
+			return ctx, usagef("gmail %s does not support --mailbox: its local state belongs to your own account", sub)
+		}
+	}
+	return googleapi.WithGmailMailbox(ctx, mailbox), nil
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+
+	"github.com/steipete/gogcli/internal/googleapi"
+)
+
+func TestGmailMailboxFlag(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"labels":[]}`))
+	}))
+	defer srv.Close()
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+	var gotMailbox string
+	newGmailService = func(ctx context.Context, _ string) (*gmail.Service, error) {
+		gotMailbox = googleapi.GmailMailbox(ctx)
+		return gmail.NewService(ctx, option.WithoutAuthentication(), option.WithHTTPClient(srv.Client()), option.WithEndpoint(srv.URL+"/"))
+	}
+
+	_ = captureStdout(t, func() {
+		if err := Execute([]string{"--json", "--account", "agent@example.com", "gmail", "labels", "list", "--mailbox", "support@example.com"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	if gotMailbox != "support@example.com" {
+		t.Fatalf("mailbox = %q", gotMailbox)
+	}
+
+	for _, args := range [][]string{
+		{"gmail", "--mailbox", "support@example.com", "settings", "filters", "list"},
+		{"gmail", "--mailbox", "support@example.com", "vacation", "get"},
+		{"gmail", "--mailbox", "support/../x@example.com", "labels", "list"},
+		{"gmail", "--mailbox", "support@example.com", "snooze", "list"},
+		{"gmail", "--mailbox", "support@example.com", "later", "list"},
+		{"gmail", "--mailbox", "support@example.com", "messages", "snooze", "m1", "--until", "tomorrow"},
+		{"gmail", "--mailbox", "support@example.com", "alias", "list"},
+		{"gmail", "--mailbox", "support@example.com", "send", "--to", "a@b.com", "--subject", "s", "--body", "b", "--track"},
+	} {
+		err := Execute(append([]string{"--account", "agent@example.com"}, args...))
+		if ExitCode(err) != 2 || !strings.Contains(err.Error(), "mailbox") {
+			t.Fatalf("%v: err = %v", args, err)
+		}
+	}
+}
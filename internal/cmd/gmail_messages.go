@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"os"
 	"strings"
-	"sync"
 	"time"
 
 	"google.golang.org/api/gmail/v1"
@@ -15,16 +14,21 @@ import (
 )
 
 type GmailMessagesCmd struct {
-	Search GmailMessagesSearchCmd `cmd:"" name:"search" group:"Read" help:"Search messages using Gmail query syntax"`
+	Search         GmailMessagesSearchCmd         `cmd:"" name:"search" group:"Read" help:"Search messages using Gmail query syntax"`
+	Get            GmailGetCmd                    `cmd:"" name:"get" group:"Read" help:"Get a message (same as gmail get; --header/--headers-json for headers only)"`
+	Parts          GmailMessagesPartsCmd          `cmd:"" name:"parts" group:"Read" help:"Show the MIME tree of a message or save one part (--save-part)"`
+	Snooze         GmailMessagesSnoozeCmd         `cmd:"" name:"snooze" group:"Organize" help:"Archive messages under the Snoozed label until a wake time"`
+	FindByRFC822ID GmailMessagesFindByRFC822IDCmd `cmd:"" name:"find-by-rfc822-id" group:"Read" help:"Find Gmail messages by their RFC822 Message-ID header"`
 }
 
 type GmailMessagesSearchCmd struct {
-	Query       []string `arg:"" name:"query" help:"Search query"`
+	Query       []string `arg:"" name:"query" optional:"" help:"Search query"`
 	Max         int64    `name:"max" aliases:"limit" help:"Max results" default:"10"`
 	Page        string   `name:"page" help:"Page token"`
 	Timezone    string   `name:"timezone" short:"z" help:"Output timezone (IANA name, e.g. America/New_York, UTC). Default: local"`
 	Local       bool     `name:"local" help:"Use local timezone (default behavior, useful to override --timezone)"`
 	IncludeBody bool     `name:"include-body" help:"Include decoded message body (JSON is full; text output is truncated)"`
+	AliasTag    string   `name:"alias-tag" help:"Only messages sent to the plus-address recorded for this tag (see gmail alias)"`
 }
 
 func (c *GmailMessagesSearchCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -34,6 +38,13 @@ func (c *GmailMessagesSearchCmd) Run(ctx context.Context, flags *RootFlags) erro
 		return err
 	}
 	query := strings.TrimSpace(strings.Join(c.Query, " "))
+	if strings.TrimSpace(c.AliasTag) != "" {
+		aliasQuery, aliasErr := aliasTagQuery(c.AliasTag, account)
+		if aliasErr != nil {
+			return aliasErr
+		}
+		query = strings.TrimSpace(aliasQuery + " " + query)
+	}
 	if query == "" {
 		return usage("missing query")
 	}
@@ -105,117 +116,74 @@ func (c *GmailMessagesSearchCmd) Run(ctx context.Context, flags *RootFlags) erro
 }
 
 type messageItem struct {
-	ID       string   `json:"id"`
-	ThreadID string   `json:"threadId,omitempty"`
-	Date     string   `json:"date,omitempty"`
-	From     string   `json:"from,omitempty"`
-	Subject  string   `json:"subject,omitempty"`
-	Labels   []string `json:"labels,omitempty"`
-	Body     string   `json:"body,omitempty"`
+	ID       string `json:"id"`
+	ThreadID string `json:"threadId,omitempty"`
+	// RFC822MessageID is the Message-ID header, the ID other systems use.
+	RFC822MessageID string   `json:"rfc822MessageId,omitempty"`
+	Date            string   `json:"date,omitempty"`
+	From            string   `json:"from,omitempty"`
+	Subject         string   `json:"subject,omitempty"`
+	Labels          []string `json:"labels,omitempty"`
+	Body            string   `json:"body,omitempty"`
 }
 
 func fetchMessageDetails(ctx context.Context, svc *gmail.Service, messages []*gmail.Message, idToName map[string]string, loc *time.Location, includeBody bool) ([]messageItem, error) {
-	if len(messages) == 0 {
-		return nil, nil
+	ids := make([]string, 0, len(messages))
+	for _, m := range messages {
+		if m != nil && m.Id != "" {
+			ids = append(ids, m.Id)
+		}
 	}
-
-	const maxConcurrency = 10
-	sem := make(chan struct{}, maxConcurrency)
-
-	type result struct {
-		index     int
-		messageID string
-		item      messageItem
-		err       error
+	if len(ids) == 0 {
+		return nil, nil
 	}
 
-	results := make(chan result, len(messages))
-	var wg sync.WaitGroup
-
-	for i, m := range messages {
-		if m == nil || m.Id == "" {
-			continue
+	items := make([]messageItem, len(ids))
+	err := fetchGmailConcurrently(ctx, len(ids), gmailQuotaMessageGet, func(ctx context.Context, idx int) error {
+		messageID := ids[idx]
+		call := svc.Users.Messages.Get("me", messageID)
+		if includeBody {
+			call = call.Format("full")
+		} else {
+			call = call.Format("metadata").
+				MetadataHeaders("From", "Subject", "Date", "Message-ID").
+				Fields("id,threadId,labelIds,payload(headers)")
+		}
+		msg, err := call.Context(ctx).Do()
+		if err != nil {
+			return fmt.Errorf("message %s: %w", messageID, err)
 		}
-		wg.Add(1)
-		go func(idx int, messageID string) {
-			defer wg.Done()
-
-			select {
-			case sem <- struct{}{}:
-				defer func() { <-sem }()
-			case <-ctx.Done():
-				results <- result{index: idx, messageID: messageID, err: ctx.Err()}
-				return
-			}
-
-			call := svc.Users.Messages.Get("me", messageID)
-			if includeBody {
-				call = call.Format("full")
-			} else {
-				call = call.Format("metadata").
-					MetadataHeaders("From", "Subject", "Date").
-					Fields("id,threadId,labelIds,payload(headers)")
-			}
-			msg, err := call.Context(ctx).Do()
-			if err != nil {
-				results <- result{index: idx, messageID: messageID, err: fmt.Errorf("message %s: %w", messageID, err)}
-				return
-			}
 
-			item := messageItem{
-				ID:       messageID,
-				ThreadID: msg.ThreadId,
-			}
+		item := messageItem{
+			ID:       messageID,
+			ThreadID: msg.ThreadId,
+		}
 
-			item.From = sanitizeTab(headerValue(msg.Payload, "From"))
-			item.Subject = sanitizeTab(headerValue(msg.Payload, "Subject"))
-			item.Date = formatGmailDateInLocation(headerValue(msg.Payload, "Date"), loc)
-			if includeBody {
-				item.Body = bestBodyText(msg.Payload)
-			}
+		item.From = sanitizeTab(headerValue(msg.Payload, "From"))
+		item.Subject = sanitizeTab(headerValue(msg.Payload, "Subject"))
+		item.Date = formatGmailDateInLocation(headerValue(msg.Payload, "Date"), loc)
+		item.RFC822MessageID = headerValue(msg.Payload, "Message-ID")
+		if includeBody {
+			item.Body = bestBodyText(msg.Payload)
+		}
 
-			if len(msg.LabelIds) > 0 {
-				names := make([]string, 0, len(msg.LabelIds))
-				for _, lid := range msg.LabelIds {
-					if n, ok := idToName[lid]; ok {
-						names = append(names, n)
-					} else {
-						names = append(names, lid)
-					}
+		if len(msg.LabelIds) > 0 {
+			names := make([]string, 0, len(msg.LabelIds))
+			for _, lid := range msg.LabelIds {
+				if n, ok := idToName[lid]; ok {
+					names = append(names, n)
+				} else {
+					names = append(names, lid)
 				}
-				item.Labels = names
-			}
-
-			results <- result{index: idx, messageID: messageID, item: item}
-		}(i, m.Id)
-	}
-
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
-
-	ordered := make([]messageItem, len(messages))
-	var firstErr error
-	for r := range results {
-		if r.err != nil {
-			if firstErr == nil {
-				firstErr = r.err
 			}
-			ordered[r.index] = messageItem{}
-			continue
+			item.Labels = names
 		}
-		ordered[r.index] = r.item
-	}
-	if firstErr != nil {
-		return nil, firstErr
-	}
 
-	items := make([]messageItem, 0, len(ordered))
-	for _, item := range ordered {
-		if item.ID != "" {
-			items = append(items, item)
-		}
+		items[idx] = item
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	return items, nil
 }
@@ -13,6 +13,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/steipete/gogcli/internal/ics"
 )
 
 type mailAttachment struct {
@@ -184,12 +186,6 @@ func buildRFC822(opts mailOptions, cfg *rfc822Config) ([]byte, error) {
 		if a.Filename == "" {
 			a.Filename = filepath.Base(a.Path)
 		}
-		if a.MIMEType == "" {
-			a.MIMEType = mime.TypeByExtension(strings.ToLower(filepath.Ext(a.Filename)))
-			if a.MIMEType == "" {
-				a.MIMEType = "application/octet-stream"
-			}
-		}
 		if len(a.Data) == 0 {
 			data, err := os.ReadFile(a.Path)
 			if err != nil {
@@ -197,6 +193,9 @@ func buildRFC822(opts mailOptions, cfg *rfc822Config) ([]byte, error) {
 			}
 			a.Data = data
 		}
+		if a.MIMEType == "" {
+			a.MIMEType = attachmentMIMEType(a.Filename, a.Data)
+		}
 
 		b.WriteString(fmt.Sprintf("\r\n--%s\r\n", mixedBoundary))
 		b.WriteString(fmt.Sprintf("Content-Type: %s\r\n", a.MIMEType))
@@ -210,6 +209,23 @@ func buildRFC822(opts mailOptions, cfg *rfc822Config) ([]byte, error) {
 	return b.Bytes(), nil
 }
 
+// attachmentMIMEType guesses the type from the file extension. iCalendar
+// files carry their METHOD so mail clients render them as invitations.
+func attachmentMIMEType(filename string, data []byte) string {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if ext == ".ics" || ext == ".ical" || ext == ".ifb" {
+		t := `text/calendar; charset="utf-8"`
+		if method := ics.MethodOf(data); method != "" {
+			t += "; method=" + method
+		}
+		return t
+	}
+	if t := mime.TypeByExtension(ext); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
+
 func writeHeader(b *bytes.Buffer, name, value string) {
 	b.WriteString(name)
 	b.WriteString(": ")
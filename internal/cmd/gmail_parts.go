@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/errfmt"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+type GmailMessagesPartsCmd struct {
+	MessageID string         `arg:"" name:"messageId" help:"Message ID"`
+	SavePart  string         `name:"save-part" help:"Save the decoded body of this part ID (see the PART column; 'root' for single-part messages) instead of listing; --out - writes to stdout"`
+	Output    OutputPathFlag `embed:""`
+}
+
+type mimePartOutput struct {
+	PartID       string `json:"partId"`
+	Depth        int    `json:"depth"`
+	MimeType     string `json:"mimeType"`
+	Filename     string `json:"filename,omitempty"`
+	Size         int64  `json:"size"`
+	Charset      string `json:"charset,omitempty"`
+	Encoding     string `json:"encoding,omitempty"`
+	Disposition  string `json:"disposition,omitempty"`
+	ContentID    string `json:"contentId,omitempty"`
+	AttachmentID string `json:"attachmentId,omitempty"`
+}
+
+func (c *GmailMessagesPartsCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	messageID := strings.TrimSpace(c.MessageID)
+	if messageID == "" {
+		return usage("empty messageId")
+	}
+
+	svc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	msg, err := svc.Users.Messages.Get("me", messageID).Format(gmailFormatFull).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+	if msg.Payload == nil {
+		return fmt.Errorf("message %s has no payload", messageID)
+	}
+
+	if partID := strings.TrimSpace(c.SavePart); partID != "" {
+		return c.savePart(ctx, u, svc, msg, partID)
+	}
+
+	parts := flattenMIMEParts(msg.Payload, 0, nil)
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"id":       msg.Id,
+			"threadId": msg.ThreadId,
+			"parts":    parts,
+		})
+	}
+
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "PART\tTYPE\tSIZE\tENCODING\tDISPOSITION\tFILENAME")
+	for _, p := range parts {
+		fmt.Fprintf(w, "%s\t%s%s\t%s\t%s\t%s\t%s\n",
+			partLabel(p.PartID), strings.Repeat("  ", p.Depth), p.MimeType, formatBytes(p.Size),
+			orDash(p.Encoding), orDash(p.Disposition), p.Filename)
+	}
+	return nil
+}
+
+func (c *GmailMessagesPartsCmd) savePart(ctx context.Context, u *ui.UI, svc *gmail.Service, msg *gmail.Message, partID string) error {
+	part := findMIMEPart(msg.Payload, partID)
+	if part == nil {
+		return fmt.Errorf("message %s has no part %q: %w", msg.Id, partID, errfmt.ErrNotFound)
+	}
+	if strings.HasPrefix(strings.ToLower(part.MimeType), "multipart/") {
+		return usagef("part %q is a %s container; pick one of its children", partID, part.MimeType)
+	}
+
+	data, err := mimePartData(ctx, svc, msg.Id, part)
+	if err != nil {
+		return err
+	}
+
+	outPath := strings.TrimSpace(c.Output.Path)
+	if outPath == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if outPath == "" {
+		dir, dirErr := config.EnsureGmailAttachmentsDir()
+		if dirErr != nil {
+			return dirErr
+		}
+		name := filepath.Base(strings.TrimSpace(part.Filename))
+		if name == "" || name == "." || name == ".." || name == "/" {
+			name = "part.bin"
+		}
+		outPath = filepath.Join(dir, fmt.Sprintf("%s_part%s_%s", msg.Id, partLabel(partID), name))
+	} else if outPath, err = config.ExpandPath(outPath); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(outPath), 0o700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(outPath, data, 0o600); err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"partId":   partID,
+			"mimeType": part.MimeType,
+			"path":     outPath,
+			"bytes":    len(data),
+		})
+	}
+	u.Out().Printf("path\t%s", outPath)
+	u.Out().Printf("bytes\t%d", len(data))
+	return nil
+}
+
+// flattenMIMEParts walks the tree depth-first, keeping container parts so the
+// structure (and the depth used for indentation) stays visible.
+func flattenMIMEParts(p *gmail.MessagePart, depth int, out []mimePartOutput) []mimePartOutput {
+	if p == nil {
+		return out
+	}
+	item := mimePartOutput{
+		PartID:   p.PartId,
+		Depth:    depth,
+		MimeType: p.MimeType,
+		Filename: p.Filename,
+		Encoding: headerValue(p, "Content-Transfer-Encoding"),
+	}
+	if p.Body != nil {
+		item.Size = p.Body.Size
+		item.AttachmentID = p.Body.AttachmentId
+	}
+	if _, params, err := mime.ParseMediaType(headerValue(p, "Content-Type")); err == nil {
+		item.Charset = params["charset"]
+	}
+	if disposition := headerValue(p, "Content-Disposition"); disposition != "" {
+		if d, _, err := mime.ParseMediaType(disposition); err == nil {
+			item.Disposition = d
+		} else {
+			item.Disposition = strings.TrimSpace(strings.SplitN(disposition, ";", 2)[0])
+		}
+	}
+	item.ContentID = strings.Trim(headerValue(p, "Content-ID"), "<> ")
+	out = append(out, item)
+	for _, child := range p.Parts {
+		out = flattenMIMEParts(child, depth+1, out)
+	}
+	return out
+}
+
+func findMIMEPart(p *gmail.MessagePart, partID string) *gmail.MessagePart {
+	if p == nil {
+		return nil
+	}
+	// Gmail gives the root part an empty ID; its children are "0", "1", ...
+	if p.PartId == partID || (p.PartId == "" && partID == rootPartLabel) {
+		return p
+	}
+	for _, child := range p.Parts {
+		if found := findMIMEPart(child, partID); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// mimePartData returns the transfer-decoded body of a leaf part, fetching it
+// through the attachments endpoint when Gmail did not inline it.
+func mimePartData(ctx context.Context, svc *gmail.Service, messageID string, part *gmail.MessagePart) ([]byte, error) {
+	if part.Body == nil {
+		return nil, nil
+	}
+	encoded := part.Body.Data
+	if encoded == "" && part.Body.AttachmentId != "" {
+		body, err := svc.Users.Messages.Attachments.Get("me", messageID, part.Body.AttachmentId).Context(ctx).Do()
+		if err != nil {
+			return nil, err
+		}
+		if body == nil || body.Data == "" {
+			return nil, errors.New("empty attachment data")
+		}
+		encoded = body.Data
+	}
+	if encoded == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		// Gmail can return padded base64url; accept both.
+		return base64.URLEncoding.DecodeString(encoded)
+	}
+	return data, nil
+}
+
+const rootPartLabel = "root"
+
+func partLabel(partID string) string {
+	if partID == "" {
+		return rootPartLabel
+	}
+	return partID
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
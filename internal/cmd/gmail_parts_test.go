@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func TestGmailMessagesParts(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+
+	text := base64.RawURLEncoding.EncodeToString([]byte("hello"))
+	pdf := base64.URLEncoding.EncodeToString([]byte("%PDF-1.4 fake"))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Path, "/attachments/att1") {
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": pdf, "size": 13})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id":       "m1",
+			"threadId": "t1",
+			"payload": map[string]any{
+				"partId":   "",
+				"mimeType": "multipart/mixed",
+				"headers":  []map[string]any{{"name": "Content-Type", "value": "multipart/mixed; boundary=x"}},
+				"body":     map[string]any{"size": 0},
+				"parts": []map[string]any{
+					{
+						"partId":   "0",
+						"mimeType": "text/plain",
+						"headers": []map[string]any{
+							{"name": "Content-Type", "value": "text/plain; charset=UTF-8"},
+							{"name": "Content-Transfer-Encoding", "value": "quoted-printable"},
+						},
+						"body": map[string]any{"size": 5, "data": text},
+					},
+					{
+						"partId":   "1",
+						"mimeType": "application/pdf",
+						"filename": "invoice.pdf",
+						"headers": []map[string]any{
+							{"name": "Content-Disposition", "value": `attachment; filename="invoice.pdf"`},
+							{"name": "Content-Transfer-Encoding", "value": "base64"},
+						},
+						"body": map[string]any{"size": 13, "attachmentId": "att1"},
+					},
+				},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	run := func(args ...string) string {
+		t.Helper()
+		return captureStdout(t, func() {
+			if err := Execute(append([]string{"--account", "a@b.com"}, args...)); err != nil {
+				t.Fatalf("Execute %v: %v", args, err)
+			}
+		})
+	}
+
+	out := run("--json", "gmail", "messages", "parts", "m1")
+	var parsed struct {
+		Parts []mimePartOutput `json:"parts"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json parse: %v\n%s", err, out)
+	}
+	if len(parsed.Parts) != 3 {
+		t.Fatalf("expected 3 parts, got %#v", parsed.Parts)
+	}
+	if p := parsed.Parts[1]; p.Depth != 1 || p.Charset != "UTF-8" || p.Encoding != "quoted-printable" {
+		t.Fatalf("unexpected text part: %#v", p)
+	}
+	if p := parsed.Parts[2]; p.Disposition != "attachment" || p.AttachmentID != "att1" || p.Filename != "invoice.pdf" {
+		t.Fatalf("unexpected pdf part: %#v", p)
+	}
+
+	out = run("gmail", "messages", "parts", "m1")
+	if !strings.Contains(out, "root") || !strings.Contains(out, "  text/plain") {
+		t.Fatalf("unexpected table: %q", out)
+	}
+
+	if got := run("gmail", "messages", "parts", "m1", "--save-part", "0", "--out", "-"); got != "hello" {
+		t.Fatalf("unexpected stdout part: %q", got)
+	}
+	dest := filepath.Join(t.TempDir(), "out.pdf")
+	run("gmail", "messages", "parts", "m1", "--save-part", "1", "--out", dest)
+	data, err := os.ReadFile(dest)
+	if err != nil || string(data) != "%PDF-1.4 fake" {
+		t.Fatalf("saved part = %q, %v", data, err)
+	}
+
+	if err := Execute([]string{"--account", "a@b.com", "gmail", "messages", "parts", "m1", "--save-part", "root"}); err == nil {
+		t.Fatal("expected error saving a multipart container")
+	}
+}
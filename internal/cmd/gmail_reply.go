@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/summarizer"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+const draftReplyInstructions = "Write a reply from the account owner to the last message in this email thread. Match the tone of the thread, answer what was asked, and do not make commitments the thread does not support. Reply with the body text only: no subject line, no greeting placeholders, no signature."
+
+// GmailReplyCmd drafts a reply for review. It never sends.
+type GmailReplyCmd struct {
+	MessageID     string `arg:"" name:"messageId" help:"Message ID to reply to"`
+	DraftFromHook bool   `name:"draft-from-hook" help:"Ask the configured summarizer hook to write the reply and save it as a draft"`
+	Instructions  string `name:"instructions" help:"Extra guidance for the hook (e.g. 'decline politely')"`
+	ReplyAll      bool   `name:"reply-all" help:"Address the draft to all original recipients"`
+}
+
+func (c *GmailReplyCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	messageID := strings.TrimSpace(c.MessageID)
+	if messageID == "" {
+		return usage("empty messageId")
+	}
+	if !c.DraftFromHook {
+		return usage("gmail reply only drafts replies: pass --draft-from-hook (or use gmail send --reply-to-message-id)")
+	}
+
+	svc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	info, err := fetchReplyInfo(ctx, svc, messageID, "")
+	if err != nil {
+		return err
+	}
+	thread, err := svc.Users.Threads.Get("me", info.ThreadID).Format("full").Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+	msgs, target := replyContext(thread, messageID)
+	if target == nil {
+		return usagef("message %s not found in thread %s", messageID, info.ThreadID)
+	}
+
+	instructions := draftReplyInstructions
+	if extra := strings.TrimSpace(c.Instructions); extra != "" {
+		instructions += "\n\nAdditional guidance: " + extra
+	}
+	body, err := runSummarizer(ctx, account, summarizer.TaskReply, instructions, msgs)
+	if err != nil {
+		return err
+	}
+
+	input := draftComposeInput{
+		Subject:          replySubject(headerValue(target.Payload, "Subject")),
+		Body:             body,
+		ReplyToMessageID: messageID,
+	}
+	if c.ReplyAll {
+		to, cc := buildReplyAllRecipients(info, account)
+		input.To = strings.Join(to, ", ")
+		input.Cc = strings.Join(cc, ", ")
+	} else {
+		input.To = info.ReplyToAddr
+		if input.To == "" {
+			input.To = info.FromAddr
+		}
+	}
+
+	msg, threadID, err := buildDraftMessage(ctx, svc, account, input)
+	if err != nil {
+		return err
+	}
+	draft, err := svc.Users.Drafts.Create("me", &gmail.Draft{Message: msg}).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+	if err := writeDraftResult(ctx, u, draft, threadID); err != nil {
+		return err
+	}
+	if !outfmt.IsJSON(ctx) {
+		u.Err().Println("Draft saved for review; nothing was sent. Send it with: gog gmail drafts send " + draft.Id)
+	}
+	return nil
+}
+
+// replyContext returns the thread up to and including the message being
+// answered, so later messages do not leak into the reply.
+func replyContext(thread *gmail.Thread, messageID string) ([]*gmail.Message, *gmail.Message) {
+	if thread == nil {
+		return nil, nil
+	}
+	for i, m := range thread.Messages {
+		if m != nil && m.Id == messageID {
+			return thread.Messages[:i+1], m
+		}
+	}
+	return nil, nil
+}
+
+func replySubject(subject string) string {
+	subject = strings.TrimSpace(subject)
+	if strings.HasPrefix(strings.ToLower(subject), "re:") {
+		return subject
+	}
+	return strings.TrimSpace("Re: " + subject)
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/summarizer"
+)
+
+func TestGmailReplyDraftFromHook(t *testing.T) {
+	textPart := func(s string) map[string]any {
+		return map[string]any{"data": base64.RawURLEncoding.EncodeToString([]byte(s))}
+	}
+	message := func(id, from, body string) map[string]any {
+		return map[string]any{
+			"id": id, "threadId": "t1",
+			"payload": map[string]any{
+				"mimeType": "text/plain",
+				"headers": []map[string]string{
+					{"name": "Subject", "value": "Review"},
+					{"name": "From", "value": from},
+					{"name": "To", "value": "me@example.com"},
+					{"name": "Message-ID", "value": "<" + id + "@example.com>"},
+				},
+				"body": textPart(body),
+			},
+		}
+	}
+
+	var raw string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(r.URL.Path, "/users/me/messages/m1") && r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(message("m1", "Ann <ann@example.com>", ""))
+		case strings.Contains(r.URL.Path, "/users/me/threads/t1") && r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "t1", "messages": []any{
+				message("m1", "Ann <ann@example.com>", "Can we move the review to Friday?"),
+				message("m2", "Bob <bob@example.com>", "Later message"),
+			}})
+		case strings.HasSuffix(r.URL.Path, "/users/me/drafts") && r.Method == http.MethodPost:
+			var draft gmail.Draft
+			_ = json.NewDecoder(r.Body).Decode(&draft)
+			raw = draft.Message.Raw
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "d1", "message": map[string]any{"id": "m9", "threadId": "t1"}})
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	stubGmailService(t, srv)
+	hook := &fakeHook{reply: "Friday works for me."}
+	stubSummarizerHook(t, hook)
+
+	stdout := captureStdout(t, func() {
+		if err := Execute([]string{"--json", "--account", "me@example.com", "gmail", "reply", "m1", "--draft-from-hook", "--instructions", "keep it short"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	var out map[string]any
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("json: %v\n%s", err, stdout)
+	}
+	if out["draftId"] != "d1" {
+		t.Fatalf("draftId = %v", out["draftId"])
+	}
+
+	if len(hook.reqs) != 1 {
+		t.Fatalf("hook calls = %d", len(hook.reqs))
+	}
+	req := hook.reqs[0]
+	if req.Task != summarizer.TaskReply || len(req.Messages) != 1 || req.Messages[0].ID != "m1" {
+		t.Fatalf("request = %+v", req)
+	}
+	if !strings.Contains(req.Instructions, "keep it short") {
+		t.Fatalf("instructions = %q", req.Instructions)
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		t.Fatalf("decode raw: %v", err)
+	}
+	mime := string(decoded)
+	for _, want := range []string{"To: Ann <ann@example.com>", "Subject: Re: Review", "In-Reply-To: <m1@example.com>", "Friday works for me."} {
+		if !strings.Contains(mime, want) {
+			t.Fatalf("draft missing %q:\n%s", want, mime)
+		}
+	}
+}
+
+func TestGmailReplyRequiresDraftFromHook(t *testing.T) {
+	err := Execute([]string{"--account", "me@example.com", "gmail", "reply", "m1"})
+	if ExitCode(err) != 2 {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+}
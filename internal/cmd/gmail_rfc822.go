@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/steipete/gogcli/internal/errfmt"
+	"github.com/steipete/gogcli/internal/outfmt"
+)
+
+type GmailMessagesFindByRFC822IDCmd struct {
+	MessageID string `arg:"" name:"messageId" help:"RFC822 Message-ID, with or without angle brackets (e.g. '<abc@mail.example>')"`
+	Timezone  string `name:"timezone" short:"z" help:"Output timezone (IANA name, e.g. America/New_York, UTC). Default: local"`
+	Local     bool   `name:"local" help:"Use local timezone (default behavior, useful to override --timezone)"`
+}
+
+func (c *GmailMessagesFindByRFC822IDCmd) Run(ctx context.Context, flags *RootFlags) error {
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	rfc822ID := normalizeRFC822MessageID(c.MessageID)
+	if rfc822ID == "" {
+		return usage("empty messageId")
+	}
+
+	svc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	// A message sent to yourself shows up twice (sent and received copy), so
+	// this can match more than one Gmail message.
+	resp, err := svc.Users.Messages.List("me").
+		Q("rfc822msgid:" + rfc822ID).
+		IncludeSpamTrash(true).
+		Fields("messages(id,threadId)").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return err
+	}
+	if len(resp.Messages) == 0 {
+		return fmt.Errorf("no message with Message-ID <%s>: %w", rfc822ID, errfmt.ErrNotFound)
+	}
+
+	idToName, err := fetchLabelIDToName(svc)
+	if err != nil {
+		return err
+	}
+	loc, err := resolveOutputLocation(c.Timezone, c.Local)
+	if err != nil {
+		return err
+	}
+	items, err := fetchMessageDetails(ctx, svc, resp.Messages, idToName, loc, false)
+	if err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"rfc822MessageId": "<" + rfc822ID + ">",
+			"messages":        items,
+		})
+	}
+
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "ID\tTHREAD\tDATE\tFROM\tSUBJECT\tLABELS")
+	for _, it := range items {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", it.ID, it.ThreadID, it.Date, it.From, it.Subject, strings.Join(it.Labels, ","))
+	}
+	return nil
+}
+
+// normalizeRFC822MessageID strips whitespace, a "mid:" URL (RFC 2392), and
+// the angle brackets, which Gmail's rfc822msgid: search does not want.
+func normalizeRFC822MessageID(raw string) string {
+	s := strings.TrimSpace(raw)
+	if len(s) > 4 && strings.EqualFold(s[:4], "mid:") {
+		s = s[4:]
+		if unescaped, err := url.PathUnescape(s); err == nil {
+			s = unescaped
+		}
+	}
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, "<")
+	s = strings.TrimSuffix(s, ">")
+	return strings.TrimSpace(s)
+}
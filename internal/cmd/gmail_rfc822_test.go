@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/mock"
+)
+
+func TestNormalizeRFC822MessageID(t *testing.T) {
+	for in, want := range map[string]string{
+		"<abc@mail.example>":     "abc@mail.example",
+		"  abc@mail.example ":    "abc@mail.example",
+		"mid:abc%40mail.example": "abc@mail.example",
+		"<>":                     "",
+	} {
+		if got := normalizeRFC822MessageID(in); got != want {
+			t.Fatalf("normalizeRFC822MessageID(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestGmailMessagesFindByRFC822ID(t *testing.T) {
+	s, err := mock.New(nil, mock.ServiceGmail)
+	if err != nil {
+		t.Fatalf("mock.New: %v", err)
+	}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	t.Setenv(googleapi.EnvAPIEndpoint, srv.URL)
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "test@example.com", "gmail", "messages", "find-by-rfc822-id", "<18c0a1b2c3d4e503@mock.gog>"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	var parsed struct {
+		Messages []messageItem `json:"messages"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if len(parsed.Messages) != 1 || parsed.Messages[0].ID != "18c0a1b2c3d4e503" || parsed.Messages[0].RFC822MessageID != "<18c0a1b2c3d4e503@mock.gog>" {
+		t.Fatalf("unexpected messages: %#v", parsed.Messages)
+	}
+
+	_ = captureStderr(t, func() {
+		err := Execute([]string{"--account", "test@example.com", "gmail", "messages", "find-by-rfc822-id", "missing@example.com"})
+		if err == nil || ExitCode(err) != 4 {
+			t.Fatalf("expected not-found exit 4, got %v (%d)", err, ExitCode(err))
+		}
+	})
+}
@@ -4,34 +4,44 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"html"
 	"net/mail"
 	"os"
 	"strings"
+	"time"
 
 	"google.golang.org/api/gmail/v1"
 
 	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/googleapi"
 	"github.com/steipete/gogcli/internal/outfmt"
 	"github.com/steipete/gogcli/internal/tracking"
 	"github.com/steipete/gogcli/internal/ui"
 )
 
 type GmailSendCmd struct {
-	To               string   `name:"to" help:"Recipients (comma-separated; required unless --reply-all is used)"`
-	Cc               string   `name:"cc" help:"CC recipients (comma-separated)"`
-	Bcc              string   `name:"bcc" help:"BCC recipients (comma-separated)"`
-	Subject          string   `name:"subject" help:"Subject (required)"`
-	Body             string   `name:"body" help:"Body (plain text; required unless --body-html is set)"`
-	BodyFile         string   `name:"body-file" help:"Body file path (plain text; '-' for stdin)"`
-	BodyHTML         string   `name:"body-html" help:"Body (HTML; optional)"`
-	ReplyToMessageID string   `name:"reply-to-message-id" aliases:"in-reply-to" help:"Reply to Gmail message ID (sets In-Reply-To/References and thread)"`
-	ThreadID         string   `name:"thread-id" help:"Reply within a Gmail thread (uses latest message for headers)"`
-	ReplyAll         bool     `name:"reply-all" help:"Auto-populate recipients from original message (requires --reply-to-message-id or --thread-id)"`
-	ReplyTo          string   `name:"reply-to" help:"Reply-To header address"`
-	Attach           []string `name:"attach" help:"Attachment file path (repeatable)"`
-	From             string   `name:"from" help:"Send from this email address (must be a verified send-as alias)"`
-	Track            bool     `name:"track" help:"Enable open tracking (requires tracking setup)"`
-	TrackSplit       bool     `name:"track-split" help:"Send tracked messages separately per recipient"`
+	To               string          `name:"to" help:"Recipients (comma-separated; required unless --reply-all or --to-group is used)"`
+	ToGroup          []string        `name:"to-group" sep:"none" help:"Contact group to expand into individual To recipients (name or contactGroups/ID; repeatable)"`
+	Cc               string          `name:"cc" help:"CC recipients (comma-separated)"`
+	Bcc              string          `name:"bcc" help:"BCC recipients (comma-separated)"`
+	Subject          string          `name:"subject" help:"Subject (required)"`
+	Body             string          `name:"body" help:"Body (plain text; required unless --body-html is set)"`
+	BodyFile         string          `name:"body-file" help:"Body file path (plain text; '-' for stdin)"`
+	BodyHTML         string          `name:"body-html" help:"Body (HTML; optional)"`
+	ReplyToMessageID string          `name:"reply-to-message-id" aliases:"in-reply-to" help:"Reply to Gmail message ID (sets In-Reply-To/References and thread)"`
+	ThreadID         string          `name:"thread-id" help:"Reply within a Gmail thread (uses latest message for headers)"`
+	ReplyAll         bool            `name:"reply-all" help:"Auto-populate recipients from original message (requires --reply-to-message-id or --thread-id)"`
+	ReplyTo          string          `name:"reply-to" help:"Reply-To header address"`
+	Attach           []string        `name:"attach" help:"Attachment file path (repeatable)"`
+	From             string          `name:"from" help:"Send from this email address (must be a verified send-as alias)"`
+	Signature        string          `name:"signature" help:"Plain-text signature appended below a '-- ' line (handy as a config default)"`
+	Track            bool            `name:"track" help:"Enable open tracking (requires tracking setup)"`
+	TrackSplit       bool            `name:"track-split" help:"Send tracked messages separately per recipient"`
+	Rate             string          `name:"rate" help:"Max send rate for per-recipient sends (e.g. 30/min, 1/s)"`
+	DailyCap         int             `name:"daily-cap" help:"Rolling 24h send cap (default: 500 for gmail.com, 2000 for Workspace)"`
+	Spread           bool            `name:"spread" help:"If the daily cap would be exceeded, wait and spread remaining sends as capacity frees up"`
+	Idempotency      IdempotencyFlag `embed:""`
+	Guardrail        GuardrailFlag   `embed:""`
 }
 
 type sendBatch struct {
@@ -58,9 +68,10 @@ type sendMessageOptions struct {
 	Attachments []mailAttachment
 	Track       bool
 	TrackingCfg *tracking.Config
+	Pacer       *sendPacer
 }
 
-func (c *GmailSendCmd) Run(ctx context.Context, flags *RootFlags) error {
+func (c *GmailSendCmd) Run(ctx context.Context, flags *RootFlags) (err error) {
 	u := ui.FromContext(ctx)
 	account, err := requireAccount(flags)
 	if err != nil {
@@ -84,9 +95,9 @@ func (c *GmailSendCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return usage("--reply-all requires --reply-to-message-id or --thread-id")
 	}
 
-	// --to is required unless --reply-all is used
-	if strings.TrimSpace(c.To) == "" && !c.ReplyAll {
-		return usage("required: --to (or use --reply-all with --reply-to-message-id or --thread-id)")
+	// --to is required unless --reply-all or --to-group is used
+	if strings.TrimSpace(c.To) == "" && !c.ReplyAll && len(c.ToGroup) == 0 {
+		return usage("required: --to, --to-group (or use --reply-all with --reply-to-message-id or --thread-id)")
 	}
 	if strings.TrimSpace(c.Subject) == "" {
 		return usage("required: --subject")
@@ -97,6 +108,19 @@ func (c *GmailSendCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if c.TrackSplit && !c.Track {
 		return usage("--track-split requires --track")
 	}
+	if c.Track && googleapi.GmailMailbox(ctx) != "" {
+		return usage("--track does not support --mailbox: tracking state belongs to your own account")
+	}
+	rate, err := parseSendRate(c.Rate)
+	if err != nil {
+		return usage(err.Error())
+	}
+
+	idem, done, err := beginIdempotent(ctx, c.Idempotency.Key, account, "gmail.send")
+	if done || err != nil {
+		return err
+	}
+	defer idem.finish(&err)
 
 	svc, err := newGmailService(ctx, account)
 	if err != nil {
@@ -152,10 +176,17 @@ func (c *GmailSendCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if strings.TrimSpace(c.Cc) != "" {
 		ccRecipients = splitCSV(c.Cc)
 	}
+	if len(c.ToGroup) > 0 {
+		groupRecipients, groupErr := expandContactGroups(ctx, account, c.ToGroup)
+		if groupErr != nil {
+			return groupErr
+		}
+		toRecipients = deduplicateAddresses(append(toRecipients, groupRecipients...))
+	}
 
 	// Final validation: we must have at least one recipient
 	if len(toRecipients) == 0 {
-		return usage("no recipients: specify --to or use --reply-all with a message that has recipients")
+		return usage("no recipients: specify --to, --to-group, or use --reply-all with a message that has recipients")
 	}
 
 	bccRecipients := splitCSV(c.Bcc)
@@ -169,6 +200,18 @@ func (c *GmailSendCmd) Run(ctx context.Context, flags *RootFlags) error {
 		atts = append(atts, mailAttachment{Path: expanded})
 	}
 
+	if err = enforceSendGuardrails(ctx, flags, account, c.Guardrail, sendGuardrailCheck{
+		Recipients:     append(append(append([]string{}, toRecipients...), ccRecipients...), bccRecipients...),
+		HasAttachments: len(atts) > 0,
+	}); err != nil {
+		return err
+	}
+
+	// Tracked group sends always go out per recipient so each pixel maps to one person.
+	if c.Track && len(c.ToGroup) > 0 {
+		c.TrackSplit = true
+	}
+
 	var trackingCfg *tracking.Config
 	if c.Track {
 		trackingCfg, err = c.resolveTrackingConfig(account, toRecipients, ccRecipients, bccRecipients)
@@ -178,17 +221,36 @@ func (c *GmailSendCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	batches := buildSendBatches(toRecipients, ccRecipients, bccRecipients, c.Track, c.TrackSplit)
+	pacer, err := newSendPacer(sendPacingOptions{
+		Account:  account,
+		Rate:     rate,
+		DailyCap: c.DailyCap,
+		Spread:   c.Spread,
+		Count:    len(batches),
+	})
+	if err != nil {
+		return err
+	}
+	if finish := pacer.finishesAt(); time.Until(finish) > time.Minute {
+		u.Err().Printf("Pacing %d sends; last send expected at %s", len(batches), finish.Local().Format(time.RFC3339))
+	}
+
 	results, err := sendGmailBatches(ctx, svc, sendMessageOptions{
 		FromAddr:    fromAddr,
 		ReplyTo:     c.ReplyTo,
 		Subject:     c.Subject,
-		Body:        body,
-		BodyHTML:    c.BodyHTML,
+		Body:        appendTextSignature(body, c.Signature),
+		BodyHTML:    appendHTMLSignature(c.BodyHTML, c.Signature),
 		ReplyInfo:   replyInfo,
 		Attachments: atts,
 		Track:       c.Track,
 		TrackingCfg: trackingCfg,
+		Pacer:       pacer,
 	}, batches)
+	// Record partial sends too: retrying them would send duplicates.
+	for _, r := range results {
+		idem.resource(r.MessageID)
+	}
 	if err != nil {
 		return err
 	}
@@ -293,10 +355,14 @@ func sendGmailBatches(ctx context.Context, svc *gmail.Service, opts sendMessageO
 			msg.ThreadId = reply.ThreadID
 		}
 
+		if err := opts.Pacer.wait(ctx); err != nil {
+			return results, err
+		}
 		sent, err := svc.Users.Messages.Send("me", msg).Context(ctx).Do()
 		if err != nil {
-			return nil, err
+			return results, err
 		}
+		opts.Pacer.recordSent()
 
 		resultRecipient := strings.TrimSpace(batch.TrackingRecipient)
 		if resultRecipient == "" {
@@ -626,3 +692,21 @@ func deduplicateAddresses(addresses []string) []string {
 	}
 	return result
 }
+
+// appendTextSignature adds sig after the conventional "-- " delimiter.
+func appendTextSignature(body, sig string) string {
+	sig = strings.TrimSpace(sig)
+	if sig == "" || strings.TrimSpace(body) == "" {
+		return body
+	}
+	return strings.TrimRight(body, "\r\n") + "\n\n-- \n" + sig + "\n"
+}
+
+func appendHTMLSignature(bodyHTML, sig string) string {
+	sig = strings.TrimSpace(sig)
+	if sig == "" || strings.TrimSpace(bodyHTML) == "" {
+		return bodyHTML
+	}
+	escaped := strings.ReplaceAll(html.EscapeString(sig), "\n", "<br>")
+	return bodyHTML + "<br><br>-- <br>" + escaped
+}
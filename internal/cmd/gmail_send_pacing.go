@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+)
+
+// Gmail sending limits (rolling 24h window). These are Google's documented
+// defaults; trial and reseller Workspace accounts may be lower.
+const (
+	gmailDailyCapConsumer  = 500
+	gmailDailyCapWorkspace = 2000
+	gmailSendWindow        = 24 * time.Hour
+)
+
+var sendPacerSleep = func(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type sendRate struct {
+	Count int
+	Per   time.Duration
+}
+
+func (r sendRate) interval() time.Duration {
+	if r.Count <= 0 || r.Per <= 0 {
+		return 0
+	}
+	return r.Per / time.Duration(r.Count)
+}
+
+// parseSendRate parses rates like "30/min", "1/s", "100/hour", "500/day".
+func parseSendRate(raw string) (sendRate, error) {
+	raw = strings.TrimSpace(strings.ToLower(raw))
+	if raw == "" {
+		return sendRate{}, nil
+	}
+	countPart, unitPart, ok := strings.Cut(raw, "/")
+	if !ok {
+		return sendRate{}, fmt.Errorf("invalid rate %q (expected N/unit, e.g. 30/min)", raw)
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(countPart))
+	if err != nil || count <= 0 {
+		return sendRate{}, fmt.Errorf("invalid rate %q: count must be a positive integer", raw)
+	}
+	var per time.Duration
+	switch strings.TrimSpace(unitPart) {
+	case "s", "sec", "second":
+		per = time.Second
+	case "m", "min", "minute":
+		per = time.Minute
+	case "h", "hr", "hour":
+		per = time.Hour
+	case "d", "day":
+		per = gmailSendWindow
+	default:
+		return sendRate{}, fmt.Errorf("invalid rate %q: unit must be s|min|hour|day", raw)
+	}
+	return sendRate{Count: count, Per: per}, nil
+}
+
+func defaultDailySendCap(account string) int {
+	if isConsumerAccount(account) {
+		return gmailDailyCapConsumer
+	}
+	return gmailDailyCapWorkspace
+}
+
+// sendLedger records recent send timestamps per account so bulk sends can
+// stay under Gmail's rolling daily cap. It only knows about sends made by gog.
+type sendLedger struct {
+	path string
+	Sent []int64 `json:"sent"`
+}
+
+func sendLedgerPath(account string) (string, error) {
+	dir, err := config.EnsureGmailSendDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, sanitizeAccountForPath(account)+".json"), nil
+}
+
+func loadSendLedger(account string) (*sendLedger, error) {
+	path, err := sendLedgerPath(account)
+	if err != nil {
+		return nil, err
+	}
+	ledger := &sendLedger{path: path}
+	data, err := os.ReadFile(path) //nolint:gosec // path under config dir
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ledger, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, ledger); err != nil {
+		return nil, fmt.Errorf("parse send ledger: %w", err)
+	}
+	return ledger, nil
+}
+
+func (l *sendLedger) prune(now time.Time) {
+	cutoff := now.Add(-gmailSendWindow).UnixMilli()
+	kept := l.Sent[:0]
+	for _, ts := range l.Sent {
+		if ts > cutoff {
+			kept = append(kept, ts)
+		}
+	}
+	l.Sent = kept
+	sort.Slice(l.Sent, func(i, j int) bool { return l.Sent[i] < l.Sent[j] })
+}
+
+func (l *sendLedger) record(now time.Time) error {
+	l.Sent = append(l.Sent, now.UnixMilli())
+	l.prune(now)
+	payload, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(l.path, append(payload, '\n'), 0o600)
+}
+
+// planSendTimes returns the earliest time each of n sends may go out, honoring
+// the minimum interval between sends and the rolling 24h cap given prior sends.
+func planSendTimes(now time.Time, prior []int64, n int, interval time.Duration, dailyCap int) []time.Time {
+	window := make([]time.Time, 0, len(prior)+n)
+	for _, ts := range prior {
+		window = append(window, time.UnixMilli(ts))
+	}
+	sort.Slice(window, func(i, j int) bool { return window[i].Before(window[j]) })
+
+	out := make([]time.Time, 0, n)
+	next := now
+	for i := 0; i < n; i++ {
+		if dailyCap > 0 && len(window) >= dailyCap {
+			// The oldest send that must age out before another slot frees up.
+			freeAt := window[len(window)-dailyCap].Add(gmailSendWindow)
+			if freeAt.After(next) {
+				next = freeAt
+			}
+		}
+		out = append(out, next)
+		window = append(window, next)
+		next = next.Add(interval)
+	}
+	return out
+}
+
+type sendPacer struct {
+	ledger   *sendLedger
+	schedule []time.Time
+	index    int
+	now      func() time.Time
+}
+
+type sendPacingOptions struct {
+	Account  string
+	Rate     sendRate
+	DailyCap int
+	Spread   bool
+	Count    int
+}
+
+// newSendPacer builds the pacing plan for a bulk send. Single sends are never
+// delayed; they are only recorded in the ledger.
+func newSendPacer(opts sendPacingOptions) (*sendPacer, error) {
+	ledger, err := loadSendLedger(opts.Account)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	ledger.prune(now)
+
+	p := &sendPacer{ledger: ledger, now: time.Now}
+	if opts.Count <= 1 {
+		return p, nil
+	}
+
+	dailyCap := opts.DailyCap
+	if dailyCap <= 0 {
+		dailyCap = defaultDailySendCap(opts.Account)
+	}
+	remaining := dailyCap - len(ledger.Sent)
+	if remaining < opts.Count && !opts.Spread {
+		if remaining < 0 {
+			remaining = 0
+		}
+		return nil, usagef("sending %d messages would exceed the daily cap (%d remaining of %d in the last 24h); use --spread to schedule the rest as capacity frees up", opts.Count, remaining, dailyCap)
+	}
+
+	p.schedule = planSendTimes(now, ledger.Sent, opts.Count, opts.Rate.interval(), dailyCap)
+	return p, nil
+}
+
+// finishesAt reports when the last planned send goes out (zero if unpaced).
+func (p *sendPacer) finishesAt() time.Time {
+	if p == nil || len(p.schedule) == 0 {
+		return time.Time{}
+	}
+	return p.schedule[len(p.schedule)-1]
+}
+
+func (p *sendPacer) wait(ctx context.Context) error {
+	if p == nil || p.index >= len(p.schedule) {
+		return nil
+	}
+	at := p.schedule[p.index]
+	p.index++
+	return sendPacerSleep(ctx, at.Sub(p.now()))
+}
+
+func (p *sendPacer) recordSent() {
+	if p == nil || p.ledger == nil {
+		return
+	}
+	if err := p.ledger.record(p.now()); err != nil {
+		slog.Warn("record send in ledger failed", "err", err)
+	}
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseSendRate(t *testing.T) {
+	cases := []struct {
+		in       string
+		interval time.Duration
+	}{
+		{"", 0},
+		{"30/min", 2 * time.Second},
+		{"1/s", time.Second},
+		{"120/hour", 30 * time.Second},
+		{"24/day", time.Hour},
+	}
+	for _, tc := range cases {
+		r, err := parseSendRate(tc.in)
+		if err != nil {
+			t.Fatalf("parseSendRate(%q): %v", tc.in, err)
+		}
+		if got := r.interval(); got != tc.interval {
+			t.Fatalf("parseSendRate(%q) interval=%v want %v", tc.in, got, tc.interval)
+		}
+	}
+
+	for _, bad := range []string{"30", "0/min", "x/min", "5/fortnight"} {
+		if _, err := parseSendRate(bad); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestPlanSendTimes_RateAndCap(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	prior := []int64{
+		now.Add(-23 * time.Hour).UnixMilli(),
+		now.Add(-22 * time.Hour).UnixMilli(),
+	}
+
+	got := planSendTimes(now, prior, 3, time.Minute, 3)
+	want := []time.Time{
+		now,
+		now.Add(time.Hour),     // oldest prior send ages out
+		now.Add(2 * time.Hour), // second prior send ages out
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Fatalf("send %d at %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	unlimited := planSendTimes(now, nil, 3, 2*time.Second, 0)
+	if !unlimited[2].Equal(now.Add(4 * time.Second)) {
+		t.Fatalf("unexpected paced schedule: %v", unlimited)
+	}
+}
+
+func TestNewSendPacer_CapExceeded(t *testing.T) {
+	account := "pacer-cap@gmail.com"
+	ledger, err := loadSendLedger(account)
+	if err != nil {
+		t.Fatalf("loadSendLedger: %v", err)
+	}
+	if err := ledger.record(time.Now()); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	_, err = newSendPacer(sendPacingOptions{Account: account, DailyCap: 2, Count: 2})
+	if err == nil || ExitCode(err) != 2 {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+
+	p, err := newSendPacer(sendPacingOptions{Account: account, DailyCap: 2, Count: 2, Spread: true})
+	if err != nil {
+		t.Fatalf("newSendPacer spread: %v", err)
+	}
+	if time.Until(p.finishesAt()) < 23*time.Hour {
+		t.Fatalf("expected second send to wait for the window, got %v", p.finishesAt())
+	}
+}
+
+func TestSendPacer_WaitAndRecord(t *testing.T) {
+	origSleep := sendPacerSleep
+	t.Cleanup(func() { sendPacerSleep = origSleep })
+
+	var waits []time.Duration
+	sendPacerSleep = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+
+	account := "pacer-wait@example.com"
+	p, err := newSendPacer(sendPacingOptions{Account: account, Rate: sendRate{Count: 1, Per: time.Minute}, Count: 3})
+	if err != nil {
+		t.Fatalf("newSendPacer: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := p.wait(context.Background()); err != nil {
+			t.Fatalf("wait: %v", err)
+		}
+		p.recordSent()
+	}
+	if len(waits) != 3 || waits[2] < 100*time.Second {
+		t.Fatalf("unexpected waits: %v", waits)
+	}
+
+	ledger, err := loadSendLedger(account)
+	if err != nil {
+		t.Fatalf("loadSendLedger: %v", err)
+	}
+	if len(ledger.Sent) != 3 {
+		t.Fatalf("expected 3 ledger entries, got %d", len(ledger.Sent))
+	}
+}
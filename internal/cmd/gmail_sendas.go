@@ -24,7 +24,9 @@ type GmailSendAsCmd struct {
 	Update GmailSendAsUpdateCmd `cmd:"" name:"update" help:"Update a send-as alias"`
 }
 
-type GmailSendAsListCmd struct{}
+type GmailSendAsListCmd struct {
+	SettingsDiffFlags `embed:""`
+}
 
 const sendAsYes = "yes"
 
@@ -40,6 +42,10 @@ func (c *GmailSendAsListCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
+	if c.active() {
+		return c.run(ctx, flags, "send-as aliases", "sendAs", &sendAsState{svc: svc})
+	}
+
 	resp, err := svc.Users.Settings.SendAs.List("me").Do()
 	if err != nil {
 		return err
@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// vacationState diffs the vacation responder. Only the fields present in the
+// desired file are compared and updated.
+type vacationState struct {
+	svc     *gmail.Service
+	live    map[string]any
+	desired map[string]any
+}
+
+func (s *vacationState) plan(ctx context.Context, desired json.RawMessage) ([]settingChange, error) {
+	if err := json.Unmarshal(desired, &s.desired); err != nil {
+		return nil, usagef("vacation settings must be a JSON object: %v", err)
+	}
+	current, err := s.svc.Users.Settings.GetVacation("me").Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	live, err := toJSONValue(current)
+	if err != nil {
+		return nil, err
+	}
+	s.live, _ = live.(map[string]any)
+	return diffSettingValues("", s.live, s.desired, nil, nil), nil
+}
+
+func (s *vacationState) apply(ctx context.Context) error {
+	var vacation gmail.VacationSettings
+	if err := convertSettingValue(mergeSettingValues(s.live, s.desired, nil), &vacation); err != nil {
+		return usagef("vacation settings: %v", err)
+	}
+	_, err := s.svc.Users.Settings.UpdateVacation("me", &vacation).Context(ctx).Do()
+	return err
+}
+
+// filtersState diffs filters as a set. Gmail filters cannot be edited, so a
+// filter is identified by its criteria and action; a changed filter shows up
+// as one removal and one addition. Filter IDs in the desired file are ignored.
+type filtersState struct {
+	svc    *gmail.Service
+	add    []*gmail.Filter
+	remove []string
+}
+
+func (s *filtersState) plan(ctx context.Context, desired json.RawMessage) ([]settingChange, error) {
+	var want []*gmail.Filter
+	if err := json.Unmarshal(desired, &want); err != nil {
+		return nil, usagef("filters must be a JSON array of filters: %v", err)
+	}
+	resp, err := s.svc.Users.Settings.Filters.List("me").Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	live := map[string]*gmail.Filter{}
+	for _, f := range resp.Filter {
+		key, _, err := filterIdentity(f)
+		if err != nil {
+			return nil, err
+		}
+		live[key] = f
+	}
+
+	var changes []settingChange
+	wanted := map[string]bool{}
+	for _, f := range want {
+		if f == nil {
+			continue
+		}
+		key, value, err := filterIdentity(f)
+		if err != nil {
+			return nil, err
+		}
+		if wanted[key] {
+			continue
+		}
+		wanted[key] = true
+		if _, ok := live[key]; ok {
+			continue
+		}
+		f.Id = ""
+		s.add = append(s.add, f)
+		changes = append(changes, settingChange{Op: settingAdd, Path: "filters", To: value})
+	}
+
+	keys := make([]string, 0, len(live))
+	for key := range live {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if wanted[key] {
+			continue
+		}
+		f := live[key]
+		_, value, _ := filterIdentity(f)
+		s.remove = append(s.remove, f.Id)
+		changes = append(changes, settingChange{Op: settingRemove, Path: "filters." + f.Id, From: value})
+	}
+	return changes, nil
+}
+
+func (s *filtersState) apply(ctx context.Context) error {
+	// Create first so a failed run never leaves mail unfiltered.
+	for _, f := range s.add {
+		if _, err := s.svc.Users.Settings.Filters.Create("me", f).Context(ctx).Do(); err != nil {
+			return fmt.Errorf("create filter: %w", err)
+		}
+	}
+	for _, id := range s.remove {
+		if err := s.svc.Users.Settings.Filters.Delete("me", id).Context(ctx).Do(); err != nil {
+			return fmt.Errorf("delete filter %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// filterIdentity returns the criteria and action of f as a comparable key and
+// as a generic JSON value.
+func filterIdentity(f *gmail.Filter) (string, any, error) {
+	value, err := toJSONValue(&gmail.Filter{Criteria: f.Criteria, Action: f.Action})
+	if err != nil {
+		return "", nil, err
+	}
+	return settingKey(value), value, nil
+}
+
+// sendAsReadOnly lists send-as fields Gmail manages itself.
+var sendAsReadOnly = map[string]bool{
+	"sendAsEmail":        true,
+	"isPrimary":          true,
+	"verificationStatus": true,
+}
+
+// sendAsState diffs send-as aliases keyed by address. The primary address is
+// never removed.
+type sendAsState struct {
+	svc    *gmail.Service
+	update []*gmail.SendAs
+	add    []*gmail.SendAs
+	remove []string
+}
+
+func (s *sendAsState) plan(ctx context.Context, desired json.RawMessage) ([]settingChange, error) {
+	var want []map[string]any
+	if err := json.Unmarshal(desired, &want); err != nil {
+		return nil, usagef("send-as aliases must be a JSON array: %v", err)
+	}
+	resp, err := s.svc.Users.Settings.SendAs.List("me").Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	live := map[string]map[string]any{}
+	primary := map[string]bool{}
+	for _, sa := range resp.SendAs {
+		value, err := toJSONValue(sa)
+		if err != nil {
+			return nil, err
+		}
+		key := strings.ToLower(sa.SendAsEmail)
+		live[key], _ = value.(map[string]any)
+		primary[key] = sa.IsPrimary
+	}
+
+	var changes []settingChange
+	wanted := map[string]bool{}
+	for i, d := range want {
+		email, _ := d["sendAsEmail"].(string)
+		email = strings.TrimSpace(email)
+		if email == "" {
+			return nil, usagef("send-as alias %d: missing sendAsEmail", i+1)
+		}
+		key := strings.ToLower(email)
+		if wanted[key] {
+			return nil, usagef("send-as alias %s listed twice", email)
+		}
+		wanted[key] = true
+
+		path := "sendAs[" + email + "]"
+		current, ok := live[key]
+		if !ok {
+			var sa gmail.SendAs
+			if err := convertSettingValue(d, &sa); err != nil {
+				return nil, usagef("send-as alias %s: %v", email, err)
+			}
+			s.add = append(s.add, &sa)
+			changes = append(changes, settingChange{Op: settingAdd, Path: path, To: d})
+			continue
+		}
+		diff := diffSettingValues(path, current, d, sendAsReadOnly, nil)
+		if len(diff) == 0 {
+			continue
+		}
+		changes = append(changes, diff...)
+		var sa gmail.SendAs
+		if err := convertSettingValue(mergeSettingValues(current, d, sendAsReadOnly), &sa); err != nil {
+			return nil, usagef("send-as alias %s: %v", email, err)
+		}
+		s.update = append(s.update, &sa)
+	}
+
+	keys := make([]string, 0, len(live))
+	for key := range live {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if wanted[key] || primary[key] {
+			continue
+		}
+		email, _ := live[key]["sendAsEmail"].(string)
+		s.remove = append(s.remove, email)
+		changes = append(changes, settingChange{Op: settingRemove, Path: "sendAs[" + email + "]", From: live[key]})
+	}
+	return changes, nil
+}
+
+func (s *sendAsState) apply(ctx context.Context) error {
+	for _, sa := range s.update {
+		if _, err := s.svc.Users.Settings.SendAs.Update("me", sa.SendAsEmail, sa).Context(ctx).Do(); err != nil {
+			return fmt.Errorf("update %s: %w", sa.SendAsEmail, err)
+		}
+	}
+	for _, sa := range s.add {
+		if _, err := s.svc.Users.Settings.SendAs.Create("me", sa).Context(ctx).Do(); err != nil {
+			return fmt.Errorf("create %s: %w", sa.SendAsEmail, err)
+		}
+	}
+	for _, email := range s.remove {
+		if err := s.svc.Users.Settings.SendAs.Delete("me", email).Context(ctx).Do(); err != nil {
+			return fmt.Errorf("delete %s: %w", email, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,406 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/events"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// snoozeLabelName is the managed label holding snoozed messages while they
+// are out of the inbox. Gmail's own snooze is not exposed through the API.
+const snoozeLabelName = "Snoozed"
+
+// snoozeWakeHour is when date-only --until values (tomorrow, monday,
+// 2026-11-02) wake up, matching Gmail's "8:00 AM" snooze presets.
+const snoozeWakeHour = 8
+
+var snoozeDaysRe = regexp.MustCompile(`^(\d+)([dw])$`)
+
+type GmailMessagesSnoozeCmd struct {
+	MessageIDs []string `arg:"" name:"messageId" help:"Message IDs"`
+	Until      string   `name:"until" help:"Wake time: duration (2h, 3d, 1w), RFC3339, YYYY-MM-DD[ HH:MM], today, tomorrow, monday (required)"`
+}
+
+type GmailSnoozeCmd struct {
+	List    GmailSnoozeListCmd    `cmd:"" name:"list" aliases:"ls" help:"List snoozed messages"`
+	Process GmailSnoozeProcessCmd `cmd:"" name:"process" help:"Return due messages to the inbox as unread (run from cron)"`
+	Cancel  GmailSnoozeCancelCmd  `cmd:"" name:"cancel" aliases:"wake" help:"Return snoozed messages to the inbox now"`
+}
+
+type snoozeEntry struct {
+	Account   string `json:"account"`
+	MessageID string `json:"messageId"`
+	ThreadID  string `json:"threadId,omitempty"`
+	Until     string `json:"until"`
+	CreatedAt string `json:"createdAt"`
+}
+
+func (e snoozeEntry) until() time.Time {
+	t, _ := time.Parse(time.RFC3339, e.Until)
+	return t
+}
+
+// snoozeStore records wake times for snoozed messages across accounts. It
+// lives in the local state dir.
+type snoozeStore struct {
+	path    string
+	Entries []snoozeEntry `json:"entries"`
+}
+
+func loadSnoozeStore() (*snoozeStore, error) {
+	path, err := config.GmailSnoozePath()
+	if err != nil {
+		return nil, err
+	}
+	store := &snoozeStore{path: path}
+	data, err := os.ReadFile(path) //nolint:gosec // path under config dir
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return store, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("parse snooze store: %w", err)
+	}
+	return store, nil
+}
+
+func (s *snoozeStore) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("ensure state dir: %w", err)
+	}
+	sort.SliceStable(s.Entries, func(i, j int) bool {
+		return s.Entries[i].Until < s.Entries[j].Until
+	})
+	payload, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, append(payload, '\n'), 0o600)
+}
+
+func (s *snoozeStore) put(entry snoozeEntry) {
+	s.remove(entry.Account, entry.MessageID)
+	s.Entries = append(s.Entries, entry)
+}
+
+func (s *snoozeStore) remove(account, messageID string) bool {
+	kept := s.Entries[:0]
+	removed := false
+	for _, e := range s.Entries {
+		if strings.EqualFold(e.Account, account) && e.MessageID == messageID {
+			removed = true
+			continue
+		}
+		kept = append(kept, e)
+	}
+	s.Entries = kept
+	return removed
+}
+
+func (s *snoozeStore) forAccount(account string) []snoozeEntry {
+	var out []snoozeEntry
+	for _, e := range s.Entries {
+		if strings.EqualFold(e.Account, account) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// parseSnoozeUntil accepts a duration from now (2h, 3d, 1w) or any time
+// expression understood by parseTimeExpr. Bare dates wake at 8:00 local.
+func parseSnoozeUntil(raw string, now time.Time) (time.Time, error) {
+	return parseFutureTime(raw, now, "--until", snoozeWakeHour)
+}
+
+// parseFutureTime parses a duration or time expression for flag that must
+// lie in the future. Bare dates resolve to dateHour:00 local time.
+func parseFutureTime(raw string, now time.Time, flag string, dateHour int) (time.Time, error) {
+	s := strings.ToLower(strings.TrimSpace(raw))
+	if s == "" {
+		return time.Time{}, usage("required: " + flag)
+	}
+	if m := snoozeDaysRe.FindStringSubmatch(s); m != nil {
+		days, _ := strconv.Atoi(m[1])
+		if m[2] == "w" {
+			days *= 7
+		}
+		if days > 0 {
+			return now.AddDate(0, 0, days), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return now.Add(d), nil
+	}
+	t, err := parseTimeExpr(raw, now, now.Location())
+	if err != nil {
+		return time.Time{}, usagef("invalid %s %q (use e.g. 2h, 3d, tomorrow, 2026-11-02 09:00)", flag, raw)
+	}
+	if t.Equal(startOfDay(t)) && !strings.ContainsAny(raw, ":T") && s != "now" {
+		t = t.Add(time.Duration(dateHour) * time.Hour)
+	}
+	// "friday" said on a Friday evening means next week's.
+	if _, ok := parseWeekday(s, now); ok && !t.After(now) {
+		t = t.AddDate(0, 0, 7)
+	}
+	if !t.After(now) {
+		return time.Time{}, usagef("%s %q is in the past", flag, raw)
+	}
+	return t, nil
+}
+
+func (c *GmailMessagesSnoozeCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	ids := make([]string, 0, len(c.MessageIDs))
+	for _, id := range c.MessageIDs {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return usage("missing messageId")
+	}
+	now := time.Now()
+	until, err := parseSnoozeUntil(c.Until, now)
+	if err != nil {
+		return err
+	}
+
+	svc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+	labelID, err := ensureLabelID(ctx, svc, snoozeLabelName)
+	if err != nil {
+		return err
+	}
+	store, err := loadSnoozeStore()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		msg, modErr := svc.Users.Messages.Modify("me", id, &gmail.ModifyMessageRequest{
+			AddLabelIds:    []string{labelID},
+			RemoveLabelIds: []string{"INBOX"},
+		}).Context(ctx).Do()
+		if modErr != nil {
+			// Remember what was already archived so it still wakes up.
+			_ = store.save()
+			return fmt.Errorf("snooze %s: %w", id, modErr)
+		}
+		store.put(snoozeEntry{
+			Account:   strings.ToLower(account),
+			MessageID: id,
+			ThreadID:  msg.ThreadId,
+			Until:     until.UTC().Format(time.RFC3339),
+			CreatedAt: now.UTC().Format(time.RFC3339),
+		})
+	}
+	if err := store.save(); err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"snoozed": ids,
+			"count":   len(ids),
+			"until":   until.Format(time.RFC3339),
+			"label":   snoozeLabelName,
+		})
+	}
+	u.Out().Printf("snoozed\t%d", len(ids))
+	u.Out().Printf("until\t%s", until.Local().Format("2006-01-02 15:04 MST"))
+	u.Err().Println("Run `gog gmail snooze process` periodically (e.g. from cron) to wake due messages")
+	return nil
+}
+
+type GmailSnoozeListCmd struct {
+	All bool `name:"all" help:"List snoozes for every account, not just the current one"`
+}
+
+func (c *GmailSnoozeListCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	store, err := loadSnoozeStore()
+	if err != nil {
+		return err
+	}
+	entries := store.Entries
+	if !c.All {
+		account, accountErr := requireAccount(flags)
+		if accountErr != nil {
+			return accountErr
+		}
+		entries = store.forAccount(account)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Until < entries[j].Until })
+
+	if outfmt.IsJSON(ctx) {
+		if entries == nil {
+			entries = []snoozeEntry{}
+		}
+		return outfmt.WriteJSON(os.Stdout, map[string]any{"snoozes": entries})
+	}
+	if len(entries) == 0 {
+		u.Err().Println("No snoozed messages")
+		return nil
+	}
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "UNTIL\tMESSAGE\tTHREAD\tACCOUNT")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.until().Local().Format("2006-01-02 15:04"), e.MessageID, e.ThreadID, e.Account)
+	}
+	return nil
+}
+
+type GmailSnoozeProcessCmd struct {
+	DryRun bool `name:"dry-run" help:"Show which messages are due without changing them"`
+}
+
+func (c *GmailSnoozeProcessCmd) Run(ctx context.Context, flags *RootFlags) error {
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	store, err := loadSnoozeStore()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	var due []string
+	for _, e := range store.forAccount(account) {
+		if !e.until().After(now) {
+			due = append(due, e.MessageID)
+		}
+	}
+	if c.DryRun {
+		return writeSnoozeWakeResult(ctx, due, nil, true)
+	}
+	return wakeSnoozed(ctx, account, store, due)
+}
+
+type GmailSnoozeCancelCmd struct {
+	MessageIDs []string `arg:"" name:"messageId" help:"Message IDs"`
+}
+
+func (c *GmailSnoozeCancelCmd) Run(ctx context.Context, flags *RootFlags) error {
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	store, err := loadSnoozeStore()
+	if err != nil {
+		return err
+	}
+	known := map[string]bool{}
+	for _, e := range store.forAccount(account) {
+		known[e.MessageID] = true
+	}
+	ids := make([]string, 0, len(c.MessageIDs))
+	for _, id := range c.MessageIDs {
+		id = strings.TrimSpace(id)
+		if !known[id] {
+			return usagef("message %q is not snoozed", id)
+		}
+		ids = append(ids, id)
+	}
+	return wakeSnoozed(ctx, account, store, ids)
+}
+
+// wakeSnoozed moves messages back to the inbox as unread, drops the managed
+// label, and forgets them. Messages deleted in the meantime are forgotten too.
+func wakeSnoozed(ctx context.Context, account string, store *snoozeStore, ids []string) error {
+	if len(ids) == 0 {
+		return writeSnoozeWakeResult(ctx, nil, nil, false)
+	}
+	svc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+	nameToID, err := fetchLabelNameToID(svc)
+	if err != nil {
+		return err
+	}
+	var remove []string
+	if id, ok := nameToID[strings.ToLower(snoozeLabelName)]; ok {
+		remove = []string{id}
+	}
+
+	var woken, missing []string
+	for _, id := range ids {
+		_, modErr := svc.Users.Messages.Modify("me", id, &gmail.ModifyMessageRequest{
+			AddLabelIds:    []string{"INBOX", "UNREAD"},
+			RemoveLabelIds: remove,
+		}).Context(ctx).Do()
+		switch {
+		case modErr == nil:
+			woken = append(woken, id)
+		case isNotFoundAPIError(modErr):
+			missing = append(missing, id)
+		default:
+			// Keep what already woke; the failed one is retried next run.
+			_ = store.save()
+			return fmt.Errorf("wake %s: %w", id, modErr)
+		}
+		store.remove(account, id)
+	}
+	if err := store.save(); err != nil {
+		return err
+	}
+	if emitErr := events.Emit(events.TypeJobFinished, account, map[string]any{
+		"job":     "gmail.snooze.wake",
+		"woken":   woken,
+		"missing": missing,
+	}); emitErr != nil {
+		ui.FromContext(ctx).Err().Printf("event log: %v", emitErr)
+	}
+	return writeSnoozeWakeResult(ctx, woken, missing, false)
+}
+
+func writeSnoozeWakeResult(ctx context.Context, woken, missing []string, dryRun bool) error {
+	if outfmt.IsJSON(ctx) {
+		if woken == nil {
+			woken = []string{}
+		}
+		out := map[string]any{"woken": woken, "count": len(woken), "missing": missing}
+		if dryRun {
+			out = map[string]any{"due": woken, "count": len(woken), "dryRun": true}
+		}
+		return outfmt.WriteJSON(os.Stdout, out)
+	}
+	u := ui.FromContext(ctx)
+	key := "woken"
+	if dryRun {
+		key = "due"
+	}
+	u.Out().Printf("%s\t%d", key, len(woken))
+	for _, id := range woken {
+		u.Out().Printf("id\t%s", id)
+	}
+	if len(missing) > 0 {
+		u.Err().Printf("Forgot %d deleted message(s): %s", len(missing), strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSnoozeUntil(t *testing.T) {
+	now := time.Date(2026, 10, 16, 15, 30, 0, 0, time.UTC) // Friday
+	cases := map[string]time.Time{
+		"2h":               now.Add(2 * time.Hour),
+		"3d":               now.AddDate(0, 0, 3),
+		"1w":               now.AddDate(0, 0, 7),
+		"tomorrow":         time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC),
+		"monday":           time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC),
+		"friday":           time.Date(2026, 10, 23, 8, 0, 0, 0, time.UTC),
+		"2026-11-02 09:15": time.Date(2026, 11, 2, 9, 15, 0, 0, time.UTC),
+	}
+	for in, want := range cases {
+		got, err := parseSnoozeUntil(in, now)
+		if err != nil {
+			t.Fatalf("parseSnoozeUntil(%q): %v", in, err)
+		}
+		if !got.Equal(want) {
+			t.Fatalf("parseSnoozeUntil(%q)=%v want %v", in, got, want)
+		}
+	}
+	for _, in := range []string{"", "yesterday", "later-ish"} {
+		if _, err := parseSnoozeUntil(in, now); err == nil || ExitCode(err) != 2 {
+			t.Fatalf("parseSnoozeUntil(%q): expected usage error, got %v", in, err)
+		}
+	}
+}
+
+func TestGmailSnooze_SnoozeAndProcess(t *testing.T) {
+	const account = "snooze@example.com"
+	t.Cleanup(func() {
+		store, err := loadSnoozeStore()
+		if err == nil {
+			store.remove(account, "m1")
+			store.remove(account, "m2")
+			_ = store.save()
+		}
+	})
+
+	type modifyReq struct {
+		AddLabelIds    []string `json:"addLabelIds"`
+		RemoveLabelIds []string `json:"removeLabelIds"`
+	}
+	modified := map[string][]modifyReq{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/gmail/v1")
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && path == "/users/me/labels":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"labels": []map[string]any{{"id": "INBOX", "name": "INBOX"}, {"id": "Label_7", "name": "Snoozed"}},
+			})
+		case r.Method == http.MethodPost && strings.HasSuffix(path, "/modify"):
+			id := strings.TrimSuffix(strings.TrimPrefix(path, "/users/me/messages/"), "/modify")
+			if id == "m2" {
+				w.WriteHeader(http.StatusNotFound)
+				_ = json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"code": 404, "message": "not found"}})
+				return
+			}
+			var req modifyReq
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			modified[id] = append(modified[id], req)
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": id, "threadId": "t-" + id})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	stubGmailService(t, srv)
+
+	_ = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", account, "gmail", "messages", "snooze", "m1", "--until", "2h"}); err != nil {
+				t.Fatalf("snooze: %v", err)
+			}
+		})
+	})
+	if got := modified["m1"]; len(got) != 1 || got[0].AddLabelIds[0] != "Label_7" || got[0].RemoveLabelIds[0] != "INBOX" {
+		t.Fatalf("unexpected snooze modify: %#v", got)
+	}
+
+	// Not due yet: process leaves it alone.
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--json", "--account", account, "gmail", "snooze", "process"}); err != nil {
+			t.Fatalf("process: %v", err)
+		}
+	})
+	if !strings.Contains(out, `"count": 0`) {
+		t.Fatalf("expected nothing due: %q", out)
+	}
+
+	// Backdate the entry and add one for a message that was since deleted.
+	store, err := loadSnoozeStore()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	past := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	for i := range store.Entries {
+		if store.Entries[i].Account == account {
+			store.Entries[i].Until = past
+		}
+	}
+	store.put(snoozeEntry{Account: account, MessageID: "m2", Until: past})
+	if err := store.save(); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	out = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", account, "gmail", "snooze", "process"}); err != nil {
+				t.Fatalf("process: %v", err)
+			}
+		})
+	})
+	var parsed struct {
+		Woken   []string `json:"woken"`
+		Missing []string `json:"missing"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json parse: %v\nout=%q", err, out)
+	}
+	if len(parsed.Woken) != 1 || parsed.Woken[0] != "m1" || len(parsed.Missing) != 1 || parsed.Missing[0] != "m2" {
+		t.Fatalf("unexpected process result: %#v", parsed)
+	}
+	wake := modified["m1"][1]
+	if strings.Join(wake.AddLabelIds, ",") != "INBOX,UNREAD" || wake.RemoveLabelIds[0] != "Label_7" {
+		t.Fatalf("unexpected wake modify: %#v", wake)
+	}
+	store, _ = loadSnoozeStore()
+	if len(store.forAccount(account)) != 0 {
+		t.Fatalf("expected store to be empty: %#v", store.Entries)
+	}
+}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/steipete/gogcli/internal/config"
 	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/summarizer"
 	"github.com/steipete/gogcli/internal/ui"
 )
 
@@ -50,12 +51,15 @@ type GmailThreadCmd struct {
 	Get         GmailThreadGetCmd         `cmd:"" name:"get" default:"withargs" help:"Get a thread with all messages (optionally download attachments)"`
 	Modify      GmailThreadModifyCmd      `cmd:"" name:"modify" help:"Modify labels on all messages in a thread"`
 	Attachments GmailThreadAttachmentsCmd `cmd:"" name:"attachments" help:"List all attachments in a thread"`
+	Split       GmailThreadSplitCmd       `cmd:"" name:"split" help:"Move messages out of a thread into a new thread (re-import)"`
+	Join        GmailThreadJoinCmd        `cmd:"" name:"join" help:"Move messages of other threads into a thread (re-import)"`
 }
 
 type GmailThreadGetCmd struct {
 	ThreadID  string        `arg:"" name:"threadId" help:"Thread ID"`
 	Download  bool          `name:"download" help:"Download attachments"`
 	Full      bool          `name:"full" help:"Show full message bodies"`
+	SummaryAI bool          `name:"summary-ai" help:"Summarize the thread with the summarizer hook from config.json"`
 	OutputDir OutputDirFlag `embed:""`
 }
 
@@ -80,6 +84,14 @@ func (c *GmailThreadGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
+	summary := ""
+	if c.SummaryAI && thread != nil && len(thread.Messages) > 0 {
+		summary, err = runSummarizer(ctx, account, summarizer.TaskSummarize, summarizeThreadInstructions, thread.Messages)
+		if err != nil {
+			return err
+		}
+	}
+
 	var attachDir string
 	if c.Download {
 		if strings.TrimSpace(c.OutputDir.Dir) == "" {
@@ -108,10 +120,14 @@ func (c *GmailThreadGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 				downloadedFiles = append(downloadedFiles, attachmentDownloadSummaries(downloads)...)
 			}
 		}
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		out := map[string]any{
 			"thread":     thread,
 			"downloaded": downloadedFiles,
-		})
+		}
+		if summary != "" {
+			out["summary"] = summary
+		}
+		return outfmt.WriteJSON(os.Stdout, out)
 	}
 	if thread == nil || len(thread.Messages) == 0 {
 		u.Err().Println("Empty thread")
@@ -121,6 +137,11 @@ func (c *GmailThreadGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 	// Show message count upfront so users know how many messages to expect
 	u.Out().Printf("Thread contains %d message(s)", len(thread.Messages))
 	u.Out().Println("")
+	if summary != "" {
+		u.Out().Println("=== Summary ===")
+		u.Out().Println(summary)
+		u.Out().Println("")
+	}
 
 	for i, msg := range thread.Messages {
 		if msg == nil {
@@ -174,8 +195,8 @@ func (c *GmailThreadGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 
 type GmailThreadModifyCmd struct {
 	ThreadID string `arg:"" name:"threadId" help:"Thread ID"`
-	Add      string `name:"add" help:"Labels to add (comma-separated, name or ID)"`
-	Remove   string `name:"remove" help:"Labels to remove (comma-separated, name or ID)"`
+	Add      string `name:"add" complete:"labels" help:"Labels to add (comma-separated, name or ID)"`
+	Remove   string `name:"remove" complete:"labels" help:"Labels to remove (comma-separated, name or ID)"`
 }
 
 func (c *GmailThreadModifyCmd) Run(ctx context.Context, flags *RootFlags) error {
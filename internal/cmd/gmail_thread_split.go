@@ -0,0 +1,392 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"google.golang.org/api/gmail/v1"
+	gapi "google.golang.org/api/googleapi"
+
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// Gmail only threads an imported message into an existing thread when its
+// References/In-Reply-To point into the thread and the subjects match, so
+// split and join re-import messages with rewritten headers. The originals are
+// trashed (recoverable for 30 days) unless --keep-original is set.
+
+type GmailThreadSplitCmd struct {
+	ThreadID     string   `arg:"" name:"threadId" help:"Thread ID"`
+	Messages     []string `name:"message" help:"Message ID to move into the new thread (repeatable or comma-separated)" sep:","`
+	KeepOriginal bool     `name:"keep-original" help:"Do not trash the original messages"`
+	DryRun       bool     `name:"dry-run" help:"Show what would be re-imported"`
+}
+
+type GmailThreadJoinCmd struct {
+	ThreadID     string   `arg:"" name:"threadId" help:"Thread to join into"`
+	Others       []string `arg:"" name:"otherThreadId" help:"Threads whose messages move into threadId"`
+	KeepOriginal bool     `name:"keep-original" help:"Do not trash the original messages"`
+	DryRun       bool     `name:"dry-run" help:"Show what would be re-imported"`
+}
+
+type threadMove struct {
+	From       string `json:"from"`
+	To         string `json:"to,omitempty"`
+	Subject    string `json:"subject,omitempty"`
+	NewSubject string `json:"newSubject,omitempty"`
+}
+
+func (c *GmailThreadSplitCmd) Run(ctx context.Context, flags *RootFlags) error {
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	threadID := strings.TrimSpace(c.ThreadID)
+	if threadID == "" {
+		return usage("empty threadId")
+	}
+	selected := map[string]bool{}
+	for _, id := range c.Messages {
+		if id = strings.TrimSpace(id); id != "" {
+			selected[id] = true
+		}
+	}
+	if len(selected) == 0 {
+		return usage("required: --message")
+	}
+
+	svc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+	thread, err := fetchThreadHeaders(ctx, svc, threadID)
+	if err != nil {
+		return err
+	}
+	var moving []*gmail.Message
+	for _, m := range thread.Messages {
+		if selected[m.Id] {
+			moving = append(moving, m)
+			delete(selected, m.Id)
+		}
+	}
+	if len(selected) > 0 {
+		return usagef("not in thread %s: %s", threadID, strings.Join(sortedSetKeys(selected), ", "))
+	}
+	if len(moving) == len(thread.Messages) {
+		return usage("--message selects every message in the thread; nothing to split off")
+	}
+
+	plan := make([]threadMove, 0, len(moving))
+	for _, m := range moving {
+		plan = append(plan, threadMove{From: m.Id, Subject: headerValue(m.Payload, "Subject")})
+	}
+	if c.DryRun {
+		return writeThreadMoves(ctx, "split", threadID, "", plan, true)
+	}
+	if err := confirmDestructive(ctx, flags, fmt.Sprintf("re-import %d message(s) from thread %s into a new thread", len(moving), threadID)); err != nil {
+		return err
+	}
+
+	// The first moved message starts the new thread; the rest reply to it.
+	var (
+		newThreadID string
+		chain       []string
+	)
+	for i, m := range moving {
+		raw, err := fetchRawMessageBytes(ctx, svc, m.Id)
+		if err != nil {
+			return err
+		}
+		// The copy gets its own Message-ID: reusing the original's would let
+		// Gmail (and other clients) thread it right back into the old thread.
+		msgID, err := randomMessageID(headerValue(m.Payload, "From"))
+		if err != nil {
+			return err
+		}
+		edits := map[string]string{"Message-ID": msgID, "References": "", "In-Reply-To": ""}
+		if len(chain) > 0 {
+			edits["References"] = strings.Join(chain, " ")
+			edits["In-Reply-To"] = chain[len(chain)-1]
+		}
+		imported, err := importRewrittenMessage(ctx, svc, rewriteRawHeaders(raw, edits), m.LabelIds, newThreadID)
+		if err != nil {
+			return fmt.Errorf("import %s: %w", m.Id, err)
+		}
+		if newThreadID == "" {
+			newThreadID = imported.ThreadId
+		}
+		chain = append(chain, msgID)
+		plan[i].To = imported.Id
+	}
+
+	if !c.KeepOriginal {
+		if err := trashMessages(ctx, svc, plan); err != nil {
+			return err
+		}
+	}
+	return writeThreadMoves(ctx, "split", threadID, newThreadID, plan, false)
+}
+
+func (c *GmailThreadJoinCmd) Run(ctx context.Context, flags *RootFlags) error {
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	targetID := strings.TrimSpace(c.ThreadID)
+	if targetID == "" {
+		return usage("empty threadId")
+	}
+	var others []string
+	for _, id := range c.Others {
+		if id = strings.TrimSpace(id); id != "" && id != targetID {
+			others = append(others, id)
+		}
+	}
+	if len(others) == 0 {
+		return usage("need at least one other threadId to join")
+	}
+
+	svc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+	target, err := fetchThreadHeaders(ctx, svc, targetID)
+	if err != nil {
+		return err
+	}
+	if len(target.Messages) == 0 {
+		return fmt.Errorf("thread %s has no messages", targetID)
+	}
+	first, last := target.Messages[0], target.Messages[len(target.Messages)-1]
+	targetSubject := baseSubject(headerValue(first.Payload, "Subject"))
+
+	var moving []*gmail.Message
+	for _, id := range others {
+		t, err := fetchThreadHeaders(ctx, svc, id)
+		if err != nil {
+			return err
+		}
+		moving = append(moving, t.Messages...)
+	}
+	sort.SliceStable(moving, func(i, j int) bool { return moving[i].InternalDate < moving[j].InternalDate })
+
+	plan := make([]threadMove, 0, len(moving))
+	for _, m := range moving {
+		move := threadMove{From: m.Id, Subject: headerValue(m.Payload, "Subject")}
+		if !strings.EqualFold(baseSubject(move.Subject), targetSubject) {
+			move.NewSubject = "Re: " + targetSubject
+		}
+		plan = append(plan, move)
+	}
+	if c.DryRun {
+		return writeThreadMoves(ctx, "join", targetID, targetID, plan, true)
+	}
+	if err := confirmDestructive(ctx, flags, fmt.Sprintf("re-import %d message(s) into thread %s", len(moving), targetID)); err != nil {
+		return err
+	}
+
+	chain := strings.Fields(headerValue(last.Payload, "References"))
+	if id := headerValue(last.Payload, "Message-ID"); id != "" {
+		chain = append(chain, id)
+	}
+	for i, m := range moving {
+		raw, err := fetchRawMessageBytes(ctx, svc, m.Id)
+		if err != nil {
+			return err
+		}
+		edits := map[string]string{}
+		if len(chain) > 0 {
+			edits["References"] = strings.Join(chain, " ")
+			edits["In-Reply-To"] = chain[len(chain)-1]
+		}
+		if plan[i].NewSubject != "" {
+			edits["Subject"] = plan[i].NewSubject
+		}
+		msgID := headerValue(m.Payload, "Message-ID")
+		if msgID == "" {
+			msgID = fmt.Sprintf("<%s.join@gogcli.local>", m.Id)
+			edits["Message-ID"] = msgID
+		}
+		imported, err := importRewrittenMessage(ctx, svc, rewriteRawHeaders(raw, edits), m.LabelIds, targetID)
+		if err != nil {
+			return fmt.Errorf("import %s: %w", m.Id, err)
+		}
+		chain = append(chain, msgID)
+		plan[i].To = imported.Id
+	}
+
+	if !c.KeepOriginal {
+		if err := trashMessages(ctx, svc, plan); err != nil {
+			return err
+		}
+	}
+	return writeThreadMoves(ctx, "join", targetID, targetID, plan, false)
+}
+
+func fetchThreadHeaders(ctx context.Context, svc *gmail.Service, threadID string) (*gmail.Thread, error) {
+	thread, err := svc.Users.Threads.Get("me", threadID).
+		Format(gmailFormatMetadata).
+		MetadataHeaders("From", "Subject", "Message-ID", "References", "In-Reply-To").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(thread.Messages, func(i, j int) bool {
+		return thread.Messages[i].InternalDate < thread.Messages[j].InternalDate
+	})
+	return thread, nil
+}
+
+func fetchRawMessageBytes(ctx context.Context, svc *gmail.Service, id string) ([]byte, error) {
+	msg, err := svc.Users.Messages.Get("me", id).Format(gmailFormatRaw).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("message %s: %w", id, err)
+	}
+	raw, err := decodeBase64URLBytes(msg.Raw)
+	if err != nil {
+		return nil, fmt.Errorf("message %s: decode raw: %w", id, err)
+	}
+	return raw, nil
+}
+
+// Labels Gmail refuses on import.
+var importSkipLabels = map[string]bool{"DRAFT": true, "CHAT": true}
+
+func importRewrittenMessage(ctx context.Context, svc *gmail.Service, raw []byte, labelIDs []string, threadID string) (*gmail.Message, error) {
+	labels := make([]string, 0, len(labelIDs))
+	for _, l := range labelIDs {
+		if !importSkipLabels[l] {
+			labels = append(labels, l)
+		}
+	}
+	return svc.Users.Messages.Import("me", &gmail.Message{ThreadId: threadID, LabelIds: labels}).
+		InternalDateSource("dateHeader").
+		NeverMarkSpam(true).
+		Media(bytes.NewReader(raw), gapi.ContentType("message/rfc822")).
+		Context(ctx).
+		Do()
+}
+
+func trashMessages(ctx context.Context, svc *gmail.Service, moves []threadMove) error {
+	for _, m := range moves {
+		if _, err := svc.Users.Messages.Trash("me", m.From).Context(ctx).Do(); err != nil {
+			return fmt.Errorf("trash original %s (copy is %s): %w", m.From, m.To, err)
+		}
+	}
+	return nil
+}
+
+func writeThreadMoves(ctx context.Context, op, threadID, newThreadID string, moves []threadMove, dryRun bool) error {
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"operation":   op,
+			"threadId":    threadID,
+			"newThreadId": newThreadID,
+			"messages":    moves,
+			"dryRun":      dryRun,
+		})
+	}
+	u := ui.FromContext(ctx)
+	if !dryRun && newThreadID != "" {
+		u.Out().Printf("thread\t%s", newThreadID)
+	}
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "FROM\tTO\tSUBJECT")
+	for _, m := range moves {
+		subject := m.Subject
+		if m.NewSubject != "" {
+			subject = m.Subject + " -> " + m.NewSubject
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", m.From, orEmpty(m.To, "-"), sanitizeTab(subject))
+	}
+	return nil
+}
+
+var subjectPrefixRe = regexp.MustCompile(`(?i)^\s*((re|fw|fwd|aw|sv|wg)\s*(\[\d+\])?\s*:\s*)+`)
+
+// baseSubject drops reply/forward prefixes, which Gmail ignores when
+// matching subjects for threading.
+func baseSubject(s string) string {
+	return strings.TrimSpace(subjectPrefixRe.ReplaceAllString(s, ""))
+}
+
+// rewriteRawHeaders sets or (with an empty value) removes headers in the
+// message's header block, keeping everything else byte for byte.
+func rewriteRawHeaders(raw []byte, edits map[string]string) []byte {
+	nl := []byte("\n")
+	if bytes.Contains(raw, []byte("\r\n")) {
+		nl = []byte("\r\n")
+	}
+	sep := append(append([]byte{}, nl...), nl...)
+	end := bytes.Index(raw, sep)
+	var head, body []byte
+	if end < 0 {
+		head, body = raw, nil
+	} else {
+		head, body = raw[:end], raw[end:]
+	}
+
+	done := map[string]bool{}
+	var out [][]byte
+	skipping := false
+	for _, line := range bytes.Split(head, nl) {
+		if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') {
+			if !skipping {
+				out = append(out, line)
+			}
+			continue
+		}
+		skipping = false
+		name, _, ok := bytes.Cut(line, []byte(":"))
+		if !ok {
+			out = append(out, line)
+			continue
+		}
+		for key, value := range edits {
+			if !strings.EqualFold(strings.TrimSpace(string(name)), key) {
+				continue
+			}
+			skipping = true
+			if value != "" && !done[key] {
+				out = append(out, []byte(key+": "+value))
+			}
+			done[key] = true
+		}
+		if !skipping {
+			out = append(out, line)
+		}
+	}
+	for _, key := range sortedKeysOf(edits) {
+		if edits[key] != "" && !done[key] {
+			out = append(out, []byte(key+": "+edits[key]))
+		}
+	}
+	return append(bytes.Join(out, nl), body...)
+}
+
+func sortedKeysOf(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedSetKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/mock"
+)
+
+func TestRewriteRawHeaders(t *testing.T) {
+	raw := "From: a@example.com\r\nReferences: <1@x>\r\n <2@x>\r\nSubject: Hi\r\n\r\nBody\r\nReferences: not a header\r\n"
+	got := string(rewriteRawHeaders([]byte(raw), map[string]string{
+		"References":  "",
+		"In-Reply-To": "<3@x>",
+		"Subject":     "Re: Other",
+	}))
+	want := "From: a@example.com\r\nSubject: Re: Other\r\nIn-Reply-To: <3@x>\r\n\r\nBody\r\nReferences: not a header\r\n"
+	if got != want {
+		t.Fatalf("rewriteRawHeaders:\n got %q\nwant %q", got, want)
+	}
+	if baseSubject("RE: Fwd: AW: Launch") != "Launch" {
+		t.Fatalf("baseSubject did not strip prefixes")
+	}
+}
+
+func newMockGmail(t *testing.T) *gmail.Service {
+	t.Helper()
+	s, err := mock.New(nil, mock.ServiceGmail)
+	if err != nil {
+		t.Fatalf("mock.New: %v", err)
+	}
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+	t.Setenv(googleapi.EnvAPIEndpoint, srv.URL)
+	svc, err := gmail.NewService(context.Background(), option.WithoutAuthentication(), option.WithEndpoint(srv.URL+"/"))
+	if err != nil {
+		t.Fatalf("gmail.NewService: %v", err)
+	}
+	return svc
+}
+
+func TestGmailThreadSplitAndJoin(t *testing.T) {
+	svc := newMockGmail(t)
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--force", "--account", "test@example.com", "gmail", "threads", "split", "18c0a1b2c3d4e501", "--message", "18c0a1b2c3d4e502"}); err != nil {
+				t.Fatalf("split: %v", err)
+			}
+		})
+	})
+	var split struct {
+		NewThreadID string       `json:"newThreadId"`
+		Messages    []threadMove `json:"messages"`
+	}
+	if err := json.Unmarshal([]byte(out), &split); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if split.NewThreadID == "" || split.NewThreadID == "18c0a1b2c3d4e501" || len(split.Messages) != 1 {
+		t.Fatalf("unexpected split result: %#v", split)
+	}
+	copyMsg, err := svc.Users.Messages.Get("me", split.Messages[0].To).Format("raw").Do()
+	if err != nil {
+		t.Fatalf("get copy: %v", err)
+	}
+	raw, _ := decodeBase64URLBytes(copyMsg.Raw)
+	if strings.Contains(string(raw), "In-Reply-To") || !hasString(copyMsg.LabelIds, "SENT") {
+		t.Fatalf("copy should drop threading headers and keep labels: %v\n%s", copyMsg.LabelIds, raw)
+	}
+	origHeaders, err := svc.Users.Messages.Get("me", "18c0a1b2c3d4e502").Format("metadata").MetadataHeaders("Message-ID").Do()
+	if err != nil {
+		t.Fatalf("get original: %v", err)
+	}
+	origID := headerValue(origHeaders.Payload, "Message-ID")
+	parsed, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("parse copy: %v", err)
+	}
+	copyID := parsed.Header.Get("Message-ID")
+	if copyID == "" || copyID == origID {
+		t.Fatalf("copy needs a fresh Message-ID, got %q (original %q)", copyID, origID)
+	}
+	orig, _ := svc.Users.Messages.Get("me", "18c0a1b2c3d4e502").Format("minimal").Do()
+	if !hasString(orig.LabelIds, "TRASH") {
+		t.Fatalf("original should be trashed: %v", orig.LabelIds)
+	}
+
+	out = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--force", "--account", "test@example.com", "gmail", "thread", "join", "18c0a1b2c3d4e504", "18c0a1b2c3d4e503", "--keep-original"}); err != nil {
+				t.Fatalf("join: %v", err)
+			}
+		})
+	})
+	var join struct {
+		Messages []threadMove `json:"messages"`
+	}
+	if err := json.Unmarshal([]byte(out), &join); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if len(join.Messages) != 1 || join.Messages[0].NewSubject != "Re: Launch checklist" {
+		t.Fatalf("unexpected join result: %#v", join.Messages)
+	}
+	thread, err := svc.Users.Threads.Get("me", "18c0a1b2c3d4e504").Do()
+	if err != nil || len(thread.Messages) != 2 {
+		t.Fatalf("joined thread: %v %d", err, len(thread.Messages))
+	}
+	if kept, _ := svc.Users.Messages.Get("me", "18c0a1b2c3d4e503").Format("minimal").Do(); hasString(kept.LabelIds, "TRASH") {
+		t.Fatalf("--keep-original should not trash")
+	}
+}
+
+func TestGmailThreadSplit_RejectsForeignMessage(t *testing.T) {
+	newMockGmail(t)
+	_ = captureStderr(t, func() {
+		err := Execute([]string{"--account", "test@example.com", "gmail", "thread", "split", "18c0a1b2c3d4e501", "--message", "18c0a1b2c3d4e503", "--dry-run"})
+		if err == nil || ExitCode(err) != 2 {
+			t.Fatalf("expected usage error, got %v", err)
+		}
+	})
+}
@@ -231,7 +231,7 @@ func TestGmailTrackOpens_JSON(t *testing.T) {
 	if parsed, err := parseTrackingSince("24h"); err != nil || parsed == "" {
 		t.Fatalf("unexpected parseTrackingSince duration result: %q err=%v", parsed, err)
 	}
-	if parsed, err := parseTrackingSince("2025-01-01"); err != nil || parsed == "" {
+	if parsed, err := parseTrackingSince("2025-01-01"); err != nil || parsed != "2025-01-01T00:00:00Z" {
 		t.Fatalf("unexpected parseTrackingSince date result: %q err=%v", parsed, err)
 	}
 }
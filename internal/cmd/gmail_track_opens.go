@@ -19,9 +19,9 @@ import (
 const trackingUnknown = "unknown"
 
 type GmailTrackOpensCmd struct {
-	TrackingID string `arg:"" optional:"" help:"Tracking ID from send command"`
-	To         string `name:"to" help:"Filter by recipient email"`
-	Since      string `name:"since" help:"Filter by time (e.g., '24h', '2024-01-01')"`
+	TrackingID string    `arg:"" optional:"" help:"Tracking ID from send command"`
+	To         string    `name:"to" help:"Filter by recipient email"`
+	Since      SinceTime `name:"since" help:"Filter by time (e.g., '24h', '7d', '2024-01-01')"`
 }
 
 func (c *GmailTrackOpensCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -124,8 +124,8 @@ func (c *GmailTrackOpensCmd) queryAdmin(ctx context.Context, cfg *tracking.Confi
 	if c.To != "" {
 		q.Set("recipient", c.To)
 	}
-	if c.Since != "" {
-		since, err := parseTrackingSince(c.Since)
+	if !c.Since.IsZero() {
+		since, err := parseTrackingSince(c.Since.String())
 		if err != nil {
 			return err
 		}
@@ -190,14 +190,16 @@ func (c *GmailTrackOpensCmd) queryAdmin(ctx context.Context, cfg *tracking.Confi
 	return nil
 }
 
+// parseTrackingSince formats --since for the tracking worker. Unlike other
+// --since flags, a bare date means UTC midnight rather than local midnight.
 func parseTrackingSince(s string) (string, error) {
 	s = strings.TrimSpace(s)
 	if s == "" {
 		return "", usage("empty --since")
 	}
 
-	if d, err := time.ParseDuration(s); err == nil {
-		return time.Now().Add(-d).UTC().Format(time.RFC3339), nil
+	if days, d, err := parseDayDuration(s); err == nil {
+		return time.Now().AddDate(0, 0, -days).Add(-d).UTC().Format(time.RFC3339), nil
 	}
 
 	if t, err := time.Parse("2006-01-02", s); err == nil {
@@ -211,5 +213,5 @@ func parseTrackingSince(s string) (string, error) {
 		return t.UTC().Format(time.RFC3339Nano), nil
 	}
 
-	return "", usagef("invalid --since %q (use duration like 24h, date YYYY-MM-DD, or RFC3339)", s)
+	return "", usagef("invalid --since %q (use duration like 24h or 7d, date YYYY-MM-DD, or RFC3339)", s)
 }
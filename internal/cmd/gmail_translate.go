@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/steipete/gogcli/internal/translate"
+)
+
+// newTranslator picks the translation provider: the shell command when one
+// is given, otherwise Google Cloud Translation (an API key in
+// GOG_TRANSLATE_API_KEY, or Application Default Credentials).
+var newTranslator = func(ctx context.Context, command string) (translate.Translator, error) {
+	if strings.TrimSpace(command) != "" {
+		return translate.NewCommand(command)
+	}
+	return translate.NewCloud(ctx, strings.TrimSpace(os.Getenv("GOG_TRANSLATE_API_KEY")))
+}
+
+func translateMessageBody(ctx context.Context, command, target, body string) (translate.Result, error) {
+	tr, err := newTranslator(ctx, command)
+	if err != nil {
+		return translate.Result{}, fmt.Errorf("translation provider: %w", err)
+	}
+	res, err := tr.Translate(ctx, body, target)
+	if err != nil {
+		return translate.Result{}, fmt.Errorf("translate: %w", err)
+	}
+	return res, nil
+}
+
+func translationHeading(res *translate.Result) string {
+	if res.SourceLanguage != "" {
+		return fmt.Sprintf("--- Translation (%s -> %s) ---", res.SourceLanguage, res.Language)
+	}
+	return fmt.Sprintf("--- Translation (%s) ---", res.Language)
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/translate"
+)
+
+type fakeTranslator struct{ got string }
+
+func (f *fakeTranslator) Translate(_ context.Context, text, target string) (translate.Result, error) {
+	f.got = text
+	return translate.Result{Text: "Hello world", Language: target, SourceLanguage: "de"}, nil
+}
+
+func TestGmailGetTranslate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": "m1", "threadId": "t1", "payload": map[string]any{
+			"mimeType": "text/plain",
+			"headers":  []map[string]string{{"name": "Subject", "value": "Gruß"}},
+			"body":     map[string]any{"data": base64.RawURLEncoding.EncodeToString([]byte("Hallo Welt"))},
+		}})
+	}))
+	defer srv.Close()
+	stubGmailService(t, srv)
+
+	fake := &fakeTranslator{}
+	var gotCommand string
+	orig := newTranslator
+	t.Cleanup(func() { newTranslator = orig })
+	newTranslator = func(_ context.Context, command string) (translate.Translator, error) {
+		gotCommand = command
+		return fake, nil
+	}
+
+	stdout := captureStdout(t, func() {
+		if err := Execute([]string{"--json", "--account", "me@example.com", "gmail", "get", "m1", "--translate", "en", "--translate-command", "trans -b"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	var out struct {
+		Body        string           `json:"body"`
+		Translation translate.Result `json:"translation"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("json: %v\n%s", err, stdout)
+	}
+	if out.Body != "Hallo Welt" || out.Translation.Text != "Hello world" || out.Translation.Language != "en" || out.Translation.SourceLanguage != "de" {
+		t.Fatalf("out = %+v", out)
+	}
+	if fake.got != "Hallo Welt" || gotCommand != "trans -b" {
+		t.Fatalf("translated %q with %q", fake.got, gotCommand)
+	}
+
+	text := captureStdout(t, func() {
+		if err := Execute([]string{"--account", "me@example.com", "gmail", "get", "m1", "--translate", "en"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	if !strings.Contains(text, "Hallo Welt\n\n--- Translation (de -> en) ---\nHello world") {
+		t.Fatalf("text output:\n%s", text)
+	}
+
+	if err := Execute([]string{"--account", "me@example.com", "gmail", "get", "m1", "--format", "metadata", "--translate", "en"}); ExitCode(err) != 2 {
+		t.Fatalf("metadata + translate: %v", err)
+	}
+}
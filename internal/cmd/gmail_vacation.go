@@ -18,7 +18,9 @@ type GmailVacationCmd struct {
 	Update GmailVacationUpdateCmd `cmd:"" name:"update" help:"Update vacation responder settings"`
 }
 
-type GmailVacationGetCmd struct{}
+type GmailVacationGetCmd struct {
+	SettingsDiffFlags `embed:""`
+}
 
 func (c *GmailVacationGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 	u := ui.FromContext(ctx)
@@ -32,6 +34,10 @@ func (c *GmailVacationGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
+	if c.active() {
+		return c.run(ctx, flags, "vacation settings", "vacation", &vacationState{svc: svc})
+	}
+
 	vacation, err := svc.Users.Settings.GetVacation("me").Do()
 	if err != nil {
 		return err
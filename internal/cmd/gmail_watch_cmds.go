@@ -34,7 +34,7 @@ type GmailWatchCmd struct {
 
 type GmailWatchStartCmd struct {
 	Topic       string   `name:"topic" help:"Pub/Sub topic (projects/.../topics/...)"`
-	Labels      []string `name:"label" help:"Label IDs or names (repeatable, comma-separated)"`
+	Labels      []string `name:"label" complete:"labels" help:"Label IDs or names (repeatable, comma-separated)"`
 	TTL         string   `name:"ttl" help:"Renew after duration (seconds or Go duration)"`
 	HookURL     string   `name:"hook-url" help:"Webhook URL to forward messages"`
 	HookToken   string   `name:"hook-token" help:"Webhook bearer token"`
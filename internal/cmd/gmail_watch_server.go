@@ -16,6 +16,8 @@ import (
 	"google.golang.org/api/gmail/v1"
 	"google.golang.org/api/googleapi"
 	"google.golang.org/api/idtoken"
+
+	"github.com/steipete/gogcli/internal/events"
 )
 
 var errNoNewMessages = errors.New("no new messages")
@@ -82,6 +84,7 @@ func (s *gmailWatchServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusAccepted)
 		return
 	}
+	s.emitReceived(result)
 
 	if s.cfg.HookURL == "" {
 		if s.cfg.AllowNoHook {
@@ -94,12 +97,35 @@ func (s *gmailWatchServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	if err := s.sendHook(r.Context(), result); err != nil {
 		s.warnf("watch: hook failed: %v", err)
+		s.emit(events.TypeGmailHookFailed, map[string]any{"messages": len(result.Messages), "error": err.Error()})
 		w.WriteHeader(http.StatusOK)
 		return
 	}
+	s.emit(events.TypeGmailHookDelivered, map[string]any{"messages": len(result.Messages)})
 	w.WriteHeader(http.StatusOK)
 }
 
+// emitReceived records one event per new message in the local event log
+// (see `gog events tail`).
+func (s *gmailWatchServer) emitReceived(result *gmailHookPayload) {
+	for _, m := range result.Messages {
+		s.emit(events.TypeGmailMessageReceived, map[string]any{
+			"id":        m.ID,
+			"threadId":  m.ThreadID,
+			"historyId": result.HistoryID,
+			"from":      m.From,
+			"subject":   m.Subject,
+			"labels":    m.Labels,
+		})
+	}
+}
+
+func (s *gmailWatchServer) emit(typ string, data map[string]any) {
+	if err := events.Emit(typ, s.cfg.Account, data); err != nil {
+		s.warnf("watch: event log: %v", err)
+	}
+}
+
 func (s *gmailWatchServer) authorize(r *http.Request) bool {
 	if s.cfg.VerifyOIDC {
 		bearer := bearerToken(r)
@@ -264,26 +290,39 @@ func (s *gmailWatchServer) resyncHistory(ctx context.Context, svc *gmail.Service
 }
 
 func (s *gmailWatchServer) fetchMessages(ctx context.Context, svc *gmail.Service, ids []string) ([]gmailHookMessage, error) {
-	messages := make([]gmailHookMessage, 0, len(ids))
 	format := gmailWatchFormatMetadata
 	if s.cfg.IncludeBody {
 		format = "full"
 	}
+	wanted := make([]string, 0, len(ids))
 	for _, id := range ids {
-		if strings.TrimSpace(id) == "" {
-			continue
+		if strings.TrimSpace(id) != "" {
+			wanted = append(wanted, id)
 		}
-		msg, err := svc.Users.Messages.Get("me", id).
+	}
+
+	fetched := make([]*gmail.Message, len(wanted))
+	err := fetchGmailConcurrently(ctx, len(wanted), gmailQuotaMessageGet, func(ctx context.Context, idx int) error {
+		msg, err := svc.Users.Messages.Get("me", wanted[idx]).
 			Format(format).
 			MetadataHeaders("From", "To", "Subject", "Date").
 			Context(ctx).
 			Do()
 		if err != nil {
 			if isNotFoundAPIError(err) {
-				continue
+				return nil
 			}
-			return nil, err
+			return err
 		}
+		fetched[idx] = msg
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]gmailHookMessage, 0, len(wanted))
+	for _, msg := range fetched {
 		if msg == nil {
 			continue
 		}
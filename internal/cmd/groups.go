@@ -24,8 +24,10 @@ const (
 )
 
 type GroupsCmd struct {
-	List    GroupsListCmd    `cmd:"" name:"list" help:"List groups you belong to"`
-	Members GroupsMembersCmd `cmd:"" name:"members" help:"List members of a group"`
+	List           GroupsListCmd           `cmd:"" name:"list" help:"List groups you belong to"`
+	Members        GroupsMembersCmd        `cmd:"" name:"members" help:"List members of a group"`
+	Update         GroupsUpdateCmd         `cmd:"" name:"update" help:"Bulk-update display names, descriptions, and labels from a CSV"`
+	VerifyDelivery GroupsVerifyDeliveryCmd `cmd:"" name:"verify-delivery" help:"Check that mail sent to a group reaches its members (--probe sends a test message)"`
 }
 
 type GroupsListCmd struct {
@@ -40,6 +42,10 @@ func (c *GroupsListCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
+	if groupsViaContacts(account) {
+		return listContactGroupsAsGroups(ctx, u, account)
+	}
+
 	svc, err := newCloudIdentityService(ctx, account)
 	if err != nil {
 		return wrapCloudIdentityError(err, account)
@@ -48,12 +54,19 @@ func (c *GroupsListCmd) Run(ctx context.Context, flags *RootFlags) error {
 	// Search for all groups the user belongs to
 	// Using "groups/-" as parent searches across all groups
 	resp, err := svc.Groups.Memberships.SearchTransitiveGroups("groups/-").
-		Query("member_key_id == '" + account + "'").
+		Query(cloudIdentityMemberQuery(account)).
 		PageSize(c.Max).
 		PageToken(c.Page).
 		Context(ctx).
 		Do()
 	if err != nil {
+		if c.Page == "" && cloudIdentityUnsupported(err) {
+			// A consumer account on its own domain: remember it and fall back.
+			if rememberErr := rememberAccountKind(account, accountKindConsumer); rememberErr != nil {
+				u.Err().Printf("capabilities cache: %v", rememberErr)
+			}
+			return listContactGroupsAsGroups(ctx, u, account)
+		}
 		return wrapCloudIdentityError(err, account)
 	}
 
@@ -111,14 +124,33 @@ func wrapCloudIdentityError(err error, account string) error {
 	}
 	if strings.Contains(errStr, "insufficientPermissions") ||
 		strings.Contains(errStr, "insufficient authentication scopes") {
-		return errfmt.NewUserFacingError("Insufficient permissions for Cloud Identity API; re-authenticate with the cloud-identity.groups.readonly scope: gog auth add <account> --services groups", err)
+		return errfmt.NewUserFacingError("Insufficient permissions for Cloud Identity API; re-authenticate with the Cloud Identity groups scopes: gog auth add <account> --services groups --force-consent", err)
 	}
-	if isConsumerAccount(account) && (strings.Contains(errStr, "invalid argument") || strings.Contains(errStr, "badRequest")) {
+	if accountKind(account) == accountKindConsumer && cloudIdentityUnsupported(err) {
 		return errfmt.NewUserFacingError("Cloud Identity groups require a Google Workspace/Cloud Identity account; consumer accounts (gmail.com/googlemail.com) are not supported.", err)
 	}
 	return err
 }
 
+// cloudIdentityUnsupported reports whether err is how Cloud Identity turns
+// away accounts without Workspace groups.
+func cloudIdentityUnsupported(err error) bool {
+	errStr := err.Error()
+	return strings.Contains(errStr, "invalid argument") || strings.Contains(errStr, "badRequest")
+}
+
+// cloudIdentityMemberQuery is the SearchTransitiveGroups query for email,
+// quoted as a string literal so the address cannot change the expression.
+func cloudIdentityMemberQuery(email string) string {
+	return "member_key_id == '" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(email) + "'"
+}
+
+// groupsViaContacts reports whether group commands should use the account's
+// contact groups: consumer accounts have no Cloud Identity groups.
+func groupsViaContacts(account string) bool {
+	return accountKind(account) == accountKindConsumer
+}
+
 // getRelationType returns a human-readable relation type.
 func getRelationType(relationType string) string {
 	switch relationType {
@@ -132,7 +164,7 @@ func getRelationType(relationType string) string {
 }
 
 type GroupsMembersCmd struct {
-	GroupEmail string `arg:"" name:"groupEmail" help:"Group email (e.g., engineering@company.com)"`
+	GroupEmail string `arg:"" name:"groupEmail" help:"Group email (e.g., engineering@company.com), or a contact group name for consumer accounts"`
 	Max        int64  `name:"max" aliases:"limit" help:"Max results" default:"100"`
 	Page       string `name:"page" help:"Page token"`
 }
@@ -149,6 +181,10 @@ func (c *GroupsMembersCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return usage("group email required")
 	}
 
+	if groupsViaContacts(account) {
+		return listContactGroupMembersAsGroup(ctx, u, account, groupEmail)
+	}
+
 	svc, err := newCloudIdentityService(ctx, account)
 	if err != nil {
 		return wrapCloudIdentityError(err, account)
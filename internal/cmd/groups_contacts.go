@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// Consumer accounts have no Cloud Identity groups; `groups list` and
+// `groups members` show their contact groups instead, in the same shape.
+
+const contactGroupsNotice = "Consumer account: showing contact groups (Workspace groups need Google Workspace; see gog capabilities)"
+
+func listContactGroupsAsGroups(ctx context.Context, u *ui.UI, account string) error {
+	svc, err := newPeopleContactsService(ctx, account)
+	if err != nil {
+		return err
+	}
+	groups, err := listContactGroups(ctx, svc)
+	if err != nil {
+		return wrapPeopleAPIError(err)
+	}
+
+	type item struct {
+		GroupName   string `json:"groupName"`
+		DisplayName string `json:"displayName,omitempty"`
+		MemberCount int64  `json:"memberCount"`
+	}
+	items := make([]item, 0, len(groups))
+	for _, g := range groups {
+		// System groups (My Contacts, Starred, ...) are not distribution lists.
+		if g == nil || g.GroupType != "USER_CONTACT_GROUP" {
+			continue
+		}
+		items = append(items, item{GroupName: g.ResourceName, DisplayName: g.FormattedName, MemberCount: g.MemberCount})
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"groups": items,
+			"source": "contacts",
+		})
+	}
+	u.Err().Println(contactGroupsNotice)
+	if len(items) == 0 {
+		u.Err().Println("No contact groups found")
+		return nil
+	}
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "GROUP\tNAME\tMEMBERS")
+	for _, it := range items {
+		fmt.Fprintf(w, "%s\t%s\t%d\n", it.GroupName, sanitizeTab(it.DisplayName), it.MemberCount)
+	}
+	return nil
+}
+
+func listContactGroupMembersAsGroup(ctx context.Context, u *ui.UI, account, group string) error {
+	emails, err := expandContactGroups(ctx, account, []string{group})
+	if err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		type item struct {
+			Email string `json:"email"`
+			Role  string `json:"role"`
+			Type  string `json:"type"`
+		}
+		items := make([]item, 0, len(emails))
+		for _, email := range emails {
+			items = append(items, item{Email: email, Role: groupRoleMember, Type: "CONTACT"})
+		}
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"members": items,
+			"source":  "contacts",
+		})
+	}
+	u.Err().Println(contactGroupsNotice)
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "EMAIL\tROLE\tTYPE")
+	for _, email := range emails {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", sanitizeTab(email), groupRoleMember, "CONTACT")
+	}
+	return nil
+}
@@ -0,0 +1,350 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"google.golang.org/api/cloudidentity/v1"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+var newCloudIdentityEditorService = googleapi.NewCloudIdentityGroupsEditor
+
+const groupLabelPrefix = "cloudidentity.googleapis.com/groups."
+
+type GroupsUpdateCmd struct {
+	CSV      string `name:"csv" help:"CSV with an email column and any of display_name, description, labels (- for stdin)"`
+	DiffWith string `name:"diff-with" help:"Desired state as a JSON array of {email, displayName, description, labels} (- for stdin); shows what differs"`
+	Apply    bool   `name:"apply" help:"With --diff-with: update groups to match the file"`
+	DryRun   bool   `name:"dry-run" help:"Show what would change without updating groups"`
+}
+
+// groupUpdateRow is one CSV row; nil fields leave the group's value alone.
+type groupUpdateRow struct {
+	Line        int
+	Email       string
+	DisplayName *string
+	Description *string
+	Labels      []string
+}
+
+type groupFieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+type groupUpdateResult struct {
+	Email   string             `json:"email"`
+	Status  string             `json:"status"`
+	Changes []groupFieldChange `json:"changes,omitempty"`
+	Error   string             `json:"error,omitempty"`
+}
+
+func (c *GroupsUpdateCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	var rows []groupUpdateRow
+	switch csvPath, diffPath := strings.TrimSpace(c.CSV), strings.TrimSpace(c.DiffWith); {
+	case csvPath != "" && diffPath != "":
+		return usage("use either --csv or --diff-with")
+	case c.Apply && diffPath == "":
+		return usage("--apply needs --diff-with <file>")
+	case diffPath != "":
+		// A desired-state file is only a diff until --apply.
+		c.DryRun = c.DryRun || !c.Apply
+		rows, err = readGroupDesiredState(diffPath)
+	case csvPath != "":
+		rows, err = readGroupUpdateCSV(csvPath)
+	default:
+		return usage("--csv or --diff-with is required")
+	}
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return usage("no groups to update")
+	}
+
+	newService := newCloudIdentityEditorService
+	if c.DryRun {
+		newService = newCloudIdentityService
+	}
+	svc, err := newService(ctx, account)
+	if err != nil {
+		return wrapCloudIdentityError(err, account)
+	}
+
+	results := make([]groupUpdateResult, 0, len(rows))
+	failed := 0
+	for _, row := range rows {
+		res := c.apply(ctx, svc, row)
+		if interrupted(ctx) {
+			// The row in flight may or may not have been patched; re-running
+			// the CSV reports it as unchanged if it was.
+			break
+		}
+		if res.Error != "" {
+			failed++
+		}
+		results = append(results, res)
+	}
+
+	if outfmt.IsJSON(ctx) {
+		out := map[string]any{"results": results, "dryRun": c.DryRun}
+		if interrupted(ctx) {
+			out["interrupted"] = true
+		}
+		if err := outfmt.WriteJSON(os.Stdout, out); err != nil {
+			return err
+		}
+	} else {
+		w, flush := tableWriter(ctx)
+		fmt.Fprintln(w, "GROUP\tSTATUS\tCHANGES")
+		for _, r := range results {
+			detail := r.Error
+			if detail == "" {
+				parts := make([]string, 0, len(r.Changes))
+				for _, ch := range r.Changes {
+					parts = append(parts, fmt.Sprintf("%s: %q -> %q", ch.Field, ch.From, ch.To))
+				}
+				detail = strings.Join(parts, "; ")
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", sanitizeTab(r.Email), r.Status, orDash(sanitizeTab(detail)))
+		}
+		flush()
+	}
+
+	if interrupted(ctx) {
+		u.Err().Printf("Interrupted after %d of %d groups", len(results), len(rows))
+		return nil
+	}
+	if failed > 0 {
+		return &ExitError{Code: 1, Err: fmt.Errorf("%d of %d groups failed", failed, len(rows))}
+	}
+	if c.DryRun {
+		u.Err().Println("Dry run; no groups were changed.")
+	}
+	return nil
+}
+
+func (c *GroupsUpdateCmd) apply(ctx context.Context, svc *cloudidentity.Service, row groupUpdateRow) groupUpdateResult {
+	res := groupUpdateResult{Email: row.Email, Status: "failed"}
+	name, err := lookupGroupByEmail(ctx, svc, row.Email)
+	if err != nil {
+		res.Error = fmt.Sprintf("line %d: lookup: %v", row.Line, err)
+		return res
+	}
+	group, err := svc.Groups.Get(name).Context(ctx).Do()
+	if err != nil {
+		res.Error = fmt.Sprintf("line %d: %v", row.Line, err)
+		return res
+	}
+
+	patch := &cloudidentity.Group{}
+	var mask []string
+	if row.DisplayName != nil && *row.DisplayName != group.DisplayName {
+		res.Changes = append(res.Changes, groupFieldChange{Field: "displayName", From: group.DisplayName, To: *row.DisplayName})
+		patch.DisplayName = *row.DisplayName
+		mask = append(mask, "display_name")
+	}
+	if row.Description != nil && *row.Description != group.Description {
+		res.Changes = append(res.Changes, groupFieldChange{Field: "description", From: group.Description, To: *row.Description})
+		patch.Description = *row.Description
+		mask = append(mask, "description")
+	}
+	if row.Labels != nil {
+		current := groupLabelNames(group.Labels)
+		if strings.Join(current, ",") != strings.Join(row.Labels, ",") {
+			res.Changes = append(res.Changes, groupFieldChange{Field: "labels", From: strings.Join(current, ";"), To: strings.Join(row.Labels, ";")})
+			patch.Labels = make(map[string]string, len(row.Labels))
+			for _, l := range row.Labels {
+				patch.Labels[groupLabelPrefix+l] = ""
+			}
+			mask = append(mask, "labels")
+		}
+	}
+
+	switch {
+	case len(mask) == 0:
+		res.Status = "unchanged"
+	case c.DryRun:
+		res.Status = "would-update"
+	default:
+		// Clearing a field needs it in the mask and in ForceSendFields.
+		patch.ForceSendFields = []string{"DisplayName", "Description"}
+		if _, err := svc.Groups.Patch(name, patch).UpdateMask(strings.Join(mask, ",")).Context(ctx).Do(); err != nil {
+			res.Error = fmt.Sprintf("line %d: %v", row.Line, err)
+			return res
+		}
+		res.Status = "updated"
+	}
+	return res
+}
+
+// readGroupUpdateCSV parses the header row case-insensitively. Empty cells
+// leave a field unchanged; "-" clears a display name or description.
+func readGroupUpdateCSV(path string) ([]groupUpdateRow, error) {
+	if path == "" {
+		return nil, usage("empty --csv")
+	}
+	var in io.Reader
+	if path == "-" {
+		in = os.Stdin
+	} else {
+		expanded, err := config.ExpandPath(path)
+		if err != nil {
+			return nil, err
+		}
+		f, err := os.Open(expanded) //nolint:gosec // user-provided path
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	r := csv.NewReader(in)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read CSV header: %w", err)
+	}
+	cols := map[string]int{}
+	for i, h := range header {
+		key := strings.ToLower(strings.TrimSpace(h))
+		key = strings.NewReplacer(" ", "_", "-", "_").Replace(strings.TrimPrefix(key, "\ufeff"))
+		if key == "displayname" {
+			key = "display_name"
+		}
+		cols[key] = i
+	}
+	if _, ok := cols["email"]; !ok {
+		return nil, usage("CSV needs an email column")
+	}
+
+	var rows []groupUpdateRow
+	seen := map[string]int{}
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read CSV: %w", err)
+		}
+		line, _ := r.FieldPos(0)
+		cell := func(name string) (string, bool) {
+			i, ok := cols[name]
+			if !ok || i >= len(rec) {
+				return "", false
+			}
+			v := strings.TrimSpace(rec[i])
+			return v, v != ""
+		}
+		email, _ := cell("email")
+		if email == "" {
+			continue
+		}
+		if prev, dup := seen[strings.ToLower(email)]; dup {
+			return nil, usagef("line %d: %s already listed on line %d", line, email, prev)
+		}
+		seen[strings.ToLower(email)] = line
+
+		row := groupUpdateRow{Line: line, Email: email}
+		if v, ok := cell("display_name"); ok {
+			v = clearDash(v)
+			row.DisplayName = &v
+		}
+		if v, ok := cell("description"); ok {
+			v = clearDash(v)
+			row.Description = &v
+		}
+		if v, ok := cell("labels"); ok {
+			row.Labels = parseGroupLabels(v)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// readGroupDesiredState reads groups update's desired-state JSON. Omitted
+// fields leave a group's value alone; an empty string clears it. Line is the
+// 1-based position in the array.
+func readGroupDesiredState(path string) ([]groupUpdateRow, error) {
+	data, err := readDesiredState(path, "groups")
+	if err != nil {
+		return nil, err
+	}
+	var items []struct {
+		Email       string   `json:"email"`
+		DisplayName *string  `json:"displayName"`
+		Description *string  `json:"description"`
+		Labels      []string `json:"labels"`
+	}
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, usagef("%s: expected a JSON array of groups: %v", path, err)
+	}
+	rows := make([]groupUpdateRow, 0, len(items))
+	seen := map[string]int{}
+	for i, item := range items {
+		email := strings.TrimSpace(item.Email)
+		if email == "" {
+			return nil, usagef("group %d: missing email", i+1)
+		}
+		if prev, dup := seen[strings.ToLower(email)]; dup {
+			return nil, usagef("group %d: %s already listed as group %d", i+1, email, prev)
+		}
+		seen[strings.ToLower(email)] = i + 1
+		row := groupUpdateRow{Line: i + 1, Email: email, DisplayName: item.DisplayName, Description: item.Description}
+		if item.Labels != nil {
+			row.Labels = parseGroupLabels(strings.Join(item.Labels, ";"))
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func clearDash(v string) string {
+	if v == "-" {
+		return ""
+	}
+	return v
+}
+
+// parseGroupLabels accepts ; or | separated labels, with or without the
+// cloudidentity.googleapis.com/groups. prefix, and returns sorted short names.
+func parseGroupLabels(v string) []string {
+	fields := strings.FieldsFunc(v, func(r rune) bool { return r == ';' || r == '|' })
+	out := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f = strings.TrimPrefix(strings.TrimSpace(f), groupLabelPrefix); f != "" && f != "-" {
+			out = append(out, f)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func groupLabelNames(labels map[string]string) []string {
+	out := make([]string, 0, len(labels))
+	for k := range labels {
+		out = append(out, strings.TrimPrefix(k, groupLabelPrefix))
+	}
+	sort.Strings(out)
+	return out
+}
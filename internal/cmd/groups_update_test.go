@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/cloudidentity/v1"
+	"google.golang.org/api/option"
+)
+
+func TestGroupsUpdateCSV(t *testing.T) {
+	groups := map[string]map[string]any{
+		"groups/eng": {"name": "groups/eng", "displayName": "Engineering", "description": "Builders",
+			"labels": map[string]string{"cloudidentity.googleapis.com/groups.discussion_forum": ""}},
+		"groups/ops": {"name": "groups/ops", "displayName": "Ops", "description": "",
+			"labels": map[string]string{"cloudidentity.googleapis.com/groups.discussion_forum": ""}},
+	}
+	byEmail := map[string]string{"eng@b.com": "groups/eng", "ops@b.com": "groups/ops"}
+	var patches []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := strings.TrimPrefix(r.URL.Path, "/v1/")
+		switch {
+		case path == "groups:lookup":
+			name, ok := byEmail[r.URL.Query().Get("groupKey.id")]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"name": name})
+		case r.Method == http.MethodGet && groups[path] != nil:
+			_ = json.NewEncoder(w).Encode(groups[path])
+		case r.Method == http.MethodPatch && groups[path] != nil:
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			patches = append(patches, path+" "+r.URL.Query().Get("updateMask")+" "+body["displayName"].(string)+"|"+body["description"].(string))
+			_ = json.NewEncoder(w).Encode(map[string]any{"done": true})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	svc, err := cloudidentity.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	origRead, origEdit := newCloudIdentityService, newCloudIdentityEditorService
+	t.Cleanup(func() { newCloudIdentityService, newCloudIdentityEditorService = origRead, origEdit })
+	newCloudIdentityService = func(context.Context, string) (*cloudidentity.Service, error) { return svc, nil }
+	newCloudIdentityEditorService = newCloudIdentityService
+
+	csvPath := filepath.Join(t.TempDir(), "groups.csv")
+	data := "Email,Display Name,Description,Labels\n" +
+		"eng@b.com,Platform Engineering,,discussion_forum\n" +
+		"ops@b.com,Ops,-,\n" +
+		"missing@b.com,Nobody,,\n"
+	if err := os.WriteFile(csvPath, []byte(data), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	run := func(args ...string) (string, error) {
+		var err error
+		out := captureStdout(t, func() {
+			_ = captureStderr(t, func() {
+				err = Execute(append([]string{"--json", "--account", "a@b.com", "groups", "update", "--csv", csvPath}, args...))
+			})
+		})
+		return out, err
+	}
+
+	out, err := run("--dry-run")
+	if ExitCode(err) != 1 || len(patches) != 0 {
+		t.Fatalf("dry run: err=%v patches=%v", err, patches)
+	}
+	var parsed struct {
+		Results []groupUpdateResult `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if len(parsed.Results) != 3 ||
+		parsed.Results[0].Status != "would-update" || len(parsed.Results[0].Changes) != 1 || parsed.Results[0].Changes[0].To != "Platform Engineering" ||
+		parsed.Results[1].Status != "unchanged" ||
+		parsed.Results[2].Status != "failed" || !strings.Contains(parsed.Results[2].Error, "line 4") {
+		t.Fatalf("unexpected dry run: %s", out)
+	}
+
+	if _, err := run(); ExitCode(err) != 1 {
+		t.Fatalf("expected partial failure, got %v", err)
+	}
+	if len(patches) != 1 || patches[0] != "groups/eng display_name Platform Engineering|" {
+		t.Fatalf("unexpected patches: %v", patches)
+	}
+}
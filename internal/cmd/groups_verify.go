@@ -0,0 +1,352 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/api/cloudidentity/v1"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+var groupProbePollInterval = 10 * time.Second
+
+type GroupsVerifyDeliveryCmd struct {
+	GroupEmail   string        `arg:"" name:"groupEmail" help:"Group email (e.g., engineering@company.com)"`
+	Probe        bool          `name:"probe" help:"Send a probe message to the group and wait for it to arrive (without it, only membership is checked)"`
+	CheckAccount []string      `name:"check-account" help:"Also look for the probe in this member's mailbox (an account already authorized in gog; repeatable)"`
+	Wait         time.Duration `name:"wait" help:"How long to wait for the probe to arrive" default:"3m"`
+	Guardrail    GuardrailFlag `embed:""`
+}
+
+// groupProbeMailbox is the delivery result for one mailbox.
+type groupProbeMailbox struct {
+	Account   string   `json:"account"`
+	Member    string   `json:"member"`
+	Delivered bool     `json:"delivered"`
+	Spam      bool     `json:"spam,omitempty"`
+	MessageID string   `json:"messageId,omitempty"`
+	Labels    []string `json:"labels,omitempty"`
+	Latency   string   `json:"latency,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+func (c *GroupsVerifyDeliveryCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	groupEmail := strings.TrimSpace(c.GroupEmail)
+	if groupEmail == "" {
+		return usage("group email required")
+	}
+	if c.Wait <= 0 {
+		return usage("--wait must be > 0")
+	}
+	if len(c.CheckAccount) > 0 && !c.Probe {
+		return usage("--check-account requires --probe")
+	}
+
+	ciSvc, err := newCloudIdentityService(ctx, account)
+	if err != nil {
+		return wrapCloudIdentityError(err, account)
+	}
+	groupName, err := lookupGroupByEmail(ctx, ciSvc, groupEmail)
+	if err != nil {
+		return fmt.Errorf("failed to find group %q: %w", groupEmail, wrapCloudIdentityError(err, account))
+	}
+
+	mailboxes := []*groupProbeMailbox{{Account: account}}
+	for _, a := range c.CheckAccount {
+		if a = strings.TrimSpace(a); a != "" && !strings.EqualFold(a, account) {
+			mailboxes = append(mailboxes, &groupProbeMailbox{Account: a})
+		}
+	}
+	for _, mb := range mailboxes {
+		mb.Member = groupMembershipStatus(ctx, ciSvc, groupName, mb.Account)
+	}
+
+	result := map[string]any{
+		"group":     groupEmail,
+		"mailboxes": mailboxes,
+	}
+	var diagnosis []string
+	var delivered bool
+	if c.Probe {
+		probe, probeErr := c.sendProbe(ctx, u, flags, account, groupEmail)
+		if probeErr != nil {
+			return probeErr
+		}
+		result["probeMessageId"] = probe.sentID
+		delivered = c.waitForProbe(ctx, u, probe, mailboxes)
+		bounces := findProbeBounces(ctx, probe, groupEmail)
+		result["bounces"] = bounces
+		diagnosis = diagnoseGroupDelivery(groupEmail, c.Wait, mailboxes, bounces)
+	} else if mailboxes[0].Member == "no" {
+		diagnosis = append(diagnosis, fmt.Sprintf("%s is not a member of %s; groups that only let members post will reject its mail.", account, groupEmail))
+	}
+	result["diagnosis"] = diagnosis
+	if c.Probe {
+		result["delivered"] = delivered
+	}
+
+	if outfmt.IsJSON(ctx) {
+		if err := outfmt.WriteJSON(os.Stdout, result); err != nil {
+			return err
+		}
+	} else {
+		w, flush := tableWriter(ctx)
+		if c.Probe {
+			fmt.Fprintln(w, "ACCOUNT\tMEMBER\tDELIVERED\tLATENCY\tLABELS")
+		} else {
+			fmt.Fprintln(w, "ACCOUNT\tMEMBER")
+		}
+		for _, mb := range mailboxes {
+			if !c.Probe {
+				fmt.Fprintf(w, "%s\t%s\n", mb.Account, mb.Member)
+				continue
+			}
+			state := "no"
+			switch {
+			case mb.Error != "":
+				state = "error: " + mb.Error
+			case mb.Spam:
+				state = "spam"
+			case mb.Delivered:
+				state = "yes"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", mb.Account, mb.Member, sanitizeTab(state), orDash(mb.Latency), strings.Join(mb.Labels, ","))
+		}
+		flush()
+		for _, d := range diagnosis {
+			u.Err().Println(d)
+		}
+		if !c.Probe {
+			u.Err().Println("Use --probe to send a test message through the group.")
+		}
+	}
+
+	if c.Probe && !delivered {
+		return &ExitError{Code: 1, Err: fmt.Errorf("probe to %s was not delivered within %s", groupEmail, c.Wait)}
+	}
+	return nil
+}
+
+// groupMembershipStatus reports yes/no, or unknown when the caller may not
+// check memberships of this group.
+func groupMembershipStatus(ctx context.Context, svc *cloudidentity.Service, groupName, email string) string {
+	resp, err := svc.Groups.Memberships.CheckTransitiveMembership(groupName).
+		Query(cloudIdentityMemberQuery(email)).
+		Context(ctx).
+		Do()
+	if err != nil {
+		return "unknown"
+	}
+	if resp.HasMembership {
+		return "yes"
+	}
+	return "no"
+}
+
+type groupProbe struct {
+	account   string
+	messageID string // RFC822 Message-ID
+	sentID    string // Gmail ID of the sender's copy
+	sentAt    time.Time
+}
+
+func (c *GroupsVerifyDeliveryCmd) sendProbe(ctx context.Context, u *ui.UI, flags *RootFlags, account, groupEmail string) (*groupProbe, error) {
+	if err := enforceSendGuardrails(ctx, flags, account, c.Guardrail, sendGuardrailCheck{Recipients: []string{groupEmail}}); err != nil {
+		return nil, err
+	}
+	svc, err := newGmailService(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+	messageID, err := randomMessageID(account)
+	if err != nil {
+		return nil, err
+	}
+	sentAt := time.Now()
+	raw, err := buildRFC822(mailOptions{
+		From:    account,
+		To:      []string{groupEmail},
+		Subject: "gog delivery probe " + sentAt.UTC().Format(time.RFC3339),
+		Body: "This is an automated delivery test for " + groupEmail + " sent with gog groups verify-delivery.\n" +
+			"It can be deleted.\n",
+		AdditionalHeaders: map[string]string{
+			"Message-ID":     messageID,
+			"X-Gog-Probe":    "groups-verify-delivery",
+			"Auto-Submitted": "auto-generated",
+		},
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	sent, err := svc.Users.Messages.Send("me", &gmail.Message{Raw: base64.RawURLEncoding.EncodeToString(raw)}).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("send probe: %w", err)
+	}
+	u.Err().Printf("Probe sent to %s (%s); waiting up to %s…", groupEmail, messageID, c.Wait)
+	return &groupProbe{account: account, messageID: messageID, sentID: sent.Id, sentAt: sentAt}, nil
+}
+
+// waitForProbe polls every mailbox until all have the probe or the wait is
+// over, and reports whether any mailbox received it.
+func (c *GroupsVerifyDeliveryCmd) waitForProbe(ctx context.Context, u *ui.UI, probe *groupProbe, mailboxes []*groupProbeMailbox) bool {
+	services := make(map[string]*gmail.Service, len(mailboxes))
+	for _, mb := range mailboxes {
+		svc, err := newGmailService(ctx, mb.Account)
+		if err != nil {
+			mb.Error = err.Error()
+			continue
+		}
+		services[mb.Account] = svc
+	}
+
+	deadline := time.Now().Add(c.Wait)
+	for {
+		pending := 0
+		for _, mb := range mailboxes {
+			svc := services[mb.Account]
+			if svc == nil || mb.Delivered {
+				continue
+			}
+			if err := checkProbeMailbox(ctx, svc, probe, mb); err != nil {
+				u.Err().Printf("%s: %v", mb.Account, err)
+			}
+			if !mb.Delivered {
+				pending++
+			}
+		}
+		if pending == 0 || !time.Now().Before(deadline) {
+			break
+		}
+		if err := sendPacerSleep(ctx, min(groupProbePollInterval, time.Until(deadline))); err != nil {
+			break
+		}
+	}
+
+	for _, mb := range mailboxes {
+		if mb.Delivered {
+			return true
+		}
+	}
+	return false
+}
+
+// checkProbeMailbox looks for a received copy of the probe. In the sender's
+// mailbox the sent copy only counts once it picks up a label besides SENT,
+// because Gmail folds the group's copy of your own post into it.
+func checkProbeMailbox(ctx context.Context, svc *gmail.Service, probe *groupProbe, mb *groupProbeMailbox) error {
+	resp, err := svc.Users.Messages.List("me").
+		Q("rfc822msgid:" + strings.Trim(probe.messageID, "<>")).
+		IncludeSpamTrash(true).
+		Context(ctx).
+		Do()
+	if err != nil {
+		return err
+	}
+	for _, ref := range resp.Messages {
+		if ref == nil {
+			continue
+		}
+		msg, err := svc.Users.Messages.Get("me", ref.Id).Format("minimal").Context(ctx).Do()
+		if err != nil {
+			return err
+		}
+		received := false
+		for _, l := range msg.LabelIds {
+			if l != "SENT" && l != "DRAFT" && l != "UNREAD" && l != "TRASH" {
+				received = true
+			}
+		}
+		if !received {
+			continue
+		}
+		mb.Delivered = true
+		mb.MessageID = msg.Id
+		mb.Labels = msg.LabelIds
+		for _, l := range msg.LabelIds {
+			if l == "SPAM" {
+				mb.Spam = true
+			}
+		}
+		if msg.InternalDate > 0 {
+			latency := time.UnixMilli(msg.InternalDate).Sub(probe.sentAt)
+			mb.Latency = max(latency, 0).Round(time.Second).String()
+		}
+		return nil
+	}
+	return nil
+}
+
+// findProbeBounces returns snippets of delivery status notifications the
+// sender received about the group since the probe went out.
+func findProbeBounces(ctx context.Context, probe *groupProbe, groupEmail string) []string {
+	svc, err := newGmailService(ctx, probe.account)
+	if err != nil {
+		return nil
+	}
+	resp, err := svc.Users.Messages.List("me").
+		Q(fmt.Sprintf("from:(mailer-daemon OR postmaster) newer_than:1d %q", groupEmail)).
+		MaxResults(10).
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil
+	}
+	var out []string
+	for _, ref := range resp.Messages {
+		if ref == nil {
+			continue
+		}
+		msg, err := svc.Users.Messages.Get("me", ref.Id).Format("minimal").Context(ctx).Do()
+		if err != nil || time.UnixMilli(msg.InternalDate).Before(probe.sentAt.Add(-time.Minute)) {
+			continue
+		}
+		out = append(out, msg.Snippet)
+	}
+	return out
+}
+
+func diagnoseGroupDelivery(groupEmail string, wait time.Duration, mailboxes []*groupProbeMailbox, bounces []string) []string {
+	var out []string
+	sender := mailboxes[0]
+	if len(bounces) > 0 {
+		out = append(out, fmt.Sprintf("The group rejected the probe: %s", bounces[0]))
+	}
+	if sender.Member == "no" {
+		out = append(out, fmt.Sprintf("%s is not a member of %s; groups that only let members post drop or reject its mail.", sender.Account, groupEmail))
+	}
+
+	anyDelivered, othersDelivered := false, false
+	for i, mb := range mailboxes {
+		if mb.Spam {
+			out = append(out, fmt.Sprintf("The probe landed in spam for %s; check the group's DMARC/From rewriting settings.", mb.Account))
+		}
+		if mb.Delivered {
+			anyDelivered = true
+			if i > 0 {
+				othersDelivered = true
+			}
+		}
+		if i > 0 && mb.Member == "no" {
+			out = append(out, fmt.Sprintf("%s is not a member of %s, so it is not expected to receive the probe.", mb.Account, groupEmail))
+		}
+	}
+	switch {
+	case !anyDelivered && len(bounces) == 0:
+		out = append(out, fmt.Sprintf("No copy arrived within %s and nothing bounced. Check who may post to the group, whether messages are held for moderation, and that email delivery to members is enabled.", wait))
+	case othersDelivered && !sender.Delivered:
+		out = append(out, "Members received the probe but the sender did not; Gmail often hides your own posts to a group, so this is usually fine.")
+	}
+	return out
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/cloudidentity/v1"
+	"google.golang.org/api/option"
+)
+
+func stubGroupsService(t *testing.T, member bool) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(r.URL.Path, "groups:lookup"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"name": "groups/eng"})
+		case strings.Contains(r.URL.Path, "groups/eng/memberships:checkTransitiveMembership"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"hasMembership": member})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	svc, err := cloudidentity.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	origNew := newCloudIdentityService
+	t.Cleanup(func() { newCloudIdentityService = origNew })
+	newCloudIdentityService = func(context.Context, string) (*cloudidentity.Service, error) { return svc, nil }
+}
+
+func TestGroupsVerifyDelivery_Probe(t *testing.T) {
+	origSleep := sendPacerSleep
+	t.Cleanup(func() { sendPacerSleep = origSleep })
+	sendPacerSleep = func(_ context.Context, _ time.Duration) error { return nil }
+	stubGroupsService(t, true)
+
+	polls := 0
+	var sentRaw, searched string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/gmail/v1")
+		w.Header().Set("Content-Type", "application/json")
+		q := r.URL.Query().Get("q")
+		switch {
+		case r.Method == http.MethodPost && path == "/users/me/messages/send":
+			var body struct {
+				Raw string `json:"raw"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			sentRaw = body.Raw
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "sent1", "threadId": "t1"})
+		case path == "/users/me/messages" && strings.HasPrefix(q, "rfc822msgid:"):
+			searched = q
+			polls++
+			msgs := []map[string]any{{"id": "sent1"}}
+			if polls >= 2 {
+				msgs = append(msgs, map[string]any{"id": "in1"})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"messages": msgs})
+		case path == "/users/me/messages":
+			_ = json.NewEncoder(w).Encode(map[string]any{})
+		case path == "/users/me/messages/sent1":
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "sent1", "labelIds": []string{"SENT"}})
+		case path == "/users/me/messages/in1":
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "in1", "labelIds": []string{"INBOX", "UNREAD"}, "internalDate": "1"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	stubGmailService(t, srv)
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "a@b.com", "groups", "verify-delivery", "eng@b.com", "--probe"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if sentRaw == "" || polls != 2 || !strings.Contains(searched, "@b.com") {
+		t.Fatalf("sent=%v polls=%d q=%q", sentRaw != "", polls, searched)
+	}
+	var parsed struct {
+		Delivered bool                 `json:"delivered"`
+		Mailboxes []*groupProbeMailbox `json:"mailboxes"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if !parsed.Delivered || len(parsed.Mailboxes) != 1 || parsed.Mailboxes[0].MessageID != "in1" || parsed.Mailboxes[0].Member != "yes" {
+		t.Fatalf("unexpected result: %s", out)
+	}
+}
+
+func TestGroupsVerifyDelivery_NotDelivered(t *testing.T) {
+	origSleep := sendPacerSleep
+	t.Cleanup(func() { sendPacerSleep = origSleep })
+	sendPacerSleep = func(_ context.Context, _ time.Duration) error { return nil }
+	stubGroupsService(t, false)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/gmail/v1")
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && path == "/users/me/messages/send":
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "sent1"})
+		case path == "/users/me/messages":
+			_ = json.NewEncoder(w).Encode(map[string]any{})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	stubGmailService(t, srv)
+
+	var err error
+	errOut := captureStderr(t, func() {
+		_ = captureStdout(t, func() {
+			err = Execute([]string{"--account", "a@b.com", "groups", "verify-delivery", "eng@b.com", "--probe", "--wait", "1ms"})
+		})
+	})
+	if err == nil || ExitCode(err) != 1 {
+		t.Fatalf("expected exit 1, got %v", err)
+	}
+	if !strings.Contains(errOut, "not a member") || !strings.Contains(errOut, "moderation") {
+		t.Fatalf("missing diagnosis: %q", errOut)
+	}
+}
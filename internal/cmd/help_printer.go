@@ -36,6 +36,11 @@ func helpPrinter(options kong.HelpOptions, ctx *kong.Context) error {
 		}
 	}()
 
+	// The config and keyring lines cost file reads, so only help pays for them.
+	if ctx.Model.Help == baseDescription() {
+		ctx.Model.Help = helpDescription()
+	}
+
 	buf := bytes.NewBuffer(nil)
 	ctx.Stdout = buf
 	ctx.Stderr = origStderr
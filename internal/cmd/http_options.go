@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"os"
+	"strings"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/googleapi"
+)
+
+// reservedHTTPHeaders are managed by the HTTP and OAuth clients.
+var reservedHTTPHeaders = map[string]bool{
+	"Authorization":     true,
+	"Host":              true,
+	"Content-Length":    true,
+	"Transfer-Encoding": true,
+}
+
+// configureHTTPOptions applies http_headers and user_agent_suffix from
+// config.json, overridden by --http-header and --user-agent-suffix, to every
+// Google request made by this process. Flags are checked now; config.json is
+// read when the first API client is created, so local commands skip it.
+func configureHTTPOptions(flags *RootFlags) error {
+	flagHeaders := http.Header{}
+	for _, raw := range flags.HTTPHeader {
+		name, value, ok := strings.Cut(raw, ":")
+		if !ok {
+			return usagef("invalid --http-header %q (expected 'Name: value')", raw)
+		}
+		if err := addHTTPHeader(flagHeaders, name, value); err != nil {
+			return usagef("--http-header: %v", err)
+		}
+	}
+	flagSuffix := strings.TrimSpace(flags.UserAgentSuffix)
+	if strings.ContainsAny(flagSuffix, "\r\n") {
+		return usagef("invalid user agent suffix %q", flagSuffix)
+	}
+
+	googleapi.SetHTTPOptionsLoader(func() (googleapi.HTTPOptions, error) {
+		cfg, err := config.ReadConfig()
+		if err != nil {
+			return googleapi.HTTPOptions{}, err
+		}
+		headers := http.Header{}
+		for name, value := range cfg.HTTPHeaders {
+			if err := addHTTPHeader(headers, name, os.ExpandEnv(value)); err != nil {
+				return googleapi.HTTPOptions{}, usagef("config http_headers: %v", err)
+			}
+		}
+		for name, values := range flagHeaders {
+			headers[name] = values
+		}
+		suffix := flagSuffix
+		if suffix == "" {
+			suffix = cfg.UserAgentSuffix
+		}
+		if strings.ContainsAny(suffix, "\r\n") {
+			return googleapi.HTTPOptions{}, usagef("invalid user agent suffix %q", suffix)
+		}
+		return googleapi.HTTPOptions{Headers: headers, UserAgentSuffix: suffix}, nil
+	})
+	return nil
+}
+
+func addHTTPHeader(h http.Header, name, value string) error {
+	name = strings.TrimSpace(name)
+	value = strings.TrimSpace(value)
+	if name == "" || strings.ContainsAny(name, " \t\r\n:") {
+		return fmt.Errorf("invalid header name %q", name)
+	}
+	if strings.ContainsAny(value, "\r\n") {
+		return fmt.Errorf("invalid value for header %s", name)
+	}
+	canonical := textproto.CanonicalMIMEHeaderKey(name)
+	if reservedHTTPHeaders[canonical] {
+		return fmt.Errorf("header %s cannot be overridden", canonical)
+	}
+	h.Add(canonical, value)
+	return nil
+}
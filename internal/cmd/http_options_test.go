@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/googleapi"
+)
+
+func TestHTTPHeadersAndUserAgentSuffix(t *testing.T) {
+	orig, err := config.ReadConfig()
+	if err != nil {
+		t.Fatalf("ReadConfig: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = config.WriteConfig(orig)
+		googleapi.SetHTTPOptions(googleapi.HTTPOptions{})
+	})
+	cfg := orig
+	cfg.HTTPHeaders = map[string]string{"X-Gateway-Token": "${GOG_TEST_GATEWAY_TOKEN}", "X-Team": "config"}
+	cfg.UserAgentSuffix = "acme-proxy/1"
+	if err := config.WriteConfig(cfg); err != nil {
+		t.Fatalf("WriteConfig: %v", err)
+	}
+	t.Setenv("GOG_TEST_GATEWAY_TOKEN", "s3cret")
+
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"labels": []any{}})
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv(googleapi.EnvAPIEndpoint, srv.URL)
+
+	run := func(args ...string) error {
+		var err error
+		_ = captureStdout(t, func() {
+			_ = captureStderr(t, func() {
+				err = Execute(append([]string{"--account", "a@b.com"}, args...))
+			})
+		})
+		return err
+	}
+
+	if err := run("--http-header", "x-team: flag, ops", "gmail", "labels", "list"); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if got.Get("X-Gateway-Token") != "s3cret" || got.Get("X-Team") != "flag, ops" {
+		t.Fatalf("unexpected headers: %v", got)
+	}
+	if ua := got.Get("User-Agent"); !strings.HasSuffix(ua, " acme-proxy/1") {
+		t.Fatalf("unexpected user agent %q", ua)
+	}
+
+	if err := run("--user-agent-suffix", "ci", "gmail", "labels", "list"); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if ua := got.Get("User-Agent"); !strings.HasSuffix(ua, " ci") || strings.Contains(ua, "acme-proxy") {
+		t.Fatalf("flag should override config suffix: %q", ua)
+	}
+
+	for _, bad := range []string{"Authorization: Bearer x", "no-colon"} {
+		if err := run("--http-header", bad, "gmail", "labels", "list"); ExitCode(err) != 2 {
+			t.Fatalf("%q: expected usage error, got %v", bad, err)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/mail"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/ics"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// icsNow is stubbed in tests for a stable DTSTAMP.
+var icsNow = time.Now
+
+type IcsCmd struct {
+	Create IcsCreateCmd `cmd:"" name:"create" help:"Write an iTIP REQUEST invite file (attach it with gmail send --attach)"`
+}
+
+type IcsCreateCmd struct {
+	Title       string        `name:"title" aliases:"summary" help:"Event title (required)"`
+	Start       string        `name:"start" help:"Start time (RFC3339, '2026-01-05 14:00', or date with --all-day)"`
+	End         string        `name:"end" help:"End time (default: start + --duration)"`
+	Duration    time.Duration `name:"duration" help:"Length when --end is not set" default:"1h"`
+	AllDay      bool          `name:"all-day" help:"All-day event (--start/--end are dates; --end is inclusive)"`
+	Timezone    string        `name:"timezone" aliases:"tz" help:"Timezone for times without an offset (IANA name or 'local')"`
+	Attendee    []string      `name:"attendee" help:"Required attendee ('Name <email>' or email; repeatable or comma-separated)"`
+	Optional    []string      `name:"optional" help:"Optional attendee (repeatable or comma-separated)"`
+	Organizer   string        `name:"organizer" help:"Organizer ('Name <email>' or email; default: the selected account)"`
+	Location    string        `name:"location" help:"Location"`
+	Description string        `name:"description" help:"Description"`
+	URL         string        `name:"url" help:"Event URL (e.g. a video call link)"`
+	UID         string        `name:"uid" help:"Event UID (default: random; reuse it with a higher --sequence to update an invite)"`
+	Sequence    int64         `name:"sequence" help:"Revision number; increase when re-sending an updated invite" default:"0"`
+	Out         string        `name:"out" aliases:"output" help:"Output file path ('-' for stdout)" default:"-"`
+}
+
+func (c *IcsCreateCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+
+	title := strings.TrimSpace(c.Title)
+	if title == "" {
+		return usage("required: --title")
+	}
+	if strings.TrimSpace(c.Start) == "" {
+		return usage("required: --start")
+	}
+	if c.Sequence < 0 {
+		return usage("--sequence must be >= 0")
+	}
+
+	loc, err := resolveOutputLocation(c.Timezone, false)
+	if err != nil {
+		return err
+	}
+	start, end, err := c.eventTimes(loc)
+	if err != nil {
+		return err
+	}
+
+	organizer, err := c.organizer(flags)
+	if err != nil {
+		return err
+	}
+	attendees, err := icsAttendees(c.Attendee, "REQ-PARTICIPANT")
+	if err != nil {
+		return err
+	}
+	optional, err := icsAttendees(c.Optional, "OPT-PARTICIPANT")
+	if err != nil {
+		return err
+	}
+	attendees = append(attendees, optional...)
+	if len(attendees) == 0 {
+		return usage("at least one --attendee or --optional is required")
+	}
+
+	uid := strings.TrimSpace(c.UID)
+	if uid == "" {
+		if uid, err = randomICSUID(organizer.Email); err != nil {
+			return err
+		}
+	}
+
+	event := ics.Event{
+		UID:         uid,
+		Summary:     title,
+		Description: c.Description,
+		Location:    strings.TrimSpace(c.Location),
+		URL:         strings.TrimSpace(c.URL),
+		Status:      "CONFIRMED",
+		Start:       start,
+		End:         end,
+		AllDay:      c.AllDay,
+		Sequence:    c.Sequence,
+		Organizer:   organizer,
+		Attendees:   attendees,
+	}
+	var buf bytes.Buffer
+	if err := ics.Encode(&buf, ics.Calendar{Method: "REQUEST", Events: []ics.Event{event}}, icsNow()); err != nil {
+		return err
+	}
+
+	path := strings.TrimSpace(c.Out)
+	toStdout := path == "" || path == "-"
+	if !toStdout {
+		if path, err = config.ExpandPath(path); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+			return err
+		}
+	}
+
+	emails := make([]string, 0, len(attendees))
+	for _, a := range attendees {
+		emails = append(emails, a.Email)
+	}
+	if outfmt.IsJSON(ctx) {
+		payload := map[string]any{
+			"uid":       uid,
+			"sequence":  c.Sequence,
+			"method":    "REQUEST",
+			"organizer": organizer.Email,
+			"attendees": emails,
+		}
+		if toStdout {
+			payload["ics"] = buf.String()
+		} else {
+			payload["path"] = path
+		}
+		return outfmt.WriteJSON(os.Stdout, payload)
+	}
+	if toStdout {
+		_, err := os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	u.Out().Printf("path\t%s", path)
+	u.Out().Printf("uid\t%s", uid)
+	u.Out().Printf("attendees\t%s", strings.Join(emails, ","))
+	u.Err().Printf("Send it with: gog gmail send --to %s --subject %q --body ... --attach %s", strings.Join(emails, ","), title, path)
+	return nil
+}
+
+func (c *IcsCreateCmd) eventTimes(loc *time.Location) (time.Time, time.Time, error) {
+	if c.AllDay {
+		start, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(c.Start), time.UTC)
+		if err != nil {
+			return time.Time{}, time.Time{}, usagef("invalid --start %q (expected YYYY-MM-DD with --all-day)", c.Start)
+		}
+		end := start
+		if v := strings.TrimSpace(c.End); v != "" {
+			if end, err = time.ParseInLocation("2006-01-02", v, time.UTC); err != nil {
+				return time.Time{}, time.Time{}, usagef("invalid --end %q (expected YYYY-MM-DD with --all-day)", c.End)
+			}
+		}
+		if end.Before(start) {
+			return time.Time{}, time.Time{}, usage("--end must not be before --start")
+		}
+		// DTEND is exclusive for all-day events.
+		return start, end.AddDate(0, 0, 1), nil
+	}
+
+	now := time.Now().In(loc)
+	start, err := parseTimeExpr(c.Start, now, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, usagef("invalid --start: %v", err)
+	}
+	var end time.Time
+	if v := strings.TrimSpace(c.End); v != "" {
+		if end, err = parseTimeExpr(v, now, loc); err != nil {
+			return time.Time{}, time.Time{}, usagef("invalid --end: %v", err)
+		}
+	} else {
+		if c.Duration <= 0 {
+			return time.Time{}, time.Time{}, usage("--duration must be > 0")
+		}
+		end = start.Add(c.Duration)
+	}
+	if !end.After(start) {
+		return time.Time{}, time.Time{}, usage("--end must be after --start")
+	}
+	return start, end, nil
+}
+
+func (c *IcsCreateCmd) organizer(flags *RootFlags) (*ics.Attendee, error) {
+	raw := strings.TrimSpace(c.Organizer)
+	if raw == "" {
+		account, err := requireAccount(flags)
+		if err != nil {
+			return nil, usage("required: --organizer (or select an account with --account)")
+		}
+		raw = account
+	}
+	addr, err := mail.ParseAddress(raw)
+	if err != nil {
+		return nil, usagef("invalid --organizer %q: %v", raw, err)
+	}
+	return &ics.Attendee{Email: addr.Address, Name: addr.Name}, nil
+}
+
+// icsAttendees parses "Name <email>" or bare addresses into invitees that are
+// asked to RSVP.
+func icsAttendees(values []string, role string) ([]ics.Attendee, error) {
+	var out []ics.Attendee
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		addr, err := mail.ParseAddress(v)
+		if err != nil {
+			return nil, usagef("invalid attendee %q: %v", v, err)
+		}
+		out = append(out, ics.Attendee{
+			Email:    addr.Address,
+			Name:     addr.Name,
+			Role:     role,
+			PartStat: "NEEDS-ACTION",
+			RSVP:     true,
+		})
+	}
+	return out, nil
+}
+
+func randomICSUID(organizer string) (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("generate uid: %w", err)
+	}
+	domain := "gog.local"
+	if _, d, ok := strings.Cut(organizer, "@"); ok && d != "" {
+		domain = d
+	}
+	return hex.EncodeToString(b[:]) + "@" + domain, nil
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIcsCreate_WritesRequest(t *testing.T) {
+	origNow := icsNow
+	t.Cleanup(func() { icsNow = origNow })
+	icsNow = func() time.Time { return time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC) }
+
+	path := filepath.Join(t.TempDir(), "invite.ics")
+	out := captureStdout(t, func() {
+		if err := Execute([]string{
+			"--json", "ics", "create",
+			"--title", "Design review",
+			"--start", "2026-03-02T10:00:00+01:00",
+			"--duration", "45m",
+			"--attendee", "Ann Lee <ann@example.com>,bob@example.com",
+			"--optional", "cat@example.com",
+			"--organizer", "Org <org@example.com>",
+			"--uid", "review-1@example.com",
+			"--sequence", "2",
+			"--out", path,
+		}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	var parsed struct {
+		UID       string   `json:"uid"`
+		Path      string   `json:"path"`
+		Attendees []string `json:"attendees"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if parsed.UID != "review-1@example.com" || parsed.Path != path || len(parsed.Attendees) != 3 {
+		t.Fatalf("unexpected output: %#v", parsed)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	got := strings.ReplaceAll(string(data), "\r\n ", "")
+	for _, want := range []string{
+		"METHOD:REQUEST\r\n",
+		"UID:review-1@example.com\r\n",
+		"DTSTAMP:20260301T120000Z\r\n",
+		"DTSTART:20260302T090000Z\r\n",
+		"DTEND:20260302T094500Z\r\n",
+		"SEQUENCE:2\r\n",
+		"ORGANIZER;CN=\"Org\":mailto:org@example.com\r\n",
+		"ATTENDEE;CN=\"Ann Lee\";ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailto:ann@example.com\r\n",
+		"ATTENDEE;ROLE=OPT-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailto:cat@example.com\r\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestIcsCreate_AllDayAndValidation(t *testing.T) {
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"ics", "create", "--title", "Offsite", "--all-day", "--start", "2026-05-04", "--end", "2026-05-05", "--attendee", "a@example.com", "--organizer", "o@example.com"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	if !strings.Contains(out, "DTSTART;VALUE=DATE:20260504\r\n") || !strings.Contains(out, "DTEND;VALUE=DATE:20260506\r\n") {
+		t.Fatalf("unexpected all-day dates:\n%s", out)
+	}
+
+	for _, args := range [][]string{
+		{"ics", "create", "--start", "2026-05-04T10:00:00Z", "--attendee", "a@example.com", "--organizer", "o@example.com"},
+		{"ics", "create", "--title", "x", "--start", "2026-05-04T10:00:00Z", "--organizer", "o@example.com"},
+		{"ics", "create", "--title", "x", "--start", "2026-05-04T10:00:00Z", "--end", "2026-05-04T09:00:00Z", "--attendee", "a@example.com", "--organizer", "o@example.com"},
+	} {
+		_ = captureStderr(t, func() {
+			if err := Execute(args); ExitCode(err) != 2 {
+				t.Fatalf("%v: expected usage error, got %v", args, err)
+			}
+		})
+	}
+}
+
+func TestIcsCreate_AttachToGmailSend(t *testing.T) {
+	svc := newMockGmail(t)
+
+	path := filepath.Join(t.TempDir(), "invite.ics")
+	_ = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--account", "test@example.com", "ics", "create", "--title", "Sync", "--start", "2026-03-02T10:00:00Z", "--attendee", "ann@example.com", "--out", path}); err != nil {
+				t.Fatalf("ics create: %v", err)
+			}
+		})
+	})
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "test@example.com", "gmail", "send", "--to", "ann@example.com", "--subject", "Sync", "--body", "See invite", "--attach", path}); err != nil {
+				t.Fatalf("gmail send: %v", err)
+			}
+		})
+	})
+	var sent struct {
+		MessageID string `json:"messageId"`
+	}
+	if err := json.Unmarshal([]byte(out), &sent); err != nil || sent.MessageID == "" {
+		t.Fatalf("send output: %v\n%s", err, out)
+	}
+
+	msg, err := svc.Users.Messages.Get("me", sent.MessageID).Format("raw").Do()
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(msg.Raw)
+	if err != nil {
+		raw, err = base64.URLEncoding.DecodeString(msg.Raw)
+	}
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !strings.Contains(string(raw), `Content-Type: text/calendar; charset="utf-8"; method=REQUEST`) {
+		t.Fatalf("missing calendar part:\n%s", raw)
+	}
+}
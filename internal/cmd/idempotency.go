@@ -0,0 +1,334 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/errfmt"
+	"github.com/steipete/gogcli/internal/events"
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// idempotencyTTL is how long a used key keeps returning its original result.
+const idempotencyTTL = 30 * 24 * time.Hour
+
+const (
+	idempotencyPending = "pending"
+	idempotencyDone    = "done"
+	// idempotencyUnknown marks a run that failed after it may have reached
+	// Google (timeout, 5xx, dropped connection); the key stays claimed.
+	idempotencyUnknown = "unknown"
+)
+
+// idempotencyLockStale is how old a store lock may get before it is assumed
+// to belong to a crashed process and is broken.
+const idempotencyLockStale = 30 * time.Second
+
+// IdempotencyFlag makes a send/create command safe to retry: the first run
+// records its result under the key, later runs with the same key print that
+// result instead of acting again.
+type IdempotencyFlag struct {
+	Key string `name:"idempotency-key" help:"Retry-safe key: a repeated key returns the original result instead of acting again"`
+}
+
+type idempotencyRecord struct {
+	Key         string    `json:"key"`
+	Account     string    `json:"account"`
+	Operation   string    `json:"operation"`
+	Status      string    `json:"status"`
+	ResourceID  string    `json:"resourceId,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	OutputJSON  bool      `json:"outputJson,omitempty"`
+	Output      string    `json:"output,omitempty"`
+	StartedAt   time.Time `json:"startedAt"`
+	CompletedAt time.Time `json:"completedAt,omitzero"`
+}
+
+type idempotencyStore struct {
+	path    string
+	Records map[string]idempotencyRecord `json:"records"`
+}
+
+func loadIdempotencyStore() (*idempotencyStore, error) {
+	path, err := config.IdempotencyPath()
+	if err != nil {
+		return nil, err
+	}
+	store := &idempotencyStore{path: path, Records: map[string]idempotencyRecord{}}
+	data, err := os.ReadFile(path) //nolint:gosec // path under config dir
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return store, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("parse idempotency store: %w", err)
+	}
+	if store.Records == nil {
+		store.Records = map[string]idempotencyRecord{}
+	}
+	return store, nil
+}
+
+func (s *idempotencyStore) save(now time.Time) error {
+	for id, rec := range s.Records {
+		if rec.Status == idempotencyDone && now.Sub(rec.CompletedAt) > idempotencyTTL {
+			delete(s.Records, id)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("ensure state dir: %w", err)
+	}
+	payload, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, append(payload, '\n'), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// lockIdempotencyStore serializes load→modify→save across processes with an
+// O_EXCL lock file next to the store. The returned func releases it.
+func lockIdempotencyStore(ctx context.Context) (func(), error) {
+	path, err := config.IdempotencyPath()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("ensure state dir: %w", err)
+	}
+	lock := path + ".lock"
+	for {
+		f, err := os.OpenFile(lock, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600) //nolint:gosec // path under config dir
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = os.Remove(lock) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("lock idempotency store: %w", err)
+		}
+		if info, statErr := os.Stat(lock); statErr == nil && time.Since(info.ModTime()) > idempotencyLockStale {
+			_ = os.Remove(lock)
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+}
+
+func idempotencyID(account, key string) string {
+	return strings.ToLower(account) + "/" + key
+}
+
+// idempotentRun tracks one keyed operation between beginIdempotent and
+// finish. A nil run (no key given) makes every method a no-op.
+type idempotentRun struct {
+	ctx        context.Context
+	id         string
+	record     idempotencyRecord
+	stopOutput func() []byte
+}
+
+// beginIdempotent checks key before a mutating operation. done=true means the
+// key was already used and its original result has been printed; the caller
+// returns err without acting. Otherwise the caller must defer finish.
+func beginIdempotent(ctx context.Context, key, account, operation string) (*idempotentRun, bool, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return nil, false, nil
+	}
+	unlock, err := lockIdempotencyStore(ctx)
+	if err != nil {
+		return nil, true, err
+	}
+	defer unlock()
+	store, err := loadIdempotencyStore()
+	if err != nil {
+		return nil, true, err
+	}
+	id := idempotencyID(account, key)
+	if rec, ok := store.Records[id]; ok && !(rec.Status == idempotencyDone && time.Since(rec.CompletedAt) > idempotencyTTL) {
+		if rec.Operation != operation {
+			return nil, true, usagef("idempotency key %q was already used for %s", key, rec.Operation)
+		}
+		if rec.Status == idempotencyUnknown {
+			return nil, true, fmt.Errorf("an earlier %s with idempotency key %q failed at %s after it may have reached Google (%s); check whether it took effect, then retry with a new key",
+				operation, key, rec.CompletedAt.Local().Format(time.RFC3339), rec.Error)
+		}
+		if rec.Status != idempotencyDone {
+			// The earlier run may or may not have reached Google, or is still
+			// running; acting again could duplicate it, so make a human look.
+			return nil, true, fmt.Errorf("an earlier %s with idempotency key %q started at %s and did not finish; check whether it took effect, then retry with a new key",
+				operation, key, rec.StartedAt.Local().Format(time.RFC3339))
+		}
+		return nil, true, replayIdempotent(ctx, rec)
+	}
+
+	run := &idempotentRun{ctx: ctx, id: id, record: idempotencyRecord{
+		Key:       key,
+		Account:   account,
+		Operation: operation,
+		Status:    idempotencyPending,
+		StartedAt: time.Now().UTC(),
+	}}
+	store.Records[id] = run.record
+	if err := store.save(time.Now()); err != nil {
+		return nil, true, err
+	}
+	stop, err := teeStdout()
+	if err != nil {
+		return nil, true, err
+	}
+	run.stopOutput = stop
+	return run, false, nil
+}
+
+// resource records IDs of what the operation created. Once any is recorded,
+// the key counts as used even if a later step fails.
+func (r *idempotentRun) resource(ids ...string) {
+	if r == nil {
+		return
+	}
+	for _, id := range ids {
+		if r.record.ResourceID != "" {
+			r.record.ResourceID += ","
+		}
+		r.record.ResourceID += id
+	}
+}
+
+// finish stores the outcome: successful (or resource-producing) runs are
+// recorded with their output; failures that clearly never took effect free
+// the key, and ambiguous ones keep it claimed as unknown.
+func (r *idempotentRun) finish(errp *error) {
+	if r == nil {
+		return
+	}
+	output := r.stopOutput()
+	unlock, err := lockIdempotencyStore(context.WithoutCancel(r.ctx))
+	if err != nil {
+		ui.FromContext(r.ctx).Err().Printf("idempotency store: %v", err)
+		return
+	}
+	defer unlock()
+	store, err := loadIdempotencyStore()
+	if err != nil {
+		ui.FromContext(r.ctx).Err().Printf("idempotency store: %v", err)
+		return
+	}
+	now := time.Now().UTC()
+	switch {
+	case *errp != nil && r.record.ResourceID == "" && idempotencyNotApplied(*errp):
+		delete(store.Records, r.id)
+	case *errp != nil && r.record.ResourceID == "":
+		r.record.Status = idempotencyUnknown
+		r.record.CompletedAt = now
+		r.record.Error = (*errp).Error()
+		store.Records[r.id] = r.record
+	default:
+		r.record.Status = idempotencyDone
+		r.record.CompletedAt = now
+		r.record.Output = string(output)
+		r.record.OutputJSON = outfmt.IsJSON(r.ctx)
+		store.Records[r.id] = r.record
+	}
+	if err := store.save(now); err != nil {
+		ui.FromContext(r.ctx).Err().Printf("idempotency store: %v", err)
+		return
+	}
+	if r.record.Status == idempotencyDone {
+		if emitErr := events.Emit(events.TypeMutationRecorded, r.record.Account, map[string]any{
+			"operation":      r.record.Operation,
+			"idempotencyKey": r.record.Key,
+			"resourceId":     r.record.ResourceID,
+		}); emitErr != nil {
+			ui.FromContext(r.ctx).Err().Printf("event log: %v", emitErr)
+		}
+	}
+}
+
+// idempotencyNotApplied reports whether err shows the operation was rejected
+// before it took effect: local/usage failures and definitive 4xx answers.
+// Timeouts, dropped connections, and 5xx may hide a success, so they don't.
+func idempotencyNotApplied(err error) bool {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return true
+	}
+	info := classifyError(err)
+	if info.HTTPStatus != 0 {
+		return info.HTTPStatus >= 400 && info.HTTPStatus < 500 && info.HTTPStatus != http.StatusRequestTimeout
+	}
+	switch info.Code {
+	case errfmt.CodeUsage, errfmt.CodeAuth, errfmt.CodePermissionDenied, errfmt.CodeNotFound,
+		errfmt.CodeRateLimit, errfmt.CodeQuota, errfmt.CodeInvalidArgument, errfmt.CodeConflict:
+		return true
+	}
+	return googleapi.IsCircuitBreakerError(err)
+}
+
+// replayIdempotent prints the original output, or a summary when the output
+// mode differs from the first run.
+func replayIdempotent(ctx context.Context, rec idempotencyRecord) error {
+	u := ui.FromContext(ctx)
+	u.Err().Printf("idempotency key %q already used by %s at %s; returning the original result",
+		rec.Key, rec.Operation, rec.CompletedAt.Local().Format(time.RFC3339))
+	if rec.OutputJSON == outfmt.IsJSON(ctx) && rec.Output != "" {
+		_, err := io.WriteString(os.Stdout, rec.Output)
+		return err
+	}
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"idempotencyKey": rec.Key,
+			"operation":      rec.Operation,
+			"resourceId":     rec.ResourceID,
+			"replayed":       true,
+		})
+	}
+	u.Out().Printf("resource_id\t%s", rec.ResourceID)
+	return nil
+}
+
+// teeStdout copies everything written to os.Stdout into a buffer until the
+// returned stop function is called.
+func teeStdout() (func() []byte, error) {
+	orig := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = io.Copy(io.MultiWriter(orig, &buf), r)
+	}()
+	os.Stdout = w
+	return func() []byte {
+		os.Stdout = orig
+		_ = w.Close()
+		<-done
+		_ = r.Close()
+		return buf.Bytes()
+	}, nil
+}
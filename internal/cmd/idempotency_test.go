@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	ggoogleapi "google.golang.org/api/googleapi"
+
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/mock"
+)
+
+func TestIdempotencyKeyReplaysCalendarCreate(t *testing.T) {
+	s, err := mock.New(nil, mock.ServiceCalendar)
+	if err != nil {
+		t.Fatalf("mock.New: %v", err)
+	}
+	var inserts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/events") {
+			inserts.Add(1)
+		}
+		s.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+	t.Setenv(googleapi.EnvAPIEndpoint, srv.URL)
+
+	run := func(args ...string) (string, error) {
+		t.Helper()
+		var runErr error
+		out := captureStdout(t, func() {
+			_ = captureStderr(t, func() {
+				runErr = Execute(append([]string{"--account", "test@example.com"}, args...))
+			})
+		})
+		return out, runErr
+	}
+
+	create := []string{
+		"calendar", "create", "primary", "--summary", "Standup",
+		"--from", "2026-03-02T09:00:00Z", "--to", "2026-03-02T09:15:00Z",
+		"--idempotency-key", "standup-0302",
+	}
+	first, err := run(create...)
+	if err != nil {
+		t.Fatalf("first create: %v", err)
+	}
+	second, err := run(create...)
+	if err != nil {
+		t.Fatalf("second create: %v", err)
+	}
+	if got := inserts.Load(); got != 1 {
+		t.Fatalf("expected 1 insert, got %d", got)
+	}
+	if first != second {
+		t.Fatalf("replayed output differs:\nfirst:  %q\nsecond: %q", first, second)
+	}
+
+	// JSON mode replays a summary carrying the original resource ID.
+	out, err := run(append([]string{"--json"}, create...)...)
+	if err != nil {
+		t.Fatalf("json replay: %v", err)
+	}
+	if !strings.Contains(out, `"replayed": true`) || !strings.Contains(out, "mockevt0001") {
+		t.Fatalf("unexpected json replay: %q", out)
+	}
+
+	// Reusing the key for a different operation is refused.
+	if _, err := run("tasks", "add", "list1", "--title", "x", "--idempotency-key", "standup-0302"); err == nil ||
+		!strings.Contains(err.Error(), "already used for calendar.create") {
+		t.Fatalf("expected key conflict, got %v", err)
+	}
+}
+
+func TestIdempotencyKeyAmbiguousFailureKeepsKey(t *testing.T) {
+	ctx := context.Background()
+	fail := func(key string, failure error) {
+		t.Helper()
+		run, done, err := beginIdempotent(ctx, key, "a@example.com", "gmail.send")
+		if done || err != nil {
+			t.Fatalf("begin %s: done=%v err=%v", key, done, err)
+		}
+		run.finish(&failure)
+	}
+
+	// A 5xx after the request went out may hide a successful send.
+	fail("ambiguous", &ggoogleapi.Error{Code: http.StatusServiceUnavailable})
+	if _, done, err := beginIdempotent(ctx, "ambiguous", "a@example.com", "gmail.send"); !done || err == nil ||
+		!strings.Contains(err.Error(), "may have reached Google") {
+		t.Fatalf("expected retry to report unknown state, got done=%v err=%v", done, err)
+	}
+
+	// A definitive rejection frees the key for another attempt.
+	fail("rejected", &ggoogleapi.Error{Code: http.StatusBadRequest})
+	run, done, err := beginIdempotent(ctx, "rejected", "a@example.com", "gmail.send")
+	if done || err != nil {
+		t.Fatalf("expected rejected key to be free, got done=%v err=%v", done, err)
+	}
+	var ok error
+	run.finish(&ok)
+}
+
+func TestIdempotencyStoreLockSerializes(t *testing.T) {
+	ctx := context.Background()
+	unlock, err := lockIdempotencyStore(ctx)
+	if err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	if _, err := lockIdempotencyStore(waitCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected second lock to wait, got %v", err)
+	}
+	unlock()
+	unlock2, err := lockIdempotencyStore(ctx)
+	if err != nil {
+		t.Fatalf("relock: %v", err)
+	}
+	unlock2()
+}
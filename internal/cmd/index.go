@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/docindex"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// indexMaxAttachmentBytes skips attachments too large to be worth reading
+// into memory for text extraction.
+const indexMaxAttachmentBytes = 50 << 20
+
+type IndexCmd struct {
+	Build  IndexBuildCmd  `cmd:"" name:"build" help:"Download attachments and index their text locally"`
+	Search IndexSearchCmd `cmd:"" name:"search" help:"Search indexed attachment text"`
+	Status IndexStatusCmd `cmd:"" name:"status" help:"Show index size and location"`
+}
+
+type IndexBuildCmd struct {
+	Query   string `name:"query" short:"q" help:"Gmail query selecting messages" default:"has:attachment"`
+	Max     int64  `name:"max" aliases:"limit" help:"Max messages to scan" default:"200"`
+	Dir     string `name:"dir" help:"Where downloaded attachments are kept (default: gog's attachment cache)"`
+	Rebuild bool   `name:"rebuild" help:"Re-extract attachments that are already indexed"`
+}
+
+func (c *IndexBuildCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	if c.Max <= 0 {
+		return usage("--max must be > 0")
+	}
+	query := strings.TrimSpace(c.Query)
+	if !strings.Contains(strings.ToLower(query), "has:attachment") {
+		query = strings.TrimSpace(query + " has:attachment")
+	}
+	dir := strings.TrimSpace(c.Dir)
+	if dir == "" {
+		if dir, err = config.EnsureGmailAttachmentsDir(); err != nil {
+			return err
+		}
+	} else if dir, err = config.ExpandPath(dir); err != nil {
+		return err
+	}
+
+	idx, err := openAttachmentIndex()
+	if err != nil {
+		return err
+	}
+	svc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+	ids, err := listMessageIDs(ctx, svc, query, c.Max)
+	if err != nil {
+		return err
+	}
+	messages := make([]*gmail.Message, len(ids))
+	err = fetchGmailConcurrently(ctx, len(ids), gmailQuotaMessageGet, func(ctx context.Context, i int) error {
+		msg, getErr := svc.Users.Messages.Get("me", ids[i]).Format("full").Context(ctx).Do()
+		if getErr != nil {
+			return fmt.Errorf("message %s: %w", ids[i], getErr)
+		}
+		messages[i] = msg
+		return nil
+	})
+	// After Ctrl-C, index what was fetched so far and save it; a re-run skips
+	// those attachments.
+	stopped := err != nil && interrupted(ctx)
+	if err != nil && !stopped {
+		return err
+	}
+
+	var fetched, indexed, skipped, unsupported, failed int
+messages:
+	for _, msg := range messages {
+		if msg == nil {
+			continue
+		}
+		fetched++
+		for _, a := range collectAttachments(msg.Payload) {
+			id := msg.Id + "/" + a.AttachmentID
+			if docindex.Kind(a.Filename, a.MimeType) == "" || a.Size > indexMaxAttachmentBytes {
+				unsupported++
+				continue
+			}
+			if !c.Rebuild && idx.Has(id) {
+				skipped++
+				continue
+			}
+			path, _, dlErr := downloadAttachment(ctx, svc, msg.Id, a, dir)
+			if dlErr != nil && interrupted(ctx) {
+				stopped = true
+				break messages
+			}
+			if dlErr != nil {
+				return fmt.Errorf("download %s from message %s: %w", a.Filename, msg.Id, dlErr)
+			}
+			data, readErr := os.ReadFile(path) //nolint:gosec // we just wrote it
+			if readErr != nil {
+				return readErr
+			}
+			text, extractErr := docindex.ExtractText(a.Filename, a.MimeType, data)
+			if extractErr != nil {
+				failed++
+				u.Err().Printf("skip %s (%s): %v", a.Filename, msg.Id, extractErr)
+				continue
+			}
+			idx.Add(docindex.Doc{
+				ID:           id,
+				MessageID:    msg.Id,
+				AttachmentID: a.AttachmentID,
+				Filename:     a.Filename,
+				MimeType:     a.MimeType,
+				Path:         path,
+				Subject:      headerValue(msg.Payload, "Subject"),
+				From:         headerValue(msg.Payload, "From"),
+				Date:         headerValue(msg.Payload, "Date"),
+				IndexedAt:    time.Now().UTC(),
+			}, text)
+			indexed++
+		}
+	}
+	if err := idx.Save(); err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		out := map[string]any{
+			"messages":    fetched,
+			"indexed":     indexed,
+			"skipped":     skipped,
+			"unsupported": unsupported,
+			"failed":      failed,
+			"documents":   idx.Len(),
+		}
+		if stopped {
+			out["interrupted"] = true
+		}
+		return outfmt.WriteJSON(os.Stdout, out)
+	}
+	if stopped {
+		u.Err().Printf("Interrupted; saved the index for %d of %d messages", fetched, len(ids))
+	}
+	u.Out().Printf("messages\t%d", fetched)
+	u.Out().Printf("indexed\t%d", indexed)
+	u.Out().Printf("already_indexed\t%d", skipped)
+	u.Out().Printf("unsupported\t%d", unsupported)
+	if failed > 0 {
+		u.Out().Printf("failed\t%d", failed)
+	}
+	u.Out().Printf("documents\t%d", idx.Len())
+	return nil
+}
+
+type IndexSearchCmd struct {
+	Query []string `arg:"" name:"query" help:"Words that must all appear (append * for a prefix match)"`
+	Max   int      `name:"max" aliases:"limit" help:"Max results" default:"20"`
+}
+
+func (c *IndexSearchCmd) Run(ctx context.Context, _ *RootFlags) error {
+	u := ui.FromContext(ctx)
+	query := strings.TrimSpace(strings.Join(c.Query, " "))
+	if query == "" {
+		return usage("missing query")
+	}
+	idx, err := openAttachmentIndex()
+	if err != nil {
+		return err
+	}
+	if idx.Len() == 0 {
+		return fmt.Errorf("attachment index is empty; run `gog index build` first: %w", os.ErrNotExist)
+	}
+	hits := idx.Search(query, c.Max)
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{"query": query, "results": hits})
+	}
+	if len(hits) == 0 {
+		u.Err().Println("No matches")
+		return nil
+	}
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "MESSAGE\tFILENAME\tSCORE\tSNIPPET\tPATH")
+	for _, h := range hits {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n", h.MessageID, sanitizeTab(h.Filename), h.Score, sanitizeTab(h.Snippet), h.Path)
+	}
+	return nil
+}
+
+type IndexStatusCmd struct{}
+
+func (c *IndexStatusCmd) Run(ctx context.Context, _ *RootFlags) error {
+	u := ui.FromContext(ctx)
+	path, err := config.AttachmentIndexPath()
+	if err != nil {
+		return err
+	}
+	idx, err := openAttachmentIndex()
+	if err != nil {
+		return err
+	}
+	var size int64
+	if st, statErr := os.Stat(path); statErr == nil {
+		size = st.Size()
+	} else if !errors.Is(statErr, os.ErrNotExist) {
+		return statErr
+	}
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"path":      path,
+			"documents": idx.Len(),
+			"terms":     len(idx.Terms),
+			"bytes":     size,
+		})
+	}
+	u.Out().Printf("path\t%s", path)
+	u.Out().Printf("documents\t%d", idx.Len())
+	u.Out().Printf("terms\t%d", len(idx.Terms))
+	u.Out().Printf("size\t%s", formatBytes(size))
+	return nil
+}
+
+func openAttachmentIndex() (*docindex.Index, error) {
+	path, err := config.AttachmentIndexPath()
+	if err != nil {
+		return nil, err
+	}
+	return docindex.Open(path)
+}
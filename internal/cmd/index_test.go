@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/mock"
+)
+
+func TestIndexBuildAndSearch(t *testing.T) {
+	part := func(id, filename, mimeType, text string) *gmail.MessagePart {
+		data := base64.URLEncoding.EncodeToString([]byte(text))
+		return &gmail.MessagePart{
+			Filename: filename,
+			MimeType: mimeType,
+			Body:     &gmail.MessagePartBody{AttachmentId: id, Data: data, Size: int64(len(text))},
+		}
+	}
+	msg := func(id, subject string, parts ...*gmail.MessagePart) mock.MessageFixture {
+		return mock.MessageFixture{Message: &gmail.Message{
+			Id:       id,
+			ThreadId: id,
+			LabelIds: []string{"INBOX"},
+			Payload: &gmail.MessagePart{
+				MimeType: "multipart/mixed",
+				Headers: []*gmail.MessagePartHeader{
+					{Name: "From", Value: "Billing <billing@vendor.example>"},
+					{Name: "Subject", Value: subject},
+					{Name: "Date", Value: "Mon, 05 Jan 2026 10:00:00 +0000"},
+				},
+				Parts: append([]*gmail.MessagePart{{MimeType: "text/plain", Body: &gmail.MessagePartBody{Data: base64.URLEncoding.EncodeToString([]byte("see attached"))}}}, parts...),
+			},
+		}}
+	}
+	f := &mock.Fixtures{Gmail: mock.GmailFixtures{Messages: []mock.MessageFixture{
+		msg("18d0000000000001", "January invoice",
+			part("att-1", "invoice.csv", "text/csv", "item,amount\nInvoice 4711,120.00\n"),
+			part("att-2", "logo.png", "image/png", "\x89PNG")),
+		msg("18d0000000000002", "Offer", part("att-3", "offer.txt", "text/plain", "Offer 9000 valid until March")),
+	}}}
+	s, err := mock.New(f, mock.ServiceGmail)
+	if err != nil {
+		t.Fatalf("mock.New: %v", err)
+	}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	t.Setenv(googleapi.EnvAPIEndpoint, srv.URL)
+
+	run := func(args ...string) string {
+		t.Helper()
+		return captureStdout(t, func() {
+			_ = captureStderr(t, func() {
+				if err := Execute(append([]string{"--json", "--account", "test@example.com"}, args...)); err != nil {
+					t.Fatalf("Execute %v: %v", args, err)
+				}
+			})
+		})
+	}
+
+	var built struct {
+		Indexed     int `json:"indexed"`
+		Unsupported int `json:"unsupported"`
+		Documents   int `json:"documents"`
+	}
+	if err := json.Unmarshal([]byte(run("index", "build", "--dir", t.TempDir())), &built); err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	if built.Indexed != 2 || built.Unsupported != 1 || built.Documents != 2 {
+		t.Fatalf("unexpected build result: %#v", built)
+	}
+	if err := json.Unmarshal([]byte(run("index", "build", "--dir", t.TempDir())), &built); err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	if built.Indexed != 0 {
+		t.Fatalf("second build should reuse the index: %#v", built)
+	}
+
+	var found struct {
+		Results []struct {
+			MessageID string `json:"messageId"`
+			Filename  string `json:"filename"`
+			Path      string `json:"path"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(run("index", "search", "invoice", "4711")), &found); err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	if len(found.Results) != 1 || found.Results[0].MessageID != "18d0000000000001" ||
+		found.Results[0].Filename != "invoice.csv" || !strings.HasSuffix(found.Results[0].Path, "invoice.csv") {
+		t.Fatalf("unexpected search results: %#v", found)
+	}
+}
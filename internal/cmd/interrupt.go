@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/steipete/gogcli/internal/errfmt"
+)
+
+// exitNow is os.Exit; a second Ctrl-C ends the process without waiting.
+var exitNow = os.Exit
+
+// flushOnInterruptCommands stop on Ctrl-C, write the results collected so
+// far, and exit with errfmt.ExitInterrupted.
+var flushOnInterruptCommands = map[string]bool{
+	"apply":                     true,
+	"batch":                     true,
+	"calendar attendees export": true,
+	"calendar rules process":    true,
+	"gmail export maildir":      true,
+	"groups update":             true,
+	"index build":               true,
+	"projects apply":            true,
+	"rules preset invites":      true,
+}
+
+// stopOnInterruptCommands are servers and daemons: Ctrl-C is how they are
+// meant to end, so they shut down and exit 0. Other commands keep the default
+// signal handling and end at once.
+var stopOnInterruptCommands = map[string]bool{
+	"calendar watch": true,
+	"cron run":       true,
+	"events tail":    true,
+	"mock serve":     true,
+	"quota watch":    true,
+	"serve ics":      true,
+}
+
+// withInterrupt cancels ctx with errfmt.ErrInterrupted on the first SIGINT or
+// SIGTERM, so long-running commands can stop cleanly; flush says the command
+// writes partial results. A second signal exits immediately.
+func withInterrupt(ctx context.Context, flush bool) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	sigs := make(chan os.Signal, 2)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-sigs:
+		case <-done:
+			return
+		}
+		cancel(errfmt.ErrInterrupted)
+		if flush {
+			fmt.Fprintln(os.Stderr, "Interrupted; writing partial results (press Ctrl-C again to quit now)")
+		}
+		select {
+		case <-sigs:
+			exitNow(errfmt.ExitInterrupted)
+		case <-done:
+		}
+	}()
+	return ctx, func() {
+		signal.Stop(sigs)
+		close(done)
+		cancel(nil)
+	}
+}
+
+// interrupted reports whether the user stopped the command. Loops use it to
+// tell Ctrl-C apart from real failures and keep the results collected so far.
+func interrupted(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), errfmt.ErrInterrupted)
+}
+
+// serveUntilInterrupted runs srv until ctx is cancelled, then shuts it down
+// and returns nil.
+func serveUntilInterrupted(ctx context.Context, srv *http.Server) error {
+	errc := make(chan error, 1)
+	go func() { errc <- listenAndServe(srv) }()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
+
+// interruptedError is the error a command run ends with after Ctrl-C: the
+// command's message unless it is just the cancellation. Only the message is
+// kept so the interrupted exit code wins over the command's own.
+func interruptedError(err error) error {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, errfmt.ErrInterrupted) {
+		return errfmt.ErrInterrupted
+	}
+	return fmt.Errorf("%w: %v", errfmt.ErrInterrupted, err)
+}
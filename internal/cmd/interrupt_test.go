@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"google.golang.org/api/cloudidentity/v1"
+	"google.golang.org/api/option"
+
+	"github.com/steipete/gogcli/internal/errfmt"
+)
+
+func TestInterruptFlushesPartialResults(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := strings.TrimPrefix(r.URL.Path, "/v1/")
+		switch {
+		case path == "groups:lookup" && r.URL.Query().Get("groupKey.id") == "ops@b.com":
+			// Ctrl-C while the second row is in flight.
+			_ = syscall.Kill(os.Getpid(), syscall.SIGINT)
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+			http.Error(w, "slow", http.StatusServiceUnavailable)
+		case path == "groups:lookup":
+			_ = json.NewEncoder(w).Encode(map[string]any{"name": "groups/eng"})
+		case r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]any{"name": "groups/eng", "displayName": "Engineering"})
+		default:
+			_ = json.NewEncoder(w).Encode(map[string]any{"done": true})
+		}
+	}))
+	defer srv.Close()
+
+	svc, err := cloudidentity.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	origRead, origEdit := newCloudIdentityService, newCloudIdentityEditorService
+	t.Cleanup(func() { newCloudIdentityService, newCloudIdentityEditorService = origRead, origEdit })
+	newCloudIdentityService = func(context.Context, string) (*cloudidentity.Service, error) { return svc, nil }
+	newCloudIdentityEditorService = newCloudIdentityService
+
+	csvPath := filepath.Join(t.TempDir(), "groups.csv")
+	data := "email,display_name\neng@b.com,Platform\nops@b.com,Ops\nsec@b.com,Security\n"
+	if err := os.WriteFile(csvPath, []byte(data), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	var runErr error
+	var errOut string
+	out := captureStdout(t, func() {
+		errOut = captureStderr(t, func() {
+			runErr = Execute([]string{"--json", "--account", "a@b.com", "groups", "update", "--csv", csvPath})
+		})
+	})
+	if ExitCode(runErr) != errfmt.ExitInterrupted || !errors.Is(runErr, errfmt.ErrInterrupted) {
+		t.Fatalf("expected exit %d, got %d (%v)", errfmt.ExitInterrupted, ExitCode(runErr), runErr)
+	}
+	var parsed struct {
+		Results     []groupUpdateResult `json:"results"`
+		Interrupted bool                `json:"interrupted"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if !parsed.Interrupted || len(parsed.Results) != 1 || parsed.Results[0].Status != "updated" {
+		t.Fatalf("unexpected partial output: %s", out)
+	}
+	if !strings.Contains(errOut, "Interrupted after 1 of 3 groups") {
+		t.Fatalf("missing interrupt note: %q", errOut)
+	}
+}
+
+func TestInterruptStopsServersWithExitZero(t *testing.T) {
+	origListen := listenAndServe
+	t.Cleanup(func() { listenAndServe = origListen })
+	listenAndServe = func(srv *http.Server) error {
+		stopped := make(chan struct{})
+		srv.RegisterOnShutdown(func() { close(stopped) })
+		_ = syscall.Kill(os.Getpid(), syscall.SIGINT)
+		select {
+		case <-stopped:
+		case <-time.After(5 * time.Second):
+			t.Errorf("server was not shut down")
+		}
+		return http.ErrServerClosed
+	}
+
+	var runErr error
+	errOut := captureStderr(t, func() {
+		runErr = Execute([]string{"mock", "serve", "--listen", "127.0.0.1:9997"})
+	})
+	if runErr != nil {
+		t.Fatalf("Ctrl-C should end mock serve cleanly, got %v", runErr)
+	}
+	if strings.Contains(errOut, "partial results") {
+		t.Fatalf("a server has no partial results to write: %q", errOut)
+	}
+}
+
+func TestInterruptedError(t *testing.T) {
+	if err := interruptedError(nil); !errors.Is(err, errfmt.ErrInterrupted) {
+		t.Fatalf("nil: %v", err)
+	}
+	if err := interruptedError(context.Canceled); err.Error() != "interrupted" {
+		t.Fatalf("canceled: %v", err)
+	}
+	err := interruptedError(&ExitError{Code: 1, Err: errors.New("2 of 5 commands failed")})
+	if ExitCode(err) != errfmt.ExitInterrupted || err.Error() != "interrupted: 2 of 5 commands failed" {
+		t.Fatalf("wrapped: %d %v", ExitCode(err), err)
+	}
+}
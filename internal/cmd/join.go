@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/steipete/gogcli/internal/errfmt"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// meetingURLPattern finds video meeting links in free text (location,
+// description) for events without conference data.
+var meetingURLPattern = regexp.MustCompile(`https://(?:[\w-]+\.)?(?:zoom\.us/(?:j|my|w)/|meet\.google\.com/|teams\.microsoft\.com/l/meetup-join/|teams\.live\.com/meet/|[\w-]+\.webex\.com/|whereby\.com/|meet\.jit\.si/)[^\s<>"')\]]*`)
+
+type JoinCmd struct {
+	Event    string `arg:"" name:"event" optional:"" help:"'next' (default) or an event ID"`
+	Calendar string `name:"calendar" complete:"calendars" help:"Calendar ID" default:"primary"`
+	Within   string `name:"within" help:"Only join meetings starting within this long (Go duration); running meetings always qualify" default:"10m"`
+	Print    bool   `name:"print" help:"Print the meeting URL instead of opening a browser"`
+}
+
+func (c *JoinCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	within, err := time.ParseDuration(strings.TrimSpace(c.Within))
+	if err != nil || within < 0 {
+		return usagef("invalid --within %q", c.Within)
+	}
+	calendarID := strings.TrimSpace(c.Calendar)
+	if calendarID == "" {
+		return usage("empty --calendar")
+	}
+
+	svc, err := newCalendarService(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	var event *calendar.Event
+	var link string
+	target := strings.TrimSpace(c.Event)
+	if target == "" || strings.EqualFold(target, "next") {
+		event, link, err = nextJoinableEvent(ctx, svc, calendarID, time.Now(), within)
+		if err != nil {
+			return err
+		}
+		if event == nil {
+			return fmt.Errorf("no meeting with a video link starts within %s: %w", within, errfmt.ErrNotFound)
+		}
+	} else {
+		event, err = svc.Events.Get(calendarID, target).Context(ctx).Do()
+		if err != nil {
+			return err
+		}
+		if link = meetingLink(event); link == "" {
+			return fmt.Errorf("event %s has no video meeting link", target)
+		}
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"eventId": event.Id,
+			"summary": event.Summary,
+			"start":   eventStartText(event),
+			"url":     link,
+		})
+	}
+	if c.Print {
+		u.Out().Println(link)
+		return nil
+	}
+	u.Err().Printf("Joining %s (%s)", orEmpty(event.Summary, "(no title)"), eventStartText(event))
+	if err := openLinkBrowser(link); err != nil {
+		u.Err().Printf("Failed to open browser: %v", err)
+		u.Out().Println(link)
+	}
+	return nil
+}
+
+// nextJoinableEvent returns the earliest timed event that is running or
+// starts within the window and has a meeting link. Declined and all-day
+// events are skipped.
+func nextJoinableEvent(ctx context.Context, svc *calendar.Service, calendarID string, now time.Time, within time.Duration) (*calendar.Event, string, error) {
+	resp, err := svc.Events.List(calendarID).
+		TimeMin(now.Format(time.RFC3339)).
+		TimeMax(now.Add(within).Format(time.RFC3339)).
+		SingleEvents(true).
+		OrderBy("startTime").
+		MaxResults(50).
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, "", err
+	}
+	for _, e := range resp.Items {
+		ev, ok := statusEventFrom(e)
+		if !ok || ev.Start.After(now.Add(within)) || (!ev.End.IsZero() && !ev.End.After(now)) {
+			continue
+		}
+		if link := meetingLink(e); link != "" {
+			return e, link, nil
+		}
+	}
+	return nil, "", nil
+}
+
+// meetingLink prefers the conference data's video entry point, then the Meet
+// hangout link, then the first meeting URL in location or description.
+func meetingLink(e *calendar.Event) string {
+	if e == nil {
+		return ""
+	}
+	if e.ConferenceData != nil {
+		for _, ep := range e.ConferenceData.EntryPoints {
+			if ep != nil && ep.EntryPointType == "video" && ep.Uri != "" {
+				return ep.Uri
+			}
+		}
+	}
+	if e.HangoutLink != "" {
+		return e.HangoutLink
+	}
+	for _, text := range []string{e.Location, e.Description} {
+		if m := meetingURLPattern.FindString(text); m != "" {
+			return strings.TrimRight(m, ".,;")
+		}
+	}
+	return ""
+}
+
+func eventStartText(e *calendar.Event) string {
+	if e == nil || e.Start == nil {
+		return ""
+	}
+	if e.Start.DateTime != "" {
+		return e.Start.DateTime
+	}
+	return e.Start.Date
+}
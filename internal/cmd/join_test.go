@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/mock"
+)
+
+func TestJoinNext(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Minute)
+	at := func(d time.Duration) *calendar.EventDateTime {
+		return &calendar.EventDateTime{DateTime: now.Add(d).Format(time.RFC3339)}
+	}
+	f := &mock.Fixtures{Calendar: mock.CalendarFixtures{
+		Calendars: []*calendar.CalendarListEntry{{Id: "test@example.com", Summary: "me", TimeZone: "UTC", AccessRole: "owner", Primary: true}},
+		Events: map[string][]*calendar.Event{"test@example.com": {
+			{Id: "nolink", Summary: "Hallway", Start: at(-5 * time.Minute), End: at(25 * time.Minute)},
+			{
+				Id: "declined", Summary: "Skip", Start: at(2 * time.Minute), End: at(30 * time.Minute),
+				HangoutLink: "https://meet.google.com/declined",
+				Attendees:   []*calendar.EventAttendee{{Email: "test@example.com", Self: true, ResponseStatus: "declined"}},
+			},
+			{
+				Id: "zoom", Summary: "Standup", Start: at(5 * time.Minute), End: at(20 * time.Minute),
+				Description: "Join: https://acme.zoom.us/j/123456789?pwd=abc.\nDial-in below",
+			},
+			{
+				Id: "meet", Summary: "Later", Start: at(time.Hour), End: at(2 * time.Hour),
+				ConferenceData: &calendar.ConferenceData{EntryPoints: []*calendar.EntryPoint{
+					{EntryPointType: "phone", Uri: "tel:+1-555-0100"},
+					{EntryPointType: "video", Uri: "https://meet.google.com/abc-defg-hij"},
+				}},
+			},
+		}},
+	}}
+	s, err := mock.New(f, mock.ServiceCalendar)
+	if err != nil {
+		t.Fatalf("mock.New: %v", err)
+	}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	t.Setenv(googleapi.EnvAPIEndpoint, srv.URL)
+
+	origOpen := openLinkBrowser
+	t.Cleanup(func() { openLinkBrowser = origOpen })
+	var opened string
+	openLinkBrowser = func(u string) error {
+		opened = u
+		return nil
+	}
+
+	run := func(args ...string) (string, error) {
+		var err error
+		out := captureStdout(t, func() {
+			_ = captureStderr(t, func() {
+				err = Execute(append([]string{"--account", "test@example.com"}, args...))
+			})
+		})
+		return out, err
+	}
+
+	if _, err := run("join"); err != nil {
+		t.Fatalf("join: %v", err)
+	}
+	if opened != "https://acme.zoom.us/j/123456789?pwd=abc" {
+		t.Fatalf("opened %q", opened)
+	}
+
+	out, err := run("--json", "join", "next", "--within", "2h")
+	if err != nil {
+		t.Fatalf("join --json: %v", err)
+	}
+	var got struct {
+		EventID string `json:"eventId"`
+		URL     string `json:"url"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if got.EventID != "zoom" {
+		t.Fatalf("unexpected event: %#v", got)
+	}
+
+	out, err = run("join", "meet", "--print")
+	if err != nil || out != "https://meet.google.com/abc-defg-hij\n" {
+		t.Fatalf("join meet --print: %q %v", out, err)
+	}
+
+	if _, err := run("join", "--within", "1m"); err == nil {
+		t.Fatalf("expected no meeting within 1m")
+	}
+	if _, err := run("join", "nolink"); err == nil {
+		t.Fatalf("expected error for event without link")
+	}
+}
@@ -0,0 +1,275 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+const (
+	linkKindURL   = "url"
+	linkKindGmail = "gmail"
+	linkKindEvent = "event"
+	linkKindDrive = "drive"
+)
+
+var (
+	linkNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+	linkSlugRe = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// openLinkBrowser opens a URL with the platform's default handler.
+var openLinkBrowser = openProposeTimeBrowser
+
+type LinkCmd struct {
+	Create LinkCreateCmd `cmd:"" name:"create" aliases:"add,set" help:"Name a Gmail thread, Calendar event, Drive file, or URL"`
+	List   LinkListCmd   `cmd:"" name:"list" aliases:"ls" help:"List named links"`
+	Delete LinkDeleteCmd `cmd:"" name:"delete" aliases:"rm,unset" help:"Remove a named link"`
+}
+
+type LinkCreateCmd struct {
+	Resource string `arg:"" name:"resource" help:"gmail:<threadId|messageId>, event:<eventId>, drive:<fileId>, or a URL"`
+	Name     string `name:"name" help:"Short name for gog open (default: derived from the title)"`
+	Calendar string `name:"calendar" help:"Calendar ID for event: resources" default:"primary"`
+}
+
+func (c *LinkCreateCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	kind, id, err := parseLinkResource(c.Resource)
+	if err != nil {
+		return err
+	}
+
+	link := config.Link{Kind: kind, ID: id}
+	if kind == linkKindURL {
+		link.URL = id
+		link.ID = ""
+	} else {
+		account, accountErr := requireAccount(flags)
+		if accountErr != nil {
+			return accountErr
+		}
+		link.Account = account
+		if err := resolveLinkTarget(ctx, account, c.Calendar, &link); err != nil {
+			return err
+		}
+	}
+
+	name := config.NormalizeLinkName(c.Name)
+	if name == "" {
+		name = linkSlug(link.Title)
+		if name == "" {
+			return usage("required: --name (no title to derive one from)")
+		}
+	}
+	if !linkNameRe.MatchString(name) {
+		return usagef("invalid link name %q (use letters, digits, '.', '_', '-')", name)
+	}
+	if existing, ok, lookupErr := config.ResolveLink(name); lookupErr != nil {
+		return lookupErr
+	} else if ok && !flags.Force && existing.URL != link.URL {
+		return usagef("link %q already points to %s (use --force to replace it)", name, existing.URL)
+	}
+	if err := config.SetLink(name, link); err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{"name": name, "link": link})
+	}
+	u.Out().Printf("name\t%s", name)
+	u.Out().Printf("url\t%s", link.URL)
+	if link.Title != "" {
+		u.Out().Printf("title\t%s", link.Title)
+	}
+	return nil
+}
+
+// parseLinkResource splits "kind:id"; anything with a scheme is a plain URL.
+func parseLinkResource(raw string) (string, string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", "", usage("empty resource")
+	}
+	if strings.HasPrefix(raw, "https://") || strings.HasPrefix(raw, "http://") {
+		if _, err := url.Parse(raw); err != nil {
+			return "", "", usagef("invalid URL %q", raw)
+		}
+		return linkKindURL, raw, nil
+	}
+	prefix, id, ok := strings.Cut(raw, ":")
+	id = strings.TrimSpace(id)
+	if ok && id != "" {
+		switch strings.ToLower(prefix) {
+		case "gmail", "thread", "message", "mail":
+			return linkKindGmail, id, nil
+		case "event", "calendar", "cal":
+			return linkKindEvent, id, nil
+		case "drive", "file", "doc":
+			return linkKindDrive, id, nil
+		}
+	}
+	return "", "", usagef("unrecognized resource %q (use gmail:<id>, event:<id>, drive:<id>, or a URL)", raw)
+}
+
+// resolveLinkTarget fills in the deep link and a title for naming.
+func resolveLinkTarget(ctx context.Context, account, calendarID string, link *config.Link) error {
+	switch link.Kind {
+	case linkKindGmail:
+		svc, err := newGmailService(ctx, account)
+		if err != nil {
+			return err
+		}
+		threadID := link.ID
+		// Accept message IDs too: links should open the whole conversation.
+		msg, err := svc.Users.Messages.Get("me", link.ID).Format("metadata").MetadataHeaders("Subject").Context(ctx).Do()
+		if err == nil {
+			threadID = msg.ThreadId
+			link.Title = headerValue(msg.Payload, "Subject")
+		} else {
+			thread, threadErr := svc.Users.Threads.Get("me", link.ID).Format("metadata").MetadataHeaders("Subject").Context(ctx).Do()
+			if threadErr != nil {
+				return threadErr
+			}
+			if len(thread.Messages) > 0 {
+				link.Title = headerValue(thread.Messages[0].Payload, "Subject")
+			}
+		}
+		link.ID = threadID
+		link.URL = fmt.Sprintf("https://mail.google.com/mail/?authuser=%s#all/%s", url.QueryEscape(account), threadID)
+	case linkKindEvent:
+		svc, err := newCalendarService(ctx, account)
+		if err != nil {
+			return err
+		}
+		ev, err := svc.Events.Get(calendarID, link.ID).Context(ctx).Do()
+		if err != nil {
+			return err
+		}
+		link.Title = ev.Summary
+		link.URL = ev.HtmlLink
+	case linkKindDrive:
+		svc, err := newDriveService(ctx, account)
+		if err != nil {
+			return err
+		}
+		f, err := svc.Files.Get(link.ID).SupportsAllDrives(true).Fields("id, name, webViewLink").Context(ctx).Do()
+		if err != nil {
+			return err
+		}
+		link.Title = f.Name
+		link.URL = f.WebViewLink
+		if link.URL == "" {
+			link.URL = fmt.Sprintf("https://drive.google.com/open?id=%s", url.QueryEscape(f.Id))
+		}
+	}
+	if link.URL == "" {
+		return fmt.Errorf("no web link for %s:%s", link.Kind, link.ID)
+	}
+	return nil
+}
+
+// linkSlug turns a title into a short mnemonic: "Q3 Plan (draft)" -> "q3-plan-draft".
+func linkSlug(title string) string {
+	title = strings.TrimSpace(title)
+	for _, prefix := range []string{"re:", "fwd:", "fw:"} {
+		for strings.HasPrefix(strings.ToLower(title), prefix) {
+			title = strings.TrimSpace(title[len(prefix):])
+		}
+	}
+	slug := strings.Trim(linkSlugRe.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if len(slug) > 40 {
+		slug = strings.TrimRight(slug[:40], "-")
+	}
+	return slug
+}
+
+type LinkListCmd struct{}
+
+func (c *LinkListCmd) Run(ctx context.Context) error {
+	u := ui.FromContext(ctx)
+	links, err := config.ListLinks()
+	if err != nil {
+		return err
+	}
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{"links": links})
+	}
+	if len(links) == 0 {
+		u.Err().Println("No links")
+		return nil
+	}
+	names := make([]string, 0, len(links))
+	for name := range links {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "NAME\tKIND\tTITLE\tURL")
+	for _, name := range names {
+		l := links[name]
+		title := l.Title
+		if title == "" {
+			title = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", name, l.Kind, sanitizeTab(title), l.URL)
+	}
+	return nil
+}
+
+type LinkDeleteCmd struct {
+	Name string `arg:"" name:"name" help:"Link name"`
+}
+
+func (c *LinkDeleteCmd) Run(ctx context.Context) error {
+	u := ui.FromContext(ctx)
+	deleted, err := config.DeleteLink(c.Name)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return usage("link not found")
+	}
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{"deleted": true, "name": config.NormalizeLinkName(c.Name)})
+	}
+	u.Out().Printf("deleted\t%s", config.NormalizeLinkName(c.Name))
+	return nil
+}
+
+type OpenCmd struct {
+	Name  string `arg:"" name:"name" help:"Link name (see gog link list)"`
+	Print bool   `name:"print" help:"Print the URL instead of opening a browser"`
+}
+
+func (c *OpenCmd) Run(ctx context.Context) error {
+	u := ui.FromContext(ctx)
+	link, ok, err := config.ResolveLink(c.Name)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return usagef("unknown link %q (see gog link list)", c.Name)
+	}
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{"name": config.NormalizeLinkName(c.Name), "link": link})
+	}
+	if c.Print {
+		u.Out().Println(link.URL)
+		return nil
+	}
+	if err := openLinkBrowser(link.URL); err != nil {
+		u.Err().Printf("Failed to open browser: %v", err)
+		u.Out().Println(link.URL)
+	}
+	return nil
+}
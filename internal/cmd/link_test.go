@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/mock"
+)
+
+func TestLinkCreateAndOpen(t *testing.T) {
+	s, err := mock.New(nil, mock.ServiceGmail, mock.ServiceCalendar)
+	if err != nil {
+		t.Fatalf("mock.New: %v", err)
+	}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	t.Setenv(googleapi.EnvAPIEndpoint, srv.URL)
+	t.Cleanup(func() {
+		for _, name := range []string{"quarterly-planning", "q1", "wiki"} {
+			_, _ = config.DeleteLink(name)
+		}
+	})
+
+	origOpen := openLinkBrowser
+	t.Cleanup(func() { openLinkBrowser = origOpen })
+	var opened string
+	openLinkBrowser = func(u string) error {
+		opened = u
+		return nil
+	}
+
+	run := func(args ...string) string {
+		t.Helper()
+		return captureStdout(t, func() {
+			_ = captureStderr(t, func() {
+				if err := Execute(append([]string{"--account", "test@example.com"}, args...)); err != nil {
+					t.Fatalf("Execute %v: %v", args, err)
+				}
+			})
+		})
+	}
+
+	// A message ID resolves to its thread; the name comes from the subject.
+	out := run("link", "create", "gmail:18c0a1b2c3d4e502")
+	if !strings.Contains(out, "name\tquarterly-planning") || !strings.Contains(out, "#all/18c0a1b2c3d4e501") {
+		t.Fatalf("unexpected create output: %q", out)
+	}
+	run("link", "create", "event:evt0001", "--name", "Q1")
+	run("link", "create", "https://wiki.example/q3", "--name", "wiki")
+
+	run("open", "q1")
+	if opened != "https://calendar.google.com/calendar/event?eid=evt0001" {
+		t.Fatalf("opened %q", opened)
+	}
+	if got := run("open", "wiki", "--print"); got != "https://wiki.example/q3\n" {
+		t.Fatalf("unexpected print: %q", got)
+	}
+
+	var listed struct {
+		Links map[string]config.Link `json:"links"`
+	}
+	if err := json.Unmarshal([]byte(run("--json", "link", "list")), &listed); err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	if len(listed.Links) != 3 || listed.Links["q1"].Title != "Q1 planning" || listed.Links["quarterly-planning"].Kind != linkKindGmail {
+		t.Fatalf("unexpected links: %#v", listed.Links)
+	}
+
+	if err := Execute([]string{"link", "create", "https://other.example", "--name", "wiki"}); err == nil || ExitCode(err) != 2 {
+		t.Fatalf("expected conflict usage error, got %v", err)
+	}
+	run("link", "delete", "wiki")
+	if err := Execute([]string{"open", "wiki"}); err == nil || ExitCode(err) != 2 {
+		t.Fatalf("expected unknown link error, got %v", err)
+	}
+}
+
+func TestLinkSlug(t *testing.T) {
+	cases := map[string]string{
+		"Re: Fwd: Q3 Plan (draft)": "q3-plan-draft",
+		"  Über  ":                 "ber",
+		"":                         "",
+	}
+	for in, want := range cases {
+		if got := linkSlug(in); got != want {
+			t.Fatalf("linkSlug(%q)=%q want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/internal/ui"
+	"github.com/steipete/gogcli/mock"
+)
+
+type MockCmd struct {
+	Serve MockServeCmd `cmd:"" name:"serve" help:"Serve fake Gmail/Calendar APIs backed by fixtures (no credentials needed)"`
+}
+
+type MockServeCmd struct {
+	Services string `name:"services" help:"Comma-separated services to fake: gmail,calendar" default:"gmail,calendar"`
+	Fixtures string `name:"fixtures" help:"Directory with gmail.json / calendar.json (default: built-in data)"`
+	Listen   string `name:"listen" help:"Listen address (host:port)" default:"127.0.0.1:8787"`
+}
+
+func (c *MockServeCmd) Run(ctx context.Context) error {
+	u := ui.FromContext(ctx)
+	services := splitCSV(c.Services)
+	if len(services) == 0 {
+		return usage("empty --services")
+	}
+	host, port, err := net.SplitHostPort(strings.TrimSpace(c.Listen))
+	if err != nil {
+		return usagef("invalid --listen %q: %v", c.Listen, err)
+	}
+	if p, convErr := strconv.Atoi(port); convErr != nil || p <= 0 {
+		return usagef("invalid --listen port %q", port)
+	}
+
+	dir := strings.TrimSpace(c.Fixtures)
+	if dir != "" {
+		if dir, err = config.ExpandPath(dir); err != nil {
+			return err
+		}
+	}
+	fixtures, err := mock.LoadFixtures(dir)
+	if err != nil {
+		return usagef("load fixtures: %v", err)
+	}
+	srv, err := mock.New(fixtures, services...)
+	if err != nil {
+		return usage(err.Error())
+	}
+
+	addr := net.JoinHostPort(host, port)
+	u.Err().Printf("mock: serving %s on http://%s", strings.Join(services, ","), addr)
+	u.Err().Printf("mock: export %s=http://%s", googleapi.EnvAPIEndpoint, addr)
+	return serveUntilInterrupted(ctx, &http.Server{
+		Addr:              addr,
+		Handler:           srv,
+		ReadHeaderTimeout: 5 * time.Second,
+	})
+}
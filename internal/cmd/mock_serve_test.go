@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/googleapi"
+)
+
+func TestMockServe_DrivesRealCommands(t *testing.T) {
+	origListen := listenAndServe
+	t.Cleanup(func() { listenAndServe = origListen })
+
+	var served *http.Server
+	listenAndServe = func(s *http.Server) error {
+		served = s
+		return nil
+	}
+	stderr := captureStderr(t, func() {
+		if err := Execute([]string{"mock", "serve", "--services", "gmail,calendar", "--listen", "127.0.0.1:9998"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	if served == nil || served.Addr != "127.0.0.1:9998" {
+		t.Fatalf("unexpected server: %#v", served)
+	}
+	if !strings.Contains(stderr, googleapi.EnvAPIEndpoint+"=http://127.0.0.1:9998") {
+		t.Fatalf("missing endpoint hint: %q", stderr)
+	}
+
+	srv := httptest.NewServer(served.Handler)
+	defer srv.Close()
+	t.Setenv(googleapi.EnvAPIEndpoint, srv.URL)
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "test@example.com", "gmail", "search", "in:inbox", "is:unread"}); err != nil {
+				t.Fatalf("gmail search: %v", err)
+			}
+		})
+	})
+	var parsed struct {
+		Threads []struct {
+			ID      string `json:"id"`
+			Subject string `json:"subject"`
+		} `json:"threads"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if len(parsed.Threads) != 2 || parsed.Threads[0].Subject != "Launch checklist" {
+		t.Fatalf("unexpected threads: %#v", parsed.Threads)
+	}
+
+	out = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "test@example.com", "calendar", "events", "primary", "--from", "2026-01-08T00:00:00Z", "--to", "2026-01-09T00:00:00Z"}); err != nil {
+				t.Fatalf("calendar events: %v", err)
+			}
+		})
+	})
+	if !strings.Contains(out, "Q1 planning") {
+		t.Fatalf("expected fixture event, got %s", out)
+	}
+}
+
+func TestMockServe_RejectsUnknownService(t *testing.T) {
+	_ = captureStderr(t, func() {
+		err := Execute([]string{"mock", "serve", "--services", "drive"})
+		if err == nil || ExitCode(err) != 2 {
+			t.Fatalf("expected usage error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/objectstore"
+)
+
+// openObjectWriter opens the bucket of a gs:// or s3:// destination.
+var openObjectWriter = objectstore.Open
+
+// writeToOutput runs write with the local output path. When out is an
+// object storage URL, write gets a temporary path instead and the file it
+// produced is uploaded; the returned location is then the object's URL. A
+// destination ending in "/" keeps the file's own name.
+func writeToOutput(ctx context.Context, out string, write func(localOut string) (string, int64, error)) (string, int64, error) {
+	loc, remote, err := objectstore.Parse(out)
+	if err != nil {
+		return "", 0, usage(err.Error())
+	}
+	if !remote {
+		return write(out)
+	}
+
+	tmp, err := os.MkdirTemp("", "gog-upload-")
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.RemoveAll(tmp)
+
+	localOut := tmp
+	keyDir := strings.TrimSuffix(loc.Key, "/")
+	if !loc.IsPrefix() {
+		localOut = filepath.Join(tmp, path.Base(loc.Key))
+		keyDir = path.Dir(loc.Key)
+		if keyDir == "." {
+			keyDir = ""
+		}
+	}
+	written, _, err := write(localOut)
+	if err != nil {
+		return "", 0, err
+	}
+	// The export may change the extension (e.g. report.docx -> report.pdf).
+	loc.Key = path.Join(keyDir, filepath.Base(written))
+
+	f, err := os.Open(written) //nolint:gosec // file we just wrote
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		return "", 0, err
+	}
+	w, err := openObjectWriter(ctx, loc)
+	if err != nil {
+		return "", 0, err
+	}
+	if err := w.Put(ctx, loc.Key, f, st.Size(), objectContentType(written)); err != nil {
+		return "", 0, fmt.Errorf("upload %s: %w", loc, err)
+	}
+	return loc.String(), st.Size(), nil
+}
+
+func objectContentType(name string) string {
+	if contentType := mime.TypeByExtension(filepath.Ext(name)); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+// outputDir is the directory form of writeToOutput, for exports that write
+// a tree of files (maildir, knowledge corpus). For a gs:// or s3://
+// destination, Local is a temporary directory and Finish uploads every file
+// in it below the destination, keeping relative paths. Remote exports start
+// from an empty directory, so they are full exports rather than incremental.
+type outputDir struct {
+	Local  string
+	loc    objectstore.Location
+	remote bool
+}
+
+func openOutputDir(out string) (*outputDir, error) {
+	loc, remote, err := objectstore.Parse(out)
+	if err != nil {
+		return nil, usage(err.Error())
+	}
+	if !remote {
+		local, expandErr := config.ExpandPath(out)
+		if expandErr != nil {
+			return nil, expandErr
+		}
+		return &outputDir{Local: local}, nil
+	}
+	tmp, err := os.MkdirTemp("", "gog-upload-")
+	if err != nil {
+		return nil, err
+	}
+	if loc.Key != "" && !loc.IsPrefix() {
+		loc.Key += "/"
+	}
+	return &outputDir{Local: tmp, loc: loc, remote: true}, nil
+}
+
+// Finish uploads a remote destination and returns where the output is.
+func (d *outputDir) Finish(ctx context.Context) (string, error) {
+	if !d.remote {
+		return d.Local, nil
+	}
+	w, err := openObjectWriter(ctx, d.loc)
+	if err != nil {
+		return "", err
+	}
+	err = filepath.WalkDir(d.Local, func(p string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil || entry.IsDir() {
+			return walkErr
+		}
+		rel, relErr := filepath.Rel(d.Local, p)
+		if relErr != nil {
+			return relErr
+		}
+		key := d.loc.Key + filepath.ToSlash(rel)
+		f, openErr := os.Open(p) //nolint:gosec // file under our temp dir
+		if openErr != nil {
+			return openErr
+		}
+		defer f.Close()
+		st, statErr := f.Stat()
+		if statErr != nil {
+			return statErr
+		}
+		if putErr := w.Put(ctx, key, f, st.Size(), objectContentType(p)); putErr != nil {
+			return fmt.Errorf("upload %s://%s/%s: %w", d.loc.Scheme, d.loc.Bucket, key, putErr)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return d.loc.String(), nil
+}
+
+// Close removes the temporary directory of a remote destination.
+func (d *outputDir) Close() {
+	if d.remote {
+		_ = os.RemoveAll(d.Local)
+	}
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/objectstore"
+)
+
+type fakeObjectWriter struct {
+	bucket string
+	puts   map[string]string
+}
+
+func (w *fakeObjectWriter) Put(_ context.Context, key string, body io.ReadSeeker, _ int64, _ string) error {
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	w.puts[w.bucket+"/"+key] = string(b)
+	return nil
+}
+
+func TestWriteToOutput(t *testing.T) {
+	fake := &fakeObjectWriter{puts: map[string]string{}}
+	orig := openObjectWriter
+	t.Cleanup(func() { openObjectWriter = orig })
+	openObjectWriter = func(_ context.Context, loc objectstore.Location) (objectstore.Writer, error) {
+		fake.bucket = loc.Scheme + "://" + loc.Bucket
+		return fake, nil
+	}
+
+	// Like a Drive export: the format decides the extension, a directory
+	// destination gets a default name.
+	export := func(out string) (string, int64, error) {
+		dest := out
+		if st, err := os.Stat(out); err == nil && st.IsDir() {
+			dest = filepath.Join(out, "id1_Doc")
+		}
+		dest = replaceExt(dest, ".pdf")
+		return dest, 3, os.WriteFile(dest, []byte("pdf"), 0o600)
+	}
+
+	for _, tc := range []struct{ out, want string }{
+		{"gs://bucket/backups/report.docx", "gs://bucket/backups/report.pdf"},
+		{"s3://bucket/backups/", "s3://bucket/backups/id1_Doc.pdf"},
+		{"gs://bucket/report", "gs://bucket/report.pdf"},
+	} {
+		got, size, err := writeToOutput(context.Background(), tc.out, export)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.out, err)
+		}
+		if got != tc.want || size != 3 || fake.puts[tc.want] != "pdf" {
+			t.Fatalf("%s: got %q (%d), puts %v", tc.out, got, size, fake.puts)
+		}
+	}
+
+	local := filepath.Join(t.TempDir(), "report")
+	got, _, err := writeToOutput(context.Background(), local, export)
+	if err != nil || got != local+".pdf" {
+		t.Fatalf("local: %q %v", got, err)
+	}
+	if len(fake.puts) != 3 {
+		t.Fatalf("local output was uploaded: %v", fake.puts)
+	}
+
+	if _, _, err := writeToOutput(context.Background(), "s3:///key", export); err == nil || ExitCode(err) != 2 {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+}
+
+func TestOutputDir(t *testing.T) {
+	fake := &fakeObjectWriter{puts: map[string]string{}}
+	orig := openObjectWriter
+	t.Cleanup(func() { openObjectWriter = orig })
+	openObjectWriter = func(_ context.Context, loc objectstore.Location) (objectstore.Writer, error) {
+		fake.bucket = loc.Scheme + "://" + loc.Bucket
+		return fake, nil
+	}
+
+	out, err := openOutputDir("s3://bucket/mail")
+	if err != nil {
+		t.Fatalf("openOutputDir: %v", err)
+	}
+	tmp := out.Local
+	if err := os.MkdirAll(filepath.Join(tmp, "cur"), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "cur", "1.eml"), []byte("hi"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	got, err := out.Finish(context.Background())
+	if err != nil || got != "s3://bucket/mail/" {
+		t.Fatalf("Finish: %q %v", got, err)
+	}
+	if fake.puts["s3://bucket/mail/cur/1.eml"] != "hi" {
+		t.Fatalf("unexpected uploads: %v", fake.puts)
+	}
+	out.Close()
+	if _, err := os.Stat(tmp); !os.IsNotExist(err) {
+		t.Fatalf("temp dir not removed: %v", err)
+	}
+
+	local := t.TempDir()
+	out, err = openOutputDir(local)
+	if err != nil || out.Local != local {
+		t.Fatalf("local: %#v %v", out, err)
+	}
+	if got, err := out.Finish(context.Background()); err != nil || got != local || len(fake.puts) != 1 {
+		t.Fatalf("local output was uploaded: %q %v %v", got, err, fake.puts)
+	}
+	out.Close()
+	if _, err := os.Stat(local); err != nil {
+		t.Fatalf("local dir removed: %v", err)
+	}
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/steipete/gogcli/internal/errfmt"
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// preflightStatusURL is the Google Workspace Status Dashboard incident feed.
+var preflightStatusURL = "https://www.google.com/appsstatus/dashboard/incidents.json"
+
+const preflightStatusTimeout = 5 * time.Second
+
+type preflightCheck struct {
+	// product is the Workspace Status Dashboard product title.
+	product string
+	probe   func(ctx context.Context, account string) error
+}
+
+// preflightChecks maps top-level commands to a cheap read against their API.
+// Commands without an entry (auth, config, time, ...) skip the preflight.
+var preflightChecks = map[string]preflightCheck{
+	"gmail": {product: "Gmail", probe: func(ctx context.Context, account string) error {
+		svc, err := newGmailService(ctx, account)
+		if err != nil {
+			return err
+		}
+		_, err = svc.Users.GetProfile("me").Context(ctx).Do()
+		return err
+	}},
+	"calendar": {product: "Google Calendar", probe: probeCalendar},
+	"join":     {product: "Google Calendar", probe: probeCalendar},
+	"drive":    {product: "Google Drive", probe: probeDrive},
+	"quota":    {product: "Google Drive", probe: probeDrive},
+	"share":    {product: "Google Drive", probe: probeDrive},
+	"docs":     {product: "Google Docs", probe: probeDrive},
+	"sheets":   {product: "Google Sheets", probe: probeDrive},
+	"slides":   {product: "Google Slides", probe: probeDrive},
+	"tasks": {product: "Google Tasks", probe: func(ctx context.Context, account string) error {
+		svc, err := newTasksService(ctx, account)
+		if err != nil {
+			return err
+		}
+		_, err = svc.Tasklists.List().MaxResults(1).Context(ctx).Do()
+		return err
+	}},
+	"contacts": {product: "Google Contacts", probe: func(ctx context.Context, account string) error {
+		svc, err := newPeopleContactsService(ctx, account)
+		if err != nil {
+			return err
+		}
+		_, err = svc.People.Connections.List("people/me").PersonFields("names").PageSize(1).Context(ctx).Do()
+		return err
+	}},
+	"groups": {product: "Google Groups", probe: func(ctx context.Context, account string) error {
+		svc, err := newCloudIdentityService(ctx, account)
+		if err != nil {
+			return err
+		}
+		_, err = svc.Groups.Memberships.SearchTransitiveGroups("groups/-").
+			Query(cloudIdentityMemberQuery(account)).PageSize(1).Context(ctx).Do()
+		return err
+	}},
+}
+
+func probeCalendar(ctx context.Context, account string) error {
+	svc, err := newCalendarService(ctx, account)
+	if err != nil {
+		return err
+	}
+	_, err = svc.CalendarList.List().MaxResults(1).Context(ctx).Do()
+	return err
+}
+
+func probeDrive(ctx context.Context, account string) error {
+	svc, err := newDriveService(ctx, account)
+	if err != nil {
+		return err
+	}
+	_, err = svc.About.Get().Fields("user").Context(ctx).Do()
+	return err
+}
+
+// runPreflight checks the Workspace status dashboard and makes one cheap API
+// call for the selected command before it runs. Ongoing outages and failing
+// probes abort; partial disruptions only warn.
+func runPreflight(ctx context.Context, kctx *kong.Context, flags *RootFlags) error {
+	fields := strings.Fields(kctx.Command())
+	if len(fields) == 0 {
+		return nil
+	}
+	check, ok := preflightChecks[fields[0]]
+	if !ok {
+		return nil
+	}
+	account, err := requireAccount(flags)
+	if err != nil {
+		// The command reports the missing account itself.
+		return nil //nolint:nilerr
+	}
+	u := ui.FromContext(ctx)
+
+	// The dashboard says nothing about a local endpoint override (gog mock).
+	if strings.TrimSpace(os.Getenv(googleapi.EnvAPIEndpoint)) == "" {
+		incidents, statusErr := fetchWorkspaceIncidents(ctx, check.product)
+		if statusErr != nil {
+			slog.Debug("preflight status check failed", "err", statusErr)
+		}
+		for _, inc := range incidents {
+			if inc.StatusImpact == "SERVICE_OUTAGE" {
+				return errfmt.NewUserFacingError(fmt.Sprintf("preflight: %s has an ongoing outage: %s (%s); not starting", check.product, inc.ExternalDesc, inc.link()), nil)
+			}
+			u.Err().Printf("preflight: %s has an ongoing disruption: %s (%s)", check.product, inc.ExternalDesc, inc.link())
+		}
+	}
+
+	start := time.Now()
+	if err := check.probe(ctx, account); err != nil {
+		return fmt.Errorf("preflight: %s API check failed, not starting: %w", check.product, err)
+	}
+	slog.Debug("preflight ok", "product", check.product, "latency", time.Since(start))
+	return nil
+}
+
+type workspaceIncident struct {
+	ExternalDesc     string `json:"external_desc"`
+	End              string `json:"end"`
+	StatusImpact     string `json:"status_impact"`
+	URI              string `json:"uri"`
+	ServiceName      string `json:"service_name"`
+	AffectedProducts []struct {
+		Title string `json:"title"`
+	} `json:"affected_products"`
+}
+
+func (i workspaceIncident) link() string {
+	if strings.HasPrefix(i.URI, "http") {
+		return i.URI
+	}
+	return "https://www.google.com/appsstatus/dashboard/" + strings.TrimPrefix(i.URI, "/")
+}
+
+func (i workspaceIncident) affects(product string) bool {
+	if strings.EqualFold(i.ServiceName, product) {
+		return true
+	}
+	for _, p := range i.AffectedProducts {
+		if strings.EqualFold(p.Title, product) {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchWorkspaceIncidents returns unresolved dashboard incidents for product.
+func fetchWorkspaceIncidents(ctx context.Context, product string) ([]workspaceIncident, error) {
+	ctx, cancel := context.WithTimeout(ctx, preflightStatusTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, preflightStatusURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status dashboard: %s", resp.Status)
+	}
+	var all []workspaceIncident
+	if err := json.NewDecoder(resp.Body).Decode(&all); err != nil {
+		return nil, fmt.Errorf("status dashboard: %w", err)
+	}
+	var out []workspaceIncident
+	for _, inc := range all {
+		if inc.End == "" && inc.affects(product) {
+			out = append(out, inc)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/googleapi"
+)
+
+func TestPreflight(t *testing.T) {
+	t.Setenv(googleapi.EnvAPIEndpoint, "")
+
+	incidents := []map[string]any{}
+	status := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(incidents)
+	}))
+	t.Cleanup(status.Close)
+	origURL := preflightStatusURL
+	t.Cleanup(func() { preflightStatusURL = origURL })
+	preflightStatusURL = status.URL
+
+	profileStatus := http.StatusOK
+	labelCalls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/gmail/v1")
+		w.Header().Set("Content-Type", "application/json")
+		switch path {
+		case "/users/me/profile":
+			w.WriteHeader(profileStatus)
+			_ = json.NewEncoder(w).Encode(map[string]any{"emailAddress": "a@b.com"})
+		case "/users/me/labels":
+			labelCalls++
+			_ = json.NewEncoder(w).Encode(map[string]any{"labels": []any{}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	stubGmailService(t, srv)
+
+	run := func() (string, error) {
+		var err error
+		errOut := captureStderr(t, func() {
+			_ = captureStdout(t, func() {
+				err = Execute([]string{"--preflight", "--account", "a@b.com", "gmail", "labels", "list"})
+			})
+		})
+		return errOut, err
+	}
+
+	// Resolved incidents and other products are ignored; disruptions warn.
+	incidents = []map[string]any{
+		{"external_desc": "Old outage", "end": "2026-01-01T00:00:00+00:00", "status_impact": "SERVICE_OUTAGE", "service_name": "Gmail"},
+		{"external_desc": "Drive is down", "status_impact": "SERVICE_OUTAGE", "service_name": "Google Drive"},
+		{"external_desc": "Slow delivery", "status_impact": "SERVICE_DISRUPTION", "uri": "incidents/abc", "affected_products": []map[string]any{{"title": "Gmail"}}},
+	}
+	errOut, err := run()
+	if err != nil || labelCalls != 1 {
+		t.Fatalf("expected command to run: err=%v calls=%d", err, labelCalls)
+	}
+	if !strings.Contains(errOut, "Slow delivery") || !strings.Contains(errOut, "dashboard/incidents/abc") {
+		t.Fatalf("missing disruption warning: %q", errOut)
+	}
+
+	incidents = append(incidents, map[string]any{"external_desc": "Gmail unavailable", "status_impact": "SERVICE_OUTAGE", "service_name": "Gmail"})
+	if errOut, err = run(); err == nil || !strings.Contains(errOut, "Gmail unavailable") || labelCalls != 1 {
+		t.Fatalf("expected outage abort: err=%v calls=%d out=%q", err, labelCalls, errOut)
+	}
+
+	incidents = nil
+	profileStatus = http.StatusServiceUnavailable
+	if _, err = run(); err == nil || !strings.Contains(err.Error(), "preflight: Gmail API check failed") || labelCalls != 1 {
+		t.Fatalf("expected probe abort: err=%v calls=%d", err, labelCalls)
+	}
+}
@@ -0,0 +1,344 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// projectPropPrefix marks events with a private extended property
+// gogProject.<name>=1, so `calendar events --private-prop-filter` and
+// `gog search --project` can find them.
+const projectPropPrefix = "gogProject."
+
+type ProjectsCmd struct {
+	List  ProjectsListCmd  `cmd:"" name:"list" help:"List projects from config"`
+	Apply ProjectsApplyCmd `cmd:"" name:"apply" help:"Label threads and tag events that match each project's keywords or participants"`
+}
+
+type ProjectsListCmd struct{}
+
+func (c *ProjectsListCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	projects, err := config.ProjectsFor(account)
+	if err != nil {
+		return err
+	}
+	if outfmt.IsJSON(ctx) {
+		if projects == nil {
+			projects = []config.Project{}
+		}
+		return outfmt.WriteJSON(os.Stdout, map[string]any{"projects": projects})
+	}
+	if len(projects) == 0 {
+		u.Err().Println("No projects (add a projects entry to config.json)")
+		return nil
+	}
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "NAME\tLABEL\tKEYWORDS\tPARTICIPANTS")
+	for _, p := range projects {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", p.Name, p.LabelName(), orEmpty(strings.Join(p.Keywords, ", "), "-"), orEmpty(strings.Join(p.Participants, ", "), "-"))
+	}
+	return nil
+}
+
+type ProjectsApplyCmd struct {
+	Since      SinceTime   `name:"since" help:"Look back this far (e.g. 7d, 2w) or since a date (YYYY-MM-DD)" default:"7d"`
+	Ahead      DayDuration `name:"ahead" help:"Also tag upcoming events this far ahead" default:"30d"`
+	Project    string      `name:"project" help:"Only apply this project"`
+	CalendarID string      `name:"calendar" help:"Calendar ID" default:"primary"`
+	Max        int64       `name:"max" aliases:"limit" help:"Max threads per project" default:"500"`
+	DryRun     bool        `name:"dry-run" help:"Only show what would be labeled"`
+}
+
+// projectChange is one thread labeled or event tagged.
+type projectChange struct {
+	Project string `json:"project"`
+	Service string `json:"service"`
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+}
+
+func (c *ProjectsApplyCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	projects, err := selectProjects(account, c.Project)
+	if err != nil {
+		return err
+	}
+
+	gsvc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+	csvc, err := newCalendarService(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	since := c.Since.Time(now)
+	var changes []projectChange
+	for _, p := range projects {
+		if interrupted(ctx) {
+			break
+		}
+		threads, err := c.labelThreads(ctx, gsvc, p, since)
+		if err != nil {
+			return fmt.Errorf("project %s: %w", p.Name, err)
+		}
+		changes = append(changes, threads...)
+		events, err := c.tagEvents(ctx, csvc, p, since, now.Add(c.Ahead.Duration()))
+		if err != nil {
+			return fmt.Errorf("project %s: %w", p.Name, err)
+		}
+		changes = append(changes, events...)
+	}
+
+	if outfmt.IsJSON(ctx) {
+		if changes == nil {
+			changes = []projectChange{}
+		}
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"changes":     changes,
+			"dryRun":      c.DryRun,
+			"interrupted": interrupted(ctx),
+		})
+	}
+	if len(changes) == 0 {
+		u.Err().Println("Nothing new to label")
+		return nil
+	}
+	w, flush := tableWriter(ctx)
+	fmt.Fprintln(w, "PROJECT\tSERVICE\tID\tTITLE")
+	for _, ch := range changes {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", ch.Project, ch.Service, ch.ID, sanitizeTab(ch.Title))
+	}
+	flush()
+	if c.DryRun {
+		u.Err().Printf("Dry run: would label %d item(s)", len(changes))
+	} else {
+		u.Err().Printf("Labeled %d item(s)", len(changes))
+	}
+	return nil
+}
+
+func selectProjects(account, only string) ([]config.Project, error) {
+	projects, err := config.ProjectsFor(account)
+	if err != nil {
+		return nil, err
+	}
+	only = strings.TrimSpace(only)
+	var out []config.Project
+	for _, p := range projects {
+		if strings.TrimSpace(p.Name) == "" {
+			return nil, usage("projects: every entry needs a name")
+		}
+		if only != "" && !strings.EqualFold(p.Name, only) {
+			continue
+		}
+		if len(p.Keywords) == 0 && len(p.Participants) == 0 {
+			return nil, usagef("project %s: needs keywords or participants", p.Name)
+		}
+		out = append(out, p)
+	}
+	if len(out) == 0 {
+		if only != "" {
+			return nil, usagef("no project named %q in config", only)
+		}
+		return nil, usage("no projects configured: add a projects entry to config.json")
+	}
+	return out, nil
+}
+
+// labelThreads adds the project label to matching threads that lack it.
+func (c *ProjectsApplyCmd) labelThreads(ctx context.Context, svc *gmail.Service, p config.Project, since time.Time) ([]projectChange, error) {
+	query := fmt.Sprintf("after:%d -label:%s %s", since.Unix(), gmailLabelQuery(p.LabelName()), projectMailQuery(p))
+	var threads []*gmail.Thread
+	pageToken := ""
+	for int64(len(threads)) < c.Max {
+		call := svc.Users.Threads.List("me").Q(query).MaxResults(min(c.Max-int64(len(threads)), 500)).Context(ctx)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return nil, err
+		}
+		threads = append(threads, resp.Threads...)
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+	if len(threads) == 0 {
+		return nil, nil
+	}
+
+	labelID := ""
+	if !c.DryRun {
+		id, err := ensureLabelID(ctx, svc, p.LabelName())
+		if err != nil {
+			return nil, err
+		}
+		labelID = id
+	}
+	var out []projectChange
+	for _, t := range threads {
+		if t == nil || t.Id == "" {
+			continue
+		}
+		if interrupted(ctx) {
+			break
+		}
+		if !c.DryRun {
+			if _, err := svc.Users.Threads.Modify("me", t.Id, &gmail.ModifyThreadRequest{AddLabelIds: []string{labelID}}).Context(ctx).Do(); err != nil {
+				return out, fmt.Errorf("label thread %s: %w", t.Id, err)
+			}
+		}
+		out = append(out, projectChange{Project: p.Name, Service: "gmail", ID: t.Id, Title: truncateRunes(html.UnescapeString(t.Snippet), 60)})
+	}
+	return out, nil
+}
+
+// projectMailQuery ORs the project's keywords and participants.
+func projectMailQuery(p config.Project) string {
+	var terms []string
+	for _, kw := range p.Keywords {
+		if kw = strings.TrimSpace(kw); kw != "" {
+			terms = append(terms, `"`+strings.ReplaceAll(kw, `"`, "")+`"`)
+		}
+	}
+	for _, who := range p.Participants {
+		if who = strings.TrimSpace(who); who != "" {
+			terms = append(terms, "from:"+who, "to:"+who, "cc:"+who)
+		}
+	}
+	return "{" + strings.Join(terms, " ") + "}"
+}
+
+// gmailLabelQuery is how Gmail search spells a label name: lowercase, with
+// spaces and nesting slashes as dashes.
+func gmailLabelQuery(name string) string {
+	return strings.NewReplacer(" ", "-", "/", "-").Replace(strings.ToLower(strings.TrimSpace(name)))
+}
+
+// tagEvents marks matching events with the project's private extended
+// property. Recurring events are tagged on the series.
+func (c *ProjectsApplyCmd) tagEvents(ctx context.Context, svc *calendar.Service, p config.Project, from, to time.Time) ([]projectChange, error) {
+	key := projectPropPrefix + p.Name
+	seen := map[string]bool{}
+	var out []projectChange
+	pageToken := ""
+	for {
+		call := svc.Events.List(c.CalendarID).
+			TimeMin(from.Format(time.RFC3339)).
+			TimeMax(to.Format(time.RFC3339)).
+			SingleEvents(true).
+			MaxResults(2500).
+			Context(ctx)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range resp.Items {
+			if e == nil || e.Status == "cancelled" || !eventMatchesProject(e, p) {
+				continue
+			}
+			if e.ExtendedProperties != nil && e.ExtendedProperties.Private[key] != "" {
+				continue
+			}
+			id := e.Id
+			if e.RecurringEventId != "" {
+				id = e.RecurringEventId
+			}
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			if interrupted(ctx) {
+				return out, nil
+			}
+			if !c.DryRun {
+				private := map[string]string{key: "1"}
+				if e.ExtendedProperties != nil {
+					for k, v := range e.ExtendedProperties.Private {
+						private[k] = v
+					}
+				}
+				patch := &calendar.Event{ExtendedProperties: &calendar.EventExtendedProperties{Private: private}}
+				if _, err := svc.Events.Patch(c.CalendarID, id, patch).Context(ctx).Do(); err != nil {
+					return out, fmt.Errorf("tag event %s: %w", id, err)
+				}
+			}
+			out = append(out, projectChange{Project: p.Name, Service: "calendar", ID: id, Title: e.Summary})
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+	return out, nil
+}
+
+func eventMatchesProject(e *calendar.Event, p config.Project) bool {
+	text := strings.ToLower(e.Summary + "\n" + e.Description)
+	for _, kw := range p.Keywords {
+		if kw = strings.ToLower(strings.TrimSpace(kw)); kw != "" && strings.Contains(text, kw) {
+			return true
+		}
+	}
+	emails := make([]string, 0, len(e.Attendees)+1)
+	if e.Organizer != nil && !e.Organizer.Self {
+		emails = append(emails, e.Organizer.Email)
+	}
+	for _, a := range e.Attendees {
+		if a != nil && !a.Self {
+			emails = append(emails, a.Email)
+		}
+	}
+	for _, who := range p.Participants {
+		for _, email := range emails {
+			if participantMatches(email, who) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// participantMatches compares an address with a participant entry: a full
+// address, or @domain (subdomains included).
+func participantMatches(email, who string) bool {
+	email = strings.ToLower(strings.TrimSpace(email))
+	who = strings.ToLower(strings.TrimSpace(who))
+	if email == "" || who == "" {
+		return false
+	}
+	if domain, ok := strings.CutPrefix(who, "@"); ok {
+		_, host, _ := strings.Cut(email, "@")
+		return host == domain || strings.HasSuffix(host, "."+domain)
+	}
+	return email == who
+}
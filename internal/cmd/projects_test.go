@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+
+	"github.com/steipete/gogcli/internal/config"
+)
+
+func setupProjectsTest(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg-config"))
+	if err := config.WriteConfig(config.File{Projects: []config.Project{
+		{Name: "falcon", Keywords: []string{"Falcon"}, Participants: []string{"@falcon.example"}},
+	}}); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	stubGmailService(t, srv)
+	origCal := newCalendarService
+	t.Cleanup(func() { newCalendarService = origCal })
+	newCalendarService = func(ctx context.Context, _ string) (*calendar.Service, error) {
+		return calendar.NewService(ctx, option.WithoutAuthentication(), option.WithHTTPClient(srv.Client()), option.WithEndpoint(srv.URL+"/"))
+	}
+}
+
+func TestProjectsApply(t *testing.T) {
+	var modified []string
+	patched := map[string]*calendar.Event{}
+	setupProjectsTest(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/users/me/threads") && r.Method == http.MethodGet:
+			q := r.URL.Query().Get("q")
+			if !strings.Contains(q, "-label:projects-falcon") || !strings.Contains(q, `{"Falcon" from:@falcon.example to:@falcon.example cc:@falcon.example}`) {
+				t.Errorf("query = %q", q)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"threads": []map[string]any{{"id": "t1", "snippet": "Falcon launch &amp; plan"}}})
+		case strings.HasSuffix(r.URL.Path, "/users/me/labels") && r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]any{"labels": []map[string]any{}})
+		case strings.HasSuffix(r.URL.Path, "/users/me/labels") && r.Method == http.MethodPost:
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "Label_9", "name": "Projects/falcon"})
+		case strings.HasSuffix(r.URL.Path, "/users/me/threads/t1/modify"):
+			var req gmail.ModifyThreadRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			modified = append(modified, req.AddLabelIds...)
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "t1"})
+		case strings.HasSuffix(r.URL.Path, "/calendars/primary/events") && r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]any{"items": []map[string]any{
+				{"id": "e1_20261001", "recurringEventId": "e1", "summary": "Weekly sync", "attendees": []map[string]any{{"email": "pm@eu.falcon.example"}}},
+				{"id": "e1_20261008", "recurringEventId": "e1", "summary": "Weekly sync", "attendees": []map[string]any{{"email": "pm@eu.falcon.example"}}},
+				{"id": "e2", "summary": "Falcon review", "extendedProperties": map[string]any{"private": map[string]string{"gogProject.falcon": "1"}}},
+				{"id": "e3", "summary": "Lunch"},
+			}})
+		case strings.Contains(r.URL.Path, "/calendars/primary/events/") && r.Method == http.MethodPatch:
+			var ev calendar.Event
+			_ = json.NewDecoder(r.Body).Decode(&ev)
+			patched[r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]] = &ev
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "e1"})
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	})
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--json", "--account", "me@example.com", "projects", "apply", "--since", "7d"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	var res struct {
+		Changes []projectChange `json:"changes"`
+	}
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if len(res.Changes) != 2 || res.Changes[0].Title != "Falcon launch & plan" || res.Changes[1].ID != "e1" {
+		t.Fatalf("changes = %+v", res.Changes)
+	}
+	if len(modified) != 1 || modified[0] != "Label_9" {
+		t.Fatalf("modified = %v", modified)
+	}
+	if len(patched) != 1 || patched["e1"] == nil || patched["e1"].ExtendedProperties.Private["gogProject.falcon"] != "1" {
+		t.Fatalf("patched = %v", patched)
+	}
+}
+
+func TestSearchProject(t *testing.T) {
+	setupProjectsTest(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/users/me/threads"):
+			if q := r.URL.Query().Get("q"); !strings.Contains(q, "label:projects-falcon budget") {
+				t.Errorf("query = %q", q)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"threads": []map[string]any{{"id": "t1"}}})
+		case strings.HasSuffix(r.URL.Path, "/users/me/threads/t1"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "t1", "messages": []map[string]any{{
+				"id": "m1", "internalDate": "1788000000000",
+				"payload": map[string]any{"headers": []map[string]string{{"name": "Subject", "value": "Falcon budget"}}},
+			}}})
+		case strings.HasSuffix(r.URL.Path, "/calendars/primary/events"):
+			if got := r.URL.Query().Get("privateExtendedProperty"); got != "gogProject.falcon=1" || r.URL.Query().Get("q") != "budget" {
+				t.Errorf("event filter = %q q=%q", got, r.URL.Query().Get("q"))
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"items": []map[string]any{{"id": "e1", "summary": "Budget review", "start": map[string]string{"date": "2026-10-01"}}}})
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	})
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--json", "--account", "me@example.com", "search", "--project", "falcon", "budget"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	var res map[string][]searchHit
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if len(res["threads"]) != 1 || res["threads"][0].Title != "Falcon budget" || len(res["events"]) != 1 || res["events"][0].Date != "2026-10-01" {
+		t.Fatalf("result = %+v", res)
+	}
+}
+
+func TestSearchEvents_KeepsMostRecent(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Query().Get("timeMax") == "" {
+			t.Errorf("expected the window to end at now")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("pageToken") == "" {
+			_ = json.NewEncoder(w).Encode(map[string]any{"nextPageToken": "p2", "items": []map[string]any{
+				{"id": "e1", "start": map[string]string{"date": "2026-01-01"}},
+				{"id": "e2", "start": map[string]string{"date": "2026-02-01"}},
+			}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"items": []map[string]any{
+			{"id": "e3", "start": map[string]string{"dateTime": "2026-03-01T10:00:00Z"}},
+			{"id": "e4", "status": "cancelled"},
+		}})
+	}))
+	defer srv.Close()
+	svc, err := calendar.NewService(context.Background(), option.WithoutAuthentication(), option.WithHTTPClient(srv.Client()), option.WithEndpoint(srv.URL+"/"))
+	if err != nil {
+		t.Fatalf("calendar.NewService: %v", err)
+	}
+
+	now := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	hits, err := searchEvents(context.Background(), svc, "primary", "", "", now.AddDate(-1, 0, 0), now, 2)
+	if err != nil {
+		t.Fatalf("searchEvents: %v", err)
+	}
+	if calls != 2 || len(hits) != 2 || hits[0].ID != "e3" || hits[0].Date != "2026-03-01" || hits[1].ID != "e2" {
+		t.Fatalf("expected the two newest events across pages, got %+v after %d calls", hits, calls)
+	}
+}
@@ -0,0 +1,315 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/events"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+const (
+	quotaNotifyDesktop = "desktop"
+	quotaNotifyEmail   = "email"
+	quotaNotifyNone    = "none"
+)
+
+// desktopNotify shows a desktop notification with the platform's notifier.
+var desktopNotify = func(title, message string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %q with title %q", message, title)
+		cmd = exec.Command("osascript", "-e", script) //nolint:gosec // fixed program, quoted args
+	case "linux", "freebsd", "openbsd", "netbsd":
+		cmd = exec.Command("notify-send", "--app-name=gog", title, message) //nolint:gosec // fixed program
+	default:
+		return fmt.Errorf("desktop notifications are not supported on %s", runtime.GOOS)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", cmd.Path, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+type QuotaCmd struct {
+	Show  QuotaShowCmd  `cmd:"" name:"show" default:"withargs" help:"Show storage usage by service"`
+	Watch QuotaWatchCmd `cmd:"" name:"watch" help:"Check storage usage periodically and alert when it crosses a threshold"`
+}
+
+// storageUsage is the account's storage quota from the Drive API. Gmail and
+// Photos are not reported separately, so they share one bucket.
+type storageUsage struct {
+	Limit          int64   `json:"limit"`
+	Usage          int64   `json:"usage"`
+	Drive          int64   `json:"drive"`
+	DriveTrash     int64   `json:"driveTrash"`
+	GmailAndPhotos int64   `json:"gmailAndPhotos"`
+	Percent        float64 `json:"percent"`
+}
+
+func fetchStorageUsage(ctx context.Context, account string) (storageUsage, error) {
+	svc, err := newDriveService(ctx, account)
+	if err != nil {
+		return storageUsage{}, err
+	}
+	about, err := svc.About.Get().Fields("storageQuota").Context(ctx).Do()
+	if err != nil {
+		return storageUsage{}, err
+	}
+	q := about.StorageQuota
+	if q == nil {
+		return storageUsage{}, errors.New("drive did not return a storage quota")
+	}
+	s := storageUsage{
+		Limit:          q.Limit,
+		Usage:          q.Usage,
+		Drive:          q.UsageInDrive,
+		DriveTrash:     q.UsageInDriveTrash,
+		GmailAndPhotos: max(q.Usage-q.UsageInDrive, 0),
+	}
+	// No limit means unlimited or pooled storage (some Workspace plans).
+	if s.Limit > 0 {
+		s.Percent = float64(s.Usage) * 100 / float64(s.Limit)
+	}
+	return s, nil
+}
+
+type QuotaShowCmd struct{}
+
+func (c *QuotaShowCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	s, err := fetchStorageUsage(ctx, account)
+	if err != nil {
+		return err
+	}
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{"account": account, "storage": s})
+	}
+	u.Out().Printf("account\t%s", account)
+	u.Out().Printf("drive\t%s", formatBytes(s.Drive))
+	u.Out().Printf("drive_trash\t%s", formatBytes(s.DriveTrash))
+	u.Out().Printf("gmail_and_photos\t%s", formatBytes(s.GmailAndPhotos))
+	u.Out().Printf("used\t%s", formatBytes(s.Usage))
+	if s.Limit > 0 {
+		u.Out().Printf("limit\t%s", formatBytes(s.Limit))
+		u.Out().Printf("percent\t%.1f%%", s.Percent)
+	} else {
+		u.Out().Printf("limit\tunlimited")
+	}
+	return nil
+}
+
+type QuotaWatchCmd struct {
+	Threshold Percent       `name:"threshold" help:"Alert when usage reaches this share of the limit" default:"90%"`
+	Interval  time.Duration `name:"interval" help:"Time between checks" default:"1h"`
+	Once      bool          `name:"once" help:"Check once and exit (for cron)"`
+	Notify    []string      `name:"notify" help:"How to alert: desktop, email, none (repeatable)" enum:"desktop,email,none" default:"desktop"`
+	EmailTo   string        `name:"email-to" help:"Recipient of email alerts (default: the account itself)"`
+	Guardrail GuardrailFlag `embed:""`
+}
+
+// quotaAlertState remembers which accounts are over their threshold, so a
+// watch alerts once per crossing instead of on every check.
+type quotaAlertState struct {
+	Accounts map[string]quotaAlert `json:"accounts"`
+}
+
+type quotaAlert struct {
+	Percent   float64   `json:"percent"`
+	AlertedAt time.Time `json:"alertedAt"`
+}
+
+func (c *QuotaWatchCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	if !c.Once && c.Interval < time.Minute {
+		return usage("--interval must be at least 1m")
+	}
+	if len(c.Notify) > 1 {
+		for _, n := range c.Notify {
+			if n == quotaNotifyNone {
+				return usage("--notify none cannot be combined with other methods")
+			}
+		}
+	}
+
+	for {
+		checkErr := c.check(ctx, u, flags, account)
+		if interrupted(ctx) {
+			return nil
+		}
+		if checkErr != nil {
+			if c.Once {
+				return checkErr
+			}
+			// A daemon keeps going through transient API errors.
+			u.Err().Printf("quota check failed: %v", checkErr)
+		}
+		if c.Once {
+			return nil
+		}
+		if err := sendPacerSleep(ctx, c.Interval); err != nil {
+			return nil //nolint:nilerr // stopped by the user
+		}
+	}
+}
+
+func (c *QuotaWatchCmd) check(ctx context.Context, u *ui.UI, flags *RootFlags, account string) error {
+	s, err := fetchStorageUsage(ctx, account)
+	if err != nil {
+		return err
+	}
+	state, err := loadQuotaAlertState()
+	if err != nil {
+		return err
+	}
+	key := statusKey(account)
+	_, alerted := state.Accounts[key]
+	over := s.Limit > 0 && s.Percent >= float64(c.Threshold)
+
+	if outfmt.IsJSON(ctx) {
+		// One line per check, so a long-running watch streams NDJSON.
+		if err := json.NewEncoder(os.Stdout).Encode(map[string]any{
+			"account":   account,
+			"checkedAt": time.Now().UTC().Format(time.RFC3339),
+			"storage":   s,
+			"threshold": float64(c.Threshold),
+			"over":      over,
+			"alert":     over && !alerted,
+		}); err != nil {
+			return err
+		}
+	} else if s.Limit > 0 {
+		u.Err().Printf("%s: %s of %s used (%.1f%%)", account, formatBytes(s.Usage), formatBytes(s.Limit), s.Percent)
+	} else {
+		u.Err().Printf("%s: %s used (no storage limit)", account, formatBytes(s.Usage))
+	}
+
+	switch {
+	case over && !alerted:
+		c.alert(ctx, u, flags, account, s)
+		state.Accounts[key] = quotaAlert{Percent: s.Percent, AlertedAt: time.Now().UTC()}
+		return saveQuotaAlertState(state)
+	case !over && alerted:
+		// Back under the threshold: the next crossing alerts again.
+		delete(state.Accounts, key)
+		return saveQuotaAlertState(state)
+	}
+	return nil
+}
+
+// alert notifies every configured way; a failing notifier is reported but
+// does not stop the others or the watch.
+func (c *QuotaWatchCmd) alert(ctx context.Context, u *ui.UI, flags *RootFlags, account string, s storageUsage) {
+	title := "Google storage almost full"
+	message := fmt.Sprintf("%s has used %.1f%% of its storage (%s of %s): Drive %s, Drive trash %s, Gmail and Photos %s.",
+		account, s.Percent, formatBytes(s.Usage), formatBytes(s.Limit),
+		formatBytes(s.Drive), formatBytes(s.DriveTrash), formatBytes(s.GmailAndPhotos))
+	u.Err().Printf("ALERT: %s", message)
+
+	if emitErr := events.Emit(events.TypeQuotaThreshold, account, map[string]any{
+		"percent":   s.Percent,
+		"threshold": float64(c.Threshold),
+		"usage":     s.Usage,
+		"limit":     s.Limit,
+	}); emitErr != nil {
+		u.Err().Printf("event log: %v", emitErr)
+	}
+
+	for _, method := range c.Notify {
+		var err error
+		switch method {
+		case quotaNotifyDesktop:
+			err = desktopNotify(title, message)
+		case quotaNotifyEmail:
+			err = sendQuotaAlertEmail(ctx, flags, account, strings.TrimSpace(c.EmailTo), c.Guardrail, title, message)
+		}
+		if err != nil {
+			u.Err().Printf("%s notification failed: %v", method, err)
+		}
+	}
+}
+
+func sendQuotaAlertEmail(ctx context.Context, flags *RootFlags, account, to string, guardrail GuardrailFlag, subject, body string) error {
+	if to == "" {
+		to = account
+	}
+	if err := enforceSendGuardrails(ctx, flags, account, guardrail, sendGuardrailCheck{Recipients: []string{to}}); err != nil {
+		return err
+	}
+	svc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+	raw, err := buildRFC822(mailOptions{
+		From:    account,
+		To:      []string{to},
+		Subject: subject,
+		Body: body + "\n\nFree up space in Drive (empty the trash) or Gmail, or upgrade the storage plan.\n" +
+			"Sent by gog quota watch.\n",
+		AdditionalHeaders: map[string]string{"Auto-Submitted": "auto-generated"},
+	}, nil)
+	if err != nil {
+		return err
+	}
+	_, err = svc.Users.Messages.Send("me", &gmail.Message{Raw: base64.RawURLEncoding.EncodeToString(raw)}).Context(ctx).Do()
+	return err
+}
+
+func loadQuotaAlertState() (*quotaAlertState, error) {
+	path, err := config.QuotaAlertsPath()
+	if err != nil {
+		return nil, err
+	}
+	state := &quotaAlertState{Accounts: map[string]quotaAlert{}}
+	data, err := os.ReadFile(path) //nolint:gosec // path under config dir
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return state, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	if state.Accounts == nil {
+		state.Accounts = map[string]quotaAlert{}
+	}
+	return state, nil
+}
+
+func saveQuotaAlertState(state *quotaAlertState) error {
+	path, err := config.QuotaAlertsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	payload, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(payload, '\n'), 0o600)
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+
+	"github.com/steipete/gogcli/internal/events"
+)
+
+func TestQuotaShowAndWatch(t *testing.T) {
+	usage := "9000"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"storageQuota": map[string]any{
+			"limit": "10000", "usage": usage, "usageInDrive": "6000", "usageInDriveTrash": "1000",
+		}})
+	}))
+	defer srv.Close()
+	svc, err := drive.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	origNew, origNotify := newDriveService, desktopNotify
+	t.Cleanup(func() { newDriveService, desktopNotify = origNew, origNotify })
+	newDriveService = func(context.Context, string) (*drive.Service, error) { return svc, nil }
+	var notified []string
+	desktopNotify = func(_, message string) error {
+		notified = append(notified, message)
+		return nil
+	}
+	logPath := filepath.Join(t.TempDir(), "events.ndjson")
+	t.Setenv(events.EnvFile, logPath)
+
+	run := func(args ...string) string {
+		t.Helper()
+		var out string
+		_ = captureStderr(t, func() {
+			out = captureStdout(t, func() {
+				if err := Execute(append([]string{"--json", "--account", "quota@b.com", "quota"}, args...)); err != nil {
+					t.Fatalf("Execute %v: %v", args, err)
+				}
+			})
+		})
+		return out
+	}
+
+	var shown struct {
+		Storage storageUsage `json:"storage"`
+	}
+	if err := json.Unmarshal([]byte(run("show")), &shown); err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	if shown.Storage.GmailAndPhotos != 3000 || shown.Storage.Percent != 90 {
+		t.Fatalf("unexpected storage: %+v", shown.Storage)
+	}
+
+	// Alerts once per crossing, and again after dropping below.
+	for _, step := range []struct {
+		usage   string
+		alerts  int
+		wantOut string
+	}{
+		{"9000", 1, `"alert":true`},
+		{"9500", 1, `"alert":false`},
+		{"5000", 1, `"over":false`},
+		{"9100", 2, `"alert":true`},
+	} {
+		usage = step.usage
+		out := run("watch", "--once", "--threshold", "90%")
+		if len(notified) != step.alerts || !strings.Contains(out, step.wantOut) {
+			t.Fatalf("usage %s: alerts=%d out=%s", step.usage, len(notified), out)
+		}
+	}
+	if !strings.Contains(notified[0], "Gmail and Photos 2.9 KB") {
+		t.Fatalf("unexpected notification: %q", notified[0])
+	}
+	logData, err := os.ReadFile(logPath)
+	if err != nil || strings.Count(string(logData), events.TypeQuotaThreshold) != 2 {
+		t.Fatalf("expected two threshold events: %v %s", err, logData)
+	}
+}
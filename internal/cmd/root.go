@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 
 	"github.com/alecthomas/kong"
 
@@ -24,15 +25,18 @@ const (
 )
 
 type RootFlags struct {
-	Color          string `help:"Color output: auto|always|never" default:"${color}"`
-	Account        string `help:"Account email for API commands (gmail/calendar/chat/classroom/drive/docs/slides/contacts/tasks/people/sheets)"`
-	Client         string `help:"OAuth client name (selects stored credentials + token bucket)" default:"${client}"`
-	EnableCommands string `help:"Comma-separated list of enabled top-level commands (restricts CLI)" default:"${enabled_commands}"`
-	JSON           bool   `help:"Output JSON to stdout (best for scripting)" default:"${json}"`
-	Plain          bool   `help:"Output stable, parseable text to stdout (TSV; no colors)" default:"${plain}"`
-	Force          bool   `help:"Skip confirmations for destructive commands"`
-	NoInput        bool   `help:"Never prompt; fail instead (useful for CI)"`
-	Verbose        bool   `help:"Enable verbose logging"`
+	Color           string   `help:"Color output: auto|always|never" default:"${color}"`
+	Account         string   `complete:"accounts" help:"Account email for API commands (gmail/calendar/chat/classroom/drive/docs/slides/contacts/tasks/people/sheets)"`
+	Client          string   `help:"OAuth client name (selects stored credentials + token bucket)" default:"${client}"`
+	EnableCommands  string   `help:"Comma-separated list of enabled top-level commands (restricts CLI)" default:"${enabled_commands}"`
+	JSON            bool     `help:"Output JSON to stdout (best for scripting)" default:"${json}"`
+	Plain           bool     `help:"Output stable, parseable text to stdout (TSV; no colors)" default:"${plain}"`
+	Force           bool     `help:"Skip confirmations for destructive commands"`
+	NoInput         bool     `help:"Never prompt; fail instead (useful for CI)"`
+	Verbose         bool     `help:"Enable verbose logging"`
+	Preflight       bool     `help:"Check the API and the Workspace status dashboard before running; abort if the service is down" default:"${preflight}"`
+	HTTPHeader      []string `name:"http-header" sep:"none" help:"Extra HTTP header for every Google request, as 'Name: value' (repeatable; overrides http_headers in config)"`
+	UserAgentSuffix string   `name:"user-agent-suffix" help:"Append this to the User-Agent of every Google request (default: user_agent_suffix in config)" default:"${user_agent_suffix}"`
 }
 
 type CLI struct {
@@ -42,6 +46,7 @@ type CLI struct {
 
 	Auth       AuthCmd               `cmd:"" help:"Auth and credentials"`
 	Groups     GroupsCmd             `cmd:"" help:"Google Groups"`
+	Admin      AdminCmd              `cmd:"" help:"Google Workspace Admin (Directory users and org units)"`
 	Drive      DriveCmd              `cmd:"" help:"Google Drive"`
 	Docs       DocsCmd               `cmd:"" help:"Google Docs (export via Drive)"`
 	Slides     SlidesCmd             `cmd:"" help:"Google Slides"`
@@ -56,6 +61,26 @@ type CLI struct {
 	Keep       KeepCmd               `cmd:"" help:"Google Keep (Workspace only)"`
 	Sheets     SheetsCmd             `cmd:"" help:"Google Sheets"`
 	Config     ConfigCmd             `cmd:"" help:"Manage configuration"`
+	Caps       CapabilitiesCmd       `cmd:"" name:"capabilities" help:"Which features each authenticated account supports (consumer vs Workspace)"`
+	ICS        IcsCmd                `cmd:"" name:"ics" help:"iCalendar invite files"`
+	Rules      RulesCmd              `cmd:"" help:"Mail rules (suggest Gmail filters from your history)"`
+	Projects   ProjectsCmd           `cmd:"" help:"Label threads and events by project from config keywords and participants"`
+	Search     SearchCmd             `cmd:"" help:"Search Gmail threads and calendar events together (--project for one project)"`
+	Plan       PlanCmd               `cmd:"" help:"Show what apply would change for a declarative workspace document"`
+	Apply      ApplyCmd              `cmd:"" help:"Create, update, and delete labels, filters, calendars, group members, and Drive shares to match a document"`
+	Status     StatusCmd             `cmd:"" help:"Next event and unread count (--compact for shell prompts)"`
+	Batch      BatchCmd              `cmd:"" help:"Run many gog commands from a file in one process, or in worker processes with --parallel (NDJSON results)"`
+	Index      IndexCmd              `cmd:"" help:"Local full-text index of mail attachments"`
+	Export     ExportCmd             `cmd:"" help:"Export mail, events, and meeting notes (knowledge corpus)"`
+	Link       LinkCmd               `cmd:"" help:"Named deep links to threads, events, and files"`
+	Open       OpenCmd               `cmd:"" help:"Open a named link in the browser"`
+	Join       JoinCmd               `cmd:"" help:"Open the video link of the next meeting (Meet, Zoom, Teams)"`
+	Quota      QuotaCmd              `cmd:"" help:"Storage usage by service and threshold alerts"`
+	Share      ShareCmd              `cmd:"" help:"Share a Drive file or Gmail message through one expiring link"`
+	Serve      ServeCmd              `cmd:"" help:"Local HTTP servers (read-only ICS calendar feeds)"`
+	Events     EventsCmd             `cmd:"" help:"Event stream of daemon activity (NDJSON)"`
+	Cron       CronCmd               `cmd:"" help:"Run gog commands on a schedule (add, list, remove, run)"`
+	Mock       MockCmd               `cmd:"" help:"Fake Google API server for testing scripts"`
 	VersionCmd VersionCmd            `cmd:"" name:"version" help:"Print version"`
 	Completion CompletionCmd         `cmd:"" help:"Generate shell completion scripts"`
 	Complete   CompletionInternalCmd `cmd:"" name:"__complete" hidden:"" help:"Internal completion helper"`
@@ -64,7 +89,7 @@ type CLI struct {
 type exitPanic struct{ code int }
 
 func Execute(args []string) (err error) {
-	parser, cli, err := newParser(helpDescription())
+	parser, cli, err := newParser(baseDescription(), onlyCommand(args)...)
 	if err != nil {
 		return err
 	}
@@ -86,12 +111,12 @@ func Execute(args []string) (err error) {
 	kctx, err := parser.Parse(args)
 	if err != nil {
 		parsedErr := wrapParseError(err)
-		_, _ = fmt.Fprintln(os.Stderr, errfmt.Format(parsedErr))
+		reportError(nil, parsedErr, cli.JSON || argsRequestJSON(args))
 		return parsedErr
 	}
 
 	if err = enforceEnabledCommands(kctx, cli.EnableCommands); err != nil {
-		_, _ = fmt.Fprintln(os.Stderr, errfmt.Format(err))
+		reportError(nil, err, cli.JSON)
 		return err
 	}
 
@@ -105,7 +130,9 @@ func Execute(args []string) (err error) {
 
 	mode, err := outfmt.FromFlags(cli.JSON, cli.Plain)
 	if err != nil {
-		return newUsageError(err)
+		err = newUsageError(err)
+		reportError(nil, err, cli.JSON)
+		return err
 	}
 
 	ctx := context.Background()
@@ -118,29 +145,98 @@ func Execute(args []string) (err error) {
 	}
 
 	u, err := ui.New(ui.Options{
-		Stdout: os.Stdout,
-		Stderr: os.Stderr,
+		Stdout: stdoutWriter{},
+		Stderr: stderrWriter{},
 		Color:  uiColor,
 	})
 	if err != nil {
 		return err
 	}
 	ctx = ui.WithUI(ctx, u)
+	command := strings.Join(commandPath(kctx), " ")
+	if flushOnInterruptCommands[command] || stopOnInterruptCommands[command] {
+		var stopInterrupt func()
+		ctx, stopInterrupt = withInterrupt(ctx, flushOnInterruptCommands[command])
+		defer stopInterrupt()
+	}
+
+	if ctx, err = withGmailMailbox(ctx, kctx, cli.Gmail.Mailbox); err != nil {
+		reportError(u, err, outfmt.IsJSON(ctx))
+		return err
+	}
 
 	kctx.BindTo(ctx, (*context.Context)(nil))
 	kctx.Bind(&cli.RootFlags)
 
+	if err = configureHTTPOptions(&cli.RootFlags); err != nil {
+		reportError(u, err, outfmt.IsJSON(ctx))
+		return err
+	}
+
+	if cli.Preflight {
+		if err = runPreflight(ctx, kctx, &cli.RootFlags); err != nil {
+			reportError(u, err, outfmt.IsJSON(ctx))
+			return err
+		}
+	}
+
 	err = kctx.Run()
+	switch {
+	case interrupted(ctx) && stopOnInterruptCommands[command]:
+		if errors.Is(err, context.Canceled) || errors.Is(err, errfmt.ErrInterrupted) {
+			err = nil
+		}
+	case interrupted(ctx):
+		err = interruptedError(err)
+	}
 	if err == nil {
 		return nil
 	}
 
-	if u := ui.FromContext(ctx); u != nil {
+	reportError(ui.FromContext(ctx), err, outfmt.IsJSON(ctx))
+	return err
+}
+
+// stdoutWriter and stderrWriter resolve os.Stdout/os.Stderr on every write,
+// so in-process redirection (gog batch, --idempotency-key) also sees output
+// printed through the UI.
+type (
+	stdoutWriter struct{}
+	stderrWriter struct{}
+)
+
+func (stdoutWriter) Write(p []byte) (int, error) { return os.Stdout.Write(p) }
+func (stderrWriter) Write(p []byte) (int, error) { return os.Stderr.Write(p) }
+
+// reportError prints err to stderr: a {"error": {...}} object in JSON mode so
+// scripts can branch on code/retryable, otherwise the human-readable message.
+func reportError(u *ui.UI, err error, jsonMode bool) {
+	if jsonMode {
+		_ = outfmt.WriteJSON(os.Stderr, map[string]any{"error": classifyError(err)})
+		return
+	}
+	if u != nil {
 		u.Err().Error(errfmt.Format(err))
-		return err
+		return
 	}
 	_, _ = fmt.Fprintln(os.Stderr, errfmt.Format(err))
-	return err
+}
+
+// argsRequestJSON reports whether JSON output was requested when parsing
+// failed before flags could be bound.
+func argsRequestJSON(args []string) bool {
+	if outfmt.FromEnv().JSON {
+		return true
+	}
+	for _, arg := range args {
+		if arg == "--" {
+			return false
+		}
+		if arg == "--json" || arg == "--json=true" {
+			return true
+		}
+	}
+	return false
 }
 
 func wrapParseError(err error) error {
@@ -168,29 +264,35 @@ func boolString(v bool) string {
 	return "false"
 }
 
-func newParser(description string) (*kong.Kong, *CLI, error) {
+func newParser(description string, options ...kong.Option) (*kong.Kong, *CLI, error) {
 	envMode := outfmt.FromEnv()
 	vars := kong.Vars{
-		"auth_services":    googleauth.UserServiceCSV(),
-		"color":            envOr("GOG_COLOR", "auto"),
-		"calendar_weekday": envOr("GOG_CALENDAR_WEEKDAY", "false"),
-		"client":           envOr("GOG_CLIENT", ""),
-		"enabled_commands": envOr("GOG_ENABLE_COMMANDS", ""),
-		"json":             boolString(envMode.JSON),
-		"plain":            boolString(envMode.Plain),
-		"version":          VersionString(),
+		"auth_services":          googleauth.UserServiceCSV(),
+		"color":                  envOr("GOG_COLOR", "auto"),
+		"calendar_weekday":       envOr("GOG_CALENDAR_WEEKDAY", "false"),
+		"client":                 envOr("GOG_CLIENT", ""),
+		"enabled_commands":       envOr("GOG_ENABLE_COMMANDS", ""),
+		"invite_responses_query": defaultInviteResponsesQuery,
+		"json":                   boolString(envMode.JSON),
+		"plain":                  boolString(envMode.Plain),
+		"preflight":              envOr("GOG_PREFLIGHT", "false"),
+		"user_agent_suffix":      envOr("GOG_USER_AGENT_SUFFIX", ""),
+		"version":                VersionString(),
 	}
 
 	cli := &CLI{}
 	parser, err := kong.New(
 		cli,
-		kong.Name("gog"),
-		kong.Description(description),
-		kong.ConfigureHelp(helpOptions()),
-		kong.Help(helpPrinter),
-		kong.Vars(vars),
-		kong.Writers(os.Stdout, os.Stderr),
-		kong.Exit(func(code int) { panic(exitPanic{code: code}) }),
+		append([]kong.Option{
+			kong.Name("gog"),
+			kong.Description(description),
+			kong.ConfigureHelp(helpOptions()),
+			kong.Help(helpPrinter),
+			kong.Vars(vars),
+			kong.Resolvers(configDefaultsResolver()),
+			kong.Writers(os.Stdout, os.Stderr),
+			kong.Exit(func(code int) { panic(exitPanic{code: code}) }),
+		}, options...)...,
 	)
 	if err != nil {
 		return nil, nil, err
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/alecthomas/kong"
+)
+
+// onlyCommand skips building every top-level command except the one args
+// select. Building the whole command tree is most of gog's startup time, and
+// prompt integrations run small commands (status --compact, __complete)
+// often. Root help and unknown commands keep the full tree, so help and
+// "did you mean" suggestions are unchanged.
+func onlyCommand(args []string) []kong.Option {
+	name := topLevelCommand(args)
+	if name == "" {
+		return nil
+	}
+	t := reflect.TypeOf(CLI{})
+	var skip []string
+	found := false
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if _, ok := f.Tag.Lookup("cmd"); !ok {
+			continue
+		}
+		if commandFieldName(f) == name || slices.Contains(strings.Split(f.Tag.Get("aliases"), ","), name) {
+			found = true
+			continue
+		}
+		skip = append(skip, f.Name)
+	}
+	if !found || len(skip) == 0 {
+		return nil
+	}
+	return []kong.Option{kong.IgnoreFields(`^CLI\.(` + strings.Join(skip, "|") + `)$`)}
+}
+
+// topLevelCommand returns the first positional argument, skipping root flags
+// and their values, or "" when there is none.
+func topLevelCommand(args []string) string {
+	valueFlags := rootValueFlags()
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return ""
+		case strings.HasPrefix(arg, "-"):
+			if !strings.Contains(arg, "=") && valueFlags[strings.TrimLeft(arg, "-")] {
+				i++
+			}
+		default:
+			return arg
+		}
+	}
+	return ""
+}
+
+// rootValueFlags are the root flags that take a separate value argument.
+func rootValueFlags() map[string]bool {
+	out := map[string]bool{}
+	t := reflect.TypeOf(RootFlags{})
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if f.Type.Kind() != reflect.Bool {
+			out[commandFieldName(f)] = true
+		}
+	}
+	return out
+}
+
+// commandFieldName is the name kong gives a command or flag field: its name
+// tag, or the field name in lower kebab case.
+func commandFieldName(f reflect.StructField) string {
+	if name := f.Tag.Get("name"); name != "" {
+		return name
+	}
+	var b strings.Builder
+	for i, r := range f.Name {
+		if unicode.IsUpper(r) && i > 0 && !unicode.IsUpper(rune(f.Name[i-1])) {
+			b.WriteByte('-')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTopLevelCommand(t *testing.T) {
+	cases := map[string]string{
+		"gmail search foo":                          "gmail",
+		"--account a@b.com --json status --compact": "status",
+		"--account=a@b.com --http-header X:y mail":  "mail",
+		"--verbose --help":                          "",
+		"-- gmail":                                  "",
+		"":                                          "",
+	}
+	for in, want := range cases {
+		if got := topLevelCommand(strings.Fields(in)); got != want {
+			t.Errorf("topLevelCommand(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestOnlyCommandBuildsSelectedSubtree(t *testing.T) {
+	parser, _, err := newParser("", onlyCommand([]string{"--account", "a@b.com", "mail", "search", "x"})...)
+	if err != nil {
+		t.Fatalf("newParser: %v", err)
+	}
+	var names []string
+	for _, child := range parser.Model.Node.Children {
+		names = append(names, child.Name)
+	}
+	if len(names) != 1 || names[0] != "gmail" {
+		t.Fatalf("children = %v", names)
+	}
+
+	for _, args := range [][]string{{"--help"}, {"gmial", "search"}} {
+		if opts := onlyCommand(args); opts != nil {
+			t.Fatalf("%v: want the full tree", args)
+		}
+	}
+}
+
+func TestRootHelpShowsConfig(t *testing.T) {
+	out := captureStdout(t, func() {
+		_ = Execute([]string{"--help"})
+	})
+	if !strings.Contains(out, "keyring backend:") {
+		t.Fatalf("root help lost the config block:\n%s", out)
+	}
+}
@@ -0,0 +1,13 @@
+package cmd
+
+// RulesCmd groups mail automation rules. Rules are plain Gmail filters, so
+// they keep working when gog is not running; presets cover what a filter
+// cannot check on its own and run from cron.
+type RulesCmd struct {
+	Suggest RulesSuggestCmd `cmd:"" name:"suggest" help:"Propose Gmail filters from how you label mail by hand"`
+	Preset  RulesPresetCmd  `cmd:"" name:"preset" help:"Built-in rules that need more than a Gmail filter (run from cron)"`
+}
+
+type RulesPresetCmd struct {
+	Invites RulesPresetInvitesCmd `cmd:"" name:"invites" help:"Archive Accepted/Declined notifications once Calendar has recorded the response"`
+}
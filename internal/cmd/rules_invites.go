@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/events"
+	"github.com/steipete/gogcli/internal/ics"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// Calendar attaches the attendee's answer as a METHOD:REPLY invite.ics, so
+// the query only needs to find candidates; the reply itself decides.
+const defaultInviteResponsesQuery = `in:inbox filename:ics {subject:Accepted subject:Declined subject:"Tentatively accepted"}`
+
+type RulesPresetInvitesCmd struct {
+	Query    string `name:"query" short:"q" help:"Gmail query selecting response notifications" default:"${invite_responses_query}"`
+	Max      int    `name:"max" aliases:"limit" help:"Max messages to look at" default:"200"`
+	Calendar string `name:"calendar" help:"Calendar holding the events" default:"primary"`
+	DryRun   bool   `name:"dry-run" help:"Report what would be archived without changing anything"`
+}
+
+// inviteResponse is one response notification and what happened to it.
+type inviteResponse struct {
+	MessageID string `json:"messageId"`
+	Subject   string `json:"subject"`
+	Attendee  string `json:"attendee,omitempty"`
+	Response  string `json:"response,omitempty"`
+	EventID   string `json:"eventId,omitempty"`
+	Archived  bool   `json:"archived"`
+	Reason    string `json:"reason,omitempty"` // why the message stays in the inbox
+}
+
+func (c *RulesPresetInvitesCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	if c.Max <= 0 {
+		return usage("--max must be > 0")
+	}
+	calendarID := strings.TrimSpace(c.Calendar)
+	if calendarID == "" {
+		return usage("--calendar must not be empty")
+	}
+
+	gsvc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+	csvc, err := newCalendarService(ctx, account)
+	if err != nil {
+		return err
+	}
+	refs, err := listMessageRefs(ctx, gsvc, strings.TrimSpace(c.Query), c.Max)
+	if err != nil {
+		return err
+	}
+
+	results := []inviteResponse{}
+	eventsByUID := map[string][]*calendar.Event{}
+	archived := 0
+	for _, ref := range refs {
+		if ref == nil || ref.Id == "" {
+			continue
+		}
+		r, procErr := c.process(ctx, gsvc, csvc, calendarID, ref.Id, eventsByUID)
+		if procErr != nil {
+			if interrupted(ctx) {
+				// Keep what was archived so far in the output.
+				break
+			}
+			return fmt.Errorf("message %s: %w", ref.Id, procErr)
+		}
+		if r.Archived {
+			archived++
+		}
+		results = append(results, r)
+	}
+
+	if !c.DryRun {
+		if emitErr := events.Emit(events.TypeJobFinished, account, map[string]any{
+			"job":      "rules.preset.invites",
+			"archived": archived,
+		}); emitErr != nil {
+			u.Err().Printf("event log: %v", emitErr)
+		}
+	}
+
+	if outfmt.IsJSON(ctx) {
+		out := map[string]any{
+			"messages": results,
+			"archived": archived,
+			"dryRun":   c.DryRun,
+		}
+		if interrupted(ctx) {
+			out["interrupted"] = true
+		}
+		return outfmt.WriteJSON(os.Stdout, out)
+	}
+	if len(results) == 0 {
+		u.Err().Println("No response notifications found")
+		return nil
+	}
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "MESSAGE\tATTENDEE\tRESPONSE\tACTION\tSUBJECT")
+	for _, r := range results {
+		action := "archived"
+		if !r.Archived {
+			action = "kept: " + r.Reason
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.MessageID, orDash(r.Attendee), orDash(r.Response), action, sanitizeTab(r.Subject))
+	}
+	if c.DryRun {
+		u.Err().Printf("Dry run: %d notification(s) would be archived", archived)
+	}
+	return nil
+}
+
+// process archives one notification when its reply matches the attendee's
+// status on the event. eventsByUID caches lookups across a run.
+func (c *RulesPresetInvitesCmd) process(ctx context.Context, gsvc *gmail.Service, csvc *calendar.Service, calendarID, id string, eventsByUID map[string][]*calendar.Event) (inviteResponse, error) {
+	msg, err := gsvc.Users.Messages.Get("me", id).Format("full").Context(ctx).Do()
+	if err != nil {
+		return inviteResponse{}, err
+	}
+	r := inviteResponse{MessageID: id, Subject: headerValue(msg.Payload, "Subject")}
+
+	reply, ok, err := inviteReplyOf(ctx, gsvc, msg)
+	if err != nil {
+		return r, err
+	}
+	if !ok {
+		r.Reason = "no calendar reply attached"
+		return r, nil
+	}
+	r.Attendee = reply.Attendee
+	r.Response = inviteResponseStatus(reply.PartStat)
+	if r.Response == "" {
+		r.Reason = "unsupported response " + orDash(reply.PartStat)
+		return r, nil
+	}
+
+	found, cached := eventsByUID[reply.UID]
+	if !cached {
+		resp, listErr := csvc.Events.List(calendarID).ICalUID(reply.UID).Context(ctx).Do()
+		if listErr != nil {
+			return r, listErr
+		}
+		found = resp.Items
+		eventsByUID[reply.UID] = found
+	}
+	if len(found) == 0 {
+		r.Reason = "event not found"
+		return r, nil
+	}
+	r.EventID = found[0].Id
+	if !attendeeResponseRecorded(found, reply.Attendee, r.Response) {
+		r.Reason = "response not recorded on the event yet"
+		return r, nil
+	}
+
+	if !c.DryRun {
+		if _, err := gsvc.Users.Messages.Modify("me", id, &gmail.ModifyMessageRequest{
+			RemoveLabelIds: []string{"INBOX"},
+		}).Context(ctx).Do(); err != nil {
+			return r, err
+		}
+	}
+	r.Archived = true
+	return r, nil
+}
+
+// inviteReplyOf finds the METHOD:REPLY calendar part of a message, inline
+// (text/calendar) or attached (application/ics).
+func inviteReplyOf(ctx context.Context, svc *gmail.Service, msg *gmail.Message) (ics.Reply, bool, error) {
+	for _, mimeType := range []string{"text/calendar", "application/ics"} {
+		part := findPartByMimeType(msg.Payload, mimeType)
+		if part == nil {
+			continue
+		}
+		data, err := mimePartData(ctx, svc, msg.Id, part)
+		if err != nil {
+			return ics.Reply{}, false, err
+		}
+		if reply, ok := ics.ParseReply(data); ok {
+			return reply, true, nil
+		}
+	}
+	return ics.Reply{}, false, nil
+}
+
+// inviteResponseStatus maps an iCalendar PARTSTAT to the Calendar API's
+// attendee responseStatus.
+func inviteResponseStatus(partStat string) string {
+	switch partStat {
+	case "ACCEPTED":
+		return "accepted"
+	case "DECLINED":
+		return "declined"
+	case "TENTATIVE":
+		return "tentative"
+	}
+	return ""
+}
+
+func attendeeResponseRecorded(found []*calendar.Event, email, status string) bool {
+	for _, e := range found {
+		if e == nil {
+			continue
+		}
+		for _, a := range e.Attendees {
+			if a != nil && strings.EqualFold(a.Email, email) && a.ResponseStatus == status {
+				return true
+			}
+		}
+	}
+	return false
+}
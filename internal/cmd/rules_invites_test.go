@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/option"
+)
+
+func TestRulesPresetInvites(t *testing.T) {
+	reply := func(attendee, partStat string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte("BEGIN:VCALENDAR\r\nMETHOD:REPLY\r\nBEGIN:VEVENT\r\n" +
+			"ATTENDEE;PARTSTAT=" + partStat + ":mailto:" + attendee + "\r\nUID:ev1@google.com\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"))
+	}
+	messages := map[string]map[string]any{
+		"m1": {"id": "m1", "payload": map[string]any{
+			"mimeType": "multipart/mixed",
+			"headers":  []map[string]string{{"name": "Subject", "value": "Accepted: Sync @ Mon"}},
+			"parts": []map[string]any{
+				{"partId": "0", "mimeType": "text/plain", "body": map[string]any{"data": "aGk"}},
+				{"partId": "1", "mimeType": "application/ics", "filename": "invite.ics", "body": map[string]any{"attachmentId": "a1"}},
+			},
+		}},
+		"m2": {"id": "m2", "payload": map[string]any{
+			"mimeType": "text/calendar",
+			"headers":  []map[string]string{{"name": "Subject", "value": "Declined: Sync @ Mon"}},
+			"body":     map[string]any{"data": reply("carol@example.com", "DECLINED")},
+		}},
+		"m3": {"id": "m3", "payload": map[string]any{
+			"mimeType": "text/plain",
+			"headers":  []map[string]string{{"name": "Subject", "value": "Accepted the offer"}},
+			"body":     map[string]any{"data": "aGk"},
+		}},
+	}
+	var modified []string
+	lookups := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := r.URL.Path
+		switch {
+		case strings.HasSuffix(path, "/users/me/messages") && r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]any{"messages": []map[string]string{{"id": "m1"}, {"id": "m2"}, {"id": "m3"}}})
+		case strings.HasSuffix(path, "/attachments/a1"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": reply("Bob@Example.com", "ACCEPTED")})
+		case strings.HasSuffix(path, "/modify"):
+			id := strings.TrimSuffix(path, "/modify")
+			modified = append(modified, id[strings.LastIndex(id, "/")+1:])
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "x"})
+		case strings.Contains(path, "/users/me/messages/"):
+			id := path[strings.LastIndex(path, "/")+1:]
+			_ = json.NewEncoder(w).Encode(messages[id])
+		case strings.HasSuffix(path, "/calendars/primary/events"):
+			lookups++
+			if got := r.URL.Query().Get("iCalUID"); got != "ev1@google.com" {
+				t.Errorf("iCalUID = %q", got)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"items": []map[string]any{{
+				"id": "e1",
+				"attendees": []map[string]any{
+					{"email": "bob@example.com", "responseStatus": "accepted"},
+					{"email": "carol@example.com", "responseStatus": "needsAction"},
+				},
+			}}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	stubGmailService(t, srv)
+
+	origCal := newCalendarService
+	t.Cleanup(func() { newCalendarService = origCal })
+	csvc, err := calendar.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newCalendarService = func(context.Context, string) (*calendar.Service, error) { return csvc, nil }
+
+	run := func(args ...string) map[string]any {
+		var out map[string]any
+		stdout := captureStdout(t, func() {
+			_ = captureStderr(t, func() {
+				if err := Execute(append([]string{"--json", "--account", "me@example.com", "rules", "preset", "invites"}, args...)); err != nil {
+					t.Fatalf("Execute: %v", err)
+				}
+			})
+		})
+		if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+			t.Fatalf("json: %v\n%s", err, stdout)
+		}
+		return out
+	}
+
+	out := run("--dry-run")
+	if len(modified) != 0 || out["archived"] != float64(1) {
+		t.Fatalf("dry run: modified=%v out=%v", modified, out)
+	}
+
+	out = run()
+	if strings.Join(modified, ",") != "m1" || out["archived"] != float64(1) {
+		t.Fatalf("modified=%v out=%v", modified, out)
+	}
+	if lookups != 2 {
+		t.Fatalf("expected one event lookup per run, got %d", lookups)
+	}
+	reasons := map[string]string{}
+	for _, m := range out["messages"].([]any) {
+		item := m.(map[string]any)
+		reason, _ := item["reason"].(string)
+		reasons[item["messageId"].(string)] = reason
+	}
+	if reasons["m2"] != "response not recorded on the event yet" || reasons["m3"] != "no calendar reply attached" {
+		t.Fatalf("reasons = %v", reasons)
+	}
+}
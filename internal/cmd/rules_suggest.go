@@ -0,0 +1,337 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/mail"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+const (
+	rulesBySender = "sender"
+	rulesByDomain = "domain"
+
+	rulesFormatXML  = "xml"
+	rulesFormatJSON = "json"
+)
+
+type RulesSuggestCmd struct {
+	Query    string  `name:"query" short:"q" help:"Gmail query selecting the history to learn from" default:"newer_than:1y -in:sent -in:chats"`
+	Max      int     `name:"max" aliases:"limit" help:"Max messages to scan" default:"500"`
+	By       string  `name:"by" help:"Group senders by: sender|domain" enum:"sender,domain" default:"sender"`
+	MinCount int     `name:"min-count" help:"Minimum labeled messages before suggesting a rule" default:"3"`
+	MinRatio float64 `name:"min-ratio" help:"Minimum share of a sender's messages that carry the label (0-1)" default:"0.8"`
+	Out      string  `name:"out" aliases:"output" help:"Write an importable filters file ('-' for stdout)"`
+	Format   string  `name:"format" help:"Filters file format: xml (Gmail Settings > Import filters) or json (API filter objects)" enum:"xml,json" default:"xml"`
+}
+
+// ruleSuggestion is a sender/label pair seen consistently in history.
+type ruleSuggestion struct {
+	From    string  `json:"from"`
+	Label   string  `json:"label"`
+	LabelID string  `json:"labelId"`
+	Archive bool    `json:"archive"`
+	Count   int     `json:"count"`
+	Total   int     `json:"total"`
+	Ratio   float64 `json:"ratio"`
+}
+
+type senderLabelStats struct {
+	total    int
+	labeled  map[string]int
+	archived map[string]int
+}
+
+func (c *RulesSuggestCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	if c.Max <= 0 {
+		return usage("--max must be > 0")
+	}
+	if c.MinCount < 1 {
+		return usage("--min-count must be >= 1")
+	}
+	if c.MinRatio <= 0 || c.MinRatio > 1 {
+		return usage("--min-ratio must be in (0, 1]")
+	}
+
+	svc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	labelsResp, err := svc.Users.Labels.List("me").Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+	idToName := map[string]string{}
+	userLabels := map[string]string{} // name -> id
+	for _, l := range labelsResp.Labels {
+		if l == nil || l.Id == "" {
+			continue
+		}
+		idToName[l.Id] = l.Name
+		if l.Type == "user" {
+			userLabels[l.Name] = l.Id
+		}
+	}
+
+	refs, err := listMessageRefs(ctx, svc, strings.TrimSpace(c.Query), c.Max)
+	if err != nil {
+		return err
+	}
+	items, err := fetchMessageDetails(ctx, svc, refs, idToName, time.UTC, false)
+	if err != nil {
+		return err
+	}
+
+	stats := map[string]*senderLabelStats{}
+	for _, item := range items {
+		key := rulesSenderKey(item.From, c.By)
+		if key == "" {
+			continue
+		}
+		st := stats[key]
+		if st == nil {
+			st = &senderLabelStats{labeled: map[string]int{}, archived: map[string]int{}}
+			stats[key] = st
+		}
+		st.total++
+		inInbox := false
+		for _, l := range item.Labels {
+			if l == "INBOX" {
+				inInbox = true
+			}
+		}
+		for _, l := range item.Labels {
+			if _, ok := userLabels[l]; !ok {
+				continue
+			}
+			st.labeled[l]++
+			if !inInbox {
+				st.archived[l]++
+			}
+		}
+	}
+
+	filters, err := svc.Users.Settings.Filters.List("me").Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+	suggestions := suggestRules(stats, userLabels, filters.Filter, c.MinCount, c.MinRatio)
+
+	path := strings.TrimSpace(c.Out)
+	if path != "" {
+		var buf bytes.Buffer
+		if c.Format == rulesFormatJSON {
+			err = outfmt.WriteJSON(&buf, map[string]any{"filters": rulesAsFilters(suggestions)})
+		} else {
+			err = writeMailFiltersXML(&buf, suggestions, time.Now())
+		}
+		if err != nil {
+			return err
+		}
+		if path == "-" {
+			_, err = os.Stdout.Write(buf.Bytes())
+			return err
+		}
+		if path, err = config.ExpandPath(path); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+			return err
+		}
+	}
+
+	if outfmt.IsJSON(ctx) {
+		payload := map[string]any{
+			"scanned":     len(items),
+			"suggestions": suggestions,
+		}
+		if path != "" {
+			payload["path"] = path
+		}
+		return outfmt.WriteJSON(os.Stdout, payload)
+	}
+
+	if len(suggestions) == 0 {
+		u.Err().Printf("No consistent labeling patterns in %d message(s)", len(items))
+		return nil
+	}
+	w, flush := tableWriter(ctx)
+	fmt.Fprintln(w, "FROM\tLABEL\tARCHIVE\tSEEN")
+	for _, s := range suggestions {
+		fmt.Fprintf(w, "%s\t%s\t%t\t%d/%d\n", s.From, s.Label, s.Archive, s.Count, s.Total)
+	}
+	flush()
+	if path != "" {
+		u.Err().Printf("Wrote %d filter(s) to %s", len(suggestions), path)
+		if c.Format == rulesFormatXML {
+			u.Err().Println("Import it in Gmail: Settings > Filters and Blocked Addresses > Import filters")
+		}
+	} else {
+		u.Err().Println("Write an importable file with --out mailFilters.xml")
+	}
+	return nil
+}
+
+// listMessageRefs pages through a search until max message references.
+func listMessageRefs(ctx context.Context, svc *gmail.Service, query string, maxMessages int) ([]*gmail.Message, error) {
+	var out []*gmail.Message
+	pageToken := ""
+	for len(out) < maxMessages {
+		call := svc.Users.Messages.List("me").MaxResults(int64(min(500, maxMessages-len(out)))).Context(ctx)
+		if query != "" {
+			call = call.Q(query)
+		}
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, resp.Messages...)
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+	if len(out) > maxMessages {
+		out = out[:maxMessages]
+	}
+	return out, nil
+}
+
+func rulesSenderKey(from, by string) string {
+	addr, err := mail.ParseAddress(from)
+	if err != nil {
+		return ""
+	}
+	email := strings.ToLower(addr.Address)
+	if by == rulesByDomain {
+		if _, domain, ok := strings.Cut(email, "@"); ok && domain != "" {
+			return "@" + domain
+		}
+		return ""
+	}
+	return email
+}
+
+func suggestRules(stats map[string]*senderLabelStats, userLabels map[string]string, existing []*gmail.Filter, minCount int, minRatio float64) []ruleSuggestion {
+	var out []ruleSuggestion
+	for key, st := range stats {
+		for label, n := range st.labeled {
+			ratio := float64(n) / float64(st.total)
+			if n < minCount || ratio < minRatio {
+				continue
+			}
+			labelID := userLabels[label]
+			if filterCovers(existing, key, labelID) {
+				continue
+			}
+			out = append(out, ruleSuggestion{
+				From:    key,
+				Label:   label,
+				LabelID: labelID,
+				Archive: float64(st.archived[label])/float64(n) >= minRatio,
+				Count:   n,
+				Total:   st.total,
+				Ratio:   ratio,
+			})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		if out[i].From != out[j].From {
+			return out[i].From < out[j].From
+		}
+		return out[i].Label < out[j].Label
+	})
+	return out
+}
+
+// filterCovers reports whether an existing filter already applies labelID to
+// mail from the sender (or its domain).
+func filterCovers(filters []*gmail.Filter, from, labelID string) bool {
+	for _, f := range filters {
+		if f == nil || f.Criteria == nil || f.Action == nil || f.Criteria.From == "" {
+			continue
+		}
+		crit := strings.ToLower(f.Criteria.From)
+		if !strings.Contains(crit, from) && !strings.Contains(from, strings.TrimPrefix(crit, "@")) {
+			continue
+		}
+		for _, id := range f.Action.AddLabelIds {
+			if id == labelID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func rulesAsFilters(suggestions []ruleSuggestion) []*gmail.Filter {
+	out := make([]*gmail.Filter, 0, len(suggestions))
+	for _, s := range suggestions {
+		action := &gmail.FilterAction{AddLabelIds: []string{s.LabelID}}
+		if s.Archive {
+			action.RemoveLabelIds = []string{"INBOX"}
+		}
+		out = append(out, &gmail.Filter{Criteria: &gmail.FilterCriteria{From: s.From}, Action: action})
+	}
+	return out
+}
+
+// writeMailFiltersXML writes the Atom feed Gmail uses for filter export and
+// import (Settings > Filters and Blocked Addresses).
+func writeMailFiltersXML(w io.Writer, suggestions []ruleSuggestion, now time.Time) error {
+	var b bytes.Buffer
+	stamp := now.UTC().Format(time.RFC3339)
+	b.WriteString("<?xml version='1.0' encoding='UTF-8'?>\n")
+	b.WriteString("<feed xmlns='http://www.w3.org/2005/Atom' xmlns:apps='http://schemas.google.com/apps/2006'>\n")
+	b.WriteString("\t<title>Mail Filters</title>\n")
+	fmt.Fprintf(&b, "\t<updated>%s</updated>\n", stamp)
+	for i, s := range suggestions {
+		b.WriteString("\t<entry>\n")
+		b.WriteString("\t\t<category term='filter'></category>\n")
+		b.WriteString("\t\t<title>Mail Filter</title>\n")
+		fmt.Fprintf(&b, "\t\t<id>tag:mail.google.com,2008:filter:gog%d</id>\n", i+1)
+		fmt.Fprintf(&b, "\t\t<updated>%s</updated>\n", stamp)
+		b.WriteString("\t\t<content></content>\n")
+		writeFilterProperty(&b, "from", s.From)
+		writeFilterProperty(&b, "label", s.Label)
+		if s.Archive {
+			writeFilterProperty(&b, "shouldArchive", "true")
+		}
+		writeFilterProperty(&b, "sizeOperator", "s_sl")
+		writeFilterProperty(&b, "sizeUnit", "s_smb")
+		b.WriteString("\t</entry>\n")
+	}
+	b.WriteString("</feed>\n")
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+func writeFilterProperty(b *bytes.Buffer, name, value string) {
+	var escaped bytes.Buffer
+	_ = xml.EscapeText(&escaped, []byte(value))
+	fmt.Fprintf(b, "\t\t<apps:property name='%s' value='%s'/>\n", name, escaped.String())
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/mock"
+)
+
+func TestRulesSuggest(t *testing.T) {
+	f := &mock.Fixtures{Gmail: mock.GmailFixtures{
+		Labels: []*gmail.Label{
+			{Id: "Label_1", Name: "Work"},
+			{Id: "Label_2", Name: "Receipts"},
+			{Id: "Label_3", Name: "Clubs & Teams"},
+		},
+		Filters: []*gmail.Filter{{
+			Criteria: &gmail.FilterCriteria{From: "boss@corp.example"},
+			Action:   &gmail.FilterAction{AddLabelIds: []string{"Label_1"}},
+		}},
+	}}
+	add := func(from string, labels ...string) {
+		id := fmt.Sprintf("%016x", 0x18d0000000000000+len(f.Gmail.Messages)+1)
+		f.Gmail.Messages = append(f.Gmail.Messages, mock.MessageFixture{
+			Message: &gmail.Message{Id: id, LabelIds: labels},
+			From:    from,
+			Subject: "msg " + id,
+			Date:    "2026-01-05T10:00:00Z",
+		})
+	}
+	for i := 0; i < 4; i++ {
+		add("Shop <orders@shop.example>", "Label_2")
+		add("Boss <boss@corp.example>", "INBOX", "Label_1")
+		add("Coach <coach@club.example>", "INBOX", "Label_3")
+	}
+	add("Shop <news@shop.example>", "INBOX")
+	add("Other <x@random.example>", "INBOX", "Label_1")
+
+	s, err := mock.New(f, mock.ServiceGmail)
+	if err != nil {
+		t.Fatalf("mock.New: %v", err)
+	}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	t.Setenv(googleapi.EnvAPIEndpoint, srv.URL)
+
+	path := filepath.Join(t.TempDir(), "mailFilters.xml")
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "test@example.com", "rules", "suggest", "--query", "", "--out", path}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	var parsed struct {
+		Scanned     int              `json:"scanned"`
+		Suggestions []ruleSuggestion `json:"suggestions"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if parsed.Scanned != 14 || len(parsed.Suggestions) != 2 {
+		t.Fatalf("unexpected suggestions: %#v", parsed)
+	}
+	if got := parsed.Suggestions[0]; got.From != "coach@club.example" || got.Label != "Clubs & Teams" || got.Archive {
+		t.Fatalf("unexpected first suggestion: %#v", got)
+	}
+	if got := parsed.Suggestions[1]; got.From != "orders@shop.example" || got.LabelID != "Label_2" || !got.Archive || got.Count != 4 {
+		t.Fatalf("unexpected second suggestion: %#v", got)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	xml := string(data)
+	for _, want := range []string{
+		"<apps:property name='from' value='orders@shop.example'/>",
+		"<apps:property name='label' value='Clubs &amp; Teams'/>",
+		"<apps:property name='shouldArchive' value='true'/>",
+	} {
+		if !strings.Contains(xml, want) {
+			t.Fatalf("missing %q in:\n%s", want, xml)
+		}
+	}
+	if strings.Count(xml, "<entry>") != 2 {
+		t.Fatalf("expected 2 entries:\n%s", xml)
+	}
+
+	// Grouping by domain merges shop senders; the newsletter in INBOX keeps
+	// the ratio at 4/5, still above the default threshold.
+	out = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--account", "test@example.com", "rules", "suggest", "--query", "", "--by", "domain", "--out", "-", "--format", "json"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	var file struct {
+		Filters []*gmail.Filter `json:"filters"`
+	}
+	if err := json.Unmarshal([]byte(out), &file); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if len(file.Filters) != 2 || file.Filters[1].Criteria.From != "@shop.example" || file.Filters[1].Action.RemoveLabelIds[0] != "INBOX" {
+		t.Fatalf("unexpected filters: %s", out)
+	}
+}
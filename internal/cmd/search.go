@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// SearchCmd searches Gmail threads and calendar events together.
+type SearchCmd struct {
+	Query      []string  `arg:"" name:"query" optional:"" help:"Words to search for (Gmail query syntax applies to threads)"`
+	Project    string    `name:"project" help:"Only threads and events labeled for this project (see gog projects apply)"`
+	Since      SinceTime `name:"since" help:"Look back this far (e.g. 90d, 1y) or since a date (YYYY-MM-DD)" default:"1y"`
+	CalendarID string    `name:"calendar" help:"Calendar ID" default:"primary"`
+	Max        int64     `name:"max" aliases:"limit" help:"Max results per service" default:"25"`
+}
+
+// searchHit is one thread or event.
+type searchHit struct {
+	Service string `json:"service"`
+	ID      string `json:"id"`
+	Date    string `json:"date,omitempty"`
+	Title   string `json:"title"`
+}
+
+func (c *SearchCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	query := strings.TrimSpace(strings.Join(c.Query, " "))
+	if query == "" && strings.TrimSpace(c.Project) == "" {
+		return usage("give a query, --project, or both")
+	}
+	if c.Max <= 0 {
+		return usage("--max must be > 0")
+	}
+
+	var label, propFilter string
+	if name := strings.TrimSpace(c.Project); name != "" {
+		projects, selectErr := selectProjects(account, name)
+		if selectErr != nil {
+			return selectErr
+		}
+		label = projects[0].LabelName()
+		propFilter = projectPropPrefix + projects[0].Name + "=1"
+	}
+	now := time.Now()
+	since := c.Since.Time(now)
+
+	gsvc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+	mailQuery := fmt.Sprintf("after:%d", since.Unix())
+	if label != "" {
+		mailQuery += " label:" + gmailLabelQuery(label)
+	}
+	if query != "" {
+		mailQuery += " " + query
+	}
+	threads, err := searchThreads(ctx, gsvc, mailQuery, c.Max)
+	if err != nil {
+		return err
+	}
+
+	csvc, err := newCalendarService(ctx, account)
+	if err != nil {
+		return err
+	}
+	events, err := searchEvents(ctx, csvc, c.CalendarID, query, propFilter, since, now, c.Max)
+	if err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{"threads": threads, "events": events})
+	}
+	if len(threads) == 0 && len(events) == 0 {
+		u.Err().Println("No results")
+		return nil
+	}
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "SERVICE\tID\tDATE\tTITLE")
+	for _, h := range append(threads, events...) {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", h.Service, h.ID, orEmpty(h.Date, "-"), sanitizeTab(h.Title))
+	}
+	return nil
+}
+
+// searchThreads lists matching threads with the subject of their first
+// message.
+func searchThreads(ctx context.Context, svc *gmail.Service, query string, limit int64) ([]searchHit, error) {
+	resp, err := svc.Users.Threads.List("me").Q(query).MaxResults(limit).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	hits := make([]searchHit, len(resp.Threads))
+	err = fetchGmailConcurrently(ctx, len(resp.Threads), gmailQuotaThreadGet, func(ctx context.Context, idx int) error {
+		id := resp.Threads[idx].Id
+		thread, getErr := svc.Users.Threads.Get("me", id).Format("metadata").MetadataHeaders("Subject").Context(ctx).Do()
+		if getErr != nil {
+			return fmt.Errorf("thread %s: %w", id, getErr)
+		}
+		hit := searchHit{Service: "gmail", ID: id}
+		if len(thread.Messages) > 0 && thread.Messages[0] != nil {
+			first, last := thread.Messages[0], thread.Messages[len(thread.Messages)-1]
+			hit.Title = headerValue(first.Payload, "Subject")
+			if last != nil && last.InternalDate > 0 {
+				hit.Date = time.UnixMilli(last.InternalDate).Local().Format("2006-01-02")
+			}
+		}
+		hits[idx] = hit
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hits, nil
+}
+
+// searchEvents lists the most recent matching events between since and now,
+// newest first. The API only orders by ascending start time, so it pages
+// through the whole window and keeps the last limit events.
+func searchEvents(ctx context.Context, svc *calendar.Service, calendarID, query, propFilter string, since, now time.Time, limit int64) ([]searchHit, error) {
+	call := svc.Events.List(calendarID).
+		TimeMin(since.Format(time.RFC3339)).
+		TimeMax(now.Format(time.RFC3339)).
+		SingleEvents(true).
+		OrderBy("startTime").
+		MaxResults(250)
+	if query != "" {
+		call = call.Q(query)
+	}
+	if propFilter != "" {
+		call = call.PrivateExtendedProperty(propFilter)
+	}
+	hits := []searchHit{}
+	err := call.Pages(ctx, func(resp *calendar.Events) error {
+		for _, e := range resp.Items {
+			if e == nil || e.Status == "cancelled" {
+				continue
+			}
+			hit := searchHit{Service: "calendar", ID: e.Id, Title: e.Summary}
+			if e.Start != nil {
+				hit.Date = orEmpty(e.Start.Date, e.Start.DateTime)
+				if len(hit.Date) > 10 {
+					hit.Date = hit.Date[:10]
+				}
+			}
+			hits = append(hits, hit)
+		}
+		if over := int64(len(hits)) - limit; over > 0 {
+			hits = hits[over:]
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(hits)
+	return hits, nil
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/input"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// GuardrailFlag lets a send go through a blocking send guardrail.
+type GuardrailFlag struct {
+	OverrideGuardrail bool `name:"override-guardrail" help:"Send despite a blocking send guardrail (asks you to type its name)"`
+}
+
+// promptGuardrailOverride reads the typed confirmation for --override-guardrail.
+var promptGuardrailOverride = func(ctx context.Context, flags *RootFlags, prompt string) (string, error) {
+	// An override is a deliberate human decision: --force and scripts do not count.
+	if flags.NoInput || !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", usage("--override-guardrail needs an interactive terminal to type the confirmation")
+	}
+	return input.PromptLine(ctx, prompt)
+}
+
+// sendGuardrailCheck describes an outgoing message for the send guardrails.
+type sendGuardrailCheck struct {
+	Recipients     []string
+	HasAttachments bool
+}
+
+// enforceSendGuardrails applies the send_guardrails from config.json before
+// anything is sent: warn rules print a warning, block rules fail unless
+// overridden with a typed confirmation.
+func enforceSendGuardrails(ctx context.Context, flags *RootFlags, account string, override GuardrailFlag, check sendGuardrailCheck) error {
+	guardrails, err := config.SendGuardrailsFor(account)
+	if err != nil || len(guardrails) == 0 {
+		return err
+	}
+	u := ui.FromContext(ctx)
+
+	var blocked []string
+	for _, g := range guardrails {
+		action := strings.ToLower(strings.TrimSpace(g.Action))
+		if action == "" {
+			action = config.GuardrailWarn
+		}
+		if action != config.GuardrailWarn && action != config.GuardrailBlock {
+			return fmt.Errorf("send guardrail %q: invalid action %q (expected warn or block)", g.Name, g.Action)
+		}
+		if len(g.InternalDomains) == 0 {
+			return fmt.Errorf("send guardrail %q: internal_domains is required", g.Name)
+		}
+		if g.AttachmentsOnly && !check.HasAttachments {
+			continue
+		}
+		external := externalRecipients(check.Recipients, g.InternalDomains)
+		if len(external) == 0 {
+			continue
+		}
+		what := "recipients outside " + strings.Join(g.InternalDomains, ", ")
+		if g.AttachmentsOnly {
+			what += " with attachments"
+		}
+		msg := fmt.Sprintf("send guardrail %q: sending to %s (%s)", g.Name, what, strings.Join(external, ", "))
+		if action == config.GuardrailWarn {
+			u.Err().Printf("Warning: %s", msg)
+			continue
+		}
+		if !override.OverrideGuardrail {
+			return usagef("blocked by %s; re-run with --override-guardrail to send anyway", msg)
+		}
+		u.Err().Println(msg)
+		blocked = append(blocked, g.Name)
+	}
+
+	for _, name := range blocked {
+		line, readErr := promptGuardrailOverride(ctx, flags, fmt.Sprintf("Type %q to send anyway: ", name))
+		if readErr != nil {
+			if errors.Is(readErr, io.EOF) {
+				return &ExitError{Code: 1, Err: errors.New("cancelled")}
+			}
+			return readErr
+		}
+		if strings.TrimSpace(line) != name {
+			return &ExitError{Code: 1, Err: fmt.Errorf("cancelled: confirmation did not match %q", name)}
+		}
+	}
+	return nil
+}
+
+// externalRecipients returns the recipients whose domain is not one of the
+// internal domains or a subdomain of one.
+func externalRecipients(recipients, internalDomains []string) []string {
+	var out []string
+	for _, r := range recipients {
+		addrs := parseEmailAddresses(r)
+		for _, addr := range addrs {
+			_, domain, ok := strings.Cut(addr, "@")
+			if !ok || !domainIsInternal(domain, internalDomains) {
+				out = append(out, addr)
+			}
+		}
+	}
+	return deduplicateAddresses(out)
+}
+
+func domainIsInternal(domain string, internalDomains []string) bool {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	for _, d := range internalDomains {
+		d = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(d), "@"))
+		if d != "" && (domain == d || strings.HasSuffix(domain, "."+d)) {
+			return true
+		}
+	}
+	return false
+}
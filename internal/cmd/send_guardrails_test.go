@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+func TestExternalRecipients(t *testing.T) {
+	got := externalRecipients(
+		[]string{"a@corp.com, Bob <bob@eu.corp.com>", "x@other.com", "X@Other.com", "y@notcorp.com"},
+		[]string{"@corp.com"},
+	)
+	want := []string{"x@other.com", "y@notcorp.com"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("externalRecipients = %v, want %v", got, want)
+	}
+}
+
+func TestSendGuardrails(t *testing.T) {
+	orig, err := config.ReadConfig()
+	if err != nil {
+		t.Fatalf("ReadConfig: %v", err)
+	}
+	t.Cleanup(func() { _ = config.WriteConfig(orig) })
+	cfg := orig
+	cfg.SendGuardrails = []config.SendGuardrail{
+		{Name: "external-attachments", InternalDomains: []string{"corp.com"}, AttachmentsOnly: true, Action: config.GuardrailBlock},
+		{Name: "external", InternalDomains: []string{"corp.com"}, Action: config.GuardrailWarn},
+	}
+	if err := config.WriteConfig(cfg); err != nil {
+		t.Fatalf("WriteConfig: %v", err)
+	}
+
+	sent := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && strings.Contains(r.URL.Path, "/users/me/messages/send") {
+			sent++
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "s1", "threadId": "t1"})
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+	stubGmailService(t, srv)
+
+	typed := ""
+	origPrompt := promptGuardrailOverride
+	t.Cleanup(func() { promptGuardrailOverride = origPrompt })
+	promptGuardrailOverride = func(context.Context, *RootFlags, string) (string, error) { return typed, nil }
+
+	attachment := filepath.Join(t.TempDir(), "plan.pdf")
+	if err := os.WriteFile(attachment, []byte("%PDF"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	send := func(to string, extra ...string) (string, error) {
+		var runErr error
+		stderr := captureStderr(t, func() {
+			_ = captureStdout(t, func() {
+				args := []string{"--json", "--account", "me@corp.com", "gmail", "send", "--to", to, "--subject", "S", "--body", "B"}
+				runErr = Execute(append(args, extra...))
+			})
+		})
+		return stderr, runErr
+	}
+
+	t.Run("internal subdomain", func(t *testing.T) {
+		sent = 0
+		stderr, err := send("team@eu.corp.com", "--attach", attachment)
+		if err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+		if sent != 1 || strings.Contains(stderr, "guardrail") {
+			t.Fatalf("sent=%d stderr=%q", sent, stderr)
+		}
+	})
+
+	t.Run("warn", func(t *testing.T) {
+		sent = 0
+		stderr, err := send("x@other.com")
+		if err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+		if sent != 1 || !strings.Contains(stderr, `Warning: send guardrail "external"`) {
+			t.Fatalf("sent=%d stderr=%q", sent, stderr)
+		}
+	})
+
+	t.Run("block", func(t *testing.T) {
+		sent = 0
+		_, err := send("x@other.com", "--attach", attachment)
+		if err == nil || ExitCode(err) != 2 || !strings.Contains(err.Error(), "--override-guardrail") {
+			t.Fatalf("expected usage error, got %v", err)
+		}
+		if sent != 0 {
+			t.Fatalf("blocked message was sent")
+		}
+	})
+
+	t.Run("override mismatch", func(t *testing.T) {
+		sent = 0
+		typed = "yes"
+		_, err := send("x@other.com", "--attach", attachment, "--override-guardrail")
+		if err == nil || ExitCode(err) != 1 {
+			t.Fatalf("expected cancel, got %v", err)
+		}
+		if sent != 0 {
+			t.Fatalf("message was sent without a matching confirmation")
+		}
+	})
+
+	t.Run("override", func(t *testing.T) {
+		sent = 0
+		typed = "external-attachments"
+		if _, err := send("x@other.com", "--attach", attachment, "--override-guardrail"); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+		if sent != 1 {
+			t.Fatalf("sent=%d", sent)
+		}
+	})
+
+	t.Run("quota alert email", func(t *testing.T) {
+		sent = 0
+		stderr := captureStderr(t, func() {
+			u, uiErr := ui.New(ui.Options{Stdout: os.Stdout, Stderr: os.Stderr, Color: "never"})
+			if uiErr != nil {
+				t.Fatalf("ui.New: %v", uiErr)
+			}
+			ctx := ui.WithUI(context.Background(), u)
+			if err := sendQuotaAlertEmail(ctx, &RootFlags{}, "me@corp.com", "ops@other.com", GuardrailFlag{}, "S", "B"); err != nil {
+				t.Fatalf("sendQuotaAlertEmail: %v", err)
+			}
+		})
+		if sent != 1 || !strings.Contains(stderr, `Warning: send guardrail "external"`) {
+			t.Fatalf("sent=%d stderr=%q", sent, stderr)
+		}
+	})
+}
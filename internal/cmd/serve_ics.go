@@ -0,0 +1,365 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/steipete/gogcli/internal/events"
+	"github.com/steipete/gogcli/internal/ics"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+type ServeCmd struct {
+	ICS ServeICSCmd `cmd:"" name:"ics" help:"Serve a read-only ICS feed of a calendar"`
+}
+
+type ServeICSCmd struct {
+	Calendar string        `name:"calendar" complete:"calendars" help:"Calendar ID" default:"primary"`
+	Listen   string        `name:"listen" help:"Listen address (host:port)" default:"127.0.0.1:8099"`
+	Path     string        `name:"path" help:"Feed path" default:"/calendar.ics"`
+	Token    string        `name:"token" help:"Shared token required as ?token= or Authorization: Bearer (required when not on loopback)"`
+	Query    string        `name:"query" short:"q" help:"Only include events matching this free-text query"`
+	Past     DayDuration   `name:"past" help:"How far back to include events (e.g. 30d, 2w, 72h)" default:"30d"`
+	Future   DayDuration   `name:"future" help:"How far ahead to include events (e.g. 180d)" default:"180d"`
+	Refresh  time.Duration `name:"refresh" help:"Re-fetch events at most this often" default:"5m"`
+	BusyOnly bool          `name:"busy-only" help:"Publish free/busy blocks only (titles become 'Busy'; no details or attendees)"`
+	Name     string        `name:"name" help:"Feed name shown by subscribers (default: calendar summary)"`
+}
+
+func (c *ServeICSCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+
+	calendarID := strings.TrimSpace(c.Calendar)
+	if calendarID == "" {
+		return usage("empty --calendar")
+	}
+	if !strings.HasPrefix(c.Path, "/") {
+		return usage("--path must start with '/'")
+	}
+	host, port, err := net.SplitHostPort(strings.TrimSpace(c.Listen))
+	if err != nil {
+		return usagef("invalid --listen %q: %v", c.Listen, err)
+	}
+	if p, convErr := strconv.Atoi(port); convErr != nil || p <= 0 {
+		return usagef("invalid --listen port %q", port)
+	}
+	// An empty host binds every interface, so it is not loopback here.
+	if c.Token == "" && (host == "" || !isLoopbackHost(host)) {
+		return usage("--token required when listening on a non-loopback address")
+	}
+	if c.Refresh < 0 {
+		return usage("--refresh must be >= 0")
+	}
+
+	svc, err := newCalendarService(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	name := strings.TrimSpace(c.Name)
+	if name == "" {
+		if entry, getErr := svc.CalendarList.Get(calendarID).Context(ctx).Do(); getErr == nil {
+			name = entry.Summary
+		}
+	}
+
+	feed := &icsFeedServer{
+		svc:        svc,
+		account:    account,
+		calendarID: calendarID,
+		name:       name,
+		path:       c.Path,
+		token:      c.Token,
+		query:      strings.TrimSpace(c.Query),
+		past:       c.Past.Duration(),
+		future:     c.Future.Duration(),
+		refresh:    c.Refresh,
+		busyOnly:   c.BusyOnly,
+		now:        time.Now,
+		logf:       u.Err().Printf,
+	}
+
+	addr := net.JoinHostPort(host, port)
+	u.Err().Printf("serve: ICS feed for %s on http://%s%s", calendarID, addr, c.Path)
+	return serveUntilInterrupted(ctx, &http.Server{
+		Addr:              addr,
+		Handler:           feed,
+		ReadHeaderTimeout: 5 * time.Second,
+	})
+}
+
+// icsFeedServer renders a calendar window as ICS, caching the rendered feed
+// for the refresh interval so subscribers polling often do not burn quota.
+type icsFeedServer struct {
+	svc        *calendar.Service
+	account    string
+	calendarID string
+	name       string
+	path       string
+	token      string
+	query      string
+	past       time.Duration
+	future     time.Duration
+	refresh    time.Duration
+	busyOnly   bool
+	now        func() time.Time
+	logf       func(string, ...any)
+
+	mu         sync.Mutex
+	body       []byte
+	etag       string
+	fetchedAt  time.Time
+	refreshing chan struct{} // closed when the in-flight fetch finishes
+}
+
+func (s *icsFeedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != s.path {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.authorized(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	body, etag, err := s.feed(r.Context())
+	if err != nil {
+		s.logf("serve: fetch events: %v", err)
+		http.Error(w, "failed to load calendar", http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d", int(s.refresh.Seconds())))
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	if r.Method == http.MethodHead {
+		return
+	}
+	_, _ = w.Write(body)
+}
+
+func (s *icsFeedServer) authorized(r *http.Request) bool {
+	if s.token == "" {
+		return true
+	}
+	got := r.URL.Query().Get("token")
+	if got == "" {
+		if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			got = strings.TrimPrefix(auth, "Bearer ")
+		}
+	}
+	return subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) == 1
+}
+
+// feed returns the cached feed, re-fetching when stale. A failed refresh
+// keeps serving the previous feed so subscribers see stale data, not errors.
+// The fetch runs outside the lock: while one request refreshes, others get
+// the previous feed, and only the very first fetch makes them wait.
+func (s *icsFeedServer) feed(ctx context.Context) ([]byte, string, error) {
+	var now time.Time
+	s.mu.Lock()
+	for {
+		now = s.now()
+		fresh := s.body != nil && now.Sub(s.fetchedAt) < s.refresh
+		if fresh || (s.body != nil && s.refreshing != nil) {
+			body, etag := s.body, s.etag
+			s.mu.Unlock()
+			return body, etag, nil
+		}
+		if s.refreshing == nil {
+			break
+		}
+		wait := s.refreshing
+		s.mu.Unlock()
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return nil, "", ctx.Err()
+		}
+		s.mu.Lock()
+	}
+	done := make(chan struct{})
+	s.refreshing = done
+	s.mu.Unlock()
+
+	body, err := s.render(ctx, now)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refreshing = nil
+	close(done)
+	if err != nil {
+		if s.body != nil {
+			s.logf("serve: refresh failed, serving cached feed: %v", err)
+			return s.body, s.etag, nil
+		}
+		return nil, "", err
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+	// A refresh is not a job; feed.refreshed keeps it out of job.finished
+	// consumers, and unchanged refreshes are not logged at all.
+	if etag != s.etag {
+		if emitErr := events.Emit(events.TypeFeedRefreshed, s.account, map[string]any{
+			"feed":       "ics",
+			"calendarId": s.calendarID,
+			"bytes":      len(body),
+		}); emitErr != nil {
+			s.logf("serve: event log: %v", emitErr)
+		}
+	}
+	s.body = body
+	s.etag = etag
+	s.fetchedAt = now
+	return s.body, s.etag, nil
+}
+
+func (s *icsFeedServer) render(ctx context.Context, now time.Time) ([]byte, error) {
+	var events []ics.Event
+	pageToken := ""
+	for {
+		call := s.svc.Events.List(s.calendarID).
+			TimeMin(now.Add(-s.past).Format(time.RFC3339)).
+			TimeMax(now.Add(s.future).Format(time.RFC3339)).
+			SingleEvents(true).
+			OrderBy("startTime").
+			MaxResults(2500).
+			PageToken(pageToken).
+			Context(ctx)
+		if s.query != "" {
+			call = call.Q(s.query)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range resp.Items {
+			if ev, ok := icsEventFromCalendar(e, s.busyOnly); ok {
+				events = append(events, ev)
+			}
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+
+	var buf bytes.Buffer
+	if err := ics.Encode(&buf, ics.Calendar{Name: s.name, Method: "PUBLISH", Events: events}, now); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// icsEventFromCalendar converts a Calendar API event. Cancelled events and
+// events without a parseable start are skipped, and so are events marked
+// "free" (transparent) in a busy-only feed.
+func icsEventFromCalendar(e *calendar.Event, busyOnly bool) (ics.Event, bool) {
+	if e == nil || e.Status == "cancelled" || e.Start == nil {
+		return ics.Event{}, false
+	}
+	if busyOnly && e.Transparency == "transparent" {
+		return ics.Event{}, false
+	}
+	out := ics.Event{
+		UID:         e.ICalUID,
+		Status:      e.Status,
+		Transparent: e.Transparency == "transparent",
+		Sequence:    e.Sequence,
+	}
+	if out.UID == "" {
+		out.UID = e.Id
+	}
+	// Recurring instances share an iCalUID; keep them distinct.
+	if e.RecurringEventId != "" {
+		out.UID = e.Id + "@google.com"
+	}
+
+	var err error
+	if e.Start.Date != "" {
+		out.AllDay = true
+		if out.Start, err = time.Parse("2006-01-02", e.Start.Date); err != nil {
+			return ics.Event{}, false
+		}
+		if e.End != nil && e.End.Date != "" {
+			out.End, _ = time.Parse("2006-01-02", e.End.Date)
+		}
+	} else {
+		if out.Start, err = time.Parse(time.RFC3339, e.Start.DateTime); err != nil {
+			return ics.Event{}, false
+		}
+		if e.End != nil && e.End.DateTime != "" {
+			out.End, _ = time.Parse(time.RFC3339, e.End.DateTime)
+		}
+	}
+
+	if busyOnly {
+		out.Summary = "Busy"
+		return out, true
+	}
+
+	out.Summary = e.Summary
+	out.Description = e.Description
+	out.Location = e.Location
+	out.URL = e.HtmlLink
+	out.Created, _ = time.Parse(time.RFC3339, e.Created)
+	out.LastModified, _ = time.Parse(time.RFC3339, e.Updated)
+	if e.Organizer != nil && e.Organizer.Email != "" {
+		out.Organizer = &ics.Attendee{Email: e.Organizer.Email, Name: e.Organizer.DisplayName}
+	}
+	for _, a := range e.Attendees {
+		if a == nil || a.Email == "" || a.Resource {
+			continue
+		}
+		role := "REQ-PARTICIPANT"
+		if a.Optional {
+			role = "OPT-PARTICIPANT"
+		}
+		out.Attendees = append(out.Attendees, ics.Attendee{
+			Email:    a.Email,
+			Name:     a.DisplayName,
+			Role:     role,
+			PartStat: icsPartStat(a.ResponseStatus),
+		})
+	}
+	return out, true
+}
+
+func icsPartStat(responseStatus string) string {
+	switch responseStatus {
+	case "accepted":
+		return "ACCEPTED"
+	case "declined":
+		return "DECLINED"
+	case "tentative":
+		return "TENTATIVE"
+	default:
+		return "NEEDS-ACTION"
+	}
+}
@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/option"
+)
+
+func newICSTestCalendar(t *testing.T, calls *atomic.Int32) *calendar.Service {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/users/me/calendarList/primary"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "primary", "summary": "Work"})
+		case strings.HasSuffix(r.URL.Path, "/calendars/primary/events"):
+			calls.Add(1)
+			if r.URL.Query().Get("singleEvents") != "true" {
+				t.Errorf("expected singleEvents=true")
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"items": []map[string]any{
+				{
+					"id": "e1", "iCalUID": "e1@google.com", "summary": "Design review", "location": "Room 4",
+					"start":     map[string]any{"dateTime": "2026-03-02T10:00:00+01:00"},
+					"end":       map[string]any{"dateTime": "2026-03-02T11:00:00+01:00"},
+					"attendees": []map[string]any{{"email": "a@example.com", "responseStatus": "accepted"}},
+				},
+				{
+					"id": "e2", "summary": "Offsite",
+					"start": map[string]any{"date": "2026-03-05"},
+					"end":   map[string]any{"date": "2026-03-06"},
+				},
+				{"id": "e3", "status": "cancelled", "start": map[string]any{"date": "2026-03-07"}},
+				{
+					"id": "e4", "summary": "Maybe lunch", "transparency": "transparent",
+					"start": map[string]any{"dateTime": "2026-03-03T12:00:00Z"},
+					"end":   map[string]any{"dateTime": "2026-03-03T13:00:00Z"},
+				},
+			}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	svc, err := calendar.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	return svc
+}
+
+func TestICSFeedServer(t *testing.T) {
+	var calls atomic.Int32
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	feed := &icsFeedServer{
+		svc:        newICSTestCalendar(t, &calls),
+		calendarID: "primary",
+		name:       "Work",
+		path:       "/calendar.ics",
+		token:      "s3cret",
+		past:       24 * time.Hour,
+		future:     30 * 24 * time.Hour,
+		refresh:    time.Minute,
+		now:        func() time.Time { return now },
+		logf:       func(string, ...any) {},
+	}
+
+	rec := httptest.NewRecorder()
+	feed.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/calendar.ics", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without token, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	feed.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/calendar.ics?token=s3cret", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/calendar") {
+		t.Fatalf("content-type=%q", ct)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{"X-WR-CALNAME:Work", "UID:e1@google.com", "SUMMARY:Design review", "DTSTART:20260302T090000Z", "DTSTART;VALUE=DATE:20260305", "ATTENDEE;ROLE=REQ-PARTICIPANT;PARTSTAT=ACCEPTED:mailto:a@example.com"} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("feed missing %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "e3") {
+		t.Fatalf("cancelled event should be skipped:\n%s", body)
+	}
+
+	// Cached within the refresh window; bearer auth and ETag revalidation work.
+	req := httptest.NewRequest(http.MethodGet, "/calendar.ics", nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	req.Header.Set("If-None-Match", rec.Header().Get("ETag"))
+	rec = httptest.NewRecorder()
+	feed.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("expected 304, got %d", rec.Code)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected cached feed, got %d fetches", calls.Load())
+	}
+
+	now = now.Add(2 * time.Minute)
+	rec = httptest.NewRecorder()
+	feed.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/calendar.ics?token=s3cret", nil))
+	if rec.Code != http.StatusOK || calls.Load() != 2 {
+		t.Fatalf("expected refresh, status=%d fetches=%d", rec.Code, calls.Load())
+	}
+}
+
+func TestICSFeedServer_BusyOnly(t *testing.T) {
+	var calls atomic.Int32
+	feed := &icsFeedServer{
+		svc:        newICSTestCalendar(t, &calls),
+		calendarID: "primary",
+		path:       "/calendar.ics",
+		busyOnly:   true,
+		future:     24 * time.Hour,
+		now:        time.Now,
+		logf:       func(string, ...any) {},
+	}
+	rec := httptest.NewRecorder()
+	feed.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/calendar.ics", nil))
+	body := rec.Body.String()
+	if !strings.Contains(body, "SUMMARY:Busy") || strings.Contains(body, "Design review") || strings.Contains(body, "Room 4") || strings.Contains(body, "ATTENDEE") {
+		t.Fatalf("busy-only feed leaked details:\n%s", body)
+	}
+	if strings.Contains(body, "UID:e4") {
+		t.Fatalf("busy-only feed should skip free (transparent) events:\n%s", body)
+	}
+}
+
+func TestICSFeedServer_RefreshDoesNotBlockReaders(t *testing.T) {
+	release := make(chan struct{})
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) > 1 {
+			<-release
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items":[]}`))
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() {
+		select {
+		case <-release:
+		default:
+			close(release)
+		}
+	})
+	svc, err := calendar.NewService(context.Background(), option.WithoutAuthentication(), option.WithHTTPClient(srv.Client()), option.WithEndpoint(srv.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+
+	var nowNanos atomic.Int64
+	nowNanos.Store(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC).UnixNano())
+	feed := &icsFeedServer{
+		svc:        svc,
+		calendarID: "primary",
+		path:       "/calendar.ics",
+		refresh:    time.Minute,
+		now:        func() time.Time { return time.Unix(0, nowNanos.Load()) },
+		logf:       func(string, ...any) {},
+	}
+	get := func() int {
+		rec := httptest.NewRecorder()
+		feed.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/calendar.ics", nil))
+		return rec.Code
+	}
+	if code := get(); code != http.StatusOK {
+		t.Fatalf("first fetch: %d", code)
+	}
+
+	// The feed goes stale; one request starts a slow refresh.
+	nowNanos.Add(int64(2 * time.Minute))
+	slow := make(chan int, 1)
+	go func() { slow <- get() }()
+	for calls.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Other readers get the previous feed instead of waiting on it.
+	done := make(chan int, 1)
+	go func() { done <- get() }()
+	select {
+	case code := <-done:
+		if code != http.StatusOK {
+			t.Fatalf("reader during refresh: %d", code)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("reader blocked behind the refresh")
+	}
+	close(release)
+	if code := <-slow; code != http.StatusOK || calls.Load() != 2 {
+		t.Fatalf("refresh: status=%d fetches=%d", code, calls.Load())
+	}
+}
+
+func TestServeICSCmd(t *testing.T) {
+	var calls atomic.Int32
+	svc := newICSTestCalendar(t, &calls)
+	origCal := newCalendarService
+	origListen := listenAndServe
+	t.Cleanup(func() {
+		newCalendarService = origCal
+		listenAndServe = origListen
+	})
+	newCalendarService = func(context.Context, string) (*calendar.Service, error) { return svc, nil }
+
+	var served *http.Server
+	listenAndServe = func(s *http.Server) error {
+		served = s
+		return nil
+	}
+
+	_ = captureStderr(t, func() {
+		if err := Execute([]string{"--account", "a@b.com", "serve", "ics", "--listen", "127.0.0.1:9999"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	if served == nil || served.Addr != "127.0.0.1:9999" {
+		t.Fatalf("unexpected server: %#v", served)
+	}
+	feed, ok := served.Handler.(*icsFeedServer)
+	if !ok || feed.name != "Work" || feed.past != 30*24*time.Hour {
+		t.Fatalf("unexpected handler: %#v", served.Handler)
+	}
+
+	_ = captureStderr(t, func() {
+		err := Execute([]string{"--account", "a@b.com", "serve", "ics", "--listen", ":8099"})
+		if err == nil || ExitCode(err) != 2 {
+			t.Fatalf("expected usage error without --token on all interfaces, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+const (
+	settingSet    = "set"
+	settingAdd    = "add"
+	settingRemove = "remove"
+)
+
+// SettingsDiffFlags compares a settings command's live state with a
+// desired-state file and optionally converges to it.
+type SettingsDiffFlags struct {
+	DiffWith string `name:"diff-with" help:"Compare live settings with a desired-state JSON file (- for stdin); the command's own --json output works as a starting point"`
+	Apply    bool   `name:"apply" help:"With --diff-with: change the live settings to match the file"`
+}
+
+func (f SettingsDiffFlags) active() bool {
+	return strings.TrimSpace(f.DiffWith) != "" || f.Apply
+}
+
+// settingChange is one difference between live and desired settings. Paths
+// are dotted JSON field names; list items are keyed in brackets.
+type settingChange struct {
+	Op   string `json:"op"`
+	Path string `json:"path"`
+	From any    `json:"from,omitempty"`
+	To   any    `json:"to,omitempty"`
+}
+
+// settingsState is one settings area that supports --diff-with. plan keeps
+// what it needs so apply can converge without fetching again.
+type settingsState interface {
+	plan(ctx context.Context, desired json.RawMessage) ([]settingChange, error)
+	apply(ctx context.Context) error
+}
+
+// run diffs (and with --apply converges) state against the desired file.
+// wrapKey is the key the command's --json output puts the settings under;
+// files saved from that output are unwrapped.
+func (f SettingsDiffFlags) run(ctx context.Context, flags *RootFlags, area, wrapKey string, state settingsState) error {
+	u := ui.FromContext(ctx)
+	if strings.TrimSpace(f.DiffWith) == "" {
+		return usage("--apply needs --diff-with <file>")
+	}
+	desired, err := readDesiredState(strings.TrimSpace(f.DiffWith), wrapKey)
+	if err != nil {
+		return err
+	}
+	changes, err := state.plan(ctx, desired)
+	if err != nil {
+		return err
+	}
+
+	applied := false
+	if f.Apply && len(changes) > 0 {
+		removals := 0
+		for _, ch := range changes {
+			if ch.Op == settingRemove {
+				removals++
+			}
+		}
+		if removals > 0 {
+			if err := confirmDestructive(ctx, flags, fmt.Sprintf("remove %d %s not in %s", removals, area, f.DiffWith)); err != nil {
+				return err
+			}
+		}
+		if err := state.apply(ctx); err != nil {
+			return err
+		}
+		applied = true
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"changes": changes,
+			"inSync":  len(changes) == 0,
+			"applied": applied,
+		})
+	}
+	if len(changes) == 0 {
+		u.Err().Printf("%s match %s", area, f.DiffWith)
+		return nil
+	}
+	w, flush := tableWriter(ctx)
+	fmt.Fprintln(w, "OP\tPATH\tFROM\tTO")
+	for _, ch := range changes {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", ch.Op, ch.Path, settingValueText(ch.From), settingValueText(ch.To))
+	}
+	flush()
+	if applied {
+		u.Err().Printf("Applied %d change(s)", len(changes))
+	} else if !f.Apply {
+		u.Err().Println("Re-run with --apply to make these changes")
+	}
+	return nil
+}
+
+func readDesiredState(path, wrapKey string) (json.RawMessage, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		expanded, expandErr := config.ExpandPath(path)
+		if expandErr != nil {
+			return nil, expandErr
+		}
+		data, err = os.ReadFile(expanded) //nolint:gosec // user-provided path
+	}
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(data)
+	if !json.Valid(data) {
+		return nil, usagef("%s is not valid JSON", path)
+	}
+	if wrapKey != "" && bytes.HasPrefix(data, []byte("{")) {
+		var wrapped map[string]json.RawMessage
+		if err := json.Unmarshal(data, &wrapped); err == nil {
+			if inner, ok := wrapped[wrapKey]; ok {
+				return inner, nil
+			}
+		}
+	}
+	return data, nil
+}
+
+// toJSONValue converts API structs to the generic JSON form diffs compare.
+func toJSONValue(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// diffSettingValues appends the fields of desired that differ from live.
+// Fields the desired state leaves out are not compared, and a missing live
+// field equals its zero value (the API omits false, 0, and "").
+func diffSettingValues(path string, live, desired any, skip map[string]bool, out []settingChange) []settingChange {
+	d, ok := desired.(map[string]any)
+	if !ok {
+		if !settingValuesEqual(live, desired) {
+			out = append(out, settingChange{Op: settingSet, Path: path, From: live, To: desired})
+		}
+		return out
+	}
+	l, _ := live.(map[string]any)
+	keys := make([]string, 0, len(d))
+	for k := range d {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if skip[k] {
+			continue
+		}
+		out = diffSettingValues(joinSettingPath(path, k), l[k], d[k], skip, out)
+	}
+	return out
+}
+
+func settingValuesEqual(live, desired any) bool {
+	if live == nil {
+		switch v := desired.(type) {
+		case nil:
+			return true
+		case bool:
+			return !v
+		case string:
+			return v == ""
+		case float64:
+			return v == 0
+		case []any:
+			return len(v) == 0
+		case map[string]any:
+			return len(v) == 0
+		}
+		return false
+	}
+	return reflect.DeepEqual(live, desired)
+}
+
+func joinSettingPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// mergeSettingValues returns live with the fields of desired laid over it,
+// recursing into objects, so an update keeps what the file leaves out.
+func mergeSettingValues(live, desired map[string]any, skip map[string]bool) map[string]any {
+	out := make(map[string]any, len(live)+len(desired))
+	for k, v := range live {
+		out[k] = v
+	}
+	for k, v := range desired {
+		if skip[k] {
+			continue
+		}
+		dm, dOK := v.(map[string]any)
+		lm, lOK := out[k].(map[string]any)
+		if dOK && lOK {
+			out[k] = mergeSettingValues(lm, dm, nil)
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// convertSettingValue decodes a generic JSON value into an API struct.
+func convertSettingValue(v any, dst any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
+
+// settingKey is a stable identity for list items without a natural key.
+func settingKey(v any) string {
+	data, _ := json.Marshal(v) // maps marshal with sorted keys
+	return string(data)
+}
+
+func settingValueText(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return "-"
+	case string:
+		return sanitizeTab(t)
+	default:
+		data, _ := json.Marshal(t)
+		return sanitizeTab(string(data))
+	}
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiffSettingValues(t *testing.T) {
+	live := map[string]any{"enableAutoReply": true, "responseSubject": "Away", "nested": map[string]any{"a": "x"}}
+	desired := map[string]any{
+		"enableAutoReply":    true,
+		"responseSubject":    "Out",
+		"restrictToContacts": false, // omitted by the API when false
+		"nested":             map[string]any{"a": "y"},
+	}
+	changes := diffSettingValues("", live, desired, nil, nil)
+	var paths []string
+	for _, ch := range changes {
+		paths = append(paths, ch.Path)
+	}
+	if strings.Join(paths, ",") != "nested.a,responseSubject" {
+		t.Fatalf("changes = %+v", changes)
+	}
+
+	merged := mergeSettingValues(live, map[string]any{"nested": map[string]any{"b": "z"}, "skip": 1}, map[string]bool{"skip": true})
+	if merged["responseSubject"] != "Away" || merged["nested"].(map[string]any)["a"] != "x" || merged["nested"].(map[string]any)["b"] != "z" || merged["skip"] != nil {
+		t.Fatalf("merged = %v", merged)
+	}
+}
+
+func TestGmailSettingsDiffWith(t *testing.T) {
+	var vacationUpdate map[string]any
+	var created, deleted []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/settings/vacation") && r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]any{"enableAutoReply": true, "responseSubject": "Away"})
+		case strings.HasSuffix(r.URL.Path, "/settings/vacation") && r.Method == http.MethodPut:
+			_ = json.NewDecoder(r.Body).Decode(&vacationUpdate)
+			_ = json.NewEncoder(w).Encode(vacationUpdate)
+		case strings.HasSuffix(r.URL.Path, "/settings/filters") && r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]any{"filter": []map[string]any{
+				{"id": "f1", "criteria": map[string]any{"from": "a@example.com"}, "action": map[string]any{"addLabelIds": []string{"L1"}}},
+				{"id": "f2", "criteria": map[string]any{"from": "old@example.com"}, "action": map[string]any{"removeLabelIds": []string{"INBOX"}}},
+			}})
+		case strings.HasSuffix(r.URL.Path, "/settings/filters") && r.Method == http.MethodPost:
+			body, _ := io.ReadAll(r.Body)
+			created = append(created, string(body))
+			_, _ = w.Write(body)
+		case strings.Contains(r.URL.Path, "/settings/filters/") && r.Method == http.MethodDelete:
+			deleted = append(deleted, r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	stubGmailService(t, srv)
+
+	dir := t.TempDir()
+	write := func(name, body string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	run := func(args ...string) map[string]any {
+		var out map[string]any
+		stdout := captureStdout(t, func() {
+			_ = captureStderr(t, func() {
+				if err := Execute(append([]string{"--json", "--account", "me@example.com", "gmail"}, args...)); err != nil {
+					t.Fatalf("Execute: %v", err)
+				}
+			})
+		})
+		if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+			t.Fatalf("json: %v\n%s", err, stdout)
+		}
+		return out
+	}
+
+	// A file saved from `gmail vacation get --json` is unwrapped.
+	vacation := write("vacation.json", `{"vacation":{"enableAutoReply":true,"responseSubject":"Out of office"}}`)
+	out := run("vacation", "get", "--diff-with", vacation)
+	if out["inSync"] != false || out["applied"] != false || len(out["changes"].([]any)) != 1 || vacationUpdate != nil {
+		t.Fatalf("diff: %v", out)
+	}
+	out = run("vacation", "get", "--diff-with", vacation, "--apply")
+	if out["applied"] != true || vacationUpdate["responseSubject"] != "Out of office" || vacationUpdate["enableAutoReply"] != true {
+		t.Fatalf("apply: out=%v update=%v", out, vacationUpdate)
+	}
+
+	filters := write("filters.json", `[
+		{"id":"ignored","criteria":{"from":"a@example.com"},"action":{"addLabelIds":["L1"]}},
+		{"criteria":{"from":"new@example.com"},"action":{"addLabelIds":["L2"]}}
+	]`)
+	out = run("filters", "list", "--diff-with", filters)
+	ops := map[string]string{}
+	for _, ch := range out["changes"].([]any) {
+		m := ch.(map[string]any)
+		ops[m["path"].(string)] = m["op"].(string)
+	}
+	if len(ops) != 2 || ops["filters"] != settingAdd || ops["filters.f2"] != settingRemove {
+		t.Fatalf("filter changes = %v", out["changes"])
+	}
+	run("--force", "filters", "list", "--diff-with", filters, "--apply")
+	if len(created) != 1 || !strings.Contains(created[0], "new@example.com") || strings.Join(deleted, ",") != "f2" {
+		t.Fatalf("created=%v deleted=%v", created, deleted)
+	}
+}
+
+func TestReadGroupDesiredState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "groups.json")
+	body := `{"groups":[{"email":"eng@example.com","description":"","labels":["security","cloudidentity.googleapis.com/groups.discussion_forum"]},{"email":"ops@example.com","displayName":"Ops"}]}`
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := readGroupDesiredState(path)
+	if err != nil {
+		t.Fatalf("readGroupDesiredState: %v", err)
+	}
+	if len(rows) != 2 || rows[0].DisplayName != nil || rows[0].Description == nil || *rows[0].Description != "" ||
+		strings.Join(rows[0].Labels, ",") != "discussion_forum,security" || rows[1].Labels != nil || *rows[1].DisplayName != "Ops" {
+		t.Fatalf("rows = %+v", rows)
+	}
+
+	if err := Execute([]string{"--account", "me@example.com", "groups", "update", "--apply"}); ExitCode(err) != 2 {
+		t.Fatalf("--apply without --diff-with: %v", err)
+	}
+}
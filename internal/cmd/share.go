@@ -0,0 +1,419 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"google.golang.org/api/drive/v3"
+	gapi "google.golang.org/api/googleapi"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+const (
+	shareRedactEmails = "emails"
+	shareRedactPhones = "phones"
+)
+
+var (
+	shareEmailRe = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	sharePhoneRe = regexp.MustCompile(`\+?\d[\d\s().\-]{7,}\d`)
+
+	shareNow = time.Now
+)
+
+type ShareCmd struct {
+	Create ShareCreateCmd `cmd:"" name:"create" default:"withargs" help:"Share a Drive file or a Gmail message through one expiring link"`
+	Expire ShareExpireCmd `cmd:"" name:"expire" help:"Revoke links whose expiry has passed (run from cron)"`
+	List   ShareListCmd   `cmd:"" name:"list" aliases:"ls" help:"List temporary shares made with gog share"`
+}
+
+type ShareCreateCmd struct {
+	Resource      string      `arg:"" name:"resource" help:"drive:<fileId> or gmail:<messageId>"`
+	Expires       DayDuration `name:"expires" help:"How long the link works (e.g. 48h, 7d)" default:"48h"`
+	To            []string    `name:"to" help:"Share only with these people; Drive expires their access itself (repeatable)"`
+	Anyone        bool        `name:"anyone" help:"Create an \"anyone with the link\" URL instead; Drive cannot expire it, so it stays open until gog share expire revokes it"`
+	Role          string      `name:"role" help:"Permission: reader|commenter" enum:"reader,commenter" default:"reader"`
+	Parent        string      `name:"parent" help:"Drive folder ID for the rendered Gmail message (default: My Drive root)"`
+	Redact        []string    `name:"redact" help:"Redact from a Gmail rendering: emails, phones (repeatable)" enum:"emails,phones"`
+	RedactPattern []string    `name:"redact-pattern" help:"Also redact matches of this regular expression (repeatable)"`
+}
+
+// shareRecord is one temporary share. Drive cannot expire "anyone with the
+// link" permissions, so gog share expire revokes them; rendered Gmail
+// messages are trashed at expiry either way.
+type shareRecord struct {
+	Account      string    `json:"account"`
+	Kind         string    `json:"kind"`
+	Source       string    `json:"source"`
+	FileID       string    `json:"fileId"`
+	PermissionID string    `json:"permissionId,omitempty"`
+	URL          string    `json:"url"`
+	Rendered     bool      `json:"rendered,omitempty"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+func (c *ShareCreateCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	kind, id, err := parseLinkResource(c.Resource)
+	if err != nil {
+		return err
+	}
+	if kind != linkKindDrive && kind != linkKindGmail {
+		return usagef("cannot share %q (use drive:<fileId> or gmail:<messageId>)", c.Resource)
+	}
+	expires := c.Expires.Duration()
+	if expires <= 0 {
+		return usage("--expires must be positive")
+	}
+	switch {
+	case len(c.To) > 0 && c.Anyone:
+		return usage("use either --to or --anyone")
+	case len(c.To) == 0 && !c.Anyone:
+		return usage("required: --to <email> (access Drive expires itself) or --anyone (public link, revoked by gog share expire)")
+	case len(c.To) > 0 && expires > 365*24*time.Hour:
+		return usage("--expires must be at most a year when sharing with --to")
+	}
+	shareWith, err := bareAddresses(splitCSV(strings.Join(c.To, ",")))
+	if err != nil {
+		return err
+	}
+	redactors, err := shareRedactors(c.Redact, c.RedactPattern)
+	if err != nil {
+		return err
+	}
+	if kind == linkKindDrive && len(redactors) > 0 {
+		return usage("--redact applies to gmail: resources only")
+	}
+
+	svc, err := newDriveService(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	now := shareNow()
+	rec := shareRecord{Account: account, Kind: kind, Source: id, FileID: id, ExpiresAt: now.Add(expires).UTC(), CreatedAt: now.UTC()}
+	if kind == linkKindGmail {
+		rec.FileID, err = c.renderMessage(ctx, account, svc, id, redactors)
+		if err != nil {
+			return err
+		}
+		rec.Rendered = true
+	}
+
+	if c.Anyone {
+		u.Err().Printf("Warning: Drive does not expire \"anyone with the link\" access; the link stays open until `gog share expire` runs after %s (e.g. hourly from cron)", rec.ExpiresAt.Local().Format("2006-01-02 15:04"))
+		perm, permErr := svc.Permissions.Create(rec.FileID, &drive.Permission{Type: "anyone", Role: c.Role}).
+			SupportsAllDrives(true).
+			Fields("id").
+			Context(ctx).
+			Do()
+		if permErr != nil {
+			return fmt.Errorf("create link: %w", permErr)
+		}
+		rec.PermissionID = perm.Id
+	} else {
+		for _, email := range shareWith {
+			_, permErr := svc.Permissions.Create(rec.FileID, &drive.Permission{
+				Type:           "user",
+				Role:           c.Role,
+				EmailAddress:   email,
+				ExpirationTime: rec.ExpiresAt.Format(time.RFC3339),
+			}).SupportsAllDrives(true).Fields("id").Context(ctx).Do()
+			if permErr != nil {
+				return fmt.Errorf("share with %s: %w", email, permErr)
+			}
+		}
+	}
+
+	rec.URL, err = driveWebLink(ctx, svc, rec.FileID)
+	if err != nil {
+		return err
+	}
+	if err := updateShareRecords(func(records []shareRecord) []shareRecord { return append(records, rec) }); err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, rec)
+	}
+	u.Out().Println(rec.URL)
+	expiry := rec.ExpiresAt.Local().Format("2006-01-02 15:04")
+	if !c.Anyone {
+		u.Err().Printf("Shared with %s until %s", strings.Join(shareWith, ", "), expiry)
+	}
+	return nil
+}
+
+// renderMessage uploads the message as a Google Doc with only the main
+// headers and the text body (no attachments, scripts, or remote images).
+func (c *ShareCreateCmd) renderMessage(ctx context.Context, account string, svc *drive.Service, messageID string, redactors []shareRedactor) (string, error) {
+	gsvc, err := newGmailService(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	msg, err := gsvc.Users.Messages.Get("me", messageID).Format("full").Context(ctx).Do()
+	if err != nil {
+		return "", err
+	}
+	redact := func(s string) string {
+		for _, r := range redactors {
+			s = r.re.ReplaceAllString(s, r.with)
+		}
+		return s
+	}
+
+	body := findPartBody(msg.Payload, "text/plain")
+	if body == "" {
+		body = stripHTMLTags(findPartBody(msg.Payload, "text/html"))
+	}
+	subject := headerValue(msg.Payload, "Subject")
+	if subject == "" {
+		subject = "(no subject)"
+	}
+
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html><html><head><meta charset=\"utf-8\"><title>")
+	b.WriteString(html.EscapeString(redact(subject)))
+	b.WriteString("</title></head><body><h1>")
+	b.WriteString(html.EscapeString(redact(subject)))
+	b.WriteString("</h1><table>")
+	for _, name := range []string{"From", "To", "Cc", "Date"} {
+		if v := headerValue(msg.Payload, name); v != "" {
+			fmt.Fprintf(&b, "<tr><td><b>%s</b></td><td>%s</td></tr>", name, html.EscapeString(redact(v)))
+		}
+	}
+	b.WriteString("</table><hr><pre style=\"white-space: pre-wrap; font-family: inherit\">")
+	b.WriteString(html.EscapeString(redact(body)))
+	b.WriteString("</pre></body></html>\n")
+
+	meta := &drive.File{
+		Name:        redact(subject),
+		MimeType:    driveMimeGoogleDoc,
+		Description: "Shared from Gmail with gog share",
+	}
+	if parent := strings.TrimSpace(c.Parent); parent != "" {
+		meta.Parents = []string{parent}
+	}
+	created, err := svc.Files.Create(meta).
+		SupportsAllDrives(true).
+		Media(strings.NewReader(b.String()), gapi.ContentType("text/html")).
+		Fields("id").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return "", fmt.Errorf("upload rendered message: %w", err)
+	}
+	return created.Id, nil
+}
+
+type shareRedactor struct {
+	re   *regexp.Regexp
+	with string
+}
+
+func shareRedactors(kinds, patterns []string) ([]shareRedactor, error) {
+	var out []shareRedactor
+	for _, k := range kinds {
+		switch k {
+		case shareRedactEmails:
+			out = append(out, shareRedactor{re: shareEmailRe, with: "[email redacted]"})
+		case shareRedactPhones:
+			out = append(out, shareRedactor{re: sharePhoneRe, with: "[phone redacted]"})
+		}
+	}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, usagef("invalid --redact-pattern %q: %v", p, err)
+		}
+		out = append(out, shareRedactor{re: re, with: "[redacted]"})
+	}
+	return out, nil
+}
+
+type ShareExpireCmd struct {
+	DryRun bool `name:"dry-run" help:"List what would be revoked without changing anything"`
+}
+
+func (c *ShareExpireCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	records, err := loadShareRecords()
+	if err != nil {
+		return err
+	}
+	now := shareNow()
+	var due []shareRecord
+	for _, r := range records {
+		if strings.EqualFold(r.Account, account) && !now.Before(r.ExpiresAt) {
+			due = append(due, r)
+		}
+	}
+
+	var revoked []shareRecord
+	var failed int
+	if len(due) > 0 && !c.DryRun {
+		svc, svcErr := newDriveService(ctx, account)
+		if svcErr != nil {
+			return svcErr
+		}
+		for _, r := range due {
+			if revokeErr := revokeShare(ctx, svc, r); revokeErr != nil {
+				failed++
+				u.Err().Printf("revoke %s: %v", r.URL, revokeErr)
+				continue
+			}
+			revoked = append(revoked, r)
+		}
+		done := map[string]bool{}
+		for _, r := range revoked {
+			done[r.FileID+"/"+r.PermissionID] = true
+		}
+		if err := updateShareRecords(func(records []shareRecord) []shareRecord {
+			kept := records[:0]
+			for _, r := range records {
+				if !strings.EqualFold(r.Account, account) || !done[r.FileID+"/"+r.PermissionID] {
+					kept = append(kept, r)
+				}
+			}
+			return kept
+		}); err != nil {
+			return err
+		}
+	} else {
+		revoked = due
+	}
+
+	if outfmt.IsJSON(ctx) {
+		if err := outfmt.WriteJSON(os.Stdout, map[string]any{"revoked": revoked, "dryRun": c.DryRun}); err != nil {
+			return err
+		}
+	} else {
+		for _, r := range revoked {
+			u.Out().Printf("%s\t%s\t%s", r.Kind, r.Source, r.URL)
+		}
+		switch {
+		case c.DryRun:
+			u.Err().Printf("Dry run: %d share(s) would be revoked", len(revoked))
+		case len(revoked) == 0 && failed == 0:
+			u.Err().Println("No expired shares")
+		}
+	}
+	if failed > 0 {
+		return &ExitError{Code: 1, Err: fmt.Errorf("%d of %d shares could not be revoked", failed, len(due))}
+	}
+	return nil
+}
+
+// revokeShare removes the link permission and trashes rendered messages.
+// Already-removed permissions and files count as revoked.
+func revokeShare(ctx context.Context, svc *drive.Service, r shareRecord) error {
+	if r.PermissionID != "" {
+		err := svc.Permissions.Delete(r.FileID, r.PermissionID).SupportsAllDrives(true).Context(ctx).Do()
+		if err != nil && !isNotFoundAPIError(err) {
+			return err
+		}
+	}
+	if r.Rendered {
+		_, err := svc.Files.Update(r.FileID, &drive.File{Trashed: true}).SupportsAllDrives(true).Context(ctx).Do()
+		if err != nil && !isNotFoundAPIError(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+type ShareListCmd struct{}
+
+func (c *ShareListCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	records, err := loadShareRecords()
+	if err != nil {
+		return err
+	}
+	mine := []shareRecord{}
+	for _, r := range records {
+		if strings.EqualFold(r.Account, account) {
+			mine = append(mine, r)
+		}
+	}
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{"shares": mine})
+	}
+	if len(mine) == 0 {
+		u.Err().Println("No temporary shares")
+		return nil
+	}
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "KIND\tSOURCE\tEXPIRES\tURL")
+	for _, r := range mine {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Kind, r.Source, r.ExpiresAt.Local().Format("2006-01-02 15:04"), r.URL)
+	}
+	return nil
+}
+
+func loadShareRecords() ([]shareRecord, error) {
+	path, err := config.SharesPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // path under config dir
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var records []shareRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return records, nil
+}
+
+func updateShareRecords(fn func([]shareRecord) []shareRecord) error {
+	records, err := loadShareRecords()
+	if err != nil {
+		return err
+	}
+	records = fn(records)
+	path, err := config.SharesPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	if records == nil {
+		records = []shareRecord{}
+	}
+	payload, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(payload, '\n'), 0o600)
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+)
+
+func TestShareCreateAndExpire(t *testing.T) {
+	body := base64.RawURLEncoding.EncodeToString([]byte("Call bob@example.com or +1 415 555 0100 about the contract."))
+	gsrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": "m1", "payload": map[string]any{
+			"mimeType": "text/plain",
+			"headers": []map[string]string{
+				{"name": "Subject", "value": "Contract <draft>"},
+				{"name": "From", "value": "Alice <alice@example.com>"},
+				{"name": "X-Internal", "value": "secret"},
+			},
+			"body": map[string]any{"data": body},
+		}})
+	}))
+	defer gsrv.Close()
+	stubGmailService(t, gsrv)
+
+	var uploaded string
+	var calls []string
+	dsrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		data, _ := io.ReadAll(r.Body)
+		path := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/upload"), "/drive/v3")
+		switch {
+		case r.Method == http.MethodPost && path == "/files":
+			uploaded = string(data)
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "doc1"})
+		case r.Method == http.MethodPost && strings.HasSuffix(path, "/permissions"):
+			calls = append(calls, "create "+path+" "+string(data))
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "perm1"})
+		case r.Method == http.MethodGet:
+			id := strings.TrimPrefix(path, "/files/")
+			_ = json.NewEncoder(w).Encode(map[string]any{"webViewLink": "https://docs.google.com/d/" + id})
+		default:
+			calls = append(calls, r.Method+" "+path+" "+string(data))
+			_ = json.NewEncoder(w).Encode(map[string]any{})
+		}
+	}))
+	defer dsrv.Close()
+	svc, err := drive.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(dsrv.Client()),
+		option.WithEndpoint(dsrv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	origDrive, origNow := newDriveService, shareNow
+	t.Cleanup(func() { newDriveService, shareNow = origDrive, origNow })
+	newDriveService = func(context.Context, string) (*drive.Service, error) { return svc, nil }
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	shareNow = func() time.Time { return now }
+
+	run := func(args ...string) string {
+		t.Helper()
+		var out string
+		_ = captureStderr(t, func() {
+			out = captureStdout(t, func() {
+				if err := Execute(append([]string{"--json", "--account", "share@b.com", "share"}, args...)); err != nil {
+					t.Fatalf("Execute %v: %v", args, err)
+				}
+			})
+		})
+		return out
+	}
+
+	var rec shareRecord
+	if err := json.Unmarshal([]byte(run("gmail:m1", "--anyone", "--redact", "emails", "--redact", "phones")), &rec); err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	if rec.URL != "https://docs.google.com/d/doc1" || !rec.Rendered || !rec.ExpiresAt.Equal(now.Add(48*time.Hour)) {
+		t.Fatalf("unexpected record: %+v", rec)
+	}
+	for _, leak := range []string{"bob@example.com", "alice@example.com", "555 0100", "X-Internal", "<draft>"} {
+		if strings.Contains(uploaded, leak) {
+			t.Fatalf("rendering leaks %q:\n%s", leak, uploaded)
+		}
+	}
+	if !strings.Contains(uploaded, "[email redacted]") || !strings.Contains(uploaded, "[phone redacted]") ||
+		!strings.Contains(uploaded, "Contract &lt;draft&gt;") || !strings.Contains(uploaded, driveMimeGoogleDoc) {
+		t.Fatalf("unexpected rendering:\n%s", uploaded)
+	}
+	if len(calls) != 1 || !strings.Contains(calls[0], "/files/doc1/permissions") || !strings.Contains(calls[0], `"type":"anyone"`) {
+		t.Fatalf("unexpected permission calls: %v", calls)
+	}
+
+	calls = nil
+	run("drive:f1", "--to", "Legal Team <legal@b.com>", "--expires", "7d")
+	if len(calls) != 1 || !strings.Contains(calls[0], `"expirationTime":"2026-10-23T12:00:00Z"`) || !strings.Contains(calls[0], `"emailAddress":"legal@b.com"`) {
+		t.Fatalf("expected expiring user permission: %v", calls)
+	}
+
+	// After three days only the rendered message has expired.
+	calls = nil
+	now = now.Add(72 * time.Hour)
+	out := run("expire")
+	if !strings.Contains(out, `"source": "m1"`) || strings.Contains(out, `"f1"`) {
+		t.Fatalf("unexpected expire output: %s", out)
+	}
+	if len(calls) != 2 || !strings.HasPrefix(calls[0], "DELETE /files/doc1/permissions/perm1") ||
+		!strings.HasPrefix(calls[1], "PATCH /files/doc1") || !strings.Contains(calls[1], `"trashed":true`) {
+		t.Fatalf("unexpected revoke calls: %v", calls)
+	}
+	var listed struct {
+		Shares []shareRecord `json:"shares"`
+	}
+	if err := json.Unmarshal([]byte(run("list")), &listed); err != nil || len(listed.Shares) != 1 || listed.Shares[0].Source != "f1" {
+		t.Fatalf("unexpected list: %+v %v", listed, err)
+	}
+}
+
+func TestShareCreate_RequiresAudience(t *testing.T) {
+	for _, args := range [][]string{
+		{"drive:f1"},
+		{"drive:f1", "--anyone", "--to", "legal@b.com"},
+	} {
+		_ = captureStderr(t, func() {
+			err := Execute(append([]string{"--account", "share@b.com", "share"}, args...))
+			if err == nil || ExitCode(err) != 2 {
+				t.Fatalf("%v: expected usage error, got %v", args, err)
+			}
+		})
+	}
+}
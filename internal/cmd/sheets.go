@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -36,9 +35,9 @@ type SheetsCmd struct {
 }
 
 type SheetsExportCmd struct {
-	SpreadsheetID string         `arg:"" name:"spreadsheetId" help:"Spreadsheet ID"`
-	Output        OutputPathFlag `embed:""`
-	Format        string         `name:"format" help:"Export format: pdf|xlsx|csv" default:"xlsx"`
+	SpreadsheetID string           `arg:"" name:"spreadsheetId" help:"Spreadsheet ID or URL"`
+	Output        ExportOutputFlag `embed:""`
+	Format        string           `name:"format" help:"Export format: pdf|xlsx|csv" default:"xlsx"`
 }
 
 func (c *SheetsExportCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -48,11 +47,11 @@ func (c *SheetsExportCmd) Run(ctx context.Context, flags *RootFlags) error {
 		KindLabel:     "Google Sheet",
 		DefaultFormat: "xlsx",
 		FormatHelp:    "Export format: pdf|xlsx|csv",
-	}, c.SpreadsheetID, c.Output.Path, c.Format)
+	}, normalizeSpreadsheetID(c.SpreadsheetID), c.Output.Path, c.Format)
 }
 
 type SheetsCopyCmd struct {
-	SpreadsheetID string `arg:"" name:"spreadsheetId" help:"Spreadsheet ID"`
+	SpreadsheetID string `arg:"" name:"spreadsheetId" help:"Spreadsheet ID or URL"`
 	Title         string `arg:"" name:"title" help:"New spreadsheet title"`
 	Parent        string `name:"parent" help:"Destination folder ID"`
 }
@@ -62,14 +61,15 @@ func (c *SheetsCopyCmd) Run(ctx context.Context, flags *RootFlags) error {
 		ArgName:      "spreadsheetId",
 		ExpectedMime: "application/vnd.google-apps.spreadsheet",
 		KindLabel:    "Google Sheet",
-	}, c.SpreadsheetID, c.Title, c.Parent)
+	}, normalizeSpreadsheetID(c.SpreadsheetID), c.Title, c.Parent)
 }
 
 type SheetsGetCmd struct {
-	SpreadsheetID     string `arg:"" name:"spreadsheetId" help:"Spreadsheet ID"`
+	SpreadsheetID     string `arg:"" name:"spreadsheetId" help:"Spreadsheet ID or URL"`
 	Range             string `arg:"" name:"range" help:"Range (eg. Sheet1!A1:B10)"`
 	MajorDimension    string `name:"dimension" help:"Major dimension: ROWS or COLUMNS"`
 	ValueRenderOption string `name:"render" help:"Value render option: FORMATTED_VALUE, UNFORMATTED_VALUE, or FORMULA"`
+	CSV               bool   `name:"csv" help:"Write values as CSV to stdout"`
 }
 
 func (c *SheetsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -79,7 +79,7 @@ func (c *SheetsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
-	spreadsheetID := strings.TrimSpace(c.SpreadsheetID)
+	spreadsheetID := normalizeSpreadsheetID(c.SpreadsheetID)
 	rangeSpec := cleanRange(c.Range)
 	if spreadsheetID == "" {
 		return usage("empty spreadsheetId")
@@ -113,6 +113,10 @@ func (c *SheetsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 		})
 	}
 
+	if c.CSV {
+		return writeSheetValuesCSV(os.Stdout, resp.Values)
+	}
+
 	if len(resp.Values) == 0 {
 		u.Err().Println("No data found")
 		return nil
@@ -131,11 +135,13 @@ func (c *SheetsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type SheetsUpdateCmd struct {
-	SpreadsheetID      string   `arg:"" name:"spreadsheetId" help:"Spreadsheet ID"`
+	SpreadsheetID      string   `arg:"" name:"spreadsheetId" help:"Spreadsheet ID or URL"`
 	Range              string   `arg:"" name:"range" help:"Range (eg. Sheet1!A1:B2)"`
 	Values             []string `arg:"" optional:"" name:"values" help:"Values (comma-separated rows, pipe-separated cells)"`
 	ValueInput         string   `name:"input" help:"Value input option: RAW or USER_ENTERED" default:"USER_ENTERED"`
 	ValuesJSON         string   `name:"values-json" help:"Values as JSON 2D array"`
+	ValuesFile         string   `name:"values-file" help:"Read rows from a CSV/TSV/JSON file ('-' for stdin)"`
+	ValuesFormat       string   `name:"values-format" help:"Format of --values-file: auto|csv|tsv|json" enum:"auto,csv,tsv,json" default:"auto"`
 	CopyValidationFrom string   `name:"copy-validation-from" help:"Copy data validation from an A1 range (eg. 'Sheet1!A2:D2') to the updated cells"`
 }
 
@@ -146,7 +152,7 @@ func (c *SheetsUpdateCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
-	spreadsheetID := strings.TrimSpace(c.SpreadsheetID)
+	spreadsheetID := normalizeSpreadsheetID(c.SpreadsheetID)
 	rangeSpec := cleanRange(c.Range)
 	if spreadsheetID == "" {
 		return usage("empty spreadsheetId")
@@ -155,27 +161,14 @@ func (c *SheetsUpdateCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return usage("empty range")
 	}
 
-	var values [][]interface{}
-
-	switch {
-	case strings.TrimSpace(c.ValuesJSON) != "":
-		if unmarshalErr := json.Unmarshal([]byte(c.ValuesJSON), &values); unmarshalErr != nil {
-			return fmt.Errorf("invalid JSON values: %w", unmarshalErr)
-		}
-	case len(c.Values) > 0:
-		// Parse comma-separated rows, pipe-separated cells
-		rawValues := strings.Join(c.Values, " ")
-		rows := strings.Split(rawValues, ",")
-		for _, row := range rows {
-			cells := strings.Split(strings.TrimSpace(row), "|")
-			rowData := make([]interface{}, len(cells))
-			for i, cell := range cells {
-				rowData[i] = strings.TrimSpace(cell)
-			}
-			values = append(values, rowData)
-		}
-	default:
-		return fmt.Errorf("provide values as args or via --values-json")
+	values, err := sheetValuesInput{
+		Values:       c.Values,
+		ValuesJSON:   c.ValuesJSON,
+		ValuesFile:   c.ValuesFile,
+		ValuesFormat: c.ValuesFormat,
+	}.read()
+	if err != nil {
+		return err
 	}
 
 	svc, err := newSheetsService(ctx, account)
@@ -222,12 +215,14 @@ func (c *SheetsUpdateCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type SheetsAppendCmd struct {
-	SpreadsheetID      string   `arg:"" name:"spreadsheetId" help:"Spreadsheet ID"`
+	SpreadsheetID      string   `arg:"" name:"spreadsheetId" help:"Spreadsheet ID or URL"`
 	Range              string   `arg:"" name:"range" help:"Range (eg. Sheet1!A:C)"`
 	Values             []string `arg:"" optional:"" name:"values" help:"Values (comma-separated rows, pipe-separated cells)"`
 	ValueInput         string   `name:"input" help:"Value input option: RAW or USER_ENTERED" default:"USER_ENTERED"`
 	Insert             string   `name:"insert" help:"Insert data option: OVERWRITE or INSERT_ROWS"`
 	ValuesJSON         string   `name:"values-json" help:"Values as JSON 2D array"`
+	ValuesFile         string   `name:"values-file" help:"Read rows from a CSV/TSV/JSON file ('-' for stdin)"`
+	ValuesFormat       string   `name:"values-format" help:"Format of --values-file: auto|csv|tsv|json" enum:"auto,csv,tsv,json" default:"auto"`
 	CopyValidationFrom string   `name:"copy-validation-from" help:"Copy data validation from an A1 range (eg. 'Sheet1!A2:D2') to the appended cells"`
 }
 
@@ -238,7 +233,7 @@ func (c *SheetsAppendCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
-	spreadsheetID := strings.TrimSpace(c.SpreadsheetID)
+	spreadsheetID := normalizeSpreadsheetID(c.SpreadsheetID)
 	rangeSpec := cleanRange(c.Range)
 	if spreadsheetID == "" {
 		return usage("empty spreadsheetId")
@@ -247,26 +242,14 @@ func (c *SheetsAppendCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return usage("empty range")
 	}
 
-	var values [][]interface{}
-
-	switch {
-	case strings.TrimSpace(c.ValuesJSON) != "":
-		if unmarshalErr := json.Unmarshal([]byte(c.ValuesJSON), &values); unmarshalErr != nil {
-			return fmt.Errorf("invalid JSON values: %w", unmarshalErr)
-		}
-	case len(c.Values) > 0:
-		rawValues := strings.Join(c.Values, " ")
-		rows := strings.Split(rawValues, ",")
-		for _, row := range rows {
-			cells := strings.Split(strings.TrimSpace(row), "|")
-			rowData := make([]interface{}, len(cells))
-			for i, cell := range cells {
-				rowData[i] = strings.TrimSpace(cell)
-			}
-			values = append(values, rowData)
-		}
-	default:
-		return fmt.Errorf("provide values as args or via --values-json")
+	values, err := sheetValuesInput{
+		Values:       c.Values,
+		ValuesJSON:   c.ValuesJSON,
+		ValuesFile:   c.ValuesFile,
+		ValuesFormat: c.ValuesFormat,
+	}.read()
+	if err != nil {
+		return err
 	}
 
 	svc, err := newSheetsService(ctx, account)
@@ -316,7 +299,7 @@ func (c *SheetsAppendCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type SheetsClearCmd struct {
-	SpreadsheetID string `arg:"" name:"spreadsheetId" help:"Spreadsheet ID"`
+	SpreadsheetID string `arg:"" name:"spreadsheetId" help:"Spreadsheet ID or URL"`
 	Range         string `arg:"" name:"range" help:"Range (eg. Sheet1!A1:B2)"`
 }
 
@@ -327,7 +310,7 @@ func (c *SheetsClearCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
-	spreadsheetID := strings.TrimSpace(c.SpreadsheetID)
+	spreadsheetID := normalizeSpreadsheetID(c.SpreadsheetID)
 	rangeSpec := cleanRange(c.Range)
 	if spreadsheetID == "" {
 		return usage("empty spreadsheetId")
@@ -357,7 +340,7 @@ func (c *SheetsClearCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type SheetsMetadataCmd struct {
-	SpreadsheetID string `arg:"" name:"spreadsheetId" help:"Spreadsheet ID"`
+	SpreadsheetID string `arg:"" name:"spreadsheetId" help:"Spreadsheet ID or URL"`
 }
 
 func (c *SheetsMetadataCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -367,7 +350,7 @@ func (c *SheetsMetadataCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
-	spreadsheetID := strings.TrimSpace(c.SpreadsheetID)
+	spreadsheetID := normalizeSpreadsheetID(c.SpreadsheetID)
 	if spreadsheetID == "" {
 		return usage("empty spreadsheetId")
 	}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/steipete/gogcli/internal/config"
+)
+
+var spreadsheetURLRe = regexp.MustCompile(`/spreadsheets/(?:u/\d+/)?d/([A-Za-z0-9_-]+)`)
+
+// normalizeSpreadsheetID accepts a bare ID or a Sheets URL
+// (https://docs.google.com/spreadsheets/d/<id>/edit#gid=0).
+func normalizeSpreadsheetID(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if m := spreadsheetURLRe.FindStringSubmatch(raw); m != nil {
+		return m[1]
+	}
+	return raw
+}
+
+// sheetValuesInput is shared by `sheets update` and `sheets append`.
+type sheetValuesInput struct {
+	Values       []string
+	ValuesJSON   string
+	ValuesFile   string
+	ValuesFormat string
+}
+
+func (in sheetValuesInput) read() ([][]interface{}, error) {
+	sources := 0
+	for _, set := range []bool{len(in.Values) > 0, strings.TrimSpace(in.ValuesJSON) != "", strings.TrimSpace(in.ValuesFile) != ""} {
+		if set {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return nil, usage("use only one of values args, --values-json, or --values-file")
+	}
+
+	switch {
+	case strings.TrimSpace(in.ValuesJSON) != "":
+		return parseSheetValuesJSON([]byte(in.ValuesJSON))
+	case strings.TrimSpace(in.ValuesFile) != "":
+		return readSheetValuesFile(strings.TrimSpace(in.ValuesFile), in.ValuesFormat)
+	case len(in.Values) > 0:
+		// Parse comma-separated rows, pipe-separated cells
+		var values [][]interface{}
+		rawValues := strings.Join(in.Values, " ")
+		for _, row := range strings.Split(rawValues, ",") {
+			cells := strings.Split(strings.TrimSpace(row), "|")
+			rowData := make([]interface{}, len(cells))
+			for i, cell := range cells {
+				rowData[i] = strings.TrimSpace(cell)
+			}
+			values = append(values, rowData)
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("provide values as args, via --values-json, or via --values-file")
+	}
+}
+
+func parseSheetValuesJSON(data []byte) ([][]interface{}, error) {
+	var values [][]interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("invalid JSON values: %w", err)
+	}
+	return values, nil
+}
+
+// readSheetValuesFile reads rows from a CSV, TSV or JSON file ("-" for
+// stdin). With format "auto" the extension decides; stdin is sniffed.
+func readSheetValuesFile(path, format string) ([][]interface{}, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		path, err = config.ExpandPath(path)
+		if err != nil {
+			return nil, err
+		}
+		data, err = os.ReadFile(path) //nolint:gosec // user-provided path
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" || format == "auto" {
+		format = sniffSheetValuesFormat(path, data)
+	}
+
+	var values [][]interface{}
+	switch format {
+	case "json":
+		values, err = parseSheetValuesJSON(data)
+	case "csv", "tsv":
+		r := csv.NewReader(bytes.NewReader(data))
+		r.FieldsPerRecord = -1
+		if format == "tsv" {
+			r.Comma = '\t'
+			r.LazyQuotes = true
+		}
+		records, readErr := r.ReadAll()
+		if readErr != nil {
+			return nil, fmt.Errorf("invalid %s values: %w", strings.ToUpper(format), readErr)
+		}
+		for _, rec := range records {
+			row := make([]interface{}, len(rec))
+			for i, cell := range rec {
+				row[i] = cell
+			}
+			values = append(values, row)
+		}
+	default:
+		return nil, usagef("invalid --values-format %q (expected auto|csv|tsv|json)", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, usage("no rows in --values-file")
+	}
+	return values, nil
+}
+
+func sniffSheetValuesFormat(path string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return "json"
+	case ".tsv", ".tab":
+		return "tsv"
+	case ".csv":
+		return "csv"
+	}
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		return "json"
+	}
+	firstLine, _, _ := bytes.Cut(trimmed, []byte("\n"))
+	if bytes.Contains(firstLine, []byte("\t")) && !bytes.Contains(firstLine, []byte(",")) {
+		return "tsv"
+	}
+	return "csv"
+}
+
+func writeSheetValuesCSV(w io.Writer, values [][]interface{}) error {
+	cw := csv.NewWriter(w)
+	for _, row := range values {
+		rec := make([]string, len(row))
+		for i, cell := range row {
+			rec[i] = fmt.Sprintf("%v", cell)
+		}
+		if err := cw.Write(rec); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestNormalizeSpreadsheetID(t *testing.T) {
+	for in, want := range map[string]string{
+		"  abc123 ": "abc123",
+		"https://docs.google.com/spreadsheets/d/abc123/edit#gid=0":      "abc123",
+		"https://docs.google.com/spreadsheets/d/abc123":                 "abc123",
+		"https://docs.google.com/spreadsheets/u/1/d/abc123?usp=sharing": "abc123",
+	} {
+		if got := normalizeSpreadsheetID(in); got != want {
+			t.Fatalf("normalizeSpreadsheetID(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSheetValuesInput_Stdin(t *testing.T) {
+	for _, tc := range []struct {
+		input  string
+		format string
+		want   [][]interface{}
+	}{
+		{input: "date,amount\n2026-03-01,\"1,5\"\n", format: "auto", want: [][]interface{}{{"date", "amount"}, {"2026-03-01", "1,5"}}},
+		{input: "a\tb\nc\td\n", format: "auto", want: [][]interface{}{{"a", "b"}, {"c", "d"}}},
+		{input: `[["x", 1]]`, format: "auto", want: [][]interface{}{{"x", float64(1)}}},
+		{input: "a;b\n", format: "tsv", want: [][]interface{}{{"a;b"}}},
+	} {
+		var got [][]interface{}
+		withStdin(t, tc.input, func() {
+			var err error
+			got, err = sheetValuesInput{ValuesFile: "-", ValuesFormat: tc.format}.read()
+			if err != nil {
+				t.Fatalf("read %q: %v", tc.input, err)
+			}
+		})
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("read %q = %#v, want %#v", tc.input, got, tc.want)
+		}
+	}
+
+	if _, err := (sheetValuesInput{Values: []string{"a"}, ValuesFile: "-"}).read(); err == nil {
+		t.Fatalf("expected error for multiple value sources")
+	}
+}
+
+func TestSheetsAppendFromStdinByURL(t *testing.T) {
+	origNew := newSheetsService
+	t.Cleanup(func() { newSheetsService = origNew })
+
+	var got sheets.ValueRange
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/sheets/v4")
+		path = strings.TrimPrefix(path, "/v4")
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasPrefix(path, "/spreadsheets/s1/values/") && strings.HasSuffix(path, ":append") && r.Method == http.MethodPost:
+			_ = json.NewDecoder(r.Body).Decode(&got)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"updates": map[string]any{"updatedRange": "Log!A5:B6", "updatedCells": 4},
+			})
+		case strings.HasPrefix(path, "/spreadsheets/s1/values/") && r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"range":  "Log!A1:B2",
+				"values": [][]any{{"when", "note"}, {"today", "hello, world"}},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	svc, err := sheets.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newSheetsService = func(context.Context, string) (*sheets.Service, error) { return svc, nil }
+
+	url := "https://docs.google.com/spreadsheets/d/s1/edit#gid=0"
+	withStdin(t, "2026-03-01,ran\n2026-03-02,ran again\n", func() {
+		_ = captureStdout(t, func() {
+			if err := Execute([]string{"--account", "a@b.com", "sheets", "append", url, "Log!A:B", "--values-file", "-"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	want := [][]interface{}{{"2026-03-01", "ran"}, {"2026-03-02", "ran again"}}
+	if !reflect.DeepEqual(got.Values, want) {
+		t.Fatalf("appended %#v, want %#v", got.Values, want)
+	}
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--account", "a@b.com", "sheets", "get", url, "Log!A1:B2", "--csv"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	if out != "when,note\ntoday,\"hello, world\"\n" {
+		t.Fatalf("csv output %q", out)
+	}
+}
@@ -20,9 +20,9 @@ type SlidesCmd struct {
 }
 
 type SlidesExportCmd struct {
-	PresentationID string         `arg:"" name:"presentationId" help:"Presentation ID"`
-	Output         OutputPathFlag `embed:""`
-	Format         string         `name:"format" help:"Export format: pdf|pptx" default:"pptx"`
+	PresentationID string           `arg:"" name:"presentationId" help:"Presentation ID"`
+	Output         ExportOutputFlag `embed:""`
+	Format         string           `name:"format" help:"Export format: pdf|pptx" default:"pptx"`
 }
 
 func (c *SlidesExportCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -0,0 +1,399 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+const (
+	// statusWindow is how far ahead events are cached, so the compact line
+	// can move on to the next event without a refresh.
+	statusWindow      = 24 * time.Hour
+	statusMaxEvents   = 10
+	statusRefreshWait = time.Minute
+)
+
+var (
+	statusNow = time.Now
+
+	// startStatusRefresh re-runs `gog status` detached to update the cache;
+	// args come from statusRefreshArgs.
+	startStatusRefresh = func(args []string) error {
+		exe, err := os.Executable()
+		if err != nil {
+			return err
+		}
+		cmd := exec.Command(exe, args...) //nolint:gosec // our own binary
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+		return cmd.Process.Release()
+	}
+)
+
+type StatusCmd struct {
+	Compact    bool          `name:"compact" help:"Print one line from the local cache (for tmux/starship prompts); never waits on the network"`
+	MaxAge     time.Duration `name:"max-age" help:"With --compact, refresh the cache in the background when older than this" default:"5m"`
+	NoRefresh  bool          `name:"no-refresh" help:"With --compact, never start a background refresh"`
+	TitleWidth int           `name:"title-width" help:"Truncate the event title in --compact output (0 = no limit)" default:"24"`
+	Calendar   string        `name:"calendar" complete:"calendars" help:"Calendar ID for the next event" default:"primary"`
+}
+
+type statusEvent struct {
+	ID      string    `json:"id"`
+	Summary string    `json:"summary"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+}
+
+type statusSnapshot struct {
+	UpdatedAt        time.Time     `json:"updatedAt"`
+	RefreshStartedAt time.Time     `json:"refreshStartedAt,omitempty"`
+	Unread           int64         `json:"unread"`
+	Events           []statusEvent `json:"events,omitempty"`
+}
+
+// statusCache keeps the last calendar/inbox snapshot per account and
+// calendar (see statusSnapshotKey) so prompt integrations can render without
+// an API round-trip.
+type statusCache struct {
+	path     string
+	Accounts map[string]*statusSnapshot `json:"accounts"`
+}
+
+func (c *StatusCmd) Run(ctx context.Context, flags *RootFlags) error {
+	cache, err := loadStatusCache()
+	if err != nil {
+		return err
+	}
+	now := statusNow()
+
+	if c.Compact {
+		account, accountErr := compactStatusAccount(flags, cache, c.Calendar)
+		if accountErr != nil {
+			return accountErr
+		}
+		return c.runCompact(ctx, flags, cache, account, now)
+	}
+
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+
+	snap, err := fetchStatusSnapshot(ctx, account, c.Calendar, now)
+	if err != nil {
+		return err
+	}
+	cache.Accounts[statusSnapshotKey(account, c.Calendar)] = snap
+	if err := cache.save(); err != nil {
+		return err
+	}
+	return writeStatus(ctx, account, snap, now)
+}
+
+// compactStatusAccount resolves the account for --compact without opening the
+// keyring, which can block a prompt on a keychain unlock: --account or
+// GOG_ACCOUNT wins, then the account whose snapshot of calendarID was
+// refreshed last. Only an empty cache falls back to the keyring, so the first
+// refresh can fill it.
+func compactStatusAccount(flags *RootFlags, cache *statusCache, calendarID string) (string, error) {
+	explicit := strings.TrimSpace(flags.Account)
+	if explicit == "" {
+		explicit = strings.TrimSpace(os.Getenv("GOG_ACCOUNT"))
+	}
+	if explicit != "" && !shouldAutoSelectAccount(explicit) {
+		return requireAccount(flags)
+	}
+	var latest string
+	var latestAt time.Time
+	for key, snap := range cache.Accounts {
+		account, cal := splitStatusSnapshotKey(key)
+		if snap == nil || cal != statusCalendar(calendarID) {
+			continue
+		}
+		if latest == "" || snap.UpdatedAt.After(latestAt) || (snap.UpdatedAt.Equal(latestAt) && account < latest) {
+			latest, latestAt = account, snap.UpdatedAt
+		}
+	}
+	if latest != "" {
+		return latest, nil
+	}
+	return requireAccount(flags)
+}
+
+func (c *StatusCmd) runCompact(ctx context.Context, flags *RootFlags, cache *statusCache, account string, now time.Time) error {
+	key := statusSnapshotKey(account, c.Calendar)
+	snap := cache.Accounts[key]
+	stale := snap == nil || now.Sub(snap.UpdatedAt) > c.MaxAge
+	if stale && !c.NoRefresh {
+		if snap == nil {
+			snap = &statusSnapshot{}
+			cache.Accounts[key] = snap
+		}
+		// Several prompts render at once; only one of them should refresh.
+		if now.Sub(snap.RefreshStartedAt) > statusRefreshWait {
+			snap.RefreshStartedAt = now
+			if err := cache.save(); err == nil {
+				_ = startStatusRefresh(statusRefreshArgs(flags, account, c.Calendar))
+			}
+		}
+	}
+
+	overdue, _ := laterCounts(account, now)
+	if outfmt.IsJSON(ctx) {
+		payload := map[string]any{"account": account, "stale": stale, "repliesOverdue": overdue}
+		if snap != nil && !snap.UpdatedAt.IsZero() {
+			payload["unread"] = snap.Unread
+			payload["updatedAt"] = snap.UpdatedAt
+			if ev, ok := snap.nextEvent(now); ok {
+				payload["nextEvent"] = ev
+				payload["minutesUntil"] = int(ev.Start.Sub(now).Minutes())
+			}
+		}
+		return outfmt.WriteJSON(os.Stdout, payload)
+	}
+
+	if snap == nil || snap.UpdatedAt.IsZero() {
+		// Nothing cached yet: print nothing rather than a misleading badge.
+		return nil
+	}
+	_, err := fmt.Fprintln(os.Stdout, compactStatusLine(snap, now, c.TitleWidth, overdue))
+	return err
+}
+
+func fetchStatusSnapshot(ctx context.Context, account, calendarID string, now time.Time) (*statusSnapshot, error) {
+	calendarID = strings.TrimSpace(calendarID)
+	if calendarID == "" {
+		return nil, usage("empty --calendar")
+	}
+
+	calSvc, err := newCalendarService(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := calSvc.Events.List(calendarID).
+		TimeMin(now.Format(time.RFC3339)).
+		TimeMax(now.Add(statusWindow).Format(time.RFC3339)).
+		SingleEvents(true).
+		OrderBy("startTime").
+		MaxResults(50).
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+
+	gmailSvc, err := newGmailService(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+	inbox, err := gmailSvc.Users.Labels.Get("me", "INBOX").Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	snap := &statusSnapshot{UpdatedAt: now, Unread: inbox.ThreadsUnread}
+	for _, item := range resp.Items {
+		ev, ok := statusEventFrom(item)
+		if !ok || !ev.Start.After(now) {
+			continue
+		}
+		snap.Events = append(snap.Events, ev)
+		if len(snap.Events) == statusMaxEvents {
+			break
+		}
+	}
+	return snap, nil
+}
+
+// statusEventFrom keeps timed events the user has not declined; all-day
+// events are not useful in a prompt badge.
+func statusEventFrom(item *calendar.Event) (statusEvent, bool) {
+	if item == nil || item.Start == nil || item.Start.DateTime == "" || item.Status == "cancelled" {
+		return statusEvent{}, false
+	}
+	for _, a := range item.Attendees {
+		if a != nil && a.Self && a.ResponseStatus == "declined" {
+			return statusEvent{}, false
+		}
+	}
+	start, err := time.Parse(time.RFC3339, item.Start.DateTime)
+	if err != nil {
+		return statusEvent{}, false
+	}
+	ev := statusEvent{ID: item.Id, Summary: item.Summary, Start: start}
+	if item.End != nil {
+		if end, err := time.Parse(time.RFC3339, item.End.DateTime); err == nil {
+			ev.End = end
+		}
+	}
+	if strings.TrimSpace(ev.Summary) == "" {
+		ev.Summary = "(no title)"
+	}
+	return ev, true
+}
+
+func (s *statusSnapshot) nextEvent(now time.Time) (statusEvent, bool) {
+	for _, ev := range s.Events {
+		if !ev.Start.Before(now) {
+			return ev, true
+		}
+	}
+	return statusEvent{}, false
+}
+
+// compactStatusLine renders the prompt badge; overdue reply-later items are
+// only mentioned when there are some.
+func compactStatusLine(snap *statusSnapshot, now time.Time, titleWidth, overdue int) string {
+	event := "no events"
+	if ev, ok := snap.nextEvent(now); ok {
+		title := ev.Summary
+		if titleWidth > 0 {
+			title = truncateRunes(title, titleWidth)
+		}
+		event = title + " " + formatUntil(ev.Start.Sub(now))
+	}
+	line := fmt.Sprintf("%s | %d unread", event, snap.Unread)
+	if overdue > 0 {
+		line += fmt.Sprintf(" | %d overdue", overdue)
+	}
+	return line
+}
+
+func formatUntil(d time.Duration) string {
+	minutes := int(d.Minutes())
+	switch {
+	case minutes < 1:
+		return "now"
+	case minutes < 60:
+		return fmt.Sprintf("in %dm", minutes)
+	default:
+		return fmt.Sprintf("in %dh%02dm", minutes/60, minutes%60)
+	}
+}
+
+func writeStatus(ctx context.Context, account string, snap *statusSnapshot, now time.Time) error {
+	ev, hasEvent := snap.nextEvent(now)
+	overdue, dueSoon := laterCounts(account, now)
+	if outfmt.IsJSON(ctx) {
+		payload := map[string]any{
+			"account":        account,
+			"unread":         snap.Unread,
+			"updatedAt":      snap.UpdatedAt,
+			"events":         snap.Events,
+			"repliesOverdue": overdue,
+			"repliesDueSoon": dueSoon,
+		}
+		if hasEvent {
+			payload["nextEvent"] = ev
+			payload["minutesUntil"] = int(ev.Start.Sub(now).Minutes())
+		}
+		return outfmt.WriteJSON(os.Stdout, payload)
+	}
+
+	u := ui.FromContext(ctx)
+	u.Out().Printf("account\t%s", account)
+	if hasEvent {
+		u.Out().Printf("next_event\t%s", ev.Summary)
+		u.Out().Printf("next_start\t%s", ev.Start.Format(time.RFC3339))
+		u.Out().Printf("minutes_until\t%d", int(ev.Start.Sub(now).Minutes()))
+	} else {
+		u.Out().Printf("next_event\t%s", "")
+	}
+	u.Out().Printf("unread\t%d", snap.Unread)
+	u.Out().Printf("replies_overdue\t%d", overdue)
+	u.Out().Printf("replies_due_soon\t%d", dueSoon)
+	return nil
+}
+
+func statusKey(account string) string {
+	return strings.ToLower(strings.TrimSpace(account))
+}
+
+// statusSnapshotKey keys a snapshot by account and calendar. The primary
+// calendar keeps the bare account key so existing caches stay valid.
+func statusSnapshotKey(account, calendarID string) string {
+	if cal := statusCalendar(calendarID); cal != "primary" {
+		return statusKey(account) + "|" + cal
+	}
+	return statusKey(account)
+}
+
+func splitStatusSnapshotKey(key string) (account, calendarID string) {
+	account, calendarID, ok := strings.Cut(key, "|")
+	if !ok {
+		return key, "primary"
+	}
+	return account, calendarID
+}
+
+func statusCalendar(calendarID string) string {
+	calendarID = strings.TrimSpace(calendarID)
+	if calendarID == "" {
+		return "primary"
+	}
+	return calendarID
+}
+
+// statusRefreshArgs rebuilds the selection flags of a --compact call for the
+// background `gog status`, so it refreshes the same snapshot the prompt reads.
+func statusRefreshArgs(flags *RootFlags, account, calendarID string) []string {
+	args := []string{"--account", account}
+	if client := strings.TrimSpace(flags.Client); client != "" {
+		args = append(args, "--client", client)
+	}
+	return append(args, "status", "--calendar", statusCalendar(calendarID))
+}
+
+func loadStatusCache() (*statusCache, error) {
+	path, err := config.StatusCachePath()
+	if err != nil {
+		return nil, err
+	}
+	cache := &statusCache{path: path, Accounts: map[string]*statusSnapshot{}}
+	data, err := os.ReadFile(path) //nolint:gosec // path under config dir
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cache, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, cache); err != nil {
+		// A corrupt cache is rebuilt on the next refresh.
+		return &statusCache{path: path, Accounts: map[string]*statusSnapshot{}}, nil
+	}
+	if cache.Accounts == nil {
+		cache.Accounts = map[string]*statusSnapshot{}
+	}
+	return cache, nil
+}
+
+func (c *statusCache) save() error {
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o700); err != nil {
+		return err
+	}
+	payload, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Write-then-rename so a prompt never reads a half-written file.
+	tmp := fmt.Sprintf("%s.%d.tmp", c.path, os.Getpid())
+	if err := os.WriteFile(tmp, append(payload, '\n'), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.path)
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/internal/secrets"
+	"github.com/steipete/gogcli/mock"
+)
+
+func TestStatus_RefreshAndCompact(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	s, err := mock.New(nil)
+	if err != nil {
+		t.Fatalf("mock.New: %v", err)
+	}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	t.Setenv(googleapi.EnvAPIEndpoint, srv.URL)
+
+	origNow, origRefresh := statusNow, startStatusRefresh
+	t.Cleanup(func() { statusNow, startStatusRefresh = origNow, origRefresh })
+	now := time.Date(2026, 1, 8, 13, 48, 0, 0, time.UTC)
+	statusNow = func() time.Time { return now }
+	var refreshes []string
+	startStatusRefresh = func(args []string) error {
+		refreshes = append(refreshes, strings.Join(args, " "))
+		return nil
+	}
+
+	run := func(args ...string) string {
+		t.Helper()
+		return captureStdout(t, func() {
+			if err := Execute(append([]string{"--account", "test@example.com", "status"}, args...)); err != nil {
+				t.Fatalf("status %v: %v", args, err)
+			}
+		})
+	}
+
+	// No cache yet: print nothing and kick off a refresh.
+	if out := run("--compact"); out != "" {
+		t.Fatalf("expected empty output without cache, got %q", out)
+	}
+	if len(refreshes) != 1 || !strings.HasPrefix(refreshes[0], "--account test@example.com ") || !strings.HasSuffix(refreshes[0], " status --calendar primary") {
+		t.Fatalf("expected one refresh, got %v", refreshes)
+	}
+
+	out := run()
+	if !strings.Contains(out, "next_event\tQ1 planning") || !strings.Contains(out, "minutes_until\t12") || !strings.Contains(out, "unread\t2") {
+		t.Fatalf("unexpected status:\n%s", out)
+	}
+
+	if got := run("--compact"); got != "Q1 planning in 12m | 2 unread\n" {
+		t.Fatalf("unexpected compact line: %q", got)
+	}
+
+	// After the meeting starts the cached list moves on to the next event.
+	now = time.Date(2026, 1, 8, 14, 1, 0, 0, time.UTC)
+	if got := run("--compact", "--max-age", "1h", "--title-width", "6"); got != "Foc... in 18h59m | 2 unread\n" {
+		t.Fatalf("unexpected compact line: %q", got)
+	}
+	if len(refreshes) != 1 {
+		t.Fatalf("fresh cache should not refresh, got %v", refreshes)
+	}
+
+	// Stale cache: one background refresh, not one per prompt render.
+	run("--compact")
+	run("--compact")
+	if len(refreshes) != 2 {
+		t.Fatalf("expected a single stale refresh, got %v", refreshes)
+	}
+
+	var parsed struct {
+		Stale        bool `json:"stale"`
+		Unread       int  `json:"unread"`
+		MinutesUntil int  `json:"minutesUntil"`
+	}
+	if err := json.Unmarshal([]byte(run("--json", "--compact", "--no-refresh")), &parsed); err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	if !parsed.Stale || parsed.Unread != 2 || parsed.MinutesUntil != 18*60+59 {
+		t.Fatalf("unexpected json: %#v", parsed)
+	}
+
+	// Without --account, --compact picks the cached account and never opens
+	// the keyring.
+	t.Setenv("GOG_ACCOUNT", "")
+	origStore := openSecretsStoreForAccount
+	t.Cleanup(func() { openSecretsStoreForAccount = origStore })
+	openSecretsStoreForAccount = func() (secrets.Store, error) {
+		t.Fatalf("status --compact opened the keyring")
+		return nil, errors.New("unreachable")
+	}
+	got := captureStdout(t, func() {
+		if err := Execute([]string{"status", "--compact", "--no-refresh"}); err != nil {
+			t.Fatalf("status --compact: %v", err)
+		}
+	})
+	if got != "Focus time in 18h59m | 2 unread\n" {
+		t.Fatalf("unexpected compact line without --account: %q", got)
+	}
+}
+
+func TestStatus_CompactRefreshKeepsCalendar(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origNow, origRefresh := statusNow, startStatusRefresh
+	t.Cleanup(func() { statusNow, startStatusRefresh = origNow, origRefresh })
+	statusNow = func() time.Time { return time.Date(2026, 1, 8, 13, 48, 0, 0, time.UTC) }
+	var refreshes [][]string
+	startStatusRefresh = func(args []string) error {
+		refreshes = append(refreshes, args)
+		return nil
+	}
+
+	captureStdout(t, func() {
+		if err := Execute([]string{"--account", "test@example.com", "--client", "work", "status", "--compact", "--calendar", "team@group.calendar.google.com"}); err != nil {
+			t.Fatalf("status --compact: %v", err)
+		}
+	})
+	want := "--account test@example.com --client work status --calendar team@group.calendar.google.com"
+	if len(refreshes) != 1 || strings.Join(refreshes[0], " ") != want {
+		t.Fatalf("expected refresh %q, got %v", want, refreshes)
+	}
+
+	cache, err := loadStatusCache()
+	if err != nil {
+		t.Fatalf("loadStatusCache: %v", err)
+	}
+	if _, ok := cache.Accounts["test@example.com|team@group.calendar.google.com"]; !ok {
+		t.Fatalf("expected a per-calendar snapshot, got %v", cache.Accounts)
+	}
+	if _, ok := cache.Accounts["test@example.com"]; ok {
+		t.Fatalf("non-primary calendar must not touch the primary snapshot")
+	}
+}
+
+func TestFormatUntil(t *testing.T) {
+	cases := map[time.Duration]string{
+		30 * time.Second:            "now",
+		5 * time.Minute:             "in 5m",
+		2*time.Hour + 5*time.Minute: "in 2h05m",
+	}
+	for d, want := range cases {
+		if got := formatUntil(d); got != want {
+			t.Fatalf("formatUntil(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/summarizer"
+)
+
+const summarizeThreadInstructions = "Summarize this email thread in a few sentences: what it is about, what was decided, and any open questions or action items with their owners. Reply with the summary only."
+
+// newSummarizerHook returns the summarizers entry from config.json for the
+// account. Swapped out in tests.
+var newSummarizerHook = func(account string) (summarizer.Hook, error) {
+	cfg, ok, err := config.SummarizerFor(account)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, usage("no summarizer configured: add a summarizers entry with a command or endpoint to config.json")
+	}
+	var timeout time.Duration
+	if t := strings.TrimSpace(cfg.Timeout); t != "" {
+		timeout, err = time.ParseDuration(t)
+		if err != nil {
+			return nil, usagef("summarizers: invalid timeout %q", cfg.Timeout)
+		}
+	}
+	return summarizer.New(summarizer.Config{
+		Command:  cfg.Command,
+		Endpoint: cfg.Endpoint,
+		Headers:  cfg.Headers,
+		Timeout:  timeout,
+	})
+}
+
+// runSummarizer sends messages to the account's hook.
+func runSummarizer(ctx context.Context, account, task, instructions string, msgs []*gmail.Message) (string, error) {
+	hook, err := newSummarizerHook(account)
+	if err != nil {
+		return "", err
+	}
+	return hook.Run(ctx, summarizer.Request{
+		Task:         task,
+		Account:      account,
+		Instructions: instructions,
+		Messages:     summarizerMessages(msgs),
+	})
+}
+
+// summarizerMessages turns full-format messages into hook input, with HTML
+// bodies reduced to text.
+func summarizerMessages(msgs []*gmail.Message) []summarizer.Message {
+	out := make([]summarizer.Message, 0, len(msgs))
+	for _, msg := range msgs {
+		if msg == nil {
+			continue
+		}
+		body, isHTML := bestBodyForDisplay(msg.Payload)
+		if isHTML {
+			body = stripHTMLTags(body)
+		}
+		out = append(out, summarizer.Message{
+			ID:      msg.Id,
+			From:    headerValue(msg.Payload, "From"),
+			To:      headerValue(msg.Payload, "To"),
+			Cc:      headerValue(msg.Payload, "Cc"),
+			Date:    headerValue(msg.Payload, "Date"),
+			Subject: headerValue(msg.Payload, "Subject"),
+			Body:    strings.TrimSpace(body),
+		})
+	}
+	return out
+}
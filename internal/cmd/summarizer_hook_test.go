@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/summarizer"
+)
+
+type fakeHook struct {
+	reqs  []summarizer.Request
+	reply string
+}
+
+func (f *fakeHook) Run(_ context.Context, req summarizer.Request) (string, error) {
+	f.reqs = append(f.reqs, req)
+	return f.reply, nil
+}
+
+func stubSummarizerHook(t *testing.T, hook summarizer.Hook) {
+	t.Helper()
+	orig := newSummarizerHook
+	t.Cleanup(func() { newSummarizerHook = orig })
+	newSummarizerHook = func(string) (summarizer.Hook, error) { return hook, nil }
+}
+
+func TestGmailThreadGetSummaryAI(t *testing.T) {
+	html := base64.RawURLEncoding.EncodeToString([]byte("<p>Can we move the <b>review</b> to Friday?</p>"))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": "t1", "messages": []map[string]any{{
+			"id": "m1", "threadId": "t1",
+			"payload": map[string]any{
+				"mimeType": "text/html",
+				"headers":  []map[string]string{{"name": "Subject", "value": "Review"}, {"name": "From", "value": "ann@example.com"}},
+				"body":     map[string]any{"data": html},
+			},
+		}}})
+	}))
+	defer srv.Close()
+	stubGmailService(t, srv)
+	hook := &fakeHook{reply: "Ann asks to move the review to Friday."}
+	stubSummarizerHook(t, hook)
+
+	stdout := captureStdout(t, func() {
+		if err := Execute([]string{"--json", "--account", "me@example.com", "gmail", "thread", "get", "t1", "--summary-ai"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	var out map[string]any
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("json: %v\n%s", err, stdout)
+	}
+	if out["summary"] != hook.reply {
+		t.Fatalf("summary = %v", out["summary"])
+	}
+	if len(hook.reqs) != 1 {
+		t.Fatalf("hook calls = %d", len(hook.reqs))
+	}
+	req := hook.reqs[0]
+	if req.Task != summarizer.TaskSummarize || req.Account != "me@example.com" || len(req.Messages) != 1 ||
+		req.Messages[0].From != "ann@example.com" || !strings.Contains(req.Messages[0].Body, "move the review to Friday") {
+		t.Fatalf("request = %+v", req)
+	}
+}
@@ -16,4 +16,5 @@ type TasksCmd struct {
 	Undo   TasksUndoCmd   `cmd:"" name:"undo" help:"Mark task needs action" aliases:"uncomplete,undone"`
 	Delete TasksDeleteCmd `cmd:"" name:"delete" help:"Delete a task" aliases:"rm,del"`
 	Clear  TasksClearCmd  `cmd:"" name:"clear" help:"Clear completed tasks"`
+	Sync   TasksSyncCmd   `cmd:"" name:"sync" help:"Two-way sync a task list with a Markdown or org-mode file"`
 }
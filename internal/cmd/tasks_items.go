@@ -151,18 +151,19 @@ func (c *TasksGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type TasksAddCmd struct {
-	TasklistID  string `arg:"" name:"tasklistId" help:"Task list ID"`
-	Title       string `name:"title" help:"Task title (required)"`
-	Notes       string `name:"notes" help:"Task notes/description"`
-	Due         string `name:"due" help:"Due date (RFC3339 or YYYY-MM-DD; time may be ignored by Google Tasks)"`
-	Parent      string `name:"parent" help:"Parent task ID (create as subtask)"`
-	Previous    string `name:"previous" help:"Previous sibling task ID (controls ordering)"`
-	Repeat      string `name:"repeat" help:"Repeat task: daily, weekly, monthly, yearly"`
-	RepeatCount int    `name:"repeat-count" help:"Number of occurrences to create (requires --repeat)"`
-	RepeatUntil string `name:"repeat-until" help:"Repeat until date/time (RFC3339 or YYYY-MM-DD; requires --repeat)"`
+	TasklistID  string          `arg:"" name:"tasklistId" help:"Task list ID"`
+	Title       string          `name:"title" help:"Task title (required)"`
+	Notes       string          `name:"notes" help:"Task notes/description"`
+	Due         string          `name:"due" help:"Due date (RFC3339 or YYYY-MM-DD; time may be ignored by Google Tasks)"`
+	Parent      string          `name:"parent" help:"Parent task ID (create as subtask)"`
+	Previous    string          `name:"previous" help:"Previous sibling task ID (controls ordering)"`
+	Repeat      string          `name:"repeat" help:"Repeat task: daily, weekly, monthly, yearly"`
+	RepeatCount int             `name:"repeat-count" help:"Number of occurrences to create (requires --repeat)"`
+	RepeatUntil string          `name:"repeat-until" help:"Repeat until date/time (RFC3339 or YYYY-MM-DD; requires --repeat)"`
+	Idempotency IdempotencyFlag `embed:""`
 }
 
-func (c *TasksAddCmd) Run(ctx context.Context, flags *RootFlags) error {
+func (c *TasksAddCmd) Run(ctx context.Context, flags *RootFlags) (err error) {
 	u := ui.FromContext(ctx)
 	account, err := requireAccount(flags)
 	if err != nil {
@@ -184,6 +185,12 @@ func (c *TasksAddCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return usage("--repeat is required when using --repeat-count or --repeat-until")
 	}
 
+	idem, done, err := beginIdempotent(ctx, c.Idempotency.Key, account, "tasks.add")
+	if done || err != nil {
+		return err
+	}
+	defer idem.finish(&err)
+
 	if repeatUnit == repeatNone {
 		svc, svcErr := newTasksService(ctx, account)
 		if svcErr != nil {
@@ -211,6 +218,7 @@ func (c *TasksAddCmd) Run(ctx context.Context, flags *RootFlags) error {
 		if createErr != nil {
 			return createErr
 		}
+		idem.resource(created.Id)
 		if outfmt.IsJSON(ctx) {
 			return outfmt.WriteJSON(os.Stdout, map[string]any{"task": created})
 		}
@@ -305,6 +313,7 @@ func (c *TasksAddCmd) Run(ctx context.Context, flags *RootFlags) error {
 		if createErr != nil {
 			return createErr
 		}
+		idem.resource(created.Id)
 		createdTasks = append(createdTasks, created)
 		if previous != "" {
 			previous = created.Id
@@ -0,0 +1,479 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/api/tasks/v1"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/todofile"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+const (
+	taskSyncPreferNewer  = "newer"
+	taskSyncPreferLocal  = "local"
+	taskSyncPreferRemote = "remote"
+
+	taskSyncSideLocal  = "local"
+	taskSyncSideRemote = "remote"
+)
+
+type TasksSyncCmd struct {
+	TasklistID string `arg:"" name:"tasklistId" help:"Task list ID"`
+	With       string `name:"with" help:"Plain-text task file: Markdown checklist (.md) or org-mode (.org); created if missing (required)"`
+	Prefer     string `name:"prefer" help:"Conflict winner when both sides changed: newer|local|remote" enum:"newer,local,remote" default:"newer"`
+	DryRun     bool   `name:"dry-run" help:"Show planned changes without touching Google Tasks or the file"`
+}
+
+// taskSyncRecord is the part of a task that is synced. Notes, links and
+// hierarchy stay with Google Tasks.
+type taskSyncRecord struct {
+	Title string `json:"title"`
+	Done  bool   `json:"done,omitempty"`
+	Due   string `json:"due,omitempty"`
+}
+
+// taskSyncState is the snapshot after the last successful sync; it is the
+// common ancestor that tells an edit on one side from an edit on the other.
+type taskSyncState struct {
+	Account    string                    `json:"account"`
+	TasklistID string                    `json:"tasklistId"`
+	File       string                    `json:"file"`
+	SyncedAt   string                    `json:"syncedAt"`
+	Tasks      map[string]taskSyncRecord `json:"tasks"`
+}
+
+type taskSyncAction struct {
+	Op       string `json:"op"`
+	Side     string `json:"side"`
+	ID       string `json:"id,omitempty"`
+	Title    string `json:"title"`
+	Conflict bool   `json:"conflict,omitempty"`
+
+	item *todofile.Item
+}
+
+func (c *TasksSyncCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	tasklistID := strings.TrimSpace(c.TasklistID)
+	if tasklistID == "" {
+		return usage("empty tasklistId")
+	}
+	path, err := taskSyncFilePath(c.With)
+	if err != nil {
+		return err
+	}
+
+	doc, mtime, err := readTaskSyncFile(path)
+	if err != nil {
+		return err
+	}
+	statePath, err := taskSyncStatePath(account, tasklistID, path)
+	if err != nil {
+		return err
+	}
+	state, err := loadTaskSyncState(statePath)
+	if err != nil {
+		return err
+	}
+
+	svc, err := newTasksService(ctx, account)
+	if err != nil {
+		return err
+	}
+	remote, order, updated, err := fetchTaskSyncRemote(ctx, svc, tasklistID)
+	if err != nil {
+		return err
+	}
+
+	resolve := func(id string) string {
+		switch c.Prefer {
+		case taskSyncPreferLocal, taskSyncPreferRemote:
+			return c.Prefer
+		}
+		if updated[id].After(mtime) {
+			return taskSyncSideRemote
+		}
+		return taskSyncSideLocal
+	}
+	actions := planTaskSync(doc, state.Tasks, remote, order, resolve)
+
+	if !c.DryRun {
+		deletes := 0
+		for _, a := range actions {
+			if a.Side == taskSyncSideRemote && a.Op == "delete" {
+				deletes++
+			}
+		}
+		if deletes > 0 {
+			if confirmErr := confirmDestructive(ctx, flags, fmt.Sprintf("delete %d task(s) from list %s", deletes, tasklistID)); confirmErr != nil {
+				return confirmErr
+			}
+		}
+		if err := applyTaskSyncRemote(ctx, svc, tasklistID, actions); err != nil {
+			return err
+		}
+		if err := writeTaskSyncFile(path, doc); err != nil {
+			return err
+		}
+		state.Account, state.TasklistID, state.File = account, tasklistID, path
+		state.SyncedAt = time.Now().UTC().Format(time.RFC3339)
+		state.Tasks = map[string]taskSyncRecord{}
+		for _, it := range doc.Items() {
+			if it.ID != "" {
+				state.Tasks[it.ID] = taskSyncRecordOf(it)
+			}
+		}
+		if err := saveTaskSyncState(statePath, state); err != nil {
+			return err
+		}
+	}
+
+	conflicts := 0
+	for _, a := range actions {
+		if a.Conflict {
+			conflicts++
+		}
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"tasklistId": tasklistID,
+			"file":       path,
+			"dryRun":     c.DryRun,
+			"actions":    actions,
+			"conflicts":  conflicts,
+		})
+	}
+
+	if len(actions) == 0 {
+		u.Err().Println("Already in sync")
+		return nil
+	}
+	w, flush := tableWriter(ctx)
+	fmt.Fprintln(w, "OP\tSIDE\tTITLE\tID\tCONFLICT")
+	for _, a := range actions {
+		conflict := ""
+		if a.Conflict {
+			conflict = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", a.Op, a.Side, a.Title, a.ID, conflict)
+	}
+	flush()
+	if c.DryRun {
+		u.Err().Println("Dry run: no changes made")
+	}
+	return nil
+}
+
+func taskSyncFilePath(with string) (string, error) {
+	raw := strings.TrimSpace(with)
+	if raw == "" {
+		return "", usage("required: --with")
+	}
+	if strings.HasPrefix(raw, "caldav://") || strings.HasPrefix(raw, "caldavs://") {
+		return "", usage("caldav:// targets are not supported; pass a Markdown (.md) or org-mode (.org) file")
+	}
+	raw = strings.TrimPrefix(raw, "file://")
+	expanded, err := config.ExpandPath(raw)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(expanded)
+}
+
+// readTaskSyncFile parses the task file. A missing file is an empty list, so
+// the first sync can simply pull everything down.
+func readTaskSyncFile(path string) (*todofile.Document, time.Time, error) {
+	format := todofile.FormatForPath(path)
+	data, err := os.ReadFile(path) //nolint:gosec // user-provided path
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			doc, parseErr := todofile.Parse(bytes.NewReader(nil), format)
+			return doc, time.Time{}, parseErr
+		}
+		return nil, time.Time{}, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	doc, err := todofile.Parse(bytes.NewReader(data), format)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return doc, info.ModTime(), nil
+}
+
+func writeTaskSyncFile(path string, doc *todofile.Document) error {
+	var buf bytes.Buffer
+	if err := doc.Encode(&buf); err != nil {
+		return err
+	}
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), mode); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("commit %s: %w", path, err)
+	}
+	return nil
+}
+
+func taskSyncStatePath(account, tasklistID, file string) (string, error) {
+	dir, err := config.TasksSyncDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(strings.ToLower(account) + "\n" + tasklistID + "\n" + file))
+	return filepath.Join(dir, hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+func loadTaskSyncState(path string) (*taskSyncState, error) {
+	state := &taskSyncState{Tasks: map[string]taskSyncRecord{}}
+	data, err := os.ReadFile(path) //nolint:gosec // path under config dir
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return state, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("read tasks sync state %s: %w", path, err)
+	}
+	if state.Tasks == nil {
+		state.Tasks = map[string]taskSyncRecord{}
+	}
+	return state, nil
+}
+
+func saveTaskSyncState(path string, state *taskSyncState) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	payload, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(payload, '\n'), 0o600)
+}
+
+func fetchTaskSyncRemote(ctx context.Context, svc *tasks.Service, tasklistID string) (map[string]taskSyncRecord, []string, map[string]time.Time, error) {
+	records := map[string]taskSyncRecord{}
+	updated := map[string]time.Time{}
+	var order []string
+	err := svc.Tasks.List(tasklistID).
+		MaxResults(100).
+		ShowCompleted(true).
+		ShowHidden(true).
+		Pages(ctx, func(resp *tasks.Tasks) error {
+			for _, t := range resp.Items {
+				if t == nil || t.Deleted || t.Id == "" {
+					continue
+				}
+				records[t.Id] = taskSyncRecord{
+					Title: strings.TrimSpace(t.Title),
+					Done:  t.Status == taskStatusCompleted,
+					Due:   taskSyncDueDate(t.Due),
+				}
+				updated[t.Id], _ = time.Parse(time.RFC3339, t.Updated)
+				order = append(order, t.Id)
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return records, order, updated, nil
+}
+
+func taskSyncDueDate(due string) string {
+	due = strings.TrimSpace(due)
+	if len(due) < len("2006-01-02") {
+		return ""
+	}
+	return due[:len("2006-01-02")]
+}
+
+func taskSyncRecordOf(it *todofile.Item) taskSyncRecord {
+	return taskSyncRecord{Title: strings.TrimSpace(it.Title), Done: it.Done, Due: it.Due}
+}
+
+// planTaskSync is a three-way merge of the file, Google Tasks and the last
+// synced snapshot. Local-side changes are applied to doc in place; remote-side
+// actions are returned for applyTaskSyncRemote. resolve picks the winning side
+// for a task changed on both sides.
+func planTaskSync(doc *todofile.Document, base, remote map[string]taskSyncRecord, remoteOrder []string, resolve func(id string) string) []taskSyncAction {
+	items := doc.Items()
+	local := map[string]*todofile.Item{}
+	for _, it := range items {
+		if it.ID != "" {
+			local[it.ID] = it
+		}
+	}
+
+	// Link new lines to same-titled remote tasks the file does not know yet,
+	// so a first sync does not duplicate everything.
+	for _, it := range items {
+		if it.ID != "" {
+			continue
+		}
+		for _, id := range remoteOrder {
+			_, seen := local[id]
+			_, synced := base[id]
+			if !seen && !synced && remote[id].Title == strings.TrimSpace(it.Title) {
+				it.ID = id
+				local[id] = it
+				break
+			}
+		}
+	}
+
+	var ids []string
+	seen := map[string]bool{}
+	add := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, it := range items {
+		add(it.ID)
+	}
+	for _, id := range remoteOrder {
+		add(id)
+	}
+	baseIDs := make([]string, 0, len(base))
+	for id := range base {
+		baseIDs = append(baseIDs, id)
+	}
+	sort.Strings(baseIDs)
+	for _, id := range baseIDs {
+		add(id)
+	}
+
+	var actions []taskSyncAction
+	for _, id := range ids {
+		l, inLocal := local[id]
+		r, inRemote := remote[id]
+		b, inBase := base[id]
+
+		switch {
+		case inLocal && inRemote:
+			lr := taskSyncRecordOf(l)
+			if lr == r {
+				continue
+			}
+			localChanged := !inBase || lr != b
+			remoteChanged := !inBase || r != b
+			conflict := localChanged && remoteChanged
+			if (localChanged && !remoteChanged) || (conflict && resolve(id) == taskSyncSideLocal) {
+				actions = append(actions, taskSyncAction{Op: "update", Side: taskSyncSideRemote, ID: id, Title: lr.Title, Conflict: conflict, item: l})
+				continue
+			}
+			l.Title, l.Done, l.Due = r.Title, r.Done, r.Due
+			actions = append(actions, taskSyncAction{Op: "update", Side: taskSyncSideLocal, ID: id, Title: r.Title, Conflict: conflict})
+
+		case inLocal:
+			if inBase && taskSyncRecordOf(l) == b {
+				doc.Remove(id)
+				actions = append(actions, taskSyncAction{Op: "delete", Side: taskSyncSideLocal, ID: id, Title: l.Title})
+				continue
+			}
+			// Unknown to Google Tasks, or edited locally after it was deleted
+			// remotely: the local edit is the newer intent, so recreate it.
+			l.ID = ""
+			actions = append(actions, taskSyncAction{Op: "create", Side: taskSyncSideRemote, Title: l.Title, Conflict: inBase, item: l})
+
+		case inRemote:
+			if inBase && r == b {
+				actions = append(actions, taskSyncAction{Op: "delete", Side: taskSyncSideRemote, ID: id, Title: r.Title})
+				continue
+			}
+			conflict := inBase
+			if conflict && resolve(id) == taskSyncSideLocal {
+				actions = append(actions, taskSyncAction{Op: "delete", Side: taskSyncSideRemote, ID: id, Title: r.Title, Conflict: true})
+				continue
+			}
+			doc.Append(todofile.Item{ID: id, Title: r.Title, Done: r.Done, Due: r.Due})
+			actions = append(actions, taskSyncAction{Op: "create", Side: taskSyncSideLocal, ID: id, Title: r.Title, Conflict: conflict})
+		}
+	}
+
+	for _, it := range doc.Items() {
+		if it.ID == "" && strings.TrimSpace(it.Title) != "" && !taskSyncPending(actions, it) {
+			actions = append(actions, taskSyncAction{Op: "create", Side: taskSyncSideRemote, Title: strings.TrimSpace(it.Title), item: it})
+		}
+	}
+	return actions
+}
+
+func taskSyncPending(actions []taskSyncAction, it *todofile.Item) bool {
+	for _, a := range actions {
+		if a.item == it {
+			return true
+		}
+	}
+	return false
+}
+
+func applyTaskSyncRemote(ctx context.Context, svc *tasks.Service, tasklistID string, actions []taskSyncAction) error {
+	for i := range actions {
+		a := &actions[i]
+		if a.Side != taskSyncSideRemote {
+			continue
+		}
+		switch a.Op {
+		case "create":
+			created, err := svc.Tasks.Insert(tasklistID, taskFromSyncItem(a.item)).Context(ctx).Do()
+			if err != nil {
+				return fmt.Errorf("create %q: %w", a.Title, err)
+			}
+			a.ID = created.Id
+			a.item.ID = created.Id
+		case "update":
+			if _, err := svc.Tasks.Patch(tasklistID, a.ID, taskFromSyncItem(a.item)).Context(ctx).Do(); err != nil {
+				return fmt.Errorf("update %q: %w", a.Title, err)
+			}
+		case "delete":
+			if err := svc.Tasks.Delete(tasklistID, a.ID).Context(ctx).Do(); err != nil {
+				return fmt.Errorf("delete %q: %w", a.Title, err)
+			}
+		}
+	}
+	return nil
+}
+
+func taskFromSyncItem(it *todofile.Item) *tasks.Task {
+	t := &tasks.Task{Title: strings.TrimSpace(it.Title), Status: taskStatusNeedsAction}
+	if it.Done {
+		t.Status = taskStatusCompleted
+	}
+	if due, err := time.Parse("2006-01-02", it.Due); err == nil {
+		t.Due = formatTaskDue(due, false)
+	} else {
+		t.NullFields = []string{"Due"}
+	}
+	return t
+}
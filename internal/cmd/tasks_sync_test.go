@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/tasks/v1"
+
+	"github.com/steipete/gogcli/internal/todofile"
+)
+
+func TestPlanTaskSync_Conflicts(t *testing.T) {
+	base := map[string]taskSyncRecord{"x": {Title: "Original"}}
+	remote := map[string]taskSyncRecord{"x": {Title: "Remote edit"}}
+
+	for _, tc := range []struct {
+		winner    string
+		wantSide  string
+		wantTitle string
+	}{
+		{winner: taskSyncSideLocal, wantSide: taskSyncSideRemote, wantTitle: "Local edit"},
+		{winner: taskSyncSideRemote, wantSide: taskSyncSideLocal, wantTitle: "Remote edit"},
+	} {
+		doc, err := todofile.Parse(strings.NewReader("- [ ] Local edit <!-- gog:x -->\n"), todofile.Markdown)
+		if err != nil {
+			t.Fatalf("Parse: %v", err)
+		}
+		actions := planTaskSync(doc, base, remote, []string{"x"}, func(string) string { return tc.winner })
+		if len(actions) != 1 || actions[0].Side != tc.wantSide || !actions[0].Conflict || actions[0].Op != "update" {
+			t.Fatalf("winner %s: actions %#v", tc.winner, actions)
+		}
+		if got := doc.Items()[0].Title; got != tc.wantTitle {
+			t.Fatalf("winner %s: local title %q, want %q", tc.winner, got, tc.wantTitle)
+		}
+	}
+}
+
+func TestPlanTaskSync_RemoteEditBeatsLocalDelete(t *testing.T) {
+	doc, err := todofile.Parse(strings.NewReader("# nothing here\n"), todofile.Markdown)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	base := map[string]taskSyncRecord{"x": {Title: "Task"}}
+	remote := map[string]taskSyncRecord{"x": {Title: "Task", Done: true}}
+	actions := planTaskSync(doc, base, remote, []string{"x"}, func(string) string { return taskSyncSideRemote })
+	if len(actions) != 1 || actions[0].Op != "create" || actions[0].Side != taskSyncSideLocal || !actions[0].Conflict {
+		t.Fatalf("actions %#v", actions)
+	}
+	if items := doc.Items(); len(items) != 1 || items[0].ID != "x" || !items[0].Done {
+		t.Fatalf("items %#v", items)
+	}
+}
+
+func TestTasksSync_TwoWay(t *testing.T) {
+	origNew := newTasksService
+	t.Cleanup(func() { newTasksService = origNew })
+
+	var mu sync.Mutex
+	store := map[string]map[string]any{
+		"r1": {"id": "r1", "title": "Buy milk", "status": "needsAction", "updated": "2026-01-01T00:00:00Z"},
+		"r2": {"id": "r2", "title": "Old remote", "status": "completed", "updated": "2026-01-01T00:00:00Z"},
+	}
+	order := []string{"r1", "r2"}
+	next := 3
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		const prefix = "/tasks/v1/lists/l1/tasks"
+		id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+		switch {
+		case r.URL.Path == prefix && r.Method == http.MethodGet:
+			items := []map[string]any{}
+			for _, id := range order {
+				if task, ok := store[id]; ok {
+					items = append(items, task)
+				}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"items": items})
+		case r.URL.Path == prefix && r.Method == http.MethodPost:
+			var task map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&task)
+			task["id"] = fmt.Sprintf("r%d", next)
+			next++
+			store[task["id"].(string)] = task
+			order = append(order, task["id"].(string))
+			_ = json.NewEncoder(w).Encode(task)
+		case r.Method == http.MethodPatch && store[id] != nil:
+			var patch map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&patch)
+			for k, v := range patch {
+				store[id][k] = v
+			}
+			_ = json.NewEncoder(w).Encode(store[id])
+		case r.Method == http.MethodDelete && store[id] != nil:
+			delete(store, id)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	svc, err := tasks.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newTasksService = func(context.Context, string) (*tasks.Service, error) { return svc, nil }
+
+	path := filepath.Join(t.TempDir(), "TODO.md")
+	if err := os.WriteFile(path, []byte("# Tasks\n- [ ] Buy milk\n- [ ] Local only due:2026-03-02\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	runSync := func() []taskSyncAction {
+		t.Helper()
+		out := captureStdout(t, func() {
+			_ = captureStderr(t, func() {
+				if err := Execute([]string{"--json", "--force", "--account", "sync@b.com", "tasks", "sync", "l1", "--with", path}); err != nil {
+					t.Fatalf("Execute: %v", err)
+				}
+			})
+		})
+		var parsed struct {
+			Actions []taskSyncAction `json:"actions"`
+		}
+		if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+			t.Fatalf("json parse: %v\nout=%q", err, out)
+		}
+		return parsed.Actions
+	}
+
+	// First sync links "Buy milk" by title, pulls r2 and pushes the new line.
+	if actions := runSync(); len(actions) != 2 {
+		t.Fatalf("first sync actions: %#v", actions)
+	}
+	data, _ := os.ReadFile(path)
+	want := "# Tasks\n- [ ] Buy milk <!-- gog:r1 -->\n- [ ] Local only due:2026-03-02 <!-- gog:r3 -->\n- [x] Old remote <!-- gog:r2 -->\n"
+	if string(data) != want {
+		t.Fatalf("file after first sync:\n%s", data)
+	}
+	mu.Lock()
+	if due, _ := store["r3"]["due"].(string); !strings.HasPrefix(due, "2026-03-02") {
+		t.Fatalf("r3 due = %q", due)
+	}
+	// Edit remotely between syncs.
+	store["r3"]["title"] = "Renamed remotely"
+	mu.Unlock()
+
+	// Edit locally: complete r1, drop r2.
+	if err := os.WriteFile(path, []byte("# Tasks\n- [x] Buy milk <!-- gog:r1 -->\n- [ ] Local only due:2026-03-02 <!-- gog:r3 -->\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	actions := runSync()
+	got := map[string]string{}
+	for _, a := range actions {
+		got[a.ID] = a.Op + "/" + a.Side
+	}
+	if got["r1"] != "update/remote" || got["r2"] != "delete/remote" || got["r3"] != "update/local" || len(got) != 3 {
+		t.Fatalf("second sync actions: %#v", actions)
+	}
+	mu.Lock()
+	if store["r1"]["status"] != "completed" || store["r2"] != nil {
+		t.Fatalf("remote after second sync: %#v", store)
+	}
+	mu.Unlock()
+	data, _ = os.ReadFile(path)
+	if !strings.Contains(string(data), "- [ ] Renamed remotely due:2026-03-02 <!-- gog:r3 -->") {
+		t.Fatalf("file after second sync:\n%s", data)
+	}
+
+	if actions := runSync(); len(actions) != 0 {
+		t.Fatalf("third sync should be a no-op: %#v", actions)
+	}
+}
+
+func TestTasksSync_RejectsCalDAV(t *testing.T) {
+	err := Execute([]string{"--account", "a@b.com", "tasks", "sync", "l1", "--with", "caldav://example.com/tasks"})
+	if err == nil || ExitCode(err) != 2 {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+}
@@ -12,8 +12,8 @@ import (
 // TimeRangeFlags provides common time range options for calendar commands.
 // Embed this struct in commands that need time range support.
 type TimeRangeFlags struct {
-	From      string `name:"from" help:"Start time (RFC3339, date, or relative: today, tomorrow, monday)"`
-	To        string `name:"to" help:"End time (RFC3339, date, or relative)"`
+	From      string `name:"from" help:"Start time (RFC3339, date, or relative: today, tomorrow, monday, -7d)"`
+	To        string `name:"to" help:"End time (RFC3339, date, or relative: +3d)"`
 	Today     bool   `name:"today" help:"Today only"`
 	Tomorrow  bool   `name:"tomorrow" help:"Tomorrow only"`
 	Week      bool   `name:"week" help:"This week (uses --week-start, default Mon)"`
@@ -154,6 +154,7 @@ func ResolveTimeRangeWithDefaults(ctx context.Context, svc *calendar.Service, fl
 // - ISO 8601 with numeric timezone: 2026-01-05T14:00:00-0800 (no colon)
 // - Date only: 2026-01-05 (interpreted as start of day in user's timezone)
 // - Relative: today, tomorrow, monday, next tuesday
+// - Offset from now: +3d, -2w, +36h
 func parseTimeExpr(expr string, now time.Time, loc *time.Location) (time.Time, error) {
 	expr = strings.TrimSpace(expr)
 
@@ -183,6 +184,16 @@ func parseTimeExpr(expr string, now time.Time, loc *time.Location) (time.Time, e
 		return startOfDay(now.AddDate(0, 0, -1)), nil
 	}
 
+	// Try an offset from now (days step by calendar day)
+	if len(exprLower) > 1 && (exprLower[0] == '+' || exprLower[0] == '-') {
+		if days, rest, err := parseDayDuration(exprLower[1:]); err == nil {
+			if exprLower[0] == '-' {
+				return now.AddDate(0, 0, -days).Add(-rest), nil
+			}
+			return now.AddDate(0, 0, days).Add(rest), nil
+		}
+	}
+
 	// Try day of week (this week or next)
 	if t, ok := parseWeekday(exprLower, now); ok {
 		return t, nil
@@ -201,7 +212,7 @@ func parseTimeExpr(expr string, now time.Time, loc *time.Location) (time.Time, e
 		return t, nil
 	}
 
-	return time.Time{}, fmt.Errorf("cannot parse %q as time (try: 2026-01-05, today, tomorrow, monday)", expr)
+	return time.Time{}, fmt.Errorf("cannot parse %q as time (try: 2026-01-05, today, tomorrow, monday, +3d)", expr)
 }
 
 // parseWeekday parses weekday expressions like "monday", "next tuesday"
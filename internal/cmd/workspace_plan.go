@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+const (
+	planCreate = "create"
+	planUpdate = "update"
+	planDelete = "delete"
+)
+
+// PlanCmd shows what `gog apply` would change.
+type PlanCmd struct {
+	File string `name:"file" short:"f" required:"" help:"Workspace document (YAML or JSON; - for stdin)"`
+}
+
+// ApplyCmd converges the account to a workspace document.
+type ApplyCmd struct {
+	File string `name:"file" short:"f" required:"" help:"Workspace document (YAML or JSON; - for stdin)"`
+}
+
+// workspaceDoc is the declarative document read by plan and apply. Sections
+// that are left out are not touched. Labels and calendars are only created
+// or updated; filters, group members, and Drive shares are managed as a
+// whole, so anything not listed there is deleted.
+type workspaceDoc struct {
+	Labels    []workspaceLabel     `json:"labels"`
+	Filters   *[]workspaceFilter   `json:"filters"`
+	Calendars []workspaceCalendar  `json:"calendars"`
+	Groups    []workspaceGroup     `json:"groups"`
+	Drive     []workspaceDriveFile `json:"drive"`
+}
+
+// planStep is one change in a plan. run is nil for steps that only report.
+type planStep struct {
+	Action   string `json:"action"`
+	Resource string `json:"resource"`
+	Name     string `json:"name"`
+	Detail   string `json:"detail,omitempty"`
+
+	run func(ctx context.Context) error
+}
+
+func (c *PlanCmd) Run(ctx context.Context, flags *RootFlags) error {
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	doc, err := readWorkspaceDoc(strings.TrimSpace(c.File))
+	if err != nil {
+		return err
+	}
+	steps, err := newWorkspacePlanner(account, false).plan(ctx, doc)
+	if err != nil {
+		return err
+	}
+	return writePlan(ctx, steps, nil)
+}
+
+func (c *ApplyCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	doc, err := readWorkspaceDoc(strings.TrimSpace(c.File))
+	if err != nil {
+		return err
+	}
+	steps, err := newWorkspacePlanner(account, true).plan(ctx, doc)
+	if err != nil {
+		return err
+	}
+	if len(steps) == 0 {
+		return writePlan(ctx, steps, nil)
+	}
+
+	// Show the plan before asking; with --json it is part of the result.
+	if !outfmt.IsJSON(ctx) {
+		if err := writePlan(ctx, steps, nil); err != nil {
+			return err
+		}
+	}
+	creates, updates, deletes := planCounts(steps)
+	if err := confirmDestructive(ctx, flags, fmt.Sprintf("apply %d create(s), %d update(s), %d delete(s) to %s", creates, updates, deletes, account)); err != nil {
+		return err
+	}
+
+	applied := 0
+	var applyErr error
+	for _, step := range steps {
+		if interrupted(ctx) {
+			break
+		}
+		if step.run != nil {
+			if err := step.run(ctx); err != nil {
+				applyErr = fmt.Errorf("%s %s %s: %w", step.Action, step.Resource, step.Name, err)
+				break
+			}
+		}
+		applied++
+	}
+
+	if outfmt.IsJSON(ctx) {
+		if err := writePlan(ctx, steps, map[string]any{"applied": applied, "interrupted": interrupted(ctx)}); err != nil {
+			return err
+		}
+	}
+	switch {
+	case interrupted(ctx):
+		u.Err().Printf("Interrupted after %d of %d changes", applied, len(steps))
+		return nil
+	case applyErr != nil:
+		return &ExitError{Code: 1, Err: fmt.Errorf("applied %d of %d changes: %w", applied, len(steps), applyErr)}
+	}
+	u.Err().Printf("Apply complete: %d change(s)", applied)
+	return nil
+}
+
+func writePlan(ctx context.Context, steps []planStep, extra map[string]any) error {
+	u := ui.FromContext(ctx)
+	creates, updates, deletes := planCounts(steps)
+	if outfmt.IsJSON(ctx) {
+		if steps == nil {
+			steps = []planStep{}
+		}
+		out := map[string]any{
+			"changes": steps,
+			"summary": map[string]int{planCreate: creates, planUpdate: updates, planDelete: deletes},
+		}
+		for k, v := range extra {
+			out[k] = v
+		}
+		return outfmt.WriteJSON(os.Stdout, out)
+	}
+	if len(steps) == 0 {
+		u.Err().Println("No changes. The account matches the document.")
+		return nil
+	}
+	w, flush := tableWriter(ctx)
+	fmt.Fprintln(w, "ACTION\tRESOURCE\tNAME\tDETAIL")
+	for _, s := range steps {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.Action, s.Resource, sanitizeTab(s.Name), orDash(sanitizeTab(s.Detail)))
+	}
+	flush()
+	u.Err().Printf("Plan: %d to create, %d to update, %d to delete.", creates, updates, deletes)
+	return nil
+}
+
+func planCounts(steps []planStep) (creates, updates, deletes int) {
+	for _, s := range steps {
+		switch s.Action {
+		case planCreate:
+			creates++
+		case planUpdate:
+			updates++
+		case planDelete:
+			deletes++
+		}
+	}
+	return creates, updates, deletes
+}
+
+// readWorkspaceDoc reads YAML (or JSON, which is valid YAML) and rejects
+// unknown keys so typos do not silently leave resources unmanaged.
+func readWorkspaceDoc(path string) (*workspaceDoc, error) {
+	if path == "" {
+		return nil, usage("empty --file")
+	}
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		expanded, expandErr := config.ExpandPath(path)
+		if expandErr != nil {
+			return nil, expandErr
+		}
+		data, err = os.ReadFile(expanded) //nolint:gosec // user-provided path
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var raw any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, usagef("%s: %v", path, err)
+	}
+	if raw == nil {
+		return nil, usagef("%s is empty", path)
+	}
+	generic, err := json.Marshal(raw)
+	if err != nil {
+		return nil, usagef("%s: %v", path, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(generic))
+	dec.DisallowUnknownFields()
+	var doc workspaceDoc
+	if err := dec.Decode(&doc); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return nil, usagef("%s: %s should be a %s", path, typeErr.Field, typeErr.Type)
+		}
+		return nil, usagef("%s: %v", path, err)
+	}
+	return &doc, nil
+}
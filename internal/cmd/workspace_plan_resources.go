@@ -0,0 +1,586 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/cloudidentity/v1"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/gmail/v1"
+)
+
+type workspaceLabel struct {
+	Name                  string `json:"name"`
+	LabelListVisibility   string `json:"labelListVisibility,omitempty"`
+	MessageListVisibility string `json:"messageListVisibility,omitempty"`
+	Color                 *struct {
+		Background string `json:"background"`
+		Text       string `json:"text"`
+	} `json:"color,omitempty"`
+}
+
+// workspaceFilter uses label names (or system label IDs such as INBOX) in
+// its action; they are resolved when the filter is created.
+type workspaceFilter struct {
+	Criteria *gmail.FilterCriteria `json:"criteria"`
+	Action   *gmail.FilterAction   `json:"action"`
+}
+
+type workspaceCalendar struct {
+	Summary     string `json:"summary"`
+	Description string `json:"description,omitempty"`
+	TimeZone    string `json:"timeZone,omitempty"`
+	Location    string `json:"location,omitempty"`
+}
+
+// workspaceGroup lists a group's direct members. Owners are never removed.
+type workspaceGroup struct {
+	Email   string   `json:"email"`
+	Members []string `json:"members"`
+}
+
+// workspaceDriveFile lists every share of a file besides its owner.
+type workspaceDriveFile struct {
+	File   string           `json:"file"`
+	Shares []workspaceShare `json:"shares"`
+}
+
+type workspaceShare struct {
+	Email  string `json:"email,omitempty"`
+	Group  bool   `json:"group,omitempty"`
+	Domain string `json:"domain,omitempty"`
+	Anyone bool   `json:"anyone,omitempty"`
+	Role   string `json:"role"`
+}
+
+// workspacePlanner compares a document with the account. Services are only
+// opened for sections the document uses.
+type workspacePlanner struct {
+	account string
+	apply   bool
+	steps   []planStep
+
+	gmail    *gmail.Service
+	labelIDs map[string]string // lower-cased name -> ID, updated as labels are created
+}
+
+func newWorkspacePlanner(account string, apply bool) *workspacePlanner {
+	return &workspacePlanner{account: account, apply: apply}
+}
+
+func (p *workspacePlanner) add(action, resource, name, detail string, run func(context.Context) error) {
+	p.steps = append(p.steps, planStep{Action: action, Resource: resource, Name: name, Detail: detail, run: run})
+}
+
+func (p *workspacePlanner) plan(ctx context.Context, doc *workspaceDoc) ([]planStep, error) {
+	if len(doc.Labels) > 0 || doc.Filters != nil {
+		svc, err := newGmailService(ctx, p.account)
+		if err != nil {
+			return nil, err
+		}
+		p.gmail = svc
+		if err := p.planLabels(ctx, doc.Labels); err != nil {
+			return nil, err
+		}
+	}
+	if doc.Filters != nil {
+		if err := p.planFilters(ctx, *doc.Filters); err != nil {
+			return nil, err
+		}
+	}
+	if len(doc.Calendars) > 0 {
+		if err := p.planCalendars(ctx, doc.Calendars); err != nil {
+			return nil, err
+		}
+	}
+	if len(doc.Groups) > 0 {
+		if err := p.planGroups(ctx, doc.Groups); err != nil {
+			return nil, err
+		}
+	}
+	if len(doc.Drive) > 0 {
+		if err := p.planDrive(ctx, doc.Drive); err != nil {
+			return nil, err
+		}
+	}
+	return p.steps, nil
+}
+
+func (p *workspacePlanner) planLabels(ctx context.Context, labels []workspaceLabel) error {
+	resp, err := p.gmail.Users.Labels.List("me").Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+	p.labelIDs = map[string]string{}
+	live := map[string]*gmail.Label{}
+	for _, l := range resp.Labels {
+		p.labelIDs[strings.ToLower(l.Name)] = l.Id
+		live[strings.ToLower(l.Name)] = l
+	}
+
+	for _, want := range labels {
+		name := strings.TrimSpace(want.Name)
+		if name == "" {
+			return usage("labels: every label needs a name")
+		}
+		patch := &gmail.Label{
+			Name:                  name,
+			LabelListVisibility:   want.LabelListVisibility,
+			MessageListVisibility: want.MessageListVisibility,
+		}
+		if want.Color != nil {
+			patch.Color = &gmail.LabelColor{BackgroundColor: want.Color.Background, TextColor: want.Color.Text}
+		}
+
+		current, ok := live[strings.ToLower(name)]
+		if !ok {
+			if patch.LabelListVisibility == "" {
+				patch.LabelListVisibility = "labelShow"
+			}
+			if patch.MessageListVisibility == "" {
+				patch.MessageListVisibility = "show"
+			}
+			p.add(planCreate, "label", name, "", func(ctx context.Context) error {
+				created, err := p.gmail.Users.Labels.Create("me", patch).Context(ctx).Do()
+				if err != nil {
+					return err
+				}
+				p.labelIDs[strings.ToLower(created.Name)] = created.Id
+				return nil
+			})
+			continue
+		}
+
+		var changes []string
+		if patch.LabelListVisibility != "" && patch.LabelListVisibility != current.LabelListVisibility {
+			changes = append(changes, fmt.Sprintf("labelListVisibility %q -> %q", current.LabelListVisibility, patch.LabelListVisibility))
+		}
+		if patch.MessageListVisibility != "" && patch.MessageListVisibility != current.MessageListVisibility {
+			changes = append(changes, fmt.Sprintf("messageListVisibility %q -> %q", current.MessageListVisibility, patch.MessageListVisibility))
+		}
+		if patch.Color != nil && (current.Color == nil ||
+			!strings.EqualFold(current.Color.BackgroundColor, patch.Color.BackgroundColor) ||
+			!strings.EqualFold(current.Color.TextColor, patch.Color.TextColor)) {
+			changes = append(changes, fmt.Sprintf("color -> %s/%s", patch.Color.BackgroundColor, patch.Color.TextColor))
+		}
+		if len(changes) == 0 {
+			continue
+		}
+		id := current.Id
+		patch.Name = current.Name
+		p.add(planUpdate, "label", current.Name, strings.Join(changes, "; "), func(ctx context.Context) error {
+			_, err := p.gmail.Users.Labels.Patch("me", id, patch).Context(ctx).Do()
+			return err
+		})
+	}
+	return nil
+}
+
+// planFilters manages the account's whole filter set, as `gmail filters
+// list --diff-with` does: filters are matched by criteria and action.
+func (p *workspacePlanner) planFilters(ctx context.Context, filters []workspaceFilter) error {
+	resp, err := p.gmail.Users.Settings.Filters.List("me").Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+	idToName := map[string]string{}
+	for name, id := range p.labelIDs {
+		idToName[id] = name
+	}
+
+	live := map[string]*gmail.Filter{}
+	for _, f := range resp.Filter {
+		key, err := workspaceFilterKey(f.Criteria, f.Action, func(id string) string {
+			if name, ok := idToName[id]; ok {
+				return name
+			}
+			return strings.ToLower(id)
+		})
+		if err != nil {
+			return err
+		}
+		live[key] = f
+	}
+
+	wanted := map[string]bool{}
+	var deletes []planStep
+	for i, want := range filters {
+		if want.Criteria == nil || want.Action == nil {
+			return usagef("filters: filter %d needs criteria and action", i+1)
+		}
+		key, err := workspaceFilterKey(want.Criteria, want.Action, strings.ToLower)
+		if err != nil {
+			return err
+		}
+		if wanted[key] {
+			continue
+		}
+		wanted[key] = true
+		if _, ok := live[key]; ok {
+			continue
+		}
+		for _, name := range append(append([]string{}, want.Action.AddLabelIds...), want.Action.RemoveLabelIds...) {
+			if _, ok := p.labelIDs[strings.ToLower(name)]; !ok && !p.labelPlanned(name) {
+				return usagef("filters: unknown label %q (add it under labels)", name)
+			}
+		}
+		p.add(planCreate, "filter", filterCriteriaSummary(want.Criteria), filterActionSummary(want.Action), func(ctx context.Context) error {
+			action := *want.Action
+			action.AddLabelIds = p.resolveLabelIDs(want.Action.AddLabelIds)
+			action.RemoveLabelIds = p.resolveLabelIDs(want.Action.RemoveLabelIds)
+			_, err := p.gmail.Users.Settings.Filters.Create("me", &gmail.Filter{Criteria: want.Criteria, Action: &action}).Context(ctx).Do()
+			return err
+		})
+	}
+
+	keys := make([]string, 0, len(live))
+	for key := range live {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if wanted[key] {
+			continue
+		}
+		f := live[key]
+		id := f.Id
+		deletes = append(deletes, planStep{Action: planDelete, Resource: "filter", Name: filterCriteriaSummary(f.Criteria), Detail: "id " + id,
+			run: func(ctx context.Context) error {
+				return p.gmail.Users.Settings.Filters.Delete("me", id).Context(ctx).Do()
+			}})
+	}
+	// Deletes go after creates so mail is never left unfiltered.
+	p.steps = append(p.steps, deletes...)
+	return nil
+}
+
+func (p *workspacePlanner) labelPlanned(name string) bool {
+	for _, s := range p.steps {
+		if s.Resource == "label" && s.Action == planCreate && strings.EqualFold(s.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *workspacePlanner) resolveLabelIDs(names []string) []string {
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		if id, ok := p.labelIDs[strings.ToLower(name)]; ok {
+			out = append(out, id)
+		} else {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// workspaceFilterKey identifies a filter by criteria and action, with label
+// IDs mapped to lower-cased names and sorted.
+func workspaceFilterKey(criteria *gmail.FilterCriteria, action *gmail.FilterAction, labelName func(string) string) (string, error) {
+	var a *gmail.FilterAction
+	if action != nil {
+		copied := *action
+		copied.AddLabelIds = mapSorted(action.AddLabelIds, labelName)
+		copied.RemoveLabelIds = mapSorted(action.RemoveLabelIds, labelName)
+		a = &copied
+	}
+	value, err := toJSONValue(&gmail.Filter{Criteria: criteria, Action: a})
+	if err != nil {
+		return "", err
+	}
+	return settingKey(value), nil
+}
+
+func mapSorted(in []string, f func(string) string) []string {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(in))
+	for _, s := range in {
+		out = append(out, f(s))
+	}
+	sort.Strings(out)
+	return out
+}
+
+func filterCriteriaSummary(c *gmail.FilterCriteria) string {
+	if c == nil {
+		return ""
+	}
+	var parts []string
+	for _, kv := range [][2]string{{"from", c.From}, {"to", c.To}, {"subject", c.Subject}, {"query", c.Query}, {"-query", c.NegatedQuery}} {
+		if kv[1] != "" {
+			parts = append(parts, kv[0]+":"+kv[1])
+		}
+	}
+	if c.HasAttachment {
+		parts = append(parts, "has:attachment")
+	}
+	return strings.Join(parts, " ")
+}
+
+func filterActionSummary(a *gmail.FilterAction) string {
+	if a == nil {
+		return ""
+	}
+	var parts []string
+	for _, l := range a.AddLabelIds {
+		parts = append(parts, "+"+l)
+	}
+	for _, l := range a.RemoveLabelIds {
+		parts = append(parts, "-"+l)
+	}
+	if a.Forward != "" {
+		parts = append(parts, "forward:"+a.Forward)
+	}
+	return strings.Join(parts, " ")
+}
+
+// planCalendars matches calendars the account owns by summary.
+func (p *workspacePlanner) planCalendars(ctx context.Context, calendars []workspaceCalendar) error {
+	svc, err := newCalendarService(ctx, p.account)
+	if err != nil {
+		return err
+	}
+	live := map[string]*calendar.CalendarListEntry{}
+	err = svc.CalendarList.List().MinAccessRole("owner").Pages(ctx, func(resp *calendar.CalendarList) error {
+		for _, e := range resp.Items {
+			live[strings.ToLower(e.Summary)] = e
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, want := range calendars {
+		summary := strings.TrimSpace(want.Summary)
+		if summary == "" {
+			return usage("calendars: every calendar needs a summary")
+		}
+		current, ok := live[strings.ToLower(summary)]
+		if !ok {
+			cal := &calendar.Calendar{Summary: summary, Description: want.Description, TimeZone: want.TimeZone, Location: want.Location}
+			p.add(planCreate, "calendar", summary, "", func(ctx context.Context) error {
+				_, err := svc.Calendars.Insert(cal).Context(ctx).Do()
+				return err
+			})
+			continue
+		}
+
+		patch := &calendar.Calendar{}
+		var changes []string
+		if want.Description != "" && want.Description != current.Description {
+			patch.Description = want.Description
+			changes = append(changes, fmt.Sprintf("description %q -> %q", current.Description, want.Description))
+		}
+		if want.TimeZone != "" && want.TimeZone != current.TimeZone {
+			patch.TimeZone = want.TimeZone
+			changes = append(changes, fmt.Sprintf("timeZone %q -> %q", current.TimeZone, want.TimeZone))
+		}
+		if want.Location != "" && want.Location != current.Location {
+			patch.Location = want.Location
+			changes = append(changes, fmt.Sprintf("location %q -> %q", current.Location, want.Location))
+		}
+		if len(changes) == 0 {
+			continue
+		}
+		id := current.Id
+		p.add(planUpdate, "calendar", summary, strings.Join(changes, "; "), func(ctx context.Context) error {
+			_, err := svc.Calendars.Patch(id, patch).Context(ctx).Do()
+			return err
+		})
+	}
+	return nil
+}
+
+// planGroups adds missing members and removes unlisted ones, except owners.
+func (p *workspacePlanner) planGroups(ctx context.Context, groups []workspaceGroup) error {
+	newService := newCloudIdentityService
+	if p.apply {
+		newService = newCloudIdentityEditorService
+	}
+	svc, err := newService(ctx, p.account)
+	if err != nil {
+		return wrapCloudIdentityError(err, p.account)
+	}
+
+	for _, want := range groups {
+		email := strings.TrimSpace(want.Email)
+		if email == "" {
+			return usage("groups: every group needs an email")
+		}
+		groupName, err := lookupGroupByEmail(ctx, svc, email)
+		if err != nil {
+			return wrapCloudIdentityError(fmt.Errorf("%s: %w", email, err), p.account)
+		}
+		memberships, err := listGroupMemberships(ctx, svc, groupName, 200)
+		if err != nil {
+			return wrapCloudIdentityError(err, p.account)
+		}
+
+		live := map[string]*cloudidentity.Membership{}
+		for _, m := range memberships {
+			if m.PreferredMemberKey != nil {
+				live[strings.ToLower(m.PreferredMemberKey.Id)] = m
+			}
+		}
+		wanted := map[string]bool{}
+		for _, member := range want.Members {
+			member = strings.TrimSpace(member)
+			key := strings.ToLower(member)
+			if key == "" || wanted[key] {
+				continue
+			}
+			wanted[key] = true
+			if _, ok := live[key]; ok {
+				continue
+			}
+			m := &cloudidentity.Membership{
+				PreferredMemberKey: &cloudidentity.EntityKey{Id: member},
+				Roles:              []*cloudidentity.MembershipRole{{Name: groupRoleMember}},
+			}
+			p.add(planCreate, "group member", email, member, func(ctx context.Context) error {
+				_, err := svc.Groups.Memberships.Create(groupName, m).Context(ctx).Do()
+				return err
+			})
+		}
+
+		keys := make([]string, 0, len(live))
+		for key := range live {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			m := live[key]
+			if wanted[key] || getMemberRole(m.Roles) == groupRoleOwner {
+				continue
+			}
+			name := m.Name
+			p.add(planDelete, "group member", email, m.PreferredMemberKey.Id, func(ctx context.Context) error {
+				_, err := svc.Groups.Memberships.Delete(name).Context(ctx).Do()
+				return err
+			})
+		}
+	}
+	return nil
+}
+
+// planDrive manages every non-owner permission on the listed files.
+func (p *workspacePlanner) planDrive(ctx context.Context, files []workspaceDriveFile) error {
+	svc, err := newDriveService(ctx, p.account)
+	if err != nil {
+		return err
+	}
+
+	for _, want := range files {
+		fileID := strings.TrimSpace(want.File)
+		if fileID == "" {
+			return usage("drive: every entry needs a file ID")
+		}
+		var perms []*drive.Permission
+		err := svc.Permissions.List(fileID).
+			SupportsAllDrives(true).
+			Fields("nextPageToken, permissions(id, type, role, emailAddress, domain)").
+			Pages(ctx, func(resp *drive.PermissionList) error {
+				perms = append(perms, resp.Permissions...)
+				return nil
+			})
+		if err != nil {
+			return fmt.Errorf("%s: %w", fileID, err)
+		}
+		live := map[string]*drive.Permission{}
+		for _, perm := range perms {
+			if perm.Role != "owner" {
+				live[drivePermissionKey(perm)] = perm
+			}
+		}
+
+		wanted := map[string]bool{}
+		for _, share := range want.Shares {
+			perm, err := share.permission()
+			if err != nil {
+				return fmt.Errorf("drive %s: %w", fileID, err)
+			}
+			key := drivePermissionKey(perm)
+			if wanted[key] {
+				return usagef("drive %s: %s listed twice", fileID, key)
+			}
+			wanted[key] = true
+
+			current, ok := live[key]
+			switch {
+			case !ok:
+				p.add(planCreate, "drive share", fileID, key+" "+perm.Role, func(ctx context.Context) error {
+					_, err := svc.Permissions.Create(fileID, perm).SupportsAllDrives(true).SendNotificationEmail(false).Context(ctx).Do()
+					return err
+				})
+			case current.Role != perm.Role:
+				id := current.Id
+				role := perm.Role
+				p.add(planUpdate, "drive share", fileID, fmt.Sprintf("%s %s -> %s", key, current.Role, role), func(ctx context.Context) error {
+					_, err := svc.Permissions.Update(fileID, id, &drive.Permission{Role: role}).SupportsAllDrives(true).Context(ctx).Do()
+					return err
+				})
+			}
+		}
+
+		keys := make([]string, 0, len(live))
+		for key := range live {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if wanted[key] {
+				continue
+			}
+			current := live[key]
+			id := current.Id
+			p.add(planDelete, "drive share", fileID, key+" "+current.Role, func(ctx context.Context) error {
+				return svc.Permissions.Delete(fileID, id).SupportsAllDrives(true).Context(ctx).Do()
+			})
+		}
+	}
+	return nil
+}
+
+func (s workspaceShare) permission() (*drive.Permission, error) {
+	role := strings.TrimSpace(s.Role)
+	if role == "" {
+		role = "reader"
+	}
+	switch role {
+	case "reader", "commenter", "writer", "fileOrganizer", "organizer":
+	default:
+		return nil, usagef("invalid role %q (expected reader|commenter|writer)", s.Role)
+	}
+	switch {
+	case s.Anyone:
+		return &drive.Permission{Type: "anyone", Role: role}, nil
+	case strings.TrimSpace(s.Domain) != "":
+		return &drive.Permission{Type: "domain", Domain: strings.TrimSpace(s.Domain), Role: role}, nil
+	case strings.TrimSpace(s.Email) != "":
+		typ := "user"
+		if s.Group {
+			typ = "group"
+		}
+		return &drive.Permission{Type: typ, EmailAddress: strings.TrimSpace(s.Email), Role: role}, nil
+	}
+	return nil, usage("each share needs email, domain, or anyone: true")
+}
+
+// drivePermissionKey names who a permission is for, e.g. "user:a@example.com".
+func drivePermissionKey(p *drive.Permission) string {
+	switch p.Type {
+	case "anyone":
+		return "anyone"
+	case "domain":
+		return "domain:" + strings.ToLower(p.Domain)
+	default:
+		return p.Type + ":" + strings.ToLower(p.EmailAddress)
+	}
+}
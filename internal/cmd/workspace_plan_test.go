@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+)
+
+const testWorkspaceDoc = `
+labels:
+  - name: Receipts
+filters:
+  - criteria: {from: billing@example.com}
+    action: {addLabelIds: [Receipts], removeLabelIds: [INBOX]}
+  - criteria: {from: boss@example.com}
+    action: {addLabelIds: [IMPORTANT]}
+drive:
+  - file: f1
+    shares:
+      - {email: bob@example.com, role: writer}
+      - {domain: example.com, role: reader}
+`
+
+func TestWorkspacePlanApply(t *testing.T) {
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := r.URL.Path
+		if r.Method != http.MethodGet {
+			body, _ := io.ReadAll(r.Body)
+			calls = append(calls, r.Method+" "+path[strings.LastIndex(path, "/users/me/")+1:]+" "+strings.TrimSpace(string(body)))
+		}
+		switch {
+		case strings.HasSuffix(path, "/labels") && r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]any{"labels": []map[string]any{
+				{"id": "INBOX", "name": "INBOX"}, {"id": "IMPORTANT", "name": "IMPORTANT"},
+			}})
+		case strings.HasSuffix(path, "/labels") && r.Method == http.MethodPost:
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "Label_9", "name": "Receipts"})
+		case strings.HasSuffix(path, "/settings/filters") && r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]any{"filter": []map[string]any{
+				{"id": "f-keep", "criteria": map[string]any{"from": "boss@example.com"}, "action": map[string]any{"addLabelIds": []string{"IMPORTANT"}}},
+				{"id": "f-old", "criteria": map[string]any{"from": "old@example.com"}, "action": map[string]any{"removeLabelIds": []string{"INBOX"}}},
+			}})
+		case strings.HasSuffix(path, "/settings/filters") && r.Method == http.MethodPost:
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "f-new"})
+		case strings.HasSuffix(path, "/files/f1/permissions") && r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]any{"permissions": []map[string]any{
+				{"id": "p0", "type": "user", "role": "owner", "emailAddress": "me@example.com"},
+				{"id": "p1", "type": "user", "role": "reader", "emailAddress": "Bob@example.com"},
+				{"id": "p2", "type": "anyone", "role": "reader"},
+			}})
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "x"})
+		}
+	}))
+	defer srv.Close()
+	stubGmailService(t, srv)
+	origDrive := newDriveService
+	t.Cleanup(func() { newDriveService = origDrive })
+	dsvc, err := drive.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newDriveService = func(context.Context, string) (*drive.Service, error) { return dsvc, nil }
+
+	doc := filepath.Join(t.TempDir(), "workspace.yaml")
+	if err := os.WriteFile(doc, []byte(testWorkspaceDoc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	run := func(args ...string) map[string]any {
+		var out map[string]any
+		stdout := captureStdout(t, func() {
+			_ = captureStderr(t, func() {
+				if err := Execute(append([]string{"--json", "--force", "--account", "me@example.com"}, args...)); err != nil {
+					t.Fatalf("Execute: %v", err)
+				}
+			})
+		})
+		if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+			t.Fatalf("json: %v\n%s", err, stdout)
+		}
+		return out
+	}
+
+	out := run("plan", "-f", doc)
+	var got []string
+	for _, ch := range out["changes"].([]any) {
+		m := ch.(map[string]any)
+		got = append(got, m["action"].(string)+" "+m["resource"].(string)+" "+m["name"].(string))
+	}
+	want := []string{
+		"create label Receipts",
+		"create filter from:billing@example.com",
+		"delete filter from:old@example.com",
+		"update drive share f1",
+		"create drive share f1",
+		"delete drive share f1",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") || len(calls) != 0 {
+		t.Fatalf("plan =\n%s\ncalls=%v", strings.Join(got, "\n"), calls)
+	}
+	if s := out["summary"].(map[string]any); s["create"] != float64(3) || s["update"] != float64(1) || s["delete"] != float64(2) {
+		t.Fatalf("summary = %v", s)
+	}
+
+	out = run("apply", "-f", doc)
+	if out["applied"] != float64(6) || len(calls) != 6 {
+		t.Fatalf("applied=%v calls=%v", out["applied"], calls)
+	}
+	// The filter created after the label uses the new label's ID.
+	if !strings.Contains(calls[1], `"addLabelIds":["Label_9"]`) || !strings.Contains(calls[1], `"removeLabelIds":["INBOX"]`) {
+		t.Fatalf("filter create = %s", calls[1])
+	}
+	if !strings.HasPrefix(calls[2], "DELETE") || !strings.HasSuffix(strings.TrimSpace(calls[2]), "f-old") {
+		t.Fatalf("filter delete = %s", calls[2])
+	}
+}
+
+func TestReadWorkspaceDoc(t *testing.T) {
+	dir := t.TempDir()
+	write := func(body string) string {
+		path := filepath.Join(dir, "doc.yaml")
+		if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	doc, err := readWorkspaceDoc(write("labels: [{name: A}]\n"))
+	if err != nil || doc.Filters != nil || len(doc.Labels) != 1 {
+		t.Fatalf("doc=%+v err=%v", doc, err)
+	}
+	// An explicit empty list manages filters: every filter is deleted.
+	doc, err = readWorkspaceDoc(write("filters: []\n"))
+	if err != nil || doc.Filters == nil {
+		t.Fatalf("doc=%+v err=%v", doc, err)
+	}
+	if _, err := readWorkspaceDoc(write("lables: [{name: A}]\n")); ExitCode(err) != 2 {
+		t.Fatalf("unknown key: %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package config
+
+import "strings"
+
+// CalendarRule declines invitations that match every condition it sets.
+type CalendarRule struct {
+	Name string `json:"name"`
+	// Account limits the rule to one account; empty applies it to all.
+	Account  string `json:"account,omitempty"`
+	Calendar string `json:"calendar,omitempty"`
+	// NoAgenda matches events whose description is shorter than
+	// MinAgendaChars (default: empty description).
+	NoAgenda       bool `json:"no_agenda,omitempty"`
+	MinAgendaChars int  `json:"min_agenda_chars,omitempty"`
+	// OutsideWorkingHours ("09:00-18:00") matches events that start before or
+	// end after these hours, or fall on a weekend.
+	OutsideWorkingHours    string `json:"outside_working_hours,omitempty"`
+	ConflictsWithFocusTime bool   `json:"conflicts_with_focus_time,omitempty"`
+	// Comment is a Go text/template for the decline note.
+	Comment     string `json:"comment,omitempty"`
+	SendUpdates string `json:"send_updates,omitempty"`
+}
+
+// CalendarRulesFor returns the rules that apply to account, in config order.
+func CalendarRulesFor(account string) ([]CalendarRule, error) {
+	cfg, err := ReadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []CalendarRule
+
+	for _, r := range cfg.CalendarRules {
+		if r.Account == "" || strings.EqualFold(strings.TrimSpace(r.Account), account) {
+			out = append(out, r)
+		}
+	}
+
+	return out, nil
+}
@@ -15,6 +15,24 @@ type File struct {
 	AccountAliases  map[string]string `json:"account_aliases,omitempty"`
 	AccountClients  map[string]string `json:"account_clients,omitempty"`
 	ClientDomains   map[string]string `json:"client_domains,omitempty"`
+	// Defaults maps "[command.path.]flag" to a default flag value.
+	Defaults map[string]string `json:"defaults,omitempty"`
+	// AccountDefaults holds per-account Defaults, keyed by account email.
+	AccountDefaults map[string]map[string]string `json:"account_defaults,omitempty"`
+	// Links maps short names (gog open <name>) to deep links.
+	Links map[string]Link `json:"links,omitempty"`
+	// CalendarRules auto-decline matching invitations (gog calendar rules process).
+	CalendarRules []CalendarRule `json:"calendar_rules,omitempty"`
+	// SendGuardrails warn about or block sends outside trusted domains.
+	SendGuardrails []SendGuardrail `json:"send_guardrails,omitempty"`
+	// Summarizers are AI hooks for --summary-ai (one per account, or shared).
+	Summarizers []Summarizer `json:"summarizers,omitempty"`
+	// Projects label matching threads and events (gog projects apply).
+	Projects []Project `json:"projects,omitempty"`
+	// HTTPHeaders are added to every Google request; values may reference
+	// environment variables as ${NAME}.
+	HTTPHeaders     map[string]string `json:"http_headers,omitempty"`
+	UserAgentSuffix string            `json:"user_agent_suffix,omitempty"`
 }
 
 func ConfigPath() (string, error) {
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultsKeyPrefix marks config keys that set flag defaults, e.g.
+// "defaults.gmail.search.max" or "defaults.json".
+const DefaultsKeyPrefix = "defaults."
+
+// ParseDefaultsKey returns the flag default name ("gmail.search.max") for a
+// "defaults."-prefixed config key.
+func ParseDefaultsKey(raw string) (string, bool) {
+	name, ok := strings.CutPrefix(strings.TrimSpace(raw), DefaultsKeyPrefix)
+	if !ok {
+		return "", false
+	}
+	name = strings.Trim(name, ".")
+	return name, name != ""
+}
+
+// SplitDefaultName splits "gmail.search.max" into the command path
+// ["gmail", "search"] and the flag name "max".
+func SplitDefaultName(name string) ([]string, string) {
+	parts := strings.Split(name, ".")
+	return parts[:len(parts)-1], parts[len(parts)-1]
+}
+
+func (f File) DefaultsFor(account string) map[string]string {
+	if account == "" {
+		return f.Defaults
+	}
+	return f.AccountDefaults[strings.ToLower(strings.TrimSpace(account))]
+}
+
+// GetDefault returns a stored flag default, globally or for one account.
+func (f File) GetDefault(account, name string) string {
+	return f.DefaultsFor(account)[name]
+}
+
+func (f *File) SetDefault(account, name, value string) error {
+	if name == "" || strings.Contains(name, "..") {
+		return fmt.Errorf("invalid defaults key %q", name)
+	}
+	if account == "" {
+		if f.Defaults == nil {
+			f.Defaults = map[string]string{}
+		}
+		f.Defaults[name] = value
+		return nil
+	}
+	account = strings.ToLower(strings.TrimSpace(account))
+	if f.AccountDefaults == nil {
+		f.AccountDefaults = map[string]map[string]string{}
+	}
+	if f.AccountDefaults[account] == nil {
+		f.AccountDefaults[account] = map[string]string{}
+	}
+	f.AccountDefaults[account][name] = value
+	return nil
+}
+
+func (f *File) UnsetDefault(account, name string) {
+	if account == "" {
+		delete(f.Defaults, name)
+		return
+	}
+	account = strings.ToLower(strings.TrimSpace(account))
+	delete(f.AccountDefaults[account], name)
+	if len(f.AccountDefaults[account]) == 0 {
+		delete(f.AccountDefaults, account)
+	}
+}
+
+// DefaultNames lists stored default names in sorted order.
+func DefaultNames(defaults map[string]string) []string {
+	names := make([]string, 0, len(defaults))
+	for name := range defaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupDefault finds the default for flag when running command (e.g.
+// ["gmail", "search"]). The entry with the longest matching command prefix
+// wins, so "gmail.search.max" beats "gmail.max", which beats "max".
+func LookupDefault(defaults map[string]string, command []string, flag string) (string, bool) {
+	best, bestLen, found := "", -1, false
+	for name, value := range defaults {
+		path, f := SplitDefaultName(name)
+		if f != flag || len(path) > len(command) || len(path) <= bestLen {
+			continue
+		}
+		match := true
+		for i, p := range path {
+			if p != command[i] {
+				match = false
+				break
+			}
+		}
+		if match {
+			best, bestLen, found = value, len(path), true
+		}
+	}
+	return best, found
+}
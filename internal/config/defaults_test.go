@@ -0,0 +1,59 @@
+package config
+
+import "testing"
+
+func TestLookupDefault_MostSpecificWins(t *testing.T) {
+	defaults := map[string]string{
+		"max":              "10",
+		"gmail.max":        "20",
+		"gmail.search.max": "30",
+		"calendar.max":     "40",
+	}
+	for _, tc := range []struct {
+		command []string
+		want    string
+	}{
+		{[]string{"gmail", "search"}, "30"},
+		{[]string{"gmail", "thread", "get"}, "20"},
+		{[]string{"tasks", "list"}, "10"},
+		{[]string{"calendar", "events"}, "40"},
+	} {
+		got, ok := LookupDefault(defaults, tc.command, "max")
+		if !ok || got != tc.want {
+			t.Fatalf("LookupDefault(%v) = %q, %v; want %q", tc.command, got, ok, tc.want)
+		}
+	}
+	if _, ok := LookupDefault(map[string]string{"gmail.search.max": "1"}, []string{"gmail"}, "max"); ok {
+		t.Fatalf("a deeper key must not apply to a parent command")
+	}
+}
+
+func TestSetUnsetDefault(t *testing.T) {
+	var f File
+	if err := f.SetDefault("", "gmail.search.max", "50"); err != nil {
+		t.Fatalf("SetDefault: %v", err)
+	}
+	if err := f.SetDefault("Me@Work.com", "gmail.send.from", "alias@work.com"); err != nil {
+		t.Fatalf("SetDefault: %v", err)
+	}
+	if got := f.GetDefault("me@work.com", "gmail.send.from"); got != "alias@work.com" {
+		t.Fatalf("account default = %q", got)
+	}
+	if got := f.GetDefault("", "gmail.send.from"); got != "" {
+		t.Fatalf("account default leaked into global: %q", got)
+	}
+	f.UnsetDefault("me@work.com", "gmail.send.from")
+	if len(f.AccountDefaults) != 0 {
+		t.Fatalf("empty account entry should be dropped: %#v", f.AccountDefaults)
+	}
+	if err := f.SetDefault("", "gmail..max", "1"); err == nil {
+		t.Fatalf("expected error for malformed key")
+	}
+
+	if name, ok := ParseDefaultsKey("defaults.gmail.search.max"); !ok || name != "gmail.search.max" {
+		t.Fatalf("ParseDefaultsKey = %q, %v", name, ok)
+	}
+	if _, ok := ParseDefaultsKey("timezone"); ok {
+		t.Fatalf("timezone is not a defaults key")
+	}
+}
@@ -10,8 +10,9 @@ import (
 type Key string
 
 const (
-	KeyTimezone       Key = "timezone"
-	KeyKeyringBackend Key = "keyring_backend"
+	KeyTimezone        Key = "timezone"
+	KeyKeyringBackend  Key = "keyring_backend"
+	KeyUserAgentSuffix Key = "user_agent_suffix"
 )
 
 type KeySpec struct {
@@ -25,6 +26,7 @@ type KeySpec struct {
 var keyOrder = []Key{
 	KeyTimezone,
 	KeyKeyringBackend,
+	KeyUserAgentSuffix,
 }
 
 var keySpecs = map[Key]KeySpec{
@@ -63,6 +65,25 @@ var keySpecs = map[Key]KeySpec{
 			return "(not set, using auto)"
 		},
 	},
+	KeyUserAgentSuffix: {
+		Key: KeyUserAgentSuffix,
+		Get: func(cfg File) string {
+			return cfg.UserAgentSuffix
+		},
+		Set: func(cfg *File, value string) error {
+			if strings.ContainsAny(value, "\r\n") {
+				return fmt.Errorf("invalid user agent suffix %q", value)
+			}
+			cfg.UserAgentSuffix = strings.TrimSpace(value)
+			return nil
+		},
+		Unset: func(cfg *File) {
+			cfg.UserAgentSuffix = ""
+		},
+		EmptyHint: func() string {
+			return "(not set)"
+		},
+	},
 }
 
 var (
@@ -0,0 +1,80 @@
+package config
+
+import "strings"
+
+// Link is a named deep link into Gmail, Calendar, Drive, or anywhere else.
+type Link struct {
+	URL     string `json:"url"`
+	Kind    string `json:"kind,omitempty"`
+	ID      string `json:"id,omitempty"`
+	Title   string `json:"title,omitempty"`
+	Account string `json:"account,omitempty"`
+}
+
+func NormalizeLinkName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func ResolveLink(name string) (Link, bool, error) {
+	name = NormalizeLinkName(name)
+	if name == "" {
+		return Link{}, false, nil
+	}
+
+	cfg, err := ReadConfig()
+	if err != nil {
+		return Link{}, false, err
+	}
+
+	link, ok := cfg.Links[name]
+
+	return link, ok, nil
+}
+
+func SetLink(name string, link Link) error {
+	name = NormalizeLinkName(name)
+
+	cfg, err := ReadConfig()
+	if err != nil {
+		return err
+	}
+
+	if cfg.Links == nil {
+		cfg.Links = map[string]Link{}
+	}
+
+	cfg.Links[name] = link
+
+	return WriteConfig(cfg)
+}
+
+func DeleteLink(name string) (bool, error) {
+	name = NormalizeLinkName(name)
+
+	cfg, err := ReadConfig()
+	if err != nil {
+		return false, err
+	}
+
+	if _, ok := cfg.Links[name]; !ok {
+		return false, nil
+	}
+
+	delete(cfg.Links, name)
+
+	return true, WriteConfig(cfg)
+}
+
+func ListLinks() (map[string]Link, error) {
+	cfg, err := ReadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]Link, len(cfg.Links))
+	for k, v := range cfg.Links {
+		out[k] = v
+	}
+
+	return out, nil
+}
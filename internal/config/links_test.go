@@ -0,0 +1,47 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLinksCRUD(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg-config"))
+
+	if err := SetLink(" Q3-Plan ", Link{URL: "https://docs.example/q3", Kind: "url"}); err != nil {
+		t.Fatalf("set link: %v", err)
+	}
+
+	link, ok, err := ResolveLink("q3-plan")
+	if err != nil {
+		t.Fatalf("resolve link: %v", err)
+	}
+
+	if !ok || link.URL != "https://docs.example/q3" {
+		t.Fatalf("unexpected link resolve: ok=%v link=%#v", ok, link)
+	}
+
+	links, err := ListLinks()
+	if err != nil {
+		t.Fatalf("list links: %v", err)
+	}
+
+	if len(links) != 1 || links["q3-plan"].Kind != "url" {
+		t.Fatalf("unexpected link list: %#v", links)
+	}
+
+	deleted, err := DeleteLink("Q3-PLAN")
+	if err != nil {
+		t.Fatalf("delete link: %v", err)
+	}
+
+	if !deleted {
+		t.Fatalf("expected link to be deleted")
+	}
+
+	if _, ok, _ := ResolveLink("q3-plan"); ok {
+		t.Fatalf("expected link to be gone")
+	}
+}
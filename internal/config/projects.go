@@ -0,0 +1,44 @@
+package config
+
+import "strings"
+
+// Project maps mail and events to a project by keyword or participant
+// (gog projects apply).
+type Project struct {
+	Name string `json:"name"`
+	// Account limits the project to one account; empty applies it to all.
+	Account string `json:"account,omitempty"`
+	// Label is the Gmail label for matching threads; default "Projects/<name>".
+	Label string `json:"label,omitempty"`
+	// Keywords match subjects and bodies (mail) or titles and descriptions
+	// (events), case-insensitively.
+	Keywords []string `json:"keywords,omitempty"`
+	// Participants are email addresses or @domains.
+	Participants []string `json:"participants,omitempty"`
+}
+
+// LabelName returns the Gmail label for p.
+func (p Project) LabelName() string {
+	if l := strings.TrimSpace(p.Label); l != "" {
+		return l
+	}
+	return "Projects/" + strings.TrimSpace(p.Name)
+}
+
+// ProjectsFor returns the projects that apply to account, in config order.
+func ProjectsFor(account string) ([]Project, error) {
+	cfg, err := ReadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Project
+
+	for _, p := range cfg.Projects {
+		if p.Account == "" || strings.EqualFold(strings.TrimSpace(p.Account), account) {
+			out = append(out, p)
+		}
+	}
+
+	return out, nil
+}
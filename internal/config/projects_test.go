@@ -0,0 +1,32 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestProjectsFor(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg-config"))
+
+	if err := WriteConfig(File{Projects: []Project{
+		{Name: "falcon", Keywords: []string{"falcon"}},
+		{Name: "heron", Account: "Work@Example.com", Label: "Clients/Heron"},
+	}}); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	got, err := ProjectsFor("work@example.com")
+	if err != nil || len(got) != 2 {
+		t.Fatalf("work: %#v err=%v", got, err)
+	}
+	if got[0].LabelName() != "Projects/falcon" || got[1].LabelName() != "Clients/Heron" {
+		t.Fatalf("labels: %q %q", got[0].LabelName(), got[1].LabelName())
+	}
+
+	got, err = ProjectsFor("me@gmail.com")
+	if err != nil || len(got) != 1 || got[0].Name != "falcon" {
+		t.Fatalf("other account: %#v err=%v", got, err)
+	}
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ScopeUsage records, per account and API, the last day gog read from or
+// wrote to it. `gog auth minimize` derives the scopes to keep from it.
+type ScopeUsage struct {
+	Accounts map[string]map[string]APIUsage `json:"accounts"`
+}
+
+// APIUsage holds YYYY-MM-DD dates of the last read and write calls.
+type APIUsage struct {
+	LastRead  string `json:"lastRead,omitempty"`
+	LastWrite string `json:"lastWrite,omitempty"`
+}
+
+var scopeUsageMu sync.Mutex
+
+func ReadScopeUsage() (ScopeUsage, error) {
+	path, err := ScopeUsagePath()
+	if err != nil {
+		return ScopeUsage{}, err
+	}
+	var usage ScopeUsage
+	data, err := os.ReadFile(path) //nolint:gosec // path under config dir
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return usage, nil
+		}
+		return usage, err
+	}
+	if err := json.Unmarshal(data, &usage); err != nil {
+		return usage, fmt.Errorf("parse scope usage: %w", err)
+	}
+	return usage, nil
+}
+
+// RecordScopeUsage notes that email used api (a service name such as
+// "gmail") on the day of now. It only writes when the date changes.
+func RecordScopeUsage(email, api string, write bool, now time.Time) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" || api == "" {
+		return nil
+	}
+	day := now.UTC().Format("2006-01-02")
+
+	scopeUsageMu.Lock()
+	defer scopeUsageMu.Unlock()
+
+	usage, err := ReadScopeUsage()
+	if err != nil {
+		return err
+	}
+	if usage.Accounts == nil {
+		usage.Accounts = map[string]map[string]APIUsage{}
+	}
+	if usage.Accounts[email] == nil {
+		usage.Accounts[email] = map[string]APIUsage{}
+	}
+	entry := usage.Accounts[email][api]
+	if write {
+		if entry.LastWrite == day {
+			return nil
+		}
+		entry.LastWrite = day
+	} else {
+		if entry.LastRead == day {
+			return nil
+		}
+		entry.LastRead = day
+	}
+	usage.Accounts[email][api] = entry
+
+	path, err := ScopeUsagePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("ensure state dir: %w", err)
+	}
+	payload, err := json.MarshalIndent(usage, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(payload, '\n'), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecordScopeUsage(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	day1 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := RecordScopeUsage("A@Example.com", "gmail", false, day1); err != nil {
+		t.Fatalf("RecordScopeUsage: %v", err)
+	}
+	if err := RecordScopeUsage("a@example.com", "gmail", true, day1.AddDate(0, 0, 2)); err != nil {
+		t.Fatalf("RecordScopeUsage: %v", err)
+	}
+
+	usage, err := ReadScopeUsage()
+	if err != nil {
+		t.Fatalf("ReadScopeUsage: %v", err)
+	}
+	got := usage.Accounts["a@example.com"]["gmail"]
+	if got.LastRead != "2026-03-01" || got.LastWrite != "2026-03-03" {
+		t.Fatalf("unexpected usage: %#v", got)
+	}
+}
@@ -0,0 +1,40 @@
+package config
+
+import "strings"
+
+// Send guardrail actions.
+const (
+	GuardrailWarn  = "warn"
+	GuardrailBlock = "block"
+)
+
+// SendGuardrail warns about or blocks sends to recipients outside
+// InternalDomains (subdomains count as internal).
+type SendGuardrail struct {
+	Name string `json:"name"`
+	// Account limits the guardrail to one account; empty applies it to all.
+	Account         string   `json:"account,omitempty"`
+	InternalDomains []string `json:"internal_domains"`
+	// AttachmentsOnly limits the guardrail to messages with attachments.
+	AttachmentsOnly bool `json:"attachments_only,omitempty"`
+	// Action is "warn" (default) or "block".
+	Action string `json:"action,omitempty"`
+}
+
+// SendGuardrailsFor returns the guardrails that apply to account, in config order.
+func SendGuardrailsFor(account string) ([]SendGuardrail, error) {
+	cfg, err := ReadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []SendGuardrail
+
+	for _, g := range cfg.SendGuardrails {
+		if g.Account == "" || strings.EqualFold(strings.TrimSpace(g.Account), account) {
+			out = append(out, g)
+		}
+	}
+
+	return out, nil
+}
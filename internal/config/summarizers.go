@@ -0,0 +1,40 @@
+package config
+
+import "strings"
+
+// Summarizer is an AI hook gog sends mail context to: either a command
+// (request JSON on stdin) or an HTTP endpoint (request JSON POSTed).
+type Summarizer struct {
+	// Account limits the hook to one account; empty applies it to all.
+	Account  string `json:"account,omitempty"`
+	Command  string `json:"command,omitempty"`
+	Endpoint string `json:"endpoint,omitempty"`
+	// Headers are sent to Endpoint; values may reference environment
+	// variables as ${NAME}.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Timeout is a Go duration such as "90s"; the default is two minutes.
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// SummarizerFor returns the hook for account: one scoped to the account wins
+// over an unscoped one. ok is false when none is configured.
+func SummarizerFor(account string) (s Summarizer, ok bool, err error) {
+	cfg, err := ReadConfig()
+	if err != nil {
+		return Summarizer{}, false, err
+	}
+
+	for _, h := range cfg.Summarizers {
+		if strings.TrimSpace(h.Account) != "" && strings.EqualFold(strings.TrimSpace(h.Account), account) {
+			return h, true, nil
+		}
+	}
+
+	for _, h := range cfg.Summarizers {
+		if strings.TrimSpace(h.Account) == "" {
+			return h, true, nil
+		}
+	}
+
+	return Summarizer{}, false, nil
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSummarizerFor(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg-config"))
+
+	if _, ok, err := SummarizerFor("a@example.com"); err != nil || ok {
+		t.Fatalf("expected no summarizer: ok=%v err=%v", ok, err)
+	}
+
+	if err := WriteConfig(File{Summarizers: []Summarizer{
+		{Command: "shared-llm"},
+		{Account: "Work@Example.com", Endpoint: "https://llm.example/summarize"},
+	}}); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	s, ok, err := SummarizerFor("work@example.com")
+	if err != nil || !ok || s.Endpoint != "https://llm.example/summarize" {
+		t.Fatalf("scoped: s=%#v ok=%v err=%v", s, ok, err)
+	}
+
+	s, ok, err = SummarizerFor("me@gmail.com")
+	if err != nil || !ok || s.Command != "shared-llm" {
+		t.Fatalf("shared: s=%#v ok=%v err=%v", s, ok, err)
+	}
+}
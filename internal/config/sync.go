@@ -0,0 +1,300 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SyncVersion is the format of the shared sync document.
+const SyncVersion = 1
+
+// Sync keys are "<section>/<name>[/<name>]". Only these sections leave the
+// machine: keyring_backend, account_clients and client_domains point at local
+// keyrings and credential files, and tokens never live in config.json.
+const (
+	syncTimezone        = "default_timezone"
+	syncAccountAliases  = "account_aliases"
+	syncDefaults        = "defaults"
+	syncAccountDefaults = "account_defaults"
+	syncLinks           = "links"
+)
+
+// SyncDoc is the shared copy of the synced config, stored remotely.
+type SyncDoc struct {
+	Version   int                  `json:"version"`
+	UpdatedAt time.Time            `json:"updatedAt"`
+	UpdatedBy string               `json:"updatedBy,omitempty"`
+	Entries   map[string]SyncEntry `json:"entries"`
+}
+
+// SyncEntry is one value; deletions are kept as tombstones so other machines
+// learn about them.
+type SyncEntry struct {
+	Value     json.RawMessage `json:"value,omitempty"`
+	Deleted   bool            `json:"deleted,omitempty"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+// SyncState is this machine's view after the last successful sync; it is the
+// base of the three-way merge.
+type SyncState struct {
+	LastSync time.Time                  `json:"lastSync"`
+	FileID   string                     `json:"fileId,omitempty"`
+	Base     map[string]json.RawMessage `json:"base"`
+}
+
+// SyncChange records what a merge did to one key.
+type SyncChange struct {
+	Key      string `json:"key"`
+	Action   string `json:"action"` // push, pull, conflict
+	Resolved string `json:"resolved,omitempty"`
+}
+
+func ReadSyncState() (SyncState, error) {
+	state := SyncState{Base: map[string]json.RawMessage{}}
+
+	path, err := ConfigSyncStatePath()
+	if err != nil {
+		return state, err
+	}
+
+	b, err := os.ReadFile(path) //nolint:gosec // config file path
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return state, nil
+		}
+
+		return state, fmt.Errorf("read sync state: %w", err)
+	}
+
+	if err := json.Unmarshal(b, &state); err != nil {
+		return state, fmt.Errorf("parse sync state %s: %w", path, err)
+	}
+
+	if state.Base == nil {
+		state.Base = map[string]json.RawMessage{}
+	}
+
+	return state, nil
+}
+
+func WriteSyncState(state SyncState) error {
+	path, err := ConfigSyncStatePath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("ensure state dir: %w", err)
+	}
+
+	b, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(b, '\n'), 0o600)
+}
+
+// SyncableValues flattens the shareable parts of cfg into sync keys.
+func SyncableValues(cfg File) map[string]json.RawMessage {
+	out := map[string]json.RawMessage{}
+	put := func(key string, v any) {
+		if b, err := json.Marshal(v); err == nil {
+			out[key] = b
+		}
+	}
+
+	if tz := strings.TrimSpace(cfg.DefaultTimezone); tz != "" {
+		put(syncTimezone, tz)
+	}
+
+	for k, v := range cfg.AccountAliases {
+		put(syncAccountAliases+"/"+k, v)
+	}
+
+	for k, v := range cfg.Defaults {
+		put(syncDefaults+"/"+k, v)
+	}
+
+	for account, defaults := range cfg.AccountDefaults {
+		for k, v := range defaults {
+			put(syncAccountDefaults+"/"+account+"/"+k, v)
+		}
+	}
+
+	for k, v := range cfg.Links {
+		put(syncLinks+"/"+k, v)
+	}
+
+	return out
+}
+
+// ApplySyncableValues replaces the shareable parts of cfg with values,
+// leaving machine-local settings untouched.
+func ApplySyncableValues(cfg *File, values map[string]json.RawMessage) error {
+	cfg.DefaultTimezone = ""
+	cfg.AccountAliases = nil
+	cfg.Defaults = nil
+	cfg.AccountDefaults = nil
+	cfg.Links = nil
+
+	for key, raw := range values {
+		section, rest, _ := strings.Cut(key, "/")
+
+		var err error
+
+		switch section {
+		case syncTimezone:
+			err = json.Unmarshal(raw, &cfg.DefaultTimezone)
+		case syncAccountAliases:
+			err = unmarshalInto(&cfg.AccountAliases, rest, raw)
+		case syncDefaults:
+			err = unmarshalInto(&cfg.Defaults, rest, raw)
+		case syncAccountDefaults:
+			account, name, ok := strings.Cut(rest, "/")
+			if !ok {
+				return fmt.Errorf("invalid sync key %q", key)
+			}
+
+			if cfg.AccountDefaults == nil {
+				cfg.AccountDefaults = map[string]map[string]string{}
+			}
+
+			m := cfg.AccountDefaults[account]
+			err = unmarshalInto(&m, name, raw)
+			cfg.AccountDefaults[account] = m
+		case syncLinks:
+			var link Link
+			if err = json.Unmarshal(raw, &link); err == nil {
+				if cfg.Links == nil {
+					cfg.Links = map[string]Link{}
+				}
+
+				cfg.Links[rest] = link
+			}
+		default:
+			// Written by a newer version; ignore rather than guess.
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("sync key %q: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+func unmarshalInto(m *map[string]string, key string, raw json.RawMessage) error {
+	var v string
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return err
+	}
+
+	if *m == nil {
+		*m = map[string]string{}
+	}
+
+	(*m)[key] = v
+
+	return nil
+}
+
+// MergeSync does a three-way merge of local values and the remote document
+// against the last synced base. Keys changed on only one side take that
+// side; keys changed differently on both are conflicts, resolved toward the
+// remote copy when preferRemote is set and toward local otherwise.
+func MergeSync(base, local map[string]json.RawMessage, remote map[string]SyncEntry, preferRemote bool, now time.Time) (map[string]json.RawMessage, map[string]SyncEntry, []SyncChange) {
+	merged := map[string]json.RawMessage{}
+	entries := map[string]SyncEntry{}
+
+	for k, e := range remote {
+		entries[k] = e
+	}
+
+	keys := map[string]bool{}
+	for k := range base {
+		keys[k] = true
+	}
+
+	for k := range local {
+		keys[k] = true
+	}
+
+	for k := range remote {
+		keys[k] = true
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+
+	sort.Strings(sorted)
+
+	var changes []SyncChange
+
+	for _, k := range sorted {
+		b, inBase := base[k]
+		l, inLocal := local[k]
+		r, inRemote := remote[k]
+		remoteLive := inRemote && !r.Deleted
+
+		localChanged := !sameValue(inLocal, l, inBase, b)
+		remoteChanged := !sameValue(remoteLive, r.Value, inBase, b)
+
+		takeLocal := func() {
+			if inLocal {
+				merged[k] = l
+				if !remoteLive || !bytes.Equal(r.Value, l) {
+					entries[k] = SyncEntry{Value: l, UpdatedAt: now}
+				}
+			} else if remoteLive {
+				entries[k] = SyncEntry{Deleted: true, UpdatedAt: now}
+			}
+		}
+		takeRemote := func() {
+			if remoteLive {
+				merged[k] = r.Value
+			}
+		}
+
+		switch {
+		case !localChanged && !remoteChanged:
+			takeLocal()
+		case localChanged && !remoteChanged:
+			takeLocal()
+			changes = append(changes, SyncChange{Key: k, Action: "push"})
+		case remoteChanged && !localChanged:
+			takeRemote()
+			changes = append(changes, SyncChange{Key: k, Action: "pull"})
+		case sameValue(inLocal, l, remoteLive, r.Value):
+			// Both sides made the same change.
+			takeLocal()
+		case preferRemote:
+			takeRemote()
+			changes = append(changes, SyncChange{Key: k, Action: "conflict", Resolved: "remote"})
+		default:
+			takeLocal()
+			changes = append(changes, SyncChange{Key: k, Action: "conflict", Resolved: "local"})
+		}
+	}
+
+	return merged, entries, changes
+}
+
+func sameValue(aOK bool, a json.RawMessage, bOK bool, b json.RawMessage) bool {
+	if aOK != bOK {
+		return false
+	}
+
+	return !aOK || bytes.Equal(a, b)
+}
@@ -0,0 +1,161 @@
+package config
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSyncableValuesRoundTrip(t *testing.T) {
+	cfg := File{
+		KeyringBackend:  "file",
+		DefaultTimezone: "Europe/Vienna",
+		AccountAliases:  map[string]string{"work": "me@work.example"},
+		AccountClients:  map[string]string{"me@work.example": "work"},
+		Defaults:        map[string]string{"gmail.search.max": "50"},
+		AccountDefaults: map[string]map[string]string{"me@work.example": {"calendar": "team"}},
+		Links:           map[string]Link{"q3": {URL: "https://docs.example/q3", Kind: "url"}},
+	}
+
+	values := SyncableValues(cfg)
+	if len(values) != 5 {
+		t.Fatalf("unexpected values: %v", values)
+	}
+
+	for key := range values {
+		if key == "keyring_backend" || key == "account_clients/me@work.example" {
+			t.Fatalf("machine-local key leaked: %s", key)
+		}
+	}
+
+	out := File{KeyringBackend: "keychain", AccountAliases: map[string]string{"old": "x@example.com"}}
+	if err := ApplySyncableValues(&out, values); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+
+	if out.KeyringBackend != "keychain" {
+		t.Fatalf("local keyring backend overwritten: %q", out.KeyringBackend)
+	}
+
+	if _, ok := out.AccountAliases["old"]; ok || out.AccountAliases["work"] != "me@work.example" {
+		t.Fatalf("unexpected aliases: %v", out.AccountAliases)
+	}
+
+	if out.DefaultTimezone != "Europe/Vienna" || out.Defaults["gmail.search.max"] != "50" {
+		t.Fatalf("unexpected config: %#v", out)
+	}
+
+	if out.AccountDefaults["me@work.example"]["calendar"] != "team" || out.Links["q3"].URL != "https://docs.example/q3" {
+		t.Fatalf("unexpected nested values: %#v", out)
+	}
+}
+
+func TestMergeSync(t *testing.T) {
+	raw := func(s string) json.RawMessage { return json.RawMessage(`"` + s + `"`) }
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	base := map[string]json.RawMessage{
+		"account_aliases/keep":    raw("a"),
+		"account_aliases/pushed":  raw("a"),
+		"account_aliases/pulled":  raw("a"),
+		"account_aliases/removed": raw("a"),
+		"account_aliases/clash":   raw("a"),
+	}
+	local := map[string]json.RawMessage{
+		"account_aliases/keep":   raw("a"),
+		"account_aliases/pushed": raw("b"),
+		"account_aliases/pulled": raw("a"),
+		"account_aliases/clash":  raw("local"),
+		"account_aliases/new":    raw("n"),
+	}
+	remote := map[string]SyncEntry{
+		"account_aliases/keep":    {Value: raw("a")},
+		"account_aliases/pushed":  {Value: raw("a")},
+		"account_aliases/pulled":  {Value: raw("c")},
+		"account_aliases/removed": {Value: raw("a")},
+		"account_aliases/clash":   {Value: raw("remote")},
+		"account_aliases/gone":    {Deleted: true},
+	}
+
+	merged, entries, changes := MergeSync(base, local, remote, false, now)
+
+	want := map[string]string{
+		"account_aliases/keep":   `"a"`,
+		"account_aliases/pushed": `"b"`,
+		"account_aliases/pulled": `"c"`,
+		"account_aliases/clash":  `"local"`,
+		"account_aliases/new":    `"n"`,
+	}
+	if len(merged) != len(want) {
+		t.Fatalf("unexpected merged: %v", merged)
+	}
+
+	for k, v := range want {
+		if string(merged[k]) != v {
+			t.Fatalf("merged[%s]=%s, want %s", k, merged[k], v)
+		}
+	}
+
+	if !entries["account_aliases/removed"].Deleted {
+		t.Fatalf("local deletion not pushed as tombstone: %#v", entries["account_aliases/removed"])
+	}
+
+	if string(entries["account_aliases/pushed"].Value) != `"b"` || !entries["account_aliases/pushed"].UpdatedAt.Equal(now) {
+		t.Fatalf("unexpected pushed entry: %#v", entries["account_aliases/pushed"])
+	}
+
+	actions := map[string]string{}
+	for _, c := range changes {
+		actions[c.Key] = c.Action + c.Resolved
+	}
+
+	wantActions := map[string]string{
+		"account_aliases/pushed":  "push",
+		"account_aliases/pulled":  "pull",
+		"account_aliases/removed": "push",
+		"account_aliases/clash":   "conflictlocal",
+		"account_aliases/new":     "push",
+	}
+	if len(actions) != len(wantActions) {
+		t.Fatalf("unexpected changes: %v", actions)
+	}
+
+	for k, v := range wantActions {
+		if actions[k] != v {
+			t.Fatalf("change[%s]=%q, want %q", k, actions[k], v)
+		}
+	}
+
+	merged, _, _ = MergeSync(base, local, remote, true, now)
+	if string(merged["account_aliases/clash"]) != `"remote"` {
+		t.Fatalf("prefer remote: %s", merged["account_aliases/clash"])
+	}
+}
+
+func TestSyncStateRoundTrip(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg-config"))
+
+	state, err := ReadSyncState()
+	if err != nil || len(state.Base) != 0 {
+		t.Fatalf("empty state: %v %#v", err, state)
+	}
+
+	state.FileID = "file1"
+	state.Base["default_timezone"] = json.RawMessage(`"UTC"`)
+
+	if err := WriteSyncState(state); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	got, err := ReadSyncState()
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+
+	if got.FileID != "file1" || string(got.Base["default_timezone"]) != `"UTC"` {
+		t.Fatalf("unexpected state: %#v", got)
+	}
+}
@@ -0,0 +1,52 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	at := func(s string) time.Time {
+		v, err := time.Parse("2006-01-02 15:04", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	cases := []struct {
+		spec, after, want string
+	}{
+		{"0 8 * * 1-5", "2026-10-16 09:00", "2026-10-19 08:00"}, // Friday after 8 -> Monday
+		{"0 8 * * mon-fri", "2026-10-19 07:59", "2026-10-19 08:00"},
+		{"*/15 * * * *", "2026-10-19 10:07", "2026-10-19 10:15"},
+		{"*/15 * * * *", "2026-10-19 10:50", "2026-10-19 11:00"},
+		{"30 9-17/4 * * *", "2026-10-19 13:30", "2026-10-19 17:30"},
+		{"0 0 1,15 * *", "2026-10-02 00:00", "2026-10-15 00:00"},
+		{"0 0 13 * 5", "2026-10-10 00:00", "2026-10-13 00:00"}, // 13th or Friday: Tue 13th comes first
+		{"0 12 * * 7", "2026-10-19 00:00", "2026-10-25 12:00"}, // 7 is Sunday
+		{"@monthly", "2026-12-15 00:00", "2027-01-01 00:00"},
+		{"0 0 29 feb *", "2026-03-01 00:00", "2028-02-29 00:00"},
+	}
+	for _, c := range cases {
+		s, err := Parse(c.spec)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", c.spec, err)
+		}
+		if got := s.Next(at(c.after)); !got.Equal(at(c.want)) {
+			t.Errorf("%q after %s = %s, want %s", c.spec, c.after, got.Format("2006-01-02 15:04"), c.want)
+		}
+	}
+
+	s, _ := Parse("0 0 30 2 *")
+	if got := s.Next(at("2026-01-01 00:00")); !got.IsZero() {
+		t.Fatalf("Feb 30 should never fire, got %s", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "* * * foo *"} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q): want error", spec)
+		}
+	}
+}
@@ -0,0 +1,382 @@
+// Package docindex extracts plain text from common attachment formats and
+// keeps a small local full-text index over it.
+package docindex
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"encoding/xml"
+	"errors"
+	"html"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrUnsupported is returned for formats ExtractText cannot read.
+var ErrUnsupported = errors.New("unsupported format")
+
+// maxInflated caps how much a single compressed PDF stream or zip entry may
+// expand to.
+const maxInflated = 32 << 20
+
+// ExtractText returns the readable text of a PDF, Office Open XML
+// (docx/xlsx/pptx), OpenDocument, HTML, or plain-text file. The format is
+// picked from the filename extension, then the MIME type.
+func ExtractText(filename, mimeType string, data []byte) (string, error) {
+	switch Kind(filename, mimeType) {
+	case "pdf":
+		return extractPDF(data)
+	case "docx":
+		return extractZipXML(data, func(name string) bool {
+			return name == "word/document.xml" || strings.HasPrefix(name, "word/footnotes")
+		})
+	case "xlsx":
+		return extractZipXML(data, func(name string) bool {
+			return name == "xl/sharedStrings.xml" || strings.HasPrefix(name, "xl/worksheets/sheet")
+		})
+	case "pptx":
+		return extractZipXML(data, func(name string) bool {
+			return strings.HasPrefix(name, "ppt/slides/slide") || strings.HasPrefix(name, "ppt/notesSlides/")
+		})
+	case "odf":
+		return extractZipXML(data, func(name string) bool { return name == "content.xml" })
+	case "html":
+		return stripHTML(string(data)), nil
+	case "text":
+		if !utf8.Valid(data) {
+			return strings.ToValidUTF8(string(data), " "), nil
+		}
+		return string(data), nil
+	default:
+		return "", ErrUnsupported
+	}
+}
+
+// Kind classifies a file for ExtractText; "" means unsupported.
+func Kind(filename, mimeType string) string {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".pdf":
+		return "pdf"
+	case ".docx", ".docm":
+		return "docx"
+	case ".xlsx", ".xlsm":
+		return "xlsx"
+	case ".pptx":
+		return "pptx"
+	case ".odt", ".ods", ".odp":
+		return "odf"
+	case ".html", ".htm":
+		return "html"
+	case ".txt", ".csv", ".tsv", ".md", ".json", ".xml", ".ics", ".eml", ".log":
+		return "text"
+	}
+	mimeType = strings.ToLower(strings.TrimSpace(strings.SplitN(mimeType, ";", 2)[0]))
+	switch {
+	case mimeType == "application/pdf":
+		return "pdf"
+	case strings.HasSuffix(mimeType, "wordprocessingml.document"):
+		return "docx"
+	case strings.HasSuffix(mimeType, "spreadsheetml.sheet"):
+		return "xlsx"
+	case strings.HasSuffix(mimeType, "presentationml.presentation"):
+		return "pptx"
+	case strings.HasPrefix(mimeType, "application/vnd.oasis.opendocument."):
+		return "odf"
+	case mimeType == "text/html":
+		return "html"
+	case strings.HasPrefix(mimeType, "text/"):
+		return "text"
+	}
+	return ""
+}
+
+// extractZipXML concatenates the character data of the matching XML entries,
+// breaking lines at paragraph-like elements.
+func extractZipXML(data []byte, want func(string) bool) (string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", err
+	}
+	files := make([]*zip.File, 0, len(zr.File))
+	for _, f := range zr.File {
+		if want(f.Name) {
+			files = append(files, f)
+		}
+	}
+	// slide10.xml sorts after slide2.xml.
+	sort.SliceStable(files, func(i, j int) bool {
+		if len(files[i].Name) != len(files[j].Name) {
+			return len(files[i].Name) < len(files[j].Name)
+		}
+		return files[i].Name < files[j].Name
+	})
+
+	var b strings.Builder
+	for _, f := range files {
+		rc, err := f.Open()
+		if err != nil {
+			return "", err
+		}
+		err = xmlText(&b, io.LimitReader(rc, maxInflated))
+		_ = rc.Close()
+		if err != nil {
+			return "", err
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+func xmlText(b *strings.Builder, r io.Reader) error {
+	dec := xml.NewDecoder(r)
+	dec.Strict = false
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			b.Write(t)
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "p", "tr", "row", "h", "si":
+				b.WriteString("\n")
+			case "t", "v", "c", "tc", "span", "tab":
+				b.WriteString(" ")
+			}
+		}
+	}
+}
+
+var (
+	htmlDropRe = regexp.MustCompile(`(?is)<(script|style)[^>]*>.*?</(script|style)>`)
+	htmlTagRe  = regexp.MustCompile(`(?s)<[^>]*>`)
+)
+
+func stripHTML(s string) string {
+	s = htmlDropRe.ReplaceAllString(s, " ")
+	s = htmlTagRe.ReplaceAllString(s, " ")
+	return html.UnescapeString(s)
+}
+
+var pdfStreamRe = regexp.MustCompile(`(?s)<<(.*?)>>\s*stream\r?\n`)
+
+// extractPDF pulls the text-showing operators (Tj, TJ, ', ") out of every
+// content stream. It is best effort: fonts with custom encodings or scanned
+// pages without a text layer yield little or nothing.
+func extractPDF(data []byte) (string, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("%PDF")) {
+		return "", errors.New("not a PDF file")
+	}
+	var b strings.Builder
+	for _, loc := range pdfStreamRe.FindAllSubmatchIndex(data, -1) {
+		dict := string(data[loc[2]:loc[3]])
+		start := loc[1]
+		end := bytes.Index(data[start:], []byte("endstream"))
+		if end < 0 {
+			break
+		}
+		raw := data[start : start+end]
+		if strings.Contains(dict, "/Subtype/Image") || strings.Contains(dict, "/Subtype /Image") {
+			continue
+		}
+		content := raw
+		if strings.Contains(dict, "FlateDecode") {
+			zr, err := zlib.NewReader(bytes.NewReader(raw))
+			if err != nil {
+				continue
+			}
+			content, err = io.ReadAll(io.LimitReader(zr, maxInflated))
+			_ = zr.Close()
+			if err != nil && len(content) == 0 {
+				continue
+			}
+		} else if strings.Contains(dict, "/Filter") {
+			continue
+		}
+		pdfContentText(&b, content)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// pdfContentText scans a content stream, writing string operands inside
+// BT/ET text objects. Large negative TJ kerning and Td/T*/TD moves become
+// whitespace.
+func pdfContentText(b *strings.Builder, content []byte) {
+	inText := false
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case c == '(' && inText:
+			s, next := pdfLiteral(content, i)
+			b.WriteString(s)
+			i = next
+		case c == '<' && inText && i+1 < len(content) && content[i+1] != '<':
+			s, next := pdfHex(content, i)
+			b.WriteString(s)
+			i = next
+		case c == '%':
+			for i < len(content) && content[i] != '\n' && content[i] != '\r' {
+				i++
+			}
+		case c == '-' && inText && i+1 < len(content) && content[i+1] >= '0' && content[i+1] <= '9':
+			j := i + 1
+			for j < len(content) && (content[j] >= '0' && content[j] <= '9' || content[j] == '.') {
+				j++
+			}
+			// Inside TJ arrays, a big negative adjustment is a word gap.
+			if j-i > 3 {
+				b.WriteString(" ")
+			}
+			i = j - 1
+		case isPDFOpStart(content, i):
+			op := pdfOperator(content, i)
+			switch op {
+			case "BT":
+				inText = true
+			case "ET":
+				inText = false
+				b.WriteString("\n")
+			case "Td", "TD", "T*", "'", `"`:
+				b.WriteString("\n")
+			case "Tj", "TJ":
+				b.WriteString(" ")
+			}
+			i += len(op) - 1
+		}
+	}
+}
+
+func isPDFOpStart(content []byte, i int) bool {
+	c := content[i]
+	if !(c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c == '\'' || c == '"') {
+		return false
+	}
+	return i == 0 || isPDFDelim(content[i-1])
+}
+
+func pdfOperator(content []byte, i int) string {
+	j := i + 1
+	for j < len(content) && !isPDFDelim(content[j]) {
+		j++
+	}
+	return string(content[i:j])
+}
+
+func isPDFDelim(c byte) bool {
+	switch c {
+	case ' ', '\t', '\r', '\n', '\f', 0, '(', ')', '<', '>', '[', ']', '/', '{', '}', '%':
+		return true
+	}
+	return false
+}
+
+// pdfLiteral decodes "( ... )" starting at content[i] and returns the text and
+// the index of the closing parenthesis.
+func pdfLiteral(content []byte, i int) (string, int) {
+	var out []byte
+	depth := 0
+	for j := i; j < len(content); j++ {
+		c := content[j]
+		switch c {
+		case '\\':
+			if j+1 >= len(content) {
+				return string(out), j
+			}
+			j++
+			switch e := content[j]; e {
+			case 'n', 'r':
+				out = append(out, '\n')
+			case 't':
+				out = append(out, ' ')
+			case 'b', 'f':
+			case '\r', '\n':
+				// Line continuation.
+			default:
+				if e >= '0' && e <= '7' {
+					v := 0
+					k := 0
+					for ; k < 3 && j+k < len(content) && content[j+k] >= '0' && content[j+k] <= '7'; k++ {
+						v = v*8 + int(content[j+k]-'0')
+					}
+					j += k - 1
+					out = append(out, pdfByte(byte(v))...)
+				} else {
+					out = append(out, e)
+				}
+			}
+		case '(':
+			depth++
+			if depth > 1 {
+				out = append(out, c)
+			}
+		case ')':
+			depth--
+			if depth == 0 {
+				return string(out), j
+			}
+			out = append(out, c)
+		default:
+			out = append(out, pdfByte(c)...)
+		}
+	}
+	return string(out), len(content) - 1
+}
+
+// pdfHex decodes "<...>" hex strings. Two-byte glyph IDs cannot be mapped
+// without the font's CMap, so only printable single bytes are kept.
+func pdfHex(content []byte, i int) (string, int) {
+	end := bytes.IndexByte(content[i:], '>')
+	if end < 0 {
+		return "", len(content) - 1
+	}
+	hexDigits := make([]byte, 0, end)
+	for _, c := range content[i+1 : i+end] {
+		if c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F' {
+			hexDigits = append(hexDigits, c)
+		}
+	}
+	if len(hexDigits)%2 == 1 {
+		hexDigits = append(hexDigits, '0')
+	}
+	var out []byte
+	for k := 0; k+1 < len(hexDigits); k += 2 {
+		v := hexVal(hexDigits[k])<<4 | hexVal(hexDigits[k+1])
+		if v >= 0x20 && v < 0x7f {
+			out = append(out, v)
+		}
+	}
+	return string(out), i + end
+}
+
+func hexVal(c byte) byte {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0'
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
+
+// pdfByte maps a WinAnsi/Latin-1 byte to UTF-8; control bytes become spaces.
+func pdfByte(c byte) []byte {
+	switch {
+	case c < 0x20:
+		return []byte{' '}
+	case c < 0x80:
+		return []byte{c}
+	default:
+		return []byte(string(rune(c)))
+	}
+}
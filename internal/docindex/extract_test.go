@@ -0,0 +1,63 @@
+package docindex
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestExtractTextPDF(t *testing.T) {
+	var z bytes.Buffer
+	zw := zlib.NewWriter(&z)
+	_, _ = zw.Write([]byte("BT /F1 12 Tf 72 712 Td (Invoice \\(final\\)) Tj 0 -14 Td [(Total)-300(4711)] TJ ET"))
+	_ = zw.Close()
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n1 0 obj\n<< /Type /Catalog >>\nendobj\n")
+	fmt.Fprintf(&pdf, "4 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n", z.Len())
+	pdf.Write(z.Bytes())
+	pdf.WriteString("\nendstream\nendobj\n")
+	pdf.WriteString("5 0 obj\n<< /Length 30 >>\nstream\nBT (Plain caf\\351) Tj ET\nendstream\nendobj\n%%EOF\n")
+
+	text, err := ExtractText("scan.pdf", "application/octet-stream", pdf.Bytes())
+	if err != nil {
+		t.Fatalf("ExtractText: %v", err)
+	}
+	for _, want := range []string{"Invoice (final)", "Total 4711", "Plain café"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("missing %q in %q", want, text)
+		}
+	}
+	if _, err := ExtractText("x.pdf", "", []byte("not a pdf")); err == nil {
+		t.Fatalf("expected error for non-PDF data")
+	}
+}
+
+func TestExtractTextOffice(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create("word/document.xml")
+	_, _ = w.Write([]byte(`<w:document xmlns:w="x"><w:body><w:p><w:r><w:t>Quarterly</w:t></w:r><w:r><w:t>report</w:t></w:r></w:p><w:p><w:r><w:t>Second line</w:t></w:r></w:p></w:body></w:document>`))
+	w, _ = zw.Create("word/styles.xml")
+	_, _ = w.Write([]byte(`<styles>ignored</styles>`))
+	_ = zw.Close()
+
+	text, err := ExtractText("", "application/vnd.openxmlformats-officedocument.wordprocessingml.document", buf.Bytes())
+	if err != nil {
+		t.Fatalf("ExtractText: %v", err)
+	}
+	if text != "Quarterly report \nSecond line" {
+		t.Fatalf("unexpected text %q", text)
+	}
+
+	if _, err := ExtractText("photo.jpg", "image/jpeg", nil); err != ErrUnsupported {
+		t.Fatalf("expected ErrUnsupported, got %v", err)
+	}
+	html, _ := ExtractText("a.html", "", []byte("<style>p{}</style><p>Hello &amp; bye</p>"))
+	if strings.TrimSpace(html) != "Hello & bye" {
+		t.Fatalf("unexpected html text %q", html)
+	}
+}
@@ -0,0 +1,233 @@
+package docindex
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// previewRunes is how much extracted text is kept per document for search
+// result snippets.
+const previewRunes = 4000
+
+// Doc is one indexed file.
+type Doc struct {
+	ID           string    `json:"id"`
+	MessageID    string    `json:"messageId,omitempty"`
+	AttachmentID string    `json:"attachmentId,omitempty"`
+	Filename     string    `json:"filename"`
+	MimeType     string    `json:"mimeType,omitempty"`
+	Path         string    `json:"path"`
+	Subject      string    `json:"subject,omitempty"`
+	From         string    `json:"from,omitempty"`
+	Date         string    `json:"date,omitempty"`
+	Chars        int       `json:"chars"`
+	Preview      string    `json:"preview,omitempty"`
+	IndexedAt    time.Time `json:"indexedAt"`
+}
+
+// Hit is a search result.
+type Hit struct {
+	Doc
+	Score   int    `json:"score"`
+	Snippet string `json:"snippet,omitempty"`
+}
+
+// Index maps terms to the documents containing them, with per-document
+// counts. It is stored as a single JSON file.
+type Index struct {
+	path  string
+	Docs  map[string]*Doc           `json:"docs"`
+	Terms map[string]map[string]int `json:"terms"`
+}
+
+// Open loads the index at path; a missing file is an empty index.
+func Open(path string) (*Index, error) {
+	idx := &Index{path: path, Docs: map[string]*Doc{}, Terms: map[string]map[string]int{}}
+	data, err := os.ReadFile(path) //nolint:gosec // caller-chosen state path
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return idx, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, idx); err != nil {
+		return nil, fmt.Errorf("parse index %s: %w", path, err)
+	}
+	if idx.Docs == nil {
+		idx.Docs = map[string]*Doc{}
+	}
+	if idx.Terms == nil {
+		idx.Terms = map[string]map[string]int{}
+	}
+	return idx, nil
+}
+
+// Save writes the index atomically.
+func (x *Index) Save() error {
+	if err := os.MkdirAll(filepath.Dir(x.path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(x)
+	if err != nil {
+		return err
+	}
+	tmp := x.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, x.path)
+}
+
+// Has reports whether a document is already indexed.
+func (x *Index) Has(id string) bool {
+	_, ok := x.Docs[id]
+	return ok
+}
+
+// Len returns the number of indexed documents.
+func (x *Index) Len() int {
+	return len(x.Docs)
+}
+
+// Add indexes text under doc, replacing any earlier version of doc.ID.
+func (x *Index) Add(doc Doc, text string) {
+	x.Remove(doc.ID)
+	counts := map[string]int{}
+	for _, term := range Tokenize(text) {
+		counts[term]++
+	}
+	for term, n := range counts {
+		postings := x.Terms[term]
+		if postings == nil {
+			postings = map[string]int{}
+			x.Terms[term] = postings
+		}
+		postings[doc.ID] = n
+	}
+	doc.Chars = len([]rune(text))
+	doc.Preview = strings.Join(strings.Fields(truncate(text, previewRunes)), " ")
+	x.Docs[doc.ID] = &doc
+}
+
+// Remove drops a document and its postings.
+func (x *Index) Remove(id string) {
+	if _, ok := x.Docs[id]; !ok {
+		return
+	}
+	delete(x.Docs, id)
+	for term, postings := range x.Terms {
+		delete(postings, id)
+		if len(postings) == 0 {
+			delete(x.Terms, term)
+		}
+	}
+}
+
+// Search returns documents containing every query term, best matches first.
+// A trailing "*" makes a term a prefix match.
+func (x *Index) Search(query string, limit int) []Hit {
+	var matched map[string]int
+	for _, raw := range strings.Fields(query) {
+		prefix := strings.HasSuffix(raw, "*")
+		terms := Tokenize(strings.TrimSuffix(raw, "*"))
+		for i, term := range terms {
+			scores := map[string]int{}
+			if prefix && i == len(terms)-1 {
+				for t, postings := range x.Terms {
+					if strings.HasPrefix(t, term) {
+						for id, n := range postings {
+							scores[id] += n
+						}
+					}
+				}
+			} else {
+				for id, n := range x.Terms[term] {
+					scores[id] = n
+				}
+			}
+			if matched == nil {
+				matched = scores
+				continue
+			}
+			for id := range matched {
+				if n, ok := scores[id]; ok {
+					matched[id] += n
+				} else {
+					delete(matched, id)
+				}
+			}
+		}
+	}
+
+	hits := make([]Hit, 0, len(matched))
+	for id, score := range matched {
+		doc := x.Docs[id]
+		if doc == nil {
+			continue
+		}
+		hits = append(hits, Hit{Doc: *doc, Score: score, Snippet: snippet(doc.Preview, query)})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// Tokenize lowercases text and splits it into letter/digit runs.
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// snippet returns a window of the preview around the first query term.
+func snippet(preview, query string) string {
+	if preview == "" {
+		return ""
+	}
+	lower := strings.ToLower(preview)
+	at := -1
+	for _, term := range Tokenize(strings.ReplaceAll(query, "*", "")) {
+		if i := strings.Index(lower, term); i >= 0 && (at < 0 || i < at) {
+			at = i
+		}
+	}
+	if at < 0 {
+		return truncate(preview, 120)
+	}
+	// Lowercasing can change byte lengths outside ASCII.
+	at = min(at, len(preview))
+	runes := []rune(preview)
+	pos := len([]rune(preview[:at]))
+	start := max(0, pos-40)
+	end := min(len(runes), pos+80)
+	out := string(runes[start:end])
+	if start > 0 {
+		out = "..." + out
+	}
+	if end < len(runes) {
+		out += "..."
+	}
+	return out
+}
+
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
@@ -0,0 +1,46 @@
+package docindex
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestIndexSearch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.json")
+	idx, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	idx.Add(Doc{ID: "m1/a", MessageID: "m1", Filename: "invoice.pdf"}, "Invoice 4711 for consulting. Invoice total due.")
+	idx.Add(Doc{ID: "m2/a", MessageID: "m2", Filename: "offer.docx"}, "Offer 4711, no invoices yet")
+	idx.Add(Doc{ID: "m3/a", MessageID: "m3", Filename: "notes.txt"}, "Unrelated")
+	if err := idx.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	idx, err = Open(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	hits := idx.Search("invoice 4711", 10)
+	if len(hits) != 1 || hits[0].MessageID != "m1" || hits[0].Score != 3 {
+		t.Fatalf("unexpected hits: %#v", hits)
+	}
+	if hits[0].Snippet == "" {
+		t.Fatalf("expected snippet")
+	}
+	if hits := idx.Search("invoice* 4711", 10); len(hits) != 2 || hits[0].MessageID != "m1" {
+		t.Fatalf("unexpected prefix hits: %#v", hits)
+	}
+	if hits := idx.Search("missing", 10); len(hits) != 0 {
+		t.Fatalf("expected no hits: %#v", hits)
+	}
+
+	idx.Remove("m1/a")
+	if idx.Has("m1/a") || len(idx.Terms["consulting"]) != 0 {
+		t.Fatalf("remove left postings behind")
+	}
+	if hits := idx.Search("4711", 10); len(hits) != 1 || hits[0].MessageID != "m2" {
+		t.Fatalf("unexpected hits after remove: %#v", hits)
+	}
+}
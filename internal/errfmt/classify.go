@@ -0,0 +1,157 @@
+package errfmt
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/99designs/keyring"
+	"github.com/alecthomas/kong"
+	ggoogleapi "google.golang.org/api/googleapi"
+
+	"github.com/steipete/gogcli/internal/config"
+	gogapi "github.com/steipete/gogcli/internal/googleapi"
+)
+
+// Stable error codes emitted in structured (--json) error output.
+const (
+	CodeUsage            = "USAGE"
+	CodeAuth             = "AUTH"
+	CodePermissionDenied = "PERMISSION_DENIED"
+	CodeNotFound         = "NOT_FOUND"
+	CodeRateLimit        = "RATE_LIMIT"
+	CodeQuota            = "QUOTA_EXCEEDED"
+	CodeUnavailable      = "UNAVAILABLE"
+	CodeInvalidArgument  = "INVALID_ARGUMENT"
+	CodeConflict         = "CONFLICT"
+	CodeAPI              = "API_ERROR"
+	CodeInternal         = "INTERNAL"
+	CodeInterrupted      = "INTERRUPTED"
+)
+
+// Process exit codes. Anything not listed exits with ExitGeneric.
+const (
+	ExitGeneric  = 1
+	ExitUsage    = 2
+	ExitAuth     = 3
+	ExitNotFound = 4
+	ExitQuota    = 5
+	// ExitInterrupted follows the shell convention for SIGINT (128+2).
+	ExitInterrupted = 130
+)
+
+// ErrInterrupted is the cancellation cause when the user stops a command with
+// Ctrl-C (or SIGTERM); commands flush partial results before returning it.
+var ErrInterrupted = errors.New("interrupted")
+
+// ErrNotFound marks a remote lookup that matched nothing (e.g. no message
+// with a given Message-ID); wrap it with %w to exit with ExitNotFound.
+// Missing local files (os.ErrNotExist) are not remote not-found errors.
+var ErrNotFound = errors.New("not found")
+
+// Classification is the machine-readable view of an error.
+type Classification struct {
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+	HTTPStatus int    `json:"httpStatus,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+	Retryable  bool   `json:"retryable"`
+	ExitCode   int    `json:"exitCode"`
+}
+
+// Classify maps err onto the error-code taxonomy. Message is the same text
+// Format would print.
+func Classify(err error) Classification {
+	if err == nil {
+		return Classification{}
+	}
+	c := classify(err)
+	c.Message = Format(err)
+	return c
+}
+
+func classify(err error) Classification {
+	if errors.Is(err, ErrInterrupted) {
+		return Classification{Code: CodeInterrupted, ExitCode: ExitInterrupted}
+	}
+
+	var parseErr *kong.ParseError
+	if errors.As(err, &parseErr) {
+		return Classification{Code: CodeUsage, ExitCode: ExitUsage}
+	}
+
+	var authErr *gogapi.AuthRequiredError
+	var credErr *config.CredentialsMissingError
+	if errors.As(err, &authErr) || errors.As(err, &credErr) || errors.Is(err, keyring.ErrKeyNotFound) {
+		return Classification{Code: CodeAuth, ExitCode: ExitAuth}
+	}
+
+	// Prefer the raw Google API error when present: it carries status and reason.
+	var gerr *ggoogleapi.Error
+	if errors.As(err, &gerr) {
+		return classifyGoogleAPIError(gerr)
+	}
+
+	switch {
+	case gogapi.IsRateLimitError(err):
+		return Classification{Code: CodeRateLimit, HTTPStatus: http.StatusTooManyRequests, Retryable: true, ExitCode: ExitQuota}
+	case gogapi.IsQuotaExceededError(err):
+		return Classification{Code: CodeQuota, ExitCode: ExitQuota}
+	case gogapi.IsCircuitBreakerError(err):
+		return Classification{Code: CodeUnavailable, Retryable: true, ExitCode: ExitGeneric}
+	case gogapi.IsNotFoundError(err), errors.Is(err, ErrNotFound):
+		return Classification{Code: CodeNotFound, ExitCode: ExitNotFound}
+	case gogapi.IsPermissionDeniedError(err):
+		return Classification{Code: CodePermissionDenied, ExitCode: ExitGeneric}
+	}
+
+	return Classification{Code: CodeInternal, ExitCode: ExitGeneric}
+}
+
+func classifyGoogleAPIError(gerr *ggoogleapi.Error) Classification {
+	c := Classification{HTTPStatus: gerr.Code, Code: CodeAPI, ExitCode: ExitGeneric}
+	if len(gerr.Errors) > 0 {
+		c.Reason = gerr.Errors[0].Reason
+	}
+
+	switch c.Reason {
+	case "rateLimitExceeded", "userRateLimitExceeded":
+		c.Code, c.Retryable, c.ExitCode = CodeRateLimit, true, ExitQuota
+		return c
+	case "quotaExceeded", "dailyLimitExceeded", "limitExceeded":
+		c.Code, c.ExitCode = CodeQuota, ExitQuota
+		return c
+	}
+
+	switch {
+	case gerr.Code == http.StatusTooManyRequests:
+		c.Code, c.Retryable, c.ExitCode = CodeRateLimit, true, ExitQuota
+	case gerr.Code == http.StatusUnauthorized:
+		c.Code, c.ExitCode = CodeAuth, ExitAuth
+	case gerr.Code == http.StatusForbidden && forbiddenByAuth(c.Reason):
+		c.Code, c.ExitCode = CodeAuth, ExitAuth
+	case gerr.Code == http.StatusForbidden:
+		// Sharing rules, domain policy, or a disabled API: re-authenticating
+		// does not help, so this is not an auth failure.
+		c.Code = CodePermissionDenied
+	case gerr.Code == http.StatusNotFound || gerr.Code == http.StatusGone:
+		c.Code, c.ExitCode = CodeNotFound, ExitNotFound
+	case gerr.Code == http.StatusConflict || gerr.Code == http.StatusPreconditionFailed:
+		c.Code = CodeConflict
+	case gerr.Code == http.StatusBadRequest:
+		c.Code = CodeInvalidArgument
+	case gerr.Code >= 500:
+		c.Code, c.Retryable = CodeUnavailable, true
+	}
+	return c
+}
+
+// forbiddenByAuth reports whether a 403 reason means the token itself falls
+// short (missing scopes, revoked grant), which a re-login fixes. Rate-limit
+// and quota reasons are handled before this.
+func forbiddenByAuth(reason string) bool {
+	switch reason {
+	case "insufficientPermissions", "authError", "ACCESS_TOKEN_SCOPE_INSUFFICIENT":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package errfmt
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/99designs/keyring"
+	ggoogleapi "google.golang.org/api/googleapi"
+
+	gogapi "github.com/steipete/gogcli/internal/googleapi"
+)
+
+func TestClassify(t *testing.T) {
+	cases := []struct {
+		name      string
+		err       error
+		code      string
+		exit      int
+		status    int
+		retryable bool
+	}{
+		{"auth", &gogapi.AuthRequiredError{Service: "gmail", Email: "a@b.com"}, CodeAuth, ExitAuth, 0, false},
+		{"keyring", fmt.Errorf("read token: %w", keyring.ErrKeyNotFound), CodeAuth, ExitAuth, 0, false},
+		{"429", &ggoogleapi.Error{Code: http.StatusTooManyRequests}, CodeRateLimit, ExitQuota, 429, true},
+		{"403 rate", &ggoogleapi.Error{Code: http.StatusForbidden, Errors: []ggoogleapi.ErrorItem{{Reason: "userRateLimitExceeded"}}}, CodeRateLimit, ExitQuota, 403, true},
+		{"403 quota", &ggoogleapi.Error{Code: http.StatusForbidden, Errors: []ggoogleapi.ErrorItem{{Reason: "dailyLimitExceeded"}}}, CodeQuota, ExitQuota, 403, false},
+		{"403 scopes", &ggoogleapi.Error{Code: http.StatusForbidden, Errors: []ggoogleapi.ErrorItem{{Reason: "insufficientPermissions"}}}, CodeAuth, ExitAuth, 403, false},
+		{"403 policy", &ggoogleapi.Error{Code: http.StatusForbidden, Errors: []ggoogleapi.ErrorItem{{Reason: "domainPolicy"}}}, CodePermissionDenied, ExitGeneric, 403, false},
+		{"403 sharing", &ggoogleapi.Error{Code: http.StatusForbidden, Errors: []ggoogleapi.ErrorItem{{Reason: "forbidden"}}}, CodePermissionDenied, ExitGeneric, 403, false},
+		{"401", &ggoogleapi.Error{Code: http.StatusUnauthorized}, CodeAuth, ExitAuth, 401, false},
+		{"404 wrapped", fmt.Errorf("get: %w", &ggoogleapi.Error{Code: http.StatusNotFound}), CodeNotFound, ExitNotFound, 404, false},
+		{"400", &ggoogleapi.Error{Code: http.StatusBadRequest}, CodeInvalidArgument, ExitGeneric, 400, false},
+		{"503", &ggoogleapi.Error{Code: http.StatusServiceUnavailable}, CodeUnavailable, ExitGeneric, 503, true},
+		{"circuit", &gogapi.CircuitBreakerError{}, CodeUnavailable, ExitGeneric, 0, true},
+		{"rate limit retries", &gogapi.RateLimitError{Retries: 3}, CodeRateLimit, ExitQuota, 429, true},
+		{"lookup", fmt.Errorf("no message with Message-ID <x@y>: %w", ErrNotFound), CodeNotFound, ExitNotFound, 0, false},
+		{"local file", fmt.Errorf("open: %w", os.ErrNotExist), CodeInternal, ExitGeneric, 0, false},
+		{"other", errNope, CodeInternal, ExitGeneric, 0, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Classify(tc.err)
+			if got.Code != tc.code || got.ExitCode != tc.exit || got.HTTPStatus != tc.status || got.Retryable != tc.retryable {
+				t.Fatalf("Classify(%v)=%+v", tc.err, got)
+			}
+			if got.Message != Format(tc.err) {
+				t.Fatalf("message %q != Format %q", got.Message, Format(tc.err))
+			}
+		})
+	}
+
+	if got := Classify(nil); got.Code != "" || got.ExitCode != 0 {
+		t.Fatalf("Classify(nil)=%+v", got)
+	}
+}
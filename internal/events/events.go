@@ -0,0 +1,247 @@
+// Package events appends daemon activity to an NDJSON log that
+// `gog events tail` can follow.
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+)
+
+// Event types written by gog.
+const (
+	TypeGmailMessageReceived  = "gmail.message.received"
+	TypeGmailHookDelivered    = "gmail.hook.delivered"
+	TypeGmailHookFailed       = "gmail.hook.failed"
+	TypeGmailMailboxRequest   = "gmail.mailbox.request"
+	TypeJobFinished           = "job.finished"
+	TypeCalendarDeclined      = "calendar.event.declined"
+	TypeCalendarEventStarting = "calendar.event.starting"
+	TypeFeedRefreshed         = "feed.refreshed"
+	TypeMutationRecorded      = "mutation.recorded"
+	TypeQuotaThreshold        = "quota.threshold.crossed"
+)
+
+// EnvFile overrides the log path; "off" disables emitting.
+const EnvFile = "GOG_EVENTS_FILE"
+
+// maxBytes is the size at which the log is rotated to "<path>.1".
+var maxBytes int64 = 10 << 20
+
+var pollInterval = 250 * time.Millisecond
+
+type Event struct {
+	Time    string         `json:"time"`
+	Type    string         `json:"type"`
+	Account string         `json:"account,omitempty"`
+	Data    map[string]any `json:"data,omitempty"`
+}
+
+// Path returns the event log location, or "" when events are disabled.
+func Path() (string, error) {
+	if v := strings.TrimSpace(os.Getenv(EnvFile)); v != "" {
+		if strings.EqualFold(v, "off") {
+			return "", nil
+		}
+		return config.ExpandPath(v)
+	}
+	return config.EventsPath()
+}
+
+var mu sync.Mutex
+
+// Emit appends one event. Failures are returned for logging but should never
+// stop the daemon that emits them.
+func Emit(typ, account string, data map[string]any) error {
+	path, err := Path()
+	if err != nil || path == "" {
+		return err
+	}
+	line, err := json.Marshal(Event{
+		Time:    time.Now().UTC().Format(time.RFC3339Nano),
+		Type:    typ,
+		Account: account,
+		Data:    data,
+	})
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	mu.Lock()
+	defer mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	if st, statErr := os.Stat(path); statErr == nil && st.Size()+int64(len(line)) > maxBytes {
+		_ = os.Rename(path, path+".1")
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600) //nolint:gosec // state dir or user-chosen path
+	if err != nil {
+		return err
+	}
+	// One write per line keeps concurrent writers from interleaving.
+	if _, err := f.Write(line); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Matches reports whether typ is selected by any of the filters. A filter
+// matches exactly or as a dotted prefix ("gmail" matches "gmail.hook.failed").
+func Matches(typ string, filters []string) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	for _, f := range filters {
+		f = strings.TrimSuffix(strings.TrimSpace(f), ".*")
+		if typ == f || strings.HasPrefix(typ, f+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Tail calls fn for the last n events matching filters and, when follow is
+// set, for every matching event appended afterwards until ctx is done.
+// Rotation and truncation restart reading from the top of the new file.
+func Tail(ctx context.Context, path string, n int, follow bool, filters []string, fn func(line []byte) error) error {
+	keep := func(line []byte) bool {
+		if len(filters) == 0 {
+			return true
+		}
+		var ev Event
+		return json.Unmarshal(line, &ev) == nil && Matches(ev.Type, filters)
+	}
+	f, offset, err := openAtLastLines(path, n, keep, fn)
+	if err != nil {
+		return err
+	}
+	if !follow {
+		if f != nil {
+			_ = f.Close()
+		}
+		return nil
+	}
+	defer func() {
+		if f != nil {
+			_ = f.Close()
+		}
+	}()
+
+	var partial []byte
+	buf := make([]byte, 32*1024)
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		if f == nil {
+			if f, err = os.Open(path); err != nil { //nolint:gosec // event log path
+				if !errors.Is(err, os.ErrNotExist) {
+					return err
+				}
+				f = nil
+			}
+			offset, partial = 0, nil
+		}
+		if f != nil {
+			for {
+				m, readErr := f.Read(buf)
+				offset += int64(m)
+				partial = append(partial, buf[:m]...)
+				for {
+					i := bytes.IndexByte(partial, '\n')
+					if i < 0 {
+						break
+					}
+					if line := bytes.TrimSpace(partial[:i]); len(line) > 0 && keep(line) {
+						if err := fn(line); err != nil {
+							return err
+						}
+					}
+					partial = partial[i+1:]
+				}
+				if readErr == io.EOF || m == 0 {
+					break
+				}
+				if readErr != nil {
+					return readErr
+				}
+			}
+			// Drain the old file before switching to a rotated one.
+			if rotated(f, path, offset) {
+				_ = f.Close()
+				f = nil
+				continue
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// rotated reports whether path now names a different or shorter file than f.
+func rotated(f *os.File, path string, offset int64) bool {
+	cur, err := os.Stat(path)
+	if err != nil {
+		return errors.Is(err, os.ErrNotExist)
+	}
+	open, err := f.Stat()
+	if err != nil {
+		return true
+	}
+	return !os.SameFile(cur, open) || cur.Size() < offset
+}
+
+func openAtLastLines(path string, n int, keep func([]byte) bool, fn func([]byte) error) (*os.File, int64, error) {
+	f, err := os.Open(path) //nolint:gosec // event log path
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, 0, nil
+		}
+		return nil, 0, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, 0, fmt.Errorf("read event log: %w", err)
+	}
+	// A trailing line without newline is still being written; the follow
+	// loop picks it up once complete.
+	complete := data[:bytes.LastIndexByte(data, '\n')+1]
+	offset := int64(len(complete))
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		_ = f.Close()
+		return nil, 0, err
+	}
+
+	var lines [][]byte
+	for _, line := range bytes.Split(complete, []byte("\n")) {
+		if line = bytes.TrimSpace(line); len(line) > 0 && keep(line) {
+			lines = append(lines, line)
+		}
+	}
+	if n < len(lines) {
+		lines = lines[len(lines)-max(n, 0):]
+	}
+	for _, line := range lines {
+		if err := fn(line); err != nil {
+			_ = f.Close()
+			return nil, 0, err
+		}
+	}
+	return f, offset, nil
+}
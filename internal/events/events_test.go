@@ -0,0 +1,134 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMatches(t *testing.T) {
+	if !Matches("gmail.hook.failed", nil) {
+		t.Fatal("no filters should match everything")
+	}
+	if !Matches("gmail.hook.failed", []string{"gmail"}) || !Matches("gmail.hook.failed", []string{"gmail.*"}) {
+		t.Fatal("prefix should match")
+	}
+	if Matches("gmailx.hook", []string{"gmail"}) || Matches("job.finished", []string{"gmail", "job.fin"}) {
+		t.Fatal("partial segment should not match")
+	}
+}
+
+func TestEmitAndTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	t.Setenv(EnvFile, path)
+
+	for i, typ := range []string{TypeGmailMessageReceived, TypeJobFinished, TypeGmailMessageReceived} {
+		if err := Emit(typ, "a@b.com", map[string]any{"n": i}); err != nil {
+			t.Fatalf("Emit: %v", err)
+		}
+	}
+
+	var got []Event
+	collect := func(line []byte) error {
+		var ev Event
+		if err := json.Unmarshal(line, &ev); err != nil {
+			return err
+		}
+		got = append(got, ev)
+		return nil
+	}
+	if err := Tail(context.Background(), path, 10, false, []string{"gmail"}, collect); err != nil {
+		t.Fatalf("Tail: %v", err)
+	}
+	if len(got) != 2 || got[0].Account != "a@b.com" || got[1].Data["n"] != float64(2) {
+		t.Fatalf("unexpected events: %#v", got)
+	}
+
+	got = nil
+	if err := Tail(context.Background(), path, 1, false, nil, collect); err != nil {
+		t.Fatalf("Tail: %v", err)
+	}
+	if len(got) != 1 || got[0].Type != TypeGmailMessageReceived {
+		t.Fatalf("unexpected last event: %#v", got)
+	}
+}
+
+func TestTailFollowsAppendsAndRotation(t *testing.T) {
+	origPoll, origMax := pollInterval, maxBytes
+	pollInterval = 5 * time.Millisecond
+	t.Cleanup(func() { pollInterval, maxBytes = origPoll, origMax })
+
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	t.Setenv(EnvFile, path)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var (
+		mu    sync.Mutex
+		types []string
+	)
+	done := make(chan error, 1)
+	go func() {
+		done <- Tail(ctx, path, 10, true, nil, func(line []byte) error {
+			var ev Event
+			_ = json.Unmarshal(line, &ev)
+			mu.Lock()
+			types = append(types, ev.Type)
+			mu.Unlock()
+			return nil
+		})
+	}()
+	waitFor := func(n int) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			mu.Lock()
+			got := len(types)
+			mu.Unlock()
+			if got >= n {
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		t.Fatalf("timed out waiting for %d events, got %v", n, types)
+	}
+
+	// The log may not exist yet when tail starts; it waits for it either way.
+	if err := Emit("one", "", nil); err != nil {
+		t.Fatalf("Emit: %v", err)
+	}
+	waitFor(1)
+
+	// Force a rotation on the next write.
+	maxBytes = 1
+	if err := Emit("two", "", nil); err != nil {
+		t.Fatalf("Emit: %v", err)
+	}
+	waitFor(2)
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Fatalf("expected rotated log: %v", err)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Tail: %v", err)
+	}
+	if strings.Join(types, ",") != "one,two" {
+		t.Fatalf("unexpected events: %v", types)
+	}
+}
+
+func TestPathDisabled(t *testing.T) {
+	t.Setenv(EnvFile, "off")
+	if p, err := Path(); err != nil || p != "" {
+		t.Fatalf("expected disabled, got %q %v", p, err)
+	}
+	if err := Emit("x", "", nil); err != nil {
+		t.Fatalf("Emit while disabled: %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package googleapi
+
+import (
+	"context"
+	"fmt"
+
+	admin "google.golang.org/api/admin/directory/v1"
+	reports "google.golang.org/api/admin/reports/v1"
+
+	"github.com/steipete/gogcli/internal/googleauth"
+)
+
+const scopeReportsAuditRO = "https://www.googleapis.com/auth/admin.reports.audit.readonly"
+
+// NewAdminDirectory creates an Admin SDK Directory service for user and org unit management.
+// The account must be a Workspace administrator (or a service account with domain-wide delegation).
+func NewAdminDirectory(ctx context.Context, email string) (*admin.Service, error) {
+	if opts, err := optionsForAccount(ctx, googleauth.ServiceAdmin, email); err != nil {
+		return nil, fmt.Errorf("admin options: %w", err)
+	} else if svc, err := admin.NewService(ctx, opts...); err != nil {
+		return nil, fmt.Errorf("create admin service: %w", err)
+	} else {
+		return svc, nil
+	}
+}
+
+// NewAdminReports creates an Admin SDK Reports service for audit activity
+// (such as Meet join and leave times). It needs an administrator account too.
+func NewAdminReports(ctx context.Context, email string) (*reports.Service, error) {
+	if opts, err := optionsForAccountScopes(ctx, "admin", email, []string{scopeReportsAuditRO}); err != nil {
+		return nil, fmt.Errorf("admin reports options: %w", err)
+	} else if svc, err := reports.NewService(ctx, opts...); err != nil {
+		return nil, fmt.Errorf("create admin reports service: %w", err)
+	} else {
+		return svc, nil
+	}
+}
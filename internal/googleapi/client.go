@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/99designs/keyring"
@@ -27,6 +29,20 @@ var (
 	openSecretsStore      = secrets.OpenDefault
 )
 
+// Token sources and the base transport are shared process-wide so many
+// commands run in one process (gog batch) reuse access tokens and
+// connections instead of refreshing and dialing per command.
+var (
+	tokenSourcesMu sync.Mutex
+	tokenSources   = map[string]oauth2.TokenSource{}
+
+	sharedTransport = &http.Transport{
+		TLSClientConfig: &tls.Config{
+			MinVersion: tls.VersionTLS12,
+		},
+	}
+)
+
 func tokenSourceForAccount(ctx context.Context, service googleauth.Service, email string) (oauth2.TokenSource, error) {
 	client, err := authclient.ResolveClient(ctx, email)
 	if err != nil {
@@ -77,10 +93,23 @@ func tokenSourceForAccountScopes(ctx context.Context, serviceLabel string, email
 		Scopes:       requiredScopes,
 	}
 
-	// Ensure refresh-token exchanges don't hang forever.
-	ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Timeout: defaultHTTPTimeout})
+	key := strings.Join([]string{client, email, clientID, tok.RefreshToken, strings.Join(requiredScopes, " ")}, "\x00")
+
+	tokenSourcesMu.Lock()
+	defer tokenSourcesMu.Unlock()
+
+	if ts, ok := tokenSources[key]; ok {
+		return ts, nil
+	}
+
+	// Ensure refresh-token exchanges don't hang forever. The source outlives
+	// the calling command, so it must not inherit its cancellation.
+	ctx = context.WithValue(context.WithoutCancel(ctx), oauth2.HTTPClient, &http.Client{Transport: withHeaders(http.DefaultTransport), Timeout: defaultHTTPTimeout})
 
-	return cfg.TokenSource(ctx, &oauth2.Token{RefreshToken: tok.RefreshToken}), nil
+	ts := oauth2.ReuseTokenSource(nil, cfg.TokenSource(ctx, &oauth2.Token{RefreshToken: tok.RefreshToken}))
+	tokenSources[key] = ts
+
+	return ts, nil
 }
 
 func optionsForAccount(ctx context.Context, service googleauth.Service, email string) ([]option.ClientOption, error) {
@@ -93,8 +122,37 @@ func optionsForAccount(ctx context.Context, service googleauth.Service, email st
 }
 
 func optionsForAccountScopes(ctx context.Context, serviceLabel string, email string, scopes []string) ([]option.ClientOption, error) {
+	c, err := httpClientForAccountScopes(ctx, serviceLabel, email, scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	return []option.ClientOption{option.WithHTTPClient(c)}, nil
+}
+
+func httpClientForAccount(ctx context.Context, service googleauth.Service, email string) (*http.Client, error) {
+	scopes, err := googleauth.Scopes(service)
+	if err != nil {
+		return nil, fmt.Errorf("resolve scopes: %w", err)
+	}
+
+	return httpClientForAccountScopes(ctx, string(service), email, scopes)
+}
+
+func httpClientForAccountScopes(ctx context.Context, serviceLabel string, email string, scopes []string) (*http.Client, error) {
 	slog.Debug("creating client options with custom scopes", "serviceLabel", serviceLabel, "email", email)
 
+	if err := loadHTTPOptions(); err != nil {
+		return nil, err
+	}
+
+	if endpoint, err := endpointOverride(); err != nil {
+		return nil, err
+	} else if endpoint != nil {
+		slog.Debug("using API endpoint override", "endpoint", endpoint.String())
+		return endpointClient(endpoint), nil
+	}
+
 	var creds config.ClientCredentials
 
 	var ts oauth2.TokenSource
@@ -122,22 +180,18 @@ func optionsForAccountScopes(ctx context.Context, serviceLabel string, email str
 			ts = tokenSource
 		}
 	}
-	baseTransport := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			MinVersion: tls.VersionTLS12,
-		},
-	}
 	// Wrap with retry logic for 429 and 5xx errors
 	retryTransport := NewRetryTransport(&oauth2.Transport{
 		Source: ts,
-		Base:   baseTransport,
+		Base:   withHeaders(sharedTransport),
 	})
+	retryTransport.RateLimiter = rateLimiterFor(usageAPI(serviceLabel), email)
 	c := &http.Client{
-		Transport: retryTransport,
+		Transport: &usageTransport{base: retryTransport, email: email, api: usageAPI(serviceLabel)},
 		Timeout:   defaultHTTPTimeout,
 	}
 
 	slog.Debug("client options with custom scopes created successfully", "serviceLabel", serviceLabel, "email", email)
 
-	return []option.ClientOption{option.WithHTTPClient(c)}, nil
+	return c, nil
 }
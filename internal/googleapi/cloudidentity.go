@@ -9,6 +9,7 @@ import (
 
 const (
 	scopeCloudIdentityGroupsRO = "https://www.googleapis.com/auth/cloud-identity.groups.readonly"
+	scopeCloudIdentityGroups   = "https://www.googleapis.com/auth/cloud-identity.groups"
 )
 
 // NewCloudIdentityGroups creates a Cloud Identity service for reading groups.
@@ -22,3 +23,15 @@ func NewCloudIdentityGroups(ctx context.Context, email string) (*cloudidentity.S
 		return svc, nil
 	}
 }
+
+// NewCloudIdentityGroupsEditor creates a Cloud Identity service that can modify
+// groups (the caller must be a group owner/manager or a Groups admin).
+func NewCloudIdentityGroupsEditor(ctx context.Context, email string) (*cloudidentity.Service, error) {
+	if opts, err := optionsForAccountScopes(ctx, "cloudidentity", email, []string{scopeCloudIdentityGroups}); err != nil {
+		return nil, fmt.Errorf("cloudidentity options: %w", err)
+	} else if svc, err := cloudidentity.NewService(ctx, opts...); err != nil {
+		return nil, fmt.Errorf("create cloudidentity service: %w", err)
+	} else {
+		return svc, nil
+	}
+}
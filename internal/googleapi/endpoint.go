@@ -0,0 +1,51 @@
+package googleapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// EnvAPIEndpoint redirects every API call to another host (such as
+// `gog mock serve`), keeping the request path and skipping OAuth.
+const EnvAPIEndpoint = "GOG_API_ENDPOINT"
+
+func endpointOverride() (*url.URL, error) {
+	raw := strings.TrimSpace(os.Getenv(EnvAPIEndpoint))
+	if raw == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("invalid %s %q (want http(s)://host:port)", EnvAPIEndpoint, raw)
+	}
+	return u, nil
+}
+
+func endpointClient(target *url.URL) *http.Client {
+	return &http.Client{
+		Transport: &endpointTransport{target: target, base: withHeaders(http.DefaultTransport)},
+		Timeout:   defaultHTTPTimeout,
+	}
+}
+
+// endpointTransport rewrites scheme and host; Google's paths already
+// identify the API (/gmail/v1/..., /calendar/v3/...).
+type endpointTransport struct {
+	target *url.URL
+	base   http.RoundTripper
+}
+
+func (t *endpointTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.URL.Scheme = t.target.Scheme
+	out.URL.Host = t.target.Host
+	out.URL.Path = strings.TrimSuffix(t.target.Path, "/") + req.URL.Path
+	if req.URL.RawPath != "" {
+		out.URL.RawPath = strings.TrimSuffix(t.target.Path, "/") + req.URL.RawPath
+	}
+	out.Host = t.target.Host
+	return t.base.RoundTrip(out)
+}
@@ -0,0 +1,78 @@
+package googleapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOptionsForAccountScopes_EndpointOverride(t *testing.T) {
+	var gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	t.Setenv(EnvAPIEndpoint, srv.URL+"/prefix/")
+
+	u, err := endpointOverride()
+	if err != nil || u == nil {
+		t.Fatalf("endpointOverride: %v %v", u, err)
+	}
+	client := &http.Client{Transport: &endpointTransport{target: u, base: http.DefaultTransport}}
+	resp, err := client.Get("https://gmail.googleapis.com/gmail/v1/users/me/profile")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	_ = resp.Body.Close()
+	if gotPath != "/prefix/gmail/v1/users/me/profile" {
+		t.Fatalf("unexpected path %q", gotPath)
+	}
+
+	t.Setenv(EnvAPIEndpoint, "localhost:8787")
+	if _, err := endpointOverride(); err == nil {
+		t.Fatalf("expected error for URL without scheme")
+	}
+}
+
+func TestHTTPOptionsLoaderRunsOnFirstClient(t *testing.T) {
+	t.Cleanup(func() { SetHTTPOptions(HTTPOptions{}) })
+	var gotUA string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUA = r.Header.Get("User-Agent")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	t.Setenv(EnvAPIEndpoint, srv.URL)
+
+	calls := 0
+	SetHTTPOptionsLoader(func() (HTTPOptions, error) {
+		calls++
+		return HTTPOptions{UserAgentSuffix: "lazy/1"}, nil
+	})
+	if calls != 0 {
+		t.Fatalf("loader ran before any client was created")
+	}
+	client, err := httpClientForAccountScopes(context.Background(), "svc", "a@b.com", []string{"s1"})
+	if err != nil {
+		t.Fatalf("client: %v", err)
+	}
+	for range 2 {
+		resp, err := client.Get("https://gmail.googleapis.com/gmail/v1/users/me/profile")
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+	if calls != 1 || !strings.HasSuffix(gotUA, "lazy/1") {
+		t.Fatalf("calls = %d, user agent = %q", calls, gotUA)
+	}
+
+	SetHTTPOptionsLoader(func() (HTTPOptions, error) { return HTTPOptions{}, errors.New("bad config") })
+	if _, err := httpClientForAccountScopes(context.Background(), "svc", "a@b.com", []string{"s1"}); err == nil || !strings.Contains(err.Error(), "bad config") {
+		t.Fatalf("want loader error, got %v", err)
+	}
+}
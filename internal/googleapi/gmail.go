@@ -5,16 +5,26 @@ import (
 	"fmt"
 
 	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
 
 	"github.com/steipete/gogcli/internal/googleauth"
 )
 
+// NewGmail creates a Gmail service for email. When ctx carries a delegated
+// mailbox (WithGmailMailbox), calls go to that mailbox instead. Calls made
+// with a WithGmailBatch context are grouped into batch requests.
 func NewGmail(ctx context.Context, email string) (*gmail.Service, error) {
-	if opts, err := optionsForAccount(ctx, googleauth.ServiceGmail, email); err != nil {
+	c, err := httpClientForAccount(ctx, googleauth.ServiceGmail, email)
+	if err != nil {
 		return nil, fmt.Errorf("gmail options: %w", err)
-	} else if svc, err := gmail.NewService(ctx, opts...); err != nil {
+	}
+	c.Transport = &gmailBatchTransport{base: c.Transport}
+	if mailbox := GmailMailbox(ctx); mailbox != "" {
+		c.Transport = &mailboxTransport{base: c.Transport, account: email, mailbox: mailbox}
+	}
+	svc, err := gmail.NewService(ctx, option.WithHTTPClient(c))
+	if err != nil {
 		return nil, fmt.Errorf("create gmail service: %w", err)
-	} else {
-		return svc, nil
 	}
+	return svc, nil
 }
@@ -0,0 +1,246 @@
+package googleapi
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GmailBatchMaxCalls is the most calls Gmail accepts in one batch request.
+// See https://developers.google.com/gmail/api/guides/batch.
+const GmailBatchMaxCalls = 100
+
+// gmailBatchLinger is how long the first queued call waits for others to join
+// its batch. Fetches are paced by the per-user quota (about 50 messages.get a
+// second), so a batch fills over a few hundred milliseconds, not instantly.
+var gmailBatchLinger = 250 * time.Millisecond
+
+const gmailBatchPath = "/batch/gmail/v1"
+
+type gmailBatchKey struct{}
+
+// WithGmailBatch marks ctx so GET calls made through a Gmail service from
+// NewGmail are coalesced into multipart batch requests. total is how many
+// calls the caller is about to make; a batch is sent as soon as that many
+// (at most GmailBatchMaxCalls) are queued. Callers must issue the calls
+// concurrently; each one blocks until its batch returns.
+func WithGmailBatch(ctx context.Context, total int) context.Context {
+	if total < 2 {
+		return ctx
+	}
+	return context.WithValue(ctx, gmailBatchKey{}, min(total, GmailBatchMaxCalls))
+}
+
+// gmailBatchSize is the batch size requested on ctx, or 0 when unbatched.
+func gmailBatchSize(ctx context.Context) int {
+	v, _ := ctx.Value(gmailBatchKey{}).(int)
+	return v
+}
+
+type gmailBatchResult struct {
+	resp *http.Response
+	err  error
+}
+
+type gmailBatchCall struct {
+	req  *http.Request
+	done chan gmailBatchResult
+}
+
+// gmailBatchTransport queues GET requests made with a WithGmailBatch context
+// and sends them to /batch/gmail/v1, up to GmailBatchMaxCalls per round trip.
+// Each call still counts against quota; batching saves the round trips.
+type gmailBatchTransport struct {
+	base http.RoundTripper
+
+	mu      sync.Mutex
+	pending []*gmailBatchCall
+	timer   *time.Timer
+}
+
+func (t *gmailBatchTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	size := gmailBatchSize(req.Context())
+	if req.Method != http.MethodGet || size == 0 {
+		return t.base.RoundTrip(req)
+	}
+
+	call := &gmailBatchCall{req: req, done: make(chan gmailBatchResult, 1)}
+	t.mu.Lock()
+	t.pending = append(t.pending, call)
+	switch {
+	case len(t.pending) >= size:
+		calls := t.takeLocked()
+		go t.send(calls)
+	case len(t.pending) == 1:
+		t.timer = time.AfterFunc(gmailBatchLinger, t.flush)
+	}
+	t.mu.Unlock()
+
+	select {
+	case res := <-call.done:
+		return res.resp, res.err
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+}
+
+func (t *gmailBatchTransport) takeLocked() []*gmailBatchCall {
+	if t.timer != nil {
+		t.timer.Stop()
+		t.timer = nil
+	}
+	calls := t.pending
+	t.pending = nil
+	return calls
+}
+
+func (t *gmailBatchTransport) flush() {
+	t.mu.Lock()
+	calls := t.takeLocked()
+	t.mu.Unlock()
+	if len(calls) > 0 {
+		t.send(calls)
+	}
+}
+
+func (t *gmailBatchTransport) send(calls []*gmailBatchCall) {
+	if len(calls) == 1 {
+		resp, err := t.base.RoundTrip(calls[0].req)
+		calls[0].done <- gmailBatchResult{resp: resp, err: err}
+		return
+	}
+
+	results, err := t.roundTripBatch(calls)
+	for i, call := range calls {
+		if err != nil {
+			call.done <- gmailBatchResult{err: err}
+			continue
+		}
+		call.done <- results[i]
+	}
+}
+
+func (t *gmailBatchTransport) roundTripBatch(calls []*gmailBatchCall) ([]gmailBatchResult, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for i, call := range calls {
+		h := textproto.MIMEHeader{}
+		h.Set("Content-Type", "application/http")
+		h.Set("Content-ID", "<item-"+strconv.Itoa(i)+">")
+		part, err := mw.CreatePart(h)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(part, "GET %s HTTP/1.1\r\n", call.req.URL.RequestURI())
+		if err := call.req.Header.Write(part); err != nil {
+			return nil, err
+		}
+		_, _ = io.WriteString(part, "\r\n")
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	first := calls[0].req
+	target := *first.URL
+	target.Path, target.RawPath, target.RawQuery = gmailBatchPath, "", ""
+	// All calls come from one fetch and share its context.
+	req, err := http.NewRequestWithContext(first.Context(), http.MethodPost, target.String(), &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "multipart/mixed; boundary="+mw.Boundary())
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// The whole batch failed (auth, rate limit): hand every call a copy so
+		// callers see the same googleapi error they would unbatched.
+		data, _ := io.ReadAll(resp.Body)
+		out := make([]gmailBatchResult, len(calls))
+		for i, call := range calls {
+			cp := *resp
+			cp.Header = resp.Header.Clone()
+			cp.Body = io.NopCloser(bytes.NewReader(data))
+			cp.Request = call.req
+			out[i] = gmailBatchResult{resp: &cp}
+		}
+		return out, nil
+	}
+	return parseGmailBatchResponse(resp, calls)
+}
+
+func parseGmailBatchResponse(resp *http.Response, calls []*gmailBatchCall) ([]gmailBatchResult, error) {
+	mediaType, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		return nil, fmt.Errorf("gmail batch: unexpected response type %q", resp.Header.Get("Content-Type"))
+	}
+
+	out := make([]gmailBatchResult, len(calls))
+	seen := make([]bool, len(calls))
+	mr := multipart.NewReader(resp.Body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("gmail batch: read response: %w", err)
+		}
+		idx, ok := gmailBatchItemIndex(part.Header.Get("Content-ID"), len(calls))
+		if !ok {
+			continue
+		}
+		inner, err := http.ReadResponse(bufio.NewReader(part), calls[idx].req)
+		if err != nil {
+			return nil, fmt.Errorf("gmail batch: read item %d: %w", idx, err)
+		}
+		// Parts are read sequentially, so buffer each body before moving on.
+		data, err := io.ReadAll(inner.Body)
+		_ = inner.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("gmail batch: read item %d: %w", idx, err)
+		}
+		inner.Body = io.NopCloser(bytes.NewReader(data))
+		inner.ContentLength = int64(len(data))
+		out[idx] = gmailBatchResult{resp: inner}
+		seen[idx] = true
+	}
+	for i := range out {
+		if !seen[i] {
+			out[i] = gmailBatchResult{err: fmt.Errorf("gmail batch: no response for %s", calls[i].req.URL.Path)}
+		}
+	}
+	return out, nil
+}
+
+// gmailBatchItemIndex maps a response Content-ID ("<response-item-3>") back to
+// the call index.
+func gmailBatchItemIndex(contentID string, n int) (int, bool) {
+	id := strings.Trim(strings.TrimSpace(contentID), "<>")
+	id = strings.TrimPrefix(id, "response-")
+	raw, ok := strings.CutPrefix(id, "item-")
+	if !ok {
+		return 0, false
+	}
+	idx, err := strconv.Atoi(raw)
+	if err != nil || idx < 0 || idx >= n {
+		return 0, false
+	}
+	return idx, true
+}
@@ -0,0 +1,92 @@
+package googleapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+
+	"github.com/steipete/gogcli/mock"
+)
+
+func TestGmailBatchTransport_GroupsCalls(t *testing.T) {
+	orig := gmailBatchLinger
+	gmailBatchLinger = 50 * time.Millisecond
+	t.Cleanup(func() { gmailBatchLinger = orig })
+
+	fixtures := &mock.Fixtures{}
+	for i := 0; i < 150; i++ {
+		fixtures.Gmail.Messages = append(fixtures.Gmail.Messages, mock.MessageFixture{
+			From: "a@example.com", To: "me@example.com", Subject: fmt.Sprintf("m%d", i), Date: "2026-01-02T10:00:00Z", Body: "hi",
+		})
+	}
+	s, err := mock.New(fixtures, mock.ServiceGmail)
+	if err != nil {
+		t.Fatalf("mock.New: %v", err)
+	}
+	var batches, singles atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == gmailBatchPath {
+			batches.Add(1)
+		} else {
+			singles.Add(1)
+		}
+		s.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &gmailBatchTransport{base: http.DefaultTransport}}
+	svc, err := gmail.NewService(context.Background(), option.WithHTTPClient(client), option.WithEndpoint(srv.URL+"/"))
+	if err != nil {
+		t.Fatalf("gmail.NewService: %v", err)
+	}
+	list, err := svc.Users.Messages.List("me").MaxResults(500).Do()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(list.Messages) != 150 {
+		t.Fatalf("expected 150 messages, got %d", len(list.Messages))
+	}
+	singles.Store(0)
+
+	ctx := WithGmailBatch(context.Background(), len(list.Messages))
+	subjects := make([]string, len(list.Messages))
+	var wg sync.WaitGroup
+	for i, m := range list.Messages {
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			msg, getErr := svc.Users.Messages.Get("me", id).Format("metadata").MetadataHeaders("Subject").Context(ctx).Do()
+			if getErr != nil {
+				t.Errorf("get %s: %v", id, getErr)
+				return
+			}
+			subjects[i] = msg.Payload.Headers[0].Value
+		}(i, m.Id)
+	}
+	wg.Wait()
+
+	if got := batches.Load(); got != 2 {
+		t.Fatalf("expected 150 calls in 2 batches, got %d batches", got)
+	}
+	if got := singles.Load(); got != 0 {
+		t.Fatalf("expected no unbatched calls, got %d", got)
+	}
+	for i, subject := range subjects {
+		if subject == "" {
+			t.Fatalf("message %d: missing subject", i)
+		}
+	}
+
+	// Per-call errors come back as the matching googleapi error.
+	if _, err := svc.Users.Messages.Get("me", "missing").Context(ctx).Do(); err == nil {
+		t.Fatalf("expected not found for missing message")
+	}
+}
@@ -0,0 +1,88 @@
+package googleapi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// HTTPOptions decorate every Google API and OAuth token request, for egress
+// through authenticating proxies and gateways.
+type HTTPOptions struct {
+	Headers         http.Header
+	UserAgentSuffix string
+}
+
+var httpOptions atomic.Pointer[HTTPOptions]
+
+var (
+	httpOptionsMu     sync.Mutex
+	httpOptionsLoader func() (HTTPOptions, error)
+)
+
+// SetHTTPOptions replaces the process-wide request decorations.
+func SetHTTPOptions(opts HTTPOptions) {
+	httpOptionsMu.Lock()
+	defer httpOptionsMu.Unlock()
+	httpOptionsLoader = nil
+	httpOptions.Store(&opts)
+}
+
+// SetHTTPOptionsLoader defers resolving the request decorations until the
+// first API client is created, so commands that never call Google do not
+// pay for reading them.
+func SetHTTPOptionsLoader(load func() (HTTPOptions, error)) {
+	httpOptionsMu.Lock()
+	defer httpOptionsMu.Unlock()
+	httpOptionsLoader = load
+}
+
+// loadHTTPOptions runs a pending loader. A failed load stays pending, so
+// every client creation reports the error.
+func loadHTTPOptions() error {
+	httpOptionsMu.Lock()
+	defer httpOptionsMu.Unlock()
+	if httpOptionsLoader == nil {
+		return nil
+	}
+	opts, err := httpOptionsLoader()
+	if err != nil {
+		return fmt.Errorf("http options: %w", err)
+	}
+	httpOptionsLoader = nil
+	httpOptions.Store(&opts)
+	return nil
+}
+
+// headerTransport adds the configured headers and User-Agent suffix. It sits
+// below the retry and OAuth transports so retries and refreshes carry them too.
+type headerTransport struct {
+	base http.RoundTripper
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := loadHTTPOptions(); err != nil {
+		return nil, err
+	}
+	opts := httpOptions.Load()
+	if opts == nil || (len(opts.Headers) == 0 && opts.UserAgentSuffix == "") {
+		return t.base.RoundTrip(req)
+	}
+	out := req.Clone(req.Context())
+	for name, values := range opts.Headers {
+		out.Header.Del(name)
+		for _, v := range values {
+			out.Header.Add(name, v)
+		}
+	}
+	if suffix := strings.TrimSpace(opts.UserAgentSuffix); suffix != "" {
+		out.Header.Set("User-Agent", strings.TrimSpace(out.Header.Get("User-Agent")+" "+suffix))
+	}
+	return t.base.RoundTrip(out)
+}
+
+func withHeaders(base http.RoundTripper) http.RoundTripper {
+	return &headerTransport{base: base}
+}
@@ -0,0 +1,69 @@
+package googleapi
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/steipete/gogcli/internal/events"
+)
+
+type gmailMailboxKey struct{}
+
+// WithGmailMailbox makes Gmail services created with ctx act on a delegated
+// mailbox instead of the signed-in account's own ("me").
+func WithGmailMailbox(ctx context.Context, mailbox string) context.Context {
+	mailbox = strings.TrimSpace(mailbox)
+	if mailbox == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, gmailMailboxKey{}, mailbox)
+}
+
+// GmailMailbox returns the delegated mailbox set on ctx, or "".
+func GmailMailbox(ctx context.Context) string {
+	if v, ok := ctx.Value(gmailMailboxKey{}).(string); ok {
+		return v
+	}
+	return ""
+}
+
+// mailboxTransport swaps the "me" userId in Gmail paths for the delegated
+// mailbox and records every call in the event log, so access to a shared
+// inbox can be traced to the account that made it.
+type mailboxTransport struct {
+	base    http.RoundTripper
+	account string
+	mailbox string
+}
+
+const gmailUsersMe = "/gmail/v1/users/me/"
+
+func (t *mailboxTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	delegated := "/gmail/v1/users/" + t.mailbox + "/"
+	out.URL.Path = strings.Replace(req.URL.Path, gmailUsersMe, delegated, 1)
+	if req.URL.RawPath != "" {
+		out.URL.RawPath = strings.Replace(req.URL.RawPath, gmailUsersMe, "/gmail/v1/users/"+url.PathEscape(t.mailbox)+"/", 1)
+	}
+
+	resp, err := t.base.RoundTrip(out)
+
+	data := map[string]any{
+		"mailbox": t.mailbox,
+		"method":  req.Method,
+		"path":    out.URL.Path,
+	}
+	if resp != nil {
+		data["status"] = resp.StatusCode
+	}
+	if err != nil {
+		data["error"] = err.Error()
+	}
+	if emitErr := events.Emit(events.TypeGmailMailboxRequest, t.account, data); emitErr != nil {
+		slog.Warn("record delegated mailbox request", "mailbox", t.mailbox, "err", emitErr)
+	}
+	return resp, err
+}
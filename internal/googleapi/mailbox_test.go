@@ -0,0 +1,78 @@
+package googleapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/events"
+)
+
+func TestNewGmail_DelegatedMailbox(t *testing.T) {
+	var gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"labels":[]}`))
+	}))
+	defer srv.Close()
+	t.Setenv(EnvAPIEndpoint, srv.URL)
+	logPath := filepath.Join(t.TempDir(), "events.ndjson")
+	t.Setenv(events.EnvFile, logPath)
+
+	ctx := WithGmailMailbox(context.Background(), " support@example.com ")
+	svc, err := NewGmail(ctx, "agent@example.com")
+	if err != nil {
+		t.Fatalf("NewGmail: %v", err)
+	}
+	if _, err := svc.Users.Labels.List("me").Context(ctx).Do(); err != nil {
+		t.Fatalf("list labels: %v", err)
+	}
+	if gotPath != "/gmail/v1/users/support@example.com/labels" {
+		t.Fatalf("path = %q", gotPath)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read event log: %v", err)
+	}
+	var ev events.Event
+	if err := json.Unmarshal([]byte(strings.TrimSpace(string(data))), &ev); err != nil {
+		t.Fatalf("event: %v\n%s", err, data)
+	}
+	if ev.Type != events.TypeGmailMailboxRequest || ev.Account != "agent@example.com" || ev.Data["mailbox"] != "support@example.com" || ev.Data["method"] != http.MethodGet || ev.Data["status"] != float64(http.StatusOK) {
+		t.Fatalf("event = %+v", ev)
+	}
+}
+
+func TestNewGmail_OwnMailbox(t *testing.T) {
+	var gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"labels":[]}`))
+	}))
+	defer srv.Close()
+	t.Setenv(EnvAPIEndpoint, srv.URL)
+	logPath := filepath.Join(t.TempDir(), "events.ndjson")
+	t.Setenv(events.EnvFile, logPath)
+
+	svc, err := NewGmail(context.Background(), "agent@example.com")
+	if err != nil {
+		t.Fatalf("NewGmail: %v", err)
+	}
+	if _, err := svc.Users.Labels.List("me").Do(); err != nil {
+		t.Fatalf("list labels: %v", err)
+	}
+	if gotPath != "/gmail/v1/users/me/labels" {
+		t.Fatalf("path = %q", gotPath)
+	}
+	if _, err := os.Stat(logPath); !os.IsNotExist(err) {
+		t.Fatalf("own mailbox should not be logged: %v", err)
+	}
+}
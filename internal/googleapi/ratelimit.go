@@ -0,0 +1,158 @@
+package googleapi
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+)
+
+var rateLimitDir = config.RateLimitDir
+
+// RateLimiter holds back every request to one API for one account while a
+// 429 backoff is in effect. Workers that share it (gog batch, fan-out over
+// accounts) wait out a Retry-After together instead of each retrying on its
+// own and spending the quota faster.
+type RateLimiter struct {
+	mu    sync.Mutex
+	until time.Time
+	now   func() time.Time
+	// path, when set, shares the backoff with other gog processes, such as
+	// the workers of `gog batch --parallel`.
+	path string
+}
+
+// NewRateLimiter returns a limiter with no backoff in effect. A non-empty
+// path shares the backoff through that file.
+func NewRateLimiter(path string) *RateLimiter {
+	return &RateLimiter{now: time.Now, path: path}
+}
+
+// Pause holds back requests for d from now. An earlier, longer pause wins.
+func (l *RateLimiter) Pause(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	until := l.now().Add(d)
+	if !until.After(l.sharedUntil()) {
+		return
+	}
+	l.until = until
+	if l.path != "" {
+		if err := writePauseFile(l.path, until); err != nil {
+			slog.Debug("share rate limit pause failed", "path", l.path, "err", err)
+		}
+	}
+}
+
+// Delay is how long requests are still held back.
+func (l *RateLimiter) Delay() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if d := l.sharedUntil().Sub(l.now()); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// sharedUntil is the later of this process's pause and the shared one.
+func (l *RateLimiter) sharedUntil() time.Time {
+	until := l.until
+	if l.path == "" {
+		return until
+	}
+	data, err := os.ReadFile(l.path)
+	if err != nil {
+		return until
+	}
+	if shared, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(string(data))); err == nil && shared.After(until) {
+		return shared
+	}
+	return until
+}
+
+// Wait blocks until no backoff is in effect. The pause can be extended while
+// waiting, so it checks again after each sleep.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		d := l.Delay()
+		if d <= 0 {
+			return nil
+		}
+		slog.Debug("waiting for rate limit backoff", "delay", d)
+		timer := time.NewTimer(d)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("rate limit wait interrupted: %w", ctx.Err())
+		}
+	}
+}
+
+func writePauseFile(path string, until time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".pause-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.WriteString(until.UTC().Format(time.RFC3339Nano)); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+var (
+	rateLimitersMu sync.Mutex
+	rateLimiters   = map[string]*RateLimiter{}
+)
+
+// rateLimiterFor returns the shared limiter for an API and account. Google's
+// quotas are per user and API, so a 429 from Gmail does not slow Drive
+// requests or another account's Gmail requests.
+func rateLimiterFor(api, email string) *RateLimiter {
+	email = strings.ToLower(strings.TrimSpace(email))
+	key := api + "\x00" + email
+
+	rateLimitersMu.Lock()
+	defer rateLimitersMu.Unlock()
+
+	if l, ok := rateLimiters[key]; ok {
+		return l
+	}
+	path := ""
+	if dir, err := rateLimitDir(); err == nil {
+		path = filepath.Join(dir, rateLimitFileName(api, email))
+	}
+	l := NewRateLimiter(path)
+	rateLimiters[key] = l
+	return l
+}
+
+func rateLimitFileName(api, email string) string {
+	clean := func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '.' || r == '@' || r == '-' {
+				return r
+			}
+			return '_'
+		}, s)
+	}
+	return clean(api) + "_" + clean(email)
+}
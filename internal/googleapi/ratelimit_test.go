@@ -0,0 +1,110 @@
+package googleapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_PauseKeepsLongest(t *testing.T) {
+	now := time.Unix(1000, 0)
+	l := NewRateLimiter("")
+	l.now = func() time.Time { return now }
+
+	l.Pause(10 * time.Second)
+	l.Pause(2 * time.Second)
+	if got := l.Delay(); got != 10*time.Second {
+		t.Fatalf("delay = %v", got)
+	}
+	now = now.Add(11 * time.Second)
+	if got := l.Delay(); got != 0 {
+		t.Fatalf("delay after expiry = %v", got)
+	}
+}
+
+func TestRetryTransport_SharedLimiterSlowsOtherWorkers(t *testing.T) {
+	limiter := NewRateLimiter("")
+	throttled := &RetryTransport{
+		Base: &mockTransport{responses: []*http.Response{
+			{StatusCode: http.StatusTooManyRequests, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(""))},
+		}},
+		MaxRetries429: 1,
+		BaseDelay:     80 * time.Millisecond,
+		RateLimiter:   limiter,
+	}
+	other := &RetryTransport{Base: &mockTransport{}, MaxRetries429: 1, RateLimiter: limiter}
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://example.com", nil)
+	first := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		// The 429 pauses the limiter; wait until it has before starting the
+		// other worker.
+		for limiter.Delay() == 0 {
+			select {
+			case <-first:
+				done <- errors.New("limiter was never paused")
+				return
+			default:
+				time.Sleep(time.Millisecond)
+			}
+		}
+		start := time.Now()
+		resp, err := other.RoundTrip(req.Clone(context.Background()))
+		if err == nil {
+			resp.Body.Close()
+			if waited := time.Since(start); waited < 40*time.Millisecond {
+				t.Errorf("other worker waited only %v", waited)
+			}
+		}
+		done <- err
+	}()
+
+	resp, err := throttled.RoundTrip(req)
+	close(first)
+	if err != nil {
+		t.Fatalf("throttled: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("throttled status = %d", resp.StatusCode)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("other: %v", err)
+	}
+}
+
+func TestRateLimiter_SharedBetweenProcesses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ratelimit", "gmail_a@example.com")
+	a := NewRateLimiter(path)
+	b := NewRateLimiter(path) // as in another gog process
+
+	a.Pause(time.Minute)
+	if d := b.Delay(); d < 50*time.Second {
+		t.Fatalf("other process delay = %v", d)
+	}
+	b.Pause(time.Second) // shorter; must not cut the shared pause
+	if d := a.Delay(); d < 50*time.Second {
+		t.Fatalf("delay after shorter pause = %v", d)
+	}
+}
+
+func TestRateLimiterFor_PerAPIAndAccount(t *testing.T) {
+	dir := t.TempDir()
+	orig := rateLimitDir
+	t.Cleanup(func() { rateLimitDir = orig })
+	rateLimitDir = func() (string, error) { return dir, nil }
+
+	a := rateLimiterFor("gmail", "A@example.com")
+	if rateLimiterFor("gmail", "a@example.com") != a {
+		t.Fatal("expected the same limiter for the same API and account")
+	}
+	if rateLimiterFor("drive", "a@example.com") == a || rateLimiterFor("gmail", "b@example.com") == a {
+		t.Fatal("expected separate limiters per API and account")
+	}
+}
@@ -19,6 +19,9 @@ type RetryTransport struct {
 	MaxRetries5xx  int
 	BaseDelay      time.Duration
 	CircuitBreaker *CircuitBreaker
+	// RateLimiter, when set, is shared with other transports for the same
+	// API and account: a 429 backoff pauses all of them.
+	RateLimiter *RateLimiter
 }
 
 // NewRetryTransport creates a RetryTransport with sensible defaults.
@@ -65,6 +68,12 @@ func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 			}
 		}
 
+		if t.RateLimiter != nil {
+			if err := t.RateLimiter.Wait(req.Context()); err != nil {
+				return nil, err
+			}
+		}
+
 		resp, err = t.Base.RoundTrip(req)
 		if err != nil {
 			return nil, fmt.Errorf("round trip: %w", err)
@@ -93,7 +102,11 @@ func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 
 			drainAndClose(resp.Body)
 
-			if err := t.sleep(req.Context(), delay); err != nil {
+			// With a shared limiter the wait happens before the next attempt,
+			// alongside every other worker's.
+			if t.RateLimiter != nil {
+				t.RateLimiter.Pause(delay)
+			} else if err := t.sleep(req.Context(), delay); err != nil {
 				return nil, err
 			}
 
@@ -0,0 +1,64 @@
+package googleapi
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+)
+
+var (
+	recordScopeUsage = config.RecordScopeUsage
+	usageNow         = time.Now
+	// scopeUsageSeen dedupes per day (UTC, like the usage file), so
+	// long-running processes keep recording use after midnight.
+	scopeUsageSeen sync.Map
+)
+
+// usageTransport notes which APIs an account reads from and writes to so
+// `gog auth minimize` can drop scopes that are never used.
+type usageTransport struct {
+	base  http.RoundTripper
+	email string
+	api   string
+}
+
+func (t *usageTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	write := isWriteRequest(req)
+	now := usageNow()
+	key := t.email + "\x00" + t.api + "\x00" + boolKey(write) + "\x00" + now.UTC().Format("2006-01-02")
+	if _, seen := scopeUsageSeen.LoadOrStore(key, true); !seen {
+		if err := recordScopeUsage(t.email, t.api, write, now); err != nil {
+			slog.Debug("record scope usage failed", "api", t.api, "err", err)
+		}
+	}
+	return t.base.RoundTrip(req)
+}
+
+// isWriteRequest treats anything but GET/HEAD as a write, except POST
+// endpoints that only query (Calendar free/busy).
+func isWriteRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		return false
+	}
+	return !strings.HasSuffix(req.URL.Path, "/freeBusy")
+}
+
+// usageAPI maps client labels to the auth service that grants them.
+func usageAPI(serviceLabel string) string {
+	if serviceLabel == "cloudidentity" {
+		return "groups"
+	}
+	return serviceLabel
+}
+
+func boolKey(b bool) string {
+	if b {
+		return "w"
+	}
+	return "r"
+}
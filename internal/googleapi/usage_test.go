@@ -0,0 +1,94 @@
+package googleapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsWriteRequest(t *testing.T) {
+	for _, tc := range []struct {
+		method, url string
+		want        bool
+	}{
+		{http.MethodGet, "https://gmail.googleapis.com/gmail/v1/users/me/messages", false},
+		{http.MethodPost, "https://gmail.googleapis.com/gmail/v1/users/me/messages/send", true},
+		{http.MethodPatch, "https://www.googleapis.com/calendar/v3/calendars/primary/events/x", true},
+		{http.MethodPost, "https://www.googleapis.com/calendar/v3/freeBusy", false},
+	} {
+		req := httptest.NewRequest(tc.method, tc.url, nil)
+		if got := isWriteRequest(req); got != tc.want {
+			t.Fatalf("%s %s: got %v want %v", tc.method, tc.url, got, tc.want)
+		}
+	}
+}
+
+func TestUsageTransport_RecordsOncePerKind(t *testing.T) {
+	orig := recordScopeUsage
+	t.Cleanup(func() { recordScopeUsage = orig })
+
+	var calls []string
+	recordScopeUsage = func(email, api string, write bool, _ time.Time) error {
+		calls = append(calls, email+"/"+api+"/"+boolKey(write))
+		return nil
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	tr := &usageTransport{base: http.DefaultTransport, email: "usage-test@example.com", api: usageAPI("cloudidentity")}
+	client := &http.Client{Transport: tr}
+	for _, method := range []string{http.MethodGet, http.MethodGet, http.MethodPost} {
+		req, _ := http.NewRequest(method, srv.URL+"/v1/groups", nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("request: %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	if strings.Join(calls, ",") != "usage-test@example.com/groups/r,usage-test@example.com/groups/w" {
+		t.Fatalf("unexpected records: %v", calls)
+	}
+}
+
+func TestUsageTransport_RecordsAgainAfterDayRollover(t *testing.T) {
+	origRecord, origNow := recordScopeUsage, usageNow
+	t.Cleanup(func() { recordScopeUsage, usageNow = origRecord, origNow })
+
+	var days []string
+	recordScopeUsage = func(_, _ string, _ bool, now time.Time) error {
+		days = append(days, now.UTC().Format("2006-01-02"))
+		return nil
+	}
+	now := time.Date(2026, 3, 1, 23, 59, 0, 0, time.UTC)
+	usageNow = func() time.Time { return now }
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &usageTransport{base: http.DefaultTransport, email: "rollover@example.com", api: "gmail"}}
+	get := func() {
+		t.Helper()
+		resp, err := client.Get(srv.URL + "/gmail/v1/users/me/profile")
+		if err != nil {
+			t.Fatalf("request: %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	get()
+	get()
+	now = now.Add(2 * time.Minute)
+	get()
+
+	if strings.Join(days, ",") != "2026-03-01,2026-03-02" {
+		t.Fatalf("expected one record per day, got %v", days)
+	}
+}
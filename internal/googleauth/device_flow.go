@@ -0,0 +1,93 @@
+package googleauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/steipete/gogcli/internal/config"
+)
+
+var errDeviceFlowUnsupported = errors.New("device flow rejected by Google")
+
+// authorizeDevice runs the OAuth 2.0 device authorization grant: print a user
+// code + verification URL, then poll the token endpoint until the user approves
+// on another device. Google only allows this for "TVs and Limited Input devices"
+// OAuth clients and a restricted scope set.
+func authorizeDevice(ctx context.Context, creds config.ClientCredentials, opts AuthorizeOptions) (string, error) {
+	cfg := oauth2.Config{
+		ClientID:     creds.ClientID,
+		ClientSecret: creds.ClientSecret,
+		Endpoint:     oauthEndpoint,
+		Scopes:       opts.Scopes,
+	}
+	if cfg.Endpoint.DeviceAuthURL == "" {
+		return "", fmt.Errorf("%w: endpoint has no device authorization URL", errDeviceFlowUnsupported)
+	}
+
+	da, err := cfg.DeviceAuth(ctx)
+	if err != nil {
+		return "", wrapDeviceFlowError("request device code", err)
+	}
+
+	verifyURL := da.VerificationURIComplete
+	if verifyURL == "" {
+		verifyURL = da.VerificationURI
+	}
+	fmt.Fprintln(os.Stderr, "On any device with a browser, visit:")
+	fmt.Fprintln(os.Stderr, "  "+verifyURL)
+	fmt.Fprintln(os.Stderr, "and enter the code:")
+	fmt.Fprintln(os.Stderr, "  "+da.UserCode)
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Waiting for authorization…")
+
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		if !da.Expiry.IsZero() {
+			timeout = time.Until(da.Expiry)
+		} else {
+			timeout = 15 * time.Minute
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	tok, err := cfg.DeviceAccessToken(ctx, da)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("authorization canceled: %w", ctx.Err())
+		}
+		return "", wrapDeviceFlowError("poll for token", err)
+	}
+	if tok.RefreshToken == "" {
+		return "", errNoRefreshToken
+	}
+
+	fmt.Fprintln(os.Stderr, "Authorization received.")
+	return tok.RefreshToken, nil
+}
+
+func wrapDeviceFlowError(action string, err error) error {
+	var retrieveErr *oauth2.RetrieveError
+	if errors.As(err, &retrieveErr) {
+		switch retrieveErr.ErrorCode {
+		case "invalid_client", "unauthorized_client":
+			return fmt.Errorf("%w: %s (the OAuth client must be of type \"TVs and Limited Input devices\"; use --manual for Desktop clients): %v", errDeviceFlowUnsupported, action, err)
+		case "invalid_scope", "restricted_client":
+			return fmt.Errorf("%w: %s (Google restricts device flow scopes; Gmail/Calendar are not allowed, use --manual instead): %v", errDeviceFlowUnsupported, action, err)
+		case "access_denied":
+			return fmt.Errorf("%w: access denied by user", errAuthorization)
+		case "expired_token":
+			return fmt.Errorf("%s: device code expired; run the command again", action)
+		}
+	}
+	if strings.TrimSpace(action) == "" {
+		return err
+	}
+	return fmt.Errorf("%s: %w", action, err)
+}
@@ -0,0 +1,121 @@
+package googleauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/steipete/gogcli/internal/config"
+)
+
+func newDeviceServer(t *testing.T, tokenErr string) *httptest.Server {
+	t.Helper()
+
+	var polls atomic.Int32
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "bad form", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/device":
+			if r.Form.Get("client_id") != "id" || r.Form.Get("scope") != "s1" {
+				http.Error(w, "bad device request", http.StatusBadRequest)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"device_code":      "dc",
+				"user_code":        "ABCD-EFGH",
+				"verification_url": "https://www.google.com/device",
+				"expires_in":       60,
+				"interval":         1,
+			})
+		case "/token":
+			if r.Form.Get("grant_type") != "urn:ietf:params:oauth:grant-type:device_code" || r.Form.Get("device_code") != "dc" {
+				http.Error(w, "bad grant", http.StatusBadRequest)
+				return
+			}
+			if tokenErr != "" {
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).Encode(map[string]any{"error": tokenErr})
+				return
+			}
+			if polls.Add(1) == 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).Encode(map[string]any{"error": "authorization_pending"})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"access_token":  "at",
+				"refresh_token": "rt-device",
+				"token_type":    "Bearer",
+				"expires_in":    3600,
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func stubDeviceEndpoint(t *testing.T, srv *httptest.Server) {
+	t.Helper()
+
+	origRead := readClientCredentials
+	origEndpoint := oauthEndpoint
+	t.Cleanup(func() {
+		readClientCredentials = origRead
+		oauthEndpoint = origEndpoint
+	})
+
+	readClientCredentials = func(string) (config.ClientCredentials, error) {
+		return config.ClientCredentials{ClientID: "id", ClientSecret: "secret"}, nil
+	}
+	oauthEndpoint = oauth2.Endpoint{
+		AuthURL:       srv.URL + "/auth",
+		TokenURL:      srv.URL + "/token",
+		DeviceAuthURL: srv.URL + "/device",
+		AuthStyle:     oauth2.AuthStyleInParams,
+	}
+}
+
+func TestAuthorize_Device_Success(t *testing.T) {
+	srv := newDeviceServer(t, "")
+	defer srv.Close()
+	stubDeviceEndpoint(t, srv)
+
+	rt, err := Authorize(context.Background(), AuthorizeOptions{
+		Scopes:  []string{"s1"},
+		Device:  true,
+		Timeout: 10 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Authorize: %v", err)
+	}
+	if rt != "rt-device" {
+		t.Fatalf("unexpected refresh token: %q", rt)
+	}
+}
+
+func TestAuthorize_Device_InvalidScope(t *testing.T) {
+	srv := newDeviceServer(t, "invalid_scope")
+	defer srv.Close()
+	stubDeviceEndpoint(t, srv)
+
+	_, err := Authorize(context.Background(), AuthorizeOptions{
+		Scopes:  []string{"s1"},
+		Device:  true,
+		Timeout: 10 * time.Second,
+	})
+	if !errors.Is(err, errDeviceFlowUnsupported) || !strings.Contains(err.Error(), "--manual") {
+		t.Fatalf("expected device flow unsupported error, got: %v", err)
+	}
+}
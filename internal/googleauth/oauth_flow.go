@@ -26,6 +26,7 @@ type AuthorizeOptions struct {
 	Services     []Service
 	Scopes       []string
 	Manual       bool
+	Device       bool
 	ForceConsent bool
 	Timeout      time.Duration
 	Client       string
@@ -60,10 +61,6 @@ var (
 )
 
 func Authorize(ctx context.Context, opts AuthorizeOptions) (string, error) {
-	if opts.Timeout <= 0 {
-		opts.Timeout = 2 * time.Minute
-	}
-
 	if len(opts.Scopes) == 0 {
 		return "", errMissingScopes
 	}
@@ -76,6 +73,15 @@ func Authorize(ctx context.Context, opts AuthorizeOptions) (string, error) {
 		creds = c
 	}
 
+	// Device codes carry their own expiry; don't clamp them to the browser timeout.
+	if opts.Device {
+		return authorizeDevice(ctx, creds, opts)
+	}
+
+	if opts.Timeout <= 0 {
+		opts.Timeout = 2 * time.Minute
+	}
+
 	state, err := randomStateFn()
 	if err != nil {
 		return "", err
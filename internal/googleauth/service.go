@@ -22,6 +22,7 @@ const (
 	ServiceSheets    Service = "sheets"
 	ServiceGroups    Service = "groups"
 	ServiceKeep      Service = "keep"
+	ServiceAdmin     Service = "admin"
 )
 
 const (
@@ -68,6 +69,7 @@ var serviceOrder = []Service{
 	ServicePeople,
 	ServiceGroups,
 	ServiceKeep,
+	ServiceAdmin,
 }
 
 var serviceInfoByService = map[Service]serviceInfo{
@@ -159,10 +161,15 @@ var serviceInfoByService = map[Service]serviceInfo{
 		note: "Export via Drive",
 	},
 	ServiceGroups: {
-		scopes: []string{"https://www.googleapis.com/auth/cloud-identity.groups.readonly"},
-		user:   false,
-		apis:   []string{"Cloud Identity API"},
-		note:   "Workspace only",
+		// The read-only scope stays alongside the full one so read clients
+		// keep requesting exactly what older grants contain.
+		scopes: []string{
+			"https://www.googleapis.com/auth/cloud-identity.groups",
+			"https://www.googleapis.com/auth/cloud-identity.groups.readonly",
+		},
+		user: false,
+		apis: []string{"Cloud Identity API"},
+		note: "Workspace only",
 	},
 	ServiceKeep: {
 		scopes: []string{"https://www.googleapis.com/auth/keep.readonly"},
@@ -170,6 +177,16 @@ var serviceInfoByService = map[Service]serviceInfo{
 		apis:   []string{"Keep API"},
 		note:   "Workspace only; service account (domain-wide delegation)",
 	},
+	ServiceAdmin: {
+		scopes: []string{
+			"https://www.googleapis.com/auth/admin.directory.user",
+			"https://www.googleapis.com/auth/admin.directory.orgunit",
+			"https://www.googleapis.com/auth/admin.reports.audit.readonly",
+		},
+		user: false,
+		apis: []string{"Admin SDK API"},
+		note: "Workspace admins only",
+	},
 }
 
 func ParseService(s string) (Service, error) {
@@ -335,6 +352,21 @@ func ScopesForManageWithOptions(services []Service, opts ScopeOptions) ([]string
 	return mergeScopes(scopes, []string{scopeOpenID, scopeEmail, scopeUserinfoEmail}), nil
 }
 
+// ScopesForManageModes is ScopesForManage with write scopes for full and
+// read-only scopes (where a service has them) for readonly.
+func ScopesForManageModes(full, readonly []Service) ([]string, error) {
+	writeScopes, err := scopesForServicesWithOptions(full, ScopeOptions{})
+	if err != nil {
+		return nil, err
+	}
+	readScopes, err := scopesForServicesWithOptions(readonly, ScopeOptions{Readonly: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeScopes(mergeScopes(writeScopes, readScopes), []string{scopeOpenID, scopeEmail, scopeUserinfoEmail}), nil
+}
+
 func scopesForServicesWithOptions(services []Service, opts ScopeOptions) ([]string, error) {
 	set := make(map[string]struct{})
 
@@ -460,8 +492,22 @@ func scopesForServiceWithOptions(service Service, opts ScopeOptions) ([]string,
 
 		return []string{driveScopeValue(), sheetsScope}, nil
 	case ServiceGroups:
+		if opts.Readonly {
+			return []string{"https://www.googleapis.com/auth/cloud-identity.groups.readonly"}, nil
+		}
+
 		return Scopes(service)
 	case ServiceKeep:
+		return Scopes(service)
+	case ServiceAdmin:
+		if opts.Readonly {
+			return []string{
+				"https://www.googleapis.com/auth/admin.directory.user.readonly",
+				"https://www.googleapis.com/auth/admin.directory.orgunit.readonly",
+				"https://www.googleapis.com/auth/admin.reports.audit.readonly",
+			}, nil
+		}
+
 		return Scopes(service)
 	default:
 		return nil, errUnknownService
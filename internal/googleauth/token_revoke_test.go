@@ -0,0 +1,58 @@
+package googleauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRevokeToken(t *testing.T) {
+	orig := revokeURL
+	t.Cleanup(func() { revokeURL = orig })
+
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("ParseForm: %v", err)
+		}
+		got = r.PostForm.Get("token")
+		if got == "bad" {
+			http.Error(w, `{"error":"invalid_token"}`, http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	revokeURL = srv.URL
+
+	if err := RevokeToken(context.Background(), "rt"); err != nil {
+		t.Fatalf("RevokeToken: %v", err)
+	}
+	if got != "rt" {
+		t.Fatalf("unexpected token posted: %q", got)
+	}
+	if err := RevokeToken(context.Background(), "bad"); err == nil {
+		t.Fatalf("expected error for rejected token")
+	}
+	if err := RevokeToken(context.Background(), " "); err == nil {
+		t.Fatalf("expected error for empty token")
+	}
+}
+
+func TestScopesForManageModes(t *testing.T) {
+	scopes, err := ScopesForManageModes([]Service{ServiceCalendar}, []Service{ServiceGmail})
+	if err != nil {
+		t.Fatalf("ScopesForManageModes: %v", err)
+	}
+	set := map[string]bool{}
+	for _, s := range scopes {
+		set[s] = true
+	}
+	if !set["https://www.googleapis.com/auth/calendar"] || !set["https://www.googleapis.com/auth/gmail.readonly"] || !set["openid"] {
+		t.Fatalf("unexpected scopes: %v", scopes)
+	}
+	if set["https://www.googleapis.com/auth/gmail.modify"] {
+		t.Fatalf("read-only gmail should not get modify scope: %v", scopes)
+	}
+}
@@ -0,0 +1,288 @@
+// Package ics reads and writes RFC 5545 iCalendar data.
+package ics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	prodID = "-//gogcli//gog//EN"
+
+	// RFC 5545 §3.1: lines SHOULD NOT be longer than 75 octets.
+	maxLineOctets = 75
+
+	dateFormat     = "20060102"
+	dateTimeFormat = "20060102T150405Z"
+)
+
+type Calendar struct {
+	Name   string
+	Method string // e.g. PUBLISH or REQUEST; omitted when empty
+	Events []Event
+}
+
+type Event struct {
+	UID          string
+	Summary      string
+	Description  string
+	Location     string
+	URL          string
+	Status       string // CONFIRMED, TENTATIVE or CANCELLED
+	Transparent  bool
+	Start        time.Time
+	End          time.Time
+	AllDay       bool // Start/End are dates; End is exclusive
+	Created      time.Time
+	LastModified time.Time
+	Sequence     int64
+	Organizer    *Attendee
+	Attendees    []Attendee
+}
+
+type Attendee struct {
+	Email    string
+	Name     string
+	Role     string // e.g. REQ-PARTICIPANT, OPT-PARTICIPANT
+	PartStat string // e.g. NEEDS-ACTION, ACCEPTED
+	RSVP     bool
+}
+
+// Encode writes c as a VCALENDAR object. stamp is used for DTSTAMP.
+func Encode(w io.Writer, c Calendar, stamp time.Time) error {
+	bw := bufio.NewWriter(w)
+	lw := &lineWriter{w: bw}
+
+	lw.prop("BEGIN", "VCALENDAR")
+	lw.prop("VERSION", "2.0")
+	lw.prop("PRODID", prodID)
+	lw.prop("CALSCALE", "GREGORIAN")
+	if c.Method != "" {
+		lw.prop("METHOD", c.Method)
+	}
+	if c.Name != "" {
+		lw.prop("X-WR-CALNAME", escapeText(c.Name))
+	}
+	for _, e := range c.Events {
+		writeEvent(lw, e, stamp)
+	}
+	lw.prop("END", "VCALENDAR")
+
+	if lw.err != nil {
+		return lw.err
+	}
+	return bw.Flush()
+}
+
+func writeEvent(lw *lineWriter, e Event, stamp time.Time) {
+	lw.prop("BEGIN", "VEVENT")
+	lw.prop("UID", escapeText(e.UID))
+	lw.prop("DTSTAMP", stamp.UTC().Format(dateTimeFormat))
+	if e.AllDay {
+		lw.prop("DTSTART;VALUE=DATE", e.Start.Format(dateFormat))
+		if !e.End.IsZero() {
+			lw.prop("DTEND;VALUE=DATE", e.End.Format(dateFormat))
+		}
+	} else {
+		lw.prop("DTSTART", e.Start.UTC().Format(dateTimeFormat))
+		if !e.End.IsZero() {
+			lw.prop("DTEND", e.End.UTC().Format(dateTimeFormat))
+		}
+	}
+	if e.Summary != "" {
+		lw.prop("SUMMARY", escapeText(e.Summary))
+	}
+	if e.Description != "" {
+		lw.prop("DESCRIPTION", escapeText(e.Description))
+	}
+	if e.Location != "" {
+		lw.prop("LOCATION", escapeText(e.Location))
+	}
+	if e.URL != "" {
+		lw.prop("URL", e.URL)
+	}
+	if e.Status != "" {
+		lw.prop("STATUS", strings.ToUpper(e.Status))
+	}
+	if e.Transparent {
+		lw.prop("TRANSP", "TRANSPARENT")
+	}
+	if e.Sequence > 0 {
+		lw.prop("SEQUENCE", fmt.Sprintf("%d", e.Sequence))
+	}
+	if !e.Created.IsZero() {
+		lw.prop("CREATED", e.Created.UTC().Format(dateTimeFormat))
+	}
+	if !e.LastModified.IsZero() {
+		lw.prop("LAST-MODIFIED", e.LastModified.UTC().Format(dateTimeFormat))
+	}
+	if e.Organizer != nil && e.Organizer.Email != "" {
+		lw.prop("ORGANIZER"+nameParam(e.Organizer.Name), "mailto:"+e.Organizer.Email)
+	}
+	for _, a := range e.Attendees {
+		if a.Email == "" {
+			continue
+		}
+		params := nameParam(a.Name)
+		if a.Role != "" {
+			params += ";ROLE=" + a.Role
+		}
+		if a.PartStat != "" {
+			params += ";PARTSTAT=" + a.PartStat
+		}
+		if a.RSVP {
+			params += ";RSVP=TRUE"
+		}
+		lw.prop("ATTENDEE"+params, "mailto:"+a.Email)
+	}
+	lw.prop("END", "VEVENT")
+}
+
+func nameParam(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ""
+	}
+	// Parameter values cannot contain DQUOTE; quote to allow ',', ';' and ':'.
+	return `;CN="` + strings.ReplaceAll(name, `"`, "'") + `"`
+}
+
+// escapeText applies RFC 5545 §3.3.11 TEXT escaping.
+func escapeText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`, "\r", `\n`)
+	return r.Replace(s)
+}
+
+type lineWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+// prop writes name:value folded at 75 octets without splitting UTF-8 sequences.
+func (lw *lineWriter) prop(name, value string) {
+	if lw.err != nil {
+		return
+	}
+	line := name + ":" + value
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		if _, lw.err = lw.w.WriteString(line[:cut] + "\r\n "); lw.err != nil {
+			return
+		}
+		line = line[cut:]
+		// Continuation lines start with a space, which counts toward the limit.
+		limit = maxLineOctets - 1
+	}
+	_, lw.err = lw.w.WriteString(line + "\r\n")
+}
+
+// MethodOf returns the calendar-level METHOD (e.g. REQUEST) of iCalendar
+// data, or "" when there is none.
+func MethodOf(data []byte) string {
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		switch strings.ToUpper(name) {
+		case "METHOD":
+			return strings.ToUpper(strings.TrimSpace(value))
+		case "BEGIN":
+			if strings.EqualFold(strings.TrimSpace(value), "VEVENT") {
+				return ""
+			}
+		}
+	}
+	return ""
+}
+
+// Reply is an attendee's answer to an invitation (METHOD:REPLY).
+type Reply struct {
+	UID      string
+	Attendee string // email address
+	PartStat string // e.g. ACCEPTED, DECLINED, TENTATIVE
+}
+
+// ParseReply reads the first VEVENT of a METHOD:REPLY calendar. ok is false
+// when data is not a reply or lacks the UID or attendee.
+func ParseReply(data []byte) (reply Reply, ok bool) {
+	if MethodOf(data) != "REPLY" {
+		return Reply{}, false
+	}
+	inEvent := false
+	for _, line := range unfoldLines(string(data)) {
+		name, params, value := splitContentLine(line)
+		switch name {
+		case "BEGIN":
+			inEvent = strings.EqualFold(value, "VEVENT")
+		case "END":
+			if inEvent && strings.EqualFold(value, "VEVENT") {
+				return reply, reply.UID != "" && reply.Attendee != ""
+			}
+		case "UID":
+			if inEvent {
+				reply.UID = value
+			}
+		case "ATTENDEE":
+			if inEvent && reply.Attendee == "" {
+				email := value
+				if len(email) >= 7 && strings.EqualFold(email[:7], "mailto:") {
+					email = email[7:]
+				}
+				reply.Attendee = strings.ToLower(strings.TrimSpace(email))
+				reply.PartStat = strings.ToUpper(params["PARTSTAT"])
+			}
+		}
+	}
+	return reply, reply.UID != "" && reply.Attendee != ""
+}
+
+// unfoldLines splits iCalendar data into logical lines, joining continuation
+// lines (RFC 5545 §3.1).
+func unfoldLines(s string) []string {
+	var out []string
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(out) > 0 {
+			out[len(out)-1] += line[1:]
+			continue
+		}
+		out = append(out, line)
+	}
+	return out
+}
+
+// splitContentLine splits NAME;PARAM=x;PARAM="y:z":VALUE. Colons inside
+// quoted parameter values do not end the name part.
+func splitContentLine(line string) (name string, params map[string]string, value string) {
+	quoted := false
+	cut := -1
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			cut = i
+			break
+		}
+	}
+	if cut < 0 {
+		return "", nil, ""
+	}
+	parts := strings.Split(line[:cut], ";")
+	params = map[string]string{}
+	for _, p := range parts[1:] {
+		k, v, _ := strings.Cut(p, "=")
+		params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return strings.ToUpper(parts[0]), params, strings.TrimSpace(line[cut+1:])
+}
@@ -0,0 +1,112 @@
+package ics
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncode(t *testing.T) {
+	stamp := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	start := time.Date(2026, 3, 2, 9, 30, 0, 0, time.FixedZone("CET", 3600))
+	var b strings.Builder
+	err := Encode(&b, Calendar{
+		Name:   "Team, Ops",
+		Method: "PUBLISH",
+		Events: []Event{
+			{
+				UID:         "e1@google.com",
+				Summary:     "Standup; daily",
+				Description: "line1\nline2",
+				Start:       start,
+				End:         start.Add(30 * time.Minute),
+				Organizer:   &Attendee{Email: "boss@example.com", Name: "The Boss"},
+				Attendees:   []Attendee{{Email: "a@example.com", PartStat: "ACCEPTED"}},
+			},
+			{
+				UID:     "e2",
+				Summary: "Holiday",
+				AllDay:  true,
+				Start:   time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC),
+				End:     time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}, stamp)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"METHOD:PUBLISH\r\n",
+		"X-WR-CALNAME:Team\\, Ops\r\n",
+		"DTSTAMP:20260301T120000Z\r\n",
+		"DTSTART:20260302T083000Z\r\n",
+		"DTEND:20260302T090000Z\r\n",
+		"SUMMARY:Standup\\; daily\r\n",
+		"DESCRIPTION:line1\\nline2\r\n",
+		"ORGANIZER;CN=\"The Boss\":mailto:boss@example.com\r\n",
+		"ATTENDEE;PARTSTAT=ACCEPTED:mailto:a@example.com\r\n",
+		"DTSTART;VALUE=DATE:20260305\r\n",
+		"DTEND;VALUE=DATE:20260306\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestEncode_FoldsLongLines(t *testing.T) {
+	var b strings.Builder
+	summary := strings.Repeat("ä", 100)
+	if err := Encode(&b, Calendar{Events: []Event{{UID: "x", Summary: summary, Start: time.Unix(0, 0)}}}, time.Unix(0, 0)); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	var unfolded strings.Builder
+	for i, line := range strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n") {
+		if len(line) > maxLineOctets {
+			t.Fatalf("line %d too long (%d): %q", i, len(line), line)
+		}
+		if strings.HasPrefix(line, " ") {
+			unfolded.WriteString(line[1:])
+			continue
+		}
+		unfolded.WriteString("\n" + line)
+	}
+	if !strings.Contains(unfolded.String(), "\nSUMMARY:"+summary+"\n") {
+		t.Fatalf("unfolded output lost summary:\n%s", unfolded.String())
+	}
+}
+
+func TestMethodOf(t *testing.T) {
+	cases := map[string]string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\nMETHOD:request\r\nEND:VCALENDAR\r\n":                  "REQUEST",
+		"BEGIN:VCALENDAR\nMETHOD:CANCEL\nEND:VCALENDAR\n":                                        "CANCEL",
+		"BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nSUMMARY:METHOD:x\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n": "",
+		"not a calendar": "",
+	}
+	for in, want := range cases {
+		if got := MethodOf([]byte(in)); got != want {
+			t.Fatalf("MethodOf(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParseReply(t *testing.T) {
+	data := "BEGIN:VCALENDAR\r\nMETHOD:REPLY\r\nBEGIN:VEVENT\r\n" +
+		"ATTENDEE;CUTYPE=INDIVIDUAL;CN=\"Doe: Jane\";PARTSTAT=ACCEPTED:mailto:Jane\r\n @Example.com\r\n" +
+		"UID:abc123@google.com\r\nSUMMARY:Sync\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	got, ok := ParseReply([]byte(data))
+	if !ok {
+		t.Fatalf("ParseReply: not a reply")
+	}
+	want := Reply{UID: "abc123@google.com", Attendee: "jane@example.com", PartStat: "ACCEPTED"}
+	if got != want {
+		t.Fatalf("ParseReply = %+v, want %+v", got, want)
+	}
+
+	if _, ok := ParseReply([]byte(strings.Replace(data, "METHOD:REPLY", "METHOD:REQUEST", 1))); ok {
+		t.Fatalf("ParseReply accepted a REQUEST")
+	}
+}
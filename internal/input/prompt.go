@@ -20,5 +20,21 @@ func PromptLineFrom(ctx context.Context, prompt string, r io.Reader) (string, er
 		_, _ = fmt.Fprint(os.Stderr, prompt)
 	}
 
-	return ReadLine(r)
+	// Read in the background so cancelling ctx (Ctrl-C) ends the prompt even
+	// while stdin blocks.
+	type result struct {
+		line string
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		line, err := ReadLine(r)
+		done <- result{line: line, err: err}
+	}()
+	select {
+	case res := <-done:
+		return res.line, res.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
 }
@@ -3,6 +3,7 @@ package input
 import (
 	"bytes"
 	"context"
+	"errors"
 	"os"
 	"strings"
 	"testing"
@@ -62,3 +63,20 @@ func TestPromptLine(t *testing.T) {
 		t.Fatalf("unexpected line: %q", line)
 	}
 }
+
+func TestPromptLineFrom_Cancelled(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe: %v", err)
+	}
+	defer func() {
+		_ = r.Close()
+		_ = w.Close()
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := PromptLineFrom(ctx, "", r); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the cancelled prompt to return, got %v", err)
+	}
+}
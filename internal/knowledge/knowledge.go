@@ -0,0 +1,247 @@
+// Package knowledge writes a Markdown corpus for search and RAG systems: one
+// file per mail thread, calendar event, or meeting notes document, each with
+// YAML frontmatter, plus a state file that makes re-runs incremental.
+package knowledge
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Kinds of documents in the corpus. Each kind has its own directory.
+const (
+	KindThread = "thread"
+	KindEvent  = "event"
+	KindDoc    = "doc"
+)
+
+// StateFile is kept in the corpus root.
+const StateFile = ".gog-knowledge.json"
+
+// maxSlugRunes keeps filenames short enough for every filesystem.
+const maxSlugRunes = 60
+
+var errEmptyKey = errors.New("document needs a kind and an id")
+
+// Doc is one corpus file. Links hold the keys (kind:id) of related
+// documents.
+type Doc struct {
+	Kind    string
+	ID      string
+	Title   string
+	Date    time.Time
+	Account string
+	Source  string
+	People  []string
+	Labels  []string
+	Links   []string
+	Body    string
+	// Version changes whenever the source changes; an unchanged version is
+	// not rewritten.
+	Version string
+}
+
+// Key identifies d across runs.
+func (d Doc) Key() string {
+	return Key(d.Kind, d.ID)
+}
+
+// Key joins a kind and an id the way Links refer to documents.
+func Key(kind, id string) string {
+	return kind + ":" + id
+}
+
+// Item is what the state file remembers about a written document.
+type Item struct {
+	Path    string    `json:"path"`
+	Title   string    `json:"title,omitempty"`
+	Version string    `json:"version"`
+	Written time.Time `json:"written"`
+}
+
+// Corpus is an output directory and its state.
+type Corpus struct {
+	root    string
+	LastRun time.Time        `json:"lastRun,omitempty"`
+	Items   map[string]*Item `json:"items"`
+}
+
+// Open loads the corpus at root, creating the directory if needed.
+func Open(root string) (*Corpus, error) {
+	if err := os.MkdirAll(root, 0o700); err != nil {
+		return nil, err
+	}
+	c := &Corpus{root: root, Items: map[string]*Item{}}
+	data, err := os.ReadFile(filepath.Join(root, StateFile)) //nolint:gosec // caller-chosen output dir
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return c, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", StateFile, err)
+	}
+	if c.Items == nil {
+		c.Items = map[string]*Item{}
+	}
+	return c, nil
+}
+
+// Current reports whether the document under key was written at version and
+// its file is still there.
+func (c *Corpus) Current(key, version string) bool {
+	item, ok := c.Items[key]
+	if !ok || item.Version != version {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(c.root, item.Path))
+	return err == nil
+}
+
+// Title returns the title recorded for key, if any.
+func (c *Corpus) Title(key string) string {
+	if item, ok := c.Items[key]; ok {
+		return item.Title
+	}
+	return ""
+}
+
+// Put writes d unless its version is already current. It reports whether
+// the file was written. A document whose title changed moves to its new
+// filename.
+func (c *Corpus) Put(d Doc, now time.Time) (bool, error) {
+	if d.Kind == "" || d.ID == "" {
+		return false, errEmptyKey
+	}
+	key := d.Key()
+	if c.Current(key, d.Version) {
+		return false, nil
+	}
+	rel := Path(d)
+	data, err := Render(d)
+	if err != nil {
+		return false, err
+	}
+	path := filepath.Join(c.root, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return false, err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return false, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return false, err
+	}
+	if old, ok := c.Items[key]; ok && old.Path != rel {
+		_ = os.Remove(filepath.Join(c.root, old.Path))
+	}
+	c.Items[key] = &Item{Path: rel, Title: d.Title, Version: d.Version, Written: now.UTC()}
+	return true, nil
+}
+
+// Save records now as the last run and writes the state file atomically.
+func (c *Corpus) Save(now time.Time) error {
+	c.LastRun = now.UTC()
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(c.root, StateFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Path is where d lives relative to the corpus root:
+// <kind>s/<date>-<title-slug>-<id>.md.
+func Path(d Doc) string {
+	name := slug(d.Title)
+	if !d.Date.IsZero() {
+		name = strings.Trim(d.Date.UTC().Format("2006-01-02")+"-"+name, "-")
+	}
+	name = strings.Trim(name+"-"+slug(d.ID), "-")
+	return filepath.Join(d.Kind+"s", name+".md")
+}
+
+// frontmatter is the YAML header of a corpus file.
+type frontmatter struct {
+	ID      string   `yaml:"id"`
+	Kind    string   `yaml:"kind"`
+	Title   string   `yaml:"title,omitempty"`
+	Date    string   `yaml:"date,omitempty"`
+	Account string   `yaml:"account,omitempty"`
+	Source  string   `yaml:"source,omitempty"`
+	People  []string `yaml:"people,omitempty"`
+	Labels  []string `yaml:"labels,omitempty"`
+	Links   []string `yaml:"links,omitempty"`
+}
+
+// Render returns d as Markdown with YAML frontmatter.
+func Render(d Doc) ([]byte, error) {
+	fm := frontmatter{
+		ID:      d.Key(),
+		Kind:    d.Kind,
+		Title:   d.Title,
+		Account: d.Account,
+		Source:  d.Source,
+		People:  d.People,
+		Labels:  d.Labels,
+		Links:   d.Links,
+	}
+	if !d.Date.IsZero() {
+		fm.Date = d.Date.UTC().Format(time.RFC3339)
+	}
+	header, err := yaml.Marshal(fm)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	buf.Write(header)
+	buf.WriteString("---\n\n")
+	if d.Title != "" {
+		buf.WriteString("# " + d.Title + "\n\n")
+	}
+	if body := strings.TrimSpace(d.Body); body != "" {
+		buf.WriteString(body)
+		buf.WriteString("\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// slug lowercases s and keeps letters and digits, joined by single dashes.
+func slug(s string) string {
+	var b strings.Builder
+	dash := false
+	n := 0
+	for _, r := range strings.ToLower(s) {
+		if n >= maxSlugRunes {
+			break
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			dash = false
+			n++
+			continue
+		}
+		if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+			n++
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
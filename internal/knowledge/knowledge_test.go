@@ -0,0 +1,76 @@
+package knowledge
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRender(t *testing.T) {
+	data, err := Render(Doc{
+		Kind:   KindEvent,
+		ID:     "e1",
+		Title:  "Q3 planning",
+		Date:   time.Date(2026, 9, 1, 15, 0, 0, 0, time.UTC),
+		People: []string{"ann@example.com"},
+		Links:  []string{"doc:d1"},
+		Body:   "Agenda\n",
+	})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	got := string(data)
+	for _, want := range []string{"---\nid: event:e1\nkind: event\n", "date: \"2026-09-01T15:00:00Z\"", "people:\n    - ann@example.com", "links:\n    - doc:d1", "---\n\n# Q3 planning\n\nAgenda\n"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestPath(t *testing.T) {
+	got := Path(Doc{Kind: KindThread, ID: "18c3AF", Title: "Re: Q3 Plan (draft)!", Date: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)})
+	if want := filepath.Join("threads", "2026-09-01-re-q3-plan-draft-18c3af.md"); got != want {
+		t.Fatalf("Path = %q, want %q", got, want)
+	}
+}
+
+func TestCorpusIncremental(t *testing.T) {
+	root := t.TempDir()
+	now := time.Now()
+	c, err := Open(root)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	doc := Doc{Kind: KindThread, ID: "t1", Title: "Hello", Version: "1"}
+	if written, err := c.Put(doc, now); err != nil || !written {
+		t.Fatalf("Put = %v, %v", written, err)
+	}
+	if err := c.Save(now); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	c, err = Open(root)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if written, _ := c.Put(doc, now); written {
+		t.Fatalf("unchanged version rewritten")
+	}
+	if c.Title("thread:t1") != "Hello" {
+		t.Fatalf("title = %q", c.Title("thread:t1"))
+	}
+
+	// A retitled document moves to its new filename.
+	doc.Title, doc.Version = "Hello again", "2"
+	if written, err := c.Put(doc, now); err != nil || !written {
+		t.Fatalf("Put = %v, %v", written, err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "threads", "hello-t1.md")); !os.IsNotExist(err) {
+		t.Fatalf("old file still there: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "threads", "hello-again-t1.md")); err != nil {
+		t.Fatalf("new file: %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+// Package maildir writes messages into Maildir / Maildir++ directories as
+// described at https://cr.yp.to/proto/maildir.html.
+package maildir
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var subdirs = []string{"tmp", "new", "cur"}
+
+// Ensure creates dir with its tmp, new and cur subdirectories.
+func Ensure(dir string) error {
+	for _, sub := range subdirs {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o700); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Folder returns the Maildir++ folder for name under root. Hierarchy
+// separators ("/") become "."; literal dots would read as hierarchy, so they
+// become "_". An empty name is the root (INBOX) maildir.
+func Folder(root, name string) string {
+	name = strings.Trim(strings.TrimSpace(name), "/")
+	if name == "" {
+		return root
+	}
+	name = strings.ReplaceAll(name, ".", "_")
+	name = strings.ReplaceAll(name, "/", ".")
+	return filepath.Join(root, "."+name)
+}
+
+// Filename builds "<time>.<uniq>.<host>:2,<flags>" with flags in the ASCII
+// order the spec requires.
+func Filename(unix int64, uniq, host, flags string) string {
+	f := []byte(flags)
+	sort.Slice(f, func(i, j int) bool { return f[i] < f[j] })
+	return fmt.Sprintf("%d.%s.%s:2,%s", unix, uniq, sanitizeHost(host), f)
+}
+
+func sanitizeHost(host string) string {
+	if host == "" {
+		host = "localhost"
+	}
+	host = strings.ReplaceAll(host, "/", `\057`)
+	return strings.ReplaceAll(host, ":", `\072`)
+}
+
+// Deliver writes data to dir/tmp and renames it into dir/cur, so readers
+// never see a partial message.
+func Deliver(dir, name string, data []byte) error {
+	tmp := filepath.Join(dir, "tmp", name)
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, "cur", name)); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// UniqueIDs returns the uniq field of every message under root, including
+// Maildir++ subfolders.
+func UniqueIDs(root string) (map[string]bool, error) {
+	out := map[string]bool{}
+	dirs := []string{root}
+	entries, err := os.ReadDir(root)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.IsDir() && strings.HasPrefix(e.Name(), ".") && e.Name() != "." && e.Name() != ".." {
+			dirs = append(dirs, filepath.Join(root, e.Name()))
+		}
+	}
+	for _, dir := range dirs {
+		for _, sub := range []string{"cur", "new"} {
+			files, readErr := os.ReadDir(filepath.Join(dir, sub))
+			if readErr != nil {
+				if os.IsNotExist(readErr) {
+					continue
+				}
+				return nil, readErr
+			}
+			for _, f := range files {
+				parts := strings.SplitN(f.Name(), ".", 3)
+				if len(parts) == 3 {
+					out[parts[1]] = true
+				}
+			}
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,56 @@
+package maildir
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFolder(t *testing.T) {
+	root := "/m"
+	cases := map[string]string{
+		"":              "/m",
+		"Sent":          "/m/.Sent",
+		"Work/Projects": "/m/.Work.Projects",
+		"v1.2":          "/m/.v1_2",
+	}
+	for in, want := range cases {
+		if got := Folder(root, in); got != filepath.FromSlash(want) {
+			t.Fatalf("Folder(%q)=%q want %q", in, got, want)
+		}
+	}
+}
+
+func TestFilename(t *testing.T) {
+	got := Filename(1700000000, "abc", "host:1/x", "SFD")
+	want := `1700000000.abc.host\0721\057x:2,DFS`
+	if got != want {
+		t.Fatalf("Filename=%q want %q", got, want)
+	}
+}
+
+func TestDeliverAndUniqueIDs(t *testing.T) {
+	root := t.TempDir()
+	sub := Folder(root, "Work")
+	for _, dir := range []string{root, sub} {
+		if err := Ensure(dir); err != nil {
+			t.Fatalf("Ensure: %v", err)
+		}
+	}
+	if err := Deliver(root, Filename(1, "m1", "h", "S"), []byte("a")); err != nil {
+		t.Fatalf("Deliver: %v", err)
+	}
+	if err := Deliver(sub, Filename(2, "m2", "h", ""), []byte("b")); err != nil {
+		t.Fatalf("Deliver: %v", err)
+	}
+	if tmp, _ := os.ReadDir(filepath.Join(root, "tmp")); len(tmp) != 0 {
+		t.Fatalf("tmp not empty: %v", tmp)
+	}
+	ids, err := UniqueIDs(root)
+	if err != nil {
+		t.Fatalf("UniqueIDs: %v", err)
+	}
+	if len(ids) != 2 || !ids["m1"] || !ids["m2"] {
+		t.Fatalf("unexpected ids: %v", ids)
+	}
+}
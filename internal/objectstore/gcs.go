@@ -0,0 +1,47 @@
+package objectstore
+
+import (
+	"context"
+	"io"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	"google.golang.org/api/storage/v1"
+)
+
+func init() {
+	Register("gs", func(ctx context.Context, bucket string) (Writer, error) {
+		// Application Default Credentials: GOOGLE_APPLICATION_CREDENTIALS,
+		// gcloud auth application-default login, or the metadata server.
+		return NewGCS(ctx, bucket, option.WithScopes(storage.DevstorageReadWriteScope))
+	})
+}
+
+type gcsWriter struct {
+	svc    *storage.Service
+	bucket string
+}
+
+// NewGCS returns a Writer for a Google Cloud Storage bucket.
+func NewGCS(ctx context.Context, bucket string, opts ...option.ClientOption) (Writer, error) {
+	svc, err := storage.NewService(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &gcsWriter{svc: svc, bucket: bucket}, nil
+}
+
+func (w *gcsWriter) Put(ctx context.Context, key string, body io.ReadSeeker, _ int64, contentType string) error {
+	if _, err := body.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	var media []googleapi.MediaOption
+	if contentType != "" {
+		media = append(media, googleapi.ContentType(contentType))
+	}
+	_, err := w.svc.Objects.Insert(w.bucket, &storage.Object{Name: key, ContentType: contentType}).
+		Media(body, media...).
+		Context(ctx).
+		Do()
+	return err
+}
@@ -0,0 +1,101 @@
+// Package objectstore uploads command output to cloud object storage, so
+// scheduled exports can land in a bucket instead of on local disk.
+//
+// Destinations are URLs like gs://bucket/path or s3://bucket/path. Backends
+// register an Opener for their scheme; gs and s3 are built in.
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var errNoBucket = errors.New("missing bucket")
+
+// Writer stores objects in one bucket.
+type Writer interface {
+	// Put uploads size bytes from body as the object at key. body is read
+	// from the start and may be read more than once (e.g. to sign it).
+	Put(ctx context.Context, key string, body io.ReadSeeker, size int64, contentType string) error
+}
+
+// Opener returns a Writer for a bucket.
+type Opener func(ctx context.Context, bucket string) (Writer, error)
+
+// Location is a parsed destination URL.
+type Location struct {
+	Scheme string
+	Bucket string
+	Key    string // may be empty or end in "/" for a prefix
+}
+
+func (l Location) String() string {
+	return l.Scheme + "://" + l.Bucket + "/" + l.Key
+}
+
+// IsPrefix reports whether the key names a "directory": objects go below it
+// under their own file names.
+func (l Location) IsPrefix() bool {
+	return l.Key == "" || strings.HasSuffix(l.Key, "/")
+}
+
+var (
+	mu      sync.RWMutex
+	openers = map[string]Opener{}
+)
+
+// Register makes a backend available for scheme (without "://").
+func Register(scheme string, open Opener) {
+	mu.Lock()
+	defer mu.Unlock()
+	openers[strings.ToLower(scheme)] = open
+}
+
+// Schemes lists the registered schemes.
+func Schemes() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	out := make([]string, 0, len(openers))
+	for s := range openers {
+		out = append(out, s)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Parse reads a destination URL. ok is false when raw does not use a
+// registered scheme, i.e. it is a local path.
+func Parse(raw string) (loc Location, ok bool, err error) {
+	scheme, rest, found := strings.Cut(strings.TrimSpace(raw), "://")
+	if !found {
+		return Location{}, false, nil
+	}
+	scheme = strings.ToLower(scheme)
+	mu.RLock()
+	_, registered := openers[scheme]
+	mu.RUnlock()
+	if !registered {
+		return Location{}, false, nil
+	}
+	bucket, key, _ := strings.Cut(rest, "/")
+	if bucket == "" {
+		return Location{}, true, fmt.Errorf("%w in %q (use %s://bucket/path)", errNoBucket, raw, scheme)
+	}
+	return Location{Scheme: scheme, Bucket: bucket, Key: key}, true, nil
+}
+
+// Open returns a Writer for the location's bucket.
+func Open(ctx context.Context, loc Location) (Writer, error) {
+	mu.RLock()
+	open, ok := openers[loc.Scheme]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported storage scheme %q (supported: %s)", loc.Scheme, strings.Join(Schemes(), ", "))
+	}
+	return open(ctx, loc.Bucket)
+}
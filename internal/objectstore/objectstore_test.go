@@ -0,0 +1,63 @@
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/option"
+)
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		raw    string
+		ok     bool
+		want   Location
+		prefix bool
+	}{
+		{raw: "gs://bucket/backups/a.pdf", ok: true, want: Location{Scheme: "gs", Bucket: "bucket", Key: "backups/a.pdf"}},
+		{raw: "S3://bucket/backups/", ok: true, want: Location{Scheme: "s3", Bucket: "bucket", Key: "backups/"}, prefix: true},
+		{raw: "gs://bucket", ok: true, want: Location{Scheme: "gs", Bucket: "bucket"}, prefix: true},
+		{raw: "./out.pdf"},
+		{raw: "ftp://host/file"},
+	} {
+		got, ok, err := Parse(tc.raw)
+		if err != nil || ok != tc.ok || got != tc.want || (ok && got.IsPrefix() != tc.prefix) {
+			t.Errorf("Parse(%q) = %+v, %v, %v", tc.raw, got, ok, err)
+		}
+	}
+	if _, ok, err := Parse("gs:///key"); !ok || err == nil {
+		t.Errorf("expected missing bucket error, got %v %v", ok, err)
+	}
+}
+
+func TestGCSPut(t *testing.T) {
+	var gotPath, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name":"a.txt","bucket":"b"}`))
+	}))
+	defer srv.Close()
+
+	w, err := NewGCS(context.Background(), "b",
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewGCS: %v", err)
+	}
+	if err := w.Put(context.Background(), "dir/a.txt", bytes.NewReader([]byte("hello")), 5, "text/plain"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if !strings.HasSuffix(gotPath, "/b/o") || !strings.Contains(gotBody, `"name":"dir/a.txt"`) || !strings.Contains(gotBody, "hello") {
+		t.Fatalf("path=%q body=%q", gotPath, gotBody)
+	}
+}
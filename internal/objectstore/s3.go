@@ -0,0 +1,214 @@
+package objectstore
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Environment variables read by the s3 backend, as in the AWS CLI.
+// AWS_ENDPOINT_URL_S3 (or AWS_ENDPOINT_URL) points at S3-compatible stores
+// such as MinIO or Cloudflare R2; those use path-style URLs.
+const (
+	envAccessKeyID     = "AWS_ACCESS_KEY_ID"
+	envSecretAccessKey = "AWS_SECRET_ACCESS_KEY" //nolint:gosec // env var name, not a credential
+	envSessionToken    = "AWS_SESSION_TOKEN"     //nolint:gosec // env var name, not a credential
+	envRegion          = "AWS_REGION"
+	envDefaultRegion   = "AWS_DEFAULT_REGION"
+	envEndpointS3      = "AWS_ENDPOINT_URL_S3"
+	envEndpoint        = "AWS_ENDPOINT_URL"
+
+	defaultS3Region = "us-east-1"
+)
+
+var errNoS3Credentials = errors.New("s3: set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+
+func init() {
+	Register("s3", func(_ context.Context, bucket string) (Writer, error) {
+		return NewS3FromEnv(bucket)
+	})
+}
+
+// S3Config configures an S3 (or S3-compatible) bucket.
+type S3Config struct {
+	Bucket          string
+	Region          string
+	Endpoint        string // empty for AWS; otherwise the base URL, used path-style
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	HTTPClient      *http.Client
+}
+
+type s3Writer struct {
+	cfg S3Config
+	now func() time.Time
+}
+
+// NewS3FromEnv returns a Writer configured from the AWS_* environment.
+func NewS3FromEnv(bucket string) (Writer, error) {
+	cfg := S3Config{
+		Bucket:          bucket,
+		Region:          firstEnv(envRegion, envDefaultRegion),
+		Endpoint:        firstEnv(envEndpointS3, envEndpoint),
+		AccessKeyID:     os.Getenv(envAccessKeyID),
+		SecretAccessKey: os.Getenv(envSecretAccessKey),
+		SessionToken:    os.Getenv(envSessionToken),
+	}
+	return NewS3(cfg)
+}
+
+// NewS3 returns a Writer that uploads with a single SigV4-signed PUT.
+func NewS3(cfg S3Config) (Writer, error) {
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, errNoS3Credentials
+	}
+	if cfg.Region == "" {
+		cfg.Region = defaultS3Region
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = http.DefaultClient
+	}
+	return &s3Writer{cfg: cfg, now: time.Now}, nil
+}
+
+func (w *s3Writer) objectURL(key string) (*url.URL, error) {
+	if w.cfg.Endpoint == "" {
+		return url.Parse(fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", w.cfg.Bucket, w.cfg.Region, s3EscapePath(key)))
+	}
+	return url.Parse(strings.TrimRight(w.cfg.Endpoint, "/") + "/" + w.cfg.Bucket + "/" + s3EscapePath(key))
+}
+
+func (w *s3Writer) Put(ctx context.Context, key string, body io.ReadSeeker, size int64, contentType string) error {
+	// S3 wants the payload hash in the signature; hash, then rewind to send.
+	if _, err := body.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, body); err != nil {
+		return err
+	}
+	if _, err := body.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	u, err := w.objectURL(key)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), io.NopCloser(body))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if w.cfg.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", w.cfg.SessionToken)
+	}
+	signS3Request(req, hex.EncodeToString(h.Sum(nil)), w.cfg, w.now().UTC())
+
+	resp, err := w.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+		return fmt.Errorf("s3 put %s/%s: %s: %s", w.cfg.Bucket, key, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// signS3Request adds AWS Signature Version 4 headers to req, signing the host
+// and every header already set on it.
+func signS3Request(req *http.Request, payloadHash string, cfg S3Config, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := day + "/" + cfg.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex(canonicalRequest)
+
+	key := hmacSHA256([]byte("AWS4"+cfg.SecretAccessKey), day)
+	key = hmacSHA256(key, cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		cfg.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// s3EscapePath percent-encodes each key segment as SigV4 expects (RFC 3986
+// unreserved characters stay as they are; "/" separates segments).
+func s3EscapePath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, s := range segments {
+		var b strings.Builder
+		for _, c := range []byte(s) {
+			if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '-' || c == '_' || c == '.' || c == '~' {
+				b.WriteByte(c)
+			} else {
+				fmt.Fprintf(&b, "%%%02X", c)
+			}
+		}
+		segments[i] = b.String()
+	}
+	return strings.Join(segments, "/")
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte(data))
+	return m.Sum(nil)
+}
+
+func firstEnv(names ...string) string {
+	for _, n := range names {
+		if v := strings.TrimSpace(os.Getenv(n)); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,173 @@
+// Package summarizer hands mail context to a user-supplied AI hook, so gog
+// can offer summaries and suggested replies without bundling a model.
+//
+// A hook is either a command, which gets the request as JSON on stdin, or an
+// HTTP endpoint, which gets it POSTed. Either answers with plain text or with
+// JSON {"text": "..."}.
+package summarizer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Tasks a hook is asked to do.
+const (
+	TaskSummarize = "summarize"
+	TaskReply     = "reply"
+)
+
+// DefaultTimeout bounds one hook call.
+const DefaultTimeout = 2 * time.Minute
+
+var (
+	errNoHook    = errors.New("summarizer needs a command or an endpoint")
+	errEmptyText = errors.New("summarizer returned no text")
+)
+
+// Message is one email in a request.
+type Message struct {
+	ID      string `json:"id"`
+	From    string `json:"from,omitempty"`
+	To      string `json:"to,omitempty"`
+	Cc      string `json:"cc,omitempty"`
+	Date    string `json:"date,omitempty"`
+	Subject string `json:"subject,omitempty"`
+	Body    string `json:"body,omitempty"`
+}
+
+// Request is what a hook receives.
+type Request struct {
+	Task    string `json:"task"`
+	Account string `json:"account"`
+	// Instructions describe the task in plain words, for hooks that pass
+	// them straight to a model as the prompt.
+	Instructions string    `json:"instructions"`
+	Messages     []Message `json:"messages"`
+}
+
+// Hook runs one request and returns the text it produced.
+type Hook interface {
+	Run(ctx context.Context, req Request) (string, error)
+}
+
+// Config selects and configures a hook.
+type Config struct {
+	Command  string
+	Endpoint string
+	Headers  map[string]string
+	Timeout  time.Duration
+	Client   *http.Client
+}
+
+// New returns the command hook when Command is set, else the endpoint hook.
+func New(cfg Config) (Hook, error) {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	switch {
+	case strings.TrimSpace(cfg.Command) != "":
+		return &commandHook{command: cfg.Command, timeout: cfg.Timeout}, nil
+	case strings.TrimSpace(cfg.Endpoint) != "":
+		client := cfg.Client
+		if client == nil {
+			client = &http.Client{}
+		}
+		return &endpointHook{url: strings.TrimSpace(cfg.Endpoint), headers: cfg.Headers, client: client, timeout: cfg.Timeout}, nil
+	}
+	return nil, errNoHook
+}
+
+type commandHook struct {
+	command string
+	timeout time.Duration
+}
+
+func (h *commandHook) Run(ctx context.Context, req Request) (string, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+	cmd := exec.CommandContext(ctx, shell, flag, h.command) //nolint:gosec // user-supplied hook
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Env = append(os.Environ(), "GOG_AI_TASK="+req.Task, "GOG_ACCOUNT="+req.Account)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("summarizer command: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return responseText(out)
+}
+
+type endpointHook struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+	timeout time.Duration
+}
+
+func (h *endpointHook) Run(ctx context.Context, req Request) (string, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	for name, value := range h.headers {
+		httpReq.Header.Set(name, os.ExpandEnv(value))
+	}
+	resp, err := h.client.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("summarizer endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+	out, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("summarizer endpoint: %s: %s", resp.Status, strings.TrimSpace(string(out)))
+	}
+	return responseText(out)
+}
+
+// responseText accepts JSON {"text": "..."} or plain text.
+func responseText(out []byte) (string, error) {
+	trimmed := bytes.TrimSpace(out)
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		var resp struct {
+			Text *string `json:"text"`
+		}
+		if err := json.Unmarshal(trimmed, &resp); err == nil && resp.Text != nil {
+			trimmed = []byte(strings.TrimSpace(*resp.Text))
+		}
+	}
+	if len(trimmed) == 0 {
+		return "", errEmptyText
+	}
+	return string(trimmed), nil
+}
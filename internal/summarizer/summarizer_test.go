@@ -0,0 +1,70 @@
+package summarizer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+)
+
+func TestCommandHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	// The hook sees the request on stdin and the task in the environment.
+	hook, err := New(Config{Command: `printf '%s:' "$GOG_AI_TASK"; grep -o '"subject":"[^"]*"'`})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	got, err := hook.Run(context.Background(), Request{Task: TaskSummarize, Messages: []Message{{ID: "m1", Subject: "Budget"}}})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if got != `summarize:"subject":"Budget"` {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestEndpointHook(t *testing.T) {
+	t.Setenv("TEST_LLM_TOKEN", "secret")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q", got)
+		}
+		var req Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Task != TaskReply || len(req.Messages) != 1 {
+			t.Errorf("request = %+v err=%v", req, err)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"text": " Sounds good, see you then. "})
+	}))
+	defer srv.Close()
+
+	hook, err := New(Config{Endpoint: srv.URL, Headers: map[string]string{"Authorization": "Bearer ${TEST_LLM_TOKEN}"}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	got, err := hook.Run(context.Background(), Request{Task: TaskReply, Messages: []Message{{ID: "m1"}}})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if got != "Sounds good, see you then." {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestResponseText(t *testing.T) {
+	if got, err := responseText([]byte("  plain answer\n")); err != nil || got != "plain answer" {
+		t.Fatalf("plain: %q %v", got, err)
+	}
+	if got, err := responseText([]byte(`{"other": 1}`)); err != nil || got != `{"other": 1}` {
+		t.Fatalf("json without text: %q %v", got, err)
+	}
+	if _, err := responseText([]byte(`{"text": ""}`)); err == nil {
+		t.Fatal("expected an error for empty text")
+	}
+	if _, err := New(Config{}); err == nil {
+		t.Fatal("expected an error without command or endpoint")
+	}
+}
@@ -0,0 +1,279 @@
+// Package todofile reads and writes plain-text task lists (Markdown
+// checklists and org-mode TODO headings) while preserving everything in the
+// file that is not a task.
+package todofile
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type Format string
+
+const (
+	Markdown Format = "markdown"
+	Org      Format = "org"
+)
+
+// FormatForPath picks the format from the file extension; anything that is
+// not .org is treated as Markdown.
+func FormatForPath(path string) Format {
+	if strings.EqualFold(filepath.Ext(path), ".org") {
+		return Org
+	}
+	return Markdown
+}
+
+// Item is one task. Due is a date (YYYY-MM-DD) or empty.
+type Item struct {
+	ID    string
+	Title string
+	Done  bool
+	Due   string
+}
+
+type block struct {
+	raw  string
+	item *entry
+}
+
+type entry struct {
+	Item
+	prefix string   // Markdown: indent + bullet; org: heading stars
+	body   []string // org: lines below the heading not managed here
+}
+
+// Document is a parsed task file. Non-task lines are kept verbatim.
+type Document struct {
+	format Format
+	blocks []block
+}
+
+var (
+	mdItemRe  = regexp.MustCompile(`^(\s*[-*+]) \[([ xX])\] ?(.*)$`)
+	mdIDRe    = regexp.MustCompile(`\s*<!--\s*gog:([^\s>]+)\s*-->\s*$`)
+	mdDueRe   = regexp.MustCompile(`(?:^|\s)due:(\d{4}-\d{2}-\d{2})(?:\s|$)`)
+	orgHeadRe = regexp.MustCompile(`^(\*+) (TODO|DONE) ?(.*)$`)
+	orgAnyRe  = regexp.MustCompile(`^\*+ `)
+	orgDueRe  = regexp.MustCompile(`DEADLINE: <(\d{4}-\d{2}-\d{2})[^>]*>`)
+	orgIDRe   = regexp.MustCompile(`^\s*:GOG_ID:\s*(\S+)\s*$`)
+)
+
+// Parse reads a task file in the given format.
+func Parse(r io.Reader, format Format) (*Document, error) {
+	var lines []string
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		lines = append(lines, strings.TrimRight(sc.Text(), "\r"))
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	doc := &Document{format: format}
+	switch format {
+	case Markdown:
+		doc.parseMarkdown(lines)
+	case Org:
+		doc.parseOrg(lines)
+	default:
+		return nil, fmt.Errorf("unknown task file format %q", format)
+	}
+	return doc, doc.checkIDs()
+}
+
+func (d *Document) parseMarkdown(lines []string) {
+	for _, line := range lines {
+		m := mdItemRe.FindStringSubmatch(line)
+		if m == nil {
+			d.blocks = append(d.blocks, block{raw: line})
+			continue
+		}
+		e := &entry{prefix: m[1]}
+		e.Done = m[2] != " "
+		rest := m[3]
+		if id := mdIDRe.FindStringSubmatch(rest); id != nil {
+			e.ID = id[1]
+			rest = rest[:len(rest)-len(id[0])]
+		}
+		if due := mdDueRe.FindStringSubmatch(rest); due != nil {
+			e.Due = due[1]
+			rest = strings.Replace(rest, strings.TrimSpace(due[0]), "", 1)
+		}
+		e.Title = strings.Join(strings.Fields(rest), " ")
+		d.blocks = append(d.blocks, block{item: e})
+	}
+}
+
+func (d *Document) parseOrg(lines []string) {
+	var cur *entry
+	for _, line := range lines {
+		if m := orgHeadRe.FindStringSubmatch(line); m != nil {
+			cur = &entry{prefix: m[1]}
+			cur.Done = m[2] == "DONE"
+			cur.Title = strings.TrimSpace(m[3])
+			d.blocks = append(d.blocks, block{item: cur})
+			continue
+		}
+		if orgAnyRe.MatchString(line) {
+			cur = nil
+		}
+		if cur == nil {
+			d.blocks = append(d.blocks, block{raw: line})
+			continue
+		}
+		if m := orgIDRe.FindStringSubmatch(line); m != nil {
+			cur.ID = m[1]
+			continue
+		}
+		if m := orgDueRe.FindStringSubmatch(line); m != nil && cur.Due == "" {
+			cur.Due = m[1]
+			line = strings.TrimRight(strings.Replace(line, m[0], "", 1), " \t")
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+		}
+		cur.body = append(cur.body, line)
+	}
+	// Drop property drawers left empty once GOG_ID was pulled out.
+	for _, b := range d.blocks {
+		if b.item == nil {
+			continue
+		}
+		for i := 0; i+1 < len(b.item.body); i++ {
+			if strings.TrimSpace(b.item.body[i]) == ":PROPERTIES:" && strings.TrimSpace(b.item.body[i+1]) == ":END:" {
+				b.item.body = append(b.item.body[:i], b.item.body[i+2:]...)
+				break
+			}
+		}
+	}
+}
+
+func (d *Document) checkIDs() error {
+	seen := map[string]bool{}
+	for _, b := range d.blocks {
+		if b.item == nil || b.item.ID == "" {
+			continue
+		}
+		if seen[b.item.ID] {
+			return fmt.Errorf("duplicate task id %q in file", b.item.ID)
+		}
+		seen[b.item.ID] = true
+	}
+	return nil
+}
+
+// Items returns pointers to the tasks in file order; changes through them
+// are written back by Encode.
+func (d *Document) Items() []*Item {
+	out := []*Item{}
+	for _, b := range d.blocks {
+		if b.item != nil {
+			out = append(out, &b.item.Item)
+		}
+	}
+	return out
+}
+
+// Remove drops the task with the given ID.
+func (d *Document) Remove(id string) {
+	for i, b := range d.blocks {
+		if b.item != nil && b.item.ID == id {
+			d.blocks = append(d.blocks[:i], d.blocks[i+1:]...)
+			return
+		}
+	}
+}
+
+// Append adds a task after the last existing task (or at the end), reusing
+// that task's indent and bullet or heading level.
+func (d *Document) Append(it Item) {
+	e := &entry{Item: it, prefix: "-"}
+	if d.format == Org {
+		e.prefix = "*"
+	}
+	at := len(d.blocks)
+	for i := len(d.blocks) - 1; i >= 0; i-- {
+		if d.blocks[i].item != nil {
+			e.prefix = d.blocks[i].item.prefix
+			at = i + 1
+			break
+		}
+	}
+	d.blocks = append(d.blocks, block{})
+	copy(d.blocks[at+1:], d.blocks[at:])
+	d.blocks[at] = block{item: e}
+}
+
+// Encode writes the document, including untouched non-task lines.
+func (d *Document) Encode(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, b := range d.blocks {
+		if b.item == nil {
+			fmt.Fprintln(bw, b.raw)
+			continue
+		}
+		if d.format == Org {
+			writeOrg(bw, b.item)
+		} else {
+			writeMarkdown(bw, b.item)
+		}
+	}
+	return bw.Flush()
+}
+
+func writeMarkdown(w io.Writer, e *entry) {
+	mark := " "
+	if e.Done {
+		mark = "x"
+	}
+	line := fmt.Sprintf("%s [%s] %s", e.prefix, mark, e.Title)
+	if e.Due != "" {
+		line += " due:" + e.Due
+	}
+	if e.ID != "" {
+		line += " <!-- gog:" + e.ID + " -->"
+	}
+	fmt.Fprintln(w, line)
+}
+
+func writeOrg(w io.Writer, e *entry) {
+	keyword := "TODO"
+	if e.Done {
+		keyword = "DONE"
+	}
+	fmt.Fprintf(w, "%s %s %s\n", e.prefix, keyword, e.Title)
+
+	body := e.body
+	indent := strings.Repeat(" ", len(e.prefix)+1)
+	if e.Due != "" {
+		fmt.Fprintf(w, "%sDEADLINE: <%s>\n", indent, orgDate(e.Due))
+	}
+	if e.ID != "" {
+		// Reuse an existing property drawer so org keeps seeing a single one.
+		if len(body) > 0 && strings.TrimSpace(body[0]) == ":PROPERTIES:" {
+			fmt.Fprintf(w, "%s\n%s:GOG_ID: %s\n", body[0], indent, e.ID)
+			body = body[1:]
+		} else {
+			fmt.Fprintf(w, "%s:PROPERTIES:\n%s:GOG_ID: %s\n%s:END:\n", indent, indent, e.ID, indent)
+		}
+	}
+	for _, line := range body {
+		fmt.Fprintln(w, line)
+	}
+}
+
+// orgDate renders YYYY-MM-DD as an org timestamp body ("2026-03-02 Mon").
+func orgDate(date string) string {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return date
+	}
+	return t.Format("2006-01-02 Mon")
+}
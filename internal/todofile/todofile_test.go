@@ -0,0 +1,78 @@
+package todofile
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarkdownRoundTrip(t *testing.T) {
+	in := "# Inbox\n\nSome notes.\n- [ ] Buy milk due:2026-03-02\n  - [x] Call Bob <!-- gog:t2 -->\n* not a task\n"
+	doc, err := Parse(strings.NewReader(in), Markdown)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	items := doc.Items()
+	if len(items) != 2 {
+		t.Fatalf("items: %#v", items)
+	}
+	if items[0].Title != "Buy milk" || items[0].Due != "2026-03-02" || items[0].Done || items[0].ID != "" {
+		t.Fatalf("item 0: %#v", items[0])
+	}
+	if items[1].Title != "Call Bob" || !items[1].Done || items[1].ID != "t2" {
+		t.Fatalf("item 1: %#v", items[1])
+	}
+
+	items[0].ID = "t1"
+	doc.Remove("t2")
+	doc.Append(Item{ID: "t3", Title: "New"})
+
+	var b strings.Builder
+	if err := doc.Encode(&b); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	want := "# Inbox\n\nSome notes.\n- [ ] Buy milk due:2026-03-02 <!-- gog:t1 -->\n- [ ] New <!-- gog:t3 -->\n* not a task\n"
+	if b.String() != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+func TestOrgRoundTrip(t *testing.T) {
+	in := "#+TITLE: Todo\n* Projects\n** TODO Write report\n   DEADLINE: <2026-03-05 Thu>\n   Some context.\n** DONE Ship it\n   :PROPERTIES:\n   :GOG_ID: t2\n   :EFFORT: 1h\n   :END:\n"
+	doc, err := Parse(strings.NewReader(in), Org)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	items := doc.Items()
+	if len(items) != 2 {
+		t.Fatalf("items: %#v", items)
+	}
+	if items[0].Title != "Write report" || items[0].Due != "2026-03-05" || items[0].Done {
+		t.Fatalf("item 0: %#v", items[0])
+	}
+	if items[1].Title != "Ship it" || !items[1].Done || items[1].ID != "t2" {
+		t.Fatalf("item 1: %#v", items[1])
+	}
+
+	items[0].ID = "t1"
+	items[1].Done = false
+	doc.Append(Item{ID: "t3", Title: "Follow up"})
+
+	var b strings.Builder
+	if err := doc.Encode(&b); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	want := "#+TITLE: Todo\n* Projects\n" +
+		"** TODO Write report\n   DEADLINE: <2026-03-05 Thu>\n   :PROPERTIES:\n   :GOG_ID: t1\n   :END:\n   Some context.\n" +
+		"** TODO Ship it\n   :PROPERTIES:\n   :GOG_ID: t2\n   :EFFORT: 1h\n   :END:\n" +
+		"** TODO Follow up\n   :PROPERTIES:\n   :GOG_ID: t3\n   :END:\n"
+	if b.String() != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+func TestParse_DuplicateIDs(t *testing.T) {
+	_, err := Parse(strings.NewReader("- [ ] a <!-- gog:x -->\n- [ ] b <!-- gog:x -->\n"), Markdown)
+	if err == nil || !strings.Contains(err.Error(), "duplicate") {
+		t.Fatalf("expected duplicate id error, got %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package translate
+
+import (
+	"context"
+	"html"
+	"strings"
+
+	"google.golang.org/api/option"
+	translatev2 "google.golang.org/api/translate/v2"
+)
+
+const cloudTranslationScope = "https://www.googleapis.com/auth/cloud-translation"
+
+type cloudTranslator struct {
+	svc *translatev2.Service
+}
+
+// NewCloud returns a Translator backed by Google Cloud Translation. Without
+// options it uses the API key in apiKey or, when that is empty, Application
+// Default Credentials.
+func NewCloud(ctx context.Context, apiKey string, opts ...option.ClientOption) (Translator, error) {
+	if len(opts) == 0 {
+		if apiKey != "" {
+			opts = append(opts, option.WithAPIKey(apiKey))
+		} else {
+			opts = append(opts, option.WithScopes(cloudTranslationScope))
+		}
+	}
+	svc, err := translatev2.NewService(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &cloudTranslator{svc: svc}, nil
+}
+
+func (t *cloudTranslator) Translate(ctx context.Context, text, target string) (Result, error) {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return Result{}, errEmptyTarget
+	}
+	res := Result{Language: target}
+	if strings.TrimSpace(text) == "" {
+		return res, nil
+	}
+	var b strings.Builder
+	for _, batch := range batches(chunks(text, maxChunk), maxBatchSegments, maxBatchBytes) {
+		resp, err := t.svc.Translations.Translate(&translatev2.TranslateTextRequest{
+			Q:      batch,
+			Target: target,
+			Format: "text",
+		}).Context(ctx).Do()
+		if err != nil {
+			return Result{}, err
+		}
+		if len(resp.Translations) != len(batch) {
+			return Result{}, errNoText
+		}
+		for _, tr := range resp.Translations {
+			// Format "text" still escapes a few entities in some responses.
+			b.WriteString(html.UnescapeString(tr.TranslatedText))
+			if res.SourceLanguage == "" {
+				res.SourceLanguage = tr.DetectedSourceLanguage
+			}
+		}
+	}
+	res.Text = b.String()
+	return res, nil
+}
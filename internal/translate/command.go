@@ -0,0 +1,46 @@
+package translate
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+type commandTranslator struct {
+	command string
+}
+
+// NewCommand returns a Translator that runs a shell command. The command
+// gets the text on stdin and the target language in $GOG_TRANSLATE_TARGET,
+// and prints the translation on stdout.
+func NewCommand(command string) (Translator, error) {
+	if strings.TrimSpace(command) == "" {
+		return nil, errEmptyCommand
+	}
+	return &commandTranslator{command: command}, nil
+}
+
+func (t *commandTranslator) Translate(ctx context.Context, text, target string) (Result, error) {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return Result{}, errEmptyTarget
+	}
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+	cmd := exec.CommandContext(ctx, shell, flag, t.command) //nolint:gosec // user-supplied hook
+	cmd.Stdin = strings.NewReader(text)
+	cmd.Env = append(os.Environ(), "GOG_TRANSLATE_TARGET="+target)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return Result{}, fmt.Errorf("translate command: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return Result{Text: strings.TrimRight(string(out), "\n"), Language: target}, nil
+}
@@ -0,0 +1,86 @@
+// Package translate translates message text through a pluggable provider:
+// Google Cloud Translation, or a local command for offline or self-hosted
+// engines.
+package translate
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+var (
+	errEmptyTarget  = errors.New("empty target language")
+	errEmptyCommand = errors.New("empty translate command")
+	errNoText       = errors.New("cloud translation returned no text")
+)
+
+// Result is a translated text.
+type Result struct {
+	Text string `json:"body"`
+	// SourceLanguage is the detected language of the original, when the
+	// provider reports it.
+	SourceLanguage string `json:"sourceLanguage,omitempty"`
+	Language       string `json:"language"`
+}
+
+// Translator translates text into a target language (BCP-47, e.g. "en").
+type Translator interface {
+	Translate(ctx context.Context, text, target string) (Result, error)
+}
+
+// Cloud Translation takes at most 128 segments and 30K characters per
+// request. maxChunk keeps each segment at the recommended size, and chunks
+// are sent in batches under both limits (bytes are never fewer than
+// characters, so counting bytes is conservative).
+const (
+	maxChunk         = 5000
+	maxBatchSegments = 128
+	maxBatchBytes    = 30000
+)
+
+// chunks splits text at paragraph breaks (or, failing that, line breaks)
+// into pieces of at most limit bytes, keeping the separators so the pieces
+// join back into the original layout.
+func chunks(text string, limit int) []string {
+	var out []string
+	for len(text) > limit {
+		cut := limit
+		for _, sep := range []string{"\n\n", "\n", " "} {
+			if i := strings.LastIndex(text[:limit-len(sep)+1], sep); i > 0 {
+				cut = i + len(sep)
+				break
+			}
+		}
+		// Never split inside a UTF-8 sequence.
+		for cut > 0 && cut < len(text) && text[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		out = append(out, text[:cut])
+		text = text[cut:]
+	}
+	if text != "" {
+		out = append(out, text)
+	}
+	return out
+}
+
+// batches groups pieces in order into batches of at most maxPieces pieces
+// and maxBytes bytes; a piece larger than maxBytes gets a batch of its own.
+func batches(pieces []string, maxPieces, maxBytes int) [][]string {
+	var out [][]string
+	var cur []string
+	size := 0
+	for _, p := range pieces {
+		if len(cur) > 0 && (len(cur) == maxPieces || size+len(p) > maxBytes) {
+			out = append(out, cur)
+			cur, size = nil, 0
+		}
+		cur = append(cur, p)
+		size += len(p)
+	}
+	if len(cur) > 0 {
+		out = append(out, cur)
+	}
+	return out
+}
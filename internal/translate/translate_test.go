@@ -0,0 +1,149 @@
+package translate
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/option"
+)
+
+func TestChunks(t *testing.T) {
+	text := "first paragraph\n\nsecond paragraph\n\nthird"
+	got := chunks(text, 20)
+	if strings.Join(got, "") != text {
+		t.Fatalf("chunks do not rejoin: %q", got)
+	}
+	for _, c := range got {
+		if len(c) > 20 {
+			t.Fatalf("chunk too long: %q", c)
+		}
+	}
+	if got[0] != "first paragraph\n\n" {
+		t.Fatalf("expected a paragraph cut, got %q", got)
+	}
+	// Multi-byte runes are never split.
+	for _, c := range chunks(strings.Repeat("ü", 10), 5) {
+		if !strings.HasPrefix(c, "ü") || strings.Trim(c, "ü") != "" {
+			t.Fatalf("split inside a rune: %q", c)
+		}
+	}
+}
+
+func TestCloudTranslate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The v2 client wraps the request body in "data".
+		var req struct {
+			Data struct {
+				Q      []string `json:"q"`
+				Target string   `json:"target"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || r.Method != http.MethodPost {
+			t.Errorf("expected a JSON POST, got %s: %v", r.Method, err)
+		}
+		if req.Data.Target != "en" {
+			t.Errorf("target = %q", req.Data.Target)
+		}
+		if got := req.Data.Q; len(got) != 1 || got[0] != "Hallo Welt" {
+			t.Errorf("q = %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"translations": []map[string]any{
+			{"translatedText": "Hello world &amp; more", "detectedSourceLanguage": "de"},
+		}}})
+	}))
+	defer srv.Close()
+
+	tr, err := NewCloud(context.Background(), "", option.WithoutAuthentication(), option.WithEndpoint(srv.URL+"/"), option.WithHTTPClient(srv.Client()))
+	if err != nil {
+		t.Fatalf("NewCloud: %v", err)
+	}
+	res, err := tr.Translate(context.Background(), "Hallo Welt", "en")
+	if err != nil {
+		t.Fatalf("Translate: %v", err)
+	}
+	if res.Text != "Hello world & more" || res.SourceLanguage != "de" || res.Language != "en" {
+		t.Fatalf("result = %+v", res)
+	}
+}
+
+func TestCloudTranslate_BatchesLongText(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var req struct {
+			Data struct {
+				Q []string `json:"q"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		size := 0
+		translations := make([]map[string]any, 0, len(req.Data.Q))
+		for _, q := range req.Data.Q {
+			size += len(q)
+			translations = append(translations, map[string]any{"translatedText": strings.ToUpper(q)})
+		}
+		if len(req.Data.Q) > maxBatchSegments || size > maxBatchBytes {
+			t.Errorf("request over limits: %d segments, %d bytes", len(req.Data.Q), size)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"translations": translations}})
+	}))
+	defer srv.Close()
+
+	tr, err := NewCloud(context.Background(), "", option.WithoutAuthentication(), option.WithEndpoint(srv.URL+"/"), option.WithHTTPClient(srv.Client()))
+	if err != nil {
+		t.Fatalf("NewCloud: %v", err)
+	}
+	text := strings.Repeat(strings.Repeat("wort ", 199)+"ende\n\n", 80)
+	res, err := tr.Translate(context.Background(), text, "en")
+	if err != nil {
+		t.Fatalf("Translate: %v", err)
+	}
+	if res.Text != strings.ToUpper(text) {
+		t.Fatalf("translation lost or reordered text")
+	}
+	if requests < 3 {
+		t.Fatalf("expected %d bytes split over several requests, got %d", len(text), requests)
+	}
+}
+
+func TestBatches(t *testing.T) {
+	got := batches([]string{"aaaa", "bb", "cc", "dddddd", "e"}, 2, 5)
+	want := [][]string{{"aaaa"}, {"bb", "cc"}, {"dddddd"}, {"e"}}
+	if len(got) != len(want) {
+		t.Fatalf("batches = %q", got)
+	}
+	for i := range want {
+		if strings.Join(got[i], "|") != strings.Join(want[i], "|") {
+			t.Fatalf("batches = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestCommandTranslate(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	tr, err := NewCommand(`printf '%s:' "$GOG_TRANSLATE_TARGET"; tr a-z A-Z`)
+	if err != nil {
+		t.Fatalf("NewCommand: %v", err)
+	}
+	res, err := tr.Translate(context.Background(), "hallo\n", "en")
+	if err != nil {
+		t.Fatalf("Translate: %v", err)
+	}
+	if res.Text != "en:HALLO" || res.Language != "en" {
+		t.Fatalf("result = %+v", res)
+	}
+	if _, err := NewCommand("  "); err == nil {
+		t.Fatal("expected an error for an empty command")
+	}
+}
@@ -0,0 +1,70 @@
+package mock
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+)
+
+// serveBatch answers a multipart/mixed batch request the way Google does:
+// every application/http part is served on its own and returned as a part of
+// the response, with Content-ID "<response-" + the request's ID + ">".
+func (s *Server) serveBatch(w http.ResponseWriter, r *http.Request) {
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" || params["boundary"] == "" {
+		writeError(w, http.StatusBadRequest, "batch requests must be multipart/mixed")
+		return
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	mr := multipart.NewReader(r.Body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid batch body")
+			return
+		}
+		inner, err := http.ReadRequest(bufio.NewReader(part))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid batch part")
+			return
+		}
+		inner = inner.WithContext(r.Context())
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, inner)
+
+		h := textproto.MIMEHeader{}
+		h.Set("Content-Type", "application/http")
+		if id := strings.Trim(part.Header.Get("Content-ID"), "<>"); id != "" {
+			h.Set("Content-ID", "<response-"+id+">")
+		}
+		out, err := mw.CreatePart(h)
+		if err != nil {
+			return
+		}
+		res := rec.Result()
+		fmt.Fprintf(out, "HTTP/1.1 %s\r\n", res.Status)
+		_ = res.Header.Write(out)
+		_, _ = io.WriteString(out, "\r\n")
+		_, _ = out.Write(rec.Body.Bytes())
+	}
+	if err := mw.Close(); err != nil {
+		return
+	}
+
+	w.Header().Set("Content-Type", "multipart/mixed; boundary="+mw.Boundary())
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body.Bytes())
+}
@@ -0,0 +1,387 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+type calendarState struct {
+	calendars []*calendar.CalendarListEntry
+	events    map[string][]*calendar.Event
+	nextID    int
+}
+
+func newCalendarState(f CalendarFixtures) (*calendarState, error) {
+	st := &calendarState{events: map[string][]*calendar.Event{}}
+	for i, c := range f.Calendars {
+		if c == nil || c.Id == "" {
+			return nil, fmt.Errorf("calendar fixture: calendar %d has no id", i)
+		}
+		entry := clone(c)
+		if entry.Summary == "" {
+			entry.Summary = entry.Id
+		}
+		if entry.TimeZone == "" {
+			entry.TimeZone = "UTC"
+		}
+		if entry.AccessRole == "" {
+			entry.AccessRole = "owner"
+		}
+		entry.Kind = "calendar#calendarListEntry"
+		st.calendars = append(st.calendars, entry)
+	}
+	if len(st.calendars) == 0 {
+		st.calendars = []*calendar.CalendarListEntry{{
+			Kind: "calendar#calendarListEntry", Id: "test@example.com", Summary: "test@example.com",
+			TimeZone: "UTC", AccessRole: "owner", Primary: true,
+		}}
+	}
+	for _, calID := range sortedKeys(f.Events) {
+		cal := st.calendar(calID)
+		if cal == nil {
+			return nil, fmt.Errorf("calendar fixture: events for unknown calendar %q", calID)
+		}
+		for i, ev := range f.Events[calID] {
+			if ev == nil {
+				continue
+			}
+			e := clone(ev)
+			if e.Id == "" {
+				e.Id = fmt.Sprintf("fixture%d", i+1)
+			}
+			if _, _, err := eventBounds(e); err != nil {
+				return nil, fmt.Errorf("calendar fixture %s: %w", e.Id, err)
+			}
+			st.fillEvent(cal.Id, e)
+			st.events[cal.Id] = append(st.events[cal.Id], e)
+		}
+	}
+	return st, nil
+}
+
+// calendar resolves an ID, with "primary" meaning the primary calendar.
+func (st *calendarState) calendar(id string) *calendar.CalendarListEntry {
+	if id == "primary" {
+		for _, c := range st.calendars {
+			if c.Primary {
+				return c
+			}
+		}
+		return st.calendars[0]
+	}
+	for _, c := range st.calendars {
+		if c.Id == id {
+			return c
+		}
+	}
+	return nil
+}
+
+func (st *calendarState) fillEvent(calID string, e *calendar.Event) {
+	e.Kind = "calendar#event"
+	if e.Status == "" {
+		e.Status = "confirmed"
+	}
+	if e.HtmlLink == "" {
+		e.HtmlLink = "https://calendar.google.com/calendar/event?eid=" + e.Id
+	}
+	if e.ICalUID == "" {
+		e.ICalUID = e.Id + "@mock.gog"
+	}
+	if e.Organizer == nil {
+		e.Organizer = &calendar.EventOrganizer{Email: calID, Self: true}
+	}
+	if e.Creator == nil {
+		e.Creator = &calendar.EventCreator{Email: calID, Self: true}
+	}
+	if e.Etag == "" {
+		e.Etag = fmt.Sprintf(`"%d"`, e.Sequence+1)
+	}
+}
+
+func (s *Server) serveCalendar(w http.ResponseWriter, r *http.Request, seg []string) {
+	st := s.calendar
+	switch {
+	case hasPrefix(seg, "users", "me", "calendarList") && len(seg) == 3:
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, &calendar.CalendarList{Kind: "calendar#calendarList", Items: st.calendars})
+	case hasPrefix(seg, "users", "me", "calendarList") && len(seg) == 4:
+		cal := st.calendar(seg[3])
+		if cal == nil {
+			writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		writeJSON(w, http.StatusOK, cal)
+	case len(seg) == 1 && seg[0] == "freeBusy":
+		s.calendarFreeBusy(w, r)
+	case len(seg) == 1 && seg[0] == "colors":
+		writeJSON(w, http.StatusOK, &calendar.Colors{
+			Kind:     "calendar#colors",
+			Calendar: map[string]calendar.ColorDefinition{},
+			Event:    map[string]calendar.ColorDefinition{},
+		})
+	case len(seg) >= 2 && seg[0] == "calendars":
+		cal := st.calendar(seg[1])
+		if cal == nil {
+			writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		switch {
+		case len(seg) == 2:
+			writeJSON(w, http.StatusOK, &calendar.Calendar{
+				Kind: "calendar#calendar", Id: cal.Id, Summary: cal.Summary,
+				Description: cal.Description, TimeZone: cal.TimeZone,
+			})
+		case len(seg) == 3 && seg[2] == "events":
+			s.calendarEvents(w, r, cal)
+		case len(seg) == 4 && seg[2] == "events":
+			s.calendarEvent(w, r, cal, seg[3])
+		default:
+			writeError(w, http.StatusNotFound, "Not Found")
+		}
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+func (s *Server) calendarEvents(w http.ResponseWriter, r *http.Request, cal *calendar.CalendarListEntry) {
+	st := s.calendar
+	switch r.Method {
+	case http.MethodGet:
+		q := r.URL.Query()
+		var from, to time.Time
+		var err error
+		if v := q.Get("timeMin"); v != "" {
+			if from, err = time.Parse(time.RFC3339, v); err != nil {
+				writeError(w, http.StatusBadRequest, "Bad Request: timeMin")
+				return
+			}
+		}
+		if v := q.Get("timeMax"); v != "" {
+			if to, err = time.Parse(time.RFC3339, v); err != nil {
+				writeError(w, http.StatusBadRequest, "Bad Request: timeMax")
+				return
+			}
+		}
+		text := strings.ToLower(q.Get("q"))
+		showDeleted := q.Get("showDeleted") == "true"
+
+		var items []*calendar.Event
+		for _, e := range st.events[cal.Id] {
+			if e.Status == "cancelled" && !showDeleted {
+				continue
+			}
+			start, end, _ := eventBounds(e)
+			if !from.IsZero() && !end.After(from) {
+				continue
+			}
+			if !to.IsZero() && !start.Before(to) {
+				continue
+			}
+			if text != "" && !strings.Contains(strings.ToLower(e.Summary+"\n"+e.Description+"\n"+e.Location), text) {
+				continue
+			}
+			items = append(items, e)
+		}
+		sort.SliceStable(items, func(i, j int) bool {
+			a, _, _ := eventBounds(items[i])
+			b, _, _ := eventBounds(items[j])
+			return a.Before(b)
+		})
+		start, end, next := page(q, len(items), 250, 2500)
+		writeJSON(w, http.StatusOK, &calendar.Events{
+			Kind:          "calendar#events",
+			Summary:       cal.Summary,
+			TimeZone:      cal.TimeZone,
+			AccessRole:    cal.AccessRole,
+			Items:         items[start:end],
+			NextPageToken: next,
+		})
+	case http.MethodPost:
+		var e calendar.Event
+		if err := decodeBody(r, &e); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if _, _, err := eventBounds(&e); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if e.Id == "" {
+			st.nextID++
+			e.Id = fmt.Sprintf("mockevt%04d", st.nextID)
+		} else if st.event(cal.Id, e.Id) != nil {
+			writeError(w, http.StatusConflict, "The requested identifier already exists.")
+			return
+		}
+		now := s.now().UTC().Format(time.RFC3339)
+		e.Created, e.Updated = now, now
+		if e.ConferenceData != nil && e.ConferenceData.CreateRequest != nil {
+			e.HangoutLink = "https://meet.google.com/mock-" + e.Id
+		}
+		st.fillEvent(cal.Id, &e)
+		st.events[cal.Id] = append(st.events[cal.Id], &e)
+		writeJSON(w, http.StatusOK, &e)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (s *Server) calendarEvent(w http.ResponseWriter, r *http.Request, cal *calendar.CalendarListEntry, eventID string) {
+	st := s.calendar
+	e := st.event(cal.Id, eventID)
+	if e == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, e)
+	case http.MethodPatch, http.MethodPut:
+		var patch map[string]json.RawMessage
+		if err := decodeBody(r, &patch); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		merged := map[string]json.RawMessage{}
+		if r.Method == http.MethodPatch {
+			current, _ := json.Marshal(e)
+			_ = json.Unmarshal(current, &merged)
+		}
+		for k, v := range patch {
+			if string(v) == "null" {
+				delete(merged, k)
+				continue
+			}
+			merged[k] = v
+		}
+		data, _ := json.Marshal(merged)
+		var updated calendar.Event
+		if err := json.Unmarshal(data, &updated); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if _, _, err := eventBounds(&updated); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		updated.Id = e.Id
+		updated.Created = e.Created
+		updated.Updated = s.now().UTC().Format(time.RFC3339)
+		updated.Sequence = e.Sequence + 1
+		updated.Etag = ""
+		if updated.ConferenceData != nil && updated.ConferenceData.CreateRequest != nil && updated.HangoutLink == "" {
+			updated.HangoutLink = "https://meet.google.com/mock-" + e.Id
+		}
+		st.fillEvent(cal.Id, &updated)
+		*e = updated
+		writeJSON(w, http.StatusOK, e)
+	case http.MethodDelete:
+		events := st.events[cal.Id]
+		for i, ev := range events {
+			if ev.Id == e.Id {
+				st.events[cal.Id] = append(events[:i], events[i+1:]...)
+				break
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (s *Server) calendarFreeBusy(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w)
+		return
+	}
+	var req calendar.FreeBusyRequest
+	if err := decodeBody(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	from, err1 := time.Parse(time.RFC3339, req.TimeMin)
+	to, err2 := time.Parse(time.RFC3339, req.TimeMax)
+	if err1 != nil || err2 != nil {
+		writeError(w, http.StatusBadRequest, "Bad Request: timeMin/timeMax")
+		return
+	}
+	resp := &calendar.FreeBusyResponse{
+		Kind:      "calendar#freeBusy",
+		TimeMin:   req.TimeMin,
+		TimeMax:   req.TimeMax,
+		Calendars: map[string]calendar.FreeBusyCalendar{},
+	}
+	for _, item := range req.Items {
+		cal := s.calendar.calendar(item.Id)
+		if cal == nil {
+			resp.Calendars[item.Id] = calendar.FreeBusyCalendar{
+				Errors: []*calendar.Error{{Domain: "global", Reason: "notFound"}},
+			}
+			continue
+		}
+		busy := []*calendar.TimePeriod{}
+		for _, e := range s.calendar.events[cal.Id] {
+			if e.Status == "cancelled" || e.Transparency == "transparent" {
+				continue
+			}
+			start, end, _ := eventBounds(e)
+			if !end.After(from) || !start.Before(to) {
+				continue
+			}
+			busy = append(busy, &calendar.TimePeriod{
+				Start: start.UTC().Format(time.RFC3339),
+				End:   end.UTC().Format(time.RFC3339),
+			})
+		}
+		sort.Slice(busy, func(i, j int) bool { return busy[i].Start < busy[j].Start })
+		resp.Calendars[item.Id] = calendar.FreeBusyCalendar{Busy: busy}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (st *calendarState) event(calID, eventID string) *calendar.Event {
+	for _, e := range st.events[calID] {
+		if e.Id == eventID {
+			return e
+		}
+	}
+	return nil
+}
+
+// eventBounds reads start/end; all-day dates are midnight UTC.
+func eventBounds(e *calendar.Event) (time.Time, time.Time, error) {
+	parse := func(dt *calendar.EventDateTime, field string) (time.Time, error) {
+		if dt == nil {
+			return time.Time{}, fmt.Errorf("missing %s", field)
+		}
+		if dt.DateTime != "" {
+			return time.Parse(time.RFC3339, dt.DateTime)
+		}
+		if dt.Date != "" {
+			return time.Parse("2006-01-02", dt.Date)
+		}
+		return time.Time{}, fmt.Errorf("missing %s", field)
+	}
+	start, err := parse(e.Start, "start")
+	if err != nil {
+		return start, start, err
+	}
+	end, err := parse(e.End, "end")
+	if err != nil {
+		return start, start, err
+	}
+	if end.Before(start) {
+		return start, end, fmt.Errorf("the specified time range is empty")
+	}
+	return start, end, nil
+}
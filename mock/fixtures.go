@@ -0,0 +1,144 @@
+package mock
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/gmail/v1"
+)
+
+//go:embed defaults/*.json
+var defaultFS embed.FS
+
+// Fixtures is the data a Server starts from. Each service reads its own file
+// in a fixtures directory: gmail.json and calendar.json.
+type Fixtures struct {
+	Gmail    GmailFixtures
+	Calendar CalendarFixtures
+}
+
+// GmailFixtures mirrors gmail.json. System labels (INBOX, SENT, ...) exist
+// implicitly; Labels only needs user labels.
+type GmailFixtures struct {
+	Email    string           `json:"email"`
+	Labels   []*gmail.Label   `json:"labels"`
+	Messages []MessageFixture `json:"messages"`
+	Filters  []*gmail.Filter  `json:"filters,omitempty"`
+}
+
+// MessageFixture is a Gmail message in API shape. When Message has no
+// payload, one is built from the shorthand fields, so fixtures can stay
+// readable: {"id": "...", "from": "...", "subject": "...", "body": "..."}.
+type MessageFixture struct {
+	Message *gmail.Message
+
+	From    string
+	To      string
+	Cc      string
+	Subject string
+	Date    string // RFC 3339
+	Body    string // text/plain
+}
+
+type messageShorthand struct {
+	From    string `json:"from,omitempty"`
+	To      string `json:"to,omitempty"`
+	Cc      string `json:"cc,omitempty"`
+	Subject string `json:"subject,omitempty"`
+	Date    string `json:"date,omitempty"`
+	Body    string `json:"body,omitempty"`
+}
+
+func (m *MessageFixture) UnmarshalJSON(data []byte) error {
+	var short messageShorthand
+	if err := json.Unmarshal(data, &short); err != nil {
+		return err
+	}
+	msg := &gmail.Message{}
+	if err := json.Unmarshal(data, msg); err != nil {
+		return err
+	}
+	*m = MessageFixture{
+		Message: msg,
+		From:    short.From,
+		To:      short.To,
+		Cc:      short.Cc,
+		Subject: short.Subject,
+		Date:    short.Date,
+		Body:    short.Body,
+	}
+	return nil
+}
+
+// CalendarFixtures mirrors calendar.json. Events are keyed by calendar ID;
+// "primary" resolves to the calendar marked primary (or the first one).
+type CalendarFixtures struct {
+	Calendars []*calendar.CalendarListEntry `json:"calendars"`
+	Events    map[string][]*calendar.Event  `json:"events"`
+}
+
+// DefaultFixtures returns the built-in data set: one account
+// (test@example.com) with a few messages, labels, calendars, and events in
+// January 2026.
+func DefaultFixtures() (*Fixtures, error) {
+	f := &Fixtures{}
+	if err := readFixture(defaultFS.ReadFile, "defaults/gmail.json", &f.Gmail); err != nil {
+		return nil, err
+	}
+	if err := readFixture(defaultFS.ReadFile, "defaults/calendar.json", &f.Calendar); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// LoadFixtures starts from DefaultFixtures and replaces a service's data
+// with dir/<service>.json when that file exists.
+func LoadFixtures(dir string) (*Fixtures, error) {
+	f, err := DefaultFixtures()
+	if err != nil {
+		return nil, err
+	}
+	if dir == "" {
+		return f, nil
+	}
+	st, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !st.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	readFile := func(name string) ([]byte, error) { return os.ReadFile(name) } //nolint:gosec // user-chosen fixtures dir
+	var gmailData GmailFixtures
+	switch err := readFixture(readFile, filepath.Join(dir, "gmail.json"), &gmailData); {
+	case err == nil:
+		f.Gmail = gmailData
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, err
+	}
+	var calendarData CalendarFixtures
+	switch err := readFixture(readFile, filepath.Join(dir, "calendar.json"), &calendarData); {
+	case err == nil:
+		f.Calendar = calendarData
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, err
+	}
+	return f, nil
+}
+
+func readFixture(readFile func(string) ([]byte, error), name string, dst any) error {
+	data, err := readFile(name)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		return fmt.Errorf("parse %s: %w", name, err)
+	}
+	return nil
+}
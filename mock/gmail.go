@@ -0,0 +1,1048 @@
+package mock
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+var gmailSystemLabels = []string{
+	"INBOX", "SENT", "DRAFT", "SPAM", "TRASH", "UNREAD", "STARRED", "IMPORTANT",
+	"CATEGORY_PERSONAL", "CATEGORY_SOCIAL", "CATEGORY_PROMOTIONS", "CATEGORY_UPDATES", "CATEGORY_FORUMS",
+}
+
+// New message IDs look like Gmail's 16-digit hex IDs and are deterministic.
+const gmailIDBase = 0x19a0000000000000
+
+type gmailState struct {
+	email    string
+	labels   []*gmail.Label
+	messages map[string]*gmail.Message
+	filters  []*gmail.Filter
+	nextID   int
+	nextUser int
+	history  uint64
+}
+
+func newGmailState(f GmailFixtures) (*gmailState, error) {
+	st := &gmailState{email: f.Email, messages: map[string]*gmail.Message{}}
+	if st.email == "" {
+		st.email = "test@example.com"
+	}
+	for _, id := range gmailSystemLabels {
+		st.labels = append(st.labels, &gmail.Label{
+			Id:                    id,
+			Name:                  id,
+			Type:                  "system",
+			MessageListVisibility: "show",
+			LabelListVisibility:   "labelShow",
+		})
+	}
+	for i, l := range f.Labels {
+		if l == nil || strings.TrimSpace(l.Name) == "" {
+			return nil, fmt.Errorf("gmail fixture: label %d has no name", i)
+		}
+		label := clone(l)
+		if label.Id == "" {
+			st.nextUser++
+			label.Id = fmt.Sprintf("Label_%d", 100+st.nextUser)
+		}
+		if label.Type == "" {
+			label.Type = "user"
+		}
+		st.labels = append(st.labels, label)
+	}
+	for _, fl := range f.Filters {
+		if fl == nil {
+			continue
+		}
+		st.filters = append(st.filters, clone(fl))
+		if st.filters[len(st.filters)-1].Id == "" {
+			st.filters[len(st.filters)-1].Id = fmt.Sprintf("ANe1Bmj%d", len(st.filters))
+		}
+	}
+	for i, fx := range f.Messages {
+		msg, err := fixtureMessage(fx, i)
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := st.messages[msg.Id]; dup {
+			return nil, fmt.Errorf("gmail fixture: duplicate message id %q", msg.Id)
+		}
+		st.history++
+		msg.HistoryId = st.history
+		st.messages[msg.Id] = msg
+	}
+	return st, nil
+}
+
+func fixtureMessage(fx MessageFixture, idx int) (*gmail.Message, error) {
+	msg := &gmail.Message{}
+	if fx.Message != nil {
+		msg = clone(fx.Message)
+	}
+	if msg.Id == "" {
+		msg.Id = fmt.Sprintf("%016x", 0x18c0000000000000+idx+1)
+	}
+	if msg.ThreadId == "" {
+		msg.ThreadId = msg.Id
+	}
+	var date time.Time
+	if fx.Date != "" {
+		t, err := time.Parse(time.RFC3339, fx.Date)
+		if err != nil {
+			return nil, fmt.Errorf("gmail fixture %s: date: %w", msg.Id, err)
+		}
+		date = t
+	}
+	if msg.InternalDate == 0 && !date.IsZero() {
+		msg.InternalDate = date.UnixMilli()
+	}
+	if date.IsZero() && msg.InternalDate != 0 {
+		date = time.UnixMilli(msg.InternalDate).UTC()
+	}
+	if msg.Payload == nil {
+		var headers []*gmail.MessagePartHeader
+		add := func(name, value string) {
+			if value != "" {
+				headers = append(headers, &gmail.MessagePartHeader{Name: name, Value: value})
+			}
+		}
+		add("From", fx.From)
+		add("To", fx.To)
+		add("Cc", fx.Cc)
+		add("Subject", fx.Subject)
+		if !date.IsZero() {
+			add("Date", date.Format(time.RFC1123Z))
+		}
+		add("Message-ID", "<"+msg.Id+"@mock.gog>")
+		add("Content-Type", "text/plain; charset=UTF-8")
+		msg.Payload = &gmail.MessagePart{
+			MimeType: "text/plain",
+			Headers:  headers,
+			Body: &gmail.MessagePartBody{
+				Data: base64.URLEncoding.EncodeToString([]byte(fx.Body)),
+				Size: int64(len(fx.Body)),
+			},
+		}
+	}
+	if msg.Snippet == "" {
+		msg.Snippet = snippet(fx.Body)
+	}
+	if msg.SizeEstimate == 0 {
+		msg.SizeEstimate = int64(len(rawMessage(msg)) * 3 / 4)
+	}
+	return msg, nil
+}
+
+func snippet(body string) string {
+	s := strings.Join(strings.Fields(body), " ")
+	if r := []rune(s); len(r) > 100 {
+		s = string(r[:100])
+	}
+	return s
+}
+
+func (s *Server) serveGmail(w http.ResponseWriter, r *http.Request, seg []string) {
+	// seg[0] is the user ID ("me" or the address); any value is accepted.
+	if len(seg) < 2 {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	st := s.gmail
+	rest := seg[1:]
+	switch {
+	case len(rest) == 1 && rest[0] == "profile":
+		s.gmailProfile(w, r)
+	case rest[0] == "labels" && len(rest) == 1:
+		s.gmailLabels(w, r)
+	case rest[0] == "labels" && len(rest) == 2:
+		s.gmailLabel(w, r, rest[1])
+	case rest[0] == "messages" && len(rest) == 1:
+		if r.Method == http.MethodPost {
+			// messages.insert is a POST on the collection.
+			s.gmailStore(w, r, "insert")
+			return
+		}
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w)
+			return
+		}
+		ids := st.search(r.URL.Query())
+		start, end, next := page(r.URL.Query(), len(ids), 100, 500)
+		refs := make([]*gmail.Message, 0, end-start)
+		for _, id := range ids[start:end] {
+			refs = append(refs, &gmail.Message{Id: id, ThreadId: st.messages[id].ThreadId})
+		}
+		writeJSON(w, http.StatusOK, &gmail.ListMessagesResponse{
+			Messages:           refs,
+			NextPageToken:      next,
+			ResultSizeEstimate: int64(len(ids)),
+		})
+	case rest[0] == "messages" && len(rest) == 2 && (rest[1] == "send" || rest[1] == "import"):
+		s.gmailStore(w, r, rest[1])
+	case rest[0] == "messages" && len(rest) == 2 && rest[1] == "batchModify":
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w)
+			return
+		}
+		var req gmail.BatchModifyMessagesRequest
+		if err := decodeBody(r, &req); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		for _, id := range req.Ids {
+			if st.messages[id] == nil {
+				writeError(w, http.StatusNotFound, "Requested entity was not found.")
+				return
+			}
+		}
+		if err := st.modify(req.Ids, req.AddLabelIds, req.RemoveLabelIds); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case rest[0] == "messages" && len(rest) == 2:
+		msg := st.messages[rest[1]]
+		if msg == nil {
+			writeError(w, http.StatusNotFound, "Requested entity was not found.")
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, messageView(msg, r.URL.Query()))
+		case http.MethodDelete:
+			delete(st.messages, msg.Id)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			methodNotAllowed(w)
+		}
+	case rest[0] == "messages" && len(rest) == 3:
+		msg := st.messages[rest[1]]
+		if msg == nil {
+			writeError(w, http.StatusNotFound, "Requested entity was not found.")
+			return
+		}
+		if !s.gmailModifyAction(w, r, []string{msg.Id}, rest[2]) {
+			return
+		}
+		writeJSON(w, http.StatusOK, messageView(msg, url.Values{"format": {"minimal"}}))
+	case rest[0] == "messages" && len(rest) == 4 && rest[2] == "attachments":
+		s.gmailAttachment(w, r, rest[1], rest[3])
+	case rest[0] == "threads" && len(rest) == 1:
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w)
+			return
+		}
+		threads := st.threadsFor(st.search(r.URL.Query()))
+		start, end, next := page(r.URL.Query(), len(threads), 100, 500)
+		writeJSON(w, http.StatusOK, &gmail.ListThreadsResponse{
+			Threads:            threads[start:end],
+			NextPageToken:      next,
+			ResultSizeEstimate: int64(len(threads)),
+		})
+	case rest[0] == "threads" && len(rest) == 2:
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w)
+			return
+		}
+		thread := st.thread(rest[1], r.URL.Query())
+		if thread == nil {
+			writeError(w, http.StatusNotFound, "Requested entity was not found.")
+			return
+		}
+		writeJSON(w, http.StatusOK, thread)
+	case rest[0] == "threads" && len(rest) == 3:
+		ids := st.threadMessageIDs(rest[1])
+		if len(ids) == 0 {
+			writeError(w, http.StatusNotFound, "Requested entity was not found.")
+			return
+		}
+		if !s.gmailModifyAction(w, r, ids, rest[2]) {
+			return
+		}
+		writeJSON(w, http.StatusOK, st.thread(rest[1], url.Values{"format": {"minimal"}}))
+	case hasPrefix(rest, "settings", "filters"):
+		s.gmailFilters(w, r, rest[2:])
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+func (s *Server) gmailFilters(w http.ResponseWriter, r *http.Request, rest []string) {
+	st := s.gmail
+	if len(rest) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, &gmail.ListFiltersResponse{Filter: st.filters})
+		case http.MethodPost:
+			var f gmail.Filter
+			if err := decodeBody(r, &f); err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			if f.Criteria == nil || f.Action == nil {
+				writeError(w, http.StatusBadRequest, "Filter must have criteria and action")
+				return
+			}
+			f.Id = fmt.Sprintf("ANe1Bmj%d", len(st.filters)+1)
+			st.filters = append(st.filters, &f)
+			writeJSON(w, http.StatusOK, &f)
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+	if len(rest) != 1 {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	for i, f := range st.filters {
+		if f.Id != rest[0] {
+			continue
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, f)
+		case http.MethodDelete:
+			st.filters = append(st.filters[:i], st.filters[i+1:]...)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+	writeError(w, http.StatusNotFound, "Requested entity was not found.")
+}
+
+func (s *Server) gmailProfile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w)
+		return
+	}
+	threads := map[string]bool{}
+	for _, m := range s.gmail.messages {
+		threads[m.ThreadId] = true
+	}
+	writeJSON(w, http.StatusOK, &gmail.Profile{
+		EmailAddress:  s.gmail.email,
+		MessagesTotal: int64(len(s.gmail.messages)),
+		ThreadsTotal:  int64(len(threads)),
+		HistoryId:     s.gmail.history,
+	})
+}
+
+func (s *Server) gmailLabels(w http.ResponseWriter, r *http.Request) {
+	st := s.gmail
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, &gmail.ListLabelsResponse{Labels: st.labels})
+	case http.MethodPost:
+		var label gmail.Label
+		if err := decodeBody(r, &label); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if strings.TrimSpace(label.Name) == "" {
+			writeError(w, http.StatusBadRequest, "Invalid label name")
+			return
+		}
+		if st.labelByName(label.Name) != nil {
+			writeError(w, http.StatusConflict, "Label name exists or conflicts")
+			return
+		}
+		st.nextUser++
+		label.Id = fmt.Sprintf("Label_%d", 100+st.nextUser)
+		label.Type = "user"
+		st.labels = append(st.labels, &label)
+		writeJSON(w, http.StatusOK, &label)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (s *Server) gmailLabel(w http.ResponseWriter, r *http.Request, id string) {
+	st := s.gmail
+	label := st.label(id)
+	if label == nil {
+		writeError(w, http.StatusNotFound, "Requested entity was not found.")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		out := clone(label)
+		threads, unreadThreads := map[string]bool{}, map[string]bool{}
+		for _, m := range st.messages {
+			if !hasLabel(m, label.Id) {
+				continue
+			}
+			out.MessagesTotal++
+			threads[m.ThreadId] = true
+			if hasLabel(m, "UNREAD") {
+				out.MessagesUnread++
+				unreadThreads[m.ThreadId] = true
+			}
+		}
+		out.ThreadsTotal = int64(len(threads))
+		out.ThreadsUnread = int64(len(unreadThreads))
+		writeJSON(w, http.StatusOK, out)
+	case http.MethodPatch, http.MethodPut:
+		var patch gmail.Label
+		if err := decodeBody(r, &patch); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if label.Type == "system" {
+			writeError(w, http.StatusBadRequest, "Invalid label: system labels cannot be modified")
+			return
+		}
+		if patch.Name != "" {
+			label.Name = patch.Name
+		}
+		if patch.Color != nil {
+			label.Color = patch.Color
+		}
+		if patch.LabelListVisibility != "" {
+			label.LabelListVisibility = patch.LabelListVisibility
+		}
+		if patch.MessageListVisibility != "" {
+			label.MessageListVisibility = patch.MessageListVisibility
+		}
+		writeJSON(w, http.StatusOK, label)
+	case http.MethodDelete:
+		if label.Type == "system" {
+			writeError(w, http.StatusBadRequest, "Invalid label: system labels cannot be deleted")
+			return
+		}
+		for i, l := range st.labels {
+			if l.Id == label.Id {
+				st.labels = append(st.labels[:i], st.labels[i+1:]...)
+				break
+			}
+		}
+		for _, m := range st.messages {
+			m.LabelIds = removeString(m.LabelIds, label.Id)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+// gmailModifyAction applies modify/trash/untrash and reports whether the
+// caller should write its response.
+func (s *Server) gmailModifyAction(w http.ResponseWriter, r *http.Request, ids []string, action string) bool {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w)
+		return false
+	}
+	var add, remove []string
+	switch action {
+	case "modify":
+		var req gmail.ModifyMessageRequest
+		if err := decodeBody(r, &req); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return false
+		}
+		add, remove = req.AddLabelIds, req.RemoveLabelIds
+	case "trash":
+		add = []string{"TRASH"}
+	case "untrash":
+		remove = []string{"TRASH"}
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+		return false
+	}
+	if err := s.gmail.modify(ids, add, remove); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+	return true
+}
+
+func (s *Server) gmailAttachment(w http.ResponseWriter, r *http.Request, messageID, attachmentID string) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w)
+		return
+	}
+	msg := s.gmail.messages[messageID]
+	if msg == nil {
+		writeError(w, http.StatusNotFound, "Requested entity was not found.")
+		return
+	}
+	var found *gmail.MessagePartBody
+	walkParts(msg.Payload, func(p *gmail.MessagePart) {
+		if p.Body != nil && p.Body.AttachmentId == attachmentID {
+			found = p.Body
+		}
+	})
+	if found == nil {
+		writeError(w, http.StatusNotFound, "Requested entity was not found.")
+		return
+	}
+	writeJSON(w, http.StatusOK, &gmail.MessagePartBody{
+		AttachmentId: found.AttachmentId,
+		Data:         found.Data,
+		Size:         found.Size,
+	})
+}
+
+// gmailStore handles messages.send, messages.import, and messages.insert,
+// with the raw message either in the JSON body or as a multipart upload.
+func (s *Server) gmailStore(w http.ResponseWriter, r *http.Request, op string) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w)
+		return
+	}
+	st := s.gmail
+	req, raw, err := decodeMessageUpload(r)
+	if err != nil || len(raw) == 0 {
+		writeError(w, http.StatusBadRequest, "Invalid raw message")
+		return
+	}
+	parsed, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid raw message: "+err.Error())
+		return
+	}
+	body, _ := io.ReadAll(parsed.Body)
+
+	labels := []string{"SENT"}
+	if op != "send" {
+		labels = req.LabelIds
+		for _, l := range labels {
+			if st.label(l) == nil {
+				writeError(w, http.StatusBadRequest, "Invalid label: "+l)
+				return
+			}
+		}
+	}
+
+	st.nextID++
+	id := fmt.Sprintf("%016x", gmailIDBase+st.nextID)
+	threadID := id
+	if req.ThreadId != "" && len(st.threadMessageIDs(req.ThreadId)) > 0 {
+		threadID = req.ThreadId
+	}
+	mimeType := "text/plain"
+	if ct := parsed.Header.Get("Content-Type"); ct != "" {
+		mimeType = strings.TrimSpace(strings.SplitN(ct, ";", 2)[0])
+	}
+	internalDate := s.now()
+	if op == "import" && r.URL.Query().Get("internalDateSource") != "receivedTime" {
+		if d, dateErr := parsed.Header.Date(); dateErr == nil {
+			internalDate = d
+		}
+	}
+	msg := &gmail.Message{
+		Id:           id,
+		ThreadId:     threadID,
+		LabelIds:     labels,
+		InternalDate: internalDate.UnixMilli(),
+		Raw:          base64.URLEncoding.EncodeToString(raw),
+		SizeEstimate: int64(len(raw)),
+		Payload: &gmail.MessagePart{
+			MimeType: mimeType,
+			Headers:  headersOf(parsed.Header),
+			Body: &gmail.MessagePartBody{
+				Data: base64.URLEncoding.EncodeToString(body),
+				Size: int64(len(body)),
+			},
+		},
+	}
+	if strings.HasPrefix(mimeType, "text/") {
+		msg.Snippet = snippet(string(body))
+	}
+	st.history++
+	msg.HistoryId = st.history
+	st.messages[id] = msg
+	writeJSON(w, http.StatusOK, &gmail.Message{Id: id, ThreadId: threadID, LabelIds: msg.LabelIds})
+}
+
+// decodeMessageUpload reads the message metadata and raw RFC 822 bytes from
+// a JSON body ({"raw": ...}) or a multipart/related media upload.
+func decodeMessageUpload(r *http.Request) (*gmail.Message, []byte, error) {
+	mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		var req gmail.Message
+		if err := decodeBody(r, &req); err != nil {
+			return nil, nil, err
+		}
+		raw, err := decodeRaw(req.Raw)
+		return &req, raw, err
+	}
+	defer r.Body.Close()
+	mr := multipart.NewReader(r.Body, params["boundary"])
+	meta := &gmail.Message{}
+	var raw []byte
+	for i := 0; ; i++ {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		data, err := io.ReadAll(part)
+		if err != nil {
+			return nil, nil, err
+		}
+		if i == 0 && strings.HasPrefix(part.Header.Get("Content-Type"), "application/json") {
+			if err := json.Unmarshal(data, meta); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+		raw = data
+	}
+	return meta, raw, nil
+}
+
+// headersOf keeps the common headers in their usual order, then the rest
+// sorted, since net/mail does not preserve order.
+func headersOf(h mail.Header) []*gmail.MessagePartHeader {
+	preferred := []string{"From", "To", "Cc", "Bcc", "Subject", "Date", "Message-Id", "In-Reply-To", "References", "Content-Type"}
+	seen := map[string]bool{}
+	var out []*gmail.MessagePartHeader
+	add := func(key string) {
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		for _, v := range h[key] {
+			out = append(out, &gmail.MessagePartHeader{Name: key, Value: v})
+		}
+	}
+	for _, key := range preferred {
+		add(key)
+	}
+	for _, key := range sortedKeys(h) {
+		add(key)
+	}
+	return out
+}
+
+func decodeRaw(s string) ([]byte, error) {
+	if b, err := base64.URLEncoding.DecodeString(s); err == nil {
+		return b, nil
+	}
+	return base64.RawURLEncoding.DecodeString(s)
+}
+
+func (st *gmailState) label(id string) *gmail.Label {
+	for _, l := range st.labels {
+		if l.Id == id {
+			return l
+		}
+	}
+	return nil
+}
+
+func (st *gmailState) labelByName(name string) *gmail.Label {
+	for _, l := range st.labels {
+		if strings.EqualFold(l.Name, name) {
+			return l
+		}
+	}
+	return nil
+}
+
+func (st *gmailState) modify(ids, add, remove []string) error {
+	for _, id := range append(append([]string{}, add...), remove...) {
+		if st.label(id) == nil {
+			return fmt.Errorf("Invalid label: %s", id) //nolint:staticcheck // matches Gmail's message
+		}
+	}
+	st.history++
+	for _, id := range ids {
+		msg := st.messages[id]
+		for _, l := range remove {
+			msg.LabelIds = removeString(msg.LabelIds, l)
+		}
+		for _, l := range add {
+			if !hasLabel(msg, l) {
+				msg.LabelIds = append(msg.LabelIds, l)
+			}
+		}
+		msg.HistoryId = st.history
+	}
+	return nil
+}
+
+// search returns matching message IDs, newest first.
+func (st *gmailState) search(q url.Values) []string {
+	query := parseGmailQuery(q.Get("q"))
+	includeSpamTrash := q.Get("includeSpamTrash") == "true" || query.mentionsSpamTrash
+	labelIDs := q["labelIds"]
+
+	var ids []string
+	for id, msg := range st.messages {
+		if !includeSpamTrash && (hasLabel(msg, "SPAM") || hasLabel(msg, "TRASH")) {
+			continue
+		}
+		matched := true
+		for _, l := range labelIDs {
+			if !hasLabel(msg, l) {
+				matched = false
+				break
+			}
+		}
+		if matched && st.matches(msg, query) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := st.messages[ids[i]], st.messages[ids[j]]
+		if a.InternalDate != b.InternalDate {
+			return a.InternalDate > b.InternalDate
+		}
+		return a.Id > b.Id
+	})
+	return ids
+}
+
+// threadsFor groups message IDs (newest first) into thread stubs, ordered
+// by each thread's newest match.
+func (st *gmailState) threadsFor(ids []string) []*gmail.Thread {
+	seen := map[string]bool{}
+	var out []*gmail.Thread
+	for _, id := range ids {
+		msg := st.messages[id]
+		if seen[msg.ThreadId] {
+			continue
+		}
+		seen[msg.ThreadId] = true
+		out = append(out, &gmail.Thread{Id: msg.ThreadId, Snippet: msg.Snippet, HistoryId: msg.HistoryId})
+	}
+	return out
+}
+
+func (st *gmailState) threadMessageIDs(threadID string) []string {
+	var ids []string
+	for id, msg := range st.messages {
+		if msg.ThreadId == threadID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := st.messages[ids[i]], st.messages[ids[j]]
+		if a.InternalDate != b.InternalDate {
+			return a.InternalDate < b.InternalDate
+		}
+		return a.Id < b.Id
+	})
+	return ids
+}
+
+func (st *gmailState) thread(threadID string, q url.Values) *gmail.Thread {
+	ids := st.threadMessageIDs(threadID)
+	if len(ids) == 0 {
+		return nil
+	}
+	thread := &gmail.Thread{Id: threadID}
+	for _, id := range ids {
+		msg := st.messages[id]
+		thread.Messages = append(thread.Messages, messageView(msg, q))
+		thread.HistoryId = max(thread.HistoryId, msg.HistoryId)
+		thread.Snippet = msg.Snippet
+	}
+	return thread
+}
+
+// messageView applies the format and metadataHeaders parameters.
+func messageView(msg *gmail.Message, q url.Values) *gmail.Message {
+	out := clone(msg)
+	switch q.Get("format") {
+	case "minimal":
+		out.Payload, out.Raw = nil, ""
+	case "metadata":
+		out.Raw = ""
+		if out.Payload != nil {
+			out.Payload = &gmail.MessagePart{
+				MimeType: out.Payload.MimeType,
+				Headers:  filterHeaders(out.Payload.Headers, q["metadataHeaders"]),
+			}
+		}
+	case "raw":
+		out.Raw = rawMessage(msg)
+		out.Payload = nil
+	default:
+		out.Raw = ""
+		walkParts(out.Payload, func(p *gmail.MessagePart) {
+			if p.Body != nil && p.Body.AttachmentId != "" {
+				p.Body.Data = ""
+			}
+		})
+	}
+	return out
+}
+
+func filterHeaders(headers []*gmail.MessagePartHeader, names []string) []*gmail.MessagePartHeader {
+	if len(names) == 0 {
+		return headers
+	}
+	var out []*gmail.MessagePartHeader
+	for _, h := range headers {
+		for _, n := range names {
+			if strings.EqualFold(h.Name, n) {
+				out = append(out, h)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// rawMessage renders headers and the first text/plain body as RFC 822 when
+// the fixture has no raw form of its own.
+func rawMessage(msg *gmail.Message) string {
+	if msg.Raw != "" {
+		return msg.Raw
+	}
+	var b strings.Builder
+	if msg.Payload != nil {
+		for _, h := range msg.Payload.Headers {
+			fmt.Fprintf(&b, "%s: %s\r\n", h.Name, h.Value)
+		}
+	}
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(plainText(msg), "\n", "\r\n"))
+	return base64.URLEncoding.EncodeToString([]byte(b.String()))
+}
+
+func plainText(msg *gmail.Message) string {
+	var text string
+	walkParts(msg.Payload, func(p *gmail.MessagePart) {
+		if text != "" || p.Body == nil || p.Body.Data == "" || p.Body.AttachmentId != "" {
+			return
+		}
+		if p.MimeType == "" || strings.HasPrefix(p.MimeType, "text/plain") {
+			if data, err := decodeRaw(p.Body.Data); err == nil {
+				text = string(data)
+			}
+		}
+	})
+	return text
+}
+
+func walkParts(p *gmail.MessagePart, fn func(*gmail.MessagePart)) {
+	if p == nil {
+		return
+	}
+	fn(p)
+	for _, child := range p.Parts {
+		walkParts(child, fn)
+	}
+}
+
+func hasLabel(msg *gmail.Message, id string) bool {
+	for _, l := range msg.LabelIds {
+		if l == id {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(list []string, v string) []string {
+	out := list[:0]
+	for _, s := range list {
+		if s != v {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func header(msg *gmail.Message, name string) string {
+	if msg.Payload == nil {
+		return ""
+	}
+	var values []string
+	for _, h := range msg.Payload.Headers {
+		if strings.EqualFold(h.Name, name) {
+			values = append(values, h.Value)
+		}
+	}
+	return strings.Join(values, ", ")
+}
+
+// gmailQuery is the subset of Gmail search the mock understands: label:,
+// in:, is:, from:, to:, cc:, subject:, rfc822msgid:, has:attachment, after:,
+// before:, newer_than:, older_than:, "-" negation, and plain words.
+type gmailQuery struct {
+	terms             []gmailTerm
+	mentionsSpamTrash bool
+}
+
+type gmailTerm struct {
+	op, value string
+	negate    bool
+}
+
+func parseGmailQuery(q string) gmailQuery {
+	var out gmailQuery
+	for _, tok := range splitQuery(q) {
+		term := gmailTerm{}
+		if strings.HasPrefix(tok, "-") && len(tok) > 1 {
+			term.negate = true
+			tok = tok[1:]
+		}
+		if op, value, ok := strings.Cut(tok, ":"); ok && value != "" && isQueryOp(op) {
+			term.op, term.value = strings.ToLower(op), strings.Trim(value, `"`)
+		} else {
+			term.value = strings.Trim(tok, `"`)
+		}
+		if term.op == "in" {
+			switch strings.ToLower(term.value) {
+			case "spam", "trash", "anywhere":
+				out.mentionsSpamTrash = true
+			}
+		}
+		out.terms = append(out.terms, term)
+	}
+	return out
+}
+
+func isQueryOp(op string) bool {
+	switch strings.ToLower(op) {
+	case "label", "in", "is", "from", "to", "cc", "subject", "rfc822msgid", "has", "after", "before", "newer_than", "older_than":
+		return true
+	}
+	return false
+}
+
+// splitQuery splits on spaces outside double quotes and drops OR/AND.
+func splitQuery(q string) []string {
+	var (
+		out     []string
+		cur     strings.Builder
+		inQuote bool
+	)
+	flush := func() {
+		if cur.Len() > 0 {
+			if s := cur.String(); s != "OR" && s != "AND" {
+				out = append(out, s)
+			}
+			cur.Reset()
+		}
+	}
+	for _, r := range q {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			cur.WriteRune(r)
+		case unicode.IsSpace(r) && !inQuote:
+			flush()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	flush()
+	return out
+}
+
+func (st *gmailState) matches(msg *gmail.Message, q gmailQuery) bool {
+	for _, term := range q.terms {
+		if st.matchTerm(msg, term) == term.negate {
+			return false
+		}
+	}
+	return true
+}
+
+func (st *gmailState) matchTerm(msg *gmail.Message, t gmailTerm) bool {
+	contains := func(haystack string) bool {
+		return strings.Contains(strings.ToLower(haystack), strings.ToLower(t.value))
+	}
+	switch t.op {
+	case "label", "in":
+		if strings.EqualFold(t.value, "anywhere") {
+			return true
+		}
+		return st.hasLabelNamed(msg, t.value)
+	case "is":
+		switch strings.ToLower(t.value) {
+		case "read":
+			return !hasLabel(msg, "UNREAD")
+		case "unread", "starred", "important":
+			return hasLabel(msg, strings.ToUpper(t.value))
+		}
+		return false
+	case "from", "to", "cc", "subject":
+		return contains(header(msg, t.op))
+	case "rfc822msgid":
+		id := strings.Trim(header(msg, "Message-ID"), "<>")
+		return strings.EqualFold(id, strings.Trim(t.value, "<>"))
+	case "has":
+		if !strings.EqualFold(t.value, "attachment") {
+			return false
+		}
+		found := false
+		walkParts(msg.Payload, func(p *gmail.MessagePart) {
+			found = found || p.Filename != ""
+		})
+		return found
+	case "after", "before":
+		day, err := time.Parse("2006/01/02", strings.ReplaceAll(t.value, "-", "/"))
+		if err != nil {
+			return false
+		}
+		if t.op == "after" {
+			return msg.InternalDate >= day.UnixMilli()
+		}
+		return msg.InternalDate < day.UnixMilli()
+	case "newer_than", "older_than":
+		d, ok := relativeAge(t.value)
+		if !ok {
+			return false
+		}
+		cutoff := time.Now().Add(-d).UnixMilli()
+		if t.op == "newer_than" {
+			return msg.InternalDate >= cutoff
+		}
+		return msg.InternalDate < cutoff
+	}
+	return contains(header(msg, "subject")) || contains(header(msg, "from")) ||
+		contains(header(msg, "to")) || contains(msg.Snippet) || contains(plainText(msg))
+}
+
+func (st *gmailState) hasLabelNamed(msg *gmail.Message, name string) bool {
+	for _, l := range st.labels {
+		dashed := strings.NewReplacer(" ", "-", "/", "-").Replace(l.Name)
+		if strings.EqualFold(l.Id, name) || strings.EqualFold(l.Name, name) || strings.EqualFold(dashed, name) {
+			return hasLabel(msg, l.Id)
+		}
+	}
+	return false
+}
+
+func relativeAge(v string) (time.Duration, bool) {
+	if len(v) < 2 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(v[:len(v)-1])
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	unit := map[byte]time.Duration{'h': time.Hour, 'd': 24 * time.Hour, 'm': 30 * 24 * time.Hour, 'y': 365 * 24 * time.Hour}[v[len(v)-1]]
+	if unit == 0 {
+		return 0, false
+	}
+	return time.Duration(n) * unit, true
+}
@@ -0,0 +1,175 @@
+package mock
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func newTestServer(t *testing.T, f *Fixtures, services ...string) *httptest.Server {
+	t.Helper()
+	s, err := New(f, services...)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGmail_SearchGetModifySend(t *testing.T) {
+	srv := newTestServer(t, nil)
+	ctx := context.Background()
+	svc, err := gmail.NewService(ctx, option.WithoutAuthentication(), option.WithEndpoint(srv.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+
+	list, err := svc.Users.Messages.List("me").Q("in:inbox is:unread").Do()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(list.Messages) != 2 || list.Messages[0].Id != "18c0a1b2c3d4e504" {
+		t.Fatalf("unexpected unread inbox: %#v", list.Messages)
+	}
+	if got, _ := svc.Users.Messages.List("me").Q("label:work -from:alice").Do(); len(got.Messages) != 1 {
+		t.Fatalf("expected 1 message for label:work -from:alice, got %d", len(got.Messages))
+	}
+
+	msg, err := svc.Users.Messages.Get("me", "18c0a1b2c3d4e501").Format("metadata").MetadataHeaders("Subject").Do()
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if len(msg.Payload.Headers) != 1 || msg.Payload.Headers[0].Value != "Quarterly planning" {
+		t.Fatalf("unexpected headers: %#v", msg.Payload.Headers)
+	}
+
+	thread, err := svc.Users.Threads.Get("me", "18c0a1b2c3d4e501").Do()
+	if err != nil || len(thread.Messages) != 2 {
+		t.Fatalf("thread: %v %#v", err, thread)
+	}
+
+	if _, err := svc.Users.Messages.Modify("me", "18c0a1b2c3d4e501", &gmail.ModifyMessageRequest{RemoveLabelIds: []string{"UNREAD"}}).Do(); err != nil {
+		t.Fatalf("modify: %v", err)
+	}
+	if got, _ := svc.Users.Messages.List("me").Q("is:unread").Do(); len(got.Messages) != 1 {
+		t.Fatalf("expected 1 unread after modify, got %d", len(got.Messages))
+	}
+	if _, err := svc.Users.Messages.Modify("me", "18c0a1b2c3d4e501", &gmail.ModifyMessageRequest{AddLabelIds: []string{"Label_404"}}).Do(); err == nil {
+		t.Fatalf("expected error for unknown label")
+	}
+
+	raw := "From: test@example.com\r\nTo: bob@example.com\r\nSubject: Hello\r\n\r\nHi Bob\r\n"
+	sent, err := svc.Users.Messages.Send("me", &gmail.Message{Raw: base64.URLEncoding.EncodeToString([]byte(raw))}).Do()
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if got, _ := svc.Users.Messages.List("me").Q("in:sent subject:hello").Do(); len(got.Messages) != 1 || got.Messages[0].Id != sent.Id {
+		t.Fatalf("sent message not searchable: %#v", got.Messages)
+	}
+	if _, err := svc.Users.Messages.Get("me", "nope").Do(); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("expected 404, got %v", err)
+	}
+}
+
+func TestCalendar_ListInsertPatchFreeBusy(t *testing.T) {
+	srv := newTestServer(t, nil)
+	ctx := context.Background()
+	svc, err := calendar.NewService(ctx, option.WithoutAuthentication(), option.WithEndpoint(srv.URL+"/calendar/v3/"))
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+
+	events, err := svc.Events.List("primary").TimeMin("2026-01-08T00:00:00Z").TimeMax("2026-01-10T00:00:00Z").Do()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(events.Items) != 2 || events.Items[0].Id != "evt0001" {
+		t.Fatalf("unexpected events: %#v", events.Items)
+	}
+
+	created, err := svc.Events.Insert("primary", &calendar.Event{
+		Summary: "Lunch",
+		Start:   &calendar.EventDateTime{DateTime: "2026-01-08T12:00:00Z"},
+		End:     &calendar.EventDateTime{DateTime: "2026-01-08T13:00:00Z"},
+	}).Do()
+	if err != nil || created.Id == "" || created.Status != "confirmed" {
+		t.Fatalf("insert: %v %#v", err, created)
+	}
+	patched, err := svc.Events.Patch("primary", created.Id, &calendar.Event{Location: "Cafe"}).Do()
+	if err != nil || patched.Location != "Cafe" || patched.Summary != "Lunch" {
+		t.Fatalf("patch: %v %#v", err, patched)
+	}
+
+	fb, err := svc.Freebusy.Query(&calendar.FreeBusyRequest{
+		TimeMin: "2026-01-08T00:00:00Z",
+		TimeMax: "2026-01-09T00:00:00Z",
+		Items:   []*calendar.FreeBusyRequestItem{{Id: "test@example.com"}},
+	}).Do()
+	if err != nil {
+		t.Fatalf("freebusy: %v", err)
+	}
+	if busy := fb.Calendars["test@example.com"].Busy; len(busy) != 2 {
+		t.Fatalf("unexpected busy: %#v", busy)
+	}
+
+	if err := svc.Events.Delete("primary", created.Id).Do(); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := svc.Events.Get("primary", created.Id).Do(); err == nil {
+		t.Fatalf("expected 404 after delete")
+	}
+}
+
+func TestServicesAndFixtures(t *testing.T) {
+	if _, err := New(nil, "drive"); err == nil {
+		t.Fatalf("expected error for unknown service")
+	}
+
+	dir := t.TempDir()
+	data := `{"email":"me@example.org","messages":[{"id":"m1","subject":"Only one","body":"x","labelIds":["INBOX"]}]}`
+	if err := os.WriteFile(filepath.Join(dir, "gmail.json"), []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := LoadFixtures(dir)
+	if err != nil {
+		t.Fatalf("LoadFixtures: %v", err)
+	}
+	if len(f.Calendar.Calendars) == 0 {
+		t.Fatalf("calendar should keep built-in data when calendar.json is missing")
+	}
+
+	srv := newTestServer(t, f, "gmail")
+	svc, err := gmail.NewService(context.Background(), option.WithoutAuthentication(), option.WithEndpoint(srv.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	profile, err := svc.Users.GetProfile("me").Do()
+	if err != nil || profile.EmailAddress != "me@example.org" || profile.MessagesTotal != 1 {
+		t.Fatalf("profile: %v %#v", err, profile)
+	}
+	raw, err := svc.Users.Messages.Get("me", "m1").Format("raw").Do()
+	if err != nil {
+		t.Fatalf("raw: %v", err)
+	}
+	decoded, _ := base64.URLEncoding.DecodeString(raw.Raw)
+	if !strings.Contains(string(decoded), "Subject: Only one") {
+		t.Fatalf("unexpected raw: %q", decoded)
+	}
+
+	calSvc, err := calendar.NewService(context.Background(), option.WithoutAuthentication(), option.WithEndpoint(srv.URL+"/calendar/v3/"))
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	if _, err := calSvc.CalendarList.List().Do(); err == nil {
+		t.Fatalf("calendar should be disabled")
+	}
+}